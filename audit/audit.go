@@ -0,0 +1,67 @@
+// Package audit implements sinks streaming the audit events of operations
+// executed against groups to external systems, e.g. a log file shipped to a
+// SIEM, so that organizations can keep track of deployments centrally.
+package audit
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// Event describes an operation executed for a group.
+type Event struct {
+	// Group is the group the operation was executed for.
+	Group string `json:"group"`
+
+	// Operation is the operation executed, e.g. "up".
+	Operation string `json:"operation"`
+
+	// Args are the arguments of the operation, e.g. the group and scale.
+	Args []string `json:"args"`
+
+	// RequestID identifies the requests sent to fleet during the operation.
+	RequestID string `json:"request-id,omitempty"`
+
+	// User is the name of the user executing the operation.
+	User string `json:"user,omitempty"`
+
+	// Host is the name of the host the operation was executed on.
+	Host string `json:"host,omitempty"`
+
+	// Started is the time the operation started.
+	Started time.Time `json:"started"`
+
+	// Duration is the time the operation took for the group.
+	Duration time.Duration `json:"duration"`
+
+	// Succeeded tells whether the operation succeeded for the group.
+	Succeeded bool `json:"succeeded"`
+
+	// Error describes why the operation failed, if it did.
+	Error string `json:"error,omitempty"`
+
+	// Slices are the slices observed during the operation.
+	Slices []Slice `json:"slices,omitempty"`
+}
+
+// Slice describes how long a slice took during an operation.
+type Slice struct {
+	ID       string        `json:"id"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Command returns the command line executing the operation, e.g.
+// "inagoctl up myapp 3".
+func (e Event) Command() string {
+	return strings.Join(append([]string{"inagoctl", e.Operation}, e.Args...), " ")
+}
+
+// Sink writes audit events to some receiver.
+type Sink interface {
+	// Write writes the given event. Auditing must not fail operations, so
+	// callers usually log errors instead.
+	Write(ctx context.Context, e Event) error
+}
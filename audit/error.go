@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"fmt"
+
+	"github.com/juju/errgo"
+)
+
+var (
+	maskAny = errgo.MaskFunc(errgo.Any)
+)
+
+func maskAnyf(err error, f string, v ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	f = fmt.Sprintf("%s: %s", err.Error(), f)
+	newErr := errgo.WithCausef(nil, errgo.Cause(err), f, v...)
+	newErr.(*errgo.Err).SetLocation(1)
+
+	return newErr
+}
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig checks whether the given error indicates that a sink cannot
+// be created using the given configuration, e.g. because the URL is missing.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var writeFailedError = errgo.New("write failed")
+
+// IsWriteFailed checks whether the given error indicates that an event was
+// rejected by the receiving service.
+func IsWriteFailed(err error) bool {
+	return errgo.Cause(err) == writeFailedError
+}
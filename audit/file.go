@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+
+	"golang.org/x/net/context"
+)
+
+// FileConfig holds configuration for the file sink.
+type FileConfig struct {
+	// Path is the path of the file events are appended to. The file is created
+	// in case it does not exist.
+	Path string
+}
+
+// DefaultFileConfig provides a set of configurations with default values by
+// best effort.
+func DefaultFileConfig() FileConfig {
+	return FileConfig{
+		Path: "",
+	}
+}
+
+// NewFile creates a Sink appending events to a local file, one JSON object per
+// line, as expected by most log shippers.
+//
+//   newFileConfig := audit.DefaultFileConfig()
+//   newFileConfig.Path = "/var/log/inago/audit.log"
+//   newFile, err := audit.NewFile(newFileConfig)
+//
+func NewFile(config FileConfig) (Sink, error) {
+	if config.Path == "" {
+		return nil, maskAnyf(invalidConfigError, "audit file path must not be empty")
+	}
+
+	newFile := file{
+		FileConfig: config,
+	}
+
+	return newFile, nil
+}
+
+type file struct {
+	FileConfig
+}
+
+func (f file) Write(ctx context.Context, e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	out, err := os.OpenFile(f.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer out.Close()
+
+	// Lines are written using a single call, so that events of operations
+	// executed in parallel are not interleaved.
+	if _, err := out.Write(append(b, '\n')); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func Test_File_Write(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inago-audit")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	defer os.RemoveAll(dir)
+
+	newFileConfig := DefaultFileConfig()
+	newFileConfig.Path = filepath.Join(dir, "audit.log")
+	newFile, err := NewFile(newFileConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	events := []Event{
+		{Group: "myapp", Operation: "up", Args: []string{"myapp", "3"}, Duration: 2 * time.Second, Succeeded: true},
+		{Group: "myapp", Operation: "update", Args: []string{"myapp"}, Error: "unit failed"},
+	}
+	for _, e := range events {
+		if err := newFile.Write(context.Background(), e); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	raw, err := ioutil.ReadFile(newFileConfig.Path)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n")
+	if len(lines) != len(events) {
+		t.Fatal("expected", len(events), "got", len(lines))
+	}
+	for i, line := range lines {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		if e.Command() != events[i].Command() || e.Succeeded != events[i].Succeeded || e.Error != events[i].Error {
+			t.Fatal("expected", events[i], "got", e)
+		}
+	}
+}
+
+func Test_NewFile_InvalidConfig(t *testing.T) {
+	if _, err := NewFile(DefaultFileConfig()); !IsInvalidConfig(err) {
+		t.Fatal("expected", "invalid config error", "got", err)
+	}
+}
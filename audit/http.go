@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// HTTPConfig holds configuration for the HTTP sink.
+type HTTPConfig struct {
+	// Client is the HTTP client used to post events.
+	Client *http.Client
+
+	// URL is the URL events are posted to, e.g. the HTTP event collector of a
+	// SIEM.
+	URL string
+
+	// Headers are added to each request, e.g. "Authorization".
+	Headers map[string]string
+}
+
+// DefaultHTTPConfig provides a set of configurations with default values by
+// best effort.
+func DefaultHTTPConfig() HTTPConfig {
+	return HTTPConfig{
+		Client:  &http.Client{Timeout: 10 * time.Second},
+		URL:     "",
+		Headers: nil,
+	}
+}
+
+// NewHTTP creates a Sink posting events as JSON to the given URL. Any 2xx
+// status code is considered a success.
+//
+//   newHTTPConfig := audit.DefaultHTTPConfig()
+//   newHTTPConfig.URL = "https://siem.example.com/events"
+//   newHTTP, err := audit.NewHTTP(newHTTPConfig)
+//
+func NewHTTP(config HTTPConfig) (Sink, error) {
+	if config.URL == "" {
+		return nil, maskAnyf(invalidConfigError, "audit URL must not be empty")
+	}
+
+	newHTTP := httpSink{
+		HTTPConfig: config,
+	}
+
+	return newHTTP, nil
+}
+
+type httpSink struct {
+	HTTPConfig
+}
+
+func (h httpSink) Write(ctx context.Context, e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	req, err := http.NewRequest("POST", h.URL, bytes.NewReader(b))
+	if err != nil {
+		return maskAny(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range h.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return maskAnyf(writeFailedError, "%s answered %s: %s", h.URL, resp.Status, string(body))
+	}
+
+	return nil
+}
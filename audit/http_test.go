@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func Test_HTTP_Write(t *testing.T) {
+	var events []Event
+	var tokens []string
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e Event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		events = append(events, e)
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	newHTTPConfig := DefaultHTTPConfig()
+	newHTTPConfig.URL = server.URL
+	newHTTPConfig.Headers = map[string]string{"Authorization": "Bearer XXX"}
+	newHTTP, err := NewHTTP(newHTTPConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	e := Event{Group: "myapp", Operation: "up", Args: []string{"myapp", "3"}, Succeeded: true}
+	if err := newHTTP.Write(context.Background(), e); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(events) != 1 || events[0].Command() != "inagoctl up myapp 3" || !events[0].Succeeded {
+		t.Fatal("expected", e, "got", events)
+	}
+	if tokens[0] != "Bearer XXX" {
+		t.Fatal("expected", "Bearer XXX", "got", tokens[0])
+	}
+
+	status = http.StatusInternalServerError
+	if err := newHTTP.Write(context.Background(), e); !IsWriteFailed(err) {
+		t.Fatal("expected", "write failed error", "got", err)
+	}
+}
+
+func Test_NewHTTP_InvalidConfig(t *testing.T) {
+	if _, err := NewHTTP(DefaultHTTPConfig()); !IsInvalidConfig(err) {
+		t.Fatal("expected", "invalid config error", "got", err)
+	}
+}
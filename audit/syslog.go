@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"encoding/json"
+	"log/syslog"
+
+	"golang.org/x/net/context"
+)
+
+// SyslogConfig holds configuration for the syslog sink.
+type SyslogConfig struct {
+	// Network is the network of the syslog daemon, e.g. "udp" or "tcp". The
+	// local syslog daemon is used in case it is empty.
+	Network string
+
+	// Address is the address of the syslog daemon, e.g. "siem.example.com:514".
+	// It is ignored for the local syslog daemon.
+	Address string
+
+	// Tag is the tag messages are logged using.
+	Tag string
+}
+
+// DefaultSyslogConfig provides a set of configurations with default values by
+// best effort.
+func DefaultSyslogConfig() SyslogConfig {
+	return SyslogConfig{
+		Network: "",
+		Address: "",
+		Tag:     "inagoctl",
+	}
+}
+
+// NewSyslog creates a Sink logging events as JSON to syslog. Events of failed
+// operations are logged using the warning severity, others using the info
+// severity.
+//
+//   newSyslogConfig := audit.DefaultSyslogConfig()
+//   newSyslogConfig.Network = "udp"
+//   newSyslogConfig.Address = "siem.example.com:514"
+//   newSyslog, err := audit.NewSyslog(newSyslogConfig)
+//
+func NewSyslog(config SyslogConfig) (Sink, error) {
+	if config.Network != "" && config.Address == "" {
+		return nil, maskAnyf(invalidConfigError, "syslog address must not be empty for network '%s'", config.Network)
+	}
+	if config.Tag == "" {
+		return nil, maskAnyf(invalidConfigError, "syslog tag must not be empty")
+	}
+
+	newSyslog := syslogSink{
+		SyslogConfig: config,
+	}
+
+	return newSyslog, nil
+}
+
+type syslogSink struct {
+	SyslogConfig
+}
+
+func (s syslogSink) Write(ctx context.Context, e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	// Operations are short lived, so we connect per event instead of keeping
+	// the connection around.
+	w, err := syslog.Dial(s.Network, s.Address, syslog.LOG_INFO|syslog.LOG_USER, s.Tag)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer w.Close()
+
+	if e.Succeeded {
+		err = w.Info(string(b))
+	} else {
+		err = w.Warning(string(b))
+	}
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
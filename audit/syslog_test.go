@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func Test_Syslog_Write(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	defer conn.Close()
+
+	newSyslogConfig := DefaultSyslogConfig()
+	newSyslogConfig.Network = "udp"
+	newSyslogConfig.Address = conn.LocalAddr().String()
+	newSyslog, err := NewSyslog(newSyslogConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	e := Event{Group: "myapp", Operation: "update", Args: []string{"myapp"}, Error: "unit failed"}
+	if err := newSyslog.Write(context.Background(), e); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	b := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(b)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	msg := string(b[:n])
+	// Failed operations are logged using the warning severity of the user
+	// facility, i.e. priority 12.
+	if !strings.HasPrefix(msg, "<12>") || !strings.Contains(msg, "inagoctl") || !strings.Contains(msg, `"error":"unit failed"`) {
+		t.Fatal("expected", "syslog message", "got", msg)
+	}
+}
+
+func Test_NewSyslog_InvalidConfig(t *testing.T) {
+	newSyslogConfig := DefaultSyslogConfig()
+	newSyslogConfig.Network = "udp"
+	if _, err := NewSyslog(newSyslogConfig); !IsInvalidConfig(err) {
+		t.Fatal("expected", "invalid config error", "got", err)
+	}
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/spec"
+	"github.com/giantswarm/inago/fleet"
+)
+
+var (
+	adoptCmd = &cobra.Command{
+		Use:   "adopt <prefix>",
+		Short: "Adopt existing units",
+		Long:  "Bring units not created by Inago under its management. All units of the cluster starting with the given prefix are considered a group named like the prefix. Their unit files are fetched from fleet and written to a group directory in the current working directory, so that they can be managed like any other group",
+		Run:   adoptRun,
+	}
+)
+
+func adoptRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting adopt")
+
+	if len(args) != 1 || args[0] == "" {
+		cmd.Help()
+		exit(1)
+	}
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = resolveGroup(args[0])
+	req := controller.NewRequest(newRequestConfig)
+
+	unitStatusList, err := newController.GetStatus(newCtx, req)
+	if controller.IsUnitNotFound(err) {
+		handleAdoptCmdError(maskAnyf(groupNotFoundError, "no units found with prefix '%s'", req.Group))
+	}
+	handleAdoptCmdError(err)
+	sliceIDs, err := adoptedSliceIDs(unitStatusList)
+	handleAdoptCmdError(err)
+
+	req.Units, err = newController.DeployedUnits(newCtx, req)
+	handleAdoptCmdError(err)
+	if ok, err := controller.ValidateRequest(req); !ok {
+		validationErr := err.(controller.ValidationError)
+		newLogger.Error(newCtx, "Failed to adopt units with prefix '%s'. %s", req.Group, FormatValidationError(validationErr))
+		exit(1)
+	}
+
+	err = writeAdoptedGroup(fs, req)
+	handleAdoptCmdError(err)
+
+	if len(sliceIDs) == 0 {
+		newLogger.Info(newCtx, "Succeeded to adopt group '%s'.", req.Group)
+	} else {
+		newLogger.Info(newCtx, "Succeeded to adopt group '%s' with %d slices: %v.", req.Group, len(sliceIDs), sliceIDs)
+	}
+}
+
+// adoptedSliceIDs infers the slices of a group from the given unit states.
+// Inago requires all slices of a group to consist of the same units. In case
+// the given units do not form consistent slices, an error that you can
+// identify using IsInconsistentSlices is returned.
+func adoptedSliceIDs(usl []fleet.UnitStatus) ([]string, error) {
+	unitsBySlice := map[string][]string{}
+	for _, us := range usl {
+		name := us.Name
+		if us.SliceID != "" {
+			name = strings.Replace(name, "@"+us.SliceID+".", "@.", 1)
+		}
+		unitsBySlice[us.SliceID] = append(unitsBySlice[us.SliceID], name)
+	}
+
+	if _, ok := unitsBySlice[""]; ok && len(unitsBySlice) > 1 {
+		return nil, maskAnyf(inconsistentSlicesError, "units with and without slice IDs found")
+	}
+
+	var sliceIDs []string
+	for sliceID, names := range unitsBySlice {
+		sort.Strings(names)
+		sliceIDs = append(sliceIDs, sliceID)
+	}
+	sort.Strings(sliceIDs)
+
+	first := unitsBySlice[sliceIDs[0]]
+	for _, sliceID := range sliceIDs[1:] {
+		if strings.Join(unitsBySlice[sliceID], ",") != strings.Join(first, ",") {
+			return nil, maskAnyf(inconsistentSlicesError, "slice '%s' has units %v, slice '%s' has units %v", sliceIDs[0], first, sliceID, unitsBySlice[sliceID])
+		}
+	}
+
+	if sliceIDs[0] == "" {
+		return nil, nil
+	}
+
+	return sliceIDs, nil
+}
+
+// writeAdoptedGroup writes the unit files of the given request to a group
+// directory named like the group. Existing group directories are never
+// touched.
+func writeAdoptedGroup(fs filesystemspec.FileSystem, req controller.Request) error {
+	if _, err := fs.ReadDir(req.Group); err == nil {
+		return maskAnyf(groupAlreadyExistsError, "directory '%s' already exists", req.Group)
+	}
+
+	for _, unit := range req.Units {
+		name, content := unit.SourceFile()
+		err := fs.WriteFile(filepath.Join(req.Group, name), []byte(content), os.FileMode(0644))
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+
+	return nil
+}
+
+func handleAdoptCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
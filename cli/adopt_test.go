@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/fake"
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_Adopt_adoptedSliceIDs(t *testing.T) {
+	testCases := []struct {
+		Input    []fleet.UnitStatus
+		Expected []string
+		Error    func(error) bool
+	}{
+		// Units without slices.
+		{
+			Input: []fleet.UnitStatus{
+				{Name: "legacy-app.service"},
+				{Name: "legacy-app.timer"},
+			},
+			Expected: nil,
+			Error:    nil,
+		},
+		// Consistent slices.
+		{
+			Input: []fleet.UnitStatus{
+				{Name: "legacy-app@2.service", SliceID: "2"},
+				{Name: "legacy-app@1.service", SliceID: "1"},
+				{Name: "legacy-sidekick@1.service", SliceID: "1"},
+				{Name: "legacy-sidekick@2.service", SliceID: "2"},
+			},
+			Expected: []string{"1", "2"},
+			Error:    nil,
+		},
+		// Slices consisting of different units.
+		{
+			Input: []fleet.UnitStatus{
+				{Name: "legacy-app@1.service", SliceID: "1"},
+				{Name: "legacy-sidekick@1.service", SliceID: "1"},
+				{Name: "legacy-app@2.service", SliceID: "2"},
+			},
+			Expected: nil,
+			Error:    IsInconsistentSlices,
+		},
+		// Units with and without slices.
+		{
+			Input: []fleet.UnitStatus{
+				{Name: "legacy-app@1.service", SliceID: "1"},
+				{Name: "legacy-db.service"},
+			},
+			Expected: nil,
+			Error:    IsInconsistentSlices,
+		},
+	}
+
+	for i, testCase := range testCases {
+		output, err := adoptedSliceIDs(testCase.Input)
+		if testCase.Error == nil && err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if testCase.Error != nil && !testCase.Error(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+		if !reflect.DeepEqual(output, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", output)
+		}
+	}
+}
+
+func Test_Adopt_writeAdoptedGroup(t *testing.T) {
+	req := controller.Request{
+		RequestConfig: controller.RequestConfig{Group: "legacy"},
+		Units: []controller.Unit{
+			{Name: "legacy-app@.service", Content: "[Service]\nExecStart=/bin/app\n"},
+		},
+	}
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	if err := writeAdoptedGroup(newFileSystem, req); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	raw, err := newFileSystem.ReadFile("legacy/legacy-app@.service")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if string(raw) != req.Units[0].Content {
+		t.Fatal("expected", req.Units[0].Content, "got", string(raw))
+	}
+
+	// Adopting the group again must not overwrite the existing directory.
+	if err := writeAdoptedGroup(newFileSystem, req); !IsGroupAlreadyExists(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
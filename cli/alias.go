@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"github.com/giantswarm/inago/controller"
+)
+
+// aliases are the group aliases of the config file. They are resolved where
+// commands build requests from their group arguments. See resolveGroup.
+var aliases controller.Aliases
+
+// newAliases creates the group aliases of the given configuration. In case the
+// aliases are invalid, an error that you can identify using IsInvalidConfig is
+// returned.
+func newAliases(c config) (controller.Aliases, error) {
+	newAliases, err := controller.NewAliases(c.Aliases)
+	if err != nil {
+		return nil, maskAnyf(invalidConfigError, "aliases: %s", err.Error())
+	}
+
+	return newAliases, nil
+}
+
+// resolveGroup replaces the alias the given group expression refers to with
+// the name of its group, e.g. "web@1" with "frontend-nginx@1". Only arguments
+// known to be groups must be resolved, not e.g. directories or machines.
+func resolveGroup(expr string) string {
+	return aliases.Resolve(expr)
+}
+
+// resolveGroups resolves the aliases of all given group expressions. See
+// resolveGroup.
+func resolveGroups(exprs []string) []string {
+	var resolved []string
+	for _, expr := range exprs {
+		resolved = append(resolved, resolveGroup(expr))
+	}
+
+	return resolved
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_Alias_resolveGroup(t *testing.T) {
+	RegisterTestingT(t)
+
+	defer func() { aliases = nil }()
+	c := config{Aliases: map[string]string{"web": "frontend-nginx"}}
+
+	var err error
+	aliases, err = newAliases(c)
+	Expect(err).To(BeNil())
+
+	Expect(resolveGroups([]string{"web@1", "web@2"})).To(Equal([]string{"frontend-nginx@1", "frontend-nginx@2"}))
+	Expect(resolveGroup("db")).To(Equal("db"))
+
+	// Group arguments are resolved where requests are built.
+	group, sliceIDs, err := parseGroupCLIArgs([]string{"web@1"})
+	Expect(err).To(BeNil())
+	Expect(group).To(Equal("frontend-nginx"))
+	Expect(sliceIDs).To(Equal([]string{"1"}))
+
+	c.Aliases["nginx"] = "web"
+	_, err = newAliases(c)
+	Expect(IsInvalidConfig(err)).To(BeTrue())
+}
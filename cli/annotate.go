@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	annotateCmd = &cobra.Command{
+		Use:   "annotate <group[@slice]...> [key=value...]",
+		Short: "Annotate slices of a group",
+		Long:  "Attach notes to the given slices, or to the group in case no slice is given, e.g. \"cordoned=investigating OOM\", so that others see why a slice is stopped or pinned. Annotations are shown by status. An empty value removes a key. Without key value pairs, the annotations of the group are printed",
+		Run:   annotateRun,
+	}
+)
+
+func annotateRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting annotate")
+
+	var groupArgs []string
+	annotations := map[string]string{}
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i < 0 {
+			groupArgs = append(groupArgs, arg)
+			continue
+		}
+		annotations[arg[:i]] = arg[i+1:]
+	}
+	if len(groupArgs) == 0 {
+		cmd.Help()
+		exit(1)
+	}
+	group, sliceIDs, err := parseGroupCLIArgs(groupArgs)
+	handleAnnotateCmdError(err)
+
+	if len(annotations) == 0 {
+		existing, err := newController.SliceAnnotations(newCtx, group)
+		handleAnnotateCmdError(err)
+		if len(existing) == 0 {
+			newLogger.Info(newCtx, "Group '%s' has no annotations.", group)
+			return
+		}
+		printResult(result{Kind: "annotations", Rows: createAnnotationSummary(group, existing)})
+		return
+	}
+
+	if len(sliceIDs) > 0 {
+		existing, err := existingSliceIDs(group)
+		handleAnnotateCmdError(err)
+		for _, sliceID := range sliceIDs {
+			if !containsString(existing, sliceID) {
+				handleAnnotateCmdError(maskAnyf(invalidArgumentsError, "slice '%s@%s' does not exist", group, sliceID))
+			}
+		}
+	}
+
+	err = newController.AnnotateSlices(newCtx, group, sliceIDs, annotations)
+	handleAnnotateCmdError(err)
+	if len(sliceIDs) == 0 {
+		newLogger.Info(newCtx, "Annotated group '%s'.", group)
+	} else {
+		newLogger.Info(newCtx, "Annotated slices %s of group '%s'.", strings.Join(sliceIDs, ", "), group)
+	}
+}
+
+// createAnnotationSummary renders the given annotations of the given group as
+// table rows.
+func createAnnotationSummary(group string, annotations []controller.SliceAnnotation) []string {
+	rows := []string{"Annotated | Key | Value | Since", ""}
+	for _, a := range annotations {
+		name := group
+		if a.SliceID != "" {
+			name += "@" + a.SliceID
+		}
+		rows = append(rows, fmt.Sprintf("%s | %s | %s | %s", name, a.Key, a.Value, a.Since.Local().Format(historyTimeFormat)))
+	}
+
+	return rows
+}
+
+// currentAnnotations returns the given annotations of the group itself and of
+// the slices in the given list, dropping the ones of slices destroyed since
+// they were annotated.
+func currentAnnotations(annotations []controller.SliceAnnotation, sliceIDs []string) []controller.SliceAnnotation {
+	var current []controller.SliceAnnotation
+	for _, a := range annotations {
+		if a.SliceID == "" || containsString(sliceIDs, a.SliceID) {
+			current = append(current, a)
+		}
+	}
+
+	return current
+}
+
+func handleAnnotateCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
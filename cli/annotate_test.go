@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+func Test_Annotate_status(t *testing.T) {
+	RegisterTestingT(t)
+
+	since := time.Date(2016, 5, 4, 12, 0, 0, 0, time.Local)
+	annotations := []controller.SliceAnnotation{
+		{Key: "owner", Value: "frontend", Since: since},
+		{SliceID: "1", Key: "cordoned", Value: "investigating OOM", Since: since},
+		{SliceID: "3", Key: "cordoned", Value: "destroyed since", Since: since},
+	}
+
+	// Annotations of destroyed slices are dropped.
+	annotations = currentAnnotations(annotations, []string{"1", "2"})
+	Expect(annotations).To(HaveLen(2))
+
+	Expect(createAnnotationSummary("app", annotations)).To(Equal([]string{
+		"Annotated | Key | Value | Since",
+		"",
+		"app | owner | frontend | 2016-05-04 12:00:00",
+		"app@1 | cordoned | investigating OOM | 2016-05-04 12:00:00",
+	}))
+
+	out := statusOutput{Group: "app", Slices: []sliceOutput{{ID: "1"}, {ID: "2"}}}
+	markAnnotations(&out, annotations)
+	Expect(out.Annotations).To(Equal(map[string]string{"owner": "frontend"}))
+	Expect(out.Slices[0].Annotations).To(Equal(map[string]string{"cordoned": "investigating OOM"}))
+	Expect(out.Slices[1].Annotations).To(BeNil())
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/signature"
+)
+
+// archiveMetadataFile is the name of the file within a group archive containing
+// the groupArchiveMetadata.
+const archiveMetadataFile = "inago.json"
+
+// archiveSignatureFile is the name of the file within a group archive
+// containing the signature embedded when the archive was created. It signs
+// all other files of the archive. See verifyArchiveSignature.
+const archiveSignatureFile = "inago.sig"
+
+// groupArchiveMetadata describes the group contained in a group archive.
+type groupArchiveMetadata struct {
+	// Group is the name of the archived group.
+	Group string `json:"group"`
+
+	// Scale is the number of slices the group had when it was archived.
+	Scale int `json:"scale"`
+
+	// SliceIDs are the IDs of the slices the group had when it was archived.
+	// They are informational only. Bringing up an archived group creates new
+	// slice IDs.
+	SliceIDs []string `json:"slice-ids,omitempty"`
+
+	// Created is the time the archive was created.
+	Created time.Time `json:"created"`
+
+	// InagoVersion is the version of inagoctl that created the archive.
+	InagoVersion string `json:"inago-version,omitempty"`
+}
+
+// isGroupArchive checks whether the given command line argument refers to a
+// group archive instead of a group directory.
+func isGroupArchive(arg string) bool {
+	return strings.HasSuffix(arg, ".tar.gz") || strings.HasSuffix(arg, ".tgz")
+}
+
+// writeGroupArchive writes a gzipped tarball of the given units and metadata.
+// The unit files are placed in a directory named like the group, so that an
+// extracted archive can be used like any other group directory. In case a key
+// is given, the signature of all files is embedded.
+//
+//   inago.json
+//   inago.sig
+//   mygroup/mygroup-foo@.service
+//   mygroup/mygroup-bar@.service
+//
+func writeGroupArchive(w io.Writer, units []controller.Unit, metadata groupArchiveMetadata, key crypto.Signer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	rawMetadata, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return maskAny(err)
+	}
+	files := map[string][]byte{
+		archiveMetadataFile: append(rawMetadata, '\n'),
+	}
+	for _, unit := range units {
+		name, content := unit.SourceFile()
+		files[path.Join(metadata.Group, name)] = []byte(content)
+	}
+	if key != nil {
+		sig, err := signature.Sign(key, files)
+		if err != nil {
+			return maskAny(err)
+		}
+		files[archiveSignatureFile] = sig
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(files[name])),
+			ModTime: metadata.Created,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return maskAny(err)
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return maskAny(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return maskAny(err)
+	}
+	if err := gw.Close(); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// readGroupArchive reads a group archive as written by writeGroupArchive. The
+// returned request contains the group and its units. In case the archive is
+// malformed, an error that you can identify using IsInvalidArchive is
+// returned.
+func readGroupArchive(raw []byte) (controller.Request, groupArchiveMetadata, error) {
+	files, err := readArchiveFiles(raw)
+	if err != nil {
+		return controller.Request{}, groupArchiveMetadata{}, maskAny(err)
+	}
+
+	var metadata *groupArchiveMetadata
+	if content, ok := files[archiveMetadataFile]; ok {
+		metadata = &groupArchiveMetadata{}
+		if err := json.Unmarshal(content, metadata); err != nil {
+			return controller.Request{}, groupArchiveMetadata{}, maskAnyf(invalidArchiveError, "%s", err.Error())
+		}
+	}
+	if metadata == nil {
+		return controller.Request{}, groupArchiveMetadata{}, maskAnyf(invalidArchiveError, "%s not found", archiveMetadataFile)
+	}
+	if metadata.Group == "" {
+		return controller.Request{}, groupArchiveMetadata{}, maskAnyf(invalidArchiveError, "group not set")
+	}
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = metadata.Group
+	req := controller.NewRequest(newRequestConfig)
+
+	for name, content := range files {
+		dir, file := path.Split(name)
+		if path.Clean(dir) != metadata.Group {
+			continue
+		}
+		if common.IsEnvFile(file) {
+			envUnit, err := controller.NewEnvUnit(metadata.Group, file, string(content))
+			if err != nil {
+				return controller.Request{}, groupArchiveMetadata{}, maskAny(err)
+			}
+			req.Units = append(req.Units, envUnit)
+			continue
+		}
+		req.Units = append(req.Units, controller.Unit{Name: file, Content: string(content)})
+	}
+	if len(req.Units) == 0 {
+		return controller.Request{}, groupArchiveMetadata{}, maskAnyf(invalidArchiveError, "no unit files found for group '%s'", metadata.Group)
+	}
+	sort.Sort(unitsByName(req.Units))
+
+	return req, *metadata, nil
+}
+
+// readArchiveFiles reads all regular files of the given gzipped tarball, keyed
+// by their cleaned path. In case the archive is malformed, an error that you
+// can identify using IsInvalidArchive is returned.
+func readArchiveFiles(raw []byte) (map[string][]byte, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, maskAnyf(invalidArchiveError, "%s", err.Error())
+	}
+	tr := tar.NewReader(gr)
+
+	files := map[string][]byte{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, maskAnyf(invalidArchiveError, "%s", err.Error())
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, maskAnyf(invalidArchiveError, "%s", err.Error())
+		}
+		files[path.Clean(header.Name)] = content
+	}
+
+	return files, nil
+}
+
+type unitsByName []controller.Unit
+
+func (u unitsByName) Len() int           { return len(u) }
+func (u unitsByName) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u unitsByName) Less(i, j int) bool { return u[i].Name < u[j].Name }
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+func Test_Archive_WriteRead(t *testing.T) {
+	units := []controller.Unit{
+		{Name: "mygroup-foo@.service", Content: "[Service]\nExecStart=/bin/foo\n"},
+		{Name: "mygroup-bar@.service", Content: "[Service]\nExecStart=/bin/bar\n"},
+	}
+	metadata := groupArchiveMetadata{
+		Group:        "mygroup",
+		Scale:        2,
+		SliceIDs:     []string{"1", "2"},
+		Created:      time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC),
+		InagoVersion: "0.1.0",
+	}
+
+	archive := bytes.NewBuffer(nil)
+	if err := writeGroupArchive(archive, units, metadata, nil); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	req, readMetadata, err := readGroupArchive(archive.Bytes())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if !reflect.DeepEqual(readMetadata, metadata) {
+		t.Fatal("expected", metadata, "got", readMetadata)
+	}
+	if req.Group != "mygroup" {
+		t.Fatal("expected", "mygroup", "got", req.Group)
+	}
+	expectedUnits := []controller.Unit{units[1], units[0]}
+	if !reflect.DeepEqual(req.Units, expectedUnits) {
+		t.Fatal("expected", expectedUnits, "got", req.Units)
+	}
+}
+
+func Test_Archive_Read_Invalid(t *testing.T) {
+	noUnits := bytes.NewBuffer(nil)
+	if err := writeGroupArchive(noUnits, nil, groupArchiveMetadata{Group: "mygroup", Scale: 1}, nil); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	noGroup := bytes.NewBuffer(nil)
+	if err := writeGroupArchive(noGroup, []controller.Unit{{Name: "mygroup-foo.service"}}, groupArchiveMetadata{}, nil); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := [][]byte{
+		[]byte("not an archive"),
+		noUnits.Bytes(),
+		noGroup.Bytes(),
+	}
+
+	for i, testCase := range testCases {
+		_, _, err := readGroupArchive(testCase)
+		if !IsInvalidArchive(err) {
+			t.Fatal("case", i+1, "expected", invalidArchiveError, "got", err)
+		}
+	}
+}
+
+func Test_Archive_IsGroupArchive(t *testing.T) {
+	testCases := map[string]bool{
+		"mygroup":            false,
+		"mygroup/":           false,
+		"mygroup.tar.gz":     true,
+		"backup/mygroup.tgz": true,
+	}
+
+	for arg, expected := range testCases {
+		if isGroupArchive(arg) != expected {
+			t.Fatal("argument", arg, "expected", expected, "got", !expected)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/audit"
+	"github.com/giantswarm/inago/state"
+)
+
+const (
+	// auditNamespace is the state store namespace the audit logs of groups are
+	// stored in.
+	auditNamespace = "audit"
+
+	// maxAuditEntries is the number of operations kept per group. Older entries
+	// are dropped.
+	maxAuditEntries = 1000
+)
+
+var (
+	// auditSinks are the sinks the audit events of operations are streamed to.
+	// They are created from the configuration file.
+	auditSinks []audit.Sink
+)
+
+// auditEntry describes an operation executed for a group, as recorded in the
+// audit log of the group.
+type auditEntry struct {
+	Operation string        `json:"operation"`
+	Args      []string      `json:"args"`
+	RequestID string        `json:"request-id,omitempty"`
+	Started   time.Time     `json:"started"`
+	Duration  time.Duration `json:"duration"`
+	Succeeded bool          `json:"succeeded"`
+	Error     string        `json:"error,omitempty"`
+	Slices    []auditSlice  `json:"slices,omitempty"`
+}
+
+// auditSlice describes how long a slice took to go through the phases observed
+// during an operation.
+type auditSlice struct {
+	ID       string        `json:"id"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// groupAudit is the audit log of a group as stored in the state store.
+type groupAudit struct {
+	Entries []auditEntry `json:"entries"`
+}
+
+// loadAudit reads the audit log of the given group from the given store. A
+// group without audit log results in an empty one.
+func loadAudit(store state.Store, group string) (groupAudit, error) {
+	var audit groupAudit
+	err := store.Get(auditNamespace, group, &audit)
+	if state.IsNotFound(err) {
+		return groupAudit{}, nil
+	} else if err != nil {
+		return groupAudit{}, maskAny(err)
+	}
+
+	return audit, nil
+}
+
+// newAuditEntry creates the audit log entry of the given group of the given
+// finished report. Groups without a duration of their own, i.e. the ones not
+// operated as part of multiple groups, took as long as the whole operation.
+func newAuditEntry(r *report, gr groupReport) auditEntry {
+	entry := auditEntry{
+		Operation: r.Operation,
+		Args:      r.Args,
+		RequestID: r.RequestID,
+		Started:   r.Started,
+		Duration:  r.Finished.Sub(r.Started),
+		Succeeded: gr.Succeeded,
+		Error:     gr.Error,
+	}
+	if d, err := time.ParseDuration(gr.Duration); err == nil {
+		entry.Duration = d
+	}
+	for _, sr := range gr.Slices {
+		entry.Slices = append(entry.Slices, auditSlice{ID: sr.ID, Duration: sr.Finished.Sub(sr.Started), Error: sr.Error})
+	}
+
+	return entry
+}
+
+// recordAudit appends the given finished report to the audit logs of all
+// groups it covers.
+func recordAudit(store state.Store, r *report) error {
+	if store == nil {
+		return nil
+	}
+
+	for _, gr := range r.Groups {
+		audit, err := loadAudit(store, gr.Group)
+		if err != nil {
+			return maskAny(err)
+		}
+		audit.Entries = append(audit.Entries, newAuditEntry(r, gr))
+		if len(audit.Entries) > maxAuditEntries {
+			audit.Entries = audit.Entries[len(audit.Entries)-maxAuditEntries:]
+		}
+		if err := store.Set(auditNamespace, gr.Group, audit); err != nil {
+			return maskAny(err)
+		}
+	}
+
+	return nil
+}
+
+// newAuditSinks creates the audit sinks configured in the given
+// configuration.
+func newAuditSinks(c config) ([]audit.Sink, error) {
+	var newSinks []audit.Sink
+
+	if c.Audit.File != nil {
+		newFileConfig := audit.DefaultFileConfig()
+		newFileConfig.Path = expandHome(c.Audit.File.Path)
+		newFile, err := audit.NewFile(newFileConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		newSinks = append(newSinks, newFile)
+	}
+	if c.Audit.Syslog != nil {
+		newSyslogConfig := audit.DefaultSyslogConfig()
+		newSyslogConfig.Network = c.Audit.Syslog.Network
+		newSyslogConfig.Address = c.Audit.Syslog.Address
+		if c.Audit.Syslog.Tag != "" {
+			newSyslogConfig.Tag = c.Audit.Syslog.Tag
+		}
+		newSyslog, err := audit.NewSyslog(newSyslogConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		newSinks = append(newSinks, newSyslog)
+	}
+	if c.Audit.HTTP != nil {
+		newHTTPConfig := audit.DefaultHTTPConfig()
+		newHTTPConfig.URL = c.Audit.HTTP.URL
+		newHTTPConfig.Headers = c.Audit.HTTP.Headers
+		newHTTP, err := audit.NewHTTP(newHTTPConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		newSinks = append(newSinks, newHTTP)
+	}
+
+	return newSinks, nil
+}
+
+// auditEvents creates the audit events of all groups the given finished
+// report covers.
+func auditEvents(r *report) []audit.Event {
+	user := os.Getenv("USER")
+	host, _ := os.Hostname()
+
+	var events []audit.Event
+	for _, gr := range r.Groups {
+		entry := newAuditEntry(r, gr)
+		e := audit.Event{
+			Group:     gr.Group,
+			Operation: entry.Operation,
+			Args:      entry.Args,
+			RequestID: entry.RequestID,
+			User:      user,
+			Host:      host,
+			Started:   entry.Started,
+			Duration:  entry.Duration,
+			Succeeded: entry.Succeeded,
+			Error:     entry.Error,
+		}
+		for _, as := range entry.Slices {
+			e.Slices = append(e.Slices, audit.Slice{ID: as.ID, Duration: as.Duration, Error: as.Error})
+		}
+		events = append(events, e)
+	}
+
+	return events
+}
+
+// streamAudit writes the audit events of the given finished report to the
+// given sinks. Auditing must not fail operations, so errors are only logged.
+func streamAudit(ctx context.Context, sinks []audit.Sink, r *report) {
+	for _, e := range auditEvents(r) {
+		for _, sink := range sinks {
+			if err := sink.Write(ctx, e); err != nil {
+				newLogger.Warning(ctx, "Failed to write audit event of '%s' for group '%s'. (%s)", e.Command(), e.Group, err.Error())
+			}
+		}
+	}
+}
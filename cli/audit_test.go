@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/audit"
+	"github.com/giantswarm/inago/file-system/fake"
+)
+
+func Test_Audit_newAuditSinks(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	err := newFileSystem.WriteFile("/etc/inago/config.yaml", []byte("audit:\n  file:\n    path: /var/log/inago/audit.log\n  syslog:\n    network: udp\n    address: siem.example.com:514\n  http:\n    url: https://siem.example.com/events\n    headers:\n      Authorization: Bearer XXX\n"), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	c, err := readConfig(newFileSystem, "/etc/inago/config.yaml", false)
+	Expect(err).To(BeNil())
+	Expect(c.Audit.HTTP).To(Equal(&auditHTTPConfig{
+		URL:     "https://siem.example.com/events",
+		Headers: map[string]string{"Authorization": "Bearer XXX"},
+	}))
+
+	sinks, err := newAuditSinks(c)
+	Expect(err).To(BeNil())
+	Expect(sinks).To(HaveLen(3))
+
+	sinks, err = newAuditSinks(config{})
+	Expect(err).To(BeNil())
+	Expect(sinks).To(BeEmpty())
+
+	// Sinks missing required settings are rejected.
+	_, err = newAuditSinks(config{Audit: auditConfig{HTTP: &auditHTTPConfig{}}})
+	Expect(audit.IsInvalidConfig(err)).To(BeTrue())
+}
+
+func Test_Audit_auditEvents(t *testing.T) {
+	RegisterTestingT(t)
+
+	started := time.Date(2016, 5, 4, 12, 0, 0, 0, time.UTC)
+	r := &report{
+		Operation: "stack up",
+		Args:      []string{"mystack"},
+		RequestID: "abc",
+		Started:   started,
+		Finished:  started.Add(time.Minute),
+		Groups: []groupReport{
+			{Group: "db", Succeeded: true, Duration: "40s"},
+			{Group: "app", Error: "unit failed", Slices: []sliceReport{{ID: "1", Started: started, Finished: started.Add(5 * time.Second), Error: "unit failed"}}},
+		},
+	}
+
+	events := auditEvents(r)
+	Expect(events).To(HaveLen(2))
+	Expect(events[0].Group).To(Equal("db"))
+	Expect(events[0].Command()).To(Equal("inagoctl stack up mystack"))
+	Expect(events[0].RequestID).To(Equal("abc"))
+	Expect(events[0].Duration).To(Equal(40 * time.Second))
+	Expect(events[0].Succeeded).To(BeTrue())
+	Expect(events[1].Group).To(Equal("app"))
+	Expect(events[1].Duration).To(Equal(time.Minute))
+	Expect(events[1].Error).To(Equal("unit failed"))
+	Expect(events[1].Slices).To(Equal([]audit.Slice{{ID: "1", Duration: 5 * time.Second, Error: "unit failed"}}))
+}
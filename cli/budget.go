@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	budgetFlags struct {
+		MaxFailures string
+	}
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{startCmd, upCmd, updateCmd} {
+		cmd.PersistentFlags().StringVar(&budgetFlags.MaxFailures, "max-failures", "", "number or percentage of slices allowed to fail before the operation stops, e.g. 3 or 5%")
+	}
+}
+
+// withMaxFailures configures the given request to continue past failing
+// slices, as given using --max-failures. In case the flag is malformed, an
+// error that you can identify using IsInvalidArgumentsError is returned.
+func withMaxFailures(req controller.Request) (controller.Request, error) {
+	budget, err := controller.ParseFailureBudget(budgetFlags.MaxFailures)
+	if err != nil {
+		return controller.Request{}, maskAnyf(invalidArgumentsError, "--max-failures: %s", err.Error())
+	}
+	req.MaxFailures = budget
+
+	return req, nil
+}
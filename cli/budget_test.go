@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+func Test_Budget_withMaxFailures(t *testing.T) {
+	RegisterTestingT(t)
+	defer func() { budgetFlags.MaxFailures = "" }()
+
+	req, err := withMaxFailures(controller.Request{})
+	Expect(err).To(BeNil())
+	Expect(req.MaxFailures.IsZero()).To(BeTrue())
+
+	budgetFlags.MaxFailures = "5%"
+	req, err = withMaxFailures(controller.Request{})
+	Expect(err).To(BeNil())
+	Expect(req.MaxFailures).To(Equal(controller.FailureBudget{Percent: 5}))
+
+	budgetFlags.MaxFailures = "five"
+	_, err = withMaxFailures(controller.Request{})
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+}
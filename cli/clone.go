@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"github.com/juju/errgo"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/task"
+)
+
+var (
+	cloneFlags struct {
+		Scale int
+	}
+
+	cloneCmd = &cobra.Command{
+		Use:   "clone <group> <newname>",
+		Short: "Clone a group",
+		Long:  "Bring up a copy of a group under a new name. The unit files are read from the local group directory, or fetched from the cluster in case it does not exist. In case the copy cannot be brought up, it is destroyed again",
+		Run:   reportingRun("clone", cloneRun),
+	}
+)
+
+func init() {
+	cloneCmd.PersistentFlags().IntVar(&cloneFlags.Scale, "scale", 0, "number of slices of the copy (defaults to the number of slices of the group)")
+}
+
+func cloneRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting clone")
+
+	if len(args) != 2 {
+		cmd.Help()
+		exit(1)
+	}
+
+	req, err := createCloneRequest(resolveGroup(args[0]), args[1], cloneFlags.Scale)
+	handleCloneCmdError(err)
+
+	// Make sure we do not mess with an existing group.
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = req.Group
+	_, err = newController.GetStatus(newCtx, controller.NewRequest(newRequestConfig))
+	if err == nil {
+		newLogger.Error(newCtx, "Failed to clone group '%s'. (group '%s' already exists)", args[0], req.Group)
+		exit(1)
+	} else if !controller.IsUnitNotFound(err) {
+		handleCloneCmdError(err)
+	}
+
+	taskObject, err := newController.Submit(newCtx, req)
+	handleCloneCmdError(err)
+	err = waitForTaskResult(taskObject)
+	if err != nil {
+		rollbackClone(req, err)
+	}
+
+	startReq, err := newController.ExtendWithExistingSliceIDs(req)
+	if err != nil {
+		rollbackClone(req, err)
+	}
+	taskObject, err = newController.Start(newCtx, startReq)
+	if err != nil {
+		rollbackClone(req, err)
+	}
+	err = waitForTaskResult(taskObject)
+	if err != nil {
+		rollbackClone(req, err)
+	}
+
+	newLogger.Info(newCtx, "Succeeded to clone group '%s' to '%s'.", args[0], req.Group)
+}
+
+// createCloneRequest creates a submit request for a copy of the given group
+// named like the given new group. In case scale is 0, the scale of the
+// deployed group is used. Group names being prefixes of each other are
+// rejected.
+func createCloneRequest(group, newGroup string, scale int) (controller.Request, error) {
+	// Groups are matched by prefix, so commands targeting one group would also
+	// affect the other one.
+	if controller.StringsSharePrefix([]string{group, newGroup}) {
+		return controller.Request{}, maskAnyf(invalidArgumentsError, "group names '%s' and '%s' must not be prefixes of each other", group, newGroup)
+	}
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	req := controller.NewRequest(newRequestConfig)
+
+	localReq, err := extendRequestWithContent(fs, req)
+	if err != nil {
+		newLogger.Debug(newCtx, "cli: reading local unit files of group '%s' failed, fetching them from fleet: %#v", group, maskAny(err))
+
+		units, err := newController.DeployedUnits(newCtx, req)
+		if controller.IsUnitNotFound(err) {
+			return controller.Request{}, maskAnyf(groupNotFoundError, "%s", group)
+		} else if err != nil {
+			return controller.Request{}, maskAny(err)
+		}
+		req.Units = units
+	} else {
+		req = localReq
+	}
+
+	if scale == 0 {
+		scale = 1
+		deployedReq, err := newController.ExtendWithExistingSliceIDs(req)
+		if err != nil && !controller.IsUnitNotFound(err) {
+			return controller.Request{}, maskAny(err)
+		}
+		if len(deployedReq.SliceIDs) > scale {
+			scale = len(deployedReq.SliceIDs)
+		}
+	}
+
+	newReq := req.WithGroup(newGroup)
+	newReq.SliceIDs = nil
+	newReq.DesiredSlices = scale
+	newReq, err = withPlacementFlags(newReq)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+
+	return newReq, nil
+}
+
+// waitForTaskResult blocks until the given task reached a final status. In
+// case the task failed, its error is returned.
+func waitForTaskResult(taskObject *task.Task) error {
+	taskObject, err := newController.WaitForTask(newCtx, taskObject.ID, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	if task.HasFailedStatus(taskObject) {
+		return maskAny(taskObject.Error)
+	}
+
+	return nil
+}
+
+// rollbackClone destroys all units of the given, partially cloned group and
+// exits.
+func rollbackClone(req controller.Request, cause error) {
+	newLogger.Error(newCtx, "Failed to clone group '%s'. Destroying it again. (%s)", req.Group, errgo.Cause(cause).Error())
+
+	req, err := newController.ExtendWithExistingSliceIDs(req)
+	handleCloneCmdError(err)
+
+	taskObject, err := newController.Destroy(newCtx, req)
+	handleCloneCmdError(err)
+	err = waitForTaskResult(taskObject)
+	handleCloneCmdError(err)
+
+	exit(1)
+}
+
+func handleCloneCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"testing"
+)
+
+func Test_Clone_createCloneRequest_prefix(t *testing.T) {
+	testCases := [][]string{
+		{"myapp", "myapp-test"},
+		{"myapp-copy", "myapp"},
+		{"myapp", "myapp"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := createCloneRequest(testCase[0], testCase[1], 1)
+		if !IsInvalidArgumentsError(err) {
+			t.Fatal("expected", true, "got", false, "for", testCase)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/giantswarm/inago/file-system/spec"
+)
+
+// clusterConfig describes how to reach a fleet cluster. Clusters are defined in
+// the configuration file and chosen using --cluster.
+//
+//   clusters:
+//     prod-eu:
+//       fleet-endpoint: https://fleet.eu.example.com:49153
+//       ca-file: ~/.inago/prod-eu/ca.pem
+//       cert-file: ~/.inago/prod-eu/client.pem
+//       key-file: ~/.inago/prod-eu/client-key.pem
+//     prod-us:
+//       tunnel: bastion.us.example.com:22
+//     staging:
+//       fleet-endpoint: http://fleet.staging.example.com:49153
+//       proxy: http://proxy.example.com:3128
+//
+type clusterConfig struct {
+	FleetEndpoint string `yaml:"fleet-endpoint,omitempty"`
+	Tunnel        string `yaml:"tunnel,omitempty"`
+	CAFile        string `yaml:"ca-file,omitempty"`
+	CertFile      string `yaml:"cert-file,omitempty"`
+	KeyFile       string `yaml:"key-file,omitempty"`
+	Proxy         string `yaml:"proxy,omitempty"`
+}
+
+// flags returns the global flags set by the cluster, keyed by their names.
+// Settings being empty are left out.
+func (c clusterConfig) flags() map[string]string {
+	flags := map[string]string{}
+	for name, value := range map[string]string{
+		"fleet-endpoint": c.FleetEndpoint,
+		"tunnel":         c.Tunnel,
+		"ca-file":        c.CAFile,
+		"cert-file":      c.CertFile,
+		"key-file":       c.KeyFile,
+		"proxy":          c.Proxy,
+	} {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+
+	return flags
+}
+
+// applyCluster sets the global flags of the given flag set to the settings of
+// the cluster given using --cluster, or the cluster of the given profile in
+// case --cluster is not given otherwise. Flags given on the command line or
+// using environment variables keep their values. applyCluster is called before
+// applyProfile, so that the settings of the cluster take precedence over the
+// ones of the profile. In case the cluster does not exist, an error that you
+// can identify using IsInvalidConfig is returned.
+func applyCluster(flags *pflag.FlagSet, c config, profileName string) error {
+	name := flags.Lookup("cluster").Value.String()
+	if !flags.Lookup("cluster").Changed {
+		if profileName == "" {
+			profileName = c.CurrentProfile
+		}
+		name = c.Profiles[profileName]["cluster"]
+	}
+	if name == "" {
+		return nil
+	}
+	cluster, ok := c.Clusters[name]
+	if !ok {
+		return maskAnyf(invalidConfigError, "cluster '%s' not found", name)
+	}
+
+	if err := flags.Set("cluster", name); err != nil {
+		return maskAny(err)
+	}
+	for key, value := range cluster.flags() {
+		if flags.Lookup(key).Changed {
+			continue
+		}
+		if err := flags.Set(key, value); err != nil {
+			return maskAnyf(invalidConfigError, "cluster '%s': %s: %s", name, key, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// newTLSConfig creates the TLS configuration used to connect to fleet using
+// the given CA certificate, client certificate and client key files. A leading
+// "~/" refers to the home directory. In case no file is given, nil is
+// returned, so that the system's CAs are used.
+func newTLSConfig(fs filesystemspec.FileSystem, caFile, certFile, keyFile string) (*tls.Config, error) {
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if caFile != "" {
+		raw, err := fs.ReadFile(expandHome(caFile))
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(raw) {
+			return nil, maskAnyf(invalidArgumentsError, "no certificates found in CA file '%s'", caFile)
+		}
+	}
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, maskAnyf(invalidArgumentsError, "--cert-file and --key-file must be given together")
+		}
+		cert, err := fs.ReadFile(expandHome(certFile))
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		key, err := fs.ReadFile(expandHome(keyFile))
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, maskAnyf(invalidArgumentsError, "%s", err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	return tlsConfig, nil
+}
+
+// parseProxy parses the proxy given using --proxy. In case it is empty, nil
+// is returned, so that the proxy is taken from the environment. In case the
+// proxy is malformed, an error that you can identify using
+// IsInvalidArgumentsError is returned.
+func parseProxy(proxy string) (*url.URL, error) {
+	if proxy == "" {
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, maskAnyf(invalidArgumentsError, "--proxy: %s", err.Error())
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, maskAnyf(invalidArgumentsError, "--proxy: scheme must be one of http, https or socks5, got '%s'", proxy)
+	}
+	if proxyURL.Host == "" {
+		return nil, maskAnyf(invalidArgumentsError, "--proxy: host missing in '%s'", proxy)
+	}
+
+	return proxyURL, nil
+}
+
+var (
+	clustersCmd = &cobra.Command{
+		Use:   "clusters",
+		Short: "List the clusters of the configuration file",
+		Long:  "List the clusters defined in the configuration file given using --config. Commands are executed against a cluster using --cluster, or by setting the cluster in a profile",
+		Run:   clustersRun,
+	}
+)
+
+func clustersRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting clusters")
+
+	if len(args) != 0 {
+		cmd.Help()
+		exit(1)
+	}
+
+	c, err := readConfig(fs, globalFlags.Config, false)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+	if len(c.Clusters) == 0 {
+		newLogger.Error(newCtx, "No clusters defined in '%s'.", globalFlags.Config)
+		exit(1)
+	}
+
+	printResult(result{Kind: "clusters", Rows: createClusterList(c.Clusters, globalFlags.Cluster)})
+}
+
+// createClusterList renders the given clusters as table rows, sorted by name.
+// The given current cluster is marked.
+func createClusterList(clusters map[string]clusterConfig, current string) []string {
+	var names []string
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+
+	rows := []string{"Cluster | Current | Fleet Endpoint | Tunnel | TLS", ""}
+	for _, name := range names {
+		cluster := clusters[name]
+		secure := strings.HasPrefix(cluster.FleetEndpoint, "https://") || cluster.CAFile != "" || cluster.CertFile != ""
+		rows = append(rows, fmt.Sprintf("%s | %s | %s | %s | %s", name, yesOrNo(name == current), orDash(cluster.FleetEndpoint), orDash(cluster.Tunnel), yesOrNo(secure)))
+	}
+
+	return rows
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/giantswarm/inago/file-system/fake"
+)
+
+func Test_Cluster_applyCluster(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFlags := func() *pflag.FlagSet {
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.String("cluster", "", "")
+		flags.String("fleet-endpoint", "unix:///var/run/fleet.sock", "")
+		flags.String("tunnel", "", "")
+		flags.String("ca-file", "", "")
+		flags.String("cert-file", "", "")
+		flags.String("key-file", "", "")
+		flags.Float64("rate-limit", 20, "")
+		return flags
+	}
+
+	c := config{
+		Clusters: map[string]clusterConfig{
+			"prod-eu": {FleetEndpoint: "https://fleet.eu:49153", CAFile: "~/ca.pem"},
+			"prod-us": {Tunnel: "bastion.us:22"},
+		},
+		Profiles: map[string]profile{
+			"us": {"cluster": "prod-us", "rate-limit": "5"},
+		},
+	}
+
+	// Without cluster, flags are left alone.
+	flags := newFlags()
+	Expect(applyCluster(flags, c, "")).To(Succeed())
+	Expect(flags.Lookup("fleet-endpoint").Changed).To(BeFalse())
+
+	// Flags given otherwise keep their values.
+	flags = newFlags()
+	Expect(flags.Set("cluster", "prod-eu")).To(Succeed())
+	Expect(flags.Set("ca-file", "/etc/ca.pem")).To(Succeed())
+	Expect(applyCluster(flags, c, "")).To(Succeed())
+	Expect(flags.Lookup("fleet-endpoint").Value.String()).To(Equal("https://fleet.eu:49153"))
+	Expect(flags.Lookup("ca-file").Value.String()).To(Equal("/etc/ca.pem"))
+
+	// Profiles may choose the cluster, unless it is given otherwise.
+	flags = newFlags()
+	Expect(applyCluster(flags, c, "us")).To(Succeed())
+	Expect(applyProfile(flags, c, "us")).To(Succeed())
+	Expect(flags.Lookup("cluster").Value.String()).To(Equal("prod-us"))
+	Expect(flags.Lookup("tunnel").Value.String()).To(Equal("bastion.us:22"))
+	Expect(flags.Lookup("rate-limit").Value.String()).To(Equal("5"))
+
+	flags = newFlags()
+	Expect(flags.Set("cluster", "prod-eu")).To(Succeed())
+	c.CurrentProfile = "us"
+	Expect(applyCluster(flags, c, "")).To(Succeed())
+	Expect(flags.Lookup("fleet-endpoint").Value.String()).To(Equal("https://fleet.eu:49153"))
+	Expect(flags.Lookup("tunnel").Value.String()).To(Equal(""))
+
+	flags = newFlags()
+	Expect(flags.Set("cluster", "prod-asia")).To(Succeed())
+	Expect(IsInvalidConfig(applyCluster(flags, c, ""))).To(BeTrue())
+}
+
+func Test_Cluster_newTLSConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	Expect(newFileSystem.WriteFile("/etc/inago/invalid.pem", []byte("invalid"), os.FileMode(0644))).To(Succeed())
+
+	tlsConfig, err := newTLSConfig(newFileSystem, "", "", "")
+	Expect(err).To(BeNil())
+	Expect(tlsConfig).To(BeNil())
+
+	_, err = newTLSConfig(newFileSystem, "/etc/inago/missing.pem", "", "")
+	Expect(err).NotTo(BeNil())
+	_, err = newTLSConfig(newFileSystem, "/etc/inago/invalid.pem", "", "")
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+	_, err = newTLSConfig(newFileSystem, "", "/etc/inago/invalid.pem", "")
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+	_, err = newTLSConfig(newFileSystem, "", "/etc/inago/invalid.pem", "/etc/inago/invalid.pem")
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+}
+
+func Test_Cluster_createClusterList(t *testing.T) {
+	RegisterTestingT(t)
+
+	rows := createClusterList(map[string]clusterConfig{
+		"prod-us": {Tunnel: "bastion.us:22"},
+		"prod-eu": {FleetEndpoint: "https://fleet.eu:49153"},
+	}, "prod-us")
+	Expect(rows).To(Equal([]string{
+		"Cluster | Current | Fleet Endpoint | Tunnel | TLS",
+		"",
+		"prod-eu | no | https://fleet.eu:49153 | - | yes",
+		"prod-us | yes | - | bastion.us:22 | no",
+	}))
+}
+
+func Test_Cluster_parseProxy(t *testing.T) {
+	RegisterTestingT(t)
+
+	proxyURL, err := parseProxy("")
+	Expect(err).To(BeNil())
+	Expect(proxyURL).To(BeNil())
+
+	proxyURL, err = parseProxy("http://proxy.example.com:3128")
+	Expect(err).To(BeNil())
+	Expect(proxyURL.Host).To(Equal("proxy.example.com:3128"))
+	_, err = parseProxy("socks5://localhost:1080")
+	Expect(err).To(BeNil())
+
+	for _, proxy := range []string{"proxy.example.com:3128", "ftp://proxy.example.com", "http://", "http://%zz"} {
+		_, err = parseProxy(proxy)
+		Expect(IsInvalidArgumentsError(err)).To(BeTrue(), proxy)
+	}
+}
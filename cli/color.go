@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"strings"
+)
+
+const (
+	colorModeAuto   = "auto"
+	colorModeAlways = "always"
+	colorModeNever  = "never"
+
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorGreen  = "\x1b[32m"
+	colorReset  = "\x1b[0m"
+)
+
+// colorEnabled defines whether tables are printed using colors. See
+// useColor.
+var colorEnabled bool
+
+// useColor decides whether to print colors, given the value of --color and
+// whether stdout is a terminal. Colors are disabled by default when output is
+// redirected, e.g. to a file or another program.
+func useColor(mode string, tty bool) (bool, error) {
+	switch mode {
+	case colorModeAuto:
+		return tty, nil
+	case colorModeAlways:
+		return true, nil
+	case colorModeNever:
+		return false, nil
+	}
+
+	return false, maskAnyf(invalidArgumentsError, "--color must be one of %s, %s or %s, got '%s'", colorModeAuto, colorModeAlways, colorModeNever, mode)
+}
+
+// stateColor returns the color highlighting the given states of a unit on a
+// machine. Failed units, and units being dead even though they are supposed
+// to run, are red. Units changing their state are yellow, and active units
+// green. Other units are not colored.
+func stateColor(desired, active, sub string) string {
+	switch {
+	case active == "failed" || sub == "failed":
+		return colorRed
+	case sub == "dead" && desired == "launched":
+		return colorRed
+	case active == "activating" || active == "deactivating" || active == "reloading":
+		return colorYellow
+	case active == "active":
+		return colorGreen
+	}
+
+	return ""
+}
+
+// deployedColor returns the color highlighting groups listed as deployed.
+func deployedColor(deployed bool) string {
+	if deployed {
+		return colorGreen
+	}
+
+	return ""
+}
+
+// colorRows colors the lines of the given table, as formatted by columnize,
+// using the given colors, one per line. Lines are colored as a whole, after
+// formatting, so that escape sequences do not break the alignment of columns.
+// In case colors are disabled, the table is returned as it is.
+func colorRows(table string, colors []string) string {
+	if !colorEnabled {
+		return table
+	}
+
+	lines := strings.Split(table, "\n")
+	for i, line := range lines {
+		if i >= len(colors) || colors[i] == "" || line == "" {
+			continue
+		}
+		lines[i] = colors[i] + line + colorReset
+	}
+
+	return strings.Join(lines, "\n")
+}
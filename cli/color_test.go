@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_Color_useColor(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []struct {
+		Mode     string
+		TTY      bool
+		Expected bool
+	}{
+		{Mode: "auto", TTY: true, Expected: true},
+		{Mode: "auto", TTY: false, Expected: false},
+		{Mode: "always", TTY: false, Expected: true},
+		{Mode: "never", TTY: true, Expected: false},
+	}
+
+	for _, testCase := range testCases {
+		ok, err := useColor(testCase.Mode, testCase.TTY)
+		Expect(err).To(BeNil())
+		Expect(ok).To(Equal(testCase.Expected), testCase.Mode)
+	}
+
+	_, err := useColor("sometimes", true)
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+}
+
+func Test_Color_stateColor(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(stateColor("launched", "failed", "failed")).To(Equal(colorRed))
+	Expect(stateColor("launched", "inactive", "dead")).To(Equal(colorRed))
+	Expect(stateColor("loaded", "inactive", "dead")).To(Equal(""))
+	Expect(stateColor("launched", "activating", "start-pre")).To(Equal(colorYellow))
+	Expect(stateColor("launched", "active", "running")).To(Equal(colorGreen))
+	Expect(stateColor("launched", "-", "-")).To(Equal(""))
+}
+
+func Test_Color_colorRows(t *testing.T) {
+	RegisterTestingT(t)
+
+	table := "Group  State\n\nfoo    active\nbar    failed"
+	colors := []string{"", "", colorGreen, colorRed}
+
+	colorEnabled = false
+	Expect(colorRows(table, colors)).To(Equal(table))
+
+	colorEnabled = true
+	defer func() { colorEnabled = false }()
+	Expect(colorRows(table, colors)).To(Equal("Group  State\n\n" + colorGreen + "foo    active" + colorReset + "\n" + colorRed + "bar    failed" + colorReset))
+}
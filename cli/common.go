@@ -1,12 +1,17 @@
 package cli
 
 import (
+	"bufio"
 	"bytes"
+	"fmt"
+	"io"
 	"net"
 	"os"
 	"strings"
 	"text/template"
 
+	"github.com/juju/errgo"
+	"github.com/mattn/go-isatty"
 	"golang.org/x/net/context"
 
 	"github.com/giantswarm/inago/controller"
@@ -17,16 +22,13 @@ import (
 var (
 	statusHeader = "Group | Units | FDState | FCState | SAState {{if .Verbose}}| Hash {{end}}| IP | Machine"
 	statusBody   = "{{.Group}}{{if .UnitState.SliceID}}@{{.UnitState.SliceID}}{{end}} | {{.UnitState.Name}} | {{.UnitState.Desired}} | {{.UnitState.Current}} | " +
-		"{{.MachineState.SystemdActive}}{{if .Verbose}} | {{.MachineState.UnitHash}}{{end}} | {{if .MachineState.IP}}{{.MachineState.IP}}{{else}}-{{end}} | {{.MachineState.ID}}"
+		"{{.MachineState.SystemdActive}}{{with .MachineState.LoadError}} ({{.}}){{end}}{{if .Verbose}} | {{.MachineState.UnitHash}}{{end}} | {{if .MachineState.IP}}{{.MachineState.IP}}{{else}}-{{end}} | {{.MachineState.ID}}"
 )
 
 func createStatus(group string, usl controller.UnitStatusList) ([]string, error) {
-	if !globalFlags.Verbose {
-		var err error
-		usl, err = usl.Group()
-		if err != nil {
-			return nil, maskAny(err)
-		}
+	rows, err := statusRows(usl)
+	if err != nil {
+		return nil, maskAny(err)
 	}
 
 	out := bytes.NewBufferString("")
@@ -40,7 +42,7 @@ func createStatus(group string, usl controller.UnitStatusList) ([]string, error)
 	out.WriteString("\n\n")
 	tmpl := template.Must(template.New("row-format").Parse(statusBody))
 
-	addRow := func(group string, us, ms interface{}) {
+	for _, row := range rows {
 		tmpl.Execute(out, struct {
 			Verbose      bool
 			Group        string
@@ -49,29 +51,140 @@ func createStatus(group string, usl controller.UnitStatusList) ([]string, error)
 		}{
 			globalFlags.Verbose,
 			group,
-			us,
-			ms,
+			row.Unit,
+			row.Machine,
 		})
 		out.WriteString("\n")
 	}
 
+	return strings.Split(out.String(), "\n"), nil
+}
+
+// statusRow is a row of the status table, showing the state of a unit on a
+// machine.
+type statusRow struct {
+	Unit    fleet.UnitStatus
+	Machine fleet.MachineStatus
+}
+
+// statusRows returns the rows of the status table of the given unit states.
+// Unless running verbose, equal states of units are grouped. Units not
+// scheduled on any machine get a row without machine.
+func statusRows(usl controller.UnitStatusList) ([]statusRow, error) {
+	if !globalFlags.Verbose {
+		var err error
+		usl, err = usl.Group()
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var rows []statusRow
 	for _, us := range usl {
 		if len(us.Machine) == 0 {
-			addRow(group, us,
-				fleet.MachineStatus{
+			rows = append(rows, statusRow{
+				Unit: us,
+				Machine: fleet.MachineStatus{
 					ID:            "-",
 					IP:            net.IP{},
 					SystemdActive: "-",
 					SystemdSub:    "-",
 					UnitHash:      "-",
-				})
+				},
+			})
 		}
 		for _, ms := range us.Machine {
-			addRow(group, us, ms)
+			rows = append(rows, statusRow{Unit: us, Machine: ms})
 		}
 	}
 
-	return strings.Split(out.String(), "\n"), nil
+	return rows, nil
+}
+
+// createStatusColors returns the colors of the rows created by createStatus
+// for the given unit states, including the header and the blank line below.
+// Units in one of the ready states the given request defines for them are
+// green, e.g. oneshot units that exited.
+func createStatusColors(req controller.Request, usl controller.UnitStatusList) ([]string, error) {
+	rows, err := statusRows(usl)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	colors := []string{"", ""}
+	for _, row := range rows {
+		if req.UnitReady(row.Unit) {
+			colors = append(colors, colorGreen)
+			continue
+		}
+		colors = append(colors, stateColor(row.Unit.Desired, row.Machine.SystemdActive, row.Machine.SystemdSub))
+	}
+
+	return colors, nil
+}
+
+// createSliceSummary renders the per slice results of the given error as
+// table rows to be formatted using columnize.
+func createSliceSummary(group string, multiErr controller.MultiSliceError) []string {
+	sliceName := func(sliceID string) string {
+		if sliceID == "" {
+			return group
+		}
+		return group + "@" + sliceID
+	}
+
+	rows := []string{"Slice | Result | Error", ""}
+	for _, sliceID := range multiErr.Succeeded {
+		rows = append(rows, fmt.Sprintf("%s | succeeded | -", sliceName(sliceID)))
+	}
+	for _, sliceErr := range multiErr.Failed {
+		rows = append(rows, fmt.Sprintf("%s | failed | %s", sliceName(sliceErr.SliceID), sliceErr.Err.Error()))
+	}
+
+	return rows
+}
+
+// askForConfirmation prints the given question and reads the answer from
+// stdin. Only "y" and "yes" are considered a confirmation. The command fails in
+// case stdin is not a terminal and provides no answer, so that scripts do not
+// silently skip the confirmed operation. See readConfirmation.
+func askForConfirmation(f string, v ...interface{}) bool {
+	fmt.Printf(f+" [y/N] ", v...)
+
+	ok, err := readConfirmation(os.Stdin, isatty.IsTerminal(os.Stdin.Fd()))
+	if IsConfirmationRequired(err) {
+		fmt.Println()
+		newLogger.Error(newCtx, "Cannot ask for confirmation, stdin is not a terminal. Use --yes to confirm.")
+		exit(1)
+	} else if err != nil {
+		fmt.Println()
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+
+	return ok
+}
+
+// readConfirmation reads an answer from r. An answer not terminated by a
+// newline is accepted as well, e.g. when piped using printf. In case r is not
+// a terminal and provides no answer at all, a confirmationRequiredError is
+// returned.
+func readConfirmation(r io.Reader, terminal bool) (bool, error) {
+	answer, err := bufio.NewReader(r).ReadString('\n')
+	if err == io.EOF {
+		if strings.TrimSpace(answer) == "" && !terminal {
+			return false, maskAny(confirmationRequiredError)
+		}
+	} else if err != nil {
+		return false, maskAny(err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
 }
 
 type blockWithFeedbackCtx struct {
@@ -80,6 +193,10 @@ type blockWithFeedbackCtx struct {
 	NoBlock    bool
 	TaskID     string
 	Closer     chan struct{}
+
+	// NoProgress disables rendering the progress of the group while waiting for
+	// the task. This is useful in case the progress has already been rendered.
+	NoProgress bool
 }
 
 func maybeBlockWithFeedback(ctx context.Context, bctx blockWithFeedbackCtx) {
@@ -89,10 +206,16 @@ func maybeBlockWithFeedback(ctx context.Context, bctx blockWithFeedbackCtx) {
 	}
 
 	if !bctx.NoBlock {
+		stopProgress := func() {}
+		if !bctx.NoProgress {
+			stopProgress = startProgress(ctx, bctx.Descriptor, bctx.Request)
+		}
 		taskObject, err := newController.WaitForTask(ctx, bctx.TaskID, bctx.Closer)
+		stopProgress()
 		if err != nil {
 			newLogger.Error(ctx, "%#v", maskAny(err))
-			os.Exit(1)
+			diagnoseConnection(ctx, err)
+			exit(1)
 		}
 
 		if controller.IsUnitsAlreadyUpToDate(taskObject.Error) {
@@ -101,6 +224,14 @@ func maybeBlockWithFeedback(ctx context.Context, bctx blockWithFeedbackCtx) {
 		}
 
 		if task.HasFailedStatus(taskObject) {
+			if multiErr, ok := errgo.Cause(taskObject.Error).(controller.MultiSliceError); ok {
+				printResult(result{Kind: "slices", Rows: createSliceSummary(bctx.Request.Group, multiErr)})
+				reportTaskError(bctx.Request.Group, taskObject.Error, &multiErr)
+			} else {
+				reportTaskError(bctx.Request.Group, taskObject.Error, nil)
+			}
+			printEvent(event{Type: eventFailed, Operation: bctx.Descriptor, Group: bctx.Request.Group, Error: taskObject.Error.Error()})
+
 			if bctx.Request.SliceIDs == nil {
 				newLogger.Error(ctx, "Failed to %s group '%s'. (%s)", bctx.Descriptor, bctx.Request.Group, taskObject.Error.Error())
 			} else if len(bctx.Request.SliceIDs) == 0 {
@@ -117,8 +248,10 @@ func maybeBlockWithFeedback(ctx context.Context, bctx blockWithFeedbackCtx) {
 					taskObject.Error,
 				)
 			}
-			os.Exit(1)
+			diagnoseConnection(ctx, taskObject.Error)
+			exit(1)
 		}
+		printEvent(event{Type: eventSucceeded, Operation: bctx.Descriptor, Group: bctx.Request.Group})
 	}
 
 	if bctx.Request.SliceIDs == nil {
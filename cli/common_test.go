@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"fmt"
 	"net"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -175,6 +177,39 @@ func Test_Common_createStatus(t *testing.T) {
 				"",
 			},
 		},
+
+		// A unit systemd failed to load
+		{
+			Comment: "A unit systemd failed to load should show why",
+			Input: input{
+				Group: "example",
+				USL: controller.UnitStatusList{
+					{
+						Current: "launched",
+						Desired: "launched",
+						Machine: []fleet.MachineStatus{
+							{
+								ID:            "505e0d7802d7439a924c269b76f34b5f",
+								IP:            net.ParseIP("172.17.8.101"),
+								SystemdLoad:   "not-found",
+								SystemdActive: "inactive",
+								SystemdSub:    "dead",
+								UnitHash:      "4311",
+							},
+						},
+						Name:    "example-1@1.service",
+						SliceID: "1",
+					},
+				},
+				Verbose: false,
+			},
+			Expected: []string{
+				"Group | Units | FDState | FCState | SAState | IP | Machine",
+				"",
+				"example@1 | * | launched | launched | inactive (not-found: unit file or a unit it references was not found) | 172.17.8.101 | 505e0d7802d7439a924c269b76f34b5f",
+				"",
+			},
+		},
 	}
 
 	// execute test cases
@@ -188,6 +223,25 @@ func Test_Common_createStatus(t *testing.T) {
 	}
 }
 
+func Test_Common_createSliceSummary(t *testing.T) {
+	RegisterTestingT(t)
+
+	multiErr := controller.MultiSliceError{
+		Succeeded: []string{"1", "3"},
+		Failed: []controller.SliceError{
+			{SliceID: "2", Err: fmt.Errorf("test error")},
+		},
+	}
+
+	Expect(createSliceSummary("example", multiErr)).To(Equal([]string{
+		"Slice | Result | Error",
+		"",
+		"example@1 | succeeded | -",
+		"example@3 | succeeded | -",
+		"example@2 | failed | test error",
+	}))
+}
+
 func loadedUnitStatus(name, sliceID, machineIP, machineID, currentState, desiredState string) fleet.UnitStatus {
 	return fleet.UnitStatus{
 		Current: currentState,
@@ -215,3 +269,62 @@ func unloadedUnitStatus(name, sliceID, desiredState string) fleet.UnitStatus {
 		SliceID: sliceID,
 	}
 }
+
+func Test_Common_appendStatusTable(t *testing.T) {
+	RegisterTestingT(t)
+
+	data, colors := appendStatusTable(nil, nil, []string{"Group | Units", "", "a | 1", ""}, []string{"", "", colorGreen}, true)
+	data, colors = appendStatusTable(data, colors, []string{"Group | Units", "", "b | 2", "b | 3", ""}, []string{"", "", colorRed, ""}, false)
+
+	Expect(data).To(Equal([]string{"Group | Units", "", "a | 1", "b | 2", "b | 3"}))
+	Expect(colors).To(Equal([]string{"", "", colorGreen, colorRed, ""}))
+}
+
+func Test_Common_createSliceVersions(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(createSliceVersions("mygroup", map[string]string{"1": "", "2": ""})).To(BeNil())
+	Expect(createSliceVersions("mygroup", map[string]string{"2": "1.4.2", "1": "1.3.0", "3": ""})).To(Equal([]string{
+		"Slice | Version",
+		"mygroup@1 | 1.3.0",
+		"mygroup@2 | 1.4.2",
+		"mygroup@3 | -",
+	}))
+	Expect(createSliceVersions("mygroup", map[string]string{"": "1.4.2"})).To(Equal([]string{
+		"Slice | Version",
+		"mygroup | 1.4.2",
+	}))
+}
+
+func Test_Common_readConfirmation(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []struct {
+		Input    string
+		Terminal bool
+		Expected bool
+		Required bool
+	}{
+		{Input: "y\n", Terminal: true, Expected: true},
+		{Input: "Yes\n", Terminal: false, Expected: true},
+		{Input: "no\n", Terminal: false, Expected: false},
+		// Answers not terminated by a newline, e.g. piped using printf.
+		{Input: "yes", Terminal: false, Expected: true},
+		{Input: "n", Terminal: false, Expected: false},
+		// Hitting Ctrl-D in a terminal declines.
+		{Input: "", Terminal: true, Expected: false},
+		// Scripts not providing any answer must not silently skip the operation.
+		{Input: "", Terminal: false, Required: true},
+		{Input: "\n", Terminal: false, Expected: false},
+	}
+
+	for i, testCase := range testCases {
+		ok, err := readConfirmation(strings.NewReader(testCase.Input), testCase.Terminal)
+		if testCase.Required {
+			Expect(IsConfirmationRequired(err)).To(BeTrue(), fmt.Sprintf("test case %d", i+1))
+			continue
+		}
+		Expect(err).To(BeNil(), fmt.Sprintf("test case %d", i+1))
+		Expect(ok).To(Equal(testCase.Expected), fmt.Sprintf("test case %d", i+1))
+	}
+}
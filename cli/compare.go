@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+)
+
+// clusterSnapshot describes the state of a group on a single fleet cluster.
+type clusterSnapshot struct {
+	// Endpoint is the fleet endpoint the snapshot was taken from.
+	Endpoint string
+
+	// Deployed is false in case no unit of the group is known to the cluster.
+	Deployed bool
+
+	// Phases are the phases of the slices of the group.
+	Phases []slicePhase
+
+	// Units are the unit files deployed to the cluster. Slice IDs contained in
+	// their contents are removed, so that units of different clusters can be
+	// compared.
+	Units []controller.Unit
+}
+
+// parseClusterCompare splits the value of the --cluster-compare flag into the
+// two endpoints to compare.
+func parseClusterCompare(value string) ([]string, error) {
+	endpoints := strings.Split(value, ",")
+	if len(endpoints) != 2 || endpoints[0] == "" || endpoints[1] == "" {
+		return nil, maskAnyf(invalidArgumentsError, "expected two endpoints separated by comma, got '%s'", value)
+	}
+	for _, e := range endpoints {
+		if _, err := url.Parse(e); err != nil {
+			return nil, maskAnyf(invalidArgumentsError, "bad endpoint '%s'", e)
+		}
+	}
+
+	return endpoints, nil
+}
+
+// newClusterController creates a controller talking to the fleet API of the
+// given endpoint. Apart from the endpoint and the tunnel, which is specific to
+// the cluster given by the global flags, the global fleet configuration is
+// used.
+func newClusterController(endpoint string) (controller.Controller, error) {
+	URL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	newClusterFleetConfig := newFleetConfig
+	newClusterFleetConfig.Client = &http.Client{}
+	newClusterFleetConfig.Endpoint = *URL
+	newClusterFleetConfig.SSHTunnel = nil
+	newClusterFleet, err := fleet.NewFleet(newClusterFleetConfig)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	newControllerConfig := controller.DefaultConfig()
+	newControllerConfig.Logger = newLogger
+	newControllerConfig.Fleet = newClusterFleet
+	newControllerConfig.TaskService = newTaskService
+
+	return controller.NewController(newControllerConfig), nil
+}
+
+// fetchClusterSnapshot fetches the state of the given group from the cluster
+// the given controller talks to.
+func fetchClusterSnapshot(ctx context.Context, c controller.Controller, endpoint, group string) (clusterSnapshot, error) {
+	snapshot := clusterSnapshot{Endpoint: endpoint}
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	req := controller.NewRequest(newRequestConfig)
+
+	usl, err := c.GetStatus(ctx, req)
+	if controller.IsUnitNotFound(err) {
+		return snapshot, nil
+	} else if err != nil {
+		return clusterSnapshot{}, maskAny(err)
+	}
+	snapshot.Deployed = true
+	snapshot.Phases = slicePhases(usl)
+
+	units, err := c.DeployedUnits(ctx, req)
+	if err != nil {
+		return clusterSnapshot{}, maskAny(err)
+	}
+	// DeployedUnits fetches the units of the first slice. Their contents may
+	// refer to the slice, e.g. units writing environment files.
+	if len(snapshot.Phases) > 0 && snapshot.Phases[0].SliceID != "" {
+		sliceID := snapshot.Phases[0].SliceID
+		for i := range units {
+			units[i].Content = strings.Replace(units[i].Content, "@"+sliceID+".", "@.", -1)
+		}
+	}
+	snapshot.Units = units
+
+	return snapshot, nil
+}
+
+// compareClusterSnapshots renders the differences of the given snapshots of
+// the given group as table rows. Slices are only compared by ID in case
+// verbose is true, since slices of different clusters usually have different
+// IDs. The returned number is the number of differences found.
+func compareClusterSnapshots(group string, a, b clusterSnapshot, verbose bool) ([]string, int) {
+	rows := []string{fmt.Sprintf("Property | %s | %s | Result", a.Endpoint, b.Endpoint), ""}
+	differences := 0
+	addRow := func(property, valueA, valueB string) {
+		result := "equal"
+		if valueA == "-" || valueB == "-" {
+			result = "missing"
+		} else if valueA != valueB {
+			result = "differs"
+		}
+		if result != "equal" {
+			differences++
+		}
+		rows = append(rows, fmt.Sprintf("%s | %s | %s | %s", property, valueA, valueB, result))
+	}
+
+	addRow("deployed", yesOrNo(a.Deployed), yesOrNo(b.Deployed))
+	if !a.Deployed || !b.Deployed {
+		return rows, differences
+	}
+
+	addRow("slices", strconv.Itoa(len(a.Phases)), strconv.Itoa(len(b.Phases)))
+	addRow("active", strconv.Itoa(activeSlices(a.Phases)), strconv.Itoa(activeSlices(b.Phases)))
+	addRow("version", controller.ContentVersion(a.Units), controller.ContentVersion(b.Units))
+
+	versionsA := unitVersions(a.Units)
+	versionsB := unitVersions(b.Units)
+	for _, name := range unionKeys(versionsA, versionsB) {
+		addRow("unit "+name, valueOrDash(versionsA, name), valueOrDash(versionsB, name))
+	}
+
+	if verbose {
+		phasesA := map[string]string{}
+		for _, sp := range a.Phases {
+			phasesA[sp.SliceID] = string(sp.Phase)
+		}
+		phasesB := map[string]string{}
+		for _, sp := range b.Phases {
+			phasesB[sp.SliceID] = string(sp.Phase)
+		}
+		for _, sliceID := range unionKeys(phasesA, phasesB) {
+			addRow("slice "+group+"@"+sliceID, valueOrDash(phasesA, sliceID), valueOrDash(phasesB, sliceID))
+		}
+	}
+
+	return rows, differences
+}
+
+func activeSlices(phases []slicePhase) int {
+	active := 0
+	for _, sp := range phases {
+		if sp.Phase == phaseActive {
+			active++
+		}
+	}
+
+	return active
+}
+
+// unitVersions maps the names of the given units to the version of their
+// content. See controller.ContentVersion.
+func unitVersions(units []controller.Unit) map[string]string {
+	versions := map[string]string{}
+	for _, u := range units {
+		versions[u.Name] = controller.ContentVersion([]controller.Unit{{Content: u.Content}})
+	}
+
+	return versions
+}
+
+func unionKeys(a, b map[string]string) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func valueOrDash(m map[string]string, k string) string {
+	if v, ok := m[k]; ok {
+		return v
+	}
+
+	return "-"
+}
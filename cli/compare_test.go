@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+func Test_Compare_parseClusterCompare(t *testing.T) {
+	RegisterTestingT(t)
+
+	endpoints, err := parseClusterCompare("http://a:49153,http://b:49153")
+	Expect(err).To(BeNil())
+	Expect(endpoints).To(Equal([]string{"http://a:49153", "http://b:49153"}))
+
+	for _, value := range []string{"http://a:49153", "http://a:49153,", "a,b,c"} {
+		_, err := parseClusterCompare(value)
+		Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+	}
+}
+
+func Test_Compare_compareClusterSnapshots(t *testing.T) {
+	RegisterTestingT(t)
+
+	a := clusterSnapshot{
+		Endpoint: "http://a:49153",
+		Deployed: true,
+		Phases:   []slicePhase{{SliceID: "1", Phase: phaseActive}, {SliceID: "2", Phase: phaseActive}},
+		Units: []controller.Unit{
+			{Name: "example-foo@.service", Content: "[Service]\nExecStart=/bin/foo\n"},
+			{Name: "example-bar@.service", Content: "[Service]\nExecStart=/bin/bar\n"},
+		},
+	}
+
+	// Equal groups only differing in their slice IDs and formatting.
+	b := clusterSnapshot{
+		Endpoint: "http://b:49153",
+		Deployed: true,
+		Phases:   []slicePhase{{SliceID: "3", Phase: phaseActive}, {SliceID: "4", Phase: phaseActive}},
+		Units: []controller.Unit{
+			{Name: "example-bar@.service", Content: "[Service]\nExecStart=/bin/bar\n"},
+			{Name: "example-foo@.service", Content: "[Service]\nExecStart=  /bin/foo\n"},
+		},
+	}
+	_, differences := compareClusterSnapshots("example", a, b, false)
+	Expect(differences).To(Equal(0))
+
+	// Slices are compared by ID in verbose mode.
+	rows, differences := compareClusterSnapshots("example", a, b, true)
+	Expect(differences).To(Equal(4))
+	Expect(rows).To(ContainElement("slice example@1 | active | - | missing"))
+
+	// Missing slice and version skew.
+	b.Phases = b.Phases[:1]
+	b.Units = []controller.Unit{
+		{Name: "example-foo@.service", Content: "[Service]\nExecStart=/bin/foo --new\n"},
+	}
+	rows, differences = compareClusterSnapshots("example", a, b, false)
+	Expect(differences).To(Equal(5))
+	Expect(rows[0]).To(Equal("Property | http://a:49153 | http://b:49153 | Result"))
+	Expect(rows).To(ContainElement("slices | 2 | 1 | differs"))
+	Expect(rows).To(ContainElement("active | 2 | 1 | differs"))
+	Expect(rows).To(ContainElement(MatchRegexp(`^unit example-bar@\.service \| [0-9a-f]+ \| - \| missing$`)))
+	Expect(rows).To(ContainElement(MatchRegexp(`^unit example-foo@\.service \| [0-9a-f]+ \| [0-9a-f]+ \| differs$`)))
+
+	// Groups not deployed to a cluster are not compared any further.
+	b = clusterSnapshot{Endpoint: "http://b:49153"}
+	rows, differences = compareClusterSnapshots("example", a, b, false)
+	Expect(differences).To(Equal(1))
+	Expect(rows).To(HaveLen(3))
+	Expect(rows[2]).To(Equal("deployed | yes | no | differs"))
+}
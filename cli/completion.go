@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	completionCmd = &cobra.Command{
+		Use:   "completion",
+		Short: "Print bash completion",
+		Long: `Print a bash completion script for inagoctl. Group arguments are completed
+using the groups of the current working directory. Load it like this.
+
+    source <(inagoctl completion)`,
+		Run: completionRun,
+	}
+)
+
+func completionRun(cmd *cobra.Command, args []string) {
+	MainCmd.BashCompletionFunction = bashCompletionFunction(groupArgCommands())
+
+	err := MainCmd.GenBashCompletion(os.Stdout)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+}
+
+// groupArgCommands returns all commands taking groups as arguments.
+func groupArgCommands() []*cobra.Command {
+	return []*cobra.Command{
+		submitCmd,
+		statusCmd,
+		endpointsCmd,
+		existsCmd,
+		waitCmd,
+		startCmd,
+		stopCmd,
+		destroyCmd,
+		upCmd,
+		scaleCmd,
+		deployCmd,
+		cloneCmd,
+		exportCmd,
+		signCmd,
+		updateCmd,
+		planCmd,
+		devCmd,
+		undoCmd,
+		statsCmd,
+		topCmd,
+		pinCmd,
+		unpinCmd,
+		annotateCmd,
+		repairCmd,
+		validateCmd,
+	}
+}
+
+// bashCompletionFunction creates the bash function cobra calls in case no
+// other completion is available. For the given commands, group arguments are
+// completed using the output of "inagoctl list --local --quiet".
+func bashCompletionFunction(cmds []*cobra.Command) string {
+	var names []string
+	for _, cmd := range cmds {
+		names = append(names, strings.Replace(cmd.CommandPath(), " ", "_", -1))
+	}
+
+	out := bytes.NewBufferString("")
+	fmt.Fprintf(out, "__inagoctl_local_groups()\n{\n")
+	fmt.Fprintf(out, "    local inagoctl_out\n")
+	fmt.Fprintf(out, "    if inagoctl_out=$(inagoctl list --local --quiet 2>/dev/null); then\n")
+	fmt.Fprintf(out, "        COMPREPLY=( $( compgen -W \"${inagoctl_out[*]}\" -- \"$cur\" ) )\n")
+	fmt.Fprintf(out, "    fi\n")
+	fmt.Fprintf(out, "}\n\n")
+	fmt.Fprintf(out, "__custom_func()\n{\n")
+	fmt.Fprintf(out, "    case ${last_command} in\n")
+	fmt.Fprintf(out, "        %s)\n", strings.Join(names, " | "))
+	fmt.Fprintf(out, "            __inagoctl_local_groups\n")
+	fmt.Fprintf(out, "            return\n")
+	fmt.Fprintf(out, "            ;;\n")
+	fmt.Fprintf(out, "        *)\n")
+	fmt.Fprintf(out, "            ;;\n")
+	fmt.Fprintf(out, "    esac\n")
+	fmt.Fprintf(out, "}\n")
+
+	return out.String()
+}
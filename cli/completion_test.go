@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Completion_BashCompletionFunction(t *testing.T) {
+	output := bashCompletionFunction(groupArgCommands())
+
+	for _, expected := range []string{
+		"__custom_func()",
+		"inagoctl list --local --quiet",
+		"inagoctl_submit | inagoctl_status |",
+		"| inagoctl_validate)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Fatal("expected", expected, "to be contained in", output)
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/giantswarm/inago/file-system/spec"
+)
+
+// config is the configuration file of inagoctl, given using --config.
+//
+//   current-profile: staging
+//   profiles:
+//     staging:
+//       fleet-endpoint: http://10.0.0.1:49153
+//       tunnel: bastion.staging.example.com:22
+//       env: staging
+//   clusters:
+//     prod-eu:
+//       fleet-endpoint: https://fleet.eu.example.com:49153
+//   notifications:
+//     slack:
+//       webhook-url: https://hooks.slack.com/services/T000/B000/XXX
+//       channel: "#deployments"
+//       mention-on-failure: "<!here>"
+//     github:
+//       repository: giantswarm/inago
+//       environment: staging
+//   aliases:
+//     web: frontend-nginx
+//   audit:
+//     file:
+//       path: /var/log/inago/audit.log
+//     syslog:
+//       network: udp
+//       address: siem.example.com:514
+//     http:
+//       url: https://siem.example.com/events
+//       headers:
+//         Authorization: Bearer XXX
+//
+type config struct {
+	// CurrentProfile is the profile used unless another one is given using
+	// --profile.
+	CurrentProfile string `yaml:"current-profile,omitempty"`
+
+	// Profiles are the profiles of the configuration, keyed by name. See
+	// applyProfile.
+	Profiles map[string]profile `yaml:"profiles,omitempty"`
+
+	// Clusters are the fleet clusters of the configuration, keyed by name. See
+	// applyCluster.
+	Clusters map[string]clusterConfig `yaml:"clusters,omitempty"`
+
+	// Notifications configures the notifiers told about operations. See
+	// notifyingRun.
+	Notifications notificationsConfig `yaml:"notifications,omitempty"`
+
+	// Aliases maps short names to the names of groups, e.g. "web" to
+	// "frontend-nginx". See resolveGroup.
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+
+	// Audit configures the sinks the audit events of operations are streamed
+	// to. See newAuditSinks.
+	Audit auditConfig `yaml:"audit,omitempty"`
+}
+
+// profile maps the names of global flags to the values they default to while
+// the profile is in use, e.g. "fleet-endpoint" or "tunnel".
+type profile map[string]string
+
+type notificationsConfig struct {
+	Slack  *slackConfig  `yaml:"slack,omitempty"`
+	GitHub *githubConfig `yaml:"github,omitempty"`
+}
+
+type slackConfig struct {
+	WebhookURL       string `yaml:"webhook-url"`
+	Channel          string `yaml:"channel"`
+	MentionOnFailure string `yaml:"mention-on-failure"`
+}
+
+type auditConfig struct {
+	File   *auditFileConfig   `yaml:"file,omitempty"`
+	Syslog *auditSyslogConfig `yaml:"syslog,omitempty"`
+	HTTP   *auditHTTPConfig   `yaml:"http,omitempty"`
+}
+
+type auditFileConfig struct {
+	Path string `yaml:"path"`
+}
+
+type auditSyslogConfig struct {
+	Network string `yaml:"network,omitempty"`
+	Address string `yaml:"address,omitempty"`
+	Tag     string `yaml:"tag,omitempty"`
+}
+
+type auditHTTPConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+// githubConfig configures GitHub deployments. The token and ref default to
+// the GITHUB_TOKEN and GITHUB_SHA environment variables, as set by CI
+// systems.
+type githubConfig struct {
+	Repository  string   `yaml:"repository"`
+	Ref         string   `yaml:"ref,omitempty"`
+	Environment string   `yaml:"environment,omitempty"`
+	Token       string   `yaml:"token,omitempty"`
+	APIURL      string   `yaml:"api-url,omitempty"`
+	Operations  []string `yaml:"operations,omitempty"`
+}
+
+// readConfig reads the configuration file at the given path. A leading "~/"
+// refers to the home directory. In case the file does not exist, an empty
+// configuration is returned, unless the file is required. In case the file
+// cannot be parsed, an error that you can identify using IsInvalidConfig is
+// returned.
+func readConfig(fs filesystemspec.FileSystem, path string, required bool) (config, error) {
+	path = expandHome(path)
+
+	// The file system interface does not tell apart missing files from other
+	// errors. So we look up the file first.
+	found := false
+	if fileInfos, err := fs.ReadDir(filepath.Dir(path)); err == nil {
+		for _, fileInfo := range fileInfos {
+			if fileInfo.Name() == filepath.Base(path) && !fileInfo.IsDir() {
+				found = true
+			}
+		}
+	}
+	if !found && !required {
+		return config{}, nil
+	}
+
+	raw, err := fs.ReadFile(path)
+	if err != nil {
+		return config{}, maskAny(err)
+	}
+	var c config
+	if err := yaml.Unmarshal(raw, &c); err != nil {
+		return config{}, maskAnyf(invalidConfigError, "%s: %s", path, err.Error())
+	}
+
+	return c, nil
+}
+
+// writeConfig writes the given configuration to the configuration file at the
+// given path. A leading "~/" refers to the home directory. Comments of the file
+// are not preserved.
+func writeConfig(fs filesystemspec.FileSystem, path string, c config) error {
+	raw, err := yaml.Marshal(c)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := fs.WriteFile(expandHome(path), raw, os.FileMode(0600)); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// expandHome replaces a leading "~/" of the given path with the home
+// directory.
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(os.Getenv("HOME"), path[2:])
+	}
+
+	return path
+}
+
+// applyProfile sets the global flags of the given flag set to the values of
+// the given profile of the given configuration. Flags given on the command
+// line or using environment variables keep their values. Without profile,
+// the current profile of the configuration is applied, if any. In case the
+// profile does not exist, or sets unknown flags or invalid values, an error
+// that you can identify using IsInvalidConfig is returned.
+func applyProfile(flags *pflag.FlagSet, c config, name string) error {
+	if name == "" {
+		name = c.CurrentProfile
+	}
+	if name == "" {
+		return nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return maskAnyf(invalidConfigError, "profile '%s' not found", name)
+	}
+
+	for _, key := range p.keys() {
+		if err := checkProfileFlag(flags, key); err != nil {
+			return maskAnyf(invalidConfigError, "profile '%s': %s", name, err.Error())
+		}
+		if flags.Lookup(key).Changed {
+			continue
+		}
+		if err := flags.Set(key, p[key]); err != nil {
+			return maskAnyf(invalidConfigError, "profile '%s': %s: %s", name, key, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// checkProfileFlag checks whether profiles may set the given flag of the
+// given flag set. Flags choosing the configuration cannot be set by profiles.
+func checkProfileFlag(flags *pflag.FlagSet, name string) error {
+	if name == "config" || name == "profile" || flags.Lookup(name) == nil {
+		return maskAnyf(invalidArgumentsError, "unknown flag '%s'", name)
+	}
+
+	return nil
+}
+
+func (p profile) keys() []string {
+	var keys []string
+	for key := range p {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+var (
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration file",
+		Long:  "Manage the profiles of the configuration file given using --config. A profile sets defaults of global flags, e.g. the fleet endpoint or tunnel of a cluster, and is used in case it is the current profile, or given using --profile",
+		Run:   configRun,
+	}
+
+	configSetProfileCmd = &cobra.Command{
+		Use:   "set-profile <name> [flag=value...]",
+		Short: "Create or change a profile",
+		Long:  "Create the given profile, or change it, setting the given global flags, e.g. fleet-endpoint=http://10.0.0.1:49153. An empty value removes the flag from the profile",
+		Run:   configSetProfileRun,
+	}
+
+	configUseProfileCmd = &cobra.Command{
+		Use:   "use-profile <name>",
+		Short: "Set the current profile",
+		Long:  "Set the current profile, used unless another one is given using --profile",
+		Run:   configUseProfileRun,
+	}
+
+	configViewCmd = &cobra.Command{
+		Use:   "view",
+		Short: "Print the configuration",
+		Long:  "Print the configuration file",
+		Run:   configViewRun,
+	}
+)
+
+func init() {
+	configCmd.AddCommand(configSetProfileCmd)
+	configCmd.AddCommand(configUseProfileCmd)
+	configCmd.AddCommand(configViewCmd)
+}
+
+func configRun(cmd *cobra.Command, args []string) {
+	cmd.Help()
+}
+
+func configSetProfileRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting config set-profile")
+
+	if len(args) == 0 {
+		cmd.Help()
+		exit(1)
+	}
+	name := args[0]
+
+	c, err := readConfig(fs, globalFlags.Config, false)
+	handleConfigCmdError(err)
+	c, err = setProfile(cmd.Root().PersistentFlags(), c, name, args[1:])
+	handleConfigCmdError(err)
+	err = writeConfig(fs, globalFlags.Config, c)
+	handleConfigCmdError(err)
+
+	newLogger.Info(newCtx, "Profile '%s' written to '%s'.", name, globalFlags.Config)
+}
+
+func configUseProfileRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting config use-profile")
+
+	if len(args) != 1 {
+		cmd.Help()
+		exit(1)
+	}
+	name := args[0]
+
+	c, err := readConfig(fs, globalFlags.Config, false)
+	handleConfigCmdError(err)
+	if _, ok := c.Profiles[name]; !ok {
+		handleConfigCmdError(maskAnyf(invalidArgumentsError, "profile '%s' not found", name))
+	}
+	c.CurrentProfile = name
+	err = writeConfig(fs, globalFlags.Config, c)
+	handleConfigCmdError(err)
+
+	newLogger.Info(newCtx, "Using profile '%s'.", name)
+}
+
+func configViewRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting config view")
+
+	c, err := readConfig(fs, globalFlags.Config, false)
+	handleConfigCmdError(err)
+	raw, err := yaml.Marshal(c)
+	handleConfigCmdError(err)
+
+	fmt.Print(string(raw))
+}
+
+// setProfile sets the given "flag=value" settings in the given profile of the
+// given configuration, creating the profile if necessary. Settings having an
+// empty value remove the flag from the profile. In case a setting is malformed
+// or refers to a flag profiles cannot set, an error that you can identify
+// using IsInvalidArgumentsError is returned.
+func setProfile(flags *pflag.FlagSet, c config, name string, settings []string) (config, error) {
+	p := profile{}
+	for key, value := range c.Profiles[name] {
+		p[key] = value
+	}
+
+	for _, setting := range settings {
+		i := strings.Index(setting, "=")
+		if i <= 0 {
+			return config{}, maskAnyf(invalidArgumentsError, "setting must have the form flag=value, got '%s'", setting)
+		}
+		key := strings.TrimPrefix(setting[:i], "--")
+		if err := checkProfileFlag(flags, key); err != nil {
+			return config{}, maskAny(err)
+		}
+		if setting[i+1:] == "" {
+			delete(p, key)
+			continue
+		}
+		p[key] = setting[i+1:]
+	}
+
+	profiles := map[string]profile{}
+	for n, existing := range c.Profiles {
+		profiles[n] = existing
+	}
+	profiles[name] = p
+	c.Profiles = profiles
+
+	return c, nil
+}
+
+func handleConfigCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/giantswarm/inago/file-system/fake"
+	"github.com/giantswarm/inago/logging"
+	"github.com/giantswarm/inago/notify"
+)
+
+func Test_Config_readConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFileSystem := filesystemfake.NewFileSystem()
+
+	// A missing file is fine, unless it is required.
+	c, err := readConfig(newFileSystem, "/etc/inago/config.yaml", false)
+	Expect(err).To(BeNil())
+	Expect(c).To(Equal(config{}))
+	_, err = readConfig(newFileSystem, "/etc/inago/config.yaml", true)
+	Expect(err).NotTo(BeNil())
+
+	err = newFileSystem.WriteFile("/etc/inago/config.yaml", []byte("notifications:\n  slack:\n    webhook-url: https://hooks.slack.com/services/T000/B000/XXX\n    channel: \"#deployments\"\n    mention-on-failure: \"<!here>\"\n"), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	c, err = readConfig(newFileSystem, "/etc/inago/config.yaml", false)
+	Expect(err).To(BeNil())
+	Expect(c.Notifications.Slack).To(Equal(&slackConfig{
+		WebhookURL:       "https://hooks.slack.com/services/T000/B000/XXX",
+		Channel:          "#deployments",
+		MentionOnFailure: "<!here>",
+	}))
+
+	newNotifiers, err := newNotifiers(c)
+	Expect(err).To(BeNil())
+	Expect(newNotifiers).To(HaveLen(1))
+
+	err = newFileSystem.WriteFile("/etc/inago/config.yaml", []byte("notifications: [\n"), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	_, err = readConfig(newFileSystem, "/etc/inago/config.yaml", false)
+	Expect(IsInvalidConfig(err)).To(BeTrue())
+}
+
+func Test_Config_newNotifiers_GitHub(t *testing.T) {
+	RegisterTestingT(t)
+
+	c := config{Notifications: notificationsConfig{GitHub: &githubConfig{Repository: "giantswarm/inago", Ref: "master"}}}
+
+	newLogger = logging.NewLogger(logging.DefaultConfig())
+
+	// The token defaults to the one of the environment. Without it, the
+	// notifier is skipped, so that commands do not fail outside of CI.
+	defer os.Setenv("GITHUB_TOKEN", os.Getenv("GITHUB_TOKEN"))
+	os.Setenv("GITHUB_TOKEN", "")
+	skipped, err := newNotifiers(c)
+	Expect(err).To(BeNil())
+	Expect(skipped).To(BeEmpty())
+
+	os.Setenv("GITHUB_TOKEN", "XXX")
+	configured, err := newNotifiers(c)
+	Expect(err).To(BeNil())
+	Expect(configured).To(HaveLen(1))
+
+	// Invalid configurations still fail.
+	c.Notifications.GitHub.Repository = "inago"
+	_, err = newNotifiers(c)
+	Expect(notify.IsInvalidConfig(err)).To(BeTrue())
+}
+
+func Test_Config_profiles(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFlags := func() *pflag.FlagSet {
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.String("config", "", "")
+		flags.String("fleet-endpoint", "unix:///var/run/fleet.sock", "")
+		flags.String("tunnel", "", "")
+		flags.Float64("rate-limit", 20, "")
+		return flags
+	}
+
+	c, err := setProfile(newFlags(), config{}, "staging", []string{"fleet-endpoint=http://10.0.0.1:49153", "--tunnel=bastion:22"})
+	Expect(err).To(BeNil())
+	c, err = setProfile(newFlags(), c, "production", []string{"tunnel=bastion:22", "rate-limit=5"})
+	Expect(err).To(BeNil())
+	// Empty values remove flags from profiles.
+	c, err = setProfile(newFlags(), c, "production", []string{"tunnel="})
+	Expect(err).To(BeNil())
+	Expect(c.Profiles).To(Equal(map[string]profile{
+		"staging":    {"fleet-endpoint": "http://10.0.0.1:49153", "tunnel": "bastion:22"},
+		"production": {"rate-limit": "5"},
+	}))
+
+	_, err = setProfile(newFlags(), c, "staging", []string{"fleet-endpont=http://10.0.0.1:49153"})
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+	_, err = setProfile(newFlags(), c, "staging", []string{"config=other.yaml"})
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+	_, err = setProfile(newFlags(), c, "staging", []string{"tunnel"})
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	c.CurrentProfile = "staging"
+	err = writeConfig(newFileSystem, "/etc/inago/config.yaml", c)
+	Expect(err).To(BeNil())
+	read, err := readConfig(newFileSystem, "/etc/inago/config.yaml", true)
+	Expect(err).To(BeNil())
+	Expect(read).To(Equal(c))
+
+	// Flags given otherwise keep their values.
+	flags := newFlags()
+	Expect(flags.Set("tunnel", "other:22")).To(Succeed())
+	Expect(applyProfile(flags, read, "")).To(Succeed())
+	Expect(flags.Lookup("fleet-endpoint").Value.String()).To(Equal("http://10.0.0.1:49153"))
+	Expect(flags.Lookup("tunnel").Value.String()).To(Equal("other:22"))
+
+	flags = newFlags()
+	Expect(applyProfile(flags, read, "production")).To(Succeed())
+	Expect(flags.Lookup("fleet-endpoint").Value.String()).To(Equal("unix:///var/run/fleet.sock"))
+	Expect(flags.Lookup("rate-limit").Value.String()).To(Equal("5"))
+
+	Expect(IsInvalidConfig(applyProfile(newFlags(), read, "unknown"))).To(BeTrue())
+	read.Profiles["production"]["rate-limit"] = "fast"
+	Expect(IsInvalidConfig(applyProfile(newFlags(), read, "production"))).To(BeTrue())
+
+	// Without profiles, flags are left alone.
+	Expect(applyProfile(newFlags(), config{}, "")).To(Succeed())
+}
@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	deployFlags struct {
+		BlueGreen bool
+		Yes       bool
+	}
+
+	deployCmd = &cobra.Command{
+		Use:   "deploy <group> [scale]",
+		Short: "Deploy a group",
+		Long:  "Deploy a group to the cluster. With --blue-green a new version of the group is brought up alongside the old one, which is destroyed once the new version is healthy",
+		Run:   reportingRun("deploy", deployRun),
+	}
+)
+
+func init() {
+	deployCmd.PersistentFlags().BoolVar(&deployFlags.BlueGreen, "blue-green", false, "bring up a new version of the group next to the old one before destroying the old one")
+	deployCmd.PersistentFlags().BoolVar(&deployFlags.Yes, "yes", false, "do not ask for confirmation before destroying the old version")
+}
+
+func deployRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting deploy")
+
+	if !deployFlags.BlueGreen {
+		upRun(cmd, args)
+		return
+	}
+
+	group := ""
+	scale := 0
+	switch len(args) {
+	case 1:
+		group = resolveGroup(args[0])
+	case 2:
+		group = resolveGroup(args[0])
+		n, err := strconv.Atoi(args[1])
+		handleDeployCmdError(err)
+		scale = n
+	default:
+		cmd.Help()
+		exit(1)
+	}
+
+	confirm := askForConfirmation
+	if deployFlags.Yes {
+		confirm = func(string, ...interface{}) bool { return true }
+	}
+	err := deployBlueGreen(group, scale, confirm)
+	if controller.IsTimeout(err) {
+		exit(1)
+	}
+	handleDeployCmdError(err)
+}
+
+// deployBlueGreen brings up a new version of the given group next to its old
+// versions, and destroys the old versions once the new one is running, in
+// case confirm returns true. In case the new version does not become running,
+// the old versions are kept and an error that you can identify using
+// controller.IsTimeout is returned. A scale of 0 keeps the scale of the old
+// versions.
+func deployBlueGreen(group string, scale int, confirm func(string, ...interface{}) bool) error {
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	group = controller.NewRequest(newRequestConfig).Group
+
+	oldVersions, err := newController.ExistingVersions(newCtx, group)
+	if err != nil {
+		return maskAny(err)
+	}
+	var oldReqs []controller.Request
+	for _, v := range oldVersions {
+		oldReq, err := versionedGroupRequest(group, v)
+		if err != nil {
+			return maskAny(err)
+		}
+		oldReqs = append(oldReqs, oldReq)
+	}
+
+	// The group may have been deployed without --blue-green before. It is
+	// replaced like an old version. Units of the old versions and of the new
+	// one, which is up before the old units are destroyed, are not part of it.
+	req, err := createSubmitRequest(fs, group, 1)
+	if err != nil {
+		return maskAny(err)
+	}
+	version := controller.ContentVersion(req.Units)
+	unversionedReq, ok, err := unversionedGroupRequest(req, append([]string{version}, oldVersions...))
+	if err != nil {
+		return maskAny(err)
+	}
+	if ok {
+		oldReqs = append(oldReqs, unversionedReq)
+	}
+	var oldGroups []string
+	for _, oldReq := range oldReqs {
+		oldGroups = append(oldGroups, oldReq.Group)
+	}
+
+	// In case no scale is given we keep the scale of the old versions.
+	if scale == 0 {
+		scale = 1
+		for _, oldReq := range oldReqs {
+			if len(oldReq.SliceIDs) > scale {
+				scale = len(oldReq.SliceIDs)
+			}
+		}
+	}
+
+	req, err = withDesiredSlices(req, scale)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	for _, v := range oldVersions {
+		if v == version {
+			newLogger.Info(newCtx, "Not deploying group '%s'. (version %s already deployed)", group, version)
+			return nil
+		}
+	}
+	newReq := req.WithVersion(version)
+
+	// Bring up the new version.
+	taskObject, err := newController.Submit(newCtx, newReq)
+	if err != nil {
+		return maskAny(err)
+	}
+	maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+		Request:    newReq,
+		Descriptor: "submit",
+		NoBlock:    false,
+		TaskID:     taskObject.ID,
+		Closer:     nil,
+	})
+
+	newReq, err = newController.ExtendWithExistingSliceIDs(newReq)
+	if err != nil {
+		return maskAny(err)
+	}
+	taskObject, err = newController.Start(newCtx, newReq)
+	if err != nil {
+		return maskAny(err)
+	}
+	maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+		Request:    newReq,
+		Descriptor: "start",
+		NoBlock:    false,
+		TaskID:     taskObject.ID,
+		Closer:     nil,
+	})
+
+	// Check the health of the new version before touching the old one. Units
+	// are healthy once they reached their ready states.
+	err = newController.WaitForStatus(newCtx, newReq, nil, controller.StatusRunning)
+	if controller.IsTimeout(err) {
+		newLogger.Error(newCtx, "Group '%s' is not running. Keeping old versions %v.", newReq.Group, oldGroups)
+		return maskAny(err)
+	} else if err != nil {
+		return maskAny(err)
+	}
+
+	if len(oldReqs) == 0 {
+		return nil
+	}
+
+	if !confirm("Destroy old versions %v of group '%s'?", oldGroups, group) {
+		newLogger.Info(newCtx, "Keeping old versions %v of group '%s'.", oldGroups, group)
+		return nil
+	}
+
+	// Tear down the old versions.
+	for _, oldReq := range oldReqs {
+		taskObject, err := newController.Stop(newCtx, oldReq)
+		if err != nil {
+			return maskAny(err)
+		}
+		maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+			Request:    oldReq,
+			Descriptor: "stop",
+			NoBlock:    false,
+			TaskID:     taskObject.ID,
+			Closer:     nil,
+		})
+
+		taskObject, err = newController.Destroy(newCtx, oldReq)
+		if err != nil {
+			return maskAny(err)
+		}
+		maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+			Request:    oldReq,
+			Descriptor: "destroy",
+			NoBlock:    false,
+			TaskID:     taskObject.ID,
+			Closer:     nil,
+		})
+	}
+
+	return nil
+}
+
+// versionedGroupRequest returns a request for the given version of the given
+// group, extended with the slice IDs currently deployed.
+func versionedGroupRequest(group, version string) (controller.Request, error) {
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = controller.VersionedGroup(group, version)
+	req := controller.NewRequest(newRequestConfig)
+
+	req, err := newController.ExtendWithExistingSliceIDs(req)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+
+	return req, nil
+}
+
+// unversionedGroupRequest returns a request for the units of the given group
+// deployed without a version, e.g. using up, extended with the slice IDs
+// currently deployed. Only units of the unit files of the given request are
+// considered, so that other groups sharing the prefix are not replaced. Units
+// of the given versions of the group are excluded. The returned bool is false
+// in case no such unit is deployed.
+func unversionedGroupRequest(req controller.Request, versions []string) (controller.Request, bool, error) {
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = req.Group
+	unversionedReq := controller.NewRequest(newRequestConfig)
+	unversionedReq.ExcludeVersions = versions
+
+	statusList, err := newController.GetStatus(newCtx, unversionedReq)
+	if controller.IsUnitNotFound(err) {
+		return controller.Request{}, false, nil
+	} else if err != nil {
+		return controller.Request{}, false, maskAny(err)
+	}
+
+	var bases []string
+	for _, unit := range req.Units {
+		bases = append(bases, common.UnitBase(unit.Name))
+	}
+	deployed := false
+	for _, us := range statusList {
+		if containsString(bases, common.UnitBase(us.Name)) {
+			deployed = true
+			break
+		}
+	}
+	if !deployed {
+		return controller.Request{}, false, nil
+	}
+
+	unversionedReq.Units = req.Units
+	unversionedReq, err = newController.ExtendWithExistingSliceIDs(unversionedReq)
+	if err != nil {
+		return controller.Request{}, false, maskAny(err)
+	}
+	unversionedReq.Units = nil
+
+	return unversionedReq, true, nil
+}
+
+func handleDeployCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
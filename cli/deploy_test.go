@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+	filesystemfake "github.com/giantswarm/inago/file-system/fake"
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/logging"
+	"github.com/giantswarm/inago/state"
+	"github.com/giantswarm/inago/task"
+)
+
+// failingWaitController fails waiting for groups to reach a status, like a
+// group that never becomes running does.
+type failingWaitController struct {
+	controller.Controller
+}
+
+func (c failingWaitController) WaitForStatus(ctx context.Context, req controller.Request, closer <-chan struct{}, desiredStatuses ...controller.Status) error {
+	return maskAny(context.DeadlineExceeded)
+}
+
+// givenSimulatedCluster configures the CLI to operate on a simulated cluster
+// and a local group "demo".
+func givenSimulatedCluster(t *testing.T) {
+	newLoggingConfig := logging.DefaultConfig()
+	newLoggingConfig.LogLevel = "ERROR"
+	newLogger = logging.NewLogger(newLoggingConfig)
+	newStateStore = state.NewMemoryStore()
+
+	newSimulatorConfig := fleet.DefaultSimulatorConfig()
+	newSimulatorConfig.Logger = newLogger
+	newSimulatorConfig.Latency = 10 * time.Millisecond
+	newSimulator, err := fleet.NewSimulator(newSimulatorConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newTaskServiceConfig := task.DefaultConfig()
+	newTaskServiceConfig.Logger = newLogger
+	newTaskServiceConfig.WaitSleep = 10 * time.Millisecond
+	newControllerConfig := controller.DefaultConfig()
+	newControllerConfig.Logger = newLogger
+	newControllerConfig.Fleet = newSimulator
+	newControllerConfig.TaskService = task.NewTaskService(newTaskServiceConfig)
+	newControllerConfig.WaitSleep = 10 * time.Millisecond
+	newControllerConfig.WaitTimeout = 5 * time.Second
+	newController = controller.NewController(newControllerConfig)
+	newCtx = context.Background()
+
+	fs = filesystemfake.NewFileSystem()
+	err = fs.WriteFile("demo/demo-main@.service", []byte("[Service]\nExecStart=/bin/true\n"), os.FileMode(0644))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	deployFlags.BlueGreen = false
+	deployFlags.Yes = false
+}
+
+// deployedUnits returns the names of the units of the group "demo" and of its
+// versions that are supposed to run.
+func deployedUnits(t *testing.T) []string {
+	usl, err := newController.GetStatus(newCtx, controller.NewRequest(controller.RequestConfig{Group: "demo"}))
+	if controller.IsUnitNotFound(err) {
+		return nil
+	} else if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	var names []string
+	for _, us := range usl {
+		if us.Desired == "launched" {
+			names = append(names, us.Name)
+		}
+	}
+
+	return names
+}
+
+func Test_Deploy_fallsBackToUp(t *testing.T) {
+	RegisterTestingT(t)
+	givenSimulatedCluster(t)
+
+	deployRun(deployCmd, []string{"demo", "2"})
+
+	Expect(deployedUnits(t)).To(HaveLen(2))
+	versions, err := newController.ExistingVersions(newCtx, "demo")
+	Expect(err).To(BeNil())
+	Expect(versions).To(BeEmpty())
+}
+
+func Test_Deploy_deployBlueGreen(t *testing.T) {
+	RegisterTestingT(t)
+	givenSimulatedCluster(t)
+	deployRun(deployCmd, []string{"demo", "2"})
+	unversioned := deployedUnits(t)
+
+	// The new version is kept next to the old one, in case destroying the old
+	// one is not confirmed.
+	var asked bool
+	err := deployBlueGreen("demo", 0, func(string, ...interface{}) bool {
+		asked = true
+		return false
+	})
+	Expect(err).To(BeNil())
+	Expect(asked).To(BeTrue())
+	versions, err := newController.ExistingVersions(newCtx, "demo")
+	Expect(err).To(BeNil())
+	Expect(versions).To(HaveLen(1))
+	Expect(deployedUnits(t)).To(HaveLen(4))
+	for _, name := range unversioned {
+		Expect(deployedUnits(t)).To(ContainElement(name))
+	}
+
+	// Confirming destroys all old versions, including the unversioned one.
+	err = fs.WriteFile("demo/demo-main@.service", []byte("[Service]\nExecStart=/bin/sleep 10\n"), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	err = deployBlueGreen("demo", 0, func(string, ...interface{}) bool { return true })
+	Expect(err).To(BeNil())
+	newVersions, err := newController.ExistingVersions(newCtx, "demo")
+	Expect(err).To(BeNil())
+	Expect(newVersions).To(HaveLen(1))
+	Expect(newVersions).NotTo(Equal(versions))
+	Expect(deployedUnits(t)).To(HaveLen(2))
+}
+
+func Test_Deploy_deployBlueGreen_NotRunning(t *testing.T) {
+	RegisterTestingT(t)
+	givenSimulatedCluster(t)
+	deployRun(deployCmd, []string{"demo", "2"})
+	unversioned := deployedUnits(t)
+
+	// Old versions must be left alone in case the new version does not become
+	// running, without asking to destroy them.
+	newController = failingWaitController{Controller: newController}
+	err := deployBlueGreen("demo", 0, func(string, ...interface{}) bool {
+		t.Fatal("expected", "no confirmation", "got", "confirmation")
+		return true
+	})
+	Expect(controller.IsTimeout(err)).To(BeTrue())
+	for _, name := range unversioned {
+		Expect(deployedUnits(t)).To(ContainElement(name))
+	}
+}
@@ -1,8 +1,6 @@
 package cli
 
 import (
-	"os"
-
 	"github.com/spf13/cobra"
 
 	"github.com/giantswarm/inago/controller"
@@ -12,17 +10,34 @@ var (
 	destroyCmd = &cobra.Command{
 		Use:   "destroy <group[@slice]...>",
 		Short: "Destroy a group",
-		Long:  "Destroy the specified group, or slices",
-		Run:   destroyRun,
+		Long:  "Destroy the specified group, or slices. Multiple groups, or all groups of the current working directory using --all-local, are destroyed in parallel. Using --match, the units selected by a regular expression over their names are destroyed instead",
+		Run:   notifyingRun("destroy", destroyRun),
 	}
 )
 
 func destroyRun(cmd *cobra.Command, args []string) {
 	newLogger.Debug(newCtx, "cli: starting destroy")
 
+	if matchFlags.Match != "" {
+		if len(args) != 0 {
+			cmd.Help()
+			exit(1)
+		}
+		destroyMatchRun()
+		return
+	}
+
+	if groups, _, ok, err := parseMultiGroupArgs(fs, args, multiGroupFlags.AllLocal, false); err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	} else if ok {
+		runMultiGroup("destroy", groups, existingGroupAction(newController.Destroy))
+		return
+	}
+
 	if len(args) == 0 {
 		cmd.Help()
-		os.Exit(1)
+		exit(1)
 	}
 
 	var err error
@@ -30,7 +45,7 @@ func destroyRun(cmd *cobra.Command, args []string) {
 	newRequestConfig.Group, newRequestConfig.SliceIDs, err = parseGroupCLIArgs(args)
 	if err != nil {
 		newLogger.Error(newCtx, "%#v", maskAny(err))
-		os.Exit(1)
+		exit(1)
 	}
 	req := controller.NewRequest(newRequestConfig)
 
@@ -40,14 +55,40 @@ func destroyRun(cmd *cobra.Command, args []string) {
 		req, err = newController.ExtendWithExistingSliceIDs(req)
 		if err != nil {
 			newLogger.Error(newCtx, "%#v", maskAny(err))
-			os.Exit(1)
+			diagnoseConnection(newCtx, err)
+			exit(1)
 		}
 	}
 
 	taskObject, err := newController.Destroy(newCtx, req)
 	if err != nil {
 		newLogger.Error(newCtx, "%#v", maskAny(err))
-		os.Exit(1)
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+
+	maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+		Request:    req,
+		Descriptor: "destroy",
+		NoBlock:    globalFlags.NoBlock,
+		TaskID:     taskObject.ID,
+		Closer:     nil,
+	})
+}
+
+// destroyMatchRun destroys the units selected using --match.
+func destroyMatchRun() {
+	req, err := matchRequest()
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+
+	taskObject, err := newController.Destroy(newCtx, req)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
 	}
 
 	maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/spec"
+	"github.com/giantswarm/inago/task"
+)
+
+var (
+	devFlags struct {
+		Interval time.Duration
+	}
+
+	devCmd = &cobra.Command{
+		Use:   "dev <group>",
+		Short: "Deploy a group on every change",
+		Long:  "Watch the directory of a group on the local filesystem and validate and deploy the group on every change, until interrupted. Groups with slices are updated without waiting between slices, groups without slices are replaced. Meant to be used against a development cluster, or the simulator backend",
+		Run:   devRun,
+	}
+)
+
+func init() {
+	devCmd.PersistentFlags().DurationVar(&devFlags.Interval, "interval", time.Second, "interval of checking the group directory for changes")
+}
+
+// devUpdateOptions are used to update groups with slices during development.
+// Availability does not matter, so all slices are replaced right away.
+var devUpdateOptions = controller.UpdateOptions{
+	MaxGrowth: 1,
+	MinAlive:  0,
+	ReadySecs: 0,
+}
+
+func devRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting dev")
+
+	if len(args) != 1 {
+		cmd.Help()
+		exit(1)
+	}
+	group := resolveGroup(args[0])
+	if ok, err := isLocalGroup(fs, group); err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	} else if !ok {
+		newLogger.Error(newCtx, "%#v", maskAnyf(groupNotFoundError, "no group directory '%s' found", group))
+		exit(1)
+	}
+	if devFlags.Interval <= 0 {
+		newLogger.Error(newCtx, "%#v", maskAnyf(invalidArgumentsError, "--interval must be positive"))
+		exit(1)
+	}
+
+	newLogger.Info(newCtx, "Watching group '%s' for changes. Press Ctrl-C to stop.", group)
+
+	// The file system is polled, like the cluster is polled for unit states.
+	// The first fingerprint never equals the empty one, so that the group is
+	// deployed right away.
+	last := ""
+	for {
+		fingerprint, err := groupFingerprint(fs, group)
+		if err != nil {
+			// Reading fails e.g. while an editor replaces a file. The error is
+			// reported once, until the group can be read again.
+			fingerprint = err.Error()
+		}
+		if fingerprint != last {
+			last = fingerprint
+			if err != nil {
+				newLogger.Error(newCtx, "Failed to read group '%s': %s", group, err.Error())
+			} else {
+				devDeployWithFeedback(newCtx, group)
+			}
+		}
+
+		time.Sleep(devFlags.Interval)
+	}
+}
+
+// devDeployWithFeedback deploys the given group using devDeploy and reports
+// the result. Failures do not exit, so that they can be fixed while watching.
+func devDeployWithFeedback(ctx context.Context, group string) {
+	newLogger.Info(ctx, "Deploying group '%s'.", group)
+
+	start := time.Now()
+	err := devDeploy(ctx, group)
+	if controller.IsUnitsAlreadyUpToDate(err) {
+		newLogger.Info(ctx, "Group '%s' is already up to date.", group)
+	} else if err != nil {
+		newLogger.Error(ctx, "Failed to deploy group '%s': %s", group, err.Error())
+		diagnoseConnection(ctx, err)
+	} else {
+		newLogger.Info(ctx, "Succeeded to deploy group '%s' in %s.", group, time.Since(start)/time.Millisecond*time.Millisecond)
+	}
+}
+
+// devDeploy validates the given local group and deploys it. Groups with slices
+// are updated using devUpdateOptions, or brought up in case none of their
+// slices exists. Groups without slices cannot be updated, so they are
+// destroyed and brought up again.
+func devDeploy(ctx context.Context, group string) error {
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	req, err := extendRequestWithContent(fs, controller.NewRequest(newRequestConfig))
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := lintRequest(req); err != nil {
+		return maskAny(err)
+	}
+	if ok, err := controller.ValidateRequest(req); !ok {
+		validationErr := err.(controller.ValidationError)
+		return maskAnyf(invalidGroupError, "%s", strings.TrimSpace(FormatValidationError(validationErr)))
+	}
+
+	if strings.Contains(req.Units[0].Name, "@") {
+		req, err = newController.ExtendWithExistingSliceIDs(req)
+		if err != nil {
+			return maskAny(err)
+		}
+		if len(req.SliceIDs) > 0 {
+			req = withStagger(withRetry(req))
+			return waitForGroupTask(ctx, func() (*task.Task, error) {
+				return newController.Update(ctx, req, devUpdateOptions)
+			})
+		}
+	} else {
+		ok, err := newController.Exists(ctx, req)
+		if err != nil {
+			return maskAny(err)
+		}
+		if ok {
+			err := waitForGroupTask(ctx, func() (*task.Task, error) {
+				return newController.Destroy(ctx, req)
+			})
+			if err != nil {
+				return maskAny(err)
+			}
+		}
+	}
+
+	if err := submitUpGroup(ctx, group, 1, false); err != nil {
+		return maskAny(err)
+	}
+
+	return existingGroupAction(newController.Start)(ctx, group)
+}
+
+// groupFingerprint returns a fingerprint of the unit files of the given group,
+// including their drop-ins and its overlay given using --env. The fingerprint
+// changes whenever a unit file or drop-in is added, removed or changed.
+func groupFingerprint(fs filesystemspec.FileSystem, group string) (string, error) {
+	unitFiles, err := readUnitFiles(fs, group)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	unitFiles, err = applyDropIns(fs, group, group, unitFiles)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	unitFiles, err = applyOverlay(fs, group, unitFiles)
+	if err != nil {
+		return "", maskAny(err)
+	}
+
+	var names []string
+	for name := range unitFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha1.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s\x00%s\x00", name, unitFiles[name])
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
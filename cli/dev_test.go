@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/file-system/fake"
+)
+
+func Test_Dev_groupFingerprint(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	Expect(newFileSystem.WriteFile("mygroup/mygroup-main@.service", []byte("[Service]\nExecStart=/bin/app\n"), os.FileMode(0644))).To(Succeed())
+	Expect(newFileSystem.WriteFile("mygroup/mygroup-sidekick@.service", []byte("[Service]\nExecStart=/bin/sidekick\n"), os.FileMode(0644))).To(Succeed())
+
+	fingerprint, err := groupFingerprint(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(fingerprint).NotTo(BeEmpty())
+
+	// Reading unchanged files results in the same fingerprint.
+	unchanged, err := groupFingerprint(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(unchanged).To(Equal(fingerprint))
+
+	// Files not belonging to the group are ignored.
+	Expect(newFileSystem.WriteFile("mygroup/README.md", []byte("# My Group\n"), os.FileMode(0644))).To(Succeed())
+	unchanged, err = groupFingerprint(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(unchanged).To(Equal(fingerprint))
+
+	// Changing a unit file changes the fingerprint.
+	Expect(newFileSystem.WriteFile("mygroup/mygroup-main@.service", []byte("[Service]\nExecStart=/bin/app --debug\n"), os.FileMode(0644))).To(Succeed())
+	changed, err := groupFingerprint(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(changed).NotTo(Equal(fingerprint))
+
+	// Adding a unit file changes the fingerprint.
+	Expect(newFileSystem.WriteFile("mygroup/mygroup-cache@.service", []byte("[Service]\nExecStart=/bin/cache\n"), os.FileMode(0644))).To(Succeed())
+	added, err := groupFingerprint(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(added).NotTo(Equal(changed))
+
+	// Missing groups cannot be fingerprinted.
+	_, err = groupFingerprint(newFileSystem, "othergroup")
+	Expect(err).NotTo(BeNil())
+}
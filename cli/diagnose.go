@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+// diagnoseConnection explains why fleet could not be reached in case the
+// given error indicates a connection failure. Other errors are ignored.
+func diagnoseConnection(ctx context.Context, err error) {
+	if !fleet.IsConnectionFailed(err) {
+		return
+	}
+
+	printDiagnoses(os.Stderr, fleet.Diagnose(ctx, newFleetConfig))
+}
+
+// printDiagnoses prints the given diagnoses followed by the hint of the
+// failed one.
+//
+//   Diagnosing the connection to fleet:
+//     ok    socket /var/run/fleet.sock exists
+//     fail  socket /var/run/fleet.sock accepts connections (dial unix /var/run/fleet.sock: connect: permission denied)
+//
+//   The current user is not allowed to access /var/run/fleet.sock. Run inagoctl as root, or as a member of the group owning the socket.
+//
+func printDiagnoses(w io.Writer, diagnoses []fleet.Diagnosis) {
+	fmt.Fprintln(w, "Diagnosing the connection to fleet:")
+	hint := ""
+	for _, d := range diagnoses {
+		if d.Err == nil {
+			fmt.Fprintf(w, "  ok    %s\n", d.Check)
+			continue
+		}
+		fmt.Fprintf(w, "  fail  %s (%s)\n", d.Check, d.Err.Error())
+		hint = d.Hint
+	}
+	if hint != "" {
+		fmt.Fprintf(w, "\n%s\n", hint)
+	}
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/juju/errgo"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_Diagnose_printDiagnoses(t *testing.T) {
+	diagnoses := []fleet.Diagnosis{
+		{Check: "socket /var/run/fleet.sock exists"},
+		{Check: "socket /var/run/fleet.sock accepts connections", Err: errgo.New("permission denied"), Hint: "Run inagoctl as root."},
+	}
+
+	expected := `Diagnosing the connection to fleet:
+  ok    socket /var/run/fleet.sock exists
+  fail  socket /var/run/fleet.sock accepts connections (permission denied)
+
+Run inagoctl as root.
+`
+
+	out := bytes.NewBuffer(nil)
+	printDiagnoses(out, diagnoses)
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
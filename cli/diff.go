@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around changes.
+const diffContext = 3
+
+// diffLine is a single line of a diff. Kind is ' ' for unchanged lines, '-'
+// for removed lines and '+' for added lines.
+type diffLine struct {
+	Kind byte
+	Text string
+
+	// Old and New are the zero based indexes of the line in the old and new
+	// text, as far as the line is part of it.
+	Old int
+	New int
+}
+
+// unifiedDiff returns the changes between the given texts in the unified diff
+// format. An empty string is returned in case the texts are equal.
+//
+//   --- mygroup-foo@.service (deployed)
+//   +++ mygroup-foo@.service (local)
+//   @@ -1,3 +1,3 @@
+//    [Unit]
+//   -Description=foo
+//   +Description=bar
+//
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	out := fmt.Sprintf("--- %s\n+++ %s\n", oldName, newName)
+	for i := 0; i < len(lines); {
+		if lines[i].Kind == ' ' {
+			i++
+			continue
+		}
+
+		// Find the end of the hunk. Changes closer to each other than twice the
+		// context are merged into one hunk.
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(lines) && j <= end+2*diffContext; j++ {
+			if lines[j].Kind != ' ' {
+				end = j
+			}
+		}
+		stop := end + diffContext + 1
+		if stop > len(lines) {
+			stop = len(lines)
+		}
+
+		out += hunk(lines[start:stop])
+		i = stop
+	}
+
+	return out
+}
+
+// hunk renders the given lines as a single hunk including its header.
+func hunk(lines []diffLine) string {
+	var oldStart, oldCount, newStart, newCount int
+	oldStart, newStart = -1, -1
+	body := ""
+	for _, l := range lines {
+		if l.Kind != '+' {
+			if oldStart < 0 {
+				oldStart = l.Old
+			}
+			oldCount++
+		}
+		if l.Kind != '-' {
+			if newStart < 0 {
+				newStart = l.New
+			}
+			newCount++
+		}
+		body += string(l.Kind) + l.Text + "\n"
+	}
+
+	// Empty ranges refer to the line before the hunk. Otherwise line numbers
+	// start at one.
+	if oldCount == 0 {
+		oldStart = lines[0].Old
+	} else {
+		oldStart++
+	}
+	if newCount == 0 {
+		newStart = lines[0].New
+	} else {
+		newStart++
+	}
+
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount) + body
+}
+
+// diffLines computes the changes between the given lines based on their
+// longest common subsequence. Unit files are small, so the quadratic
+// complexity does not matter.
+func diffLines(a, b []string) []diffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{Kind: ' ', Text: a[i], Old: i, New: j})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{Kind: '-', Text: a[i], Old: i, New: j})
+			i++
+		default:
+			lines = append(lines, diffLine{Kind: '+', Text: b[j], Old: i, New: j})
+			j++
+		}
+	}
+
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"testing"
+)
+
+func Test_Diff_unifiedDiff(t *testing.T) {
+	testCases := []struct {
+		Old      string
+		New      string
+		Expected string
+	}{
+		{
+			Old:      "[Unit]\nDescription=foo\n",
+			New:      "[Unit]\nDescription=foo\n",
+			Expected: "",
+		},
+		{
+			Old: "[Unit]\nDescription=foo\n\n[Service]\nExecStart=/bin/foo\n",
+			New: "[Unit]\nDescription=bar\n\n[Service]\nExecStart=/bin/foo\n",
+			Expected: `--- old
++++ new
+@@ -1,5 +1,5 @@
+ [Unit]
+-Description=foo
++Description=bar
+ 
+ [Service]
+ ExecStart=/bin/foo
+`,
+		},
+		{
+			Old: "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n",
+			New: "A\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n",
+			Expected: `--- old
++++ new
+@@ -1,4 +1,4 @@
+-a
++A
+ b
+ c
+ d
+@@ -11,3 +11,4 @@
+ k
+ l
+ m
++n
+`,
+		},
+		{
+			Old: "",
+			New: "a\n",
+			Expected: `--- old
++++ new
+@@ -0,0 +1,1 @@
++a
+`,
+		},
+	}
+
+	for i, testCase := range testCases {
+		output := unifiedDiff("old", "new", testCase.Old, testCase.New)
+		if output != testCase.Expected {
+			t.Fatalf("case %d: expected:\n%s\ngot:\n%s", i+1, testCase.Expected, output)
+		}
+	}
+}
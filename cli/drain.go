@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/task"
+)
+
+var (
+	drainFlags struct {
+		Groups []string
+	}
+
+	drainCmd = &cobra.Command{
+		Use:   "drain <machine-id|ip>",
+		Short: "Drain a machine",
+		Long:  "Reschedule all slices of all groups running on the given machine. Affected slices are destroyed, submitted again and started, so that fleet schedules them on other machines",
+		Run:   reportingRun("drain", drainRun),
+	}
+)
+
+func init() {
+	drainCmd.PersistentFlags().StringSliceVar(&drainFlags.Groups, "group", nil, "group to drain instead of all known groups having units on the machine (can be given multiple times)")
+}
+
+func drainRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting drain")
+
+	if len(args) != 1 || args[0] == "" {
+		cmd.Help()
+		exit(1)
+	}
+	machine := args[0]
+
+	groups := resolveGroups(drainFlags.Groups)
+	if len(groups) == 0 {
+		// Only groups managed by inago are drained, i.e. the groups of the
+		// current working directory and the groups deployed with labels. Units
+		// of other groups need to be drained using --group.
+		local, err := localGroups(fs)
+		handleDrainCmdError(err)
+		groups, err = newController.MachineGroups(newCtx, machine, local)
+		handleDrainCmdError(err)
+	}
+
+	var drained []controller.Request
+	for _, group := range groups {
+		req, err := createDrainRequest(group, machine)
+		handleDrainCmdError(err)
+		if req.Units == nil {
+			newLogger.Debug(newCtx, "cli: group '%s' has no slices on machine '%s'", group, machine)
+			continue
+		}
+
+		steps := []struct {
+			Descriptor string
+			Action     func(context.Context, controller.Request) (*task.Task, error)
+		}{
+			{Descriptor: "destroy", Action: newController.Destroy},
+			{Descriptor: "submit", Action: newController.Submit},
+			{Descriptor: "start", Action: newController.Start},
+		}
+		for _, step := range steps {
+			taskObject, err := step.Action(newCtx, req)
+			handleDrainCmdError(err)
+
+			maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+				Request:    req,
+				Descriptor: step.Descriptor,
+				NoBlock:    false,
+				TaskID:     taskObject.ID,
+				Closer:     nil,
+			})
+		}
+		drained = append(drained, req)
+	}
+
+	if len(drained) == 0 {
+		newLogger.Info(newCtx, "No slices found on machine '%s'. Use --group to drain groups not found in the current working directory.", machine)
+		return
+	}
+
+	// Fleet is free to schedule slices on the drained machine again. We can
+	// only tell the user about it.
+	for _, req := range drained {
+		sliceIDs, err := newController.MachineSlices(newCtx, req, machine)
+		handleDrainCmdError(err)
+		for _, sliceID := range sliceIDs {
+			if sliceID == "" {
+				newLogger.Warning(newCtx, "Group '%s' has been scheduled on machine '%s' again.", req.Group, machine)
+				continue
+			}
+			newLogger.Warning(newCtx, "Slice '%s' of group '%s' has been scheduled on machine '%s' again.", sliceID, req.Group, machine)
+		}
+	}
+
+	newLogger.Info(newCtx, "Succeeded to drain machine '%s'.", machine)
+}
+
+// createDrainRequest creates a request for all slices of the given group
+// scheduled on the given machine. The units of the request are fetched from
+// fleet, so that slices are rescheduled using the deployed unit files. In case
+// the group has no slices on the machine, the returned request has no units.
+func createDrainRequest(group, machine string) (controller.Request, error) {
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	req := controller.NewRequest(newRequestConfig)
+	req.Units = nil
+
+	sliceIDs, err := newController.MachineSlices(newCtx, req, machine)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+	if len(sliceIDs) == 0 {
+		return req, nil
+	}
+
+	req.Units, err = newController.DeployedUnits(newCtx, req)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+	// Groups without slices are rescheduled as a whole.
+	if len(sliceIDs) != 1 || sliceIDs[0] != "" {
+		req.SliceIDs = sliceIDs
+	}
+
+	return req, nil
+}
+
+func handleDrainCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
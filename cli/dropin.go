@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/file-system/spec"
+)
+
+const (
+	// dropInDirSuffix is the suffix of directories containing the drop-ins of
+	// the unit file named like the directory without the suffix, like systemd
+	// expects them.
+	//
+	//   mygroup/
+	//     mygroup-main@.service
+	//     mygroup-main@.service.d/
+	//       override.conf
+	//
+	dropInDirSuffix = ".d"
+
+	// dropInFileExt is the extension of drop-in files. Other files of drop-in
+	// directories are ignored, like systemd does.
+	dropInFileExt = ".conf"
+)
+
+// readDropInFiles reads the drop-ins of the unit files of the given group
+// found in the given directory and returns a map of path => filecontent. Paths
+// are relative to the directory, e.g. "mygroup-main@.service.d/override.conf".
+func readDropInFiles(fs filesystemspec.FileSystem, dir, group string) (map[string]string, error) {
+	fileInfos, err := fs.ReadDir(dir)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	dropIns := map[string]string{}
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() || !strings.HasPrefix(fileInfo.Name(), group) || !strings.HasSuffix(fileInfo.Name(), dropInDirSuffix) {
+			continue
+		}
+
+		dropInInfos, err := fs.ReadDir(filepath.Join(dir, fileInfo.Name()))
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		for _, dropInInfo := range dropInInfos {
+			if dropInInfo.IsDir() || filepath.Ext(dropInInfo.Name()) != dropInFileExt {
+				continue
+			}
+
+			path := filepath.Join(fileInfo.Name(), dropInInfo.Name())
+			raw, err := fs.ReadFile(filepath.Join(dir, path))
+			if err != nil {
+				return nil, maskAny(err)
+			}
+			dropIns[path] = string(raw)
+		}
+	}
+
+	return dropIns, nil
+}
+
+// applyDropIns merges the drop-ins of the given group found in the given
+// directory into the given unit files, see appendDropIn. fleet does not know
+// about drop-ins, so they are part of the unit files submitted. Drop-ins are
+// applied in the order of their file names, like systemd does. In case a
+// drop-in directory has no unit file, an error that you can identify using
+// IsInvalidGroup is returned.
+func applyDropIns(fs filesystemspec.FileSystem, dir, group string, unitFiles map[string]string) (map[string]string, error) {
+	dropIns, err := readDropInFiles(fs, dir, group)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if len(dropIns) == 0 {
+		return unitFiles, nil
+	}
+
+	var paths []string
+	for path := range dropIns {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	merged := map[string]string{}
+	for name, content := range unitFiles {
+		merged[name] = content
+	}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Dir(path), dropInDirSuffix)
+		base, ok := merged[name]
+		if !ok || common.IsEnvFile(name) {
+			return nil, maskAnyf(invalidGroupError, "drop-in '%s' of group '%s' has no unit file '%s'", filepath.Join(dir, path), group, name)
+		}
+		merged[name] = appendDropIn(base, dropIns[path])
+	}
+
+	return merged, nil
+}
+
+// appendDropIn appends the options set by the given drop-in to the sections of
+// the given unit file. Options of the unit file are kept, so that systemd
+// evaluates them like it evaluates drop-ins: options taking a list of values
+// are extended, unless reset by an empty assignment, and the last assignment
+// of all other options wins. Sections only present in the drop-in are
+// appended.
+//
+//   [Service]              [Service]                  [Service]
+//   ExecStart=/bin/app  +  Environment=ENV=stg     =  ExecStart=/bin/app
+//                                                     Environment=ENV=stg
+//
+func appendDropIn(unitFile, dropIn string) string {
+	return mergeUnitFileOptions(unitFile, dropIn, false)
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/fake"
+)
+
+func Test_DropIn_appendDropIn(t *testing.T) {
+	RegisterTestingT(t)
+
+	unitFile := "[Unit]\n" +
+		"Description=My App\n" +
+		"\n" +
+		"[Service]\n" +
+		"Environment=ENV=prod\n" +
+		"ExecStart=/bin/app\n"
+	dropIn := "# Staging settings\n" +
+		"[Service]\n" +
+		"Environment=ENV=staging\n" +
+		"\n" +
+		"[X-Fleet]\n" +
+		"MachineMetadata=env=staging\n"
+
+	Expect(appendDropIn(unitFile, dropIn)).To(Equal("[Unit]\n" +
+		"Description=My App\n" +
+		"\n" +
+		"[Service]\n" +
+		"Environment=ENV=prod\n" +
+		"ExecStart=/bin/app\n" +
+		"Environment=ENV=staging\n" +
+		"\n" +
+		"[X-Fleet]\n" +
+		"MachineMetadata=env=staging\n"))
+}
+
+func Test_DropIn_extendRequestWithContent(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	files := map[string]string{
+		"mygroup/mygroup-main@.service":                                "[Service]\nExecStart=/bin/app\n",
+		"mygroup/mygroup-main@.service.d/20-restart.conf":              "[Service]\nRestart=always\n",
+		"mygroup/mygroup-main@.service.d/10-limits.conf":               "[Service]\nLimitNOFILE=4096\n",
+		"mygroup/mygroup-main@.service.d/README":                       "Not a drop-in.\n",
+		"mygroup/overlays/staging/mygroup-main@.service.d/50-env.conf": "[Service]\nEnvironment=ENV=staging\n",
+	}
+	for name, content := range files {
+		err := newFileSystem.WriteFile(name, []byte(content), os.FileMode(0644))
+		Expect(err).To(BeNil())
+	}
+
+	mainContent := func() (string, error) {
+		newRequestConfig := controller.DefaultRequestConfig()
+		newRequestConfig.Group = "mygroup"
+		req, err := extendRequestWithContent(newFileSystem, controller.NewRequest(newRequestConfig))
+		if err != nil {
+			return "", err
+		}
+		Expect(req.Units).To(HaveLen(1))
+		return req.Units[0].Content, nil
+	}
+
+	// Drop-ins are applied in the order of their names.
+	content, err := mainContent()
+	Expect(err).To(BeNil())
+	Expect(content).To(Equal("[Service]\nExecStart=/bin/app\nLimitNOFILE=4096\nRestart=always\n"))
+
+	// Overlays can have drop-ins as well.
+	globalFlags.Env = "staging"
+	defer func() { globalFlags.Env = "" }()
+	content, err = mainContent()
+	Expect(err).To(BeNil())
+	Expect(content).To(Equal("[Service]\nExecStart=/bin/app\nLimitNOFILE=4096\nRestart=always\nEnvironment=ENV=staging\n"))
+
+	// Signatures cover drop-ins.
+	definitionFiles, err := groupDefinitionFiles(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(definitionFiles).To(HaveKey("mygroup-main@.service.d/10-limits.conf"))
+	Expect(definitionFiles).To(HaveKey("overlays/staging/mygroup-main@.service.d/50-env.conf"))
+	Expect(definitionFiles).To(HaveLen(4))
+
+	// Drop-ins of unit files not part of the group are rejected.
+	err = newFileSystem.WriteFile("mygroup/mygroup-other.service.d/override.conf", []byte("[Service]\nRestart=always\n"), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	_, err = mainContent()
+	Expect(IsInvalidGroup(err)).To(BeTrue())
+}
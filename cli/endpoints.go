@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+)
+
+var (
+	endpointsFlags struct {
+		Port int
+	}
+
+	endpointsCmd = &cobra.Command{
+		Use:   "endpoints <group>",
+		Short: "Print the addresses of the active slices of a group",
+		Long:  "Print the IP of the machine each active slice of a group runs on, e.g. to generate upstream lists of load balancers. Using --port, the port the slices listen on is added to the addresses. Using --format hosts, entries for /etc/hosts are printed, naming slices like myapp-1",
+		Run:   endpointsRun,
+	}
+)
+
+func init() {
+	endpointsCmd.PersistentFlags().IntVar(&endpointsFlags.Port, "port", 0, "port the slices listen on, added to their addresses")
+}
+
+func endpointsRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting endpoints")
+
+	if len(args) != 1 {
+		cmd.Help()
+		exit(1)
+	}
+	if endpointsFlags.Port < 0 || endpointsFlags.Port > 65535 {
+		handleEndpointsCmdError(maskAnyf(invalidArgumentsError, "--port must be between 1 and 65535, got %d", endpointsFlags.Port))
+	}
+	tmpl, err := parseOutput(outputFlags.Output)
+	handleEndpointsCmdError(err)
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = resolveGroup(args[0])
+	req := controller.NewRequest(newRequestConfig)
+	req, err = newController.ExtendWithExistingSliceIDs(req)
+	handleEndpointsCmdError(err)
+	statusList, err := newController.GetStatus(newCtx, req)
+	handleEndpointsCmdError(err)
+
+	endpoints := createEndpoints(req.Group, statusList, endpointsFlags.Port)
+	if tmpl != nil {
+		err := executeOutput(os.Stdout, tmpl, endpoints)
+		handleEndpointsCmdError(err)
+		return
+	}
+	printResult(result{Kind: "endpoints", Rows: endpoints.rows(), Data: endpoints})
+}
+
+// endpoint is the address of an active slice of a group.
+type endpoint struct {
+	Group   string `json:"group"`
+	SliceID string `json:"slice-id"`
+	Machine string `json:"machine"`
+	IP      string `json:"ip"`
+	Port    int    `json:"port,omitempty"`
+
+	// Address is the IP, joined with the port in case it is given, e.g.
+	// "10.0.0.101:8080".
+	Address string `json:"address"`
+}
+
+// hostname names the slice of the endpoint in /etc/hosts, e.g. "myapp-1".
+// Groups without slices are named like the group.
+func (e endpoint) hostname() string {
+	if e.SliceID == "" {
+		return e.Group
+	}
+
+	return e.Group + "-" + e.SliceID
+}
+
+// endpointList is the result of endpoints, sorted by slice ID.
+type endpointList []endpoint
+
+// rows renders the endpoints as table rows. Machine IDs are shortened.
+func (l endpointList) rows() []string {
+	rows := []string{"Slice | Machine | Address", ""}
+	for _, e := range l {
+		name := e.Group
+		if e.SliceID != "" {
+			name += "@" + e.SliceID
+		}
+		machine := e.Machine
+		if len(machine) > 8 {
+			machine = machine[:8]
+		}
+		rows = append(rows, fmt.Sprintf("%s | %s | %s", name, machine, e.Address))
+	}
+
+	return rows
+}
+
+func (l endpointList) hostsEntries() []string {
+	var entries []string
+	for _, e := range l {
+		entries = append(entries, e.IP+" "+e.hostname())
+	}
+
+	return entries
+}
+
+// createEndpoints returns the addresses of the active slices of the given
+// group. Slices running units on multiple machines have an endpoint for each
+// machine. The given port is added to the addresses, unless it is zero.
+func createEndpoints(group string, usl []fleet.UnitStatus, port int) endpointList {
+	endpoints := endpointList{}
+	for _, sp := range slicePhases(usl) {
+		if sp.Phase != phaseActive {
+			continue
+		}
+
+		seen := map[string]bool{}
+		for _, us := range usl {
+			if us.SliceID != sp.SliceID {
+				continue
+			}
+			for _, ms := range us.Machine {
+				if ms.IP == nil || seen[ms.IP.String()] {
+					continue
+				}
+				seen[ms.IP.String()] = true
+
+				e := endpoint{Group: group, SliceID: sp.SliceID, Machine: ms.ID, IP: ms.IP.String(), Port: port, Address: ms.IP.String()}
+				if port > 0 {
+					e.Address = net.JoinHostPort(e.IP, strconv.Itoa(port))
+				}
+				endpoints = append(endpoints, e)
+			}
+		}
+	}
+
+	return endpoints
+}
+
+func handleEndpointsCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
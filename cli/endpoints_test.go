@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_Endpoints_createEndpoints(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := func(name, sliceID, active, machine, ip string) fleet.UnitStatus {
+		return fleet.UnitStatus{
+			Name:    name,
+			SliceID: sliceID,
+			Current: "launched",
+			Desired: "launched",
+			Machine: []fleet.MachineStatus{{ID: machine, IP: net.ParseIP(ip), SystemdActive: active, SystemdSub: "running"}},
+		}
+	}
+	usl := []fleet.UnitStatus{
+		unit("myapp-main@1.service", "1", "active", "505e0d7802d7439a924c269b76f34b5f", "10.0.0.101"),
+		unit("myapp-side@1.service", "1", "active", "505e0d7802d7439a924c269b76f34b5f", "10.0.0.101"),
+		unit("myapp-main@2.service", "2", "failed", "9ebb53b04b0d46fb94b4fd1b3f562d2b", "10.0.0.102"),
+		unit("myapp-main@3.service", "3", "active", "9ebb53b04b0d46fb94b4fd1b3f562d2b", "10.0.0.102"),
+	}
+
+	// Only active slices are endpoints.
+	endpoints := createEndpoints("myapp", usl, 0)
+	Expect(endpoints).To(HaveLen(2))
+	Expect(endpoints[0]).To(Equal(endpoint{Group: "myapp", SliceID: "1", Machine: "505e0d7802d7439a924c269b76f34b5f", IP: "10.0.0.101", Address: "10.0.0.101"}))
+	Expect(endpoints[1].SliceID).To(Equal("3"))
+
+	endpoints = createEndpoints("myapp", usl, 8080)
+	Expect(endpoints[1].Address).To(Equal("10.0.0.102:8080"))
+	Expect(endpoints.rows()).To(Equal([]string{
+		"Slice | Machine | Address",
+		"",
+		"myapp@1 | 505e0d78 | 10.0.0.101:8080",
+		"myapp@3 | 9ebb53b0 | 10.0.0.102:8080",
+	}))
+
+	var out bytes.Buffer
+	Expect(hostsPrinter{}.PrintResult(&out, result{Kind: "endpoints", Data: endpoints})).To(Succeed())
+	Expect(out.String()).To(Equal("10.0.0.101 myapp-1\n10.0.0.102 myapp-3\n"))
+
+	err := hostsPrinter{}.PrintResult(&out, result{Kind: "status", Data: statusOutput{Group: "myapp"}})
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+
+	Expect(createEndpoints("myapp", nil, 0)).To(BeEmpty())
+}
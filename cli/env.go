@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// envPrefix is the prefix of environment variables overriding flags.
+const envPrefix = "INAGO_"
+
+// envName returns the name of the environment variable overriding the given
+// flag.
+//
+//   fleet-endpoint  =>  INAGO_FLEET_ENDPOINT
+//   ssh-timeout     =>  INAGO_SSH_TIMEOUT
+//
+func envName(flag string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(flag, "-", "_", -1))
+}
+
+// bindEnvironment sets all flags of the given flag set not given on the
+// command line using the environment variables named according to envName.
+// Flags given on the command line always take precedence. The given lookup
+// function is usually os.LookupEnv. In case the value of an environment
+// variable cannot be parsed, an error that you can identify using
+// IsInvalidEnvironment is returned.
+func bindEnvironment(flags *pflag.FlagSet, lookup func(string) (string, bool)) error {
+	var err error
+	flags.VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed {
+			return
+		}
+
+		name := envName(f.Name)
+		value, ok := lookup(name)
+		if !ok {
+			return
+		}
+
+		if setErr := flags.Set(f.Name, value); setErr != nil {
+			err = maskAnyf(invalidEnvironmentError, "%s: %s", name, setErr.Error())
+		}
+	})
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func Test_Env_envName(t *testing.T) {
+	testCases := map[string]string{
+		"fleet-endpoint":               "INAGO_FLEET_ENDPOINT",
+		"tunnel":                       "INAGO_TUNNEL",
+		"ssh-strict-host-key-checking": "INAGO_SSH_STRICT_HOST_KEY_CHECKING",
+	}
+
+	for flag, expected := range testCases {
+		if output := envName(flag); output != expected {
+			t.Fatal("flag", flag, "expected", expected, "got", output)
+		}
+	}
+}
+
+func Test_Env_bindEnvironment(t *testing.T) {
+	var flags struct {
+		FleetEndpoint string
+		Tunnel        string
+		NoBlock       bool
+		SSHTimeout    time.Duration
+	}
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flagSet.StringVar(&flags.FleetEndpoint, "fleet-endpoint", "unix:///var/run/fleet.sock", "")
+	flagSet.StringVar(&flags.Tunnel, "tunnel", "", "")
+	flagSet.BoolVar(&flags.NoBlock, "no-block", false, "")
+	flagSet.DurationVar(&flags.SSHTimeout, "ssh-timeout", 10*time.Second, "")
+
+	if err := flagSet.Parse([]string{"--tunnel", "flag.example.com"}); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	env := map[string]string{
+		"INAGO_FLEET_ENDPOINT": "http://127.0.0.1:49153",
+		"INAGO_TUNNEL":         "env.example.com",
+		"INAGO_NO_BLOCK":       "true",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	if err := bindEnvironment(flagSet, lookup); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if flags.FleetEndpoint != "http://127.0.0.1:49153" {
+		t.Fatal("expected", "http://127.0.0.1:49153", "got", flags.FleetEndpoint)
+	}
+	// Flags given on the command line take precedence.
+	if flags.Tunnel != "flag.example.com" {
+		t.Fatal("expected", "flag.example.com", "got", flags.Tunnel)
+	}
+	if !flags.NoBlock {
+		t.Fatal("expected", true, "got", false)
+	}
+	if flags.SSHTimeout != 10*time.Second {
+		t.Fatal("expected", 10*time.Second, "got", flags.SSHTimeout)
+	}
+
+	env["INAGO_SSH_TIMEOUT"] = "ten seconds"
+	if err := bindEnvironment(flagSet, lookup); !IsInvalidEnvironment(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
@@ -12,6 +12,18 @@ var (
 	maskAny = errgo.MaskFunc(errgo.Any)
 )
 
+func maskAnyf(err error, f string, v ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	f = fmt.Sprintf("%s: %s", err.Error(), f)
+	newErr := errgo.WithCausef(nil, errgo.Cause(err), f, v...)
+	newErr.(*errgo.Err).SetLocation(1)
+
+	return newErr
+}
+
 var invalidArgumentsError = errgo.Newf("invalid arguments")
 
 // IsInvalidArgumentsError checks whether the given command line
@@ -20,6 +32,94 @@ func IsInvalidArgumentsError(err error) bool {
 	return errgo.Cause(err) == invalidArgumentsError
 }
 
+var groupNotFoundError = errgo.Newf("group not found")
+
+// IsGroupNotFound checks whether the given error indicates that a group could
+// neither be found on the local filesystem nor in the cluster.
+func IsGroupNotFound(err error) bool {
+	return errgo.Cause(err) == groupNotFoundError
+}
+
+var groupAlreadyExistsError = errgo.Newf("group already exists")
+
+// IsGroupAlreadyExists checks whether the given error indicates that a group
+// directory already exists.
+func IsGroupAlreadyExists(err error) bool {
+	return errgo.Cause(err) == groupAlreadyExistsError
+}
+
+var inconsistentSlicesError = errgo.Newf("inconsistent slices")
+
+// IsInconsistentSlices checks whether the given error indicates that the
+// slices of a group do not consist of the same units.
+func IsInconsistentSlices(err error) bool {
+	return errgo.Cause(err) == inconsistentSlicesError
+}
+
+var invalidEnvironmentError = errgo.Newf("invalid environment")
+
+// IsInvalidEnvironment checks whether the given error indicates that an
+// environment variable overriding a flag has an invalid value.
+func IsInvalidEnvironment(err error) bool {
+	return errgo.Cause(err) == invalidEnvironmentError
+}
+
+var invalidArchiveError = errgo.Newf("invalid archive")
+
+// IsInvalidArchive checks whether the given error indicates that a group
+// archive could not be read.
+func IsInvalidArchive(err error) bool {
+	return errgo.Cause(err) == invalidArchiveError
+}
+
+var invalidManifestError = errgo.Newf("invalid group manifest")
+
+// IsInvalidManifest checks whether the given error indicates that the manifest
+// of a group could not be parsed.
+func IsInvalidManifest(err error) bool {
+	return errgo.Cause(err) == invalidManifestError
+}
+
+var invalidConfigError = errgo.Newf("invalid config")
+
+// IsInvalidConfig checks whether the given error indicates that the
+// configuration file of inagoctl could not be parsed.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var lintFailedError = errgo.Newf("lint failed")
+
+// IsLintFailed checks whether the given error indicates that a group has lint
+// warnings, while --strict is given.
+func IsLintFailed(err error) bool {
+	return errgo.Cause(err) == lintFailedError
+}
+
+var commandFailedError = errgo.Newf("command failed")
+
+// IsCommandFailed checks whether the given error indicates that a command
+// exited using a non-zero code.
+func IsCommandFailed(err error) bool {
+	return errgo.Cause(err) == commandFailedError
+}
+
+var invalidGroupError = errgo.Newf("invalid group")
+
+// IsInvalidGroup checks whether the given error indicates that a group on the
+// local filesystem failed the validation.
+func IsInvalidGroup(err error) bool {
+	return errgo.Cause(err) == invalidGroupError
+}
+
+var confirmationRequiredError = errgo.Newf("confirmation required")
+
+// IsConfirmationRequired checks whether the given error indicates that a
+// command requires a confirmation, which cannot be asked for.
+func IsConfirmationRequired(err error) bool {
+	return errgo.Cause(err) == confirmationRequiredError
+}
+
 // FormatValidationError returns the CausingErrors formatted:
 // Validation Error found:
 //		* unit slice not found
@@ -31,3 +131,20 @@ func FormatValidationError(err controller.ValidationError) string {
 	}
 	return msg
 }
+
+var signatureNotFoundError = errgo.Newf("signature not found")
+
+// IsSignatureNotFound checks whether the given error indicates that a group
+// was supposed to be verified using --verify-key, but is not signed.
+func IsSignatureNotFound(err error) bool {
+	return errgo.Cause(err) == signatureNotFoundError
+}
+
+var clusterSkippedError = errgo.Newf("cluster skipped")
+
+// IsClusterSkipped checks whether the given error indicates that a cluster
+// given using --clusters was not operated on, because operating on another
+// cluster failed before.
+func IsClusterSkipped(err error) bool {
+	return errgo.Cause(err) == clusterSkippedError
+}
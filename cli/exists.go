@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	existsCmd = &cobra.Command{
+		Use:   "exists <group[@slice]...>",
+		Short: "Check whether a group exists",
+		Long:  "Check whether the specified group, or all of the specified slices, are deployed. Exits with 0 in case they exist, 1 in case they do not exist and 2 in case the check failed, e.g. to be used in shell conditionals",
+		Run:   existsRun,
+	}
+)
+
+func existsRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting exists")
+
+	if len(args) == 0 {
+		cmd.Help()
+		exit(2)
+	}
+
+	var err error
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group, newRequestConfig.SliceIDs, err = parseGroupCLIArgs(args)
+	handleExistsCmdError(err)
+	req := controller.NewRequest(newRequestConfig)
+
+	// Knowing the unit files of a group allows to look up its units directly,
+	// instead of listing all units of the cluster.
+	if ok, _ := isLocalGroup(fs, req.Group); ok {
+		req, err = extendRequestWithContent(fs, req)
+		handleExistsCmdError(err)
+	}
+
+	ok, err := newController.Exists(newCtx, req)
+	handleExistsCmdError(err)
+	if !ok {
+		newLogger.Debug(newCtx, "cli: group '%s' does not exist", req.Group)
+		exit(1)
+	}
+}
+
+func handleExistsCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(2)
+	}
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"crypto"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/signature"
+)
+
+var (
+	exportFlags struct {
+		Out     string
+		SignKey string
+	}
+
+	exportCmd = &cobra.Command{
+		Use:   "export <group>",
+		Short: "Export a group",
+		Long:  "Package the unit files and scale of a deployed group into an archive. The archive can be brought up again using \"inagoctl up <archive>\"",
+		Run:   exportRun,
+	}
+)
+
+func init() {
+	exportCmd.PersistentFlags().StringVar(&exportFlags.Out, "out", "", "file the archive is written to (defaults to <group>.tar.gz)")
+	exportCmd.PersistentFlags().StringVar(&exportFlags.SignKey, "sign-key", "", "PEM encoded private key used to embed a signature into the archive")
+}
+
+func exportRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting export")
+
+	if len(args) != 1 {
+		cmd.Help()
+		exit(1)
+	}
+	group := resolveGroup(args[0])
+
+	out := exportFlags.Out
+	if out == "" {
+		out = group + ".tar.gz"
+	}
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	req := controller.NewRequest(newRequestConfig)
+
+	units, err := newController.DeployedUnits(newCtx, req)
+	if controller.IsUnitNotFound(err) {
+		handleExportCmdError(maskAnyf(groupNotFoundError, "%s", group))
+	}
+	handleExportCmdError(err)
+
+	req, err = newController.ExtendWithExistingSliceIDs(req)
+	handleExportCmdError(err)
+
+	metadata := groupArchiveMetadata{
+		Group:        group,
+		Scale:        len(req.SliceIDs),
+		SliceIDs:     req.SliceIDs,
+		Created:      time.Now().UTC(),
+		InagoVersion: projectVersion,
+	}
+	if metadata.Scale == 0 {
+		// Groups without slices are deployed exactly once.
+		metadata.Scale = 1
+	}
+
+	var key crypto.Signer
+	if exportFlags.SignKey != "" {
+		raw, err := fs.ReadFile(exportFlags.SignKey)
+		handleExportCmdError(err)
+		key, err = signature.ParsePrivateKey(raw)
+		handleExportCmdError(err)
+	}
+
+	archive := bytes.NewBuffer(nil)
+	err = writeGroupArchive(archive, units, metadata, key)
+	handleExportCmdError(err)
+	err = fs.WriteFile(out, archive.Bytes(), os.FileMode(0644))
+	handleExportCmdError(err)
+
+	newLogger.Info(newCtx, "Succeeded to export group '%s' to '%s'.", group, out)
+}
+
+func handleExportCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+// fanOutEnv is set for the inagoctl processes executed against single clusters
+// by runClusters. It holds the name of the cluster.
+var fanOutEnv = envPrefix + "FAN_OUT_CLUSTER"
+
+var (
+	fanOutFlags struct {
+		Clusters           []string
+		ClusterParallelism int
+		ContinueOnError    bool
+	}
+
+	// fanOutFlagNames are the flags controlling runClusters. They are removed
+	// from the command line of the processes executed against single clusters.
+	// The value tells whether the flag takes a value.
+	fanOutFlagNames = map[string]bool{
+		"cluster":             true,
+		"clusters":            true,
+		"cluster-parallelism": true,
+		"continue-on-error":   false,
+	}
+)
+
+func init() {
+	upCmd.PersistentFlags().StringSliceVar(&fanOutFlags.Clusters, "clusters", nil, "clusters of the config file to bring the group up on, one after another, e.g. prod-eu,prod-us")
+	upCmd.PersistentFlags().IntVar(&fanOutFlags.ClusterParallelism, "cluster-parallelism", 1, "maximum number of clusters given using --clusters operated on at the same time")
+	upCmd.PersistentFlags().BoolVar(&fanOutFlags.ContinueOnError, "continue-on-error", false, "operate on the remaining clusters given using --clusters in case operating on a cluster failed")
+}
+
+// runClusters executes the current command line once for each cluster given
+// using --clusters, as if it was given using --cluster. Clusters are operated
+// on one after another, unless --cluster-parallelism is given. The output of
+// each cluster is prefixed with its name. Unless --continue-on-error is given,
+// clusters not started yet are skipped once a cluster failed. A summary of the
+// results is printed at the end. In case any cluster failed, inagoctl exits
+// with a non-zero status.
+func runClusters(cmd *cobra.Command, args []string) {
+	clusters := fanOutFlags.Clusters
+	if cmd.Root().PersistentFlags().Changed("cluster") {
+		newLogger.Error(newCtx, "Failed to parse flags. (--cluster and --clusters cannot be given together)")
+		exit(1)
+	}
+	c, err := readConfig(fs, globalFlags.Config, false)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+	for _, cluster := range clusters {
+		if _, ok := c.Clusters[cluster]; !ok {
+			newLogger.Error(newCtx, "%#v", maskAnyf(invalidConfigError, "cluster '%s' not found", cluster))
+			exit(1)
+		}
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+
+	newLogger.Info(newCtx, "Going to %s on %d clusters: %v.", cmd.Name(), len(clusters), clusters)
+
+	var mutex sync.Mutex
+	var failed bool
+	results := controller.RunGroups(newCtx, clusters, fanOutFlags.ClusterParallelism, func(ctx context.Context, cluster string) error {
+		mutex.Lock()
+		skip := failed && !fanOutFlags.ContinueOnError
+		mutex.Unlock()
+		if skip {
+			return maskAny(clusterSkippedError)
+		}
+
+		err := runCluster(executable, os.Args[1:], cluster)
+		if err != nil {
+			mutex.Lock()
+			failed = true
+			mutex.Unlock()
+		}
+		return err
+	})
+	printResult(result{Kind: "clusters", Rows: createClusterSummary(results)})
+
+	var failedClusters, skippedClusters []string
+	for _, result := range results {
+		if IsClusterSkipped(result.Err) {
+			skippedClusters = append(skippedClusters, result.Group)
+		} else if result.Err != nil {
+			failedClusters = append(failedClusters, result.Group)
+		}
+	}
+	if len(failedClusters) > 0 {
+		newLogger.Error(newCtx, "Failed to %s on %d of %d clusters: %v.", cmd.Name(), len(failedClusters), len(clusters), failedClusters)
+		if len(skippedClusters) > 0 {
+			newLogger.Error(newCtx, "Skipped %d clusters: %v. Use --continue-on-error to operate on them anyway.", len(skippedClusters), skippedClusters)
+		}
+		exit(1)
+	}
+
+	newLogger.Info(newCtx, "Succeeded to %s on %d clusters: %v.", cmd.Name(), len(clusters), clusters)
+}
+
+// runCluster executes inagoctl using the given command line against the given
+// cluster, and prefixes its output with the name of the cluster.
+func runCluster(executable string, args []string, cluster string) error {
+	stdout := newPrefixWriter(os.Stdout, "["+cluster+"] ")
+	stderr := newPrefixWriter(os.Stderr, "["+cluster+"] ")
+	defer stdout.Flush()
+	defer stderr.Flush()
+
+	c := exec.Command(executable, clusterArgs(args, cluster)...)
+	c.Env = append(os.Environ(), fanOutEnv+"="+cluster)
+	c.Stdout = stdout
+	c.Stderr = stderr
+
+	if err := c.Run(); err != nil {
+		return maskAnyf(commandFailedError, "%s", err.Error())
+	}
+
+	return nil
+}
+
+// clusterArgs returns the given command line arguments executing the command
+// against the given cluster. The flags listed in fanOutFlagNames are removed.
+//
+//   up myapp --clusters a,b --continue-on-error  =>  --cluster a up myapp
+//
+func clusterArgs(args []string, cluster string) []string {
+	newArgs := []string{"--cluster", cluster}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			newArgs = append(newArgs, args[i:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "--") {
+			newArgs = append(newArgs, arg)
+			continue
+		}
+
+		name := strings.SplitN(arg[2:], "=", 2)[0]
+		takesValue, ok := fanOutFlagNames[name]
+		if !ok {
+			newArgs = append(newArgs, arg)
+			continue
+		}
+		if takesValue && !strings.Contains(arg, "=") {
+			// The value is the next argument.
+			i++
+		}
+	}
+
+	return newArgs
+}
+
+// createClusterSummary renders the given results of runClusters as table
+// rows.
+func createClusterSummary(results []controller.GroupResult) []string {
+	rows := []string{"Cluster | Result | Duration | Error", ""}
+	for _, result := range results {
+		status := "ok"
+		message := "-"
+		if IsClusterSkipped(result.Err) {
+			status = "skipped"
+		} else if result.Err != nil {
+			status = "failed"
+			message = result.Err.Error()
+		}
+		rows = append(rows, fmt.Sprintf("%s | %s | %s | %s", result.Group, status, (result.Duration/time.Second)*time.Second, message))
+	}
+
+	return rows
+}
+
+// prefixWriter prefixes each line written to it, so that the output of
+// multiple clusters can be told apart. All writers share a lock, so that
+// lines are not interleaved.
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+var prefixWriterMutex sync.Mutex
+
+func newPrefixWriter(w io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{w: w, prefix: prefix}
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf.Write(b)
+	for {
+		i := bytes.IndexByte(p.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := p.buf.Next(i + 1)
+		if err := p.writeLine(line); err != nil {
+			return 0, maskAny(err)
+		}
+	}
+
+	return len(b), nil
+}
+
+// Flush writes a last line not terminated by a newline.
+func (p *prefixWriter) Flush() error {
+	if p.buf.Len() == 0 {
+		return nil
+	}
+	line := append(p.buf.Bytes(), '\n')
+	p.buf.Reset()
+
+	return p.writeLine(line)
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	prefixWriterMutex.Lock()
+	defer prefixWriterMutex.Unlock()
+
+	_, err := io.WriteString(p.w, p.prefix+string(line))
+	return err
+}
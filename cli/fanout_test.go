@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+func Test_FanOut_clusterArgs(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []struct {
+		Args     []string
+		Expected []string
+	}{
+		{
+			Args:     []string{"up", "myapp", "--clusters", "a,b", "--continue-on-error"},
+			Expected: []string{"--cluster", "a", "up", "myapp"},
+		},
+		{
+			Args:     []string{"--cluster-parallelism=2", "up", "myapp", "3", "--clusters=a,b", "--no-block"},
+			Expected: []string{"--cluster", "a", "up", "myapp", "3", "--no-block"},
+		},
+		{
+			Args:     []string{"up", "myapp", "--clusters", "a,b", "--", "--clusters"},
+			Expected: []string{"--cluster", "a", "up", "myapp", "--", "--clusters"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		Expect(clusterArgs(testCase.Args, "a")).To(Equal(testCase.Expected))
+	}
+}
+
+func Test_FanOut_createClusterSummary(t *testing.T) {
+	RegisterTestingT(t)
+
+	rows := createClusterSummary([]controller.GroupResult{
+		{Group: "prod-eu", Duration: 62500 * time.Millisecond},
+		{Group: "prod-us", Err: maskAnyf(commandFailedError, "exit status 1"), Duration: time.Second},
+		{Group: "prod-asia", Err: maskAny(clusterSkippedError)},
+	})
+	Expect(rows).To(Equal([]string{
+		"Cluster | Result | Duration | Error",
+		"",
+		"prod-eu | ok | 1m2s | -",
+		"prod-us | failed | 1s | command failed: exit status 1",
+		"prod-asia | skipped | 0s | -",
+	}))
+}
+
+func Test_FanOut_prefixWriter(t *testing.T) {
+	RegisterTestingT(t)
+
+	var buf bytes.Buffer
+	w := newPrefixWriter(&buf, "[a] ")
+	w.Write([]byte("first\nsec"))
+	w.Write([]byte("ond\nthird"))
+	Expect(buf.String()).To(Equal("[a] first\n[a] second\n"))
+	Expect(w.Flush()).To(Succeed())
+	Expect(buf.String()).To(Equal("[a] first\n[a] second\n[a] third\n"))
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/policy"
+)
+
+var (
+	freezeCmd = &cobra.Command{
+		Use:   "freeze",
+		Short: "Manage deployment freezes",
+		Long:  "Freeze the cluster, so that commands changing groups refuse to run unless --override-freeze is given, lift the freeze, or show all freezes in effect. Freezes of groups within time windows are defined using --policy-file",
+		Run:   freezeRun,
+	}
+
+	freezeOnCmd = &cobra.Command{
+		Use:   "on [reason]",
+		Short: "Freeze the cluster",
+		Long:  "Freeze all groups of the cluster until the freeze is lifted. The reason is shown to everyone running into the freeze",
+		Run:   freezeOnRun,
+	}
+
+	freezeOffCmd = &cobra.Command{
+		Use:   "off",
+		Short: "Lift the freeze of the cluster",
+		Long:  "Lift the freeze of the cluster. Freezes defined using --policy-file are not affected",
+		Run:   freezeOffRun,
+	}
+
+	freezeStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show freezes",
+		Long:  "Show whether the cluster is frozen, and the freezes defined using --policy-file that did not end yet",
+		Run:   freezeStatusRun,
+	}
+)
+
+func init() {
+	freezeCmd.AddCommand(freezeOnCmd)
+	freezeCmd.AddCommand(freezeOffCmd)
+	freezeCmd.AddCommand(freezeStatusCmd)
+}
+
+func freezeRun(cmd *cobra.Command, args []string) {
+	cmd.Help()
+}
+
+func freezeOnRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting freeze on")
+
+	reason := strings.Join(args, " ")
+	err := newController.Freeze(newCtx, reason)
+	handleFreezeCmdError(err)
+
+	newLogger.Info(newCtx, "Cluster frozen.")
+}
+
+func freezeOffRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting freeze off")
+
+	if len(args) != 0 {
+		cmd.Help()
+		exit(1)
+	}
+
+	err := newController.Unfreeze(newCtx)
+	handleFreezeCmdError(err)
+
+	newLogger.Info(newCtx, "Cluster unfrozen.")
+}
+
+func freezeStatusRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting freeze status")
+
+	if len(args) != 0 {
+		cmd.Help()
+		exit(1)
+	}
+
+	freeze, err := newController.GetClusterFreeze(newCtx)
+	handleFreezeCmdError(err)
+
+	var p policy.Policy
+	if globalFlags.PolicyFile != "" {
+		raw, err := fs.ReadFile(globalFlags.PolicyFile)
+		handleFreezeCmdError(err)
+		p, err = policy.Parse(raw)
+		handleFreezeCmdError(err)
+	}
+
+	printResult(result{Kind: "freeze", Rows: createFreezeSummary(freeze, p.Freezes, time.Now())})
+}
+
+// createFreezeSummary creates the table rows describing the given freeze of
+// the cluster and the given freezes of the policy not ended at the given time.
+func createFreezeSummary(freeze controller.ClusterFreeze, freezes []policy.Freeze, now time.Time) []string {
+	rows := []string{"Groups | Start | End | Active | Reason", ""}
+
+	reasonCell := func(reason string) string {
+		if reason == "" {
+			return "-"
+		}
+		return reason
+	}
+	if freeze.Frozen {
+		rows = append(rows, fmt.Sprintf("* | %s | - | yes | %s", freeze.Since.Format(time.RFC3339), reasonCell(freeze.Reason)))
+	}
+	for _, f := range freezes {
+		if !now.Before(f.End) {
+			continue
+		}
+		group := f.Group
+		if group == "" {
+			group = "*"
+		}
+		active := !now.Before(f.Start)
+		rows = append(rows, fmt.Sprintf("%s | %s | %s | %s | %s", group, f.Start.Format(time.RFC3339), f.End.Format(time.RFC3339), yesOrNo(active), reasonCell(f.Reason)))
+	}
+
+	return rows
+}
+
+func handleFreezeCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/policy"
+)
+
+func Test_Freeze_createFreezeSummary(t *testing.T) {
+	RegisterTestingT(t)
+
+	now := time.Date(2016, 12, 24, 12, 0, 0, 0, time.UTC)
+	freeze := controller.ClusterFreeze{Frozen: true, Reason: "incident", Since: now.Add(-time.Hour)}
+	freezes := []policy.Freeze{
+		{Group: "prod-*", Start: now.Add(-24 * time.Hour), End: now.Add(24 * time.Hour), Reason: "holidays"},
+		{Start: now.Add(24 * time.Hour), End: now.Add(48 * time.Hour)},
+		{Group: "old", Start: now.Add(-48 * time.Hour), End: now.Add(-24 * time.Hour)},
+	}
+
+	Expect(createFreezeSummary(freeze, freezes, now)).To(Equal([]string{
+		"Groups | Start | End | Active | Reason",
+		"",
+		"* | 2016-12-24T11:00:00Z | - | yes | incident",
+		"prod-* | 2016-12-23T12:00:00Z | 2016-12-25T12:00:00Z | yes | holidays",
+		"* | 2016-12-25T12:00:00Z | 2016-12-26T12:00:00Z | no | -",
+	}))
+
+	Expect(createFreezeSummary(controller.ClusterFreeze{}, nil, now)).To(HaveLen(2))
+}
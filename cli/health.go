@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+var (
+	clusterHealthCmd = &cobra.Command{
+		Use:   "cluster-health",
+		Short: "Check the health of the cluster",
+		Long:  "Check whether the fleet API of the configured endpoint answers, machines are registered, fleet can read its unit registry stored in etcd, and units are scheduled by the leading fleet engine. Exits with a non-zero code in case any check fails, e.g. to be used before deploying in CI",
+		Run:   clusterHealthRun,
+	}
+)
+
+func clusterHealthRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting cluster-health")
+
+	if len(args) != 0 {
+		cmd.Help()
+		exit(1)
+	}
+
+	checks := fleet.CheckHealth(newCtx, newFleet)
+	printResult(result{Kind: "health checks", Rows: createHealthSummary(checks)})
+
+	for _, check := range checks {
+		if check.Err != nil {
+			newLogger.Error(newCtx, "Cluster is not healthy: %s failed.", check.Check)
+			diagnoseConnection(newCtx, check.Err)
+			exit(1)
+		}
+	}
+	newLogger.Info(newCtx, "Cluster is healthy.")
+}
+
+// createHealthSummary renders the given health checks as table rows. Latencies
+// are rounded to milliseconds.
+func createHealthSummary(checks []fleet.HealthCheck) []string {
+	rows := []string{"Check | Result | Latency | Detail", ""}
+	for _, check := range checks {
+		status := "pass"
+		detail := check.Detail
+		if check.Err != nil {
+			status = "fail"
+			detail = check.Err.Error()
+		}
+		if detail == "" {
+			detail = "-"
+		}
+		rows = append(rows, fmt.Sprintf("%s | %s | %s | %s", check.Check, status, (check.Latency/time.Millisecond)*time.Millisecond, detail))
+	}
+
+	return rows
+}
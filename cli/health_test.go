@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/juju/errgo"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_Health_createHealthSummary(t *testing.T) {
+	checks := []fleet.HealthCheck{
+		{Check: "fleet API answers", Detail: "v1", Latency: 3*time.Millisecond + 420*time.Microsecond},
+		{Check: "machines registered", Detail: "3 machines", Latency: 12 * time.Millisecond},
+		{Check: "units scheduled", Latency: 0},
+		{Check: "unit registry readable", Err: errgo.New("etcd cluster is unavailable"), Latency: 5 * time.Second},
+	}
+
+	rows := createHealthSummary(checks)
+	expected := []string{
+		"Check | Result | Latency | Detail",
+		"",
+		"fleet API answers | pass | 3ms | v1",
+		"machines registered | pass | 12ms | 3 machines",
+		"units scheduled | pass | 0s | -",
+		"unit registry readable | fail | 5s | etcd cluster is unavailable",
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatal("expected", expected, "got", rows)
+	}
+}
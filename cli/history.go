@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/state"
+)
+
+const (
+	// historyNamespace is the state store namespace group histories are stored
+	// in.
+	historyNamespace = "history"
+
+	// maxHistoryTransitions is the number of transitions kept per group. Older
+	// transitions are dropped.
+	maxHistoryTransitions = 1000
+
+	// flappingThreshold is the number of times a slice needs to become active
+	// within flappingWindow to be considered flapping.
+	flappingThreshold = 3
+	flappingWindow    = 1 * time.Hour
+
+	historyTimeFormat = "2006-01-02 15:04:05"
+)
+
+// sliceTransition describes a slice changing its phase or machine.
+type sliceTransition struct {
+	Time    time.Time `json:"time"`
+	SliceID string    `json:"slice-id"`
+	Phase   phase     `json:"phase"`
+
+	// Machine is the IP of the machine the slice is scheduled on. It is empty
+	// in case the slice is not scheduled.
+	Machine string `json:"machine,omitempty"`
+}
+
+// groupHistory is the history of a group as stored in the state store.
+type groupHistory struct {
+	Transitions []sliceTransition `json:"transitions"`
+}
+
+// sliceHistory summarizes the transitions of a single slice.
+type sliceHistory struct {
+	SliceID        string
+	Phase          phase
+	Since          time.Time
+	Restarts       int
+	LastRestart    time.Time
+	MachineChanges int
+	Flapping       bool
+}
+
+// loadHistory reads the history of the given group from the given store. A
+// group without history results in an empty history.
+func loadHistory(store state.Store, group string) (groupHistory, error) {
+	var history groupHistory
+	err := store.Get(historyNamespace, group, &history)
+	if state.IsNotFound(err) {
+		return groupHistory{}, nil
+	} else if err != nil {
+		return groupHistory{}, maskAny(err)
+	}
+
+	return history, nil
+}
+
+// recordHistory compares the given unit states with the latest transitions
+// stored for the given group and records a transition for each slice whose
+// phase or machine changed. Slices that disappeared are recorded as destroyed.
+func recordHistory(store state.Store, group string, usl []fleet.UnitStatus, now time.Time) error {
+	history, err := loadHistory(store, group)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	latest := map[string]sliceTransition{}
+	for _, t := range history.Transitions {
+		latest[t.SliceID] = t
+	}
+
+	machines := sliceMachines(usl)
+	current := map[string]struct{}{}
+	var transitions []sliceTransition
+	for _, sp := range slicePhases(usl) {
+		current[sp.SliceID] = struct{}{}
+
+		machine := machines[sp.SliceID]
+		last, ok := latest[sp.SliceID]
+		if ok && last.Phase == sp.Phase && (machine == "" || machine == last.Machine) {
+			continue
+		}
+		if machine == "" && last.Phase != phaseDestroyed {
+			// Units being rescheduled are not bound to a machine for a moment. We
+			// want to see machine changes, not the gap in between.
+			machine = last.Machine
+		}
+		transitions = append(transitions, sliceTransition{Time: now, SliceID: sp.SliceID, Phase: sp.Phase, Machine: machine})
+	}
+	for sliceID, last := range latest {
+		if _, ok := current[sliceID]; ok || last.Phase == phaseDestroyed {
+			continue
+		}
+		transitions = append(transitions, sliceTransition{Time: now, SliceID: sliceID, Phase: phaseDestroyed})
+	}
+
+	if len(transitions) == 0 {
+		return nil
+	}
+	sort.Sort(sliceTransitionsBySliceID(transitions))
+	history.Transitions = append(history.Transitions, transitions...)
+	if len(history.Transitions) > maxHistoryTransitions {
+		history.Transitions = history.Transitions[len(history.Transitions)-maxHistoryTransitions:]
+	}
+
+	if err := store.Set(historyNamespace, group, history); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// sliceMachines returns the IP of the machine each slice of the given unit
+// states is scheduled on. Units of a slice are expected to be scheduled on
+// the same machine, so the first one found wins.
+func sliceMachines(usl []fleet.UnitStatus) map[string]string {
+	sorted := make([]fleet.UnitStatus, len(usl))
+	copy(sorted, usl)
+	sort.Sort(unitStatusesByName(sorted))
+
+	machines := map[string]string{}
+	for _, us := range sorted {
+		if _, ok := machines[us.SliceID]; ok || len(us.Machine) == 0 {
+			continue
+		}
+		if us.Machine[0].IP != nil {
+			machines[us.SliceID] = us.Machine[0].IP.String()
+		} else {
+			machines[us.SliceID] = us.Machine[0].ID
+		}
+	}
+
+	return machines
+}
+
+// summarizeHistory summarizes the transitions of each slice of the given
+// history. The result is sorted by slice ID.
+func summarizeHistory(history groupHistory, now time.Time) []sliceHistory {
+	summaries := map[string]*sliceHistory{}
+	lastMachines := map[string]string{}
+	activations := map[string][]time.Time{}
+	for _, t := range history.Transitions {
+		s, ok := summaries[t.SliceID]
+		if !ok {
+			s = &sliceHistory{SliceID: t.SliceID}
+			summaries[t.SliceID] = s
+		}
+
+		if s.Phase != t.Phase {
+			s.Since = t.Time
+		}
+		s.Phase = t.Phase
+
+		if t.Phase == phaseActive {
+			if len(activations[t.SliceID]) > 0 {
+				s.Restarts++
+				s.LastRestart = t.Time
+			}
+			activations[t.SliceID] = append(activations[t.SliceID], t.Time)
+		}
+
+		if t.Machine != "" {
+			if last := lastMachines[t.SliceID]; last != "" && last != t.Machine {
+				s.MachineChanges++
+			}
+			lastMachines[t.SliceID] = t.Machine
+		}
+	}
+
+	var result []sliceHistory
+	for sliceID, s := range summaries {
+		var recent int
+		for _, t := range activations[sliceID] {
+			if now.Sub(t) <= flappingWindow {
+				recent++
+			}
+		}
+		s.Flapping = recent >= flappingThreshold
+		result = append(result, *s)
+	}
+	sort.Sort(sliceHistoriesByID(result))
+
+	return result
+}
+
+// createHistory renders the given history of the given group as table rows.
+// In verbose mode all recorded transitions are listed as well.
+func createHistory(group string, history groupHistory, now time.Time, verbose bool) []string {
+	sliceName := func(sliceID string) string {
+		if sliceID == "" {
+			return group
+		}
+		return group + "@" + sliceID
+	}
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Local().Format(historyTimeFormat)
+	}
+
+	rows := []string{"Slice | Phase | Since | Restarts | Last Restart | Machine Changes | Flapping", ""}
+	for _, s := range summarizeHistory(history, now) {
+		flapping := "no"
+		if s.Flapping {
+			flapping = "yes"
+		}
+		rows = append(rows, fmt.Sprintf("%s | %s | %s | %d | %s | %d | %s", sliceName(s.SliceID), s.Phase, formatTime(s.Since), s.Restarts, formatTime(s.LastRestart), s.MachineChanges, flapping))
+	}
+
+	if verbose {
+		rows = append(rows, "", "Time | Slice | Phase | Machine", "")
+		for _, t := range history.Transitions {
+			machine := t.Machine
+			if machine == "" {
+				machine = "-"
+			}
+			rows = append(rows, fmt.Sprintf("%s | %s | %s | %s", formatTime(t.Time), sliceName(t.SliceID), t.Phase, machine))
+		}
+	}
+
+	return rows
+}
+
+var stateDirReplacer = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// stateDir returns the directory state of the cluster reachable using the
+// given endpoint and tunnel is stored in. Each cluster gets its own directory
+// below the given base directory.
+//
+//   unix:///var/run/fleet.sock                =>  <base>/var_run_fleet.sock
+//   http://127.0.0.1:49153, fleet.example.com  =>  <base>/fleet.example.com_127.0.0.1_49153
+//
+func stateDir(base string, endpoint url.URL, tunnel string) string {
+	if strings.HasPrefix(base, "~/") {
+		base = filepath.Join(os.Getenv("HOME"), base[2:])
+	}
+
+	name := endpoint.Host + endpoint.Path
+	if tunnel != "" {
+		name = tunnel + "/" + name
+	}
+	name = strings.Trim(stateDirReplacer.ReplaceAllString(name, "_"), "_")
+	if name == "" {
+		name = "default"
+	}
+
+	return filepath.Join(base, name)
+}
+
+type sliceTransitionsBySliceID []sliceTransition
+
+func (s sliceTransitionsBySliceID) Len() int           { return len(s) }
+func (s sliceTransitionsBySliceID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s sliceTransitionsBySliceID) Less(i, j int) bool { return s[i].SliceID < s[j].SliceID }
+
+type sliceHistoriesByID []sliceHistory
+
+func (s sliceHistoriesByID) Len() int           { return len(s) }
+func (s sliceHistoriesByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s sliceHistoriesByID) Less(i, j int) bool { return s[i].SliceID < s[j].SliceID }
+
+type unitStatusesByName []fleet.UnitStatus
+
+func (u unitStatusesByName) Len() int           { return len(u) }
+func (u unitStatusesByName) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u unitStatusesByName) Less(i, j int) bool { return u[i].Name < u[j].Name }
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/logging"
+	"github.com/giantswarm/inago/state"
+)
+
+func runningUnitStatusOn(name, sliceID, machineIP string) fleet.UnitStatus {
+	us := runningUnitStatus(name, sliceID)
+	us.Machine[0].IP = net.ParseIP(machineIP)
+	return us
+}
+
+func Test_History_recordHistory(t *testing.T) {
+	RegisterTestingT(t)
+	newLogger = logging.NewLogger(logging.DefaultConfig())
+
+	store := state.NewMemoryStore()
+	start := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	steps := [][]fleet.UnitStatus{
+		{
+			unloadedUnitStatus("example-foo@1.service", "1", "launched"),
+			unloadedUnitStatus("example-foo@2.service", "2", "launched"),
+		},
+		{
+			runningUnitStatusOn("example-foo@1.service", "1", "172.17.8.101"),
+			runningUnitStatusOn("example-foo@2.service", "2", "172.17.8.102"),
+		},
+		// Nothing changed.
+		{
+			runningUnitStatusOn("example-foo@1.service", "1", "172.17.8.101"),
+			runningUnitStatusOn("example-foo@2.service", "2", "172.17.8.102"),
+		},
+		// Slice 1 is rescheduled to another machine, slice 2 is gone.
+		{
+			unloadedUnitStatus("example-foo@1.service", "1", "launched"),
+		},
+		{
+			runningUnitStatusOn("example-foo@1.service", "1", "172.17.8.103"),
+		},
+	}
+	for i, usl := range steps {
+		err := recordHistory(store, "example", usl, start.Add(time.Duration(i)*time.Minute))
+		Expect(err).To(BeNil())
+	}
+
+	history, err := loadHistory(store, "example")
+	Expect(err).To(BeNil())
+	Expect(history.Transitions).To(Equal([]sliceTransition{
+		{Time: start, SliceID: "1", Phase: phaseSubmitting},
+		{Time: start, SliceID: "2", Phase: phaseSubmitting},
+		{Time: start.Add(1 * time.Minute), SliceID: "1", Phase: phaseActive, Machine: "172.17.8.101"},
+		{Time: start.Add(1 * time.Minute), SliceID: "2", Phase: phaseActive, Machine: "172.17.8.102"},
+		{Time: start.Add(3 * time.Minute), SliceID: "1", Phase: phaseSubmitting, Machine: "172.17.8.101"},
+		{Time: start.Add(3 * time.Minute), SliceID: "2", Phase: phaseDestroyed},
+		{Time: start.Add(4 * time.Minute), SliceID: "1", Phase: phaseActive, Machine: "172.17.8.103"},
+	}))
+
+	summaries := summarizeHistory(history, start.Add(5*time.Minute))
+	Expect(summaries).To(Equal([]sliceHistory{
+		{
+			SliceID:        "1",
+			Phase:          phaseActive,
+			Since:          start.Add(4 * time.Minute),
+			Restarts:       1,
+			LastRestart:    start.Add(4 * time.Minute),
+			MachineChanges: 1,
+		},
+		{
+			SliceID: "2",
+			Phase:   phaseDestroyed,
+			Since:   start.Add(3 * time.Minute),
+		},
+	}))
+}
+
+func Test_History_summarizeHistory_Flapping(t *testing.T) {
+	RegisterTestingT(t)
+
+	start := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	var history groupHistory
+	for i := 0; i < flappingThreshold; i++ {
+		history.Transitions = append(history.Transitions,
+			sliceTransition{Time: start.Add(time.Duration(2*i) * time.Minute), SliceID: "1", Phase: phaseActive},
+			sliceTransition{Time: start.Add(time.Duration(2*i+1) * time.Minute), SliceID: "1", Phase: phaseFailed},
+		)
+	}
+
+	summaries := summarizeHistory(history, start.Add(10*time.Minute))
+	Expect(summaries).To(HaveLen(1))
+	Expect(summaries[0].Flapping).To(BeTrue())
+	Expect(summaries[0].Restarts).To(Equal(flappingThreshold - 1))
+
+	summaries = summarizeHistory(history, start.Add(2*flappingWindow))
+	Expect(summaries[0].Flapping).To(BeFalse())
+}
+
+func Test_History_recordHistory_Limit(t *testing.T) {
+	RegisterTestingT(t)
+	newLogger = logging.NewLogger(logging.DefaultConfig())
+
+	store := state.NewMemoryStore()
+	start := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < maxHistoryTransitions+10; i++ {
+		usl := []fleet.UnitStatus{runningUnitStatusOn("example-foo@1.service", "1", "172.17.8.101")}
+		if i%2 == 1 {
+			usl = nil
+		}
+		err := recordHistory(store, "example", usl, start.Add(time.Duration(i)*time.Minute))
+		Expect(err).To(BeNil())
+	}
+
+	history, err := loadHistory(store, "example")
+	Expect(err).To(BeNil())
+	Expect(history.Transitions).To(HaveLen(maxHistoryTransitions))
+	Expect(history.Transitions[len(history.Transitions)-1].Phase).To(Equal(phaseDestroyed))
+}
+
+func Test_History_stateDir(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []struct {
+		Endpoint string
+		Tunnel   string
+		Expected string
+	}{
+		{
+			Endpoint: "unix:///var/run/fleet.sock",
+			Expected: "/state/var_run_fleet.sock",
+		},
+		{
+			Endpoint: "http://127.0.0.1:49153",
+			Tunnel:   "fleet.example.com",
+			Expected: "/state/fleet.example.com_127.0.0.1_49153",
+		},
+		{
+			Endpoint: "",
+			Expected: "/state/default",
+		},
+	}
+
+	for _, testCase := range testCases {
+		endpoint, err := url.Parse(testCase.Endpoint)
+		Expect(err).To(BeNil())
+		Expect(stateDir("/state", *endpoint, testCase.Tunnel)).To(Equal(testCase.Expected))
+	}
+}
@@ -4,8 +4,10 @@ package cli
 
 import (
 	"net/url"
+	"os"
 	"time"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 
@@ -14,14 +16,56 @@ import (
 	"github.com/giantswarm/inago/file-system/spec"
 	"github.com/giantswarm/inago/fleet"
 	"github.com/giantswarm/inago/logging"
+	"github.com/giantswarm/inago/policy"
+	"github.com/giantswarm/inago/registry"
+	"github.com/giantswarm/inago/state"
 	"github.com/giantswarm/inago/task"
+	"github.com/giantswarm/inago/tracing"
+)
+
+const (
+	// backendFleet executes operations against a fleet cluster.
+	backendFleet = "fleet"
+
+	// backendSimulator executes operations against a cluster simulated
+	// in-process. See fleet.Simulator.
+	backendSimulator = "simulator"
 )
 
 var (
 	globalFlags struct {
-		FleetEndpoint string
-		NoBlock       bool
-		Verbose       bool
+		Backend        string
+		CAFile         string
+		CertFile       string
+		Cluster        string
+		Color          string
+		Format         string
+		Config         string
+		Env            string
+		FleetEndpoint  string
+		IdempotencyKey string
+		KeyFile        string
+		RateLimit      float64
+		NoBlock        bool
+		NoTTY          bool
+		PrePullImages  bool
+		Profile        string
+		Proxy          string
+		Report         string
+		SliceIDs       string
+		StateDir       string
+		Verbose        bool
+
+		PolicyFile     string
+		ForcePolicy    bool
+		OverrideFreeze bool
+		VerifyImages   bool
+		VerifyKey      string
+
+		SimulatorLatency     time.Duration
+		SimulatorFailureRate float64
+		SimulatorChaos       float64
+		SimulatorSeed        int64
 
 		Tunnel                   string
 		SSHUsername              string
@@ -32,9 +76,11 @@ var (
 
 	fs             filesystemspec.FileSystem
 	newLogger      logging.Logger
+	newFleetConfig fleet.Config
 	newFleet       fleet.Fleet
 	newTaskService task.Service
 	newController  controller.Controller
+	newStateStore  state.Store
 
 	newCtx context.Context
 
@@ -49,38 +95,172 @@ var (
 			// command runs.
 			fs = filesystemreal.NewFileSystem()
 
+			// Global flags not given on the command line can be set using
+			// environment variables, e.g. INAGO_FLEET_ENDPOINT.
+			envErr := bindEnvironment(cmd.Root().PersistentFlags(), os.LookupEnv)
+
+			// The cluster and profile of the config file set defaults of global
+			// flags not given otherwise. So they are applied before flags are
+			// used. The config commands create the file in case it does not exist
+			// yet. Group aliases of the config file are resolved by the commands
+			// where they build requests. See resolveGroup.
+			required := cmd.Root().PersistentFlags().Changed("config") && cmd.Parent() != configCmd
+			c, configErr := readConfig(fs, globalFlags.Config, required)
+			if envErr == nil && configErr == nil {
+				configErr = applyCluster(cmd.Root().PersistentFlags(), c, globalFlags.Profile)
+			}
+			if envErr == nil && configErr == nil {
+				configErr = applyProfile(cmd.Root().PersistentFlags(), c, globalFlags.Profile)
+			}
+			if envErr == nil && configErr == nil {
+				aliases, configErr = newAliases(c)
+			}
+
 			loggingConfig := logging.DefaultConfig()
 			if globalFlags.Verbose {
 				loggingConfig.LogLevel = "DEBUG"
 			}
 			newLogger = logging.NewLogger(loggingConfig)
 
-			URL, err := url.Parse(globalFlags.FleetEndpoint)
+			if envErr != nil {
+				newLogger.Error(context.Background(), "Failed to read flags from environment. (%s)", envErr.Error())
+				exit(1)
+			}
+			if configErr != nil {
+				newLogger.Error(context.Background(), "Failed to read config file '%s'. (%s)", globalFlags.Config, configErr.Error())
+				exit(1)
+			}
+
+			var err error
+			colorEnabled, err = useColor(globalFlags.Color, isatty.IsTerminal(os.Stdout.Fd()))
 			if err != nil {
-				panic(err)
-			}
-
-			newFleetConfig := fleet.DefaultConfig()
-			newFleetConfig.Endpoint = *URL
-			newFleetConfig.Logger = newLogger
-			if globalFlags.Tunnel != "" {
-				newSSHTunnelConfig := fleet.DefaultSSHTunnelConfig()
-				newSSHTunnelConfig.Endpoint = *URL
-				newSSHTunnelConfig.KnownHostsFile = globalFlags.SSHKnownHostsFile
-				newSSHTunnelConfig.Logger = newLogger
-				newSSHTunnelConfig.StrictHostKeyChecking = globalFlags.SSHStrictHostKeyChecking
-				newSSHTunnelConfig.Timeout = globalFlags.SSHTimeout
-				newSSHTunnelConfig.Tunnel = globalFlags.Tunnel
-				newSSHTunnelConfig.Username = globalFlags.SSHUsername
-				newSSHTunnel, err := fleet.NewSSHTunnel(newSSHTunnelConfig)
+				newLogger.Error(context.Background(), "Failed to parse flags. (%s)", err.Error())
+				exit(1)
+			}
+
+			outPrinter, err = newPrinter(globalFlags.Format)
+			if err != nil {
+				newLogger.Error(context.Background(), "Failed to parse flags. (%s)", err.Error())
+				exit(1)
+			}
+
+			notifiers, err = newNotifiers(c)
+			if err != nil {
+				newLogger.Error(context.Background(), "Failed to configure notifications. (%s)", err.Error())
+				exit(1)
+			}
+			auditSinks, err = newAuditSinks(c)
+			if err != nil {
+				newLogger.Error(context.Background(), "Failed to configure audit sinks. (%s)", err.Error())
+				exit(1)
+			}
+			if _, ok := os.LookupEnv(fanOutEnv); ok {
+				// Operations executed against multiple clusters using --clusters
+				// are notified once, not once per cluster.
+				notifiers = nil
+			}
+
+			newTracingConfig, err := tracing.ConfigFromEnv("inagoctl", os.LookupEnv)
+			if err != nil {
+				newLogger.Error(context.Background(), "Failed to configure tracing. (%s)", err.Error())
+				exit(1)
+			}
+			newTracer = tracing.NewTracer(newTracingConfig)
+
+			if isOfflineCommand(cmd) {
+				// Offline commands only read groups from the local filesystem, so
+				// that CI can check changes of groups without cluster credentials.
+				// They operate on an empty simulated cluster, without connecting to
+				// fleet or touching the state directory.
+				newStateStore = state.NewMemoryStore()
+				newFleetConfig = fleet.DefaultConfig()
+				newFleetConfig.Logger = newLogger
+				newFleetConfig.RequestID = fleet.NewRequestID()
+				newFleet, err = newOfflineFleet(newLogger)
+				if err != nil {
+					newLogger.Error(context.Background(), "Failed to create simulator. (%s)", err.Error())
+					exit(1)
+				}
+			} else {
+				// Fleet's socket is not at the same place on all distributions. Unless
+				// told otherwise, we use the one we find.
+				if !cmd.Root().PersistentFlags().Changed("fleet-endpoint") && globalFlags.Tunnel == "" {
+					if socketPath := fleet.DetectSocket(); socketPath != "" {
+						globalFlags.FleetEndpoint = "unix://" + socketPath
+					}
+				}
+
+				URL, err := url.Parse(globalFlags.FleetEndpoint)
 				if err != nil {
 					panic(err)
 				}
-				newFleetConfig.SSHTunnel = newSSHTunnel
-			}
-			newFleet, err = fleet.NewFleet(newFleetConfig)
-			if err != nil {
-				panic(err)
+
+				// The simulated cluster is kept apart from the state of real ones.
+				newFileStoreConfig := state.DefaultFileStoreConfig()
+				newFileStoreConfig.FileSystem = fs
+				newFileStoreConfig.Dir = stateDir(globalFlags.StateDir, *URL, globalFlags.Tunnel)
+				if globalFlags.Backend == backendSimulator {
+					newFileStoreConfig.Dir = stateDir(globalFlags.StateDir, url.URL{Host: backendSimulator}, "")
+				}
+				newStateStore = state.NewFileStore(newFileStoreConfig)
+
+				newFleetConfig = fleet.DefaultConfig()
+				newFleetConfig.Endpoint = *URL
+				newFleetConfig.Logger = newLogger
+				newFleetConfig.RateLimit = globalFlags.RateLimit
+				newFleetConfig.UserAgent = userAgent()
+				newFleetConfig.RequestID = fleet.NewRequestID()
+				newFleetConfig.TLSConfig, err = newTLSConfig(fs, globalFlags.CAFile, globalFlags.CertFile, globalFlags.KeyFile)
+				if err != nil {
+					newLogger.Error(context.Background(), "Failed to configure TLS. (%s)", err.Error())
+					exit(1)
+				}
+				newFleetConfig.Proxy, err = parseProxy(globalFlags.Proxy)
+				if err != nil {
+					newLogger.Error(context.Background(), "Failed to parse flags. (%s)", err.Error())
+					exit(1)
+				}
+				if globalFlags.Tunnel != "" {
+					newSSHTunnelConfig := fleet.DefaultSSHTunnelConfig()
+					newSSHTunnelConfig.Endpoint = *URL
+					newSSHTunnelConfig.KnownHostsFile = globalFlags.SSHKnownHostsFile
+					newSSHTunnelConfig.Logger = newLogger
+					newSSHTunnelConfig.StrictHostKeyChecking = globalFlags.SSHStrictHostKeyChecking
+					newSSHTunnelConfig.Timeout = globalFlags.SSHTimeout
+					newSSHTunnelConfig.Tunnel = globalFlags.Tunnel
+					newSSHTunnelConfig.Username = globalFlags.SSHUsername
+					newSSHTunnel, err := fleet.NewSSHTunnel(newSSHTunnelConfig)
+					if err != nil {
+						panic(err)
+					}
+					newFleetConfig.SSHTunnel = newSSHTunnel
+				}
+				switch globalFlags.Backend {
+				case backendFleet:
+					newFleet, err = fleet.NewFleet(newFleetConfig)
+					if err != nil {
+						newLogger.Error(context.Background(), "Failed to create fleet client. (%s)", err.Error())
+						exit(1)
+					}
+				case backendSimulator:
+					newSimulatorConfig := fleet.DefaultSimulatorConfig()
+					newSimulatorConfig.Logger = newLogger
+					newSimulatorConfig.Latency = globalFlags.SimulatorLatency
+					newSimulatorConfig.FailureRate = globalFlags.SimulatorFailureRate
+					newSimulatorConfig.Chaos = globalFlags.SimulatorChaos
+					if globalFlags.SimulatorSeed != 0 {
+						newSimulatorConfig.Seed = globalFlags.SimulatorSeed
+					}
+					newSimulatorConfig.Store = newStateStore
+					newFleet, err = fleet.NewSimulator(newSimulatorConfig)
+					if err != nil {
+						newLogger.Error(context.Background(), "Failed to create simulator. (%s)", err.Error())
+						exit(1)
+					}
+				default:
+					newLogger.Error(context.Background(), "Failed to parse flags. (--backend must be one of %s or %s, got '%s')", backendFleet, backendSimulator, globalFlags.Backend)
+					exit(1)
+				}
 			}
 
 			newTaskServiceConfig := task.DefaultConfig()
@@ -91,19 +271,82 @@ var (
 			newControllerConfig.Logger = newLogger
 			newControllerConfig.Fleet = newFleet
 			newControllerConfig.TaskService = newTaskService
+			newControllerConfig.StateStore = newStateStore
+			newControllerConfig.ForcePolicy = globalFlags.ForcePolicy
+			newControllerConfig.OverrideFreeze = globalFlags.OverrideFreeze
+			newControllerConfig.PrePullImages = globalFlags.PrePullImages
+			if globalFlags.VerifyImages {
+				newControllerConfig.Registry = registry.NewRegistry(registry.DefaultConfig())
+			}
+			newControllerConfig.Tracer = newTracer
+			newControllerConfig.SliceIDRule, err = controller.ParseSliceIDRule(globalFlags.SliceIDs)
+			if err != nil {
+				newLogger.Error(context.Background(), "Failed to parse slice ID pattern '%s'. (%s)", globalFlags.SliceIDs, err.Error())
+				exit(1)
+			}
+			if globalFlags.PolicyFile != "" {
+				raw, err := fs.ReadFile(globalFlags.PolicyFile)
+				if err != nil {
+					newLogger.Error(context.Background(), "Failed to read policy file '%s'. (%s)", globalFlags.PolicyFile, err.Error())
+					exit(1)
+				}
+				newControllerConfig.Policy, err = policy.Parse(raw)
+				if err != nil {
+					newLogger.Error(context.Background(), "Failed to parse policy file '%s'. (%s)", globalFlags.PolicyFile, err.Error())
+					exit(1)
+				}
+			}
 
 			newController = controller.NewController(newControllerConfig)
 
-			newCtx = context.Background()
+			// Retried invocations using the same key do not apply operations
+			// twice. See controller.WithIdempotencyKey.
+			newCtx = controller.WithIdempotencyKey(context.Background(), globalFlags.IdempotencyKey)
+			// All calls of fleet made by the command carry the same request ID,
+			// which is logged along with each message as well.
+			newCtx = fleet.WithRequestID(newCtx, newFleetConfig.RequestID)
+			newCtx = startTracing(newCtx, cmd, args)
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			finishTracing(0)
 		},
 	}
 )
 
 func init() {
+	MainCmd.PersistentFlags().StringVar(&globalFlags.Backend, "backend", backendFleet, "backend operations are executed against, one of fleet or simulator")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.CAFile, "ca-file", "", "CA certificate file used to verify the fleet endpoint, for https endpoints")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.CertFile, "cert-file", "", "client certificate file used to authenticate against the fleet endpoint, for https endpoints")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.Cluster, "cluster", "", "cluster of the config file to connect to, setting fleet-endpoint, tunnel and TLS files")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.Color, "color", colorModeAuto, "color status output, one of auto, always or never")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.Format, "format", formatTable, "print results and progress as table, json, yaml or jsonl, or endpoints as hosts")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.Config, "config", "~/.inago/config.yaml", "configuration file, e.g. defining notifications")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.Env, "env", "", "environment whose overlay is merged over the definitions of groups, i.e. <group>/overlays/<env>")
 	MainCmd.PersistentFlags().StringVar(&globalFlags.FleetEndpoint, "fleet-endpoint", "unix:///var/run/fleet.sock", "endpoint used to connect to fleet")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.IdempotencyKey, "idempotency-key", "", "operations already applied using this key are skipped, e.g. when retrying in CI")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.KeyFile, "key-file", "", "client key file used to authenticate against the fleet endpoint, for https endpoints")
+	MainCmd.PersistentFlags().Float64Var(&globalFlags.RateLimit, "rate-limit", fleet.DefaultConfig().RateLimit, "maximum number of requests per second sent to fleet (0 disables the limit)")
 	MainCmd.PersistentFlags().BoolVar(&globalFlags.NoBlock, "no-block", false, "block on synchronous actions")
+	MainCmd.PersistentFlags().BoolVar(&globalFlags.NoTTY, "no-tty", false, "print progress line by line instead of updating it in place")
+	MainCmd.PersistentFlags().BoolVar(&globalFlags.PrePullImages, "pre-pull-images", false, "pull Docker images of groups on their machines before starting them")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.Profile, "profile", "", "profile of the config file setting defaults of global flags (the current profile by default)")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.Proxy, "proxy", "", "HTTP or SOCKS5 proxy requests to http and https fleet endpoints are sent through, e.g. http://proxy:3128 (HTTP_PROXY and HTTPS_PROXY by default)")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.Report, "report", "", "file a JSON report of mutating commands is written to, including timings, states and errors of slices")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.SliceIDs, "slice-ids", "", "pattern IDs of new slices are taken from, e.g. 'web-{01..20}' (random IDs by default)")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.StateDir, "state-dir", "~/.inago/state", "directory used to store state like the history of groups")
 	MainCmd.PersistentFlags().BoolVarP(&globalFlags.Verbose, "verbose", "v", false, "verbose output")
 
+	MainCmd.PersistentFlags().StringVar(&globalFlags.PolicyFile, "policy-file", "", "file defining rules that restrict operations against groups")
+	MainCmd.PersistentFlags().BoolVar(&globalFlags.ForcePolicy, "force-policy", false, "execute operations even though they violate the policy")
+	MainCmd.PersistentFlags().BoolVar(&globalFlags.OverrideFreeze, "override-freeze", false, "execute operations even though groups are frozen")
+	MainCmd.PersistentFlags().BoolVar(&globalFlags.VerifyImages, "verify-images", false, "verify that the Docker images of groups exist in their registries before submitting them")
+	MainCmd.PersistentFlags().StringVar(&globalFlags.VerifyKey, "verify-key", "", "public key the signatures of groups are verified with before submitting them")
+
+	MainCmd.PersistentFlags().DurationVar(&globalFlags.SimulatorLatency, "simulator-latency", fleet.DefaultSimulatorConfig().Latency, "average time units of the simulator take to change their state")
+	MainCmd.PersistentFlags().Float64Var(&globalFlags.SimulatorFailureRate, "simulator-failure-rate", 0, "probability of units started by the simulator to fail, from 0 to 1")
+	MainCmd.PersistentFlags().Float64Var(&globalFlags.SimulatorChaos, "simulator-chaos", 0, "probability of chaos failing units, delaying transitions and dropping machines of the simulator, from 0 to 1")
+	MainCmd.PersistentFlags().Int64Var(&globalFlags.SimulatorSeed, "simulator-seed", 0, "seed making latencies and failures of the simulator reproducible (random by default)")
+
 	MainCmd.PersistentFlags().StringVar(&globalFlags.Tunnel, "tunnel", "", "use a tunnel to communicate with fleet")
 	MainCmd.PersistentFlags().StringVar(&globalFlags.SSHUsername, "ssh-username", "core", "username to use when connecting to CoreOS machine")
 	MainCmd.PersistentFlags().DurationVar(&globalFlags.SSHTimeout, "ssh-timeout", time.Duration(10*time.Second), "timeout in seconds when establishing the connection via SSH")
@@ -112,12 +355,41 @@ func init() {
 
 	MainCmd.AddCommand(submitCmd)
 	MainCmd.AddCommand(statusCmd)
+	MainCmd.AddCommand(endpointsCmd)
+	MainCmd.AddCommand(existsCmd)
+	MainCmd.AddCommand(waitCmd)
 	MainCmd.AddCommand(startCmd)
 	MainCmd.AddCommand(stopCmd)
 	MainCmd.AddCommand(destroyCmd)
 	MainCmd.AddCommand(upCmd)
+	MainCmd.AddCommand(scaleCmd)
+	MainCmd.AddCommand(deployCmd)
+	MainCmd.AddCommand(cloneCmd)
+	MainCmd.AddCommand(drainCmd)
+	MainCmd.AddCommand(repairCmd)
+	MainCmd.AddCommand(exportCmd)
+	MainCmd.AddCommand(signCmd)
+	MainCmd.AddCommand(adoptCmd)
+	MainCmd.AddCommand(orphansCmd)
+	MainCmd.AddCommand(pullCmd)
 	MainCmd.AddCommand(updateCmd)
+	MainCmd.AddCommand(planCmd)
+	MainCmd.AddCommand(devCmd)
+	MainCmd.AddCommand(undoCmd)
+	MainCmd.AddCommand(statsCmd)
+	MainCmd.AddCommand(topCmd)
+	MainCmd.AddCommand(pinCmd)
+	MainCmd.AddCommand(unpinCmd)
+	MainCmd.AddCommand(annotateCmd)
 	MainCmd.AddCommand(validateCmd)
+	MainCmd.AddCommand(initCmd)
+	MainCmd.AddCommand(listCmd)
+	MainCmd.AddCommand(stackCmd)
+	MainCmd.AddCommand(freezeCmd)
+	MainCmd.AddCommand(clusterHealthCmd)
+	MainCmd.AddCommand(clustersCmd)
+	MainCmd.AddCommand(configCmd)
+	MainCmd.AddCommand(completionCmd)
 	MainCmd.AddCommand(versionCmd)
 }
 
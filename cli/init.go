@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/file-system/spec"
+)
+
+var (
+	initFlags struct {
+		Image    string
+		Owner    string
+		Sidekick bool
+	}
+
+	initCmd = &cobra.Command{
+		Use:   "init <group>",
+		Short: "Create a group",
+		Long:  "Scaffold a group directory containing a templated unit running a Docker image, one slice per machine, and a manifest. Using --sidekick, the manifest enables a sidekick unit announcing each slice in etcd. The created files are meant to be edited before bringing the group up",
+		Run:   initRun,
+	}
+)
+
+func init() {
+	initCmd.PersistentFlags().StringVar(&initFlags.Image, "image", "busybox", "Docker image run by the group")
+	initCmd.PersistentFlags().StringVar(&initFlags.Owner, "owner", "", "team or person responsible for the group, recorded in its manifest")
+	initCmd.PersistentFlags().BoolVar(&initFlags.Sidekick, "sidekick", false, "announce each slice in etcd using a sidekick unit")
+}
+
+func initRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting init")
+
+	if len(args) != 1 {
+		cmd.Help()
+		exit(1)
+	}
+	group := args[0]
+
+	files, err := scaffoldGroup(group, initFlags.Image, initFlags.Owner, initFlags.Sidekick)
+	handleInitCmdError(err)
+	err = writeScaffoldedGroup(fs, group, files)
+	handleInitCmdError(err)
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Println(filepath.Join(group, name))
+	}
+
+	newLogger.Info(newCtx, "Succeeded to create group '%s'. Edit its unit files, then bring it up using \"inagoctl up %s\".", group, group)
+}
+
+// scaffoldGroup returns the files of a new group, mapping file names to their
+// content. The group consists of a templated unit running the given Docker
+// image. Slices conflict with each other, so that each runs on a different
+// machine. In case sidekick is true, the manifest enables the sidekick units
+// announcing each slice in etcd. See extendRequestWithAnnounceUnits. In case
+// the group name cannot be used, an error that you can identify using
+// IsInvalidArgumentsError is returned.
+func scaffoldGroup(group, image, owner string, sidekick bool) (map[string]string, error) {
+	if group == "" || strings.ContainsAny(group, "@/\\. ") {
+		return nil, maskAnyf(invalidArgumentsError, "group name '%s' must not be empty or contain any of '@/\\. '", group)
+	}
+
+	files := map[string]string{
+		group + "-main@.service": fmt.Sprintf(`[Unit]
+Description=%[1]s %%i
+After=docker.service
+Requires=docker.service
+
+[Service]
+Restart=on-failure
+RestartSec=5
+TimeoutStartSec=0
+Environment="IMAGE=%[2]s"
+Environment="NAME=%%p-%%i"
+ExecStartPre=-/usr/bin/docker rm -f $NAME
+ExecStartPre=/usr/bin/docker pull $IMAGE
+ExecStart=/usr/bin/docker run --rm --name $NAME $IMAGE
+ExecStop=-/usr/bin/docker stop -t 10 $NAME
+
+[X-Fleet]
+Conflicts=%[1]s-main@*.service
+`, group, image),
+	}
+
+	manifest := groupManifest{
+		Description: fmt.Sprintf("Runs %s.", image),
+		Owner:       owner,
+	}
+	if sidekick {
+		manifest.Announce = &groupAnnounce{}
+	}
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	files[groupManifestFile] = string(raw) + "\n"
+
+	return files, nil
+}
+
+// writeScaffoldedGroup writes the given files to a group directory named like
+// the given group. Existing group directories are never touched.
+func writeScaffoldedGroup(fs filesystemspec.FileSystem, group string, files map[string]string) error {
+	if _, err := fs.ReadDir(group); err == nil {
+		return maskAnyf(groupAlreadyExistsError, "directory '%s' already exists", group)
+	}
+
+	for name, content := range files {
+		err := fs.WriteFile(filepath.Join(group, name), []byte(content), os.FileMode(0644))
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+
+	return nil
+}
+
+func handleInitCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+}
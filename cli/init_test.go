@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/fake"
+)
+
+func Test_Init_scaffoldGroup(t *testing.T) {
+	files, err := scaffoldGroup("web", "nginx:1.11", "frontend", true)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	if err := writeScaffoldedGroup(newFileSystem, "web", files); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// The scaffolded group must be valid as it is.
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = "web"
+	req, err := extendRequestWithContent(newFileSystem, controller.NewRequest(newRequestConfig))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if ok, err := controller.ValidateRequest(req); !ok {
+		t.Fatal("expected", true, "got", err)
+	}
+	if warnings := requestWarnings(req); len(warnings) != 0 {
+		t.Fatal("expected", nil, "got", warnings)
+	}
+	if len(req.Units) != 2 {
+		t.Fatal("expected", 2, "got", len(req.Units))
+	}
+
+	main := files["web-main@.service"]
+	if !strings.Contains(main, `Environment="IMAGE=nginx:1.11"`) {
+		t.Fatal("expected image in", main)
+	}
+	if !strings.Contains(main, "Conflicts=web-main@*.service") {
+		t.Fatal("expected conflicts in", main)
+	}
+	// The sidekick is generated from the manifest.
+	if req.Units[1].Name != "web-main-announce@.service" && req.Units[0].Name != "web-main-announce@.service" {
+		t.Fatal("expected", "web-main-announce@.service", "got", req.Units)
+	}
+
+	manifest, err := readGroupManifest(newFileSystem, "web")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if manifest.Owner != "frontend" {
+		t.Fatal("expected", "frontend", "got", manifest.Owner)
+	}
+
+	// Scaffolding the group again must not overwrite the existing directory.
+	if err := writeScaffoldedGroup(newFileSystem, "web", files); !IsGroupAlreadyExists(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
+
+func Test_Init_scaffoldGroup_WithoutSidekick(t *testing.T) {
+	files, err := scaffoldGroup("web", "nginx", "", false)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if strings.Contains(files[groupManifestFile], "announce") {
+		t.Fatal("expected no announce in", files[groupManifestFile])
+	}
+	if len(files) != 2 {
+		t.Fatal("expected", 2, "got", len(files))
+	}
+}
+
+func Test_Init_scaffoldGroup_InvalidName(t *testing.T) {
+	for _, group := range []string{"", "web@1", "apps/web"} {
+		if _, err := scaffoldGroup(group, "nginx", "", false); !IsInvalidArgumentsError(err) {
+			t.Fatal("expected", true, "got", false, "for", group)
+		}
+	}
+}
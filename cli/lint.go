@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	lintFlags struct {
+		Strict bool
+	}
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{validateCmd, submitCmd, upCmd, updateCmd} {
+		cmd.PersistentFlags().BoolVar(&lintFlags.Strict, "strict", false, "fail on warnings about groups, e.g. unsatisfiable constraints or deprecated options")
+	}
+}
+
+// requestWarnings returns all warnings about the units of the given request.
+// See controller.UnitWarnings and controller.LintRequest.
+func requestWarnings(req controller.Request) []string {
+	return append(controller.UnitWarnings(req.Units), controller.LintRequest(req)...)
+}
+
+// lintRequest checks the given request for warnings in case --strict is given.
+// If there are any, an error that you can identify using IsLintFailed is
+// returned.
+func lintRequest(req controller.Request) error {
+	if !lintFlags.Strict {
+		return nil
+	}
+
+	warnings := requestWarnings(req)
+	if len(warnings) > 0 {
+		return maskAnyf(lintFailedError, "group '%s': %s", req.Group, strings.Join(warnings, "; "))
+	}
+
+	return nil
+}
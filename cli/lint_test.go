@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+func Test_Lint_lintRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	defer func() { lintFlags.Strict = false }()
+
+	req := controller.Request{
+		RequestConfig: controller.RequestConfig{Group: "mygroup"},
+		Units: []controller.Unit{
+			{Name: "mygroup-1.service", Content: "[Service]\nExecStart=/bin/main\n"},
+		},
+	}
+
+	lintFlags.Strict = false
+	Expect(lintRequest(req)).To(BeNil())
+
+	lintFlags.Strict = true
+	Expect(IsLintFailed(lintRequest(req))).To(BeTrue())
+
+	req.Units[0].Content += "Restart=always\n"
+	Expect(lintRequest(req)).To(BeNil())
+}
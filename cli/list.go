@@ -0,0 +1,327 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	listFlags struct {
+		Local    bool
+		Long     bool
+		Quiet    bool
+		Selector string
+	}
+
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List groups",
+		Long:  "List the groups of the current working directory and whether they are deployed. Using --selector, groups deployed to the cluster are listed as well, filtered by their labels. Using --match, the units deployed to the cluster selected by a regular expression over their names are listed instead",
+		Run:   listRun,
+	}
+)
+
+func init() {
+	listCmd.PersistentFlags().BoolVar(&listFlags.Local, "local", false, "only list groups of the local filesystem without checking fleet")
+	listCmd.PersistentFlags().BoolVarP(&listFlags.Long, "long", "l", false, "show the description, owner and contact of groups")
+	listCmd.PersistentFlags().BoolVarP(&listFlags.Quiet, "quiet", "q", false, "only print group names")
+	listCmd.PersistentFlags().StringVar(&listFlags.Selector, "selector", "", "only list groups having the given labels, e.g. team=payments,tier")
+}
+
+func listRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting list")
+
+	if matchFlags.Match != "" {
+		listMatchRun()
+		return
+	}
+
+	groups, err := localGroups(fs)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+
+	tmpl, err := parseOutput(outputFlags.Output)
+	handleListCmdError(err)
+	if tmpl != nil {
+		err := executeOutput(os.Stdout, tmpl, createListOutput(groups))
+		handleListCmdError(err)
+		return
+	}
+	if structuredFormat() {
+		printResult(result{Kind: "list", Data: createListOutput(groups)})
+		return
+	}
+
+	if listFlags.Selector != "" {
+		listSelectorRun(groups)
+		return
+	}
+
+	if listFlags.Quiet {
+		for _, group := range groups {
+			fmt.Println(group)
+		}
+		return
+	}
+
+	header := "Group"
+	if !listFlags.Local {
+		header += " | Deployed"
+	}
+	if listFlags.Long {
+		header += " | Description | Owner | Contact"
+	}
+	data := []string{header, ""}
+	colors := []string{"", ""}
+
+	for _, group := range groups {
+		cells := []string{group}
+		deployed := false
+		if !listFlags.Local {
+			deployed, err = isDeployed(group)
+			handleListCmdError(err)
+			cells = append(cells, yesOrNo(deployed))
+		}
+		if listFlags.Long {
+			metadata, err := readGroupMetadata(fs, group)
+			handleListCmdError(err)
+			cells = append(cells, metadataCells(metadata)...)
+		}
+		data = append(data, strings.Join(cells, " | "))
+		colors = append(colors, deployedColor(deployed))
+	}
+
+	printResult(result{Kind: "list", Rows: data, Colors: colors})
+}
+
+// listSelectorRun lists the groups selected using --selector. See
+// selectedGroups.
+func listSelectorRun(groups []string) {
+	selected, err := selectedGroups(groups)
+	handleListCmdError(err)
+	if listFlags.Quiet {
+		for _, lg := range selected {
+			fmt.Println(lg.Group)
+		}
+		return
+	}
+
+	header := "Group | Labels"
+	if !listFlags.Local {
+		header = "Group | Deployed | Labels"
+	}
+	if listFlags.Long {
+		header += " | Description | Owner | Contact"
+	}
+	data := []string{header, ""}
+	colors := []string{"", ""}
+	for _, lg := range selected {
+		cells := []string{lg.Group}
+		if !listFlags.Local {
+			if !lg.Deployed {
+				// Groups deployed without labels are not known from the cluster.
+				lg.Deployed, err = isDeployed(lg.Group)
+				handleListCmdError(err)
+			}
+			cells = append(cells, yesOrNo(lg.Deployed))
+		}
+		cells = append(cells, formatLabels(lg.Labels))
+		if listFlags.Long {
+			metadata, err := readGroupMetadata(fs, lg.Group)
+			handleListCmdError(err)
+			cells = append(cells, metadataCells(metadata)...)
+		}
+		data = append(data, strings.Join(cells, " | "))
+		colors = append(colors, deployedColor(lg.Deployed))
+	}
+
+	printResult(result{Kind: "list", Rows: data, Colors: colors})
+}
+
+// listMatchRun lists the units deployed to the cluster selected using
+// --match, by unit file, along with their slices.
+func listMatchRun() {
+	if listFlags.Local || listFlags.Selector != "" || outputFlags.Output != "" {
+		handleListCmdError(maskAnyf(invalidArgumentsError, "--local, --selector and --output cannot be combined with --match"))
+	}
+	req, err := matchRequest()
+	handleListCmdError(err)
+
+	statusList, err := newController.GetStatus(newCtx, req)
+	if controller.IsUnitNotFound(err) {
+		statusList = nil
+	} else {
+		handleListCmdError(err)
+	}
+	grouped := groupMatchedUnits(statusList)
+
+	if structuredFormat() {
+		printResult(result{Kind: "list", Data: grouped})
+		return
+	}
+	if listFlags.Quiet {
+		for _, mu := range grouped {
+			fmt.Println(mu.Unit)
+		}
+		return
+	}
+
+	printResult(result{Kind: "list", Rows: createMatchList(grouped)})
+}
+
+// createListOutput creates the data describing the given local groups, or
+// the groups selected using --selector, for --output templates and structured
+// formats. See listOutput.
+func createListOutput(groups []string) listOutput {
+	var listed []labeledGroup
+	if listFlags.Selector != "" {
+		var err error
+		listed, err = selectedGroups(groups)
+		handleListCmdError(err)
+	} else {
+		for _, group := range groups {
+			manifest, err := readGroupManifest(fs, group)
+			handleListCmdError(err)
+			listed = append(listed, labeledGroup{Group: group, Labels: manifest.Labels})
+		}
+	}
+
+	var out listOutput
+	for _, lg := range listed {
+		if !listFlags.Local && !lg.Deployed {
+			var err error
+			lg.Deployed, err = isDeployed(lg.Group)
+			handleListCmdError(err)
+		}
+		metadata, err := readGroupMetadata(fs, lg.Group)
+		handleListCmdError(err)
+		out.Groups = append(out.Groups, newGroupOutput(lg, metadata))
+	}
+
+	return out
+}
+
+// selectedGroups returns the given local groups and the groups deployed to
+// the cluster having labels matching --selector. Labels of deployed groups
+// are taken from the cluster. Labels of other groups are taken from their
+// manifest.
+func selectedGroups(groups []string) ([]labeledGroup, error) {
+	selector, err := controller.ParseSelector(listFlags.Selector)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	local := map[string]map[string]string{}
+	for _, group := range groups {
+		manifest, err := readGroupManifest(fs, group)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		local[group] = manifest.Labels
+	}
+	deployed := map[string]map[string]string{}
+	if !listFlags.Local {
+		deployed, err = newController.GroupLabels(newCtx)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	return selectGroups(selector, local, deployed), nil
+}
+
+// labeledGroup is a group selected by its labels.
+type labeledGroup struct {
+	Group  string
+	Labels map[string]string
+
+	// Deployed is true in case the labels have been taken from the cluster.
+	Deployed bool
+}
+
+// selectGroups returns the groups matching the given selector, sorted by
+// name. Labels of deployed groups win over the labels of local groups.
+func selectGroups(selector controller.Selector, local, deployed map[string]map[string]string) []labeledGroup {
+	var names []string
+	for group := range deployed {
+		names = append(names, group)
+	}
+	for group := range local {
+		if _, ok := deployed[group]; !ok {
+			names = append(names, group)
+		}
+	}
+	sort.Strings(names)
+
+	var selected []labeledGroup
+	for _, group := range names {
+		lg := labeledGroup{Group: group, Labels: local[group]}
+		if labels, ok := deployed[group]; ok {
+			lg.Labels = labels
+			lg.Deployed = true
+		}
+		if selector.Matches(lg.Labels) {
+			selected = append(selected, lg)
+		}
+	}
+
+	return selected
+}
+
+// formatLabels renders the given labels sorted by key.
+//
+//   map[tier:backend team:payments]  =>  team=payments,tier=backend
+//
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+
+	var pairs []string
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func handleListCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
+
+// isDeployed checks whether any unit of the given group is known to fleet.
+func isDeployed(group string) (bool, error) {
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	req := controller.NewRequest(newRequestConfig)
+
+	_, err := newController.GetStatus(newCtx, req)
+	if controller.IsUnitNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, maskAny(err)
+	}
+
+	return true, nil
+}
+
+func yesOrNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
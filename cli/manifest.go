@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/spec"
+)
+
+// groupManifestFile is the name of the optional file within a group directory
+// describing the group. It is not prefixed with the group name, so it is never
+// considered a unit file.
+const groupManifestFile = "group.json"
+
+// groupManifest describes a group in addition to its unit files.
+//
+//   {
+//     "description": "Serves the public payments API.",
+//     "owner": "payments",
+//     "contact": "#payments-oncall",
+//     "version": "1.4.2",
+//     "labels": {"team": "payments"},
+//     "readiness": {"mygroup-check@.service": "active/running"},
+//     "oneshot": ["mygroup-init@.service"],
+//     "announce": {"prefix": "/services", "ttl": "60s"}
+//   }
+//
+type groupManifest struct {
+	// Description tells what the group is about. It overrides the first
+	// paragraph of group.md. See readGroupMetadata.
+	Description string `json:"description,omitempty"`
+
+	// Owner is the team or person responsible for the group.
+	Owner string `json:"owner,omitempty"`
+
+	// Contact tells how to reach the owner, e.g. a chat channel or pager.
+	Contact string `json:"contact,omitempty"`
+
+	// Version is the semantic version of the group, embedded into all units.
+	// See controller.Request.WithSemVer.
+	Version string `json:"version,omitempty"`
+
+	// Labels are attached to all units of the group. See
+	// controller.Request.WithLabels.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Readiness maps unit file names to the systemd states the unit is
+	// considered running in while waiting for it. See
+	// controller.ParseReadyStates.
+	Readiness map[string]string `json:"readiness,omitempty"`
+
+	// Oneshot lists unit file names of units exiting after doing their work,
+	// e.g. units of type oneshot. Such units are considered done once they
+	// exited, instead of failed. See controller.OneshotReadyStates.
+	Oneshot []string `json:"oneshot,omitempty"`
+
+	// Announce enables sidekick units announcing the service units of the
+	// group in etcd. See controller.Request.WithAnnounceUnits.
+	Announce *groupAnnounce `json:"announce,omitempty"`
+}
+
+// groupAnnounce configures the sidekick units of a group. Empty fields use
+// the defaults of controller.DefaultAnnounceConfig.
+type groupAnnounce struct {
+	// Prefix is the etcd directory units are announced in.
+	Prefix string `json:"prefix,omitempty"`
+
+	// TTL is the time announcements expire after, e.g. "60s".
+	TTL string `json:"ttl,omitempty"`
+}
+
+var (
+	labelFlags struct {
+		Labels []string
+	}
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{submitCmd, upCmd, updateCmd, deployCmd} {
+		cmd.PersistentFlags().StringSliceVar(&labelFlags.Labels, "label", nil, "label attached to the group in addition to the labels of its manifest, e.g. team=payments")
+	}
+}
+
+// readGroupManifest reads the manifest of the given group. Groups without
+// manifest get an empty one. In case the manifest cannot be parsed, an error
+// that you can identify using IsInvalidManifest is returned.
+func readGroupManifest(fs filesystemspec.FileSystem, group string) (groupManifest, error) {
+	fileInfos, err := fs.ReadDir(group)
+	if err != nil {
+		return groupManifest{}, maskAny(err)
+	}
+	found := false
+	for _, fileInfo := range fileInfos {
+		if fileInfo.Name() == groupManifestFile && !fileInfo.IsDir() {
+			found = true
+		}
+	}
+	if !found {
+		return groupManifest{}, nil
+	}
+
+	raw, err := fs.ReadFile(filepath.Join(group, groupManifestFile))
+	if err != nil {
+		return groupManifest{}, maskAny(err)
+	}
+	var manifest groupManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return groupManifest{}, maskAnyf(invalidManifestError, "%s: %s", group, err.Error())
+	}
+
+	return manifest, nil
+}
+
+// groupLabels returns the labels of the given manifest merged with the labels
+// given using --label. Labels given on the command line win.
+func groupLabels(manifest groupManifest, flagLabels []string) (map[string]string, error) {
+	labels, err := controller.ParseLabels(flagLabels)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	for key, value := range manifest.Labels {
+		if _, ok := labels[key]; !ok {
+			labels[key] = value
+		}
+	}
+
+	return labels, nil
+}
+
+// extendRequestWithReadiness adds the ready states of the given group's
+// manifest to the given request, including the ones of oneshot units. Groups
+// not available in the current working directory have no ready states. In case
+// the ready states of the manifest are malformed, an error that you can
+// identify using IsInvalidManifest is returned.
+func extendRequestWithReadiness(fs filesystemspec.FileSystem, req controller.Request) (controller.Request, error) {
+	if _, err := fs.ReadDir(req.Group); err != nil {
+		return req, nil
+	}
+	manifest, err := readGroupManifest(fs, req.Group)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+	if len(manifest.Readiness) == 0 && len(manifest.Oneshot) == 0 {
+		return req, nil
+	}
+
+	req.ReadyStates = map[string][]controller.ReadyState{}
+	for name, raw := range manifest.Readiness {
+		if !strings.HasPrefix(name, req.Group) {
+			return controller.Request{}, maskAnyf(invalidManifestError, "%s: readiness of unit '%s' not belonging to the group", req.Group, name)
+		}
+		states, err := controller.ParseReadyStates(raw)
+		if err != nil {
+			return controller.Request{}, maskAnyf(invalidManifestError, "%s: %s", req.Group, err.Error())
+		}
+		req.ReadyStates[name] = states
+	}
+	for _, name := range manifest.Oneshot {
+		if !strings.HasPrefix(name, req.Group) {
+			return controller.Request{}, maskAnyf(invalidManifestError, "%s: oneshot unit '%s' not belonging to the group", req.Group, name)
+		}
+		if _, ok := req.ReadyStates[name]; ok {
+			return controller.Request{}, maskAnyf(invalidManifestError, "%s: unit '%s' is oneshot and has readiness", req.Group, name)
+		}
+		req.ReadyStates[name] = controller.OneshotReadyStates
+	}
+
+	return req, nil
+}
+
+// extendRequestWithAnnounceUnits adds the sidekick units announcing the units
+// of the given request, in case the given manifest enables them. In case the
+// announce configuration of the manifest is malformed, an error that you can
+// identify using IsInvalidManifest is returned.
+func extendRequestWithAnnounceUnits(manifest groupManifest, req controller.Request) (controller.Request, error) {
+	if manifest.Announce == nil {
+		return req, nil
+	}
+
+	newAnnounceConfig := controller.DefaultAnnounceConfig()
+	if manifest.Announce.Prefix != "" {
+		newAnnounceConfig.Prefix = manifest.Announce.Prefix
+	}
+	if manifest.Announce.TTL != "" {
+		ttl, err := time.ParseDuration(manifest.Announce.TTL)
+		if err != nil {
+			return controller.Request{}, maskAnyf(invalidManifestError, "%s: announce TTL: %s", req.Group, err.Error())
+		}
+		newAnnounceConfig.TTL = ttl
+	}
+
+	newReq, err := req.WithAnnounceUnits(newAnnounceConfig)
+	if err != nil {
+		return controller.Request{}, maskAnyf(invalidManifestError, "%s: %s", req.Group, err.Error())
+	}
+
+	return newReq, nil
+}
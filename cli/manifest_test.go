@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/fake"
+)
+
+func Test_Manifest_extendRequestWithContent(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	err := newFileSystem.WriteFile("mygroup/mygroup-1.service", []byte(givenSomeUnitFileContent()), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"labels": {"team": "payments", "tier": "backend"}}`), os.FileMode(0644))
+	Expect(err).To(BeNil())
+
+	labelFlags.Labels = []string{"tier=frontend"}
+	defer func() { labelFlags.Labels = nil }()
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = "mygroup"
+	req, err := extendRequestWithContent(newFileSystem, controller.NewRequest(newRequestConfig))
+	Expect(err).To(BeNil())
+	Expect(req.Units).To(HaveLen(1))
+
+	group, labels := controller.UnitLabels(req.Units[0].Content)
+	Expect(group).To(Equal("mygroup"))
+	Expect(labels).To(Equal(map[string]string{"team": "payments", "tier": "frontend"}))
+
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"version": "1.4.2"}`), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	req, err = extendRequestWithContent(newFileSystem, controller.NewRequest(newRequestConfig))
+	Expect(err).To(BeNil())
+	Expect(controller.UnitSemVer(req.Units[0].Content)).To(Equal("1.4.2"))
+
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"version": "latest"}`), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	_, err = extendRequestWithContent(newFileSystem, controller.NewRequest(newRequestConfig))
+	Expect(IsInvalidManifest(err)).To(BeTrue())
+
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"labels": `), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	_, err = extendRequestWithContent(newFileSystem, controller.NewRequest(newRequestConfig))
+	Expect(IsInvalidManifest(err)).To(BeTrue())
+}
+
+func Test_Manifest_extendRequestWithAnnounceUnits(t *testing.T) {
+	RegisterTestingT(t)
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = "mygroup"
+	req := controller.NewRequest(newRequestConfig)
+	req.Units = []controller.Unit{{Name: "mygroup-app@.service", Content: givenSomeUnitFileContent()}}
+
+	newReq, err := extendRequestWithAnnounceUnits(groupManifest{}, req)
+	Expect(err).To(BeNil())
+	Expect(newReq.Units).To(HaveLen(1))
+
+	manifest := groupManifest{Announce: &groupAnnounce{Prefix: "/apps", TTL: "30s"}}
+	newReq, err = extendRequestWithAnnounceUnits(manifest, req)
+	Expect(err).To(BeNil())
+	Expect(newReq.Units).To(HaveLen(2))
+	Expect(newReq.Units[1].Name).To(Equal("mygroup-app-announce@.service"))
+	Expect(newReq.Units[1].Content).To(ContainSubstring("etcdctl set /apps/mygroup-app/%i ${COREOS_PRIVATE_IPV4} --ttl 30;"))
+
+	manifest = groupManifest{Announce: &groupAnnounce{TTL: "soon"}}
+	_, err = extendRequestWithAnnounceUnits(manifest, req)
+	Expect(IsInvalidManifest(err)).To(BeTrue())
+	manifest = groupManifest{Announce: &groupAnnounce{TTL: "1s"}}
+	_, err = extendRequestWithAnnounceUnits(manifest, req)
+	Expect(IsInvalidManifest(err)).To(BeTrue())
+}
+
+func Test_Manifest_extendRequestWithReadiness(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	err := newFileSystem.WriteFile("mygroup/mygroup-init.service", []byte(givenSomeUnitFileContent()), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"readiness": {"mygroup-init.service": "inactive/dead, active/exited"}}`), os.FileMode(0644))
+	Expect(err).To(BeNil())
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = "mygroup"
+	req, err := extendRequestWithReadiness(newFileSystem, controller.NewRequest(newRequestConfig))
+	Expect(err).To(BeNil())
+	Expect(req.ReadyStates).To(Equal(map[string][]controller.ReadyState{
+		"mygroup-init.service": {{Active: "inactive", Sub: "dead"}, {Active: "active", Sub: "exited"}},
+	}))
+
+	// Groups not available locally have no ready states.
+	newRequestConfig.Group = "othergroup"
+	req, err = extendRequestWithReadiness(newFileSystem, controller.NewRequest(newRequestConfig))
+	Expect(err).To(BeNil())
+	Expect(req.ReadyStates).To(BeNil())
+
+	newRequestConfig.Group = "mygroup"
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"readiness": {"mygroup-init.service": "inactive/"}}`), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	_, err = extendRequestWithReadiness(newFileSystem, controller.NewRequest(newRequestConfig))
+	Expect(IsInvalidManifest(err)).To(BeTrue())
+
+	// Oneshot units are done once they exited.
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"oneshot": ["mygroup-init.service"]}`), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	req, err = extendRequestWithReadiness(newFileSystem, controller.NewRequest(newRequestConfig))
+	Expect(err).To(BeNil())
+	Expect(req.ReadyStates).To(Equal(map[string][]controller.ReadyState{
+		"mygroup-init.service": controller.OneshotReadyStates,
+	}))
+
+	for _, manifest := range []string{
+		`{"oneshot": ["othergroup-init.service"]}`,
+		`{"oneshot": ["mygroup-init.service"], "readiness": {"mygroup-init.service": "inactive/dead"}}`,
+	} {
+		err = newFileSystem.WriteFile("mygroup/group.json", []byte(manifest), os.FileMode(0644))
+		Expect(err).To(BeNil())
+		_, err = extendRequestWithReadiness(newFileSystem, controller.NewRequest(newRequestConfig))
+		Expect(IsInvalidManifest(err)).To(BeTrue(), manifest)
+	}
+}
+
+func Test_Manifest_selectGroups(t *testing.T) {
+	RegisterTestingT(t)
+
+	selector, err := controller.ParseSelector("team=payments")
+	Expect(err).To(BeNil())
+
+	local := map[string]map[string]string{
+		"api":    {"team": "payments"},
+		"search": {"team": "search"},
+		"worker": {"team": "search"},
+	}
+	deployed := map[string]map[string]string{
+		"billing": {"team": "payments", "tier": "backend"},
+		"worker":  {"team": "payments"},
+	}
+
+	selected := selectGroups(selector, local, deployed)
+	Expect(selected).To(Equal([]labeledGroup{
+		{Group: "api", Labels: map[string]string{"team": "payments"}},
+		{Group: "billing", Labels: map[string]string{"team": "payments", "tier": "backend"}, Deployed: true},
+		{Group: "worker", Labels: map[string]string{"team": "payments"}, Deployed: true},
+	}))
+
+	Expect(formatLabels(selected[1].Labels)).To(Equal("team=payments,tier=backend"))
+	Expect(formatLabels(nil)).To(Equal("-"))
+}
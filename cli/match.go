@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+)
+
+var (
+	matchFlags struct {
+		Match string
+	}
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{statusCmd, listCmd, destroyCmd} {
+		cmd.PersistentFlags().StringVar(&matchFlags.Match, "match", "", "select units by a regular expression over their names instead of by group, e.g. '^legacy-.*@\\d+\\.service$'")
+	}
+}
+
+// matchRequest creates a request selecting the units given using --match. The
+// expression is used as group, so that messages refer to it.
+func matchRequest() (controller.Request, error) {
+	match, err := controller.ParseUnitMatch(matchFlags.Match)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+	req := controller.NewRequest(controller.RequestConfig{Group: matchFlags.Match})
+	req.Match = match
+
+	return req, nil
+}
+
+// matchedUnits describes the units selected using --match sharing the same
+// unit file, e.g. "legacy-db@.service" for "legacy-db@1.service".
+type matchedUnits struct {
+	Unit   string
+	Slices []string
+}
+
+// groupMatchedUnits groups the given units by their unit file, sorted by name.
+// Slice IDs are sorted as well.
+func groupMatchedUnits(usl []fleet.UnitStatus) []matchedUnits {
+	byUnit := map[string]*matchedUnits{}
+	var names []string
+	for _, us := range usl {
+		name := common.UnitBase(us.Name)
+		if us.SliceID != "" {
+			name += "@"
+		}
+		name += common.UnitExtension(us.Name)
+
+		mu, ok := byUnit[name]
+		if !ok {
+			mu = &matchedUnits{Unit: name}
+			byUnit[name] = mu
+			names = append(names, name)
+		}
+		if us.SliceID != "" && !containsString(mu.Slices, us.SliceID) {
+			mu.Slices = append(mu.Slices, us.SliceID)
+		}
+	}
+	sort.Strings(names)
+
+	var grouped []matchedUnits
+	for _, name := range names {
+		sort.Strings(byUnit[name].Slices)
+		grouped = append(grouped, *byUnit[name])
+	}
+
+	return grouped
+}
+
+// createMatchList renders the given grouped units as table rows.
+func createMatchList(grouped []matchedUnits) []string {
+	rows := []string{"Unit | Slices", ""}
+	for _, mu := range grouped {
+		slices := "-"
+		if len(mu.Slices) > 0 {
+			slices = fmt.Sprintf("%d (%s)", len(mu.Slices), strings.Join(mu.Slices, ", "))
+		}
+		rows = append(rows, fmt.Sprintf("%s | %s", mu.Unit, slices))
+	}
+
+	return rows
+}
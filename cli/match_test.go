@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_Match_groupMatchedUnits(t *testing.T) {
+	grouped := groupMatchedUnits([]fleet.UnitStatus{
+		{Name: "legacy-db@2.service", SliceID: "2"},
+		{Name: "legacy-cache.service"},
+		{Name: "legacy-db@1.service", SliceID: "1"},
+		{Name: "legacy-db@1.timer", SliceID: "1"},
+	})
+	expected := []matchedUnits{
+		{Unit: "legacy-cache.service"},
+		{Unit: "legacy-db@.service", Slices: []string{"1", "2"}},
+		{Unit: "legacy-db@.timer", Slices: []string{"1"}},
+	}
+	if !reflect.DeepEqual(grouped, expected) {
+		t.Fatal("expected", expected, "got", grouped)
+	}
+
+	rows := createMatchList(grouped)
+	expectedRows := []string{
+		"Unit | Slices",
+		"",
+		"legacy-cache.service | -",
+		"legacy-db@.service | 2 (1, 2)",
+		"legacy-db@.timer | 1 (1)",
+	}
+	if !reflect.DeepEqual(rows, expectedRows) {
+		t.Fatal("expected", expectedRows, "got", rows)
+	}
+}
+
+func Test_Match_unitStatusesByBase(t *testing.T) {
+	bases, byBase := unitStatusesByBase([]fleet.UnitStatus{
+		{Name: "legacy-db@2.service"},
+		{Name: "legacy-cache.service"},
+		{Name: "legacy-db@1.service"},
+	})
+	if !reflect.DeepEqual(bases, []string{"legacy-cache", "legacy-db"}) {
+		t.Fatal("expected", []string{"legacy-cache", "legacy-db"}, "got", bases)
+	}
+	if len(byBase["legacy-db"]) != 2 || byBase["legacy-db"][0].Name != "legacy-db@2.service" {
+		t.Fatal("expected", 2, "units of legacy-db in order, got", byBase["legacy-db"])
+	}
+}
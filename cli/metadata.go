@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/giantswarm/inago/file-system/spec"
+)
+
+// groupReadmeFile is the name of the optional file within a group directory
+// describing the group in Markdown. Its first paragraph is used as the
+// group's description, in case the manifest does not provide one.
+const groupReadmeFile = "group.md"
+
+// groupMetadata tells on-call engineers what a group is about and whom to
+// contact about it.
+type groupMetadata struct {
+	Description string
+	Owner       string
+	Contact     string
+}
+
+// isEmpty checks whether there is any metadata.
+func (m groupMetadata) isEmpty() bool {
+	return m.Description == "" && m.Owner == "" && m.Contact == ""
+}
+
+// readGroupMetadata reads the metadata of the given group from its manifest
+// and group.md. Groups not available in the current working directory have
+// no metadata. In case the manifest cannot be parsed, an error that you can
+// identify using IsInvalidManifest is returned.
+func readGroupMetadata(fs filesystemspec.FileSystem, group string) (groupMetadata, error) {
+	fileInfos, err := fs.ReadDir(group)
+	if err != nil {
+		return groupMetadata{}, nil
+	}
+	manifest, err := readGroupManifest(fs, group)
+	if err != nil {
+		return groupMetadata{}, maskAny(err)
+	}
+
+	metadata := groupMetadata{
+		Description: manifest.Description,
+		Owner:       manifest.Owner,
+		Contact:     manifest.Contact,
+	}
+	if metadata.Description != "" {
+		return metadata, nil
+	}
+	for _, fileInfo := range fileInfos {
+		if fileInfo.Name() != groupReadmeFile || fileInfo.IsDir() {
+			continue
+		}
+		raw, err := fs.ReadFile(filepath.Join(group, groupReadmeFile))
+		if err != nil {
+			return groupMetadata{}, maskAny(err)
+		}
+		metadata.Description = firstParagraph(string(raw))
+	}
+
+	return metadata, nil
+}
+
+// firstParagraph returns the first paragraph of the given Markdown text,
+// joined into a single line. Headings are skipped.
+//
+//   # My App
+//
+//   Serves the public API.
+//   Talks to the database.
+//
+//   =>  Serves the public API. Talks to the database.
+//
+func firstParagraph(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			if len(lines) > 0 {
+				break
+			}
+			continue
+		}
+		if line == "" {
+			if len(lines) > 0 {
+				break
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, " ")
+}
+
+// createMetadataSummary creates a table row for each of the given metadata
+// being set, to be formatted using columnize.
+func createMetadataSummary(metadata groupMetadata) []string {
+	var rows []string
+	for _, field := range []struct{ Name, Value string }{
+		{"Description", metadata.Description},
+		{"Owner", metadata.Owner},
+		{"Contact", metadata.Contact},
+	} {
+		if field.Value != "" {
+			rows = append(rows, field.Name+": | "+field.Value)
+		}
+	}
+
+	return rows
+}
+
+// metadataCells returns the table cells of the given metadata as shown by
+// list --long. Missing values are shown as "-".
+func metadataCells(metadata groupMetadata) []string {
+	var cells []string
+	for _, value := range []string{metadata.Description, metadata.Owner, metadata.Contact} {
+		if value == "" {
+			value = "-"
+		}
+		cells = append(cells, value)
+	}
+
+	return cells
+}
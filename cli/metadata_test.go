@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/file-system/fake"
+)
+
+func Test_Metadata_readGroupMetadata(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	err := newFileSystem.WriteFile("mygroup/mygroup-1.service", []byte(givenSomeUnitFileContent()), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	err = newFileSystem.WriteFile("mygroup/group.md", []byte("# My Group\n\nServes the public API.\nOwned by payments.\n\n## Details\n\nMore.\n"), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"owner": "payments", "contact": "#payments-oncall"}`), os.FileMode(0644))
+	Expect(err).To(BeNil())
+
+	metadata, err := readGroupMetadata(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(metadata).To(Equal(groupMetadata{
+		Description: "Serves the public API. Owned by payments.",
+		Owner:       "payments",
+		Contact:     "#payments-oncall",
+	}))
+	Expect(createMetadataSummary(metadata)).To(Equal([]string{
+		"Description: | Serves the public API. Owned by payments.",
+		"Owner: | payments",
+		"Contact: | #payments-oncall",
+	}))
+
+	// The description of the manifest wins.
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"description": "Payments API"}`), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	metadata, err = readGroupMetadata(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(metadataCells(metadata)).To(Equal([]string{"Payments API", "-", "-"}))
+
+	// Groups not available locally have no metadata.
+	metadata, err = readGroupMetadata(newFileSystem, "othergroup")
+	Expect(err).To(BeNil())
+	Expect(metadata.isEmpty()).To(BeTrue())
+}
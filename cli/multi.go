@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/spec"
+	"github.com/giantswarm/inago/task"
+)
+
+var (
+	multiGroupFlags struct {
+		AllLocal    bool
+		Parallelism int
+	}
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{submitCmd, upCmd, startCmd, stopCmd, destroyCmd} {
+		cmd.PersistentFlags().BoolVar(&multiGroupFlags.AllLocal, "all-local", false, "operate on all groups of the current working directory")
+		cmd.PersistentFlags().IntVar(&multiGroupFlags.Parallelism, "parallelism", 4, "maximum number of groups operated on at the same time")
+	}
+}
+
+// parseMultiGroupArgs checks whether the given command line arguments refer to
+// multiple groups, or all local groups in case allLocal is true. In case
+// withScale is true, a trailing number is considered the scale of all groups.
+// It defaults to 1. The returned bool is false in case the arguments refer to
+// a single group, which is left to the normal handling of the command.
+//
+//   api worker cron     =>  [api worker cron], 1, true
+//   api worker 3        =>  [api worker], 3, true
+//   api 3               =>  [], 0, false
+//   api@1 api@2         =>  [], 0, false
+//
+func parseMultiGroupArgs(fs filesystemspec.FileSystem, args []string, allLocal, withScale bool) ([]string, int, bool, error) {
+	scale := 1
+	if withScale && len(args) > 0 {
+		if n, err := strconv.Atoi(args[len(args)-1]); err == nil {
+			scale = n
+			args = args[:len(args)-1]
+		}
+	}
+
+	if allLocal {
+		if len(args) > 0 {
+			return nil, 0, false, maskAnyf(invalidArgumentsError, "groups given together with --all-local")
+		}
+		groups, err := localGroups(fs)
+		if err != nil {
+			return nil, 0, false, maskAny(err)
+		}
+		if len(groups) == 0 {
+			return nil, 0, false, maskAnyf(groupNotFoundError, "no groups found in the current working directory")
+		}
+		return groups, scale, true, nil
+	}
+
+	var groups []string
+	seen := map[string]struct{}{}
+	for _, arg := range resolveGroups(args) {
+		if strings.Contains(arg, "@") || isGroupArchive(arg) {
+			return nil, 0, false, nil
+		}
+		if _, ok := seen[arg]; !ok {
+			seen[arg] = struct{}{}
+			groups = append(groups, arg)
+		}
+	}
+	if len(groups) < 2 {
+		return nil, 0, false, nil
+	}
+
+	return groups, scale, true, nil
+}
+
+// runMultiGroup executes the given action for each of the given groups using
+// controller.RunGroups and prints a summary of the results. Progress of single
+// groups is not rendered, since it would be interleaved. In case any action
+// failed, inagoctl exits with a non-zero status.
+func runMultiGroup(descriptor string, groups []string, action func(ctx context.Context, group string) error) {
+	newLogger.Info(newCtx, "Going to %s %d groups: %v.", descriptor, len(groups), groups)
+
+	reportGroupsBefore(newCtx, groups)
+	results := controller.RunGroups(newCtx, groups, multiGroupFlags.Parallelism, action)
+	reportGroupResults(results)
+	printResult(result{Kind: "groups", Rows: createMultiGroupSummary(results)})
+
+	var failed []string
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result.Group)
+		}
+	}
+	if len(failed) > 0 {
+		newLogger.Error(newCtx, "Failed to %s %d of %d groups: %v.", descriptor, len(failed), len(groups), failed)
+		for _, result := range results {
+			if result.Err != nil {
+				diagnoseConnection(newCtx, result.Err)
+				break
+			}
+		}
+		exit(1)
+	}
+
+	newLogger.Info(newCtx, "Succeeded to %s %d groups: %v.", descriptor, len(groups), groups)
+}
+
+// createMultiGroupSummary renders the given results as table rows.
+func createMultiGroupSummary(results []controller.GroupResult) []string {
+	rows := []string{"Group | Result | Duration | Error", ""}
+	for _, result := range results {
+		status := "ok"
+		message := "-"
+		if result.Err != nil {
+			status = "failed"
+			message = result.Err.Error()
+		}
+		rows = append(rows, fmt.Sprintf("%s | %s | %s | %s", result.Group, status, (result.Duration/time.Second)*time.Second, message))
+	}
+
+	return rows
+}
+
+// waitForGroupTask waits for the task created by the given function, unless
+// --no-block is given. In case the task fails, its error is returned.
+func waitForGroupTask(ctx context.Context, create func() (*task.Task, error)) error {
+	taskObject, err := create()
+	if err != nil {
+		return maskAny(err)
+	}
+	if globalFlags.NoBlock {
+		return nil
+	}
+
+	taskObject, err = newController.WaitForTask(ctx, taskObject.ID, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	if task.HasFailedStatus(taskObject) {
+		return maskAny(taskObject.Error)
+	}
+
+	return nil
+}
+
+// submitGroup submits the given number of slices of the given local group.
+func submitGroup(ctx context.Context, group string, scale int) error {
+	req, err := createSubmitRequest(fs, group, scale)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return waitForGroupTask(ctx, func() (*task.Task, error) {
+		return newController.Submit(ctx, req)
+	})
+}
+
+// existingGroupAction returns an action executing the given controller
+// operation against all slices of a group.
+func existingGroupAction(op func(ctx context.Context, req controller.Request) (*task.Task, error)) func(ctx context.Context, group string) error {
+	return func(ctx context.Context, group string) error {
+		newRequestConfig := controller.DefaultRequestConfig()
+		newRequestConfig.Group = group
+		req, err := newController.ExtendWithExistingSliceIDs(controller.NewRequest(newRequestConfig))
+		if err != nil {
+			return maskAny(err)
+		}
+		req, err = withMaxFailures(withStagger(withRetry(req)))
+		if err != nil {
+			return maskAny(err)
+		}
+		req, err = extendRequestWithReadiness(fs, req)
+		if err != nil {
+			return maskAny(err)
+		}
+
+		return waitForGroupTask(ctx, func() (*task.Task, error) {
+			return op(ctx, req)
+		})
+	}
+}
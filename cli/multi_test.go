@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/inago/file-system/fake"
+)
+
+func Test_Multi_parseMultiGroupArgs(t *testing.T) {
+	newFileSystem := filesystemfake.NewFileSystem()
+	for _, name := range []string{"api/api-1@.service", "worker/worker-1@.service"} {
+		err := newFileSystem.WriteFile(name, []byte(givenSomeUnitFileContent()), os.FileMode(0644))
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	testCases := []struct {
+		Args          []string
+		AllLocal      bool
+		WithScale     bool
+		ErrorMatcher  func(err error) bool
+		ExpectedOK    bool
+		ExpectedGroup []string
+		ExpectedScale int
+	}{
+		{Args: []string{"api"}, WithScale: true, ExpectedOK: false},
+		{Args: []string{"api", "3"}, WithScale: true, ExpectedOK: false},
+		{Args: []string{"api@1", "api@2"}, ExpectedOK: false},
+		{Args: []string{"api", "api"}, ExpectedOK: false},
+		{Args: []string{"api.tar.gz", "3"}, WithScale: true, ExpectedOK: false},
+		{Args: []string{"api", "worker", "cron"}, WithScale: true, ExpectedOK: true, ExpectedGroup: []string{"api", "worker", "cron"}, ExpectedScale: 1},
+		{Args: []string{"api", "worker", "3"}, WithScale: true, ExpectedOK: true, ExpectedGroup: []string{"api", "worker"}, ExpectedScale: 3},
+		{Args: []string{"api", "worker", "api"}, ExpectedOK: true, ExpectedGroup: []string{"api", "worker"}, ExpectedScale: 1},
+		{Args: nil, AllLocal: true, ExpectedOK: true, ExpectedGroup: []string{"api", "worker"}, ExpectedScale: 1},
+		{Args: []string{"2"}, AllLocal: true, WithScale: true, ExpectedOK: true, ExpectedGroup: []string{"api", "worker"}, ExpectedScale: 2},
+		{Args: []string{"api"}, AllLocal: true, ErrorMatcher: IsInvalidArgumentsError},
+	}
+
+	for i, testCase := range testCases {
+		groups, scale, ok, err := parseMultiGroupArgs(newFileSystem, testCase.Args, testCase.AllLocal, testCase.WithScale)
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.ExpectedOK {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedOK, "got", ok)
+		}
+		if !ok {
+			continue
+		}
+		if !reflect.DeepEqual(groups, testCase.ExpectedGroup) {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedGroup, "got", groups)
+		}
+		if scale != testCase.ExpectedScale {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedScale, "got", scale)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/notify"
+)
+
+var (
+	// notifiers are told when operations wrapped using notifyingRun start,
+	// succeed or fail. They are created from the configuration file.
+	notifiers []notify.Notifier
+
+	// notification is the message describing the operation currently
+	// executed, in case it is wrapped using notifyingRun.
+	notification *notify.Message
+
+	// notificationStart is the time the current operation started.
+	notificationStart time.Time
+)
+
+// newNotifiers creates the notifiers configured in the given configuration.
+// The GitHub notifier is skipped with a warning in case its token or ref is
+// missing.
+func newNotifiers(c config) ([]notify.Notifier, error) {
+	var newNotifiers []notify.Notifier
+
+	if c.Notifications.Slack != nil {
+		newSlackConfig := notify.DefaultSlackConfig()
+		newSlackConfig.WebhookURL = c.Notifications.Slack.WebhookURL
+		newSlackConfig.Channel = c.Notifications.Slack.Channel
+		newSlackConfig.MentionOnFailure = c.Notifications.Slack.MentionOnFailure
+		newSlack, err := notify.NewSlack(newSlackConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		newNotifiers = append(newNotifiers, newSlack)
+	}
+	if c.Notifications.GitHub != nil {
+		newGitHubConfig := notify.DefaultGitHubConfig()
+		newGitHubConfig.Repository = c.Notifications.GitHub.Repository
+		newGitHubConfig.Ref = valueOrEnv(c.Notifications.GitHub.Ref, "GITHUB_SHA")
+		newGitHubConfig.Token = valueOrEnv(c.Notifications.GitHub.Token, "GITHUB_TOKEN")
+		if c.Notifications.GitHub.Environment != "" {
+			newGitHubConfig.Environment = c.Notifications.GitHub.Environment
+		}
+		if c.Notifications.GitHub.APIURL != "" {
+			newGitHubConfig.APIURL = c.Notifications.GitHub.APIURL
+		}
+		if len(c.Notifications.GitHub.Operations) > 0 {
+			newGitHubConfig.Operations = c.Notifications.GitHub.Operations
+		}
+		if newGitHubConfig.Token == "" || newGitHubConfig.Ref == "" {
+			// The token and ref are usually only available in CI. Commands
+			// executed elsewhere must not fail because of that.
+			newLogger.Warning(context.Background(), "Not notifying GitHub deployments. Token (GITHUB_TOKEN) or ref (GITHUB_SHA) is missing.")
+		} else {
+			newGitHub, err := notify.NewGitHub(newGitHubConfig)
+			if err != nil {
+				return nil, maskAny(err)
+			}
+			newNotifiers = append(newNotifiers, newGitHub)
+		}
+	}
+
+	return newNotifiers, nil
+}
+
+// valueOrEnv returns the given value, or the value of the given environment
+// variable in case it is empty.
+func valueOrEnv(value, key string) string {
+	if value != "" {
+		return value
+	}
+
+	return os.Getenv(key)
+}
+
+// notifyingRun wraps the given run function of a command, so that the
+// configured notifiers are told when the given operation starts, and whether
+// it succeeded or failed. The operation is reported as well. See reportingRun.
+// Commands fail by exiting using a non-zero code. See exit.
+func notifyingRun(operation string, run func(cmd *cobra.Command, args []string)) func(cmd *cobra.Command, args []string) {
+	run = reportingRun(operation, run)
+
+	return func(cmd *cobra.Command, args []string) {
+		notification = &notify.Message{
+			Operation: operation,
+			Args:      args,
+		}
+		notificationStart = time.Now()
+		sendNotification(notify.PhaseStarted)
+
+		run(cmd, args)
+
+		sendNotification(notify.PhaseSucceeded)
+		notification = nil
+	}
+}
+
+// exit exits using the given code. In case an operation wrapped using
+// notifyingRun is executed, the notifiers are told it failed, or succeeded in
+// case the code is zero, before. Recorded traces are exported and the report
+// of the operation is written as well.
+func exit(code int) {
+	finishReport(newCtx, code)
+	if code == 0 {
+		sendNotification(notify.PhaseSucceeded)
+	} else {
+		sendNotification(notify.PhaseFailed)
+	}
+	finishTracing(code)
+
+	os.Exit(code)
+}
+
+// sendNotification tells all notifiers that the current operation reached the
+// given phase. Notifications must not fail operations, so errors are only
+// logged.
+func sendNotification(phase notify.Phase) {
+	if notification == nil {
+		return
+	}
+
+	m := *notification
+	m.Phase = phase
+	if phase != notify.PhaseStarted {
+		m.Duration = time.Since(notificationStart) / time.Second * time.Second
+	}
+	for _, n := range notifiers {
+		if err := n.Notify(context.Background(), m); err != nil {
+			newLogger.Warning(newCtx, "Failed to send notification '%s'. (%s)", m.String(), err.Error())
+		}
+	}
+}
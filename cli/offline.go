@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/logging"
+)
+
+// isOfflineCommand checks whether the given command works without connecting
+// to fleet, e.g. to validate groups in CI jobs lacking cluster credentials.
+// Plans are only made offline using --offline.
+func isOfflineCommand(cmd *cobra.Command) bool {
+	switch cmd {
+	case validateCmd, initCmd:
+		return true
+	case planCmd:
+		return planFlags.Offline
+	}
+
+	return false
+}
+
+// newOfflineFleet creates the fleet offline commands operate on, i.e. an empty
+// simulated cluster kept in memory. Groups are therefore planned as if they
+// were not deployed yet.
+func newOfflineFleet(logger logging.Logger) (fleet.Fleet, error) {
+	newSimulatorConfig := fleet.DefaultSimulatorConfig()
+	newSimulatorConfig.Logger = logger
+	newSimulator, err := fleet.NewSimulator(newSimulatorConfig)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return newSimulator, nil
+}
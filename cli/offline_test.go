@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/logging"
+)
+
+func Test_Offline_isOfflineCommand(t *testing.T) {
+	RegisterTestingT(t)
+	defer func() { planFlags.Offline = false }()
+
+	Expect(isOfflineCommand(validateCmd)).To(BeTrue())
+	Expect(isOfflineCommand(upCmd)).To(BeFalse())
+
+	Expect(isOfflineCommand(planCmd)).To(BeFalse())
+	planFlags.Offline = true
+	Expect(isOfflineCommand(planCmd)).To(BeTrue())
+}
+
+func Test_Offline_newOfflineFleet(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFleet, err := newOfflineFleet(logging.NewLogger(logging.DefaultConfig()))
+	Expect(err).To(BeNil())
+
+	// The offline cluster is empty.
+	exists, err := newFleet.Exists(context.Background(), "app-main@1.service")
+	Expect(err).To(BeNil())
+	Expect(exists).To(BeFalse())
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	orphansFlags struct {
+		Quiet bool
+	}
+
+	orphansCmd = &cobra.Command{
+		Use:   "orphans",
+		Short: "List orphaned units",
+		Long:  "List the units of the cluster not belonging to any group of the current working directory, nor to any group deployed with labels, e.g. leftovers of failed deploys or manual fleetctl usage. Units not following the naming pattern of groups are listed as well",
+		Run:   orphansRun,
+	}
+)
+
+func init() {
+	orphansCmd.PersistentFlags().BoolVarP(&orphansFlags.Quiet, "quiet", "q", false, "only print unit names")
+}
+
+func orphansRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting orphans")
+
+	if len(args) != 0 {
+		cmd.Help()
+		exit(1)
+	}
+
+	groups, err := localGroups(fs)
+	handleOrphansCmdError(err)
+	orphans, err := newController.OrphanUnits(newCtx, groups)
+	handleOrphansCmdError(err)
+
+	if orphansFlags.Quiet {
+		for _, orphan := range orphans {
+			fmt.Println(orphan.Name)
+		}
+		return
+	}
+	if len(orphans) == 0 {
+		newLogger.Info(newCtx, "No orphaned units found.")
+		return
+	}
+
+	printResult(result{Kind: "orphans", Rows: createOrphanTable(orphans)})
+}
+
+// createOrphanTable creates the table rows listing the given orphaned units.
+// Units not named after a group show a dash as group.
+func createOrphanTable(orphans []controller.OrphanUnit) []string {
+	rows := []string{"Unit | Named After | Desired | Current", ""}
+	for _, orphan := range orphans {
+		group := orphan.Group
+		if group == "" {
+			group = "-"
+		}
+		rows = append(rows, fmt.Sprintf("%s | %s | %s | %s", orphan.Name, group, orphan.Desired, orphan.Current))
+	}
+
+	return rows
+}
+
+func handleOrphansCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_Orphans_createOrphanTable(t *testing.T) {
+	orphans := []controller.OrphanUnit{
+		{UnitStatus: fleet.UnitStatus{Name: "etcd.service", Desired: "launched", Current: "launched"}},
+		{UnitStatus: fleet.UnitStatus{Name: "old-main@1.service", Desired: "launched", Current: "loaded"}, Group: "old"},
+	}
+
+	expected := []string{
+		"Unit | Named After | Desired | Current",
+		"",
+		"etcd.service | - | launched | launched",
+		"old-main@1.service | old | launched | loaded",
+	}
+	if rows := createOrphanTable(orphans); !reflect.DeepEqual(rows, expected) {
+		t.Fatal("expected", expected, "got", rows)
+	}
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+)
+
+// outputGoTemplate is the prefix of --output values giving a Go template.
+const outputGoTemplate = "go-template="
+
+var (
+	outputFlags struct {
+		Output string
+	}
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{statusCmd, listCmd, endpointsCmd} {
+		cmd.PersistentFlags().StringVarP(&outputFlags.Output, "output", "o", "", "print using a Go template instead of a table, e.g. go-template='{{range .Slices}}{{.ID}}{{\"\\n\"}}{{end}}'")
+	}
+}
+
+// parseOutput parses the value of --output. A nil template is returned in
+// case the default table output is asked for. In case the value is malformed,
+// an error that you can identify using IsInvalidArgumentsError is returned.
+//
+//   go-template={{range .Slices}}{{.ID}} {{.State}}{{"\n"}}{{end}}
+//
+func parseOutput(output string) (*template.Template, error) {
+	if output == "" {
+		return nil, nil
+	}
+	if structuredFormat() {
+		return nil, maskAnyf(invalidArgumentsError, "--output cannot be combined with --format %s", globalFlags.Format)
+	}
+	if !strings.HasPrefix(output, outputGoTemplate) {
+		return nil, maskAnyf(invalidArgumentsError, "--output must have the form go-template=<template>, got '%s'", output)
+	}
+
+	tmpl, err := template.New("output").Parse(strings.TrimPrefix(output, outputGoTemplate))
+	if err != nil {
+		return nil, maskAnyf(invalidArgumentsError, "--output: %s", err.Error())
+	}
+
+	return tmpl, nil
+}
+
+// executeOutput executes the given template of --output on the given data.
+func executeOutput(w io.Writer, tmpl *template.Template, data interface{}) error {
+	if err := tmpl.Execute(w, data); err != nil {
+		return maskAnyf(invalidArgumentsError, "--output: %s", err.Error())
+	}
+
+	return nil
+}
+
+// statusOutput is the data templates given to status using --output are
+// executed on, once per group. Annotations are the ones of the group itself,
+// see controller.Controller.AnnotateSlices.
+type statusOutput struct {
+	Group       string
+	Annotations map[string]string
+	Slices      []sliceOutput
+}
+
+// sliceOutput describes a slice of a group. State is the phase of the slice,
+// e.g. "active" or "failed", which is the one of its least progressed unit.
+// Flapping is true in case the slice restarts too often, see
+// controller.Controller.CheckFlapping.
+type sliceOutput struct {
+	ID          string
+	State       string
+	Flapping    bool
+	Annotations map[string]string
+	Units       []unitOutput
+}
+
+type unitOutput struct {
+	Name     string
+	Desired  string
+	Current  string
+	Machines []machineOutput
+}
+
+type machineOutput struct {
+	ID        string
+	IP        string
+	Load      string
+	LoadError string
+	Active    string
+	Sub       string
+	Hash      string
+}
+
+// newStatusOutput creates the data --output templates of status are executed
+// on from the given unit states. Slices are sorted by ID.
+func newStatusOutput(group string, usl []fleet.UnitStatus) statusOutput {
+	out := statusOutput{Group: group}
+	for _, sp := range slicePhases(usl) {
+		slice := sliceOutput{ID: sp.SliceID, State: string(sp.Phase)}
+		for _, us := range usl {
+			if us.SliceID != sp.SliceID {
+				continue
+			}
+			unit := unitOutput{Name: us.Name, Desired: us.Desired, Current: us.Current}
+			for _, ms := range us.Machine {
+				machine := machineOutput{
+					ID:        ms.ID,
+					Load:      ms.SystemdLoad,
+					LoadError: ms.LoadError(),
+					Active:    ms.SystemdActive,
+					Sub:       ms.SystemdSub,
+					Hash:      ms.UnitHash,
+				}
+				if ms.IP != nil {
+					machine.IP = ms.IP.String()
+				}
+				unit.Machines = append(unit.Machines, machine)
+			}
+			slice.Units = append(slice.Units, unit)
+		}
+		sort.Sort(unitOutputsByName(slice.Units))
+		out.Slices = append(out.Slices, slice)
+	}
+
+	return out
+}
+
+// markFlapping marks the slices of the given output contained in the given
+// flapping slices as flapping.
+func markFlapping(out *statusOutput, flapping map[string]controller.FlappingSlice) {
+	for i := range out.Slices {
+		_, out.Slices[i].Flapping = flapping[out.Slices[i].ID]
+	}
+}
+
+// markAnnotations adds the given annotations to the group and slices of the
+// given output.
+func markAnnotations(out *statusOutput, annotations []controller.SliceAnnotation) {
+	add := func(m *map[string]string, a controller.SliceAnnotation) {
+		if *m == nil {
+			*m = map[string]string{}
+		}
+		(*m)[a.Key] = a.Value
+	}
+	for _, a := range annotations {
+		if a.SliceID == "" {
+			add(&out.Annotations, a)
+			continue
+		}
+		for i := range out.Slices {
+			if out.Slices[i].ID == a.SliceID {
+				add(&out.Slices[i].Annotations, a)
+			}
+		}
+	}
+}
+
+type unitOutputsByName []unitOutput
+
+func (u unitOutputsByName) Len() int           { return len(u) }
+func (u unitOutputsByName) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u unitOutputsByName) Less(i, j int) bool { return u[i].Name < u[j].Name }
+
+// listOutput is the data templates given to list using --output are executed
+// on.
+type listOutput struct {
+	Groups []groupOutput
+}
+
+// groupOutput describes a listed group. Deployed is always false using
+// --local.
+type groupOutput struct {
+	Name        string
+	Deployed    bool
+	Labels      map[string]string
+	Description string
+	Owner       string
+	Contact     string
+}
+
+// newGroupOutput creates the data describing the given group in templates
+// of list.
+func newGroupOutput(lg labeledGroup, metadata groupMetadata) groupOutput {
+	labels := lg.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	return groupOutput{
+		Name:        lg.Group,
+		Deployed:    lg.Deployed,
+		Labels:      labels,
+		Description: metadata.Description,
+		Owner:       metadata.Owner,
+		Contact:     metadata.Contact,
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_Output_parseOutput(t *testing.T) {
+	RegisterTestingT(t)
+
+	tmpl, err := parseOutput("")
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(tmpl).To(BeNil())
+
+	_, err = parseOutput("json")
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+
+	_, err = parseOutput("go-template={{range .Slices}")
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+
+	tmpl, err = parseOutput(`go-template={{.Group}}`)
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(tmpl).To(Not(BeNil()))
+}
+
+func Test_Output_status(t *testing.T) {
+	RegisterTestingT(t)
+
+	usl := []fleet.UnitStatus{
+		{
+			Name:    "foo-main@2.service",
+			SliceID: "2",
+			Desired: "launched",
+			Current: "launched",
+			Machine: []fleet.MachineStatus{
+				{ID: "abc", IP: net.ParseIP("10.0.0.1"), SystemdActive: "failed", SystemdSub: "failed"},
+			},
+		},
+		{
+			Name:    "foo-main@1.service",
+			SliceID: "1",
+			Desired: "launched",
+			Current: "launched",
+			Machine: []fleet.MachineStatus{
+				{ID: "def", IP: net.ParseIP("10.0.0.2"), SystemdActive: "active", SystemdSub: "running"},
+			},
+		},
+	}
+
+	tmpl, err := parseOutput(`go-template={{range .Slices}}{{.ID}} {{.State}}{{range .Units}}{{range .Machines}} {{.IP}}{{end}}{{end}}{{"\n"}}{{end}}`)
+	Expect(err).To(Not(HaveOccurred()))
+
+	var out bytes.Buffer
+	err = executeOutput(&out, tmpl, newStatusOutput("foo", usl))
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(out.String()).To(Equal("1 active 10.0.0.2\n2 failed 10.0.0.1\n"))
+
+	statusOutput := newStatusOutput("foo", usl)
+	markFlapping(&statusOutput, map[string]controller.FlappingSlice{"2": {SliceID: "2", Restarts: 4}})
+	tmpl, err = parseOutput(`go-template={{range .Slices}}{{if .Flapping}}{{.ID}}{{end}}{{end}}`)
+	Expect(err).To(Not(HaveOccurred()))
+	out.Reset()
+	err = executeOutput(&out, tmpl, statusOutput)
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(out.String()).To(Equal("2"))
+	Expect(createFlappingSummary("foo", map[string]controller.FlappingSlice{"2": {SliceID: "2", Restarts: 4}})[2]).To(HavePrefix("foo@2 | 4 | "))
+
+	tmpl, err = parseOutput(`go-template={{.Unknown}}`)
+	Expect(err).To(Not(HaveOccurred()))
+	err = executeOutput(&out, tmpl, newStatusOutput("foo", usl))
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+}
+
+func Test_Output_list(t *testing.T) {
+	RegisterTestingT(t)
+
+	out := listOutput{
+		Groups: []groupOutput{
+			newGroupOutput(labeledGroup{Group: "foo", Deployed: true, Labels: map[string]string{"team": "payments"}}, groupMetadata{Owner: "alice"}),
+			newGroupOutput(labeledGroup{Group: "bar"}, groupMetadata{}),
+		},
+	}
+
+	tmpl, err := parseOutput(`go-template={{range .Groups}}{{.Name}} {{.Deployed}} {{index .Labels "team"}} {{.Owner}}{{"\n"}}{{end}}`)
+	Expect(err).To(Not(HaveOccurred()))
+
+	var buf bytes.Buffer
+	err = executeOutput(&buf, tmpl, out)
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(buf.String()).To(Equal("foo true payments alice\nbar false  \n"))
+}
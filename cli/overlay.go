@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/file-system/spec"
+)
+
+// groupOverlaysDir is the directory within a group directory containing the
+// overlays of the group, one directory per environment.
+//
+//   mygroup/
+//     mygroup-main@.service
+//     mygroup-main@.env
+//     overlays/
+//       staging/
+//         mygroup-main@.service
+//         mygroup-main@.env
+//
+const groupOverlaysDir = "overlays"
+
+// overlayDir returns the directory of the overlay of the given group for the
+// given environment.
+func overlayDir(group, env string) string {
+	return filepath.Join(group, groupOverlaysDir, env)
+}
+
+// readOverlayFiles reads the unit files of the overlay of the given group for
+// the given environment and returns a map of filename => filecontent. The
+// returned bool is false in case the group has no overlay for the environment.
+func readOverlayFiles(fs filesystemspec.FileSystem, group, env string) (map[string]string, bool, error) {
+	envs, err := overlayEnvs(fs, group)
+	if err != nil {
+		return nil, false, maskAny(err)
+	}
+	if !containsString(envs, env) {
+		return nil, false, nil
+	}
+
+	dir := overlayDir(group, env)
+	fileInfos, err := fs.ReadDir(dir)
+	if err != nil {
+		return nil, false, maskAny(err)
+	}
+
+	overlayFiles := map[string]string{}
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() || !strings.HasPrefix(fileInfo.Name(), group) {
+			continue
+		}
+
+		raw, err := fs.ReadFile(filepath.Join(dir, fileInfo.Name()))
+		if err != nil {
+			return nil, false, maskAny(err)
+		}
+
+		overlayFiles[fileInfo.Name()] = string(raw)
+	}
+
+	return overlayFiles, true, nil
+}
+
+// overlayEnvs returns the sorted environments the given group has overlays
+// for.
+func overlayEnvs(fs filesystemspec.FileSystem, group string) ([]string, error) {
+	fileInfos, err := fs.ReadDir(group)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	found := false
+	for _, fileInfo := range fileInfos {
+		if fileInfo.Name() == groupOverlaysDir && fileInfo.IsDir() {
+			found = true
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	fileInfos, err = fs.ReadDir(filepath.Join(group, groupOverlaysDir))
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	var envs []string
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() {
+			envs = append(envs, fileInfo.Name())
+		}
+	}
+	sort.Strings(envs)
+
+	return envs, nil
+}
+
+// applyOverlay merges the overlay of the given group for the environment given
+// using --env over the given unit files of the group. Unit files only present
+// in the overlay are added. Unit files present in both are merged using
+// mergeUnitFile, environment file templates using mergeEnvFile. Drop-ins of
+// the overlay are applied to the merged unit files afterwards. Without --env,
+// or in case the group has no overlay for the environment, the unit files are
+// returned as they are.
+func applyOverlay(fs filesystemspec.FileSystem, group string, unitFiles map[string]string) (map[string]string, error) {
+	if globalFlags.Env == "" {
+		return unitFiles, nil
+	}
+
+	overlayFiles, ok, err := readOverlayFiles(fs, group, globalFlags.Env)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if !ok {
+		newLogger.Warning(newCtx, "Group '%s' has no overlay for environment '%s'. Using its base definition.", group, globalFlags.Env)
+		return unitFiles, nil
+	}
+
+	merged := map[string]string{}
+	for name, content := range unitFiles {
+		merged[name] = content
+	}
+	for name, content := range overlayFiles {
+		base, ok := merged[name]
+		switch {
+		case !ok:
+			merged[name] = content
+		case common.IsEnvFile(name):
+			merged[name] = mergeEnvFile(base, content)
+		default:
+			merged[name] = mergeUnitFile(base, content)
+		}
+	}
+
+	merged, err = applyDropIns(fs, overlayDir(group, globalFlags.Env), group, merged)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return merged, nil
+}
+
+// unitFileLine is a logical line of a unit file, i.e. a line including its
+// continuation lines. Key is the option name in case the line sets an option.
+type unitFileLine struct {
+	Section string
+	Key     string
+	Text    string
+}
+
+// parseUnitFileLines splits the given unit file content into logical lines,
+// keeping comments and blank lines.
+func parseUnitFileLines(content string) []unitFileLine {
+	var lines []unitFileLine
+	section := ""
+	continued := false
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		if continued {
+			lines[len(lines)-1].Text += "\n" + line
+			continued = strings.HasSuffix(line, "\\")
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		l := unitFileLine{Text: line}
+		switch {
+		case strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+			section = trimmed[1 : len(trimmed)-1]
+		case trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";"):
+		case strings.Contains(trimmed, "="):
+			l.Key = strings.TrimSpace(trimmed[:strings.Index(trimmed, "=")])
+			continued = strings.HasSuffix(line, "\\")
+		}
+		l.Section = section
+		lines = append(lines, l)
+	}
+
+	return lines
+}
+
+// mergeUnitFile merges the given overlay unit file over the given base unit
+// file. Options set by the overlay replace all options of the same name within
+// the same section of the base, and are appended to that section. Sections
+// only present in the overlay are appended.
+//
+//   [Service]                  [Service]             [Service]
+//   ExecStart=/bin/app    +    Environment=ENV=stg = ExecStart=/bin/app
+//   Environment=ENV=prod                             Environment=ENV=stg
+//
+func mergeUnitFile(base, overlay string) string {
+	return mergeUnitFileOptions(base, overlay, true)
+}
+
+// mergeUnitFileOptions merges the options of the given overlay unit file into
+// the sections of the given base unit file, see mergeUnitFile. In case replace
+// is false, options of the base are kept, see appendDropIn.
+func mergeUnitFileOptions(base, overlay string, replace bool) string {
+	overlayLines := parseUnitFileLines(overlay)
+	overridden := map[string]map[string]bool{}
+	var sections []string
+	options := map[string][]string{}
+	for _, l := range overlayLines {
+		// Options outside of any section are not valid and ignored.
+		if l.Key == "" || l.Section == "" {
+			continue
+		}
+		if _, ok := overridden[l.Section]; !ok {
+			overridden[l.Section] = map[string]bool{}
+			sections = append(sections, l.Section)
+		}
+		overridden[l.Section][l.Key] = true
+		options[l.Section] = append(options[l.Section], l.Text)
+	}
+
+	var merged []string
+	seen := map[string]bool{}
+	flush := func(section string) {
+		if seen[section] {
+			return
+		}
+		seen[section] = true
+		// Keep blank lines separating sections after the appended options.
+		var blank []string
+		for len(merged) > 0 && strings.TrimSpace(merged[len(merged)-1]) == "" {
+			blank = append(blank, merged[len(merged)-1])
+			merged = merged[:len(merged)-1]
+		}
+		merged = append(merged, options[section]...)
+		merged = append(merged, blank...)
+	}
+
+	baseLines := parseUnitFileLines(base)
+	for i, l := range baseLines {
+		if i > 0 && l.Section != baseLines[i-1].Section {
+			flush(baseLines[i-1].Section)
+		}
+		if replace && l.Key != "" && overridden[l.Section][l.Key] {
+			continue
+		}
+		merged = append(merged, l.Text)
+	}
+	if len(baseLines) > 0 {
+		flush(baseLines[len(baseLines)-1].Section)
+	}
+
+	for _, section := range sections {
+		if seen[section] {
+			continue
+		}
+		if len(merged) > 0 {
+			merged = append(merged, "")
+		}
+		merged = append(merged, "["+section+"]")
+		merged = append(merged, options[section]...)
+	}
+
+	return strings.Join(merged, "\n") + "\n"
+}
+
+// mergeEnvFile merges the given overlay environment file template over the
+// given base environment file template. Variables set by the overlay replace
+// the ones of the base in place. Variables only set by the overlay are
+// appended.
+//
+//   ENV=prod           ENV=staging        ENV=staging
+//   LOG_LEVEL=info  +  DEBUG=true      =  LOG_LEVEL=info
+//                                         DEBUG=true
+//
+func mergeEnvFile(base, overlay string) string {
+	var keys []string
+	values := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSuffix(overlay, "\n"), "\n") {
+		key, ok := envFileKey(line)
+		if !ok {
+			continue
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = line
+	}
+
+	var merged []string
+	replaced := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSuffix(base, "\n"), "\n") {
+		key, ok := envFileKey(line)
+		if ok {
+			if _, overridden := values[key]; overridden {
+				if !replaced[key] {
+					merged = append(merged, values[key])
+					replaced[key] = true
+				}
+				continue
+			}
+		}
+		merged = append(merged, line)
+	}
+	for _, key := range keys {
+		if !replaced[key] {
+			merged = append(merged, values[key])
+		}
+	}
+
+	return strings.Join(merged, "\n") + "\n"
+}
+
+// envFileKey returns the name of the variable set by the given line of an
+// environment file. The returned bool is false in case the line does not set
+// a variable, e.g. because it is a comment.
+func envFileKey(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return "", false
+	}
+	i := strings.Index(trimmed, "=")
+	if i <= 0 {
+		return "", false
+	}
+
+	return strings.TrimPrefix(strings.TrimSpace(trimmed[:i]), "export "), true
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/fake"
+	"github.com/giantswarm/inago/logging"
+)
+
+func Test_Overlay_mergeUnitFile(t *testing.T) {
+	RegisterTestingT(t)
+
+	base := "[Unit]\n" +
+		"Description=My App\n" +
+		"\n" +
+		"[Service]\n" +
+		"Environment=ENV=prod\n" +
+		"Environment=LOG_LEVEL=info\n" +
+		"ExecStart=/bin/app \\\n" +
+		"  --port 80\n"
+	overlay := "[Service]\n" +
+		"Environment=ENV=staging\n" +
+		"ExecStart=/bin/app --port 8080\n" +
+		"\n" +
+		"[X-Fleet]\n" +
+		"MachineMetadata=env=staging\n"
+
+	Expect(mergeUnitFile(base, overlay)).To(Equal("[Unit]\n" +
+		"Description=My App\n" +
+		"\n" +
+		"[Service]\n" +
+		"Environment=ENV=staging\n" +
+		"ExecStart=/bin/app --port 8080\n" +
+		"\n" +
+		"[X-Fleet]\n" +
+		"MachineMetadata=env=staging\n"))
+
+	// Sections of the base are kept in order, including blank lines.
+	Expect(mergeUnitFile("[Service]\nExecStart=/bin/app\n\n[Install]\nWantedBy=multi-user.target\n", "[Service]\nRestart=always\n")).To(Equal(
+		"[Service]\nExecStart=/bin/app\nRestart=always\n\n[Install]\nWantedBy=multi-user.target\n",
+	))
+}
+
+func Test_Overlay_mergeEnvFile(t *testing.T) {
+	RegisterTestingT(t)
+
+	base := "# Settings of {{.Group}}\n" +
+		"ENV=prod\n" +
+		"LOG_LEVEL=info\n"
+	overlay := "ENV=staging\n" +
+		"DEBUG=true\n"
+
+	Expect(mergeEnvFile(base, overlay)).To(Equal("# Settings of {{.Group}}\n" +
+		"ENV=staging\n" +
+		"LOG_LEVEL=info\n" +
+		"DEBUG=true\n"))
+}
+
+func Test_Overlay_extendRequestWithContent(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	files := map[string]string{
+		"mygroup/mygroup-main@.service":                  "[Service]\nExecStart=/bin/app\n",
+		"mygroup/mygroup-main@.env":                      "ENV=prod\n",
+		"mygroup/overlays/staging/mygroup-main@.service": "[Service]\nRestart=always\n",
+		"mygroup/overlays/staging/mygroup-main@.env":     "ENV=staging\n",
+		"mygroup/overlays/staging/mygroup-debug.service": "[Service]\nExecStart=/bin/debug\n",
+	}
+	for name, content := range files {
+		err := newFileSystem.WriteFile(name, []byte(content), os.FileMode(0644))
+		Expect(err).To(BeNil())
+	}
+
+	envs, err := overlayEnvs(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(envs).To(Equal([]string{"staging"}))
+
+	unitContents := func() map[string]string {
+		newRequestConfig := controller.DefaultRequestConfig()
+		newRequestConfig.Group = "mygroup"
+		req, err := extendRequestWithContent(newFileSystem, controller.NewRequest(newRequestConfig))
+		Expect(err).To(BeNil())
+
+		contents := map[string]string{}
+		for _, u := range req.Units {
+			content := u.Content
+			if u.EnvFile != "" {
+				content = u.EnvTemplate
+			}
+			contents[u.Name] = content
+		}
+		return contents
+	}
+
+	Expect(unitContents()).To(Equal(map[string]string{
+		"mygroup-main@.service":     "[Service]\nExecStart=/bin/app\n",
+		"mygroup-main-env@.service": "ENV=prod\n",
+	}))
+
+	globalFlags.Env = "staging"
+	defer func() { globalFlags.Env = "" }()
+	Expect(unitContents()).To(Equal(map[string]string{
+		"mygroup-main@.service":     "[Service]\nExecStart=/bin/app\nRestart=always\n",
+		"mygroup-main-env@.service": "ENV=staging\n",
+		"mygroup-debug.service":     "[Service]\nExecStart=/bin/debug\n",
+	}))
+
+	// Groups without overlay for the environment use their base definition.
+	newLogger = logging.NewLogger(logging.DefaultConfig())
+	globalFlags.Env = "production"
+	Expect(unitContents()).To(HaveLen(2))
+
+	// Signatures cover the files of all overlays.
+	definitionFiles, err := groupDefinitionFiles(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(definitionFiles).To(HaveKey("overlays/staging/mygroup-debug.service"))
+	Expect(definitionFiles).To(HaveLen(5))
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	unpinFlags struct {
+		Sync bool
+	}
+
+	pinCmd = &cobra.Command{
+		Use:   "pin <group[@slice]...>",
+		Short: "Pin slices of a group",
+		Long:  "Pin the given slices, so that updates and scaling down skip them, e.g. to keep an instance around while debugging it. Without slices, the pinned slices of the group are printed",
+		Run:   pinRun,
+	}
+
+	unpinCmd = &cobra.Command{
+		Use:   "unpin <group@slice...>",
+		Short: "Unpin slices of a group",
+		Long:  "Release the given slices pinned using pin. Using --sync, the released slices are updated to the version of the local filesystem",
+		Run:   reportingRun("unpin", unpinRun),
+	}
+)
+
+func init() {
+	unpinCmd.PersistentFlags().BoolVar(&unpinFlags.Sync, "sync", false, "update the released slices to the version of the local filesystem")
+}
+
+func pinRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting pin")
+
+	if len(args) == 0 {
+		cmd.Help()
+		exit(1)
+	}
+	group, sliceIDs, err := parseGroupCLIArgs(args)
+	handlePinCmdError(err)
+
+	if len(sliceIDs) == 0 {
+		pinned, err := newController.PinnedSlices(newCtx, group)
+		handlePinCmdError(err)
+		for _, sliceID := range pinned {
+			fmt.Printf("%s@%s\n", group, sliceID)
+		}
+		return
+	}
+
+	existing, err := existingSliceIDs(group)
+	handlePinCmdError(err)
+	for _, sliceID := range sliceIDs {
+		if !containsString(existing, sliceID) {
+			handlePinCmdError(maskAnyf(invalidArgumentsError, "slice '%s@%s' does not exist", group, sliceID))
+		}
+	}
+
+	err = newController.PinSlices(newCtx, group, sliceIDs)
+	handlePinCmdError(err)
+	newLogger.Info(newCtx, "Pinned slices %s of group '%s'.", strings.Join(sliceIDs, ", "), group)
+}
+
+func unpinRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting unpin")
+
+	if len(args) == 0 {
+		cmd.Help()
+		exit(1)
+	}
+	group, sliceIDs, err := parseGroupCLIArgs(args)
+	handlePinCmdError(err)
+	if len(sliceIDs) == 0 {
+		handlePinCmdError(maskAnyf(invalidArgumentsError, "no slices given to unpin"))
+	}
+
+	err = newController.UnpinSlices(newCtx, group, sliceIDs)
+	handlePinCmdError(err)
+	newLogger.Info(newCtx, "Unpinned slices %s of group '%s'.", strings.Join(sliceIDs, ", "), group)
+
+	if !unpinFlags.Sync {
+		return
+	}
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	req := withRetry(controller.NewRequest(newRequestConfig))
+	req, err = extendRequestWithContent(fs, req)
+	handlePinCmdError(err)
+	err = lintRequest(req)
+	handlePinCmdError(err)
+	req.SliceIDs = sliceIDs
+
+	// The released slices are replaced one after another, as they are running
+	// outdated units anyway.
+	opts := controller.UpdateOptions{
+		MaxGrowth: 1,
+		MinAlive:  0,
+	}
+	taskObject, err := newController.Update(newCtx, req, opts)
+	handlePinCmdError(err)
+	maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+		Request:    req,
+		Descriptor: "update",
+		NoBlock:    globalFlags.NoBlock,
+		TaskID:     taskObject.ID,
+	})
+}
+
+func handlePinCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	placementFlags struct {
+		Placement []string
+	}
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{submitCmd, upCmd, scaleCmd, deployCmd, cloneCmd} {
+		cmd.PersistentFlags().StringSliceVar(&placementFlags.Placement, "placement", nil, "schedule submitted units only on machines having the given metadata, e.g. role=canary")
+	}
+}
+
+// withPlacementFlags adds the placement hints given using --placement to the
+// given request.
+func withPlacementFlags(req controller.Request) (controller.Request, error) {
+	placement, err := controller.ParsePlacement(placementFlags.Placement)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+	req.Placement = placement
+
+	return req, nil
+}
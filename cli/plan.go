@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	planFlags struct {
+		MaxGrowth     int
+		MinAlive      int
+		ReadySecs     int
+		MaxPerMachine int
+		Offline       bool
+	}
+
+	planCmd = &cobra.Command{
+		Use:   "plan <group> [scale]",
+		Short: "Show the changes needed to deploy a group",
+		Long:  "Show which slices of the group on the local filesystem would be created, updated, left alone or destroyed, without changing anything. Using a structured --format, the plan can be reviewed by other tools before deploying. Using scale, slices are planned to be created or destroyed until the group runs that many slices. Using --offline, the group is planned without connecting to fleet, as if it was not deployed",
+		Run:   planRun,
+	}
+)
+
+func init() {
+	planCmd.PersistentFlags().IntVar(&planFlags.MaxGrowth, "max-growth", 1, "maximum number of group slices added at a time")
+	planCmd.PersistentFlags().IntVar(&planFlags.MinAlive, "min-alive", 1, "minimum number of group slices staying alive at a time")
+	planCmd.PersistentFlags().IntVar(&planFlags.ReadySecs, "ready-secs", 30, "number of seconds to sleep before updating the next group slice")
+	planCmd.PersistentFlags().IntVar(&planFlags.MaxPerMachine, "max-per-machine", 1, "maximum number of group slices replaced at a time on a single machine (0 disables the limit)")
+	planCmd.PersistentFlags().BoolVar(&planFlags.Offline, "offline", false, "plan without connecting to fleet, as if the group was not deployed, e.g. to check groups in CI")
+}
+
+func planRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting plan")
+
+	var scale int
+	switch len(args) {
+	case 1:
+	case 2:
+		var err error
+		scale, err = strconv.Atoi(args[1])
+		if err != nil || scale < 1 {
+			handlePlanCmdError(maskAnyf(invalidArgumentsError, "scale must be a positive number, got '%s'", args[1]))
+		}
+	default:
+		cmd.Help()
+		exit(1)
+	}
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = resolveGroup(args[0])
+	req := controller.NewRequest(newRequestConfig)
+	req, err := extendRequestWithContent(fs, req)
+	handlePlanCmdError(err)
+
+	opts := controller.UpdateOptions{
+		MaxGrowth:     planFlags.MaxGrowth,
+		MinAlive:      planFlags.MinAlive,
+		ReadySecs:     planFlags.ReadySecs,
+		MaxPerMachine: planFlags.MaxPerMachine,
+	}
+	plan, err := newController.Plan(newCtx, req, scale, opts)
+	handlePlanCmdError(err)
+
+	printResult(result{Kind: "plan", Rows: createPlanSummary(plan), Data: plan})
+
+	var changed []string
+	for _, change := range plan.Changes {
+		changed = append(changed, change.Name)
+	}
+	if len(changed) > 0 {
+		newLogger.Info(newCtx, "Unit files changed: %s.", strings.Join(changed, ", "))
+	}
+}
+
+// createPlanSummary renders the steps of the given plan as table rows, in the
+// order they are applied. Slices to be created are shown without slice ID,
+// since it is generated when submitting them. Machine IDs are shortened.
+//
+//   Slice | Action | Reason | Machines
+//
+//   myapp@2 | update | units changed | 505e0d78
+//   myapp@1 | no-op | pinned | 9ebb53b0
+//   myapp | create | scaled up | -
+//
+func createPlanSummary(plan controller.Plan) []string {
+	rows := []string{"Slice | Action | Reason | Machines", ""}
+	for _, step := range plan.Steps {
+		machines := "-"
+		if len(step.Machines) > 0 {
+			var short []string
+			for _, machine := range step.Machines {
+				if len(machine) > 8 {
+					machine = machine[:8]
+				}
+				short = append(short, machine)
+			}
+			machines = strings.Join(short, ",")
+		}
+		name := plan.Group
+		if step.SliceID != "" {
+			name += "@" + step.SliceID
+		}
+		rows = append(rows, fmt.Sprintf("%s | %s | %s | %s", name, step.Action, step.Reason, machines))
+	}
+
+	return rows
+}
+
+func handlePlanCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+func Test_Plan_createPlanSummary(t *testing.T) {
+	RegisterTestingT(t)
+
+	rows := createPlanSummary(controller.Plan{
+		Group: "myapp",
+		Steps: []controller.PlanStep{
+			{SliceID: "3", Action: controller.PlanDestroy, Reason: "scaled down"},
+			{Action: controller.PlanCreate, Reason: "scaled up"},
+			{SliceID: "2", Action: controller.PlanUpdate, Reason: "units changed", Machines: []string{"505e0d7802d7439a924c269b76f34b5f"}},
+			{SliceID: "1", Action: controller.PlanNoOp, Reason: "pinned", Machines: []string{"9ebb53b0", "505e0d78"}},
+		},
+	})
+	Expect(rows).To(Equal([]string{
+		"Slice | Action | Reason | Machines",
+		"",
+		"myapp@3 | destroy | scaled down | -",
+		"myapp | create | scaled up | -",
+		"myapp@2 | update | units changed | 505e0d78",
+		"myapp@1 | no-op | pinned | 9ebb53b0,505e0d78",
+	}))
+}
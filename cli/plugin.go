@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+// pluginPrefix is the prefix of executables extending inagoctl. Running
+// "inagoctl foo" executes "inagoctl-foo" in case there is no command "foo".
+const pluginPrefix = "inagoctl-"
+
+// plugin describes the execution of a plugin.
+type plugin struct {
+	// Path is the path of the plugin's executable.
+	Path string
+
+	// Globals are the global flags given in front of the plugin's name.
+	Globals []string
+
+	// Args are the arguments given after the plugin's name.
+	Args []string
+}
+
+// ExecutePlugin executes the plugin the given command line arguments refer
+// to, and exits using its exit code. The configuration of inagoctl, like the
+// fleet endpoint, is passed to the plugin using the environment variables
+// described by envName. In case the arguments refer to a command of inagoctl,
+// or there is no plugin, nothing happens.
+func ExecutePlugin(args []string) {
+	p, ok := findPlugin(MainCmd, args, exec.LookPath)
+	if !ok {
+		return
+	}
+
+	env, err := pluginEnv(MainCmd.PersistentFlags(), p.Globals, os.LookupEnv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to execute plugin '%s'. (%s)\n", p.Path, err.Error())
+		os.Exit(1)
+	}
+
+	cmd := exec.Command(p.Path, p.Args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			os.Exit(status.ExitStatus())
+		}
+		os.Exit(1)
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to execute plugin '%s'. (%s)\n", p.Path, err.Error())
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+// findPlugin checks whether the given command line arguments refer to a
+// plugin. Global flags may be given in front of the plugin's name. The given
+// lookPath function is usually exec.LookPath.
+//
+//   inagoctl --tunnel host.example.com backup myapp --full
+//
+//   plugin:   inagoctl-backup
+//   globals:  --tunnel host.example.com
+//   args:     myapp --full
+//
+func findPlugin(root *cobra.Command, args []string, lookPath func(string) (string, error)) (plugin, bool) {
+	flags := root.PersistentFlags()
+
+	var i int
+	for i = 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			break
+		}
+		if arg == "--" || strings.Contains(arg, "=") {
+			continue
+		}
+
+		var f *pflag.Flag
+		if strings.HasPrefix(arg, "--") {
+			f = flags.Lookup(arg[2:])
+		} else {
+			f = lookupShorthand(flags, arg[len(arg)-1:])
+		}
+		if f == nil {
+			// Unknown flags are left to cobra to complain about.
+			return plugin{}, false
+		}
+		if f.NoOptDefVal == "" {
+			// The flag takes a value, which is the next argument.
+			i++
+		}
+	}
+	if i >= len(args) {
+		return plugin{}, false
+	}
+
+	name := args[i]
+	if name == "help" || strings.ContainsAny(name, "/\\") {
+		return plugin{}, false
+	}
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return plugin{}, false
+		}
+	}
+
+	path, err := lookPath(pluginPrefix + name)
+	if err != nil {
+		return plugin{}, false
+	}
+
+	p := plugin{
+		Path:    path,
+		Globals: args[:i],
+		Args:    args[i+1:],
+	}
+
+	return p, true
+}
+
+// lookupShorthand returns the flag of the given flag set having the given
+// shorthand, or nil in case there is none.
+func lookupShorthand(flags *pflag.FlagSet, shorthand string) *pflag.Flag {
+	var found *pflag.Flag
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Shorthand == shorthand {
+			found = f
+		}
+	})
+
+	return found
+}
+
+// pluginEnv returns the environment variables passing the configuration of
+// inagoctl to a plugin. The given global flags are parsed. All global flags
+// are passed, using the names described by envName, so that a plugin running
+// inagoctl itself uses the same configuration. Flags not given are taken from
+// the environment the same way inagoctl does. See bindEnvironment.
+func pluginEnv(flags *pflag.FlagSet, globals []string, lookup func(string) (string, bool)) ([]string, error) {
+	if err := flags.Parse(globals); err != nil {
+		return nil, maskAny(err)
+	}
+	if err := bindEnvironment(flags, lookup); err != nil {
+		return nil, maskAny(err)
+	}
+
+	// Plugins are meant to connect to the same fleet as inagoctl does.
+	if f := flags.Lookup("fleet-endpoint"); f != nil && !f.Changed && flags.Lookup("tunnel").Value.String() == "" {
+		if socketPath := fleet.DetectSocket(); socketPath != "" {
+			flags.Set("fleet-endpoint", "unix://"+socketPath)
+		}
+	}
+
+	var env []string
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Name == "help" {
+			return
+		}
+		env = append(env, envName(f.Name)+"="+f.Value.String())
+	})
+
+	return env, nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/juju/errgo"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func givenPluginRoot() *cobra.Command {
+	root := &cobra.Command{Use: "inagoctl"}
+	root.PersistentFlags().String("tunnel", "", "")
+	root.PersistentFlags().BoolP("verbose", "v", false, "")
+	root.AddCommand(&cobra.Command{Use: "status", Run: func(*cobra.Command, []string) {}})
+
+	return root
+}
+
+func Test_Plugin_findPlugin(t *testing.T) {
+	lookPath := func(name string) (string, error) {
+		if name == "inagoctl-backup" {
+			return "/usr/local/bin/inagoctl-backup", nil
+		}
+		return "", errgo.New("not found")
+	}
+
+	testCases := []struct {
+		Args     []string
+		Expected plugin
+		Found    bool
+	}{
+		{
+			Args:     []string{"backup", "myapp", "--full"},
+			Expected: plugin{Path: "/usr/local/bin/inagoctl-backup", Globals: []string{}, Args: []string{"myapp", "--full"}},
+			Found:    true,
+		},
+		{
+			Args:     []string{"--tunnel", "host", "-v", "backup"},
+			Expected: plugin{Path: "/usr/local/bin/inagoctl-backup", Globals: []string{"--tunnel", "host", "-v"}, Args: []string{}},
+			Found:    true,
+		},
+		{
+			Args:     []string{"--tunnel=host", "backup", "myapp"},
+			Expected: plugin{Path: "/usr/local/bin/inagoctl-backup", Globals: []string{"--tunnel=host"}, Args: []string{"myapp"}},
+			Found:    true,
+		},
+		{Args: []string{"status", "myapp"}},
+		{Args: []string{"--tunnel", "backup"}},
+		{Args: []string{"--unknown", "backup"}},
+		{Args: []string{"restore", "myapp"}},
+		{Args: []string{"help"}},
+		{Args: []string{}},
+	}
+
+	for i, testCase := range testCases {
+		p, ok := findPlugin(givenPluginRoot(), testCase.Args, lookPath)
+		if ok != testCase.Found {
+			t.Fatal("case", i+1, "expected", testCase.Found, "got", ok)
+		}
+		if !ok {
+			continue
+		}
+		if !reflect.DeepEqual(p, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", p)
+		}
+	}
+}
+
+func Test_Plugin_pluginEnv(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("fleet-endpoint", "unix:///var/run/fleet.sock", "")
+	flags.String("tunnel", "", "")
+	flags.Bool("no-block", false, "")
+	flags.BoolP("verbose", "v", false, "")
+
+	lookup := func(name string) (string, bool) {
+		if name == "INAGO_NO_BLOCK" {
+			return "true", true
+		}
+		return "", false
+	}
+
+	env, err := pluginEnv(flags, []string{"--fleet-endpoint", "http://127.0.0.1:49153", "-v"}, lookup)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	expected := []string{
+		"INAGO_FLEET_ENDPOINT=http://127.0.0.1:49153",
+		"INAGO_NO_BLOCK=true",
+		"INAGO_TUNNEL=",
+		"INAGO_VERBOSE=true",
+	}
+	if !reflect.DeepEqual(env, expected) {
+		t.Fatal("expected", expected, "got", env)
+	}
+}
@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ryanuber/columnize"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+	formatJSONL = "jsonl"
+	formatHosts = "hosts"
+)
+
+const (
+	// eventProgress is emitted each time the phase of a slice changes while an
+	// operation is running.
+	eventProgress = "progress"
+
+	// eventSucceeded and eventFailed are emitted once an operation finished.
+	eventSucceeded = "succeeded"
+	eventFailed    = "failed"
+
+	// eventResult carries the result of a command using the jsonl format.
+	eventResult = "result"
+)
+
+// outPrinter prints the results and the progress of all commands in the
+// format given using --format. See newPrinter.
+var outPrinter printer = tablePrinter{}
+
+// printer prints the results and the progress of commands, so that --format
+// behaves the same way for all of them.
+type printer interface {
+	// PrintResult prints the result of a command.
+	PrintResult(w io.Writer, r result) error
+
+	// PrintEvent prints the given progress event of an operation. Printers
+	// producing a single document, like json, ignore events.
+	PrintEvent(w io.Writer, e event) error
+}
+
+// result is the result of a command, e.g. the status of a group.
+type result struct {
+	// Kind names the result, e.g. "status".
+	Kind string
+
+	// Rows is the table shown using the table format, to be formatted using
+	// columnize. The first row is the header.
+	Rows []string
+
+	// Colors are the colors of the rows, see colorRows.
+	Colors []string
+
+	// Data is the result printed using the structured formats. In case it is
+	// nil, the table rows are printed as list of objects keyed by the header.
+	Data interface{}
+}
+
+// data returns the structured form of the result. See result.Data.
+func (r result) data() interface{} {
+	if r.Data != nil {
+		return r.Data
+	}
+
+	return tableObjects(r.Rows)
+}
+
+// event is a progress event of an operation. Using the jsonl format, each
+// event is printed as a single line of JSON.
+//
+//   {"time":"2016-05-01T12:00:00Z","type":"progress","operation":"start","group":"myapp","slice":"0ds","phase":"active"}
+//   {"time":"2016-05-01T12:00:01Z","type":"succeeded","operation":"start","group":"myapp"}
+//
+type event struct {
+	Time      time.Time   `json:"time"`
+	Type      string      `json:"type"`
+	Operation string      `json:"operation,omitempty"`
+	Kind      string      `json:"kind,omitempty"`
+	Group     string      `json:"group,omitempty"`
+	Slice     string      `json:"slice,omitempty"`
+	Phase     string      `json:"phase,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// newPrinter returns the printer of the given format. In case the format is
+// unknown, an error that you can identify using IsInvalidArgumentsError is
+// returned.
+func newPrinter(format string) (printer, error) {
+	switch format {
+	case formatTable:
+		return tablePrinter{}, nil
+	case formatJSON:
+		return jsonPrinter{}, nil
+	case formatYAML:
+		return yamlPrinter{}, nil
+	case formatJSONL:
+		return jsonlPrinter{}, nil
+	case formatHosts:
+		return hostsPrinter{}, nil
+	}
+
+	return nil, maskAnyf(invalidArgumentsError, "--format must be one of %s, %s, %s, %s or %s, got '%s'", formatTable, formatJSON, formatYAML, formatJSONL, formatHosts, format)
+}
+
+// structuredFormat checks whether results are printed using a structured
+// format like json, instead of tables.
+func structuredFormat() bool {
+	_, ok := outPrinter.(tablePrinter)
+	return !ok
+}
+
+// printResult prints the given result to stdout using outPrinter and exits in
+// case this fails.
+func printResult(r result) {
+	if err := outPrinter.PrintResult(os.Stdout, r); err != nil {
+		newLogger.Error(newCtx, "Failed to print %s. (%s)", r.Kind, err.Error())
+		exit(1)
+	}
+}
+
+// printEvent prints the given event to stdout using outPrinter. Failing to
+// print progress does not fail the operation.
+func printEvent(e event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if err := outPrinter.PrintEvent(os.Stdout, e); err != nil {
+		newLogger.Debug(newCtx, "cli: printing event failed: %#v", maskAny(err))
+	}
+}
+
+// tableObjects turns the given table rows into a list of objects keyed by the
+// cells of the header row. Empty rows are skipped. Rows following an empty
+// row below table rows are treated as a new table having its own header.
+//
+//   Group | Desired | Actual      =>  [{"Actual": "3", "Desired": "3", "Group": "myapp"}]
+//   myapp | 3       | 3
+//
+func tableObjects(rows []string) []map[string]string {
+	objects := []map[string]string{}
+	var header []string
+	empty := true
+	for _, row := range rows {
+		if strings.TrimSpace(row) == "" {
+			// The empty row many tables have below their header is not the end
+			// of the table.
+			if !empty {
+				header = nil
+			}
+			continue
+		}
+		cells := splitRow(row)
+		if header == nil {
+			header = cells
+			empty = true
+			continue
+		}
+		empty = false
+		object := map[string]string{}
+		for i, cell := range cells {
+			if i < len(header) && header[i] != "" {
+				object[header[i]] = cell
+			}
+		}
+		objects = append(objects, object)
+	}
+
+	return objects
+}
+
+func splitRow(row string) []string {
+	var cells []string
+	for _, cell := range strings.Split(row, "|") {
+		cells = append(cells, strings.TrimSpace(cell))
+	}
+
+	return cells
+}
+
+// tablePrinter prints results as tables, the way inagoctl always did.
+// Progress is printed as one line each time the phase of a slice changes.
+type tablePrinter struct{}
+
+func (tablePrinter) PrintResult(w io.Writer, r result) error {
+	if len(r.Rows) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintln(w, colorRows(columnize.SimpleFormat(r.Rows), r.Colors))
+	return maskAny(err)
+}
+
+func (tablePrinter) PrintEvent(w io.Writer, e event) error {
+	if e.Type != eventProgress {
+		// The outcome of operations is logged.
+		return nil
+	}
+	name := e.Group
+	if e.Slice != "" {
+		name += "@" + e.Slice
+	}
+	_, err := fmt.Fprintf(w, "%s: %s\n", name, e.Phase)
+	return maskAny(err)
+}
+
+// jsonPrinter prints results as a single, indented JSON document.
+type jsonPrinter struct{}
+
+func (jsonPrinter) PrintResult(w io.Writer, r result) error {
+	raw, err := json.MarshalIndent(r.data(), "", "  ")
+	if err != nil {
+		return maskAny(err)
+	}
+	_, err = fmt.Fprintln(w, string(raw))
+	return maskAny(err)
+}
+
+func (jsonPrinter) PrintEvent(w io.Writer, e event) error {
+	return nil
+}
+
+// yamlPrinter prints results as a single YAML document. Results are converted
+// to JSON first, so that both formats use the same keys.
+type yamlPrinter struct{}
+
+func (yamlPrinter) PrintResult(w io.Writer, r result) error {
+	raw, err := json.Marshal(r.data())
+	if err != nil {
+		return maskAny(err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return maskAny(err)
+	}
+	raw, err = yaml.Marshal(generic)
+	if err != nil {
+		return maskAny(err)
+	}
+	_, err = w.Write(raw)
+	return maskAny(err)
+}
+
+func (yamlPrinter) PrintEvent(w io.Writer, e event) error {
+	return nil
+}
+
+// jsonlPrinter prints each progress event, and the result of a command, as a
+// single line of JSON, so that e.g. deployment dashboards can follow
+// operations as they happen. See event.
+type jsonlPrinter struct{}
+
+func (p jsonlPrinter) PrintResult(w io.Writer, r result) error {
+	return p.PrintEvent(w, event{
+		Time: time.Now().UTC(),
+		Type: eventResult,
+		Kind: r.Kind,
+		Data: r.data(),
+	})
+}
+
+func (jsonlPrinter) PrintEvent(w io.Writer, e event) error {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return maskAny(err)
+	}
+	_, err = fmt.Fprintln(w, string(raw))
+	return maskAny(err)
+}
+
+// hostsData is implemented by results that can be printed as /etc/hosts
+// entries, e.g. the endpoints of a group.
+type hostsData interface {
+	hostsEntries() []string
+}
+
+// hostsPrinter prints results in the format of /etc/hosts. Only results
+// implementing hostsData can be printed this way. Progress is not printed.
+type hostsPrinter struct{}
+
+func (hostsPrinter) PrintResult(w io.Writer, r result) error {
+	data, ok := r.Data.(hostsData)
+	if !ok {
+		return maskAnyf(invalidArgumentsError, "--format %s is not supported for %s", formatHosts, r.Kind)
+	}
+	for _, entry := range data.hostsEntries() {
+		if _, err := fmt.Fprintln(w, entry); err != nil {
+			return maskAny(err)
+		}
+	}
+
+	return nil
+}
+
+func (hostsPrinter) PrintEvent(w io.Writer, e event) error {
+	return nil
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_Printer_newPrinter(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, format := range []string{formatTable, formatJSON, formatYAML, formatJSONL, formatHosts} {
+		_, err := newPrinter(format)
+		Expect(err).To(BeNil(), format)
+	}
+
+	_, err := newPrinter("xml")
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+}
+
+func Test_Printer_tableObjects(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []struct {
+		Comment  string
+		Rows     []string
+		Expected []map[string]string
+	}{
+		{
+			Comment:  "no rows",
+			Rows:     nil,
+			Expected: []map[string]string{},
+		},
+		{
+			Comment: "empty row below the header",
+			Rows: []string{
+				"Group | Desired | Actual",
+				"",
+				"myapp | 3 | 2",
+			},
+			Expected: []map[string]string{
+				{"Group": "myapp", "Desired": "3", "Actual": "2"},
+			},
+		},
+		{
+			Comment: "multiple tables",
+			Rows: []string{
+				"Group | Slice",
+				"myapp | 0ds",
+				"",
+				"Unit | State",
+				"",
+				"myapp-foo@0ds.service | active",
+			},
+			Expected: []map[string]string{
+				{"Group": "myapp", "Slice": "0ds"},
+				{"Unit": "myapp-foo@0ds.service", "State": "active"},
+			},
+		},
+		{
+			Comment: "empty header cells are dropped",
+			Rows: []string{
+				"Group | ",
+				"myapp | extra",
+			},
+			Expected: []map[string]string{
+				{"Group": "myapp"},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		Expect(tableObjects(testCase.Rows)).To(Equal(testCase.Expected), "test case %d (%s)", i+1, testCase.Comment)
+	}
+}
+
+func Test_Printer_PrintResult(t *testing.T) {
+	RegisterTestingT(t)
+
+	r := result{
+		Kind: "scale",
+		Rows: createScaleSummary("myapp", 3, 2),
+	}
+
+	testCases := []struct {
+		Printer  printer
+		Expected string
+	}{
+		{
+			Printer:  tablePrinter{},
+			Expected: "Group  Desired  Actual\nmyapp  3        2\n",
+		},
+		{
+			Printer:  jsonPrinter{},
+			Expected: "[\n  {\n    \"Actual\": \"2\",\n    \"Desired\": \"3\",\n    \"Group\": \"myapp\"\n  }\n]\n",
+		},
+		{
+			Printer:  yamlPrinter{},
+			Expected: "- Actual: \"2\"\n  Desired: \"3\"\n  Group: myapp\n",
+		},
+	}
+
+	for i, testCase := range testCases {
+		var out bytes.Buffer
+		err := testCase.Printer.PrintResult(&out, r)
+		Expect(err).To(BeNil(), "test case %d", i+1)
+		Expect(out.String()).To(Equal(testCase.Expected), "test case %d", i+1)
+	}
+}
+
+func Test_Printer_jsonl(t *testing.T) {
+	RegisterTestingT(t)
+
+	var out bytes.Buffer
+	p := jsonlPrinter{}
+	err := p.PrintEvent(&out, event{
+		Time:      time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC),
+		Type:      eventProgress,
+		Operation: "start",
+		Group:     "myapp",
+		Slice:     "0ds",
+		Phase:     string(phaseActive),
+	})
+	Expect(err).To(BeNil())
+	err = p.PrintResult(&out, result{Kind: "status", Data: statusOutput{Group: "myapp"}})
+	Expect(err).To(BeNil())
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	Expect(lines).To(HaveLen(2))
+	Expect(lines[0]).To(Equal(`{"time":"2016-05-01T12:00:00Z","type":"progress","operation":"start","group":"myapp","slice":"0ds","phase":"active"}`))
+
+	var e map[string]interface{}
+	err = json.Unmarshal([]byte(lines[1]), &e)
+	Expect(err).To(BeNil())
+	Expect(e["type"]).To(Equal(eventResult))
+	Expect(e["kind"]).To(Equal("status"))
+	Expect(e["data"]).To(HaveKeyWithValue("Group", "myapp"))
+}
+
+func Test_Printer_tablePrinter_PrintEvent(t *testing.T) {
+	RegisterTestingT(t)
+
+	var out bytes.Buffer
+	p := tablePrinter{}
+	err := p.PrintEvent(&out, event{Type: eventProgress, Group: "myapp", Slice: "0ds", Phase: string(phaseLoading)})
+	Expect(err).To(BeNil())
+	err = p.PrintEvent(&out, event{Type: eventSucceeded, Group: "myapp"})
+	Expect(err).To(BeNil())
+
+	Expect(out.String()).To(Equal("myapp@0ds: loading\n"))
+}
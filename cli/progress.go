@@ -0,0 +1,371 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/ryanuber/columnize"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+)
+
+var (
+	// progressInterval is the time to wait between rendering the status of a
+	// group while a task is running.
+	progressInterval = 1 * time.Second
+)
+
+// phase represents the phase of a slice during a long running operation.
+type phase string
+
+const (
+	phaseFailed     phase = "failed"
+	phaseStopping   phase = "stopping"
+	phaseSubmitting phase = "submitting"
+	phaseLoading    phase = "loading"
+	phaseLoaded     phase = "loaded"
+	phaseStarting   phase = "starting"
+	phaseActive     phase = "active"
+	phaseDestroyed  phase = "destroyed"
+)
+
+// phaseOrder defines which phase wins in case the units of a slice are in
+// different phases. The slice is considered to be in the phase of its least
+// progressed unit, except that failures always win.
+var phaseOrder = []phase{
+	phaseFailed,
+	phaseStopping,
+	phaseSubmitting,
+	phaseLoading,
+	phaseLoaded,
+	phaseStarting,
+	phaseActive,
+}
+
+func phaseRank(p phase) int {
+	for i, o := range phaseOrder {
+		if o == p {
+			return i
+		}
+	}
+
+	return len(phaseOrder)
+}
+
+// slicePhase represents the phase of a single slice of a group.
+type slicePhase struct {
+	SliceID string
+	Phase   phase
+}
+
+// slicePhases maps the given unit states to the phase of each slice. The
+// result is sorted by slice ID.
+func slicePhases(usl []fleet.UnitStatus) []slicePhase {
+	return readySlicePhases(usl, controller.Request{})
+}
+
+// readySlicePhases works like slicePhases, but considers units active once
+// they are in one of the ready states the given request defines for them,
+// e.g. oneshot units that exited. See controller.Request.UnitReady.
+func readySlicePhases(usl []fleet.UnitStatus, req controller.Request) []slicePhase {
+	names := map[string]struct{}{}
+	for _, us := range usl {
+		names[us.Name] = struct{}{}
+	}
+	// Units activated by timers, sockets and the like are not started
+	// explicitly. Their state does not tell anything about the progress.
+	triggered := map[string]struct{}{}
+	for _, us := range usl {
+		if _, ok := names[common.TriggeredUnit(us.Name)]; ok {
+			triggered[common.TriggeredUnit(us.Name)] = struct{}{}
+		}
+	}
+
+	phases := map[string]phase{}
+	for _, us := range usl {
+		if _, ok := triggered[us.Name]; ok {
+			continue
+		}
+
+		p := unitPhase(us)
+		if req.UnitReady(us) {
+			p = phaseActive
+		}
+		if current, ok := phases[us.SliceID]; !ok || phaseRank(p) < phaseRank(current) {
+			phases[us.SliceID] = p
+		}
+	}
+
+	var result []slicePhase
+	for sliceID, p := range phases {
+		result = append(result, slicePhase{SliceID: sliceID, Phase: p})
+	}
+	sort.Sort(slicePhasesByID(result))
+
+	return result
+}
+
+// unitPhase maps the state of the given unit to a phase.
+func unitPhase(us fleet.UnitStatus) phase {
+	if len(us.Machine) == 0 {
+		// The unit is not scheduled to any machine yet.
+		return phaseSubmitting
+	}
+
+	aggregator := controller.Aggregator{Logger: newLogger}
+	result := phaseActive
+	for _, ms := range us.Machine {
+		var p phase
+
+		status, err := aggregator.AggregateStatus(us.Current, us.Desired, ms.SystemdActive, ms.SystemdSub)
+		if err != nil {
+			// Unknown states are reported as soon as the operation finishes. Here we
+			// only want to give feedback, so we consider the unit to be in progress.
+			p = phaseStarting
+		} else {
+			switch status {
+			case controller.StatusFailed:
+				p = phaseFailed
+			case controller.StatusRunning:
+				p = phaseActive
+			case controller.StatusStarting:
+				p = phaseStarting
+			case controller.StatusStopping:
+				p = phaseStopping
+			default:
+				switch {
+				case us.Desired == "launched":
+					p = phaseStarting
+				case us.Current == "loaded" || us.Current == "launched":
+					p = phaseLoaded
+				case us.Desired == "loaded":
+					p = phaseLoading
+				default:
+					p = phaseSubmitting
+				}
+			}
+		}
+
+		if phaseRank(p) < phaseRank(result) {
+			result = p
+		}
+	}
+
+	return result
+}
+
+type slicePhasesByID []slicePhase
+
+func (s slicePhasesByID) Len() int           { return len(s) }
+func (s slicePhasesByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s slicePhasesByID) Less(i, j int) bool { return s[i].SliceID < s[j].SliceID }
+
+// progressRenderer renders the phases of the slices of a group. On a terminal
+// the rendered table is updated in place. Otherwise a progress event is printed
+// using Printer each time the phase of a slice changes.
+type progressRenderer struct {
+	Group string
+	Out   io.Writer
+	TTY   bool
+
+	// Operation is the operation in progress, e.g. "start". It is part of the
+	// printed events.
+	Operation string
+
+	// Printer prints the progress events. See printer.PrintEvent.
+	Printer printer
+
+	// lines is the number of lines written by the last render in TTY mode.
+	lines int
+	// phases contains the last rendered phase of each slice.
+	phases map[string]phase
+}
+
+func newProgressRenderer(group string, out io.Writer, tty bool) *progressRenderer {
+	return &progressRenderer{
+		Group:   group,
+		Out:     out,
+		TTY:     tty,
+		Printer: outPrinter,
+		phases:  map[string]phase{},
+	}
+}
+
+// Render renders the given slice phases. Slices rendered before, but missing
+// now, are considered to be destroyed.
+func (p *progressRenderer) Render(sps []slicePhase) {
+	seen := map[string]struct{}{}
+	for _, sp := range sps {
+		seen[sp.SliceID] = struct{}{}
+	}
+	for sliceID := range p.phases {
+		if _, ok := seen[sliceID]; !ok {
+			sps = append(sps, slicePhase{SliceID: sliceID, Phase: phaseDestroyed})
+		}
+	}
+	sort.Sort(slicePhasesByID(sps))
+
+	if p.TTY {
+		p.renderTable(sps)
+	} else {
+		p.renderLines(sps)
+	}
+
+	for _, sp := range sps {
+		p.phases[sp.SliceID] = sp.Phase
+	}
+}
+
+func (p *progressRenderer) renderTable(sps []slicePhase) {
+	if len(sps) == 0 {
+		return
+	}
+
+	rows := []string{"Slice | Phase", ""}
+	for _, sp := range sps {
+		rows = append(rows, fmt.Sprintf("%s | %s", p.sliceName(sp.SliceID), sp.Phase))
+	}
+	out := columnize.SimpleFormat(rows) + "\n"
+
+	if p.lines > 0 {
+		// Move the cursor to the beginning of the previously rendered table and
+		// clear everything below it.
+		fmt.Fprintf(p.Out, "\033[%dA\033[J", p.lines)
+	}
+	fmt.Fprint(p.Out, out)
+	p.lines = strings.Count(out, "\n")
+}
+
+func (p *progressRenderer) renderLines(sps []slicePhase) {
+	for _, sp := range sps {
+		if current, ok := p.phases[sp.SliceID]; ok && current == sp.Phase {
+			continue
+		}
+		p.Printer.PrintEvent(p.Out, event{
+			Time:      time.Now().UTC(),
+			Type:      eventProgress,
+			Operation: p.Operation,
+			Group:     p.Group,
+			Slice:     sp.SliceID,
+			Phase:     string(sp.Phase),
+		})
+	}
+}
+
+func (p *progressRenderer) sliceName(sliceID string) string {
+	if sliceID == "" {
+		return p.Group
+	}
+	return p.Group + "@" + sliceID
+}
+
+// startProgress renders the progress of the given operation on the group of
+// the given request until the returned function is called. Unit states are
+// watched using Controller.WatchGroup, and rendered each progressInterval in
+// case they changed. The returned function blocks until the final state of the
+// group has been rendered. The table updated in place is only used for the
+// table format. The history of units selected using --match is not recorded,
+// as they do not form a group.
+func startProgress(ctx context.Context, operation string, groupReq controller.Request) func() {
+	group := groupReq.Group
+	_, table := outPrinter.(tablePrinter)
+	tty := table && isatty.IsTerminal(os.Stdout.Fd()) && !globalFlags.NoTTY && !globalFlags.Verbose
+	renderer := newProgressRenderer(group, os.Stdout, tty)
+	renderer.Operation = operation
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	req := controller.NewRequest(newRequestConfig)
+	req.Match = groupReq.Match
+	req.ReadyStates = groupReq.ReadyStates
+
+	render := func(usl []fleet.UnitStatus) {
+		sps := readySlicePhases(usl, req)
+		renderer.Render(sps)
+		reportProgress(group, sps, time.Now())
+
+		if req.Match != nil {
+			return
+		}
+		if err := recordHistory(newStateStore, group, usl, time.Now()); err != nil {
+			newLogger.Debug(ctx, "cli: recording history of group '%s' failed: %#v", group, maskAny(err))
+		}
+	}
+
+	changes, stopWatch := newController.WatchGroup(ctx, req)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		defer stopWatch()
+
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+
+		statuses := map[string]fleet.UnitStatus{}
+		changed := false
+		for {
+			select {
+			case change, ok := <-changes:
+				if !ok {
+					changes = nil
+					continue
+				}
+				if change.Err != nil {
+					newLogger.Debug(ctx, "cli: fetching progress of group '%s' failed: %#v", group, maskAny(change.Err))
+				} else if change.Removed {
+					delete(statuses, change.Name)
+				} else {
+					statuses[change.Name] = change.Status
+				}
+				changed = true
+			case <-ticker.C:
+				if changed {
+					render(sortedUnitStatuses(statuses))
+					changed = false
+				}
+			case <-done:
+				// The final state is fetched right away, instead of waiting for the
+				// watch to notice it.
+				usl, err := newController.GetStatus(ctx, req)
+				if controller.IsUnitNotFound(err) {
+					usl = nil
+				} else if err != nil {
+					newLogger.Debug(ctx, "cli: fetching progress of group '%s' failed: %#v", group, maskAny(err))
+					return
+				}
+				render(usl)
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// sortedUnitStatuses returns the given unit statuses sorted by unit name.
+func sortedUnitStatuses(statuses map[string]fleet.UnitStatus) []fleet.UnitStatus {
+	var names []string
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var usl []fleet.UnitStatus
+	for _, name := range names {
+		usl = append(usl, statuses[name])
+	}
+
+	return usl
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/logging"
+)
+
+func runningUnitStatus(name, sliceID string) fleet.UnitStatus {
+	return fleet.UnitStatus{
+		Current: "launched",
+		Desired: "launched",
+		Machine: []fleet.MachineStatus{
+			{
+				ID:            "505e0d7802d7439a924c269b76f34b5f",
+				SystemdActive: "active",
+				SystemdSub:    "running",
+			},
+		},
+		Name:    name,
+		SliceID: sliceID,
+	}
+}
+
+func Test_Progress_slicePhases(t *testing.T) {
+	RegisterTestingT(t)
+	newLogger = logging.NewLogger(logging.DefaultConfig())
+
+	testCases := []struct {
+		Comment  string
+		USL      []fleet.UnitStatus
+		Expected []slicePhase
+	}{
+		{
+			Comment:  "no units",
+			USL:      nil,
+			Expected: nil,
+		},
+		{
+			Comment: "slices in different phases",
+			USL: []fleet.UnitStatus{
+				unloadedUnitStatus("example-foo@1.service", "1", "loaded"),
+				loadedUnitStatus("example-foo@2.service", "2", "172.17.8.101", "505e0d7802d7439a924c269b76f34b5f", "inactive", "loaded"),
+				loadedUnitStatus("example-foo@3.service", "3", "172.17.8.101", "505e0d7802d7439a924c269b76f34b5f", "loaded", "loaded"),
+				loadedUnitStatus("example-foo@4.service", "4", "172.17.8.101", "505e0d7802d7439a924c269b76f34b5f", "loaded", "launched"),
+				runningUnitStatus("example-foo@5.service", "5"),
+			},
+			Expected: []slicePhase{
+				{SliceID: "1", Phase: phaseSubmitting},
+				{SliceID: "2", Phase: phaseLoading},
+				{SliceID: "3", Phase: phaseLoaded},
+				{SliceID: "4", Phase: phaseStarting},
+				{SliceID: "5", Phase: phaseActive},
+			},
+		},
+		{
+			Comment: "least progressed unit determines the phase of a slice",
+			USL: []fleet.UnitStatus{
+				runningUnitStatus("example-foo@1.service", "1"),
+				loadedUnitStatus("example-bar@1.service", "1", "172.17.8.101", "505e0d7802d7439a924c269b76f34b5f", "loaded", "launched"),
+			},
+			Expected: []slicePhase{
+				{SliceID: "1", Phase: phaseStarting},
+			},
+		},
+		{
+			Comment: "units activated by timers are ignored",
+			USL: []fleet.UnitStatus{
+				runningUnitStatus("example-foo.timer", ""),
+				loadedUnitStatus("example-foo.service", "", "172.17.8.101", "505e0d7802d7439a924c269b76f34b5f", "loaded", "loaded"),
+			},
+			Expected: []slicePhase{
+				{SliceID: "", Phase: phaseActive},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		Expect(slicePhases(testCase.USL)).To(Equal(testCase.Expected), "test case %d: %s", i+1, testCase.Comment)
+	}
+}
+
+func Test_Progress_readySlicePhases(t *testing.T) {
+	RegisterTestingT(t)
+	newLogger = logging.NewLogger(logging.DefaultConfig())
+
+	exited := runningUnitStatus("example-init@1.service", "1")
+	exited.Machine[0].SystemdActive = "inactive"
+	exited.Machine[0].SystemdSub = "dead"
+	usl := []fleet.UnitStatus{runningUnitStatus("example-foo@1.service", "1"), exited}
+
+	// Oneshot units that exited are done, other units dead while supposed to
+	// run are still starting.
+	req := controller.Request{ReadyStates: map[string][]controller.ReadyState{"example-init@.service": controller.OneshotReadyStates}}
+	Expect(readySlicePhases(usl, req)).To(Equal([]slicePhase{{SliceID: "1", Phase: phaseActive}}))
+	Expect(slicePhases(usl)).To(Equal([]slicePhase{{SliceID: "1", Phase: phaseStarting}}))
+}
+
+func Test_Progress_Render_Lines(t *testing.T) {
+	RegisterTestingT(t)
+
+	out := bytes.NewBufferString("")
+	renderer := newProgressRenderer("example", out, false)
+
+	renderer.Render([]slicePhase{{SliceID: "1", Phase: phaseLoading}, {SliceID: "2", Phase: phaseLoading}})
+	renderer.Render([]slicePhase{{SliceID: "1", Phase: phaseLoading}, {SliceID: "2", Phase: phaseActive}})
+	renderer.Render([]slicePhase{{SliceID: "2", Phase: phaseActive}})
+	renderer.Render([]slicePhase{{SliceID: "2", Phase: phaseActive}})
+
+	Expect(out.String()).To(Equal("example@1: loading\nexample@2: loading\nexample@2: active\nexample@1: destroyed\n"))
+}
+
+func Test_Progress_Render_Table(t *testing.T) {
+	RegisterTestingT(t)
+
+	out := bytes.NewBufferString("")
+	renderer := newProgressRenderer("example", out, true)
+
+	renderer.Render([]slicePhase{{SliceID: "1", Phase: phaseLoading}})
+	renderer.Render([]slicePhase{{SliceID: "1", Phase: phaseActive}})
+
+	Expect(out.String()).To(Equal("Slice      Phase\n\nexample@1  loading\n\033[3A\033[JSlice      Phase\n\nexample@1  active\n"))
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/spec"
+)
+
+var (
+	pullFlags struct {
+		Force bool
+	}
+
+	pullCmd = &cobra.Command{
+		Use:   "pull <group> [directory]",
+		Short: "Pull a group",
+		Long:  "Write the unit files of a deployed group to a local group directory, e.g. to recover a lost group directory, or to start editing the group as it runs. Labels of the group are written to its manifest. The directory defaults to the group's name",
+		Run:   pullRun,
+	}
+)
+
+func init() {
+	pullCmd.PersistentFlags().BoolVar(&pullFlags.Force, "force", false, "overwrite the files of an existing directory")
+}
+
+func pullRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting pull")
+
+	if len(args) == 0 || len(args) > 2 {
+		cmd.Help()
+		exit(1)
+	}
+	group := resolveGroup(args[0])
+	dir := group
+	if len(args) == 2 {
+		dir = args[1]
+	}
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	files, err := newController.Pull(newCtx, controller.NewRequest(newRequestConfig))
+	if controller.IsUnitNotFound(err) {
+		handlePullCmdError(maskAnyf(groupNotFoundError, "%s", group))
+	}
+	handlePullCmdError(err)
+
+	err = writePulledGroup(fs, dir, files, pullFlags.Force)
+	handlePullCmdError(err)
+
+	newLogger.Info(newCtx, "Succeeded to pull group '%s' with %d slices into '%s'.", group, len(files.SliceIDs), dir)
+}
+
+// writePulledGroup writes the given group files to the given directory. In
+// case the group has labels, they are written to the group's manifest. Other
+// fields of an existing manifest are kept. Existing directories are only
+// touched in case force is true. Otherwise an error that you can identify
+// using IsGroupAlreadyExists is returned.
+func writePulledGroup(fs filesystemspec.FileSystem, dir string, files controller.GroupFiles, force bool) error {
+	exists := false
+	if _, err := fs.ReadDir(dir); err == nil {
+		exists = true
+	}
+	if exists && !force {
+		return maskAnyf(groupAlreadyExistsError, "directory '%s' already exists", dir)
+	}
+
+	for _, unit := range files.Units {
+		err := fs.WriteFile(filepath.Join(dir, unit.Name), []byte(unit.Content), os.FileMode(0644))
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+
+	if len(files.Labels) == 0 {
+		return nil
+	}
+	manifest, err := readGroupManifest(fs, dir)
+	if err != nil {
+		return maskAny(err)
+	}
+	manifest.Labels = files.Labels
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return maskAny(err)
+	}
+	err = fs.WriteFile(filepath.Join(dir, groupManifestFile), append(raw, '\n'), os.FileMode(0644))
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func handlePullCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/fake"
+)
+
+func Test_Pull_writePulledGroup(t *testing.T) {
+	RegisterTestingT(t)
+
+	files := controller.GroupFiles{
+		Group: "mygroup",
+		Units: []controller.Unit{
+			{Name: "mygroup-app@.service", Content: "[Service]\nExecStart=/bin/app\n"},
+		},
+		Labels:   map[string]string{"team": "payments"},
+		SliceIDs: []string{"1", "2"},
+	}
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	Expect(writePulledGroup(newFileSystem, "mygroup", files, false)).To(Succeed())
+	raw, err := newFileSystem.ReadFile("mygroup/mygroup-app@.service")
+	Expect(err).To(BeNil())
+	Expect(string(raw)).To(Equal(files.Units[0].Content))
+	manifest, err := readGroupManifest(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(manifest.Labels).To(Equal(files.Labels))
+
+	// Existing directories are only overwritten using force. Other fields of
+	// the manifest are kept.
+	err = writePulledGroup(newFileSystem, "mygroup", files, false)
+	Expect(IsGroupAlreadyExists(err)).To(BeTrue())
+
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"owner": "payments", "labels": {"tier": "backend"}}`), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	Expect(writePulledGroup(newFileSystem, "mygroup", files, true)).To(Succeed())
+	manifest, err = readGroupManifest(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(manifest.Owner).To(Equal("payments"))
+	Expect(manifest.Labels).To(Equal(files.Labels))
+}
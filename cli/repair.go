@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+)
+
+var (
+	repairFlags struct {
+		DryRun bool
+	}
+
+	repairCmd = &cobra.Command{
+		Use:   "repair [group...]",
+		Short: "Repair groups",
+		Long:  "Reschedule slices having units on machines that left the cluster, or units that are supposed to run but are dead. Affected slices are destroyed, submitted again using the unit files deployed to the cluster, and started. Without arguments, the groups of the current working directory are repaired",
+		Run:   reportingRun("repair", repairRun),
+	}
+)
+
+func init() {
+	repairCmd.PersistentFlags().BoolVar(&repairFlags.DryRun, "dry-run", false, "only show the slices that need to be repaired")
+}
+
+// repairedSlice describes a slice rescheduled by the repair command.
+type repairedSlice struct {
+	Group   string
+	Damaged controller.DamagedSlice
+
+	// To is the ID of the machine the slice has been scheduled on after the
+	// repair. It is empty in case the slice was not repaired yet.
+	To string
+}
+
+func repairRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting repair")
+
+	groups := resolveGroups(args)
+	if len(groups) == 0 {
+		var err error
+		groups, err = localGroups(fs)
+		handleRepairCmdError(err)
+	}
+
+	var repaired []repairedSlice
+	for _, group := range groups {
+		newRequestConfig := controller.DefaultRequestConfig()
+		newRequestConfig.Group = group
+		req, err := extendRequestWithReadiness(fs, controller.NewRequest(newRequestConfig))
+		handleRepairCmdError(err)
+
+		damaged, err := newController.DamagedSlices(newCtx, req)
+		if controller.IsUnitNotFound(err) && len(args) == 0 {
+			// Local groups that are not deployed do not need to be repaired.
+			newLogger.Debug(newCtx, "cli: group '%s' is not deployed", group)
+			continue
+		}
+		handleRepairCmdError(err)
+		if len(damaged) == 0 {
+			newLogger.Debug(newCtx, "cli: group '%s' has no damaged slices", group)
+			continue
+		}
+
+		for _, d := range damaged {
+			if d.SliceID != "" {
+				req.SliceIDs = append(req.SliceIDs, d.SliceID)
+			}
+		}
+
+		if !repairFlags.DryRun {
+			taskObject, err := newController.Repair(newCtx, req)
+			handleRepairCmdError(err)
+
+			maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+				Request:    req,
+				Descriptor: "repair",
+				NoBlock:    false,
+				TaskID:     taskObject.ID,
+				Closer:     nil,
+			})
+		}
+
+		var unitStatusList []fleet.UnitStatus
+		if !repairFlags.DryRun {
+			unitStatusList, err = newController.GetStatus(newCtx, req)
+			handleRepairCmdError(err)
+		}
+		for _, d := range damaged {
+			repaired = append(repaired, repairedSlice{
+				Group:   group,
+				Damaged: d,
+				To:      sliceMachineID(unitStatusList, d.SliceID),
+			})
+		}
+	}
+
+	if len(repaired) == 0 {
+		newLogger.Info(newCtx, "No damaged slices found.")
+		return
+	}
+
+	printResult(result{Kind: "repair", Rows: createRepairSummary(repaired)})
+}
+
+// createRepairSummary creates a table row for each of the given slices,
+// telling why it has been repaired and where it has been moved.
+func createRepairSummary(repaired []repairedSlice) []string {
+	rows := []string{"Slice | Reason | From | To"}
+	for _, r := range repaired {
+		name := r.Group
+		if r.Damaged.SliceID != "" {
+			name += "@" + r.Damaged.SliceID
+		}
+		rows = append(rows, fmt.Sprintf("%s | %s | %s | %s", name, r.Damaged.Reason, shortMachineID(r.Damaged.MachineID), shortMachineID(r.To)))
+	}
+
+	return rows
+}
+
+// sliceMachineID returns the ID of the machine the units of the given slice
+// are scheduled on. It is empty in case the slice is not scheduled.
+func sliceMachineID(unitStatusList []fleet.UnitStatus, sliceID string) string {
+	for _, us := range unitStatusList {
+		if us.SliceID != sliceID {
+			continue
+		}
+		for _, ms := range us.Machine {
+			return ms.ID
+		}
+	}
+
+	return ""
+}
+
+// shortMachineID abbreviates the given fleet machine ID like fleetctl does.
+func shortMachineID(machineID string) string {
+	if machineID == "" {
+		return "-"
+	}
+	if len(machineID) > 8 {
+		return machineID[:8]
+	}
+
+	return machineID
+}
+
+func handleRepairCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_Repair_createRepairSummary(t *testing.T) {
+	unitStatusList := []fleet.UnitStatus{
+		{Name: "foo-main@1.service", SliceID: "1", Machine: []fleet.MachineStatus{{ID: "9ebb53b04b0d46fb94b4fd1b3f562d2b"}}},
+	}
+
+	repaired := []repairedSlice{
+		{
+			Group:   "foo",
+			Damaged: controller.DamagedSlice{SliceID: "1", MachineID: "505e0d7802d7439a924c269b76f34b5f", Reason: controller.RepairReasonMachineVanished},
+			To:      sliceMachineID(unitStatusList, "1"),
+		},
+		{
+			Group:   "bar",
+			Damaged: controller.DamagedSlice{MachineID: "505e0d78", Reason: controller.RepairReasonUnitDead},
+			To:      sliceMachineID(unitStatusList, ""),
+		},
+	}
+
+	expected := []string{
+		"Slice | Reason | From | To",
+		"foo@1 | machine vanished | 505e0d78 | 9ebb53b0",
+		"bar | unit dead | 505e0d78 | -",
+	}
+	rows := createRepairSummary(repaired)
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatal("expected", expected, "got", rows)
+	}
+}
@@ -0,0 +1,330 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/spec"
+	"github.com/giantswarm/inago/fleet"
+)
+
+var (
+	// currentReport is the report of the operation currently executed, in case
+	// --report is given and the operation is wrapped using reportingRun.
+	currentReport *report
+)
+
+// report describes a mutating operation and its outcome. It is written to the
+// file given using --report once the operation finished, e.g. to be attached
+// to change tickets or CI artifacts.
+type report struct {
+	Operation string        `json:"operation"`
+	Args      []string      `json:"args"`
+	RequestID string        `json:"request-id,omitempty"`
+	Started   time.Time     `json:"started"`
+	Finished  time.Time     `json:"finished"`
+	Duration  string        `json:"duration"`
+	Succeeded bool          `json:"succeeded"`
+	ExitCode  int           `json:"exit-code"`
+	Groups    []groupReport `json:"groups"`
+
+	mutex sync.Mutex
+}
+
+// groupReport describes the outcome of an operation for a single group.
+// Before and After contain the state of the group, including the content
+// hashes of its units, when the operation started and finished. Before is nil
+// for groups not known when the operation started, e.g. the ones of a stack.
+type groupReport struct {
+	Group     string        `json:"group"`
+	Duration  string        `json:"duration,omitempty"`
+	Succeeded bool          `json:"succeeded"`
+	Error     string        `json:"error,omitempty"`
+	Slices    []sliceReport `json:"slices,omitempty"`
+	Before    *statusOutput `json:"before,omitempty"`
+	After     *statusOutput `json:"after,omitempty"`
+
+	// Flapping contains the slices restarting too often once the operation
+	// finished. See controller.Controller.CheckFlapping.
+	Flapping []controller.FlappingSlice `json:"flapping,omitempty"`
+}
+
+// sliceReport describes the phases a slice went through during an operation.
+// Started and Finished are the times the first and last phase change of the
+// slice was observed.
+type sliceReport struct {
+	ID       string        `json:"id"`
+	Started  time.Time     `json:"started"`
+	Finished time.Time     `json:"finished"`
+	Duration string        `json:"duration"`
+	Phases   []phaseReport `json:"phases"`
+	Error    string        `json:"error,omitempty"`
+}
+
+type phaseReport struct {
+	Phase string    `json:"phase"`
+	Time  time.Time `json:"time"`
+}
+
+// reportingRun wraps the given run function of a command, so that a report of
+// the given operation is written to the file given using --report, and recorded
+// in the audit log, once the command finished. Commands fail by exiting using a
+// non-zero code. See exit.
+func reportingRun(operation string, run func(cmd *cobra.Command, args []string)) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		startReport(newCtx, operation, args)
+
+		run(cmd, args)
+
+		finishReport(newCtx, 0)
+	}
+}
+
+// startReport starts the report of the given operation. Reports are written to
+// the file given using --report, and recorded in the audit log of each group,
+// see recordAudit. The state of the groups given as arguments is recorded as
+// the state before the operation.
+func startReport(ctx context.Context, operation string, args []string) {
+	currentReport = &report{
+		Operation: operation,
+		Args:      append([]string{}, args...),
+		RequestID: fleet.RequestID(ctx),
+		Started:   time.Now().UTC(),
+	}
+
+	var groups []string
+	for _, arg := range args {
+		if isGroupArchive(arg) {
+			continue
+		}
+		groups = append(groups, strings.Split(resolveGroup(arg), "@")[0])
+	}
+	reportGroupsBefore(ctx, groups)
+}
+
+// reportGroupsBefore records the current state of the given groups as the
+// state before the current operation. Groups already recorded are skipped.
+func reportGroupsBefore(ctx context.Context, groups []string) {
+	if currentReport == nil {
+		return
+	}
+
+	for _, group := range groups {
+		usl, err := reportGroupStatus(ctx, group)
+		if err != nil {
+			continue
+		}
+		// Arguments not being groups, e.g. the name of a stack, are neither
+		// deployed nor defined in the current working directory.
+		if _, err := fs.ReadDir(group); len(usl) == 0 && err != nil {
+			continue
+		}
+
+		currentReport.mutex.Lock()
+		gr := currentReport.group(group)
+		if gr.Before == nil {
+			out := newStatusOutput(group, usl)
+			gr.Before = &out
+		}
+		currentReport.mutex.Unlock()
+	}
+}
+
+// reportGroupStatus fetches the state of the given group for the current
+// report. Groups not being deployed have an empty state.
+func reportGroupStatus(ctx context.Context, group string) ([]fleet.UnitStatus, error) {
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	usl, err := newController.GetStatus(ctx, controller.NewRequest(newRequestConfig))
+	if controller.IsUnitNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		newLogger.Debug(ctx, "cli: fetching state of group '%s' for report failed: %#v", group, maskAny(err))
+		return nil, maskAny(err)
+	}
+
+	return usl, nil
+}
+
+// reportProgress records the given phases of the slices of the given group,
+// as observed at the given time, in the current report.
+func reportProgress(group string, sps []slicePhase, now time.Time) {
+	if currentReport == nil {
+		return
+	}
+
+	currentReport.mutex.Lock()
+	defer currentReport.mutex.Unlock()
+
+	gr := currentReport.group(group)
+	for _, sp := range sps {
+		sr := gr.slice(sp.SliceID)
+		if n := len(sr.Phases); n > 0 && sr.Phases[n-1].Phase == string(sp.Phase) {
+			continue
+		}
+		if len(sr.Phases) == 0 {
+			sr.Started = now.UTC()
+		}
+		sr.Phases = append(sr.Phases, phaseReport{Phase: string(sp.Phase), Time: now.UTC()})
+		sr.Finished = now.UTC()
+		sr.Duration = (sr.Finished.Sub(sr.Started) / time.Millisecond * time.Millisecond).String()
+	}
+}
+
+// reportTaskError records the given error of a task executed for the given
+// group in the current report. Errors of single slices are taken from
+// controller.MultiSliceError.
+func reportTaskError(group string, err error, multiErr *controller.MultiSliceError) {
+	if currentReport == nil {
+		return
+	}
+
+	currentReport.mutex.Lock()
+	defer currentReport.mutex.Unlock()
+
+	gr := currentReport.group(group)
+	gr.Error = err.Error()
+	if multiErr != nil {
+		for _, se := range multiErr.Failed {
+			gr.slice(se.SliceID).Error = se.Err.Error()
+		}
+	}
+}
+
+// reportGroupResults records the given results of an operation executed for
+// multiple groups in the current report.
+func reportGroupResults(results []controller.GroupResult) {
+	if currentReport == nil {
+		return
+	}
+
+	currentReport.mutex.Lock()
+	defer currentReport.mutex.Unlock()
+
+	for _, result := range results {
+		gr := currentReport.group(result.Group)
+		gr.Duration = (result.Duration / time.Millisecond * time.Millisecond).String()
+		if result.Err != nil {
+			gr.Error = result.Err.Error()
+		}
+	}
+}
+
+// finishReport finishes the current report using the given exit code, and
+// records it in the audit log and the configured audit sinks. In case --report
+// is given, the state of all groups of the report is recorded as the state
+// after the operation, and the report is written to the file. Reports must not
+// fail operations, so errors are only logged.
+func finishReport(ctx context.Context, code int) {
+	if currentReport == nil {
+		return
+	}
+	r := currentReport
+	currentReport = nil
+
+	r.Finished = time.Now().UTC()
+	r.Duration = (r.Finished.Sub(r.Started) / time.Millisecond * time.Millisecond).String()
+	r.ExitCode = code
+	r.Succeeded = code == 0
+
+	for i := range r.Groups {
+		gr := &r.Groups[i]
+		gr.Succeeded = r.Succeeded && gr.Error == ""
+	}
+	if err := recordAudit(newStateStore, r); err != nil {
+		newLogger.Warning(ctx, "Failed to record operation in audit log. (%s)", err.Error())
+	}
+	streamAudit(ctx, auditSinks, r)
+	if globalFlags.Report == "" {
+		return
+	}
+
+	for i := range r.Groups {
+		gr := &r.Groups[i]
+		if usl, err := reportGroupStatus(ctx, gr.Group); err == nil {
+			out := newStatusOutput(gr.Group, usl)
+			gr.After = &out
+		}
+		if flapping, err := flappingSlices(ctx, gr.Group); err == nil {
+			var sliceIDs []string
+			for sliceID := range flapping {
+				sliceIDs = append(sliceIDs, sliceID)
+			}
+			sort.Strings(sliceIDs)
+			for _, sliceID := range sliceIDs {
+				gr.Flapping = append(gr.Flapping, flapping[sliceID])
+			}
+			if gr.After != nil {
+				markFlapping(gr.After, flapping)
+			}
+		}
+	}
+
+	if err := writeReport(fs, globalFlags.Report, r); err != nil {
+		newLogger.Warning(ctx, "Failed to write report '%s'. (%s)", globalFlags.Report, err.Error())
+	}
+}
+
+// writeReport writes the given report to the given file as indented JSON.
+func writeReport(fs filesystemspec.FileSystem, path string, r *report) error {
+	sort.Sort(groupReportsByName(r.Groups))
+	for _, gr := range r.Groups {
+		sort.Sort(sliceReportsByID(gr.Slices))
+	}
+
+	raw, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := fs.WriteFile(path, append(raw, '\n'), os.FileMode(0644)); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// group returns the report of the given group, which is added in case it is
+// not part of the report yet. The caller must hold the mutex of the report.
+func (r *report) group(group string) *groupReport {
+	for i := range r.Groups {
+		if r.Groups[i].Group == group {
+			return &r.Groups[i]
+		}
+	}
+	r.Groups = append(r.Groups, groupReport{Group: group})
+
+	return &r.Groups[len(r.Groups)-1]
+}
+
+// slice returns the report of the given slice, which is added in case it is
+// not part of the group report yet.
+func (gr *groupReport) slice(sliceID string) *sliceReport {
+	for i := range gr.Slices {
+		if gr.Slices[i].ID == sliceID {
+			return &gr.Slices[i]
+		}
+	}
+	gr.Slices = append(gr.Slices, sliceReport{ID: sliceID})
+
+	return &gr.Slices[len(gr.Slices)-1]
+}
+
+type groupReportsByName []groupReport
+
+func (g groupReportsByName) Len() int           { return len(g) }
+func (g groupReportsByName) Swap(i, j int)      { g[i], g[j] = g[j], g[i] }
+func (g groupReportsByName) Less(i, j int) bool { return g[i].Group < g[j].Group }
+
+type sliceReportsByID []sliceReport
+
+func (s sliceReportsByID) Len() int           { return len(s) }
+func (s sliceReportsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s sliceReportsByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/fake"
+)
+
+func Test_Report(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Without --report nothing is recorded.
+	reportProgress("app", []slicePhase{{SliceID: "1", Phase: phaseSubmitting}}, time.Now())
+	Expect(currentReport).To(BeNil())
+
+	started := time.Date(2016, 5, 4, 12, 0, 0, 0, time.UTC)
+	currentReport = &report{Operation: "up", Args: []string{"app", "2"}, Started: started}
+	defer func() { currentReport = nil }()
+
+	reportProgress("app", []slicePhase{{SliceID: "2", Phase: phaseSubmitting}, {SliceID: "1", Phase: phaseSubmitting}}, started.Add(1*time.Second))
+	reportProgress("app", []slicePhase{{SliceID: "2", Phase: phaseSubmitting}, {SliceID: "1", Phase: phaseStarting}}, started.Add(2*time.Second))
+	reportProgress("app", []slicePhase{{SliceID: "2", Phase: phaseFailed}, {SliceID: "1", Phase: phaseActive}}, started.Add(4*time.Second))
+
+	taskErr := controller.MultiSliceError{
+		Succeeded: []string{"1"},
+		Failed:    []controller.SliceError{{SliceID: "2", Err: errors.New("unit failed")}},
+	}
+	reportTaskError("app", taskErr, &taskErr)
+	reportGroupResults([]controller.GroupResult{{Group: "db", Duration: 1500 * time.Millisecond}})
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	Expect(writeReport(newFileSystem, "report.json", currentReport)).To(Succeed())
+
+	raw, err := newFileSystem.ReadFile("report.json")
+	Expect(err).To(BeNil())
+	var written map[string]interface{}
+	Expect(json.Unmarshal(raw, &written)).To(Succeed())
+	Expect(written["operation"]).To(Equal("up"))
+
+	// Groups and slices are sorted, and only phase changes are recorded.
+	r := currentReport
+	Expect(r.Groups).To(HaveLen(2))
+	Expect(r.Groups[0].Group).To(Equal("app"))
+	Expect(r.Groups[0].Error).To(Equal(taskErr.Error()))
+	Expect(r.Groups[1].Group).To(Equal("db"))
+	Expect(r.Groups[1].Duration).To(Equal("1.5s"))
+
+	slices := r.Groups[0].Slices
+	Expect(slices).To(HaveLen(2))
+	Expect(slices[0].ID).To(Equal("1"))
+	Expect(slices[0].Phases).To(Equal([]phaseReport{
+		{Phase: "submitting", Time: started.Add(1 * time.Second)},
+		{Phase: "starting", Time: started.Add(2 * time.Second)},
+		{Phase: "active", Time: started.Add(4 * time.Second)},
+	}))
+	Expect(slices[0].Duration).To(Equal("3s"))
+	Expect(slices[0].Error).To(BeEmpty())
+	Expect(slices[1].ID).To(Equal("2"))
+	Expect(slices[1].Phases).To(HaveLen(2))
+	Expect(slices[1].Error).To(Equal("unit failed"))
+}
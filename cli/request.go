@@ -2,10 +2,13 @@ package cli
 
 import (
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/coreos/fleet/unit"
 	"github.com/juju/errgo"
 
+	"github.com/giantswarm/inago/common"
 	"github.com/giantswarm/inago/controller"
 	"github.com/giantswarm/inago/file-system/spec"
 )
@@ -38,14 +41,89 @@ func readUnitFiles(fs filesystemspec.FileSystem, dir string) (map[string]string,
 	return unitFiles, nil
 }
 
+// localGroups returns the sorted names of all group directories found in the
+// current working directory. A directory is considered a group in case it
+// contains unit files prefixed with the directory name, and all of these unit
+// files can be parsed. Hidden directories are ignored.
+func localGroups(fs filesystemspec.FileSystem) ([]string, error) {
+	fileInfos, err := fs.ReadDir(".")
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	var groups []string
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() || strings.HasPrefix(fileInfo.Name(), ".") {
+			continue
+		}
+
+		ok, err := isLocalGroup(fs, fileInfo.Name())
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		if ok {
+			groups = append(groups, fileInfo.Name())
+		}
+	}
+	sort.Strings(groups)
+
+	return groups, nil
+}
+
+// isLocalGroup checks whether the given directory contains parsable unit files
+// of a group named like the directory.
+func isLocalGroup(fs filesystemspec.FileSystem, dir string) (bool, error) {
+	unitFiles, err := readUnitFiles(fs, dir)
+	if err != nil {
+		return false, maskAny(err)
+	}
+	if len(unitFiles) == 0 {
+		return false, nil
+	}
+
+	for name, content := range unitFiles {
+		if common.IsEnvFile(name) {
+			continue
+		}
+		if _, err := unit.NewUnitFile(content); err != nil {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 // extendRequestWithContent reads all unitfiles for the given group and returns
-// a new Request with the Units filled.
+// a new Request with the Units filled. Drop-ins of the unit files are merged
+// into them, see applyDropIns. The overlay of the environment given using --env
+// is merged over the unit files, see applyOverlay. Sidekick units announcing the units
+// are added in case the group manifest enables them. The labels of the group
+// manifest and the ones given using --label are added to the units, and the
+// ready states of the manifest are added to the request.
 func extendRequestWithContent(fs filesystemspec.FileSystem, req controller.Request) (controller.Request, error) {
 	unitFiles, err := readUnitFiles(fs, req.Group)
 	if err != nil {
 		return controller.Request{}, maskAny(err)
 	}
+	unitFiles, err = applyDropIns(fs, req.Group, req.Group, unitFiles)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+	unitFiles, err = applyOverlay(fs, req.Group, unitFiles)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
 	for name, content := range unitFiles {
+		if common.IsEnvFile(name) {
+			// Environment file templates are turned into units writing the
+			// rendered environment file for each slice.
+			envUnit, err := controller.NewEnvUnit(req.Group, name, content)
+			if err != nil {
+				return controller.Request{}, maskAny(err)
+			}
+			req.Units = append(req.Units, envUnit)
+			continue
+		}
 		req.Units = append(req.Units, controller.Unit{Name: name, Content: content})
 	}
 
@@ -53,6 +131,38 @@ func extendRequestWithContent(fs filesystemspec.FileSystem, req controller.Reque
 		return controller.Request{}, errgo.Newf("No unit files found for group '%s'", req.Group)
 	}
 
+	req.Signed, err = verifyGroupSignature(fs, req.Group)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+
+	manifest, err := readGroupManifest(fs, req.Group)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+	req, err = extendRequestWithAnnounceUnits(manifest, req)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+	labels, err := groupLabels(manifest, labelFlags.Labels)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+	req, err = req.WithLabels(labels)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+	if manifest.Version != "" {
+		req, err = req.WithSemVer(manifest.Version)
+		if err != nil {
+			return controller.Request{}, maskAnyf(invalidManifestError, "%s: %s", req.Group, err.Error())
+		}
+	}
+	req, err = extendRequestWithReadiness(fs, req)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+
 	return req, nil
 }
 
@@ -60,6 +170,7 @@ func extendRequestWithContent(fs filesystemspec.FileSystem, req controller.Reque
 // given sliceIDs.
 // "mygroup@123", "mygroup@456" => "mygroup", ["123", "456"]
 func parseGroupCLIArgs(args []string) (string, []string, error) {
+	args = resolveGroups(args)
 	group := strings.Split(args[0], "@")[0]
 	sliceIDs := []string{}
 
@@ -226,3 +226,74 @@ func Test_Request_ParseGroupCLIArgs_Error(t *testing.T) {
 		}
 	}
 }
+
+func Test_Request_LocalGroups(t *testing.T) {
+	testCases := []struct {
+		Setup    []testFileSystemSetup
+		Dirs     []string
+		Expected []string
+	}{
+		// Test that no groups are found in an empty directory.
+		{
+			Setup:    nil,
+			Dirs:     nil,
+			Expected: nil,
+		},
+		// Test that groups are found and sorted by name.
+		{
+			Setup: []testFileSystemSetup{
+				{FileName: "zgroup/zgroup-1.service", FileContent: []byte(givenSomeUnitFileContent()), FilePerm: os.FileMode(0644)},
+				{FileName: "agroup/agroup-1@.service", FileContent: []byte(givenSomeUnitFileContent()), FilePerm: os.FileMode(0644)},
+				{FileName: "agroup/agroup-2@.service", FileContent: []byte(givenSomeUnitFileContent()), FilePerm: os.FileMode(0644)},
+			},
+			Dirs:     nil,
+			Expected: []string{"agroup", "zgroup"},
+		},
+		// Test that files, empty directories, hidden directories and directories
+		// without unit files prefixed with the directory name are ignored.
+		{
+			Setup: []testFileSystemSetup{
+				{FileName: "README.md", FileContent: []byte("readme"), FilePerm: os.FileMode(0644)},
+				{FileName: ".git/git-1.service", FileContent: []byte(givenSomeUnitFileContent()), FilePerm: os.FileMode(0644)},
+				{FileName: "docs/README.md", FileContent: []byte("readme"), FilePerm: os.FileMode(0644)},
+				{FileName: "mygroup/mygroup-1.service", FileContent: []byte(givenSomeUnitFileContent()), FilePerm: os.FileMode(0644)},
+			},
+			Dirs:     []string{"empty"},
+			Expected: []string{"mygroup"},
+		},
+		// Test that directories containing unparsable unit files are ignored.
+		{
+			Setup: []testFileSystemSetup{
+				{FileName: "broken/broken-1.service", FileContent: []byte("[Unit\nDescription=broken"), FilePerm: os.FileMode(0644)},
+				{FileName: "mygroup/mygroup-1.service", FileContent: []byte(givenSomeUnitFileContent()), FilePerm: os.FileMode(0644)},
+			},
+			Dirs:     nil,
+			Expected: []string{"mygroup"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		newFileSystem := filesystemfake.NewFileSystem()
+
+		for _, setup := range testCase.Setup {
+			err := newFileSystem.WriteFile(setup.FileName, setup.FileContent, setup.FilePerm)
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+		}
+		for _, dir := range testCase.Dirs {
+			err := newFileSystem.MkdirAll(dir, os.FileMode(0755))
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+		}
+
+		groups, err := localGroups(newFileSystem)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if !reflect.DeepEqual(groups, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", groups)
+		}
+	}
+}
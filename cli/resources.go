@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+)
+
+// resourceRow is a row of the resource table, showing the resources a unit
+// uses on a machine.
+type resourceRow struct {
+	Unit    string
+	Machine string
+	Usage   fleet.ResourceUsage
+}
+
+// newResourceSampler creates the ResourceSampler used by status --resources,
+// connecting to machines using the same SSH configuration as --tunnel does.
+func newResourceSampler() fleet.ResourceSampler {
+	newSamplerConfig := fleet.DefaultSSHResourceSamplerConfig()
+	newSamplerConfig.KnownHostsFile = globalFlags.SSHKnownHostsFile
+	newSamplerConfig.Logger = newLogger
+	newSamplerConfig.StrictHostKeyChecking = globalFlags.SSHStrictHostKeyChecking
+	newSamplerConfig.Timeout = globalFlags.SSHTimeout
+	newSamplerConfig.Tunnel = globalFlags.Tunnel
+	newSamplerConfig.Username = globalFlags.SSHUsername
+
+	return fleet.NewSSHResourceSampler(newSamplerConfig)
+}
+
+// sampleResources samples the resources used by the given units on the
+// machines they are scheduled on, one machine at a time. Machines failing to
+// answer are reported as warnings, so that the usage of the other machines
+// can still be shown.
+func sampleResources(ctx context.Context, sampler fleet.ResourceSampler, usl controller.UnitStatusList) []resourceRow {
+	unitsByIP := map[string][]string{}
+	var ips []string
+	for _, us := range usl {
+		for _, ms := range us.Machine {
+			if ms.Vanished || ms.IP == nil {
+				continue
+			}
+			ip := ms.IP.String()
+			if _, ok := unitsByIP[ip]; !ok {
+				ips = append(ips, ip)
+			}
+			unitsByIP[ip] = append(unitsByIP[ip], us.Name)
+		}
+	}
+	sort.Strings(ips)
+
+	var rows []resourceRow
+	for _, ip := range ips {
+		usage, err := sampler.SampleResources(ctx, ip, unitsByIP[ip])
+		if err != nil {
+			newLogger.Warning(ctx, "Failed to sample resources on machine '%s'. (%s)", ip, err.Error())
+			continue
+		}
+		for _, name := range unitsByIP[ip] {
+			if u, ok := usage[name]; ok {
+				rows = append(rows, resourceRow{Unit: name, Machine: ip, Usage: u})
+			}
+		}
+	}
+
+	return rows
+}
+
+// createResourceTable creates the table rows showing the resources used by
+// each unit, followed by the resources used by the group on each machine, so
+// that unbalanced placement stands out.
+func createResourceTable(rows []resourceRow) []string {
+	data := []string{"Unit | Machine | CPU | Memory"}
+	for _, row := range rows {
+		data = append(data, fmt.Sprintf("%s | %s | %.1f%% | %s", row.Unit, row.Machine, row.Usage.CPU, formatMemory(row.Usage.Memory)))
+	}
+
+	totals := map[string]fleet.ResourceUsage{}
+	var machines []string
+	for _, row := range rows {
+		total, ok := totals[row.Machine]
+		if !ok {
+			machines = append(machines, row.Machine)
+		}
+		total.CPU += row.Usage.CPU
+		total.Memory += row.Usage.Memory
+		totals[row.Machine] = total
+	}
+	sort.Strings(machines)
+
+	data = append(data, "", "Machine | Units | CPU | Memory")
+	for _, machine := range machines {
+		units := 0
+		for _, row := range rows {
+			if row.Machine == machine {
+				units++
+			}
+		}
+		total := totals[machine]
+		data = append(data, fmt.Sprintf("%s | %d | %.1f%% | %s", machine, units, total.CPU, formatMemory(total.Memory)))
+	}
+
+	return data
+}
+
+// formatMemory formats the given number of bytes using binary units.
+func formatMemory(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+
+	value := float64(bytes)
+	suffixes := []string{"K", "M", "G", "T"}
+	var suffix string
+	for _, suffix = range suffixes {
+		value /= unit
+		if value < unit {
+			break
+		}
+	}
+
+	return fmt.Sprintf("%.1f%s", value, suffix)
+}
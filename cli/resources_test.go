@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/logging"
+)
+
+type staticResourceSampler map[string]map[string]fleet.ResourceUsage
+
+func (s staticResourceSampler) SampleResources(ctx context.Context, machineIP string, units []string) (map[string]fleet.ResourceUsage, error) {
+	usage, ok := s[machineIP]
+	if !ok {
+		return nil, maskAnyf(commandFailedError, "machine not reachable")
+	}
+	return usage, nil
+}
+
+func TestResources_sampleResources(t *testing.T) {
+	RegisterTestingT(t)
+
+	newLogger = logging.NewLogger(logging.DefaultConfig())
+	usl := controller.UnitStatusList{
+		{Name: "mygroup-app@1.service", Machine: []fleet.MachineStatus{{ID: "a", IP: net.ParseIP("10.0.0.1")}}},
+		{Name: "mygroup-app@2.service", Machine: []fleet.MachineStatus{{ID: "a", IP: net.ParseIP("10.0.0.1")}}},
+		{Name: "mygroup-app@3.service", Machine: []fleet.MachineStatus{{ID: "b", IP: net.ParseIP("10.0.0.2")}}},
+		{Name: "mygroup-app@4.service", Machine: []fleet.MachineStatus{{ID: "c", IP: net.ParseIP("10.0.0.3")}}},
+		{Name: "mygroup-app@5.service", Machine: []fleet.MachineStatus{{ID: "d", Vanished: true}}},
+	}
+	sampler := staticResourceSampler{
+		"10.0.0.1": {
+			"mygroup-app@1.service": {CPU: 50, Memory: 2048},
+			"mygroup-app@2.service": {CPU: 25.5, Memory: 1024},
+		},
+		"10.0.0.2": {
+			"mygroup-app@3.service": {CPU: 1, Memory: 3 * 1024 * 1024},
+		},
+	}
+
+	rows := sampleResources(context.Background(), sampler, usl)
+	Expect(rows).To(HaveLen(3))
+
+	Expect(createResourceTable(rows)).To(Equal([]string{
+		"Unit | Machine | CPU | Memory",
+		"mygroup-app@1.service | 10.0.0.1 | 50.0% | 2.0K",
+		"mygroup-app@2.service | 10.0.0.1 | 25.5% | 1.0K",
+		"mygroup-app@3.service | 10.0.0.2 | 1.0% | 3.0M",
+		"",
+		"Machine | Units | CPU | Memory",
+		"10.0.0.1 | 2 | 75.5% | 3.0K",
+		"10.0.0.2 | 1 | 1.0% | 3.0M",
+	}))
+}
+
+func TestResources_formatMemory(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(formatMemory(512)).To(Equal("512B"))
+	Expect(formatMemory(1536)).To(Equal("1.5K"))
+	Expect(formatMemory(2 * 1024 * 1024 * 1024)).To(Equal("2.0G"))
+}
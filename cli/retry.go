@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	retryFlags struct {
+		Attempts int
+		Window   time.Duration
+		Resubmit bool
+	}
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{startCmd, upCmd, updateCmd} {
+		cmd.PersistentFlags().IntVar(&retryFlags.Attempts, "retry", 0, "number of times slices not running after being started are started again")
+		cmd.PersistentFlags().DurationVar(&retryFlags.Window, "retry-window", 0, "time slices have to be running before they are started again (the wait timeout by default)")
+		cmd.PersistentFlags().BoolVar(&retryFlags.Resubmit, "retry-resubmit", false, "destroy and submit slices again before starting them again")
+	}
+}
+
+// withRetry configures the given request to retry starting slices as given
+// using --retry, --retry-window and --retry-resubmit.
+func withRetry(req controller.Request) controller.Request {
+	req.Retry = controller.RetryOptions{
+		Attempts: retryFlags.Attempts,
+		Window:   retryFlags.Window,
+		Resubmit: retryFlags.Resubmit,
+	}
+
+	return req
+}
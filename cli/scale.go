@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/task"
+)
+
+var (
+	scaleCmd = &cobra.Command{
+		Use:   "scale <group> [scale]",
+		Short: "Scale a group",
+		Long:  "Submit and start, or destroy slices, until the group runs the given number of slices, and record it as the group's desired scale. Without scale, the desired and actual number of slices are printed. Bringing a group up without scale uses its desired scale",
+		Run:   reportingRun("scale", scaleRun),
+	}
+)
+
+func scaleRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting scale")
+
+	if len(args) == 0 || len(args) > 2 {
+		cmd.Help()
+		exit(1)
+	}
+	group := resolveGroup(args[0])
+
+	existing, err := existingSliceIDs(group)
+	handleScaleCmdError(err)
+
+	if len(args) == 1 {
+		desired, err := newController.DesiredScale(newCtx, group)
+		if !controller.IsDesiredScaleNotFound(err) {
+			handleScaleCmdError(err)
+		}
+		printResult(result{Kind: "scale", Rows: createScaleSummary(group, desired, len(existing))})
+		return
+	}
+
+	scale, err := strconv.Atoi(args[1])
+	handleScaleCmdError(err)
+	var surplus []string
+	if scale < len(existing) {
+		pinned, err := newController.PinnedSlices(newCtx, group)
+		handleScaleCmdError(err)
+		surplus, err = surplusSliceIDs(existing, pinned, scale)
+		handleScaleCmdError(err)
+	}
+	var submitReq controller.Request
+	if scale > len(existing) {
+		submitReq, err = createSubmitRequest(fs, group, scale-len(existing))
+		handleScaleCmdError(err)
+		// New slices have to be able to run side by side with the existing ones.
+		if ok, err := controller.ValidateSliceAwareness(submitReq); scale > 1 && !ok {
+			newLogger.Error(newCtx, "Failed to scale group '%s' to %d slices. %s", group, scale, FormatValidationError(err.(controller.ValidationError)))
+			exit(1)
+		}
+	}
+	err = newController.SetDesiredScale(newCtx, group, scale)
+	handleScaleCmdError(err)
+
+	switch {
+	case scale > len(existing):
+		req := submitReq
+		taskObject, err := newController.Submit(newCtx, req)
+		handleScaleCmdError(err)
+		maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+			Request:    req,
+			Descriptor: "submit",
+			TaskID:     taskObject.ID,
+		})
+
+		// Starting slices already running does not change them.
+		req, err = extendRequestWithReadiness(fs, controller.NewRequest(controller.RequestConfig{Group: group}))
+		handleScaleCmdError(err)
+		req, err = newController.ExtendWithExistingSliceIDs(req)
+		handleScaleCmdError(err)
+		taskObject, err = newController.Start(newCtx, req)
+		handleScaleCmdError(err)
+		maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+			Request:    req,
+			Descriptor: "start",
+			NoBlock:    globalFlags.NoBlock,
+			TaskID:     taskObject.ID,
+		})
+	case scale < len(existing):
+		newRequestConfig := controller.DefaultRequestConfig()
+		newRequestConfig.Group = group
+		newRequestConfig.SliceIDs = surplus
+		req := controller.NewRequest(newRequestConfig)
+		taskObject, err := newController.Destroy(newCtx, req)
+		handleScaleCmdError(err)
+		maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+			Request:    req,
+			Descriptor: "destroy",
+			NoBlock:    globalFlags.NoBlock,
+			TaskID:     taskObject.ID,
+		})
+	default:
+		newLogger.Info(newCtx, "Group '%s' already runs %d slices.", group, scale)
+	}
+}
+
+// existingSliceIDs returns the IDs of all slices of the given group deployed
+// to the cluster.
+func existingSliceIDs(group string) ([]string, error) {
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = group
+	req, err := newController.ExtendWithExistingSliceIDs(controller.NewRequest(newRequestConfig))
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return req.SliceIDs, nil
+}
+
+// surplusSliceIDs returns the IDs of the slices to destroy in order to scale
+// the given slices down to the given scale. The slices sorted last are
+// destroyed, so that numbered slice IDs keep the lowest numbers. Pinned slices
+// are never destroyed. In case too many slices are pinned to reach the given
+// scale, an error is returned.
+func surplusSliceIDs(sliceIDs, pinned []string, scale int) ([]string, error) {
+	if scale >= len(sliceIDs) {
+		return nil, nil
+	}
+
+	var unpinned []string
+	for _, sliceID := range sliceIDs {
+		if !containsString(pinned, sliceID) {
+			unpinned = append(unpinned, sliceID)
+		}
+	}
+	sort.Strings(unpinned)
+
+	n := len(sliceIDs) - scale
+	if n > len(unpinned) {
+		return nil, maskAnyf(invalidArgumentsError, "cannot scale down to %d slices, %d slices are pinned", scale, len(sliceIDs)-len(unpinned))
+	}
+
+	return unpinned[len(unpinned)-n:], nil
+}
+
+// createScaleSummary creates the table rows comparing the desired and actual
+// scale of the given group. A desired scale of zero means none is recorded.
+func createScaleSummary(group string, desired, actual int) []string {
+	desiredCell := "-"
+	if desired > 0 {
+		desiredCell = strconv.Itoa(desired)
+	}
+
+	return []string{
+		"Group | Desired | Actual",
+		fmt.Sprintf("%s | %s | %d", group, desiredCell, actual),
+	}
+}
+
+// upSubmitRequest creates the request submitting the slices needed to bring
+// the given local group up. In case a scale is given, that many slices are
+// submitted, and the resulting number of slices is recorded as the group's
+// desired scale. Otherwise the slices missing to reach the recorded desired
+// scale are submitted, or a single slice in case none is recorded. The
+// returned bool is false in case no slice needs to be submitted.
+func upSubmitRequest(ctx context.Context, group string, scale int, scaleGiven bool) (controller.Request, bool, error) {
+	req, err := createSubmitRequest(fs, group, 1)
+	if err != nil {
+		return controller.Request{}, false, maskAny(err)
+	}
+	if !strings.Contains(req.Units[0].Name, "@") {
+		// Groups without slices cannot be scaled.
+		req, err = withDesiredSlices(req, scale)
+		if err != nil {
+			return controller.Request{}, false, maskAny(err)
+		}
+		return req, true, nil
+	}
+
+	existing, err := existingSliceIDs(group)
+	if err != nil {
+		return controller.Request{}, false, maskAny(err)
+	}
+
+	if scaleGiven {
+		if err := newController.SetDesiredScale(ctx, group, len(existing)+scale); err != nil {
+			return controller.Request{}, false, maskAny(err)
+		}
+	} else {
+		desired, err := newController.DesiredScale(ctx, group)
+		if controller.IsDesiredScaleNotFound(err) {
+			desired = len(existing) + 1
+		} else if err != nil {
+			return controller.Request{}, false, maskAny(err)
+		}
+		scale = desired - len(existing)
+		if scale < 1 {
+			newLogger.Info(ctx, "Group '%s' already runs %d of %d desired slices.", group, len(existing), desired)
+			return controller.Request{}, false, nil
+		}
+	}
+
+	req, err = withDesiredSlices(req, scale)
+	if err != nil {
+		return controller.Request{}, false, maskAny(err)
+	}
+
+	return req, true, nil
+}
+
+// submitUpGroup submits the slices needed to bring the given local group up.
+// See upSubmitRequest.
+func submitUpGroup(ctx context.Context, group string, scale int, scaleGiven bool) error {
+	req, ok, err := upSubmitRequest(ctx, group, scale, scaleGiven)
+	if err != nil {
+		return maskAny(err)
+	}
+	if !ok {
+		return nil
+	}
+
+	return waitForGroupTask(ctx, func() (*task.Task, error) {
+		return newController.Submit(ctx, req)
+	})
+}
+
+func handleScaleCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
+
+func containsString(list []string, item string) bool {
+	for _, l := range list {
+		if l == item {
+			return true
+		}
+	}
+
+	return false
+}
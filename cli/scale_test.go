@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_Scale_surplusSliceIDs(t *testing.T) {
+	RegisterTestingT(t)
+
+	sliceIDs := []string{"3", "1", "2"}
+	Expect(surplusSliceIDs(sliceIDs, nil, 1)).To(Equal([]string{"2", "3"}))
+	Expect(surplusSliceIDs(sliceIDs, nil, 3)).To(BeNil())
+	Expect(surplusSliceIDs(sliceIDs, nil, 5)).To(BeNil())
+	Expect(sliceIDs).To(Equal([]string{"3", "1", "2"}))
+}
+
+func Test_Scale_surplusSliceIDs_Pinned(t *testing.T) {
+	RegisterTestingT(t)
+
+	sliceIDs := []string{"1", "2", "3", "4"}
+	Expect(surplusSliceIDs(sliceIDs, []string{"4"}, 2)).To(Equal([]string{"2", "3"}))
+	Expect(surplusSliceIDs(sliceIDs, []string{"1", "3"}, 3)).To(Equal([]string{"4"}))
+
+	_, err := surplusSliceIDs(sliceIDs, []string{"2", "3", "4"}, 2)
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+}
+
+func Test_Scale_createScaleSummary(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(createScaleSummary("myapp", 3, 2)).To(Equal([]string{
+		"Group | Desired | Actual",
+		"myapp | 3 | 2",
+	}))
+	Expect(createScaleSummary("myapp", 0, 2)).To(Equal([]string{
+		"Group | Desired | Actual",
+		"myapp | - | 2",
+	}))
+}
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"crypto"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/file-system/spec"
+	"github.com/giantswarm/inago/signature"
+)
+
+// groupSignatureFile is the name of the optional file within a group directory
+// containing the detached signature of the group's unit files and manifest.
+// Like the manifest, it is not prefixed with the group name.
+const groupSignatureFile = "group.sig"
+
+var (
+	signFlags struct {
+		Key string
+	}
+
+	signCmd = &cobra.Command{
+		Use:   "sign <group|archive>",
+		Short: "Sign a group",
+		Long:  "Sign the unit files and manifest of a group using a private key, and write the signature to group.sig within the group directory. Signing an archive writes the signature next to it, to <archive>.sig. Signatures are verified using --verify-key",
+		Run:   signRun,
+	}
+)
+
+func init() {
+	signCmd.PersistentFlags().StringVar(&signFlags.Key, "key", "", "PEM encoded RSA or ECDSA private key used to sign")
+}
+
+func signRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting sign")
+
+	if len(args) != 1 {
+		cmd.Help()
+		exit(1)
+	}
+	if signFlags.Key == "" {
+		handleSignCmdError(maskAnyf(invalidArgumentsError, "--key must be given"))
+	}
+
+	raw, err := fs.ReadFile(signFlags.Key)
+	handleSignCmdError(err)
+	key, err := signature.ParsePrivateKey(raw)
+	handleSignCmdError(err)
+
+	var files map[string][]byte
+	var out string
+	if isGroupArchive(args[0]) {
+		raw, err := fs.ReadFile(args[0])
+		handleSignCmdError(err)
+		files, err = readArchiveFiles(raw)
+		handleSignCmdError(err)
+		delete(files, archiveSignatureFile)
+		out = args[0] + ".sig"
+	} else {
+		files, err = groupDefinitionFiles(fs, args[0])
+		handleSignCmdError(err)
+		out = filepath.Join(args[0], groupSignatureFile)
+	}
+
+	sig, err := signature.Sign(key, files)
+	handleSignCmdError(err)
+	err = fs.WriteFile(out, sig, os.FileMode(0644))
+	handleSignCmdError(err)
+
+	newLogger.Info(newCtx, "Succeeded to sign '%s', signature written to '%s'.", args[0], out)
+}
+
+// groupDefinitionFiles returns the files defining the given local group, i.e.
+// its unit files, its manifest and the unit files of its overlays, including
+// their drop-ins, keyed by their names. Drop-ins and files of overlays are
+// keyed by their path within the group directory, e.g.
+// "overlays/staging/mygroup-main@.service". These are the files covered by the
+// signature of a group.
+func groupDefinitionFiles(fs filesystemspec.FileSystem, group string) (map[string][]byte, error) {
+	unitFiles, err := readUnitFiles(fs, group)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	files := map[string][]byte{}
+	for name, content := range unitFiles {
+		files[name] = []byte(content)
+	}
+	dropIns, err := readDropInFiles(fs, group, group)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	for path, content := range dropIns {
+		files[path] = []byte(content)
+	}
+
+	fileInfos, err := fs.ReadDir(group)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	for _, fileInfo := range fileInfos {
+		if fileInfo.Name() != groupManifestFile || fileInfo.IsDir() {
+			continue
+		}
+		raw, err := fs.ReadFile(filepath.Join(group, groupManifestFile))
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		files[groupManifestFile] = raw
+	}
+
+	envs, err := overlayEnvs(fs, group)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	for _, env := range envs {
+		overlayFiles, _, err := readOverlayFiles(fs, group, env)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		for name, content := range overlayFiles {
+			files[filepath.Join(groupOverlaysDir, env, name)] = []byte(content)
+		}
+		dropIns, err := readDropInFiles(fs, overlayDir(group, env), group)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		for path, content := range dropIns {
+			files[filepath.Join(groupOverlaysDir, env, path)] = []byte(content)
+		}
+	}
+
+	return files, nil
+}
+
+// verifyKey returns the public key given using --verify-key, or nil in case
+// signatures are not verified.
+func verifyKey(fs filesystemspec.FileSystem) (crypto.PublicKey, error) {
+	if globalFlags.VerifyKey == "" {
+		return nil, nil
+	}
+
+	raw, err := fs.ReadFile(globalFlags.VerifyKey)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	key, err := signature.ParsePublicKey(raw)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return key, nil
+}
+
+// verifyGroupSignature verifies the signature of the given local group using
+// the key given using --verify-key. It returns false in case no key is given.
+// In case the group is not signed, an error that you can identify using
+// IsSignatureNotFound is returned. In case the signature does not match, an
+// error that you can identify using signature.IsInvalidSignature is returned.
+func verifyGroupSignature(fs filesystemspec.FileSystem, group string) (bool, error) {
+	key, err := verifyKey(fs)
+	if err != nil {
+		return false, maskAny(err)
+	} else if key == nil {
+		return false, nil
+	}
+
+	files, err := groupDefinitionFiles(fs, group)
+	if err != nil {
+		return false, maskAny(err)
+	}
+	ok, err := fileExists(fs, filepath.Join(group, groupSignatureFile))
+	if err != nil {
+		return false, maskAny(err)
+	} else if !ok {
+		return false, maskAnyf(signatureNotFoundError, "group '%s' has no %s", group, groupSignatureFile)
+	}
+	sig, err := fs.ReadFile(filepath.Join(group, groupSignatureFile))
+	if err != nil {
+		return false, maskAny(err)
+	}
+	if err := signature.Verify(key, files, sig); err != nil {
+		return false, maskAnyf(err, "group '%s'", group)
+	}
+
+	return true, nil
+}
+
+// verifyArchiveSignature verifies the signature of the group archive read from
+// the given path using the key given using --verify-key, like
+// verifyGroupSignature. The signature embedded in the archive is used, or the
+// one in <archive>.sig in case there is none.
+func verifyArchiveSignature(fs filesystemspec.FileSystem, archive string, raw []byte) (bool, error) {
+	key, err := verifyKey(fs)
+	if err != nil {
+		return false, maskAny(err)
+	} else if key == nil {
+		return false, nil
+	}
+
+	files, err := readArchiveFiles(raw)
+	if err != nil {
+		return false, maskAny(err)
+	}
+	sig, ok := files[archiveSignatureFile]
+	delete(files, archiveSignatureFile)
+	if !ok {
+		ok, err := fileExists(fs, archive+".sig")
+		if err != nil {
+			return false, maskAny(err)
+		} else if !ok {
+			return false, maskAnyf(signatureNotFoundError, "archive '%s' has no embedded signature and no %s.sig", archive, archive)
+		}
+		sig, err = fs.ReadFile(archive + ".sig")
+		if err != nil {
+			return false, maskAny(err)
+		}
+	}
+	if err := signature.Verify(key, files, sig); err != nil {
+		return false, maskAnyf(err, "archive '%s'", archive)
+	}
+
+	return true, nil
+}
+
+// fileExists checks whether the given regular file exists. The directory of
+// the file is listed, since file systems do not agree on the error of reading
+// missing files.
+func fileExists(fs filesystemspec.FileSystem, name string) (bool, error) {
+	fileInfos, err := fs.ReadDir(filepath.Dir(name))
+	if err != nil {
+		return false, maskAny(err)
+	}
+	for _, fileInfo := range fileInfos {
+		if fileInfo.Name() == filepath.Base(name) && !fileInfo.IsDir() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func handleSignCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/file-system/fake"
+	"github.com/giantswarm/inago/file-system/spec"
+	"github.com/giantswarm/inago/signature"
+)
+
+// givenSigningKey writes the public key of a new key pair to the given file
+// system as inago.pub, and returns the private key.
+func givenSigningKey(newFileSystem filesystemspec.FileSystem) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(BeNil())
+	raw, err := x509.MarshalPKIXPublicKey(key.Public())
+	Expect(err).To(BeNil())
+	err = newFileSystem.WriteFile("inago.pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: raw}), os.FileMode(0644))
+	Expect(err).To(BeNil())
+
+	return key
+}
+
+func Test_Signature_verifyGroupSignature(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	err := newFileSystem.WriteFile("mygroup/mygroup-1.service", []byte(givenSomeUnitFileContent()), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"version": "1.0.0"}`), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	key := givenSigningKey(newFileSystem)
+
+	// Without --verify-key, groups are not verified.
+	signed, err := verifyGroupSignature(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(signed).To(BeFalse())
+
+	globalFlags.VerifyKey = "inago.pub"
+	defer func() { globalFlags.VerifyKey = "" }()
+
+	_, err = verifyGroupSignature(newFileSystem, "mygroup")
+	Expect(IsSignatureNotFound(err)).To(BeTrue())
+
+	files, err := groupDefinitionFiles(newFileSystem, "mygroup")
+	Expect(err).To(BeNil())
+	Expect(files).To(HaveLen(2))
+	sig, err := signature.Sign(key, files)
+	Expect(err).To(BeNil())
+	err = newFileSystem.WriteFile("mygroup/group.sig", sig, os.FileMode(0644))
+	Expect(err).To(BeNil())
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = "mygroup"
+	req, err := extendRequestWithContent(newFileSystem, controller.NewRequest(newRequestConfig))
+	Expect(err).To(BeNil())
+	Expect(req.Signed).To(BeTrue())
+
+	// Changing the manifest breaks the signature.
+	err = newFileSystem.WriteFile("mygroup/group.json", []byte(`{"version": "2.0.0"}`), os.FileMode(0644))
+	Expect(err).To(BeNil())
+	_, err = extendRequestWithContent(newFileSystem, controller.NewRequest(newRequestConfig))
+	Expect(signature.IsInvalidSignature(err)).To(BeTrue())
+}
+
+func Test_Signature_verifyArchiveSignature(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFileSystem := filesystemfake.NewFileSystem()
+	key := givenSigningKey(newFileSystem)
+	units := []controller.Unit{{Name: "mygroup-foo@.service", Content: "[Service]\nExecStart=/bin/foo\n"}}
+	metadata := groupArchiveMetadata{Group: "mygroup", Scale: 1}
+
+	globalFlags.VerifyKey = "inago.pub"
+	defer func() { globalFlags.VerifyKey = "" }()
+
+	// Embedded signatures are verified.
+	embedded := bytes.NewBuffer(nil)
+	err := writeGroupArchive(embedded, units, metadata, key)
+	Expect(err).To(BeNil())
+	signed, err := verifyArchiveSignature(newFileSystem, "mygroup.tar.gz", embedded.Bytes())
+	Expect(err).To(BeNil())
+	Expect(signed).To(BeTrue())
+
+	// Archives without embedded signature need a detached one.
+	unsigned := bytes.NewBuffer(nil)
+	err = writeGroupArchive(unsigned, units, metadata, nil)
+	Expect(err).To(BeNil())
+	_, err = verifyArchiveSignature(newFileSystem, "mygroup.tar.gz", unsigned.Bytes())
+	Expect(IsSignatureNotFound(err)).To(BeTrue())
+
+	files, err := readArchiveFiles(unsigned.Bytes())
+	Expect(err).To(BeNil())
+	sig, err := signature.Sign(key, files)
+	Expect(err).To(BeNil())
+	err = newFileSystem.WriteFile("mygroup.tar.gz.sig", sig, os.FileMode(0644))
+	Expect(err).To(BeNil())
+	signed, err = verifyArchiveSignature(newFileSystem, "mygroup.tar.gz", unsigned.Bytes())
+	Expect(err).To(BeNil())
+	Expect(signed).To(BeTrue())
+
+	// A signature of other files does not match.
+	other := bytes.NewBuffer(nil)
+	err = writeGroupArchive(other, []controller.Unit{{Name: "mygroup-bar@.service"}}, metadata, nil)
+	Expect(err).To(BeNil())
+	_, err = verifyArchiveSignature(newFileSystem, "mygroup.tar.gz", other.Bytes())
+	Expect(signature.IsInvalidSignature(err)).To(BeTrue())
+}
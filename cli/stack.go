@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/stack"
+)
+
+var (
+	stackCmd = &cobra.Command{
+		Use:   "stack",
+		Short: "Manage a stack of groups",
+		Long:  "Bring up, bring down or show the status of all groups described by a stack file, respecting the dependencies between them",
+		Run:   stackRun,
+	}
+
+	stackUpCmd = &cobra.Command{
+		Use:   "up <stackfile>",
+		Short: "Bring a stack up",
+		Long:  "Submit and start all groups of a stack. A group is brought up once all groups it depends on are up. Groups not depending on each other are brought up in parallel. Groups already deployed are only started",
+		Run:   notifyingRun("stack up", stackUpRun),
+	}
+
+	stackDownCmd = &cobra.Command{
+		Use:   "down <stackfile>",
+		Short: "Bring a stack down",
+		Long:  "Stop and destroy all groups of a stack. A group is brought down once all groups depending on it are down",
+		Run:   notifyingRun("stack down", stackDownRun),
+	}
+
+	stackStatusCmd = &cobra.Command{
+		Use:   "status <stackfile>",
+		Short: "Get stack status",
+		Long:  "Print how many slices of each group of a stack are deployed and active",
+		Run:   stackStatusRun,
+	}
+
+	stackFlags struct {
+		Parallelism int
+	}
+)
+
+func init() {
+	stackCmd.PersistentFlags().IntVar(&stackFlags.Parallelism, "parallelism", 4, "maximum number of groups operated on at the same time")
+
+	stackCmd.AddCommand(stackUpCmd)
+	stackCmd.AddCommand(stackDownCmd)
+	stackCmd.AddCommand(stackStatusCmd)
+}
+
+func stackRun(cmd *cobra.Command, args []string) {
+	cmd.Help()
+}
+
+func stackUpRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting stack up")
+
+	s := loadStack(cmd, args)
+	levels, err := s.Levels()
+	handleStackCmdError(err)
+
+	runStackLevels("bring up", levels, func(ctx context.Context, group string) error {
+		deployed, err := isDeployed(group)
+		if err != nil {
+			return maskAny(err)
+		}
+		if !deployed {
+			g, _ := s.Group(group)
+			if err := submitGroup(ctx, group, g.Scale); err != nil {
+				return maskAny(err)
+			}
+		}
+		return existingGroupAction(newController.Start)(ctx, group)
+	})
+}
+
+func stackDownRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting stack down")
+
+	s := loadStack(cmd, args)
+	levels, err := s.Levels()
+	handleStackCmdError(err)
+
+	var reversed [][]string
+	for i := len(levels) - 1; i >= 0; i-- {
+		reversed = append(reversed, levels[i])
+	}
+
+	runStackLevels("bring down", reversed, func(ctx context.Context, group string) error {
+		deployed, err := isDeployed(group)
+		if err != nil {
+			return maskAny(err)
+		}
+		if !deployed {
+			return nil
+		}
+		if err := existingGroupAction(newController.Stop)(ctx, group); err != nil {
+			return maskAny(err)
+		}
+		return existingGroupAction(newController.Destroy)(ctx, group)
+	})
+}
+
+func stackStatusRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting stack status")
+
+	s := loadStack(cmd, args)
+	levels, err := s.Levels()
+	handleStackCmdError(err)
+
+	var groups []stack.Group
+	statuses := map[string][]fleet.UnitStatus{}
+	for _, level := range levels {
+		for _, name := range level {
+			g, _ := s.Group(name)
+			groups = append(groups, g)
+
+			newRequestConfig := controller.DefaultRequestConfig()
+			newRequestConfig.Group = name
+			usl, err := newController.GetStatus(newCtx, controller.NewRequest(newRequestConfig))
+			if controller.IsUnitNotFound(err) {
+				continue
+			}
+			handleStackCmdError(err)
+			statuses[name] = usl
+		}
+	}
+
+	printResult(result{Kind: "stack", Rows: createStackStatus(groups, statuses)})
+}
+
+// loadStack reads and parses the stack file given as only argument. Group
+// directories are located next to the stack file, so the working directory is
+// changed to the directory containing it.
+func loadStack(cmd *cobra.Command, args []string) stack.Stack {
+	if len(args) != 1 || args[0] == "" {
+		cmd.Help()
+		exit(1)
+	}
+
+	raw, err := fs.ReadFile(args[0])
+	handleStackCmdError(err)
+	s, err := stack.Parse(raw)
+	handleStackCmdError(err)
+
+	err = os.Chdir(filepath.Dir(args[0]))
+	handleStackCmdError(err)
+
+	return s
+}
+
+// runStackLevels executes the given action for all groups of the given levels,
+// one level after the other. Groups of the same level are handled in parallel
+// using controller.RunGroups. In case any action of a level fails, the
+// following levels are skipped. Once done, a summary of the results is
+// printed.
+func runStackLevels(descriptor string, levels [][]string, action func(ctx context.Context, group string) error) {
+	var results []controller.GroupResult
+	for i, level := range levels {
+		newLogger.Info(newCtx, "Going to %s groups %v.", descriptor, level)
+
+		reportGroupsBefore(newCtx, level)
+		levelResults := controller.RunGroups(newCtx, level, stackFlags.Parallelism, action)
+		reportGroupResults(levelResults)
+		results = append(results, levelResults...)
+
+		var failed []string
+		var failedErr error
+		for _, result := range levelResults {
+			if result.Err != nil {
+				failed = append(failed, result.Group)
+				if failedErr == nil {
+					failedErr = result.Err
+				}
+			}
+		}
+		if len(failed) > 0 {
+			printResult(result{Kind: "groups", Rows: createMultiGroupSummary(results)})
+			var skipped []string
+			for _, l := range levels[i+1:] {
+				skipped = append(skipped, l...)
+			}
+			if len(skipped) > 0 {
+				newLogger.Error(newCtx, "Failed to %s groups %v. Skipped groups %v.", descriptor, failed, skipped)
+			} else {
+				newLogger.Error(newCtx, "Failed to %s groups %v.", descriptor, failed)
+			}
+			diagnoseConnection(newCtx, failedErr)
+			exit(1)
+		}
+	}
+
+	printResult(result{Kind: "groups", Rows: createMultiGroupSummary(results)})
+	newLogger.Info(newCtx, "Succeeded to %s %d groups.", descriptor, len(results))
+}
+
+// createStackStatus renders the given groups as table rows. The given unit
+// states are looked up by group name. Groups without unit states are not
+// deployed.
+func createStackStatus(groups []stack.Group, statuses map[string][]fleet.UnitStatus) []string {
+	rows := []string{"Group | Depends On | Scale | Slices | Active", ""}
+	for _, g := range groups {
+		dependsOn := "-"
+		if len(g.DependsOn) > 0 {
+			dependsOn = strings.Join(g.DependsOn, ",")
+		}
+
+		usl, ok := statuses[g.Name]
+		if !ok {
+			rows = append(rows, fmt.Sprintf("%s | %s | %d | - | -", g.Name, dependsOn, g.Scale))
+			continue
+		}
+
+		phases := slicePhases(usl)
+		rows = append(rows, fmt.Sprintf("%s | %s | %d | %d | %d", g.Name, dependsOn, g.Scale, len(phases), activeSlices(phases)))
+	}
+
+	return rows
+}
+
+func handleStackCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+}
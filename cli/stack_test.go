@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/stack"
+)
+
+func Test_Stack_createStackStatus(t *testing.T) {
+	RegisterTestingT(t)
+
+	groups := []stack.Group{
+		{Name: "db", Scale: 1},
+		{Name: "api", Scale: 2, DependsOn: []string{"db"}},
+		{Name: "worker", Scale: 3, DependsOn: []string{"db", "api"}},
+	}
+	statuses := map[string][]fleet.UnitStatus{
+		"db": {
+			runningUnitStatusOn("db-main@1.service", "1", "172.17.8.101"),
+		},
+		"api": {
+			runningUnitStatusOn("api-main@1.service", "1", "172.17.8.101"),
+			unloadedUnitStatus("api-main@2.service", "2", "launched"),
+		},
+	}
+
+	Expect(createStackStatus(groups, statuses)).To(Equal([]string{
+		"Group | Depends On | Scale | Slices | Active",
+		"",
+		"db | - | 1 | 1 | 1",
+		"api | db | 2 | 2 | 1",
+		"worker | db,api | 3 | - | -",
+	}))
+}
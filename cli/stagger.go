@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	staggerFlags struct {
+		Stagger time.Duration
+	}
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{startCmd, upCmd, updateCmd} {
+		cmd.PersistentFlags().DurationVar(&staggerFlags.Stagger, "stagger", 0, "delay between launching two slices, e.g. 10s")
+	}
+}
+
+// withStagger configures the given request to launch slices one after
+// another, as given using --stagger.
+func withStagger(req controller.Request) controller.Request {
+	req.Stagger = staggerFlags.Stagger
+
+	return req
+}
@@ -1,8 +1,6 @@
 package cli
 
 import (
-	"os"
-
 	"github.com/spf13/cobra"
 
 	"github.com/giantswarm/inago/controller"
@@ -12,17 +10,25 @@ var (
 	startCmd = &cobra.Command{
 		Use:   "start <group[@slice]...>",
 		Short: "Start a group",
-		Long:  "Start the specified group, or slices",
-		Run:   startRun,
+		Long:  "Start the specified group, or slices. Multiple groups, or all groups of the current working directory using --all-local, are started in parallel",
+		Run:   reportingRun("start", startRun),
 	}
 )
 
 func startRun(cmd *cobra.Command, args []string) {
 	newLogger.Debug(newCtx, "cli: starting start")
 
+	if groups, _, ok, err := parseMultiGroupArgs(fs, args, multiGroupFlags.AllLocal, false); err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	} else if ok {
+		runMultiGroup("start", groups, existingGroupAction(newController.Start))
+		return
+	}
+
 	if len(args) == 0 {
 		cmd.Help()
-		os.Exit(1)
+		exit(1)
 	}
 
 	var err error
@@ -30,22 +36,33 @@ func startRun(cmd *cobra.Command, args []string) {
 	newRequestConfig.Group, newRequestConfig.SliceIDs, err = parseGroupCLIArgs(args)
 	if err != nil {
 		newLogger.Error(newCtx, "%#v", maskAny(err))
-		os.Exit(1)
+		exit(1)
+	}
+	req, err := withMaxFailures(withStagger(withRetry(controller.NewRequest(newRequestConfig))))
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+	req, err = extendRequestWithReadiness(fs, req)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
 	}
-	req := controller.NewRequest(newRequestConfig)
 
 	if len(newRequestConfig.SliceIDs) == 0 {
 		req, err = newController.ExtendWithExistingSliceIDs(req)
 		if err != nil {
 			newLogger.Error(newCtx, "%#v", maskAny(err))
-			os.Exit(1)
+			diagnoseConnection(newCtx, err)
+			exit(1)
 		}
 	}
 
 	taskObject, err := newController.Start(newCtx, req)
 	if err != nil {
 		newLogger.Error(newCtx, "%#v", maskAny(err))
-		os.Exit(1)
+		diagnoseConnection(newCtx, err)
+		exit(1)
 	}
 
 	maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
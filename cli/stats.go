@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// maxSlowestSlices is the number of slowest slices shown by stats.
+	maxSlowestSlices = 5
+)
+
+var (
+	statsFlags struct {
+		Since time.Duration
+	}
+
+	statsCmd = &cobra.Command{
+		Use:   "stats [group]",
+		Short: "Show deployment statistics",
+		Long:  "Show how long deployments of groups took, how often they failed, how often groups were rolled back using undo, and the slices slowest to deploy, based on the audit log of the operations executed by inagoctl on this machine. Without group, all groups having an audit log are shown",
+		Run:   statsRun,
+	}
+
+	// deployOperations are the operations deploying groups, see groupStats.
+	deployOperations = []string{"deploy", "stack up", "up", "update"}
+)
+
+func init() {
+	statsCmd.PersistentFlags().DurationVar(&statsFlags.Since, "since", 7*24*time.Hour, "only consider operations started within this duration")
+}
+
+// groupStats summarizes the operations recorded in the audit log of a group.
+type groupStats struct {
+	Group             string
+	Operations        int
+	Deploys           int
+	AvgDeployDuration time.Duration
+	FailedDeploys     int
+	DeployFailureRate float64
+	Rollbacks         int
+}
+
+// slowSlice describes a slice taking long to be deployed.
+type slowSlice struct {
+	Group     string
+	SliceID   string
+	Operation string
+	Started   time.Time
+	Duration  time.Duration
+}
+
+func statsRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting stats")
+
+	var groups []string
+	switch len(args) {
+	case 0:
+		var err error
+		groups, err = newStateStore.List(auditNamespace)
+		if err != nil {
+			newLogger.Error(newCtx, "%#v", maskAny(err))
+			exit(1)
+		}
+	case 1:
+		groups = resolveGroups(args)
+	default:
+		cmd.Help()
+		exit(1)
+	}
+
+	audits := map[string]groupAudit{}
+	for _, group := range groups {
+		audit, err := loadAudit(newStateStore, group)
+		if err != nil {
+			newLogger.Error(newCtx, "%#v", maskAny(err))
+			exit(1)
+		}
+		audits[group] = audit
+	}
+
+	stats, slowest := computeStats(audits, time.Now().Add(-statsFlags.Since))
+	if len(stats) == 0 {
+		newLogger.Error(newCtx, "No operations recorded within the last %s.", statsFlags.Since)
+		exit(1)
+	}
+
+	printResult(result{Kind: "stats", Rows: createStats(stats, slowest)})
+}
+
+// computeStats summarizes the operations of the given audit logs started
+// after the given time. Groups without such operations are left out. Besides
+// the stats sorted by group, the slices of all groups slowest to deploy are
+// returned, the slowest first. Deploys are the operations listed in
+// deployOperations. Rollbacks are operations reverting a group using undo.
+func computeStats(audits map[string]groupAudit, since time.Time) ([]groupStats, []slowSlice) {
+	var stats []groupStats
+	var slices []slowSlice
+	for group, audit := range audits {
+		s := groupStats{Group: group}
+		var deployDuration time.Duration
+		for _, e := range audit.Entries {
+			if e.Started.Before(since) {
+				continue
+			}
+			s.Operations++
+			if e.Operation == "undo" {
+				s.Rollbacks++
+			}
+			if !containsString(deployOperations, e.Operation) {
+				continue
+			}
+
+			s.Deploys++
+			deployDuration += e.Duration
+			if !e.Succeeded {
+				s.FailedDeploys++
+			}
+			for _, sl := range e.Slices {
+				slices = append(slices, slowSlice{Group: group, SliceID: sl.ID, Operation: e.Operation, Started: e.Started, Duration: sl.Duration})
+			}
+		}
+		if s.Operations == 0 {
+			continue
+		}
+		if s.Deploys > 0 {
+			s.AvgDeployDuration = deployDuration / time.Duration(s.Deploys)
+			s.DeployFailureRate = float64(s.FailedDeploys) / float64(s.Deploys)
+		}
+		stats = append(stats, s)
+	}
+	sort.Sort(groupStatsByGroup(stats))
+
+	sort.Stable(slowSlicesByDuration(slices))
+	if len(slices) > maxSlowestSlices {
+		slices = slices[:maxSlowestSlices]
+	}
+
+	return stats, slices
+}
+
+// createStats renders the given stats and slowest slices as table rows.
+func createStats(stats []groupStats, slowest []slowSlice) []string {
+	round := func(d time.Duration) string {
+		return (d / time.Second * time.Second).String()
+	}
+
+	rows := []string{"Group | Operations | Deploys | Avg Deploy Duration | Failure Rate | Rollbacks", ""}
+	for _, s := range stats {
+		avg := "-"
+		failureRate := "-"
+		if s.Deploys > 0 {
+			avg = round(s.AvgDeployDuration)
+			failureRate = fmt.Sprintf("%.0f%%", s.DeployFailureRate*100)
+		}
+		rows = append(rows, fmt.Sprintf("%s | %d | %d | %s | %s | %d", s.Group, s.Operations, s.Deploys, avg, failureRate, s.Rollbacks))
+	}
+
+	if len(slowest) > 0 {
+		rows = append(rows, "", "Slowest Slice | Operation | Started | Duration", "")
+		for _, s := range slowest {
+			name := s.Group
+			if s.SliceID != "" {
+				name += "@" + s.SliceID
+			}
+			rows = append(rows, fmt.Sprintf("%s | %s | %s | %s", name, s.Operation, s.Started.Local().Format(historyTimeFormat), round(s.Duration)))
+		}
+	}
+
+	return rows
+}
+
+type groupStatsByGroup []groupStats
+
+func (g groupStatsByGroup) Len() int           { return len(g) }
+func (g groupStatsByGroup) Swap(i, j int)      { g[i], g[j] = g[j], g[i] }
+func (g groupStatsByGroup) Less(i, j int) bool { return g[i].Group < g[j].Group }
+
+type slowSlicesByDuration []slowSlice
+
+func (s slowSlicesByDuration) Len() int      { return len(s) }
+func (s slowSlicesByDuration) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s slowSlicesByDuration) Less(i, j int) bool {
+	if s[i].Duration != s[j].Duration {
+		return s[i].Duration > s[j].Duration
+	}
+	if s[i].Group != s[j].Group {
+		return s[i].Group < s[j].Group
+	}
+	return s[i].SliceID < s[j].SliceID
+}
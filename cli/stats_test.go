@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/state"
+)
+
+func Test_Stats_computeStats(t *testing.T) {
+	RegisterTestingT(t)
+
+	store := state.NewMemoryStore()
+	started := time.Date(2016, 5, 4, 12, 0, 0, 0, time.UTC)
+	record := func(operation string, age time.Duration, groups ...groupReport) {
+		r := &report{
+			Operation: operation,
+			Started:   started.Add(-age),
+			Finished:  started.Add(-age + 10*time.Second),
+			Groups:    groups,
+		}
+		Expect(recordAudit(store, r)).To(Succeed())
+	}
+	slices := func(durations ...time.Duration) []sliceReport {
+		var srs []sliceReport
+		for i, d := range durations {
+			srs = append(srs, sliceReport{ID: strconv.Itoa(i + 1), Started: started, Finished: started.Add(d)})
+		}
+		return srs
+	}
+
+	record("up", 30*24*time.Hour, groupReport{Group: "app", Succeeded: true, Slices: slices(time.Hour)})
+	record("up", 3*time.Hour, groupReport{Group: "app", Succeeded: true, Slices: slices(4*time.Second, 8*time.Second)})
+	record("update", 2*time.Hour, groupReport{Group: "app", Error: "unit failed", Slices: slices(2*time.Second, 9*time.Second)})
+	record("undo", 1*time.Hour, groupReport{Group: "app", Succeeded: true})
+	record("stack up", 1*time.Hour, groupReport{Group: "db", Succeeded: true, Duration: "40s"}, groupReport{Group: "app", Succeeded: true, Duration: "2s"})
+	record("stop", 1*time.Hour, groupReport{Group: "cache", Succeeded: true})
+
+	groups, err := store.List(auditNamespace)
+	Expect(err).To(BeNil())
+	Expect(groups).To(Equal([]string{"app", "cache", "db"}))
+
+	audits := map[string]groupAudit{}
+	for _, group := range groups {
+		audits[group], err = loadAudit(store, group)
+		Expect(err).To(BeNil())
+	}
+
+	stats, slowest := computeStats(audits, started.Add(-7*24*time.Hour))
+	Expect(stats).To(Equal([]groupStats{
+		{Group: "app", Operations: 4, Deploys: 3, AvgDeployDuration: 22 * time.Second / 3, FailedDeploys: 1, DeployFailureRate: 1.0 / 3, Rollbacks: 1},
+		{Group: "cache", Operations: 1},
+		{Group: "db", Operations: 1, Deploys: 1, AvgDeployDuration: 40 * time.Second},
+	}))
+	Expect(slowest).To(HaveLen(4))
+	Expect(slowest[0].Duration).To(Equal(9 * time.Second))
+	Expect(slowest[0].SliceID).To(Equal("2"))
+	Expect(slowest[0].Operation).To(Equal("update"))
+
+	rows := createStats(stats, slowest)
+	Expect(rows[2]).To(Equal("app | 4 | 3 | 7s | 33% | 1"))
+	Expect(rows[3]).To(Equal("cache | 1 | 0 | - | - | 0"))
+}
@@ -3,48 +3,422 @@ package cli
 import (
 	"fmt"
 	"os"
+	"sort"
+	"text/template"
+	"time"
 
+	"github.com/juju/errgo"
 	"github.com/ryanuber/columnize"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 
+	"github.com/giantswarm/inago/common"
 	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
 )
 
 var (
 	statusCmd = &cobra.Command{
-		Use:   "status <group>",
+		Use:   "status <group|pattern>",
 		Short: "Get group status",
-		Long:  "Print the status of a group",
+		Long:  "Print the status of a group. Given a glob pattern like 'api-*', the status of all groups deployed to the cluster matching it is printed. Using --match, the status of the units selected by a regular expression over their names is printed instead",
 		Run:   statusRun,
 	}
+
+	statusFlags struct {
+		History        bool
+		ClusterCompare string
+		Resources      bool
+	}
 )
 
+func init() {
+	statusCmd.PersistentFlags().BoolVar(&statusFlags.History, "history", false, "show when slices restarted, flapped or changed machines")
+	statusCmd.PersistentFlags().StringVar(&statusFlags.ClusterCompare, "cluster-compare", "", "compare the group between two fleet endpoints, given as endpointA,endpointB")
+	statusCmd.PersistentFlags().BoolVar(&statusFlags.Resources, "resources", false, "sample CPU and memory usage of units on their machines using SSH")
+}
+
 func statusRun(cmd *cobra.Command, args []string) {
 	newLogger.Debug(newCtx, "cli: starting status")
 
+	if matchFlags.Match != "" {
+		if len(args) != 0 {
+			cmd.Help()
+			exit(1)
+		}
+		statusMatchRun()
+		return
+	}
+
 	group := ""
 	switch len(args) {
 	case 1:
-		group = args[0]
+		group = resolveGroup(args[0])
 	default:
 		cmd.Help()
-		os.Exit(1)
+		exit(1)
 	}
 
 	newRequestConfig := controller.DefaultRequestConfig()
 	newRequestConfig.Group = group
 	req := controller.NewRequest(newRequestConfig)
 
-	req, err := newController.ExtendWithExistingSliceIDs(req)
+	tmpl, err := parseOutput(outputFlags.Output)
+	handleStatusCmdError(newCtx, req, err)
+	if tmpl != nil && (statusFlags.History || statusFlags.ClusterCompare != "") {
+		handleStatusCmdError(newCtx, req, maskAnyf(invalidArgumentsError, "--output cannot be combined with --history or --cluster-compare"))
+	}
+	if statusFlags.Resources && (tmpl != nil || statusFlags.History || statusFlags.ClusterCompare != "") {
+		handleStatusCmdError(newCtx, req, maskAnyf(invalidArgumentsError, "--resources cannot be combined with --output, --history or --cluster-compare"))
+	}
+
+	if controller.IsGroupPattern(group) {
+		statusPatternRun(group, tmpl)
+		return
+	}
+	if statusFlags.History {
+		statusHistoryRun(req)
+		return
+	}
+	if statusFlags.ClusterCompare != "" {
+		statusCompareRun(req)
+		return
+	}
+
+	req, err = newController.ExtendWithExistingSliceIDs(req)
+	handleStatusCmdError(newCtx, req, err)
+	req, err = extendRequestWithReadiness(fs, req)
 	handleStatusCmdError(newCtx, req, err)
 
 	statusList, err := newController.GetStatus(newCtx, req)
 	handleStatusCmdError(newCtx, req, err)
 
+	if err := recordHistory(newStateStore, req.Group, statusList, time.Now()); err != nil {
+		newLogger.Debug(newCtx, "cli: recording history of group '%s' failed: %#v", req.Group, maskAny(err))
+	}
+	flapping, err := flappingSlices(newCtx, req.Group)
+	handleStatusCmdError(newCtx, req, err)
+	annotations, err := newController.SliceAnnotations(newCtx, req.Group)
+	handleStatusCmdError(newCtx, req, err)
+	annotations = currentAnnotations(annotations, req.SliceIDs)
+	out := newStatusOutput(req.Group, statusList)
+	markFlapping(&out, flapping)
+	markAnnotations(&out, annotations)
+
+	if tmpl != nil {
+		err := executeOutput(os.Stdout, tmpl, out)
+		handleStatusCmdError(newCtx, req, err)
+		return
+	}
+	if structuredFormat() {
+		printResult(result{Kind: "status", Data: out})
+		if statusFlags.Resources {
+			rows := sampleResources(newCtx, newResourceSampler(), statusList)
+			printResult(result{Kind: "resources", Rows: createResourceTable(rows)})
+		}
+		return
+	}
+
+	// On-call engineers need to know what a group is about and whom to page.
+	metadata, err := readGroupMetadata(fs, req.Group)
+	handleStatusCmdError(newCtx, req, err)
+	if !metadata.isEmpty() {
+		fmt.Println(columnize.SimpleFormat(createMetadataSummary(metadata)))
+		fmt.Println()
+	}
+
 	data, err := createStatus(req.Group, statusList)
 	handleStatusCmdError(newCtx, req, err)
-	fmt.Println(columnize.SimpleFormat(data))
+	colors, err := createStatusColors(req, statusList)
+	handleStatusCmdError(newCtx, req, err)
+	printResult(result{Kind: "status", Rows: data, Colors: colors})
+	if len(flapping) > 0 {
+		printResult(result{Kind: "flapping", Rows: createFlappingSummary(req.Group, flapping)})
+	}
+	if len(annotations) > 0 {
+		printResult(result{Kind: "annotations", Rows: createAnnotationSummary(req.Group, annotations)})
+	}
+
+	versions, err := newController.SliceVersions(newCtx, req)
+	handleStatusCmdError(newCtx, req, err)
+	if versionData := createSliceVersions(req.Group, versions); versionData != nil {
+		printResult(result{Kind: "versions", Rows: versionData})
+	}
+
+	if statusFlags.Resources {
+		rows := sampleResources(newCtx, newResourceSampler(), statusList)
+		printResult(result{Kind: "resources", Rows: createResourceTable(rows)})
+	}
+}
+
+// flappingSlices returns the slices of the given group restarting too often,
+// keyed by slice ID. See controller.Controller.CheckFlapping.
+func flappingSlices(ctx context.Context, group string) (map[string]controller.FlappingSlice, error) {
+	err := newController.CheckFlapping(ctx, group)
+	if controller.IsFlapping(err) {
+		flapping := map[string]controller.FlappingSlice{}
+		for _, s := range errgo.Cause(err).(controller.FlappingError).Slices {
+			flapping[s.SliceID] = s
+		}
+		return flapping, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return nil, nil
+}
+
+// createFlappingSummary renders the given flapping slices of the given group
+// as table rows, sorted by slice ID.
+func createFlappingSummary(group string, flapping map[string]controller.FlappingSlice) []string {
+	var sliceIDs []string
+	for sliceID := range flapping {
+		sliceIDs = append(sliceIDs, sliceID)
+	}
+	sort.Strings(sliceIDs)
+
+	rows := []string{"Flapping | Restarts | Last Restart", ""}
+	for _, sliceID := range sliceIDs {
+		name := group
+		if sliceID != "" {
+			name += "@" + sliceID
+		}
+		s := flapping[sliceID]
+		rows = append(rows, fmt.Sprintf("%s | %d | %s", name, s.Restarts, s.LastRestart.Local().Format(historyTimeFormat)))
+	}
+
+	return rows
+}
+
+// statusPatternRun prints the status of all groups deployed to the cluster
+// matching the given glob pattern in a single table. In case a template is
+// given using --output, it is executed once per group instead.
+func statusPatternRun(pattern string, tmpl *template.Template) {
+	req := controller.NewRequest(controller.RequestConfig{Group: pattern})
+	if statusFlags.History || statusFlags.ClusterCompare != "" {
+		handleStatusCmdError(newCtx, req, maskAnyf(invalidArgumentsError, "--history and --cluster-compare require a single group"))
+	}
+	if statusFlags.Resources {
+		handleStatusCmdError(newCtx, req, maskAnyf(invalidArgumentsError, "--resources requires a single group"))
+	}
+
+	groups, err := newController.MatchingGroups(newCtx, pattern)
+	handleStatusCmdError(newCtx, req, err)
+	if len(groups) == 0 {
+		newLogger.Error(newCtx, "Failed to find groups matching '%s'.", pattern)
+		exit(1)
+	}
+
+	var data, colors []string
+	var outputs []statusOutput
+	for i, group := range groups {
+		req, err := extendRequestWithReadiness(fs, controller.NewRequest(controller.RequestConfig{Group: group}))
+		handleStatusCmdError(newCtx, req, err)
+		statusList, err := newController.GetStatus(newCtx, req)
+		handleStatusCmdError(newCtx, req, err)
+
+		if err := recordHistory(newStateStore, group, statusList, time.Now()); err != nil {
+			newLogger.Debug(newCtx, "cli: recording history of group '%s' failed: %#v", group, maskAny(err))
+		}
+
+		if tmpl != nil {
+			err := executeOutput(os.Stdout, tmpl, newStatusOutput(group, statusList))
+			handleStatusCmdError(newCtx, req, err)
+			continue
+		}
+		if structuredFormat() {
+			outputs = append(outputs, newStatusOutput(group, statusList))
+			continue
+		}
+
+		groupData, err := createStatus(group, statusList)
+		handleStatusCmdError(newCtx, req, err)
+		groupColors, err := createStatusColors(req, statusList)
+		handleStatusCmdError(newCtx, req, err)
+		data, colors = appendStatusTable(data, colors, groupData, groupColors, i == 0)
+	}
+
+	switch {
+	case tmpl != nil:
+	case structuredFormat():
+		printResult(result{Kind: "status", Data: outputs})
+	default:
+		printResult(result{Kind: "status", Rows: data, Colors: colors})
+	}
+}
+
+// statusMatchRun prints the status of the units selected using --match in a
+// single table. Units are shown by the base of their names, e.g. "legacy-db"
+// for "legacy-db@1.service", and aggregated the same way as the units of a
+// group. In case a template is given using --output, it is executed once per
+// unit base instead.
+func statusMatchRun() {
+	req, err := matchRequest()
+	handleStatusCmdError(newCtx, req, err)
+	tmpl, err := parseOutput(outputFlags.Output)
+	handleStatusCmdError(newCtx, req, err)
+	if statusFlags.History || statusFlags.ClusterCompare != "" {
+		handleStatusCmdError(newCtx, req, maskAnyf(invalidArgumentsError, "--history and --cluster-compare cannot be combined with --match"))
+	}
+
+	statusList, err := newController.GetStatus(newCtx, req)
+	if controller.IsUnitNotFound(err) {
+		newLogger.Error(newCtx, "Failed to find units matching '%s'.", req.Group)
+		exit(1)
+	}
+	handleStatusCmdError(newCtx, req, err)
+
+	bases, byBase := unitStatusesByBase(statusList)
+	var data, colors []string
+	var outputs []statusOutput
+	for i, base := range bases {
+		if tmpl != nil {
+			err := executeOutput(os.Stdout, tmpl, newStatusOutput(base, byBase[base]))
+			handleStatusCmdError(newCtx, req, err)
+			continue
+		}
+		if structuredFormat() {
+			outputs = append(outputs, newStatusOutput(base, byBase[base]))
+			continue
+		}
+
+		baseData, err := createStatus(base, byBase[base])
+		handleStatusCmdError(newCtx, req, err)
+		baseColors, err := createStatusColors(req, byBase[base])
+		handleStatusCmdError(newCtx, req, err)
+		data, colors = appendStatusTable(data, colors, baseData, baseColors, i == 0)
+	}
+
+	switch {
+	case tmpl != nil:
+	case structuredFormat():
+		printResult(result{Kind: "status", Data: outputs})
+	default:
+		printResult(result{Kind: "status", Rows: data, Colors: colors})
+	}
+
+	if statusFlags.Resources && tmpl == nil {
+		rows := sampleResources(newCtx, newResourceSampler(), statusList)
+		printResult(result{Kind: "resources", Rows: createResourceTable(rows)})
+	}
+}
+
+// unitStatusesByBase partitions the given unit states by the base of the
+// unit names, see common.UnitBase. The bases are returned sorted.
+func unitStatusesByBase(usl []fleet.UnitStatus) ([]string, map[string][]fleet.UnitStatus) {
+	var bases []string
+	byBase := map[string][]fleet.UnitStatus{}
+	for _, us := range usl {
+		base := common.UnitBase(us.Name)
+		if _, ok := byBase[base]; !ok {
+			bases = append(bases, base)
+		}
+		byBase[base] = append(byBase[base], us)
+	}
+	sort.Strings(bases)
+
+	return bases, byBase
+}
+
+// appendStatusTable appends the rows and colors of the status table of a
+// group, as created by createStatus and createStatusColors, to the ones of
+// other groups. The header is only kept for the first group.
+func appendStatusTable(data, colors, groupData, groupColors []string, first bool) ([]string, []string) {
+	for len(groupData) > 0 && groupData[len(groupData)-1] == "" {
+		groupData = groupData[:len(groupData)-1]
+	}
+	if !first {
+		groupData = groupData[2:]
+		groupColors = groupColors[2:]
+	}
+
+	return append(data, groupData...), append(colors, groupColors...)
+}
+
+// statusHistoryRun records the current status of the group of the given
+// request and prints its history. Groups that are not deployed anymore still
+// have a history.
+func statusHistoryRun(req controller.Request) {
+	statusList, err := newController.GetStatus(newCtx, req)
+	if controller.IsUnitNotFound(err) {
+		statusList = nil
+	} else {
+		handleStatusCmdError(newCtx, req, err)
+	}
+	err = recordHistory(newStateStore, req.Group, statusList, time.Now())
+	handleStatusCmdError(newCtx, req, err)
+
+	history, err := loadHistory(newStateStore, req.Group)
+	handleStatusCmdError(newCtx, req, err)
+	if len(history.Transitions) == 0 {
+		newLogger.Error(newCtx, "No history recorded for group '%s'.", req.Group)
+		exit(1)
+	}
+
+	printResult(result{Kind: "history", Rows: createHistory(req.Group, history, time.Now(), globalFlags.Verbose)})
+}
+
+// statusCompareRun prints the differences of the group of the given request
+// between the two clusters given using --cluster-compare. In case the group
+// differs, inagoctl exits with a non-zero status.
+func statusCompareRun(req controller.Request) {
+	endpoints, err := parseClusterCompare(statusFlags.ClusterCompare)
+	handleStatusCmdError(newCtx, req, err)
+
+	var snapshots []clusterSnapshot
+	for _, endpoint := range endpoints {
+		c, err := newClusterController(endpoint)
+		handleStatusCmdError(newCtx, req, err)
+		snapshot, err := fetchClusterSnapshot(newCtx, c, endpoint, req.Group)
+		handleStatusCmdError(newCtx, req, err)
+		snapshots = append(snapshots, snapshot)
+	}
+	if !snapshots[0].Deployed && !snapshots[1].Deployed {
+		newLogger.Error(newCtx, "Failed to find group '%s' on %s and %s.", req.Group, endpoints[0], endpoints[1])
+		exit(1)
+	}
+
+	data, differences := compareClusterSnapshots(req.Group, snapshots[0], snapshots[1], globalFlags.Verbose)
+	printResult(result{Kind: "compare", Rows: data})
+
+	if differences > 0 {
+		newLogger.Error(newCtx, "Group '%s' differs in %d properties between %s and %s.", req.Group, differences, endpoints[0], endpoints[1])
+		exit(1)
+	}
+	newLogger.Info(newCtx, "Group '%s' is equal on %s and %s.", req.Group, endpoints[0], endpoints[1])
+}
+
+// createSliceVersions creates the table rows showing the semantic version of
+// each slice of the given group, or nil in case no slice carries a version.
+// See controller.Request.WithSemVer.
+func createSliceVersions(group string, versions map[string]string) []string {
+	var sliceIDs []string
+	found := false
+	for sliceID, version := range versions {
+		sliceIDs = append(sliceIDs, sliceID)
+		if version != "" {
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	sort.Strings(sliceIDs)
+
+	data := []string{"Slice | Version"}
+	for _, sliceID := range sliceIDs {
+		name := group
+		if sliceID != "" {
+			name += "@" + sliceID
+		}
+		version := versions[sliceID]
+		if version == "" {
+			version = "-"
+		}
+		data = append(data, name+" | "+version)
+	}
+
+	return data
 }
 
 func handleStatusCmdError(ctx context.Context, req controller.Request, err error) {
@@ -56,9 +430,10 @@ func handleStatusCmdError(ctx context.Context, req controller.Request, err error
 		} else {
 			newLogger.Error(ctx, "Failed to find %d slices for group '%s': %v.", len(req.SliceIDs), req.Group, req.SliceIDs)
 		}
-		os.Exit(1)
+		exit(1)
 	} else if err != nil {
 		newLogger.Error(ctx, "%#v", maskAny(err))
-		os.Exit(1)
+		diagnoseConnection(ctx, err)
+		exit(1)
 	}
 }
@@ -1,8 +1,6 @@
 package cli
 
 import (
-	"os"
-
 	"github.com/spf13/cobra"
 
 	"github.com/giantswarm/inago/controller"
@@ -12,17 +10,25 @@ var (
 	stopCmd = &cobra.Command{
 		Use:   "stop <group[@slice]...>",
 		Short: "Stop a group",
-		Long:  "Stop the specified group, or slices",
-		Run:   stopRun,
+		Long:  "Stop the specified group, or slices. Multiple groups, or all groups of the current working directory using --all-local, are stopped in parallel",
+		Run:   reportingRun("stop", stopRun),
 	}
 )
 
 func stopRun(cmd *cobra.Command, args []string) {
 	newLogger.Debug(newCtx, "cli: starting stop")
 
+	if groups, _, ok, err := parseMultiGroupArgs(fs, args, multiGroupFlags.AllLocal, false); err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	} else if ok {
+		runMultiGroup("stop", groups, existingGroupAction(newController.Stop))
+		return
+	}
+
 	if len(args) == 0 {
 		cmd.Help()
-		os.Exit(1)
+		exit(1)
 	}
 
 	var err error
@@ -30,7 +36,7 @@ func stopRun(cmd *cobra.Command, args []string) {
 	newRequestConfig.Group, newRequestConfig.SliceIDs, err = parseGroupCLIArgs(args)
 	if err != nil {
 		newLogger.Error(newCtx, "%#v", maskAny(err))
-		os.Exit(1)
+		exit(1)
 	}
 	req := controller.NewRequest(newRequestConfig)
 
@@ -38,14 +44,16 @@ func stopRun(cmd *cobra.Command, args []string) {
 		req, err = newController.ExtendWithExistingSliceIDs(req)
 		if err != nil {
 			newLogger.Error(newCtx, "%#v", maskAny(err))
-			os.Exit(1)
+			diagnoseConnection(newCtx, err)
+			exit(1)
 		}
 	}
 
 	taskObject, err := newController.Stop(newCtx, req)
 	if err != nil {
 		newLogger.Error(newCtx, "%#v", maskAny(err))
-		os.Exit(1)
+		diagnoseConnection(newCtx, err)
+		exit(1)
 	}
 
 	maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
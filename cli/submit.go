@@ -1,11 +1,11 @@
 package cli
 
 import (
-	"os"
 	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
 
 	"github.com/giantswarm/inago/controller"
 	"github.com/giantswarm/inago/file-system/spec"
@@ -14,44 +14,55 @@ import (
 
 var (
 	submitCmd = &cobra.Command{
-		Use:   "submit <group> [scale]",
+		Use:   "submit <group>... [scale]",
 		Short: "Submit a group",
-		Long:  "Submit a group to the cluster, with an optional scale",
-		Run:   submitRun,
+		Long:  "Submit a group to the cluster, with an optional scale. Multiple groups, or all groups of the current working directory using --all-local, are submitted in parallel",
+		Run:   reportingRun("submit", submitRun),
 	}
 )
 
 func submitRun(cmd *cobra.Command, args []string) {
 	newLogger.Debug(newCtx, "cli: starting submit")
 
+	if groups, scale, ok, err := parseMultiGroupArgs(fs, args, multiGroupFlags.AllLocal, true); err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	} else if ok {
+		runMultiGroup("submit", groups, func(ctx context.Context, group string) error {
+			return submitGroup(ctx, group, scale)
+		})
+		return
+	}
+
 	group := ""
 	scale := 1
 	switch len(args) {
 	case 1:
-		group = args[0]
+		group = resolveGroup(args[0])
 	case 2:
-		group = args[0]
+		group = resolveGroup(args[0])
 		n, err := strconv.Atoi(args[1])
 		if err != nil {
 			newLogger.Error(newCtx, "%#v\n", maskAny(err))
-			os.Exit(1)
+			exit(1)
 		}
 		scale = n
 	default:
 		cmd.Help()
-		os.Exit(1)
+		exit(1)
 	}
 
 	req, err := createSubmitRequest(fs, group, scale)
 	if err != nil {
 		newLogger.Error(newCtx, "%#v", maskAny(err))
-		os.Exit(1)
+		exit(1)
 	}
 
 	taskObject, err := newController.Submit(newCtx, req)
 	if err != nil {
 		newLogger.Error(newCtx, "%#v", maskAny(err))
-		os.Exit(1)
+		diagnoseConnection(newCtx, err)
+		exit(1)
 	}
 
 	maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
@@ -72,7 +83,20 @@ func createSubmitRequest(fs filesystemspec.FileSystem, group string, scale int)
 	if err != nil {
 		return controller.Request{}, err
 	}
+	if err := lintRequest(req); err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+	req, err = withPlacementFlags(req)
+	if err != nil {
+		return controller.Request{}, maskAny(err)
+	}
+
+	return withDesiredSlices(req, scale)
+}
 
+// withDesiredSlices configures the given request to submit the given number of
+// slices. Groups without slices can only be submitted once.
+func withDesiredSlices(req controller.Request, scale int) (controller.Request, error) {
 	if strings.Contains(req.Units[0].Name, "@") {
 		req.DesiredSlices = scale
 	} else {
@@ -0,0 +1,468 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/ryanuber/columnize"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/task"
+)
+
+const (
+	// maxTopEvents is the number of events kept by top. Older events are
+	// dropped.
+	maxTopEvents = 100
+
+	// maxTopSliceEvents is the number of events of the selected slice shown in
+	// the detail pane of top.
+	maxTopSliceEvents = 10
+
+	topHelp = "j/k: select  s: start  x: stop  r: restart  q: quit"
+)
+
+var (
+	topFlags struct {
+		Interval time.Duration
+	}
+
+	topCmd = &cobra.Command{
+		Use:   "top [group...]",
+		Short: "Show a live dashboard of groups",
+		Long:  "Show the slices of the given groups, or the ones in the current working directory, with their phases and machines, refreshed while they change. Slices can be selected to be started, stopped or restarted, and the recent events of the selected slice are shown below",
+		Run:   topRun,
+	}
+)
+
+func init() {
+	topCmd.PersistentFlags().DurationVar(&topFlags.Interval, "interval", 500*time.Millisecond, "time to wait between refreshing the screen")
+}
+
+// topAction is an action triggered by a key pressed in top.
+type topAction string
+
+const (
+	topActionNone    topAction = ""
+	topActionQuit    topAction = "quit"
+	topActionStart   topAction = "start"
+	topActionStop    topAction = "stop"
+	topActionRestart topAction = "restart"
+)
+
+// topRow is a slice listed by top. SliceID is empty for groups without slices.
+type topRow struct {
+	Group   string
+	SliceID string
+	Phase   phase
+	Units   int
+	Machine string
+}
+
+func (r topRow) name() string {
+	if r.SliceID == "" {
+		return r.Group
+	}
+	return r.Group + "@" + r.SliceID
+}
+
+// topEvent is shown in the detail pane of top in case its slice is selected.
+type topEvent struct {
+	Time    time.Time
+	Slice   string
+	Message string
+}
+
+// topModel is the state of top. It is only accessed by the goroutine drawing
+// the screen.
+type topModel struct {
+	Groups []string
+
+	statuses map[string]map[string]fleet.UnitStatus
+	phases   map[string]phase
+	rows     []topRow
+	selected int
+	events   []topEvent
+}
+
+func newTopModel(groups []string) *topModel {
+	m := &topModel{
+		Groups:   groups,
+		statuses: map[string]map[string]fleet.UnitStatus{},
+		phases:   map[string]phase{},
+	}
+	for _, group := range groups {
+		m.statuses[group] = map[string]fleet.UnitStatus{}
+	}
+
+	return m
+}
+
+// Apply applies the given change of a unit of the given group observed at the
+// given time. Slices changing their phase get an event.
+func (m *topModel) Apply(group string, change fleet.UnitState, now time.Time) {
+	if change.Removed {
+		delete(m.statuses[group], change.Name)
+	} else {
+		m.statuses[group][change.Name] = change.Status
+	}
+
+	var selected string
+	if row, ok := m.Selected(); ok {
+		selected = row.name()
+	}
+
+	var rows []topRow
+	for _, g := range m.Groups {
+		if g == group {
+			continue
+		}
+		for _, row := range m.rows {
+			if row.Group == g {
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	usl := sortedUnitStatuses(m.statuses[group])
+	machines := sliceMachines(usl)
+	units := map[string]int{}
+	for _, us := range usl {
+		units[us.SliceID]++
+	}
+	seen := map[string]bool{}
+	for _, sp := range slicePhases(usl) {
+		row := topRow{Group: group, SliceID: sp.SliceID, Phase: sp.Phase, Units: units[sp.SliceID], Machine: machines[sp.SliceID]}
+		rows = append(rows, row)
+		seen[row.name()] = true
+		if last, ok := m.phases[row.name()]; !ok || last != sp.Phase {
+			m.phases[row.name()] = sp.Phase
+			m.AddEvent(topEvent{Time: now, Slice: row.name(), Message: string(sp.Phase)})
+		}
+	}
+	for name := range m.phases {
+		if strings.SplitN(name, "@", 2)[0] == group && !seen[name] {
+			delete(m.phases, name)
+			m.AddEvent(topEvent{Time: now, Slice: name, Message: string(phaseDestroyed)})
+		}
+	}
+
+	sort.Sort(topRowsByGroup{rows: rows, groups: m.Groups})
+	m.rows = rows
+	m.selected = 0
+	for i, row := range m.rows {
+		if row.name() == selected {
+			m.selected = i
+		}
+	}
+}
+
+// AddEvent adds the given event, dropping the oldest in case there are more
+// than maxTopEvents.
+func (m *topModel) AddEvent(e topEvent) {
+	m.events = append(m.events, e)
+	if len(m.events) > maxTopEvents {
+		m.events = m.events[len(m.events)-maxTopEvents:]
+	}
+}
+
+// Selected returns the selected row. The returned bool is false in case no
+// slices are listed.
+func (m *topModel) Selected() (topRow, bool) {
+	if len(m.rows) == 0 {
+		return topRow{}, false
+	}
+	return m.rows[m.selected], true
+}
+
+// HandleKey changes the selection according to the given key, and returns
+// the action the key triggers for the selected slice. Arrow keys are given as
+// their ANSI escape sequences.
+func (m *topModel) HandleKey(key string) topAction {
+	switch key {
+	case "q", "Q":
+		return topActionQuit
+	case "j", "\x1b[B":
+		if m.selected < len(m.rows)-1 {
+			m.selected++
+		}
+	case "k", "\x1b[A":
+		if m.selected > 0 {
+			m.selected--
+		}
+	case "s":
+		return topActionStart
+	case "x":
+		return topActionStop
+	case "r":
+		return topActionRestart
+	}
+
+	return topActionNone
+}
+
+// Render renders the screen of top at the given time.
+func (m *topModel) Render(now time.Time) string {
+	var deployed int
+	for _, group := range m.Groups {
+		if len(m.statuses[group]) > 0 {
+			deployed++
+		}
+	}
+	lines := []string{
+		fmt.Sprintf("inagoctl top - %d of %d groups deployed, %d slices - %s", deployed, len(m.Groups), len(m.rows), now.Local().Format(historyTimeFormat)),
+		"",
+	}
+
+	if len(m.rows) == 0 {
+		lines = append(lines, "  No slices deployed.")
+	} else {
+		rows := []string{"Slice | Phase | Units | Machine", ""}
+		for _, row := range m.rows {
+			machine := row.Machine
+			if machine == "" {
+				machine = "-"
+			}
+			rows = append(rows, fmt.Sprintf("%s | %s | %d | %s", row.name(), row.Phase, row.Units, machine))
+		}
+		for i, line := range strings.Split(columnize.SimpleFormat(rows), "\n") {
+			marker := "  "
+			if i-2 == m.selected {
+				marker = "> "
+			}
+			lines = append(lines, marker+line)
+		}
+	}
+
+	if row, ok := m.Selected(); ok {
+		lines = append(lines, "", "  Events of "+row.name(), "")
+		var events []topEvent
+		for _, e := range m.events {
+			if e.Slice == row.name() {
+				events = append(events, e)
+			}
+		}
+		if len(events) > maxTopSliceEvents {
+			events = events[len(events)-maxTopSliceEvents:]
+		}
+		for _, e := range events {
+			lines = append(lines, fmt.Sprintf("  %s  %s", e.Time.Local().Format("15:04:05"), e.Message))
+		}
+	}
+
+	lines = append(lines, "", "  "+topHelp)
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func topRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting top")
+
+	if !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		newLogger.Error(newCtx, "top requires a terminal.")
+		exit(1)
+	}
+
+	groups := resolveGroups(args)
+	if len(groups) == 0 {
+		var err error
+		groups, err = localGroups(fs)
+		if err != nil {
+			newLogger.Error(newCtx, "%#v", maskAny(err))
+			exit(1)
+		}
+		if len(groups) == 0 {
+			newLogger.Error(newCtx, "No groups found in the current working directory.")
+			exit(1)
+		}
+	}
+
+	type groupChange struct {
+		Group  string
+		Change fleet.UnitState
+	}
+	changes := make(chan groupChange)
+	done := make(chan struct{})
+	for _, group := range groups {
+		newRequestConfig := controller.DefaultRequestConfig()
+		newRequestConfig.Group = group
+		watch, stopWatch := newController.WatchGroup(newCtx, controller.NewRequest(newRequestConfig))
+		defer stopWatch()
+		go func(group string) {
+			for change := range watch {
+				select {
+				case changes <- groupChange{Group: group, Change: change}:
+				case <-done:
+					return
+				}
+			}
+		}(group)
+	}
+	defer close(done)
+
+	restore, err := rawTerminal()
+	if err != nil {
+		newLogger.Error(newCtx, "Failed to set up terminal. (%s)", err.Error())
+		exit(1)
+	}
+	// Use the alternate screen and hide the cursor, so that the terminal looks
+	// like before once top quits.
+	fmt.Print("\033[?1049h\033[?25l")
+	defer func() {
+		fmt.Print("\033[?25h\033[?1049l")
+		restore()
+	}()
+
+	keys := make(chan string)
+	go func() {
+		buf := make([]byte, 16)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- string(buf[:n])
+		}
+	}()
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	events := make(chan topEvent)
+	ticker := time.NewTicker(topFlags.Interval)
+	defer ticker.Stop()
+
+	m := newTopModel(groups)
+	changed := true
+	for {
+		select {
+		case c := <-changes:
+			if c.Change.Err != nil {
+				newLogger.Debug(newCtx, "cli: fetching state of group '%s' failed: %#v", c.Group, maskAny(c.Change.Err))
+				continue
+			}
+			m.Apply(c.Group, c.Change, time.Now())
+			changed = true
+		case key, ok := <-keys:
+			if !ok {
+				return
+			}
+			action := m.HandleKey(key)
+			if action == topActionQuit {
+				return
+			}
+			if row, ok := m.Selected(); ok && action != topActionNone {
+				go executeTopAction(newCtx, action, row, events)
+			}
+			changed = true
+		case e := <-events:
+			m.AddEvent(e)
+			changed = true
+		case <-interrupts:
+			return
+		case <-ticker.C:
+			if changed {
+				// Move the cursor home and clear the screen before drawing.
+				fmt.Print("\033[H\033[2J" + m.Render(time.Now()))
+				changed = false
+			}
+		}
+	}
+}
+
+// executeTopAction executes the given action for the slice of the given row,
+// and sends events describing its progress to the given channel. Restarting
+// stops the slice and starts it again.
+func executeTopAction(ctx context.Context, action topAction, row topRow, events chan<- topEvent) {
+	emit := func(format string, v ...interface{}) {
+		events <- topEvent{Time: time.Now(), Slice: row.name(), Message: fmt.Sprintf(format, v...)}
+	}
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group = row.Group
+	if row.SliceID != "" {
+		newRequestConfig.SliceIDs = []string{row.SliceID}
+	}
+	req := controller.NewRequest(newRequestConfig)
+
+	operations := []func(context.Context, controller.Request) (*task.Task, error){newController.Start}
+	switch action {
+	case topActionStop:
+		operations = []func(context.Context, controller.Request) (*task.Task, error){newController.Stop}
+	case topActionRestart:
+		operations = []func(context.Context, controller.Request) (*task.Task, error){newController.Stop, newController.Start}
+	}
+
+	emit("%s requested", action)
+	for _, operation := range operations {
+		taskObject, err := operation(ctx, req)
+		if err == nil {
+			taskObject, err = newController.WaitForTask(ctx, taskObject.ID, nil)
+		}
+		if err == nil && task.HasFailedStatus(taskObject) {
+			err = taskObject.Error
+		}
+		if err != nil {
+			emit("%s failed (%s)", action, err.Error())
+			return
+		}
+	}
+	emit("%s succeeded", action)
+}
+
+// rawTerminal makes the terminal of stdin pass each key pressed right away,
+// without echoing it. Calling the returned function restores the previous
+// settings. stty is used, so that this works on all platforms supported.
+func rawTerminal() (func(), error) {
+	stty := func(args ...string) (string, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+		out, err := cmd.Output()
+		return strings.TrimSpace(string(out)), err
+	}
+
+	previous, err := stty("-g")
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, maskAny(err)
+	}
+
+	return func() { stty(previous) }, nil
+}
+
+// topRowsByGroup sorts rows in the order of the given groups, and by slice ID
+// within a group.
+type topRowsByGroup struct {
+	rows   []topRow
+	groups []string
+}
+
+func (t topRowsByGroup) Len() int      { return len(t.rows) }
+func (t topRowsByGroup) Swap(i, j int) { t.rows[i], t.rows[j] = t.rows[j], t.rows[i] }
+func (t topRowsByGroup) Less(i, j int) bool {
+	if t.rows[i].Group != t.rows[j].Group {
+		return indexOf(t.groups, t.rows[i].Group) < indexOf(t.groups, t.rows[j].Group)
+	}
+	return t.rows[i].SliceID < t.rows[j].SliceID
+}
+
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_Top_model(t *testing.T) {
+	RegisterTestingT(t)
+
+	now := time.Date(2016, 5, 4, 12, 0, 0, 0, time.UTC)
+	unit := func(name, sliceID, active string, ip string) fleet.UnitState {
+		us := fleet.UnitStatus{Name: name, SliceID: sliceID, Current: "launched", Desired: "launched"}
+		if ip != "" {
+			us.Machine = []fleet.MachineStatus{{ID: "m", IP: net.ParseIP(ip), SystemdActive: active, SystemdSub: "running"}}
+		}
+		return fleet.UnitState{Name: name, Status: us}
+	}
+
+	m := newTopModel([]string{"web", "app"})
+	Expect(m.Render(now)).To(ContainSubstring("No slices deployed."))
+
+	m.Apply("app", unit("app-main@1.service", "1", "active", "10.0.0.1"), now)
+	m.Apply("app", unit("app-main@2.service", "2", "active", "10.0.0.2"), now)
+	m.Apply("web", unit("web.service", "", "active", "10.0.0.3"), now)
+
+	// Groups are listed in the given order, and the selection sticks to its
+	// slice.
+	Expect(m.rows).To(HaveLen(3))
+	Expect(m.rows[0].name()).To(Equal("web"))
+	Expect(m.rows[1].name()).To(Equal("app@1"))
+	row, ok := m.Selected()
+	Expect(ok).To(BeTrue())
+	Expect(row.name()).To(Equal("app@1"))
+
+	Expect(m.HandleKey("k")).To(Equal(topActionNone))
+	Expect(m.HandleKey("k")).To(Equal(topActionNone))
+	row, _ = m.Selected()
+	Expect(row.name()).To(Equal("web"))
+	Expect(m.HandleKey("j")).To(Equal(topActionNone))
+	Expect(m.HandleKey("\x1b[B")).To(Equal(topActionNone))
+	Expect(m.HandleKey("j")).To(Equal(topActionNone))
+	row, _ = m.Selected()
+	Expect(row.name()).To(Equal("app@2"))
+	Expect(m.HandleKey("k")).To(Equal(topActionNone))
+	Expect(m.HandleKey("r")).To(Equal(topActionRestart))
+	Expect(m.HandleKey("q")).To(Equal(topActionQuit))
+
+	// Phase changes and destroyed slices are events.
+	m.Apply("app", unit("app-main@1.service", "1", "failed", "10.0.0.1"), now.Add(time.Second))
+	m.Apply("app", fleet.UnitState{Name: "app-main@2.service", Removed: true}, now.Add(2*time.Second))
+	row, _ = m.Selected()
+	Expect(row.name()).To(Equal("app@1"))
+	Expect(m.events).To(ContainElement(topEvent{Time: now.Add(time.Second), Slice: "app@1", Message: "failed"}))
+	Expect(m.events).To(ContainElement(topEvent{Time: now.Add(2 * time.Second), Slice: "app@2", Message: "destroyed"}))
+
+	screen := m.Render(now)
+	Expect(screen).To(ContainSubstring("2 of 2 groups deployed, 2 slices"))
+	lines := strings.Split(screen, "\n")
+	Expect(lines[4]).To(HavePrefix("  web    "))
+	Expect(lines[5]).To(HavePrefix("> app@1  failed"))
+	Expect(screen).To(ContainSubstring("Events of app@1"))
+	Expect(screen).To(ContainSubstring("failed\n"))
+	Expect(screen).To(HaveSuffix(topHelp + "\n"))
+}
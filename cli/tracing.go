@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/tracing"
+)
+
+var (
+	// newTracer records the command being executed, and the operations and
+	// calls of fleet it makes. It is nil unless tracing is configured using
+	// the standard OpenTelemetry environment variables. See
+	// tracing.ConfigFromEnv.
+	newTracer *tracing.Tracer
+
+	// commandSpan is the span of the command being executed. The spans of all
+	// operations executed by the command are its children.
+	commandSpan *tracing.Span
+)
+
+// startTracing starts the span of the given command. The returned context
+// carries it.
+func startTracing(ctx context.Context, cmd *cobra.Command, args []string) context.Context {
+	ctx, commandSpan = newTracer.Start(ctx, cmd.CommandPath(), tracing.Attr("inago.command", cmd.Name()), tracing.Attr("inago.request_id", fleet.RequestID(ctx)))
+	if len(args) > 0 {
+		commandSpan.SetAttributes(tracing.Attr("inago.group", args[0]))
+	}
+
+	return ctx
+}
+
+// finishTracing finishes the span of the command being executed, which failed
+// in case the given code is non-zero, and exports all recorded spans. Tracing
+// must not fail commands, so errors are only logged.
+func finishTracing(code int) {
+	if newTracer == nil {
+		return
+	}
+
+	if code != 0 {
+		commandSpan.Finish(maskAnyf(commandFailedError, "exit code %d", code))
+	} else {
+		commandSpan.Finish(nil)
+	}
+	commandSpan = nil
+
+	if err := newTracer.Flush(context.Background()); err != nil {
+		newLogger.Warning(newCtx, "Failed to export traces. (%s)", err.Error())
+	}
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+var (
+	undoFlags struct {
+		Yes bool
+	}
+
+	undoCmd = &cobra.Command{
+		Use:   "undo <group>",
+		Short: "Undo the last operation of a group",
+		Long:  "Revert a group to its state before the latest submit, update or destroy, e.g. after scaling or updating it by mistake. The state is recorded in the state directory of the cluster, so only operations executed by inagoctl on this machine can be undone. Undoing again redoes the operation",
+		Run:   notifyingRun("undo", undoRun),
+	}
+)
+
+func init() {
+	undoCmd.PersistentFlags().BoolVar(&undoFlags.Yes, "yes", false, "do not ask for confirmation before undoing")
+}
+
+func undoRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting undo")
+
+	if len(args) != 1 {
+		cmd.Help()
+		exit(1)
+	}
+	req := controller.NewRequest(controller.RequestConfig{Group: resolveGroup(args[0])})
+
+	op, err := newController.LastOperation(newCtx, req.Group)
+	if controller.IsUndoNotFound(err) {
+		newLogger.Error(newCtx, "Nothing to undo for group '%s'.", req.Group)
+		exit(1)
+	} else if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+
+	if !undoFlags.Yes && !askForConfirmation("Undo %s of group '%s' executed at %s?", op.Operation, req.Group, op.Time.Local().Format(historyTimeFormat)) {
+		newLogger.Info(newCtx, "Not undoing %s of group '%s'.", op.Operation, req.Group)
+		return
+	}
+
+	taskObject, err := newController.Undo(newCtx, req.Group)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+
+	maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+		Request:    req,
+		Descriptor: "undo",
+		NoBlock:    globalFlags.NoBlock,
+		TaskID:     taskObject.ID,
+		Closer:     nil,
+	})
+}
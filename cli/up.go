@@ -1,26 +1,157 @@
 package cli
 
 import (
+	"strconv"
+
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/controller"
 )
 
 var (
 	upCmd = &cobra.Command{
-		Use:   "up <group> [scale]",
+		Use:   "up <group...|archive> [scale]",
 		Short: "Bring a group up",
-		Long:  "Submit a group, with an optional scale, and start it",
-		Run:   upRun,
+		Long:  "Submit a group, with an optional scale, and start it. Without scale, the slices missing to reach the group's desired scale are submitted, see \"inagoctl scale\". Instead of a group directory, an archive created using \"inagoctl export\" can be given. Multiple groups, or all groups of the current working directory using --all-local, are brought up in parallel",
+		Run:   notifyingRun("up", upRun),
 	}
 )
 
 func upRun(cmd *cobra.Command, args []string) {
-	submitRun(cmd, args)
+	if len(fanOutFlags.Clusters) > 0 {
+		runClusters(cmd, args)
+		return
+	}
+
+	// The failure budget is validated before submitting anything, so that an
+	// invalid flag does not leave submitted but unstarted groups behind.
+	if _, err := withMaxFailures(controller.Request{}); err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+
+	if len(args) > 0 && isGroupArchive(args[0]) {
+		upArchiveRun(cmd, args)
+		return
+	}
 
-	// If a scale argument has been passed to submit,
-	// remove it from the args list, as start doesn't want it.
-	if len(args) > 1 {
-		args = args[:1]
+	if groups, scale, ok, err := parseMultiGroupArgs(fs, args, multiGroupFlags.AllLocal, true); err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	} else if ok {
+		scaleGiven := false
+		if len(args) > 0 {
+			_, err := strconv.Atoi(args[len(args)-1])
+			scaleGiven = err == nil
+		}
+		runMultiGroup("bring up", groups, func(ctx context.Context, group string) error {
+			if err := submitUpGroup(ctx, group, scale, scaleGiven); err != nil {
+				return maskAny(err)
+			}
+			return existingGroupAction(newController.Start)(ctx, group)
+		})
+		return
 	}
 
-	startRun(cmd, args)
+	if len(args) == 0 || len(args) > 2 {
+		cmd.Help()
+		exit(1)
+	}
+
+	// Without scale, the group is brought up using its desired scale. See
+	// upSubmitRequest.
+	scale := 1
+	scaleGiven := len(args) == 2
+	if scaleGiven {
+		var err error
+		scale, err = strconv.Atoi(args[1])
+		if err != nil {
+			newLogger.Error(newCtx, "%#v", maskAny(err))
+			exit(1)
+		}
+	}
+	req, ok, err := upSubmitRequest(newCtx, resolveGroup(args[0]), scale, scaleGiven)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+	if ok {
+		taskObject, err := newController.Submit(newCtx, req)
+		if err != nil {
+			newLogger.Error(newCtx, "%#v", maskAny(err))
+			diagnoseConnection(newCtx, err)
+			exit(1)
+		}
+
+		maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+			Request:    req,
+			Descriptor: "submit",
+			NoBlock:    globalFlags.NoBlock,
+			TaskID:     taskObject.ID,
+			Closer:     nil,
+		})
+	}
+
+	startRun(cmd, args[:1])
+}
+
+// upArchiveRun brings up the group contained in the archive given as first
+// argument. Unless a scale argument is given, the group is brought up using the
+// scale stored in the archive.
+func upArchiveRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting up from archive")
+
+	if len(args) > 2 {
+		cmd.Help()
+		exit(1)
+	}
+
+	raw, err := fs.ReadFile(args[0])
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+	req, metadata, err := readGroupArchive(raw)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+	req.Signed, err = verifyArchiveSignature(fs, args[0], raw)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+
+	scale := metadata.Scale
+	if len(args) == 2 {
+		scale, err = strconv.Atoi(args[1])
+		if err != nil {
+			newLogger.Error(newCtx, "%#v", maskAny(err))
+			exit(1)
+		}
+	}
+	req, err = withDesiredSlices(req, scale)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		exit(1)
+	}
+
+	taskObject, err := newController.Submit(newCtx, req)
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(1)
+	}
+
+	maybeBlockWithFeedback(newCtx, blockWithFeedbackCtx{
+		Request:    req,
+		Descriptor: "submit",
+		NoBlock:    globalFlags.NoBlock,
+		TaskID:     taskObject.ID,
+		Closer:     nil,
+	})
+
+	startRun(cmd, []string{req.Group})
 }
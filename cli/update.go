@@ -2,7 +2,9 @@ package cli
 
 import (
 	"fmt"
-	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -11,16 +13,20 @@ import (
 
 var (
 	updateFlags struct {
-		MaxGrowth int
-		MinAlive  int
-		ReadySecs int
+		MaxGrowth      int
+		MinAlive       int
+		ReadySecs      int
+		MaxPerMachine  int
+		Yes            bool
+		AllowDowngrade bool
+		BatchSize      string
 	}
 
 	updateCmd = &cobra.Command{
 		Use:   "update <group>",
 		Short: "Update a group",
 		Long:  "Update a group to the latest version on the local filesystem",
-		Run:   updateRun,
+		Run:   notifyingRun("update", updateRun),
 	}
 )
 
@@ -28,6 +34,10 @@ func init() {
 	updateCmd.PersistentFlags().IntVar(&updateFlags.MaxGrowth, "max-growth", 1, "maximum number of group slices added at a time")
 	updateCmd.PersistentFlags().IntVar(&updateFlags.MinAlive, "min-alive", 1, "minimum number of group slices staying alive at a time")
 	updateCmd.PersistentFlags().IntVar(&updateFlags.ReadySecs, "ready-secs", 30, "number of seconds to sleep before updating the next group slice")
+	updateCmd.PersistentFlags().IntVar(&updateFlags.MaxPerMachine, "max-per-machine", 1, "maximum number of group slices replaced at a time on a single machine (0 disables the limit)")
+	updateCmd.PersistentFlags().BoolVar(&updateFlags.Yes, "yes", false, "do not show the pending changes and ask for confirmation before updating")
+	updateCmd.PersistentFlags().StringVar(&updateFlags.BatchSize, "batch-size", "", "percentage of the group slices replaced at a time, e.g. 25%, overriding --max-growth and --min-alive")
+	updateCmd.PersistentFlags().BoolVar(&updateFlags.AllowDowngrade, "allow-downgrade", false, "allow updating to a lower version than the one of the group's manifest deployed")
 }
 
 func updateRun(cmd *cobra.Command, args []string) {
@@ -36,17 +46,23 @@ func updateRun(cmd *cobra.Command, args []string) {
 	group := ""
 	switch len(args) {
 	case 1:
-		group = args[0]
+		group = resolveGroup(args[0])
 	default:
 		cmd.Help()
-		os.Exit(1)
+		exit(1)
 	}
 
+	batchPercent, err := parseBatchSize(updateFlags.BatchSize)
+	handleUpdateCmdError(err)
+
 	newRequestConfig := controller.DefaultRequestConfig()
 	newRequestConfig.Group = group
-	req := controller.NewRequest(newRequestConfig)
+	req, err := withMaxFailures(withStagger(withRetry(controller.NewRequest(newRequestConfig))))
+	handleUpdateCmdError(err)
 
-	req, err := extendRequestWithContent(fs, req)
+	req, err = extendRequestWithContent(fs, req)
+	handleUpdateCmdError(err)
+	err = lintRequest(req)
 	handleUpdateCmdError(err)
 	req, err = newController.ExtendWithExistingSliceIDs(req)
 	handleUpdateCmdError(err)
@@ -56,10 +72,19 @@ func updateRun(cmd *cobra.Command, args []string) {
 		MinAlive:  updateFlags.MinAlive,
 		ReadySecs: updateFlags.ReadySecs,
 
+		MaxPerMachine:  updateFlags.MaxPerMachine,
+		AllowDowngrade: updateFlags.AllowDowngrade,
+		BatchPercent:   batchPercent,
+
 		// TODO Verbosity flag for displaying feedback about the current update steps?
 		// TODO Force flag for forcing the update even if the unit hashes do not differ?
 	}
 
+	if !updateFlags.Yes && !confirmUpdate(req, opts) {
+		newLogger.Info(newCtx, "Not updating group '%s'.", req.Group)
+		return
+	}
+
 	taskObject, err := newController.Update(newCtx, req, opts)
 	handleUpdateCmdError(err)
 	// The update creates new slices. Thus new slice IDs. We want to give the
@@ -67,7 +92,9 @@ func updateRun(cmd *cobra.Command, args []string) {
 	// slice IDs once the task has finished. We don't want to mix this specific
 	// detail with the general implementation of maybeBlockWithFeedback. Thus we
 	// wait for the task to be finished here manually.
+	stopProgress := startProgress(newCtx, "update", req)
 	taskObject, err = newController.WaitForTask(newCtx, taskObject.ID, nil)
+	stopProgress()
 	handleUpdateCmdError(err)
 
 	req, err = newController.ExtendWithExistingSliceIDs(req)
@@ -79,12 +106,151 @@ func updateRun(cmd *cobra.Command, args []string) {
 		NoBlock:    false,
 		TaskID:     taskObject.ID,
 		Closer:     nil,
+		NoProgress: true,
 	})
 }
 
+// confirmUpdate prints the changes the update of the given request would
+// apply and asks for confirmation. Groups that are already up to date are
+// not confirmed, so the update reports them as usual.
+func confirmUpdate(req controller.Request, opts controller.UpdateOptions) bool {
+	dirtyReq, ok, err := newController.GroupNeedsUpdate(newCtx, req)
+	handleUpdateCmdError(err)
+	if !ok {
+		return true
+	}
+
+	// Pinned slices are skipped by the update.
+	pinned, err := newController.PinnedSlices(newCtx, req.Group)
+	handleUpdateCmdError(err)
+	var dirtySliceIDs []string
+	for _, sliceID := range dirtyReq.SliceIDs {
+		if !containsString(pinned, sliceID) {
+			dirtySliceIDs = append(dirtySliceIDs, sliceID)
+		}
+	}
+	if len(dirtySliceIDs) == 0 {
+		return true
+	}
+	dirtyReq.SliceIDs = dirtySliceIDs
+
+	deployed, err := newController.DeployedUnits(newCtx, req)
+	handleUpdateCmdError(err)
+
+	// The units deployed are the ones of the first slice. Environment file units
+	// need to be rendered for this slice to be comparable.
+	local := req
+	sliceIDs := append([]string{}, req.SliceIDs...)
+	sort.Strings(sliceIDs)
+	if len(sliceIDs) > 0 {
+		local.SliceIDs = sliceIDs[:1]
+	}
+	local, err = local.ExtendSlices()
+	handleUpdateCmdError(err)
+	for i := range local.Units {
+		local.Units[i].Name = req.Units[i].Name
+	}
+
+	fmt.Print(createUpdatePlan(req.Group, deployed, local.Units, dirtyReq.SliceIDs, len(req.SliceIDs), opts))
+
+	return askForConfirmation("Update %d slices of group '%s'?", len(dirtyReq.SliceIDs), req.Group)
+}
+
+// createUpdatePlan describes the changes between the given deployed and local
+// units, and the order in which the given slices are going to be replaced.
+// The group size is the number of slices of the group, which batches are
+// computed from.
+func createUpdatePlan(group string, deployed, local []controller.Unit, sliceIDs []string, groupSize int, opts controller.UpdateOptions) string {
+	deployedContent := map[string]string{}
+	for _, u := range deployed {
+		deployedContent[u.Name] = u.Content
+	}
+	localContent := map[string]string{}
+	for _, u := range local {
+		localContent[u.Name] = u.Content
+	}
+
+	var names []string
+	for _, u := range deployed {
+		names = append(names, u.Name)
+	}
+	for _, u := range local {
+		if _, ok := deployedContent[u.Name]; !ok {
+			names = append(names, u.Name)
+		}
+	}
+	sort.Strings(names)
+
+	plan := ""
+	for _, name := range names {
+		oldName := name + " (deployed)"
+		if _, ok := deployedContent[name]; !ok {
+			oldName = "/dev/null"
+		}
+		newName := name + " (local)"
+		if _, ok := localContent[name]; !ok {
+			newName = "/dev/null"
+		}
+		if controller.UnitContentEqual(deployedContent[name], localContent[name]) {
+			// Formatting changes are not applied. See
+			// controller.NormalizeUnitContent.
+			continue
+		}
+		plan += unifiedDiff(oldName, newName, deployedContent[name], localContent[name])
+	}
+	if plan == "" {
+		// The unit contents are equal, but the unit hashes of some slices differ,
+		// e.g. because of slices in an inconsistent state.
+		plan = "No unit content changes.\n"
+	}
+
+	if opts.BatchPercent > 0 {
+		plan += fmt.Sprintf(
+			"\nSlices are replaced in the following order, in batches of %d%% of the group's %d slices (%d at a time), waiting %ds between slices.\n",
+			opts.BatchPercent,
+			groupSize,
+			opts.BatchSize(groupSize),
+			opts.ReadySecs,
+		)
+	} else {
+		plan += fmt.Sprintf(
+			"\nSlices are replaced in the following order, adding at most %d and keeping at least %d alive, waiting %ds between slices.\n",
+			opts.MaxGrowth,
+			opts.MinAlive,
+			opts.ReadySecs,
+		)
+	}
+	if opts.MaxPerMachine > 0 {
+		plan += fmt.Sprintf("At most %d slices are replaced at a time on a single machine, which may change the order.\n", opts.MaxPerMachine)
+	}
+	for i, sliceID := range sliceIDs {
+		plan += fmt.Sprintf("  %d. %s@%s\n", i+1, group, sliceID)
+	}
+
+	return plan
+}
+
+// parseBatchSize parses the percentage given using --batch-size, e.g. "25%".
+// An empty batch size is 0, which disables batches.
+func parseBatchSize(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+	if err != nil || !strings.HasSuffix(s, "%") || percent < 1 || percent > 100 {
+		return 0, maskAnyf(invalidArgumentsError, "--batch-size must be a percentage from 1%% to 100%%, got '%s'", s)
+	}
+
+	return percent, nil
+}
+
 func handleUpdateCmdError(err error) {
-	if err != nil {
+	if controller.IsDowngradeNotAllowed(err) {
+		newLogger.Error(newCtx, "Refusing to downgrade. Use --allow-downgrade to update anyway. (%s)", err.Error())
+		exit(1)
+	} else if err != nil {
 		fmt.Printf("%#v\n", maskAny(err))
-		os.Exit(1)
+		diagnoseConnection(newCtx, err)
+		exit(1)
 	}
 }
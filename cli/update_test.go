@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+func Test_Update_createUpdatePlan(t *testing.T) {
+	deployed := []controller.Unit{
+		{Name: "example-bar@.service", Content: "[Unit]\nDescription=bar\n"},
+		{Name: "example-foo@.service", Content: "[Unit]\nDescription=foo\n"},
+	}
+	local := []controller.Unit{
+		{Name: "example-baz@.service", Content: "[Unit]\nDescription=baz\n"},
+		{Name: "example-foo@.service", Content: "[Unit]\nDescription=foo v2\n"},
+	}
+	opts := controller.UpdateOptions{
+		MaxGrowth: 1,
+		MinAlive:  2,
+		ReadySecs: 30,
+	}
+
+	expected := `--- example-bar@.service (deployed)
++++ /dev/null
+@@ -1,2 +0,0 @@
+-[Unit]
+-Description=bar
+--- /dev/null
++++ example-baz@.service (local)
+@@ -0,0 +1,2 @@
++[Unit]
++Description=baz
+--- example-foo@.service (deployed)
++++ example-foo@.service (local)
+@@ -1,2 +1,2 @@
+ [Unit]
+-Description=foo
++Description=foo v2
+
+Slices are replaced in the following order, adding at most 1 and keeping at least 2 alive, waiting 30s between slices.
+  1. example@b2c
+  2. example@a1b
+`
+
+	plan := createUpdatePlan("example", deployed, local, []string{"b2c", "a1b"}, 4, opts)
+	if plan != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, plan)
+	}
+
+	opts.MaxPerMachine = 1
+	plan = createUpdatePlan("example", deployed, local, []string{"b2c", "a1b"}, 4, opts)
+	if !strings.Contains(plan, "At most 1 slices are replaced at a time on a single machine") {
+		t.Fatalf("expected per machine limit in:\n%s", plan)
+	}
+
+	opts.BatchPercent = 25
+	plan = createUpdatePlan("example", deployed, local, []string{"b2c", "a1b"}, 4, opts)
+	if !strings.Contains(plan, "in batches of 25% of the group's 4 slices (1 at a time)") {
+		t.Fatalf("expected batches in:\n%s", plan)
+	}
+}
+
+func Test_Update_parseBatchSize(t *testing.T) {
+	testCases := []struct {
+		BatchSize string
+		Expected  int
+		Valid     bool
+	}{
+		{BatchSize: "", Expected: 0, Valid: true},
+		{BatchSize: "25%", Expected: 25, Valid: true},
+		{BatchSize: "100%", Expected: 100, Valid: true},
+		{BatchSize: "25", Valid: false},
+		{BatchSize: "0%", Valid: false},
+		{BatchSize: "120%", Valid: false},
+		{BatchSize: "a%", Valid: false},
+	}
+
+	for i, testCase := range testCases {
+		percent, err := parseBatchSize(testCase.BatchSize)
+		if testCase.Valid && (err != nil || percent != testCase.Expected) {
+			t.Fatal("test case", i+1, "expected", testCase.Expected, "got", percent, err)
+		}
+		if !testCase.Valid && !IsInvalidArgumentsError(err) {
+			t.Fatal("test case", i+1, "expected", "invalid arguments error", "got", err)
+		}
+	}
+}
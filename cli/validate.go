@@ -3,7 +3,6 @@ package cli
 import (
 	"fmt"
 	"io/ioutil"
-	"os"
 	"sort"
 	"strings"
 
@@ -16,13 +15,13 @@ var (
 	validateCmd = &cobra.Command{
 		Use:   "validate [directory...]",
 		Short: "Validate groups",
-		Long:  "Validate group directories on the local filesystem",
+		Long:  "Validate group directories on the local filesystem. Using --strict, warnings fail the validation, and invalid groups exit with a non-zero code",
 		Run:   validateRun,
 	}
 )
 
 func validateRun(cmd *cobra.Command, args []string) {
-	groups := args
+	groups := resolveGroups(args)
 
 	// If no groups are specified, assume all directories in current
 	// directory are groups to be checked.
@@ -30,7 +29,7 @@ func validateRun(cmd *cobra.Command, args []string) {
 		files, err := ioutil.ReadDir(".")
 		if err != nil {
 			newLogger.Error(newCtx, "%#v", maskAny(err))
-			os.Exit(1)
+			exit(1)
 		}
 
 		for _, file := range files {
@@ -39,7 +38,7 @@ func validateRun(cmd *cobra.Command, args []string) {
 				subfiles, err := ioutil.ReadDir(file.Name())
 				if err != nil {
 					newLogger.Error(newCtx, "%#v", maskAny(err))
-					os.Exit(1)
+					exit(1)
 				}
 				if len(subfiles) == 0 {
 					continue
@@ -62,18 +61,26 @@ func validateRun(cmd *cobra.Command, args []string) {
 		request, err := extendRequestWithContent(fs, request)
 		if err != nil {
 			newLogger.Error(newCtx, "%#v", maskAny(err))
-			os.Exit(1)
+			exit(1)
 		}
 		requests = append(requests, request)
 	}
 
+	// In strict mode, warnings fail the validation, so that CI can gate
+	// changes of groups.
+	failed := false
 	for _, request := range requests {
+		for _, warning := range requestWarnings(request) {
+			newLogger.Warning(newCtx, "Group '%v': %s.", request.Group, warning)
+			failed = failed || lintFlags.Strict
+		}
 		ok, err := controller.ValidateRequest(request)
 		if ok {
 			fmt.Printf("Group '%v' is valid.\n", request.Group)
 		} else {
 			validationErr := err.(controller.ValidationError)
 			fmt.Printf("Group '%v' not valid: %v", request.Group, FormatValidationError(validationErr))
+			failed = failed || lintFlags.Strict
 		}
 	}
 
@@ -83,5 +90,10 @@ func validateRun(cmd *cobra.Command, args []string) {
 	} else {
 		validationErr := err.(controller.ValidationError)
 		fmt.Printf("Groups are not valid globally: %v\n", FormatValidationError(validationErr))
+		failed = failed || lintFlags.Strict
+	}
+
+	if failed {
+		exit(1)
 	}
 }
@@ -2,22 +2,62 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/fleet"
 )
 
 var (
-	projectBuild   string
-	projectVersion string
+	projectBuild     string
+	projectBuildDate string
+	projectVersion   string
 
 	versionCmd = &cobra.Command{
 		Use:   "version",
 		Short: "Print version",
-		Long:  "Print inagoctl version",
+		Long:  "Print inagoctl version and the version of the fleet API of the configured endpoint",
 		Run:   versionRun,
 	}
 )
 
 func versionRun(cmd *cobra.Command, args []string) {
 	fmt.Printf("inagoctl %s (%s)\n", projectVersion, projectBuild)
+	fmt.Printf("Build date: %s\n", valueOrUnknown(projectBuildDate))
+
+	apiVersion, err := newFleet.APIVersion(newCtx)
+	if err != nil {
+		newLogger.Debug(newCtx, "cli: fetching fleet API version failed: %#v", maskAny(err))
+		fmt.Printf("Fleet API:  unknown (%s not reachable)\n", globalFlags.FleetEndpoint)
+		return
+	}
+	fmt.Printf("Fleet API:  %s (%s)\n", apiVersion, globalFlags.FleetEndpoint)
+
+	if !fleet.IsSupportedAPIVersion(apiVersion) {
+		newLogger.Warning(
+			newCtx,
+			"Fleet API version '%s' is not supported. Supported versions are: %s.",
+			apiVersion,
+			strings.Join(fleet.SupportedAPIVersions, ", "),
+		)
+	}
+}
+
+func valueOrUnknown(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+
+	return v
+}
+
+// userAgent returns the User-Agent header inagoctl calls the fleet API with,
+// e.g. "inagoctl/0.4.0".
+func userAgent() string {
+	if projectVersion == "" {
+		return "inagoctl"
+	}
+
+	return "inagoctl/" + projectVersion
 }
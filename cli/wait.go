@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+const (
+	// waitForActive waits for all units to be running, or ready in case the
+	// manifest of the group defines readiness.
+	waitForActive = "active"
+
+	// waitForStopped waits for all units to be stopped.
+	waitForStopped = "stopped"
+
+	// waitForGone waits for all units to be destroyed.
+	waitForGone = "gone"
+)
+
+var (
+	waitFlags struct {
+		For     string
+		Timeout time.Duration
+	}
+
+	waitCmd = &cobra.Command{
+		Use:   "wait <group[@slice]...>",
+		Short: "Wait for a group to reach a state",
+		Long:  "Wait for the specified group, or slices, to be active, stopped or gone, e.g. to be used in scripts between custom steps. Exits with 0 in case the state was reached, 1 in case the timeout expired and 2 in case waiting failed",
+		Run:   waitRun,
+	}
+)
+
+func init() {
+	waitCmd.PersistentFlags().StringVar(&waitFlags.For, "for", waitForActive, "state to wait for, one of active, stopped or gone")
+	waitCmd.PersistentFlags().DurationVar(&waitFlags.Timeout, "timeout", 5*time.Minute, "maximum time to wait for the state")
+}
+
+// waitStatuses returns the statuses of controller.WaitForStatus the given
+// --for state corresponds to. In case the state is unknown, an error that you
+// can identify using IsInvalidArgumentsError is returned.
+func waitStatuses(state string) ([]controller.Status, error) {
+	switch state {
+	case waitForActive:
+		return []controller.Status{controller.StatusRunning}, nil
+	case waitForStopped:
+		return []controller.Status{controller.StatusStopped}, nil
+	case waitForGone:
+		return []controller.Status{controller.StatusNotFound}, nil
+	}
+
+	return nil, maskAnyf(invalidArgumentsError, "--for must be one of %s, %s or %s, got '%s'", waitForActive, waitForStopped, waitForGone, state)
+}
+
+func waitRun(cmd *cobra.Command, args []string) {
+	newLogger.Debug(newCtx, "cli: starting wait")
+
+	if len(args) == 0 {
+		cmd.Help()
+		exit(2)
+	}
+
+	statuses, err := waitStatuses(waitFlags.For)
+	handleWaitCmdError(err)
+
+	newRequestConfig := controller.DefaultRequestConfig()
+	newRequestConfig.Group, newRequestConfig.SliceIDs, err = parseGroupCLIArgs(args)
+	handleWaitCmdError(err)
+	req, err := extendRequestWithReadiness(fs, controller.NewRequest(newRequestConfig))
+	handleWaitCmdError(err)
+	req.WaitTimeout = waitFlags.Timeout
+
+	newLogger.Info(newCtx, "Waiting for group '%s' to be %s.", req.Group, waitFlags.For)
+	err = newController.WaitForStatus(newCtx, req, nil, statuses...)
+	if controller.IsTimeout(err) {
+		newLogger.Error(newCtx, "Group '%s' did not become %s within %s.", req.Group, waitFlags.For, waitFlags.Timeout)
+		exit(1)
+	}
+	handleWaitCmdError(err)
+
+	newLogger.Info(newCtx, "Group '%s' is %s.", req.Group, waitFlags.For)
+}
+
+func handleWaitCmdError(err error) {
+	if err != nil {
+		newLogger.Error(newCtx, "%#v", maskAny(err))
+		diagnoseConnection(newCtx, err)
+		exit(2)
+	}
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/giantswarm/inago/controller"
+)
+
+func Test_Wait_waitStatuses(t *testing.T) {
+	RegisterTestingT(t)
+
+	statuses, err := waitStatuses("active")
+	Expect(err).To(BeNil())
+	Expect(statuses).To(Equal([]controller.Status{controller.StatusRunning}))
+
+	statuses, err = waitStatuses("stopped")
+	Expect(err).To(BeNil())
+	Expect(statuses).To(Equal([]controller.Status{controller.StatusStopped}))
+
+	statuses, err = waitStatuses("gone")
+	Expect(err).To(BeNil())
+	Expect(statuses).To(Equal([]controller.Status{controller.StatusNotFound}))
+
+	_, err = waitStatuses("running")
+	Expect(IsInvalidArgumentsError(err)).To(BeTrue())
+}
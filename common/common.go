@@ -50,3 +50,72 @@ func UnitBase(name string) string {
 	name = groupExp.ReplaceAllString(name, "")
 	return ExtExp.ReplaceAllString(name, "")
 }
+
+var (
+	// ServiceUnitExtension is the extension of service units.
+	ServiceUnitExtension = ".service"
+
+	// TriggerUnitExtensions contains the extensions of unit kinds activating a
+	// service unit. Unless configured otherwise using the Unit= directive, the
+	// activated service unit is named like the triggering unit.
+	TriggerUnitExtensions = []string{".timer", ".socket", ".path"}
+
+	// SupportedUnitExtensions contains the extensions of all unit kinds Inago
+	// is able to manage.
+	SupportedUnitExtensions = append([]string{ServiceUnitExtension, ".mount"}, TriggerUnitExtensions...)
+)
+
+// EnvFileExtension is the extension of environment file templates.
+var EnvFileExtension = ".env"
+
+// IsEnvFile checks whether the given file name has the EnvFileExtension.
+func IsEnvFile(name string) bool {
+	return UnitExtension(name) == EnvFileExtension
+}
+
+// UnitExtension returns the extension of the given unit name.
+//
+//   app@1.service  =>  .service
+//   app.timer      =>  .timer
+//
+func UnitExtension(name string) string {
+	return ExtExp.FindString(name)
+}
+
+// IsSupportedUnit checks whether the extension of the given unit name is
+// contained in SupportedUnitExtensions.
+func IsSupportedUnit(name string) bool {
+	return containsString(SupportedUnitExtensions, UnitExtension(name))
+}
+
+// IsTriggerUnit checks whether the extension of the given unit name is
+// contained in TriggerUnitExtensions.
+func IsTriggerUnit(name string) bool {
+	return containsString(TriggerUnitExtensions, UnitExtension(name))
+}
+
+// TriggeredUnit returns the name of the service unit activated by default by
+// the given trigger unit. In case the given unit is no trigger unit, an empty
+// string is returned.
+//
+//   app@1.timer   =>  app@1.service
+//   app.socket    =>  app.service
+//   app.service   =>  ""
+//
+func TriggeredUnit(name string) string {
+	if !IsTriggerUnit(name) {
+		return ""
+	}
+
+	return ExtExp.ReplaceAllString(name, ServiceUnitExtension)
+}
+
+func containsString(list []string, item string) bool {
+	for _, l := range list {
+		if l == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -126,6 +126,60 @@ func Test_UnitBase(t *testing.T) {
 	}
 }
 
+func Test_TriggeredUnit(t *testing.T) {
+	var testCases = []struct {
+		Input     string
+		Supported bool
+		Expected  string
+	}{
+		{
+			Input:     "app@1.service",
+			Supported: true,
+			Expected:  "",
+		},
+		{
+			Input:     "app@1.mount",
+			Supported: true,
+			Expected:  "",
+		},
+		{
+			Input:     "app@1.timer",
+			Supported: true,
+			Expected:  "app@1.service",
+		},
+		{
+			Input:     "app.socket",
+			Supported: true,
+			Expected:  "app.service",
+		},
+		{
+			Input:     "app@foo.path",
+			Supported: true,
+			Expected:  "app@foo.service",
+		},
+		{
+			Input:     "app.md",
+			Supported: false,
+			Expected:  "",
+		},
+		{
+			Input:     "app",
+			Supported: false,
+			Expected:  "",
+		},
+	}
+
+	for i, testCase := range testCases {
+		if IsSupportedUnit(testCase.Input) != testCase.Supported {
+			t.Fatal("case", i+1, "expected", testCase.Supported, "got", !testCase.Supported)
+		}
+		output := TriggeredUnit(testCase.Input)
+		if output != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", output)
+		}
+	}
+}
+
 // ExampleSliceID is an example of the SliceID function.
 func ExampleSliceID() {
 	for _, input := range []string{
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"strings"
+)
+
+// Aliases maps short names to the names of groups, e.g. "web" to
+// "frontend-nginx", so that interactive users can refer to groups using short
+// names, while the units deployed to the cluster keep their full names.
+type Aliases map[string]string
+
+// NewAliases creates aliases from the given map of short names to group
+// names. In case a name is empty or contains "@" or "/", or an alias refers to
+// another alias, an error that you can identify using IsInvalidArgument is
+// returned.
+func NewAliases(m map[string]string) (Aliases, error) {
+	aliases := Aliases{}
+	for alias, group := range m {
+		if !isAliasName(alias) {
+			return nil, maskAnyf(invalidArgumentError, "invalid alias '%s'", alias)
+		}
+		if !isAliasName(group) {
+			return nil, maskAnyf(invalidArgumentError, "alias '%s' refers to invalid group '%s'", alias, group)
+		}
+		if alias == group {
+			return nil, maskAnyf(invalidArgumentError, "alias '%s' refers to itself", alias)
+		}
+		if _, ok := m[group]; ok {
+			return nil, maskAnyf(invalidArgumentError, "alias '%s' refers to alias '%s'", alias, group)
+		}
+		aliases[alias] = group
+	}
+
+	return aliases, nil
+}
+
+func isAliasName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, "@/")
+}
+
+// Resolve replaces the alias the given group expression refers to with the
+// name of the group. Slice expressions are kept, e.g. "web@1" resolves to
+// "frontend-nginx@1". Expressions not referring to an alias are returned as
+// they are.
+func (a Aliases) Resolve(expr string) string {
+	name, slice := expr, ""
+	if i := strings.Index(expr, "@"); i >= 0 {
+		name, slice = expr[:i], expr[i:]
+	}
+
+	group, ok := a[strings.TrimRight(name, "/")]
+	if !ok {
+		return expr
+	}
+
+	return group + slice
+}
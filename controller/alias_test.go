@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAliases_Resolve(t *testing.T) {
+	RegisterTestingT(t)
+
+	aliases, err := NewAliases(map[string]string{"web": "frontend-nginx", "db": "backend-postgres"})
+	Expect(err).To(BeNil())
+
+	testCases := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "web", Expected: "frontend-nginx"},
+		{Input: "web/", Expected: "frontend-nginx"},
+		{Input: "web@1", Expected: "frontend-nginx@1"},
+		{Input: "db@", Expected: "backend-postgres@"},
+		{Input: "frontend-nginx@1", Expected: "frontend-nginx@1"},
+		{Input: "webapp", Expected: "webapp"},
+		{Input: "3", Expected: "3"},
+	}
+	for _, testCase := range testCases {
+		Expect(aliases.Resolve(testCase.Input)).To(Equal(testCase.Expected), testCase.Input)
+	}
+}
+
+func TestNewAliases_Invalid(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, m := range []map[string]string{
+		{"": "frontend-nginx"},
+		{"web@1": "frontend-nginx"},
+		{"web": ""},
+		{"web": "groups/frontend-nginx"},
+		{"web": "web"},
+		{"web": "nginx", "nginx": "frontend-nginx"},
+	} {
+		_, err := NewAliases(m)
+		Expect(IsInvalidArgument(err)).To(BeTrue(), "%v", m)
+	}
+}
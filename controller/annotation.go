@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/state"
+)
+
+// annotationNamespace is the namespace of the state store annotations of
+// slices are stored in, keyed by group.
+const annotationNamespace = "annotation"
+
+// SliceAnnotation is a note attached to a slice using AnnotateSlices, e.g.
+// "cordoned: investigating OOM". An empty SliceID refers to the group itself.
+type SliceAnnotation struct {
+	SliceID string    `json:"slice-id,omitempty"`
+	Key     string    `json:"key"`
+	Value   string    `json:"value"`
+	Since   time.Time `json:"since"`
+}
+
+// groupAnnotations is stored for each group having annotated slices.
+type groupAnnotations struct {
+	Annotations []SliceAnnotation `json:"annotations"`
+}
+
+func (c controller) AnnotateSlices(ctx context.Context, group string, sliceIDs []string, annotations map[string]string) error {
+	c.Config.Logger.Debug(ctx, "controller: annotating slices %v of group '%s'", sliceIDs, group)
+
+	if c.Config.StateStore == nil {
+		return maskAnyf(invalidArgumentError, "annotating slices requires a state store")
+	}
+	for key := range annotations {
+		if key == "" {
+			return maskAnyf(invalidArgumentError, "annotation key must not be empty")
+		}
+	}
+	if len(sliceIDs) == 0 {
+		sliceIDs = []string{""}
+	}
+
+	existing, err := c.SliceAnnotations(ctx, group)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	// Annotations given again are replaced, keeping the time they were
+	// created in case the value did not change.
+	var kept []SliceAnnotation
+	for _, a := range existing {
+		if value, ok := annotations[a.Key]; ok && contains(sliceIDs, a.SliceID) && value != a.Value {
+			continue
+		}
+		kept = append(kept, a)
+	}
+	now := time.Now()
+	for _, sliceID := range sliceIDs {
+		for key, value := range annotations {
+			if value == "" || hasAnnotation(kept, sliceID, key) {
+				continue
+			}
+			kept = append(kept, SliceAnnotation{SliceID: sliceID, Key: key, Value: value, Since: now})
+		}
+	}
+	sort.Sort(sliceAnnotationsBySlice(kept))
+
+	err = c.Config.StateStore.Set(annotationNamespace, group, groupAnnotations{Annotations: kept})
+	if state.IsInvalidKey(err) {
+		return maskAnyf(invalidArgumentError, "group: %s", err.Error())
+	} else if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (c controller) SliceAnnotations(ctx context.Context, group string) ([]SliceAnnotation, error) {
+	if c.Config.StateStore == nil {
+		return nil, nil
+	}
+
+	var annotations groupAnnotations
+	err := c.Config.StateStore.Get(annotationNamespace, group, &annotations)
+	if state.IsNotFound(err) {
+		return nil, nil
+	} else if state.IsInvalidKey(err) {
+		return nil, maskAnyf(invalidArgumentError, "group: %s", err.Error())
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return annotations.Annotations, nil
+}
+
+// hasAnnotation checks whether the given annotations contain the given key
+// for the given slice.
+func hasAnnotation(annotations []SliceAnnotation, sliceID, key string) bool {
+	for _, a := range annotations {
+		if a.SliceID == sliceID && a.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+type sliceAnnotationsBySlice []SliceAnnotation
+
+func (s sliceAnnotationsBySlice) Len() int      { return len(s) }
+func (s sliceAnnotationsBySlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s sliceAnnotationsBySlice) Less(i, j int) bool {
+	if s[i].SliceID != s[j].SliceID {
+		return s[i].SliceID < s[j].SliceID
+	}
+	return s[i].Key < s[j].Key
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/state"
+)
+
+func TestController_AnnotateSlices(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	c, _ := getTestController()
+
+	err := c.AnnotateSlices(ctx, "app", []string{"1"}, map[string]string{"cordoned": "investigating OOM"})
+	Expect(IsInvalidArgument(err)).To(BeTrue())
+	annotations, err := c.SliceAnnotations(ctx, "app")
+	Expect(err).To(BeNil())
+	Expect(annotations).To(BeEmpty())
+
+	c.Config.StateStore = state.NewMemoryStore()
+	Expect(c.AnnotateSlices(ctx, "app", []string{"2", "1"}, map[string]string{"cordoned": "investigating OOM", "ticket": "OPS-1"})).To(Succeed())
+	Expect(c.AnnotateSlices(ctx, "app", nil, map[string]string{"owner": "frontend"})).To(Succeed())
+	annotations, err = c.SliceAnnotations(ctx, "app")
+	Expect(err).To(BeNil())
+	Expect(annotations).To(HaveLen(5))
+	Expect(annotations[0].SliceID).To(Equal(""))
+	Expect(annotations[1].SliceID).To(Equal("1"))
+	Expect(annotations[1].Key).To(Equal("cordoned"))
+	Expect(annotations[1].Value).To(Equal("investigating OOM"))
+	since := annotations[1].Since
+
+	// Unchanged values keep their time, changed values are replaced, and empty
+	// values remove keys.
+	Expect(c.AnnotateSlices(ctx, "app", []string{"1"}, map[string]string{"cordoned": "investigating OOM", "ticket": "OPS-2"})).To(Succeed())
+	Expect(c.AnnotateSlices(ctx, "app", []string{"2"}, map[string]string{"cordoned": "", "ticket": ""})).To(Succeed())
+	annotations, err = c.SliceAnnotations(ctx, "app")
+	Expect(err).To(BeNil())
+	Expect(annotations).To(HaveLen(3))
+	Expect(annotations[1].Since).To(Equal(since))
+	Expect(annotations[2].Key).To(Equal("ticket"))
+	Expect(annotations[2].Value).To(Equal("OPS-2"))
+
+	err = c.AnnotateSlices(ctx, "app", []string{"1"}, map[string]string{"": "value"})
+	Expect(IsInvalidArgument(err)).To(BeTrue())
+}
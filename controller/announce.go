@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/giantswarm/inago/common"
+)
+
+// AnnounceConfig configures the sidekick units announcing the units of a
+// group in etcd. See Request.WithAnnounceUnits.
+type AnnounceConfig struct {
+	// Prefix is the etcd directory units are announced in, e.g. "/services".
+	Prefix string
+
+	// TTL is the time an announcement expires after, in case its sidekick
+	// stops refreshing it, e.g. because the machine died. Announcements are
+	// refreshed each half TTL.
+	TTL time.Duration
+}
+
+// DefaultAnnounceConfig provides a default configuration to announce units.
+func DefaultAnnounceConfig() AnnounceConfig {
+	return AnnounceConfig{
+		Prefix: "/services",
+		TTL:    60 * time.Second,
+	}
+}
+
+// announceUnitTemplate is the template of the content of sidekick units. The
+// sidekick is bound to the announced unit, so it stops together with it, and
+// is scheduled on the same machine.
+var announceUnitTemplate = template.Must(template.New("announce-unit").Parse(`[Unit]
+Description=Announce {{.Unit}} in etcd
+BindsTo={{.Unit}}
+After={{.Unit}}
+
+[Service]
+Restart=on-failure
+EnvironmentFile=/etc/environment
+ExecStart=/bin/sh -c "while true; do etcdctl set {{.Key}} ${COREOS_PRIVATE_IPV4} --ttl {{.TTL}}; sleep {{.Interval}}; done"
+ExecStop=-/usr/bin/etcdctl rm {{.Key}}
+
+[X-Fleet]
+MachineOf={{.Unit}}
+`))
+
+// AnnounceUnitName returns the name of the sidekick unit announcing the given
+// unit.
+//
+//   mygroup-app@.service  =>  mygroup-app-announce@.service
+//   mygroup-app.service   =>  mygroup-app-announce.service
+//
+func AnnounceUnitName(name string) string {
+	base := strings.TrimSuffix(name, common.ServiceUnitExtension)
+	if strings.HasSuffix(base, "@") {
+		return strings.TrimSuffix(base, "@") + "-announce@" + common.ServiceUnitExtension
+	}
+
+	return base + "-announce" + common.ServiceUnitExtension
+}
+
+// NewAnnounceUnit creates the sidekick unit announcing the given unit in etcd
+// while it is running. The private IP of the unit's machine is stored using a
+// key named like the unit, within the configured prefix. Slices of a unit are
+// announced using their slice ID.
+//
+//   mygroup-app@.service  =>  /services/mygroup-app/<slice ID>
+//   mygroup-app.service   =>  /services/mygroup-app
+//
+// In case the TTL is shorter than two seconds, an error that you can identify
+// using IsInvalidRequest is returned.
+func NewAnnounceUnit(name string, config AnnounceConfig) (Unit, error) {
+	unitName := strings.Replace(name, "@.", "@%i.", 1)
+	key := strings.TrimRight(config.Prefix, "/") + "/" + strings.TrimSuffix(strings.TrimSuffix(name, common.ServiceUnitExtension), "@")
+	if strings.Contains(name, "@.") {
+		key += "/%i"
+	}
+	ttl := int(config.TTL.Seconds())
+	if ttl < 2 {
+		return Unit{}, maskAnyf(invalidArgumentError, "announce TTL must be at least 2s, got %s", config.TTL)
+	}
+
+	content := bytes.NewBuffer(nil)
+	err := announceUnitTemplate.Execute(content, struct {
+		Unit     string
+		Key      string
+		TTL      int
+		Interval int
+	}{
+		Unit:     unitName,
+		Key:      key,
+		TTL:      ttl,
+		Interval: ttl / 2,
+	})
+	if err != nil {
+		return Unit{}, maskAny(err)
+	}
+
+	newUnit := Unit{
+		Name:    AnnounceUnitName(name),
+		Content: content.String(),
+	}
+
+	return newUnit, nil
+}
+
+// WithAnnounceUnits returns a copy of r where a sidekick unit announcing the
+// unit in etcd is added for each service unit. See NewAnnounceUnit. Since the
+// sidekicks are part of the request, they are submitted, updated and
+// destroyed together with the slices they announce. Units writing environment
+// files, units activated by trigger units, and sidekicks themselves are not
+// announced. Sidekicks already contained in the request are kept, so that
+// groups can provide their own. In case the configuration is invalid, an error
+// that you can identify using IsInvalidRequest is returned.
+func (r Request) WithAnnounceUnits(config AnnounceConfig) (Request, error) {
+	names := map[string]struct{}{}
+	for _, u := range r.Units {
+		names[u.Name] = struct{}{}
+	}
+	triggered := map[string]struct{}{}
+	for _, u := range r.Units {
+		if name := common.TriggeredUnit(u.Name); name != "" {
+			triggered[name] = struct{}{}
+		}
+	}
+
+	newUnits := append([]Unit{}, r.Units...)
+	for _, u := range r.Units {
+		if u.EnvFile != "" || common.UnitExtension(u.Name) != common.ServiceUnitExtension {
+			continue
+		}
+		if _, ok := triggered[u.Name]; ok {
+			continue
+		}
+		if isAnnounceUnit(u.Name) {
+			continue
+		}
+		if _, ok := names[AnnounceUnitName(u.Name)]; ok {
+			continue
+		}
+
+		announceUnit, err := NewAnnounceUnit(u.Name, config)
+		if err != nil {
+			return Request{}, maskAny(err)
+		}
+		newUnits = append(newUnits, announceUnit)
+	}
+	r.Units = newUnits
+
+	return r, nil
+}
+
+// isAnnounceUnit checks whether the given unit is named like a sidekick unit
+// created by NewAnnounceUnit.
+func isAnnounceUnit(name string) bool {
+	return strings.HasSuffix(name, "-announce@"+common.ServiceUnitExtension) || strings.HasSuffix(name, "-announce"+common.ServiceUnitExtension)
+}
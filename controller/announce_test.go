@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_AnnounceUnitName(t *testing.T) {
+	testCases := map[string]string{
+		"mygroup-app@.service": "mygroup-app-announce@.service",
+		"mygroup-app.service":  "mygroup-app-announce.service",
+	}
+
+	for input, expected := range testCases {
+		if output := AnnounceUnitName(input); output != expected {
+			t.Fatal("input", input, "expected", expected, "got", output)
+		}
+	}
+}
+
+func Test_NewAnnounceUnit(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Expected []string
+	}{
+		{
+			Name: "mygroup-app@.service",
+			Expected: []string{
+				"BindsTo=mygroup-app@%i.service\n",
+				"MachineOf=mygroup-app@%i.service\n",
+				"etcdctl set /services/mygroup-app/%i ${COREOS_PRIVATE_IPV4} --ttl 60; sleep 30;",
+				"ExecStop=-/usr/bin/etcdctl rm /services/mygroup-app/%i\n",
+			},
+		},
+		{
+			Name: "mygroup-app.service",
+			Expected: []string{
+				"BindsTo=mygroup-app.service\n",
+				"MachineOf=mygroup-app.service\n",
+				"etcdctl set /services/mygroup-app ${COREOS_PRIVATE_IPV4} --ttl 60;",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		u, err := NewAnnounceUnit(testCase.Name, DefaultAnnounceConfig())
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if u.Name != AnnounceUnitName(testCase.Name) {
+			t.Fatal("case", i+1, "expected", AnnounceUnitName(testCase.Name), "got", u.Name)
+		}
+		for _, expected := range testCase.Expected {
+			if !strings.Contains(u.Content, expected) {
+				t.Fatal("case", i+1, "expected", expected, "in", u.Content)
+			}
+		}
+	}
+
+	_, err := NewAnnounceUnit("mygroup-app@.service", AnnounceConfig{Prefix: "/services", TTL: time.Second})
+	if !IsInvalidRequest(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
+
+func Test_Request_WithAnnounceUnits(t *testing.T) {
+	req := Request{
+		RequestConfig: RequestConfig{Group: "mygroup"},
+		Units: []Unit{
+			{Name: "mygroup-app@.service"},
+			{Name: "mygroup-api@.service"},
+			{Name: "mygroup-api-announce@.service", Content: "custom"},
+			{Name: "mygroup-cron@.service"},
+			{Name: "mygroup-cron@.timer"},
+			{Name: "mygroup-app-env@.service", EnvFile: "mygroup-app@.env"},
+		},
+	}
+
+	newConfig := DefaultAnnounceConfig()
+	newConfig.Prefix = "/apps/"
+	newReq, err := req.WithAnnounceUnits(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(newReq.Units) != len(req.Units)+1 {
+		t.Fatal("expected", len(req.Units)+1, "got", len(newReq.Units))
+	}
+	added := newReq.Units[len(newReq.Units)-1]
+	if added.Name != "mygroup-app-announce@.service" {
+		t.Fatal("expected", "mygroup-app-announce@.service", "got", added.Name)
+	}
+	if !strings.Contains(added.Content, "etcdctl set /apps/mygroup-app/%i ") {
+		t.Fatal("expected prefix in", added.Content)
+	}
+
+	// Adding sidekicks again does not change the request.
+	againReq, err := newReq.WithAnnounceUnits(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(againReq.Units) != len(newReq.Units) {
+		t.Fatal("expected", len(newReq.Units), "got", len(againReq.Units))
+	}
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"time"
+)
+
+// waitBackoff adapts the time to sleep between the status checks of wait
+// loops to the load of fleet. Intervals are doubled, up to Max, in case fleet
+// is unavailable or answers slowly, i.e. takes longer than half of the
+// current interval. This reduces the load caused by waiting operations during
+// cluster incidents. Intervals are halved, down to a quarter of Base, in case
+// transitions are imminent, i.e. some units already reached the desired status
+// and the others are likely to follow shortly. Otherwise intervals return to
+// Base step by step. In case Max is not greater than Base, Base is always
+// used.
+type waitBackoff struct {
+	Base     time.Duration
+	Max      time.Duration
+	interval time.Duration
+}
+
+func newWaitBackoff(base, max time.Duration) *waitBackoff {
+	return &waitBackoff{
+		Base:     base,
+		Max:      max,
+		interval: base,
+	}
+}
+
+// Next returns the time to sleep before the next status check, given the
+// latency of the last one, whether fleet was unavailable, and whether
+// transitions are imminent.
+func (b *waitBackoff) Next(latency time.Duration, unavailable, imminent bool) time.Duration {
+	if b.Max <= b.Base {
+		return b.Base
+	}
+
+	switch {
+	case unavailable || latency > b.interval/2:
+		b.interval *= 2
+		if b.interval > b.Max {
+			b.interval = b.Max
+		}
+	case imminent:
+		b.interval /= 2
+		if min := b.Base / 4; b.interval < min {
+			b.interval = min
+		}
+	case b.interval > b.Base:
+		b.interval -= (b.interval - b.Base + 1) / 2
+	default:
+		b.interval = b.Base
+	}
+
+	return b.interval
+}
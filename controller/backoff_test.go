@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_waitBackoff(t *testing.T) {
+	RegisterTestingT(t)
+
+	b := newWaitBackoff(time.Second, 8*time.Second)
+	fast := 10 * time.Millisecond
+
+	Expect(b.Next(fast, false, false)).To(Equal(time.Second))
+
+	// Slow responses and unavailability lengthen intervals up to the maximum.
+	Expect(b.Next(800*time.Millisecond, false, false)).To(Equal(2 * time.Second))
+	Expect(b.Next(fast, true, false)).To(Equal(4 * time.Second))
+	Expect(b.Next(fast, true, false)).To(Equal(8 * time.Second))
+	Expect(b.Next(fast, true, false)).To(Equal(8 * time.Second))
+
+	// Healthy responses return to the base interval step by step.
+	Expect(b.Next(fast, false, false)).To(BeNumerically("~", 4500*time.Millisecond, time.Millisecond))
+	Expect(b.Next(fast, false, false)).To(BeNumerically("~", 2750*time.Millisecond, time.Millisecond))
+	for i := 0; i < 40; i++ {
+		b.Next(fast, false, false)
+	}
+	Expect(b.Next(fast, false, false)).To(Equal(time.Second))
+
+	// Imminent transitions tighten intervals down to a quarter of the base.
+	Expect(b.Next(fast, false, true)).To(Equal(500 * time.Millisecond))
+	Expect(b.Next(fast, false, true)).To(Equal(250 * time.Millisecond))
+	Expect(b.Next(fast, false, true)).To(Equal(250 * time.Millisecond))
+	Expect(b.Next(fast, false, false)).To(Equal(time.Second))
+
+	// Without a greater maximum, the interval is fixed.
+	b = newWaitBackoff(time.Second, 0)
+	Expect(b.Next(5*time.Second, true, false)).To(Equal(time.Second))
+	Expect(b.Next(fast, false, true)).To(Equal(time.Second))
+}
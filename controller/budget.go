@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// FailureBudget is the number of slices an operation spanning many slices may
+// fail for, before it stops. A single bad machine then does not abort the
+// rollout of a large group. The zero value allows no failures, which is the
+// default behavior of all operations.
+type FailureBudget struct {
+	// Count is the number of slices allowed to fail.
+	Count int
+
+	// Percent is the share of the slices of the operation allowed to fail, from
+	// 0 to 100. It is used in case Count is zero.
+	Percent int
+}
+
+// ParseFailureBudget parses a failure budget given as number of slices, or as
+// percentage of the slices. An empty string is the zero budget. In case the
+// budget is malformed, an error that you can identify using IsInvalidRequest
+// is returned.
+//
+//   3
+//   10%
+//
+func ParseFailureBudget(s string) (FailureBudget, error) {
+	if s == "" {
+		return FailureBudget{}, nil
+	}
+
+	raw := strings.TrimSuffix(s, "%")
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return FailureBudget{}, maskAnyf(invalidArgumentError, "failure budget must be a number or percentage, got '%s'", s)
+	}
+	if raw == s {
+		return FailureBudget{Count: n}, nil
+	}
+	if n > 100 {
+		return FailureBudget{}, maskAnyf(invalidArgumentError, "failure budget must not exceed 100%%, got '%s'", s)
+	}
+
+	return FailureBudget{Percent: n}, nil
+}
+
+// String returns the budget the way ParseFailureBudget accepts it.
+func (b FailureBudget) String() string {
+	if b.Count == 0 && b.Percent > 0 {
+		return fmt.Sprintf("%d%%", b.Percent)
+	}
+
+	return strconv.Itoa(b.Count)
+}
+
+// IsZero checks whether the budget allows no failures at all.
+func (b FailureBudget) IsZero() bool {
+	return b.Count <= 0 && b.Percent <= 0
+}
+
+// Allowed returns the number of slices allowed to fail out of the given
+// number of slices. Percentages are rounded down.
+func (b FailureBudget) Allowed(slices int) int {
+	if b.Count > 0 {
+		return b.Count
+	}
+
+	return slices * b.Percent / 100
+}
+
+// Exceeded checks whether the given number of failed slices out of the given
+// number of slices exceeds the budget.
+func (b FailureBudget) Exceeded(failed, slices int) bool {
+	return failed > b.Allowed(slices)
+}
+
+// checkFailureBudget returns the given result in case its failures exceed the
+// failure budget of the given request. Otherwise the failed slices are only
+// reported as warning, and nil is returned.
+func (c controller) checkFailureBudget(ctx context.Context, req Request, result MultiSliceError) error {
+	if !result.HasFailed() {
+		return nil
+	}
+	slices := len(result.Succeeded) + len(result.Failed)
+	if req.MaxFailures.Exceeded(len(result.Failed), slices) {
+		return maskAny(result)
+	}
+
+	var sliceIDs []string
+	for _, sliceErr := range result.Failed {
+		sliceIDs = append(sliceIDs, sliceErr.SliceID)
+	}
+	c.Config.Logger.Warning(ctx, "Slices %v of group '%s' failed, which the failure budget of %s allows.", sliceIDs, req.Group, req.MaxFailures)
+
+	return nil
+}
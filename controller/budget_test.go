@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juju/errgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/logging"
+	"github.com/giantswarm/inago/task"
+)
+
+func TestParseFailureBudget(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []struct {
+		Input    string
+		Expected FailureBudget
+		Allowed  int
+	}{
+		{Input: "", Expected: FailureBudget{}, Allowed: 0},
+		{Input: "3", Expected: FailureBudget{Count: 3}, Allowed: 3},
+		{Input: "10%", Expected: FailureBudget{Percent: 10}, Allowed: 2},
+		{Input: "100%", Expected: FailureBudget{Percent: 100}, Allowed: 25},
+	}
+
+	for _, testCase := range testCases {
+		budget, err := ParseFailureBudget(testCase.Input)
+		Expect(err).To(BeNil(), testCase.Input)
+		Expect(budget).To(Equal(testCase.Expected), testCase.Input)
+		Expect(budget.Allowed(25)).To(Equal(testCase.Allowed), testCase.Input)
+		if testCase.Input != "" {
+			Expect(budget.String()).To(Equal(testCase.Input))
+		}
+	}
+
+	for _, input := range []string{"a", "-1", "%", "101%", "1.5"} {
+		_, err := ParseFailureBudget(input)
+		Expect(IsInvalidRequest(err)).To(BeTrue(), input)
+	}
+
+	Expect(FailureBudget{}.Exceeded(1, 100)).To(BeTrue())
+	Expect(FailureBudget{Percent: 10}.Exceeded(10, 100)).To(BeFalse())
+	Expect(FailureBudget{Percent: 10}.Exceeded(11, 100)).To(BeTrue())
+}
+
+func TestController_Start_MaxFailures(t *testing.T) {
+	RegisterTestingT(t)
+
+	newLoggingConfig := logging.DefaultConfig()
+	newLoggingConfig.LogLevel = "ERROR"
+	newLogger := logging.NewLogger(newLoggingConfig)
+
+	// All units started by the simulator fail.
+	newSimulatorConfig := fleet.DefaultSimulatorConfig()
+	newSimulatorConfig.Logger = newLogger
+	newSimulatorConfig.Latency = 10 * time.Millisecond
+	newSimulatorConfig.FailureRate = 1
+	newSimulator, err := fleet.NewSimulator(newSimulatorConfig)
+	Expect(err).To(BeNil())
+
+	newTaskServiceConfig := task.DefaultConfig()
+	newTaskServiceConfig.Logger = newLogger
+	newTaskServiceConfig.WaitSleep = 10 * time.Millisecond
+	newControllerConfig := DefaultConfig()
+	newControllerConfig.Fleet = newSimulator
+	newControllerConfig.TaskService = task.NewTaskService(newTaskServiceConfig)
+	newControllerConfig.Logger = newLogger
+	newControllerConfig.WaitCount = 1
+	newControllerConfig.WaitSleep = 10 * time.Millisecond
+	newControllerConfig.WaitTimeout = 2 * time.Second
+	c := controller{newControllerConfig}
+
+	ctx := context.Background()
+	sliceIDs := []string{"1", "2", "3", "4"}
+	for _, sliceID := range sliceIDs {
+		Expect(newSimulator.Submit(ctx, "budget-app@"+sliceID+".service", "[Service]\nExecStart=/bin/app\n")).To(Succeed())
+	}
+	req := Request{RequestConfig: RequestConfig{Group: "budget", SliceIDs: sliceIDs}}
+
+	// Failures exceeding the budget fail the operation, reporting all slices.
+	req.MaxFailures = FailureBudget{Percent: 50}
+	err = c.executeTaskAction(c.Start, ctx, req)
+	Expect(IsMultiSliceError(err)).To(BeTrue())
+	result := errgo.Cause(err).(MultiSliceError)
+	Expect(result.Failed).To(HaveLen(4))
+	Expect(IsSliceNotRunning(result.Failed[0].Err)).To(BeTrue())
+
+	// Failures within the budget do not.
+	req.MaxFailures = FailureBudget{Count: 4}
+	Expect(c.executeTaskAction(c.Start, ctx, req)).To(Succeed())
+}
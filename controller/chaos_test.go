@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/logging"
+	"github.com/giantswarm/inago/task"
+)
+
+// sliceCountingFleet records the minimum and maximum number of slices
+// deployed at the same time while submitting and destroying units.
+type sliceCountingFleet struct {
+	fleet.Fleet
+
+	Mutex sync.Mutex
+	Min   int
+	Max   int
+}
+
+func (f *sliceCountingFleet) Submit(ctx context.Context, name, content string) error {
+	if err := f.Fleet.Submit(ctx, name, content); err != nil {
+		return maskAny(err)
+	}
+	f.count()
+	return nil
+}
+
+func (f *sliceCountingFleet) Destroy(ctx context.Context, name string) error {
+	if err := f.Fleet.Destroy(ctx, name); err != nil {
+		return maskAny(err)
+	}
+	f.count()
+	return nil
+}
+
+func (f *sliceCountingFleet) count() {
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+
+	usl, _ := f.Fleet.GetStatusWithMatcher(func(string) bool { return true })
+	sliceIDs := map[string]struct{}{}
+	for _, us := range usl {
+		sliceIDs[us.SliceID] = struct{}{}
+	}
+	if len(sliceIDs) < f.Min {
+		f.Min = len(sliceIDs)
+	}
+	if len(sliceIDs) > f.Max {
+		f.Max = len(sliceIDs)
+	}
+}
+
+// TestUpdateWithStrategy_Chaos tests that updates against a flaky cluster
+// either succeed or fail, but never hang, and never violate MaxGrowth or
+// MinAlive in terms of deployed slices.
+func TestUpdateWithStrategy_Chaos(t *testing.T) {
+	oldContent := "[Service]\nExecStart=/bin/old\n"
+	newContent := "[Service]\nExecStart=/bin/new\n"
+	oldSliceIDs := []string{"1", "2", "3"}
+	opts := UpdateOptions{MaxGrowth: 1, MinAlive: 2}
+
+	for seed := int64(1); seed <= 10; seed++ {
+		newLoggingConfig := logging.DefaultConfig()
+		newLoggingConfig.LogLevel = "ERROR"
+		newLogger := logging.NewLogger(newLoggingConfig)
+
+		newSimulatorConfig := fleet.DefaultSimulatorConfig()
+		newSimulatorConfig.Logger = newLogger
+		newSimulatorConfig.Latency = 20 * time.Millisecond
+		newSimulatorConfig.Seed = seed
+		newSimulator, err := fleet.NewSimulator(newSimulatorConfig)
+		if err != nil {
+			t.Fatal("seed", seed, "expected", nil, "got", err)
+		}
+		ctx := context.Background()
+		for _, sliceID := range oldSliceIDs {
+			name := "chaos-app@" + sliceID + ".service"
+			if err := newSimulator.Submit(ctx, name, oldContent); err != nil {
+				t.Fatal("seed", seed, "expected", nil, "got", err)
+			}
+			if err := newSimulator.Start(ctx, name); err != nil {
+				t.Fatal("seed", seed, "expected", nil, "got", err)
+			}
+		}
+		time.Sleep(2 * newSimulatorConfig.Latency)
+		newSimulator.Config.Chaos = 0.05
+
+		countingFleet := &sliceCountingFleet{Fleet: newSimulator, Min: len(oldSliceIDs), Max: len(oldSliceIDs)}
+
+		newTaskServiceConfig := task.DefaultConfig()
+		newTaskServiceConfig.Logger = newLogger
+		newTaskServiceConfig.WaitSleep = 10 * time.Millisecond
+		newControllerConfig := DefaultConfig()
+		newControllerConfig.Fleet = countingFleet
+		newControllerConfig.TaskService = task.NewTaskService(newTaskServiceConfig)
+		newControllerConfig.Logger = newLogger
+		newControllerConfig.WaitCount = 1
+		newControllerConfig.WaitSleep = 10 * time.Millisecond
+		newControllerConfig.WaitTimeout = 2 * time.Second
+		newController := controller{newControllerConfig}
+
+		req := Request{
+			RequestConfig: RequestConfig{Group: "chaos", SliceIDs: oldSliceIDs},
+			Units:         []Unit{{Name: "chaos-app@.service", Content: newContent}},
+		}
+		result := make(chan error, 1)
+		go func() {
+			result <- newController.UpdateWithStrategy(ctx, req, opts)
+		}()
+
+		select {
+		case err = <-result:
+		case <-time.After(30 * time.Second):
+			t.Fatal("seed", seed, "expected update to finish, but it hangs")
+		}
+
+		countingFleet.Mutex.Lock()
+		if countingFleet.Max > len(oldSliceIDs)+opts.MaxGrowth {
+			t.Fatal("seed", seed, "expected at most", len(oldSliceIDs)+opts.MaxGrowth, "slices, got", countingFleet.Max)
+		}
+		if countingFleet.Min < opts.MinAlive {
+			t.Fatal("seed", seed, "expected at least", opts.MinAlive, "slices, got", countingFleet.Min)
+		}
+		countingFleet.Mutex.Unlock()
+
+		if err != nil {
+			// Failing is fine, as long as the update does not leave the group
+			// without slices. See the check of MinAlive above.
+			t.Log("seed", seed, "update failed:", err)
+			continue
+		}
+		usl, err := newSimulator.GetStatusWithMatcher(func(string) bool { return true })
+		if err != nil {
+			t.Fatal("seed", seed, "expected", nil, "got", err)
+		}
+		if len(usl) != len(oldSliceIDs) {
+			t.Fatal("seed", seed, "expected", len(oldSliceIDs), "got", len(usl))
+		}
+		for _, us := range usl {
+			if contains(oldSliceIDs, us.SliceID) {
+				t.Fatal("seed", seed, "expected slice", us.SliceID, "to be replaced")
+			}
+			content, err := newSimulator.GetContent(ctx, us.Name)
+			if err != nil {
+				t.Fatal("seed", seed, "expected", nil, "got", err)
+			}
+			if content != newContent {
+				t.Fatal("seed", seed, "expected", newContent, "got", content)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+// submitUnit submits the given unit to fleet. Destroying units is not
+// synchronous, so a unit destroyed shortly before, e.g. while updating a
+// group, may still be known to fleet's registry. Fleet then answers with 409
+// Conflict. Instead of returning the conflict, the unit is submitted again
+// once the old unit has been purged from the registry. In case a unit having
+// the same name is deployed on purpose, or the old unit is not purged within
+// Config.ConflictTimeout, an error that you can identify using
+// fleet.IsConflict is returned.
+func (c controller) submitUnit(ctx context.Context, name, content string) error {
+	err := c.Fleet.Submit(ctx, name, content)
+	if !fleet.IsConflict(err) || c.ConflictTimeout <= 0 {
+		return maskAny(err)
+	}
+
+	c.Config.Logger.Info(ctx, "Unit '%s' conflicts with a unit known to fleet, waiting for it to be purged.", name)
+	if err := c.waitForPurge(ctx, name, err); err != nil {
+		return maskAny(err)
+	}
+	if err := c.Fleet.Submit(ctx, name, content); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// waitForPurge waits until the unit having the given name is not known to
+// fleet anymore. Only destroyed units are purged, so the given conflict is
+// returned immediately in case the unit is still deployed, i.e. its desired
+// state is not inactive. In case the unit still exists after
+// Config.ConflictTimeout, the given conflict is returned as well. In case the
+// given context is canceled, its error is returned.
+func (c controller) waitForPurge(ctx context.Context, name string, conflict error) error {
+	us, err := c.Fleet.GetStatus(ctx, name)
+	if err == nil && us.Desired != "inactive" {
+		return maskAnyf(conflict, "unit '%s' is deployed", name)
+	} else if err != nil && !fleet.IsUnitNotFound(err) {
+		return maskAny(err)
+	}
+
+	timeout := time.After(c.ConflictTimeout)
+	backoff := newWaitBackoff(c.WaitSleep, c.WaitMaxSleep)
+	for {
+		start := time.Now()
+		exists, err := c.Fleet.Exists(ctx, name)
+		if err != nil {
+			return maskAny(err)
+		}
+		if !exists {
+			c.Config.Logger.Debug(ctx, "controller: unit '%s' has been purged", name)
+			return nil
+		}
+
+		select {
+		case <-timeout:
+			return maskAnyf(conflict, "unit '%s' still exists after %s", name, c.ConflictTimeout)
+		case <-ctx.Done():
+			return maskAny(ctx.Err())
+		case <-time.After(backoff.Next(time.Since(start), false, false)):
+		}
+	}
+}
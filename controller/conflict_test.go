@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juju/errgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/logging"
+)
+
+func TestController_submitUnit_Conflict(t *testing.T) {
+	RegisterTestingT(t)
+
+	newLoggingConfig := logging.DefaultConfig()
+	newLoggingConfig.LogLevel = "ERROR"
+	newLogger := logging.NewLogger(newLoggingConfig)
+
+	newSimulatorConfig := fleet.DefaultSimulatorConfig()
+	newSimulatorConfig.Logger = newLogger
+	newSimulatorConfig.Latency = 10 * time.Millisecond
+	newSimulatorConfig.PurgeDelay = 50 * time.Millisecond
+	newSimulator, err := fleet.NewSimulator(newSimulatorConfig)
+	Expect(err).To(BeNil())
+
+	newControllerConfig := DefaultConfig()
+	newControllerConfig.Fleet = newSimulator
+	newControllerConfig.Logger = newLogger
+	newControllerConfig.WaitSleep = 5 * time.Millisecond
+	newControllerConfig.ConflictTimeout = 1 * time.Second
+	c := controller{newControllerConfig}
+
+	ctx := context.Background()
+	name := "app-main@1.service"
+	content := "[Service]\nExecStart=/bin/app\n"
+
+	// Units destroyed shortly before are submitted again once they are purged.
+	Expect(c.submitUnit(ctx, name, content)).To(Succeed())
+	Expect(newSimulator.Destroy(ctx, name)).To(Succeed())
+	Expect(c.submitUnit(ctx, name, content)).To(Succeed())
+	content, err = newSimulator.GetContent(ctx, name)
+	Expect(err).To(BeNil())
+	Expect(content).To(Equal("[Service]\nExecStart=/bin/app\n"))
+
+	// Units not purged in time cause conflicts.
+	Expect(newSimulator.Destroy(ctx, name)).To(Succeed())
+	c.ConflictTimeout = 10 * time.Millisecond
+	Expect(fleet.IsConflict(c.submitUnit(ctx, name, content))).To(BeTrue())
+	c.ConflictTimeout = 0
+	Expect(fleet.IsConflict(c.submitUnit(ctx, name, content))).To(BeTrue())
+}
+
+// conflictingFleet answers submits of units known to the wrapped fleet with the
+// given conflict, like fleet does for units having different contents.
+type conflictingFleet struct {
+	fleet.Fleet
+	conflict error
+}
+
+func (f conflictingFleet) Submit(ctx context.Context, name, content string) error {
+	if exists, _ := f.Fleet.Exists(ctx, name); exists {
+		return f.conflict
+	}
+
+	return f.Fleet.Submit(ctx, name, content)
+}
+
+func TestController_submitUnit_Deployed(t *testing.T) {
+	RegisterTestingT(t)
+
+	newLoggingConfig := logging.DefaultConfig()
+	newLoggingConfig.LogLevel = "ERROR"
+	newLogger := logging.NewLogger(newLoggingConfig)
+
+	newSimulatorConfig := fleet.DefaultSimulatorConfig()
+	newSimulatorConfig.Logger = newLogger
+	newSimulatorConfig.PurgeDelay = 1 * time.Minute
+	newSimulator, err := fleet.NewSimulator(newSimulatorConfig)
+	Expect(err).To(BeNil())
+
+	ctx := context.Background()
+	content := "[Service]\nExecStart=/bin/app\n"
+
+	// Obtain a conflict by submitting a unit being purged.
+	Expect(newSimulator.Submit(ctx, "app-other.service", content)).To(Succeed())
+	Expect(newSimulator.Destroy(ctx, "app-other.service")).To(Succeed())
+	conflict := newSimulator.Submit(ctx, "app-other.service", content)
+	Expect(fleet.IsConflict(conflict)).To(BeTrue())
+
+	newControllerConfig := DefaultConfig()
+	newControllerConfig.Fleet = conflictingFleet{Fleet: newSimulator, conflict: conflict}
+	newControllerConfig.Logger = newLogger
+	newControllerConfig.WaitSleep = 5 * time.Millisecond
+	newControllerConfig.ConflictTimeout = 1 * time.Minute
+	c := controller{newControllerConfig}
+
+	// Units deployed on purpose are no leftovers, so there is no point waiting
+	// for them to be purged.
+	name := "app-main@1.service"
+	Expect(newSimulator.Submit(ctx, name, content)).To(Succeed())
+	start := time.Now()
+	Expect(fleet.IsConflict(c.submitUnit(ctx, name, "[Service]\nExecStart=/bin/other\n"))).To(BeTrue())
+	Expect(time.Since(start)).To(BeNumerically("<", 1*time.Second))
+
+	// Canceling the context ends waiting for units to be purged.
+	canceled, cancel := context.WithCancel(ctx)
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start = time.Now()
+	err = c.submitUnit(canceled, "app-other.service", content)
+	Expect(errgo.Cause(err)).To(Equal(context.Canceled))
+	Expect(time.Since(start)).To(BeNumerically("<", 1*time.Second))
+}
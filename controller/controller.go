@@ -14,7 +14,11 @@ import (
 	"github.com/giantswarm/inago/common"
 	"github.com/giantswarm/inago/fleet"
 	"github.com/giantswarm/inago/logging"
+	"github.com/giantswarm/inago/policy"
+	"github.com/giantswarm/inago/registry"
+	"github.com/giantswarm/inago/state"
 	"github.com/giantswarm/inago/task"
+	"github.com/giantswarm/inago/tracing"
 )
 
 // Config provides all necessary and injectable configurations for a new
@@ -26,6 +30,11 @@ type Config struct {
 
 	TaskService task.Service
 
+	// StateStore persists the idempotency keys of operations and the desired
+	// scale of groups. Nil disables both. See WithIdempotencyKey and
+	// Controller.SetDesiredScale.
+	StateStore state.Store
+
 	// Settings.
 
 	// WaitCount represents the amount of times a desired status is required to
@@ -37,13 +46,93 @@ type Config struct {
 	// WaitSleep represents the time to sleep between status-check cycles.
 	WaitSleep time.Duration
 
+	// WaitMaxSleep is the maximum time to sleep between status-check cycles.
+	// Wait loops sleep longer than WaitSleep in case fleet answers slowly or is
+	// unavailable, to not add to its load during incidents, and shorter while
+	// some units already reached the desired status. In case WaitMaxSleep is
+	// not greater than WaitSleep, WaitSleep is always used.
+	WaitMaxSleep time.Duration
+
+	// UnavailableTimeout is the maximum time wait loops keep retrying while
+	// fleet is unavailable or does not answer in time, using the backoff of
+	// WaitMaxSleep. Once fleet did not answer successfully for this long, the
+	// wait fails with the last error of fleet. An UnavailableTimeout of 0
+	// fails on the first such error.
+	UnavailableTimeout time.Duration
+
 	// WaitTimeout represents the maximum time to wait to reach a certain
 	// status. When the desired status was not reached within the given period of
 	// time, the wait ends.
 	WaitTimeout time.Duration
 
+	// ConflictTimeout is the maximum time to wait for a unit to be purged from
+	// fleet's registry, in case submitting a unit having the same name
+	// conflicts with it. This happens when units are destroyed and created
+	// again in quick succession, e.g. while updating. A ConflictTimeout of 0
+	// returns conflicts right away.
+	ConflictTimeout time.Duration
+
 	// Logger provides an initialised logger.
 	Logger logging.Logger
+
+	// Policy restricts the operations the controller is allowed to execute.
+	// Operations violating the policy fail with an error that you can identify
+	// using policy.IsPolicyViolation, before any change is made to the cluster.
+	Policy policy.Policy
+
+	// ForcePolicy defines whether policy violations are ignored. They are still
+	// logged as warnings.
+	ForcePolicy bool
+
+	// OverrideFreeze defines whether freezes are ignored, i.e. operations are
+	// executed even though groups are frozen by a freeze of Policy or the
+	// cluster is frozen. Freezes are still logged as warnings. See
+	// Controller.Freeze.
+	OverrideFreeze bool
+
+	// PrePullImages defines whether the Docker images used by a group are
+	// pulled before its units are started. Images are pulled using transient
+	// units on the machines the group is scheduled on. This prevents units from
+	// running into start timeouts while large images are downloaded. Failing to
+	// pull images is logged as a warning. See UnitImages.
+	PrePullImages bool
+
+	// Registry is used to verify that the Docker images used by the units of a
+	// group exist before the group is submitted, so that typos in image tags
+	// fail right away instead of on all machines. Nil disables the
+	// verification. See UnitImages.
+	Registry registry.Registry
+
+	// SliceIDRule defines how the IDs of new slices are created, e.g. to follow
+	// existing naming conventions like "app@web-01.service". The zero value
+	// creates random IDs. See ParseSliceIDRule.
+	SliceIDRule SliceIDRule
+
+	// ReadyStates defines the systemd states units are considered running in
+	// while waiting for them to start, e.g. only "active/running". Nil means
+	// the aggregated status of units decides, see StatusIndex. Ready states
+	// given for a unit using Request.ReadyStates win.
+	ReadyStates []ReadyState
+
+	// Events receives events describing the progress of operations, e.g. to
+	// render custom progress UIs in applications embedding Inago. Sending
+	// events never blocks. Events are dropped in case the channel is full, so
+	// it should be buffered. Nil disables events.
+	Events chan Event
+
+	// Tracer records operations and the calls of fleet they make as spans, so
+	// that deployments can be traced end-to-end. Nil disables tracing. See
+	// tracing.ConfigFromEnv.
+	Tracer *tracing.Tracer
+
+	// FlappingThreshold is the number of restarts within FlappingWindow a slice
+	// needs to exceed to be considered flapping. Restarts are tracked in
+	// StateStore whenever the status of a group is fetched, e.g. while waiting
+	// for it. See Controller.CheckFlapping.
+	FlappingThreshold int
+
+	// FlappingWindow is the period of time restarts of slices are counted in.
+	FlappingWindow time.Duration
 }
 
 // DefaultConfig provides a set of configurations with default values by best
@@ -61,10 +150,26 @@ func DefaultConfig() Config {
 	newConfig := Config{
 		Fleet:       newFleet,
 		TaskService: newTaskService,
+		StateStore:  nil,
 		WaitCount:   3,
 		WaitSleep:   1 * time.Second,
 		WaitTimeout: 5 * time.Minute,
 		Logger:      logging.NewLogger(logging.DefaultConfig()),
+		Policy:      policy.Policy{},
+		ForcePolicy: false,
+		Events:      nil,
+		Tracer:      nil,
+
+		WaitMaxSleep:       10 * time.Second,
+		UnavailableTimeout: 1 * time.Minute,
+		ConflictTimeout:    1 * time.Minute,
+
+		OverrideFreeze: false,
+		PrePullImages:  false,
+		Registry:       nil,
+
+		FlappingThreshold: 1,
+		FlappingWindow:    1 * time.Hour,
 	}
 
 	return newConfig
@@ -72,44 +177,278 @@ func DefaultConfig() Config {
 
 // Controller defines the interface a controller needs to implement to provide
 // operations for groups of unit files against a fleet cluster.
+//
+// Errors returned by the controller can be identified using the Is* functions
+// of this package, regardless of how often they have been wrapped. Errors
+// of the fleet API are passed through and can be identified using the
+// functions of the fleet package, e.g. fleet.IsConnectionFailed. Operations
+// returning a task.Task only validate their input synchronously. Errors
+// occurring while executing the task are stored in task.Task.Error and can be
+// identified the same way once the task has finished. See WaitForTask.
 type Controller interface {
+	// ExtendWithExistingSliceIDs sets the slice IDs of the given request to the
+	// IDs of all slices of the group deployed to the cluster. In case no unit of
+	// the group can be found, an error that you can identify using
+	// IsUnitNotFound is returned.
 	ExtendWithExistingSliceIDs(req Request) (Request, error)
 
+	// DeployedUnits fetches the unit files of the given group as deployed to
+	// the cluster. The unit names are turned back into unit file names, e.g.
+	// "mygroup-foo@1.service" becomes "mygroup-foo@.service". Units writing
+	// environment files carry the template they have been rendered from, so
+	// that they are rendered for each slice when submitted again. In case no
+	// unit of the group can be found, an error that you can identify using
+	// IsUnitNotFound is returned.
+	DeployedUnits(ctx context.Context, req Request) ([]Unit, error)
+
+	// SliceVersions fetches the semantic versions the slices of the given group
+	// carry, keyed by slice ID. Slices without version map to an empty string.
+	// See Request.WithSemVer. In case no unit of the group can be found, an
+	// error that you can identify using IsUnitNotFound is returned.
+	SliceVersions(ctx context.Context, req Request) (map[string]string, error)
+
+	// Pull fetches the unit files of the given group as deployed to the
+	// cluster, like DeployedUnits, so that they can be written to a group
+	// directory again. Labels stored in the units are removed from their
+	// content and returned separately. In case no unit of the group can be
+	// found, an error that you can identify using IsUnitNotFound is returned.
+	Pull(ctx context.Context, req Request) (GroupFiles, error)
+
+	// ExistingVersions returns the versions of the given group currently
+	// deployed to the cluster. See Request.WithVersion for how versioned groups
+	// are named. An empty list is returned in case no versioned group exists.
+	ExistingVersions(ctx context.Context, group string) ([]string, error)
+
+	// Exists checks whether the group of the given request is deployed to the
+	// cluster. In case slice IDs are given, all of these slices need to exist.
+	// In case the request contains the group's units, and the group either has
+	// no slices or slice IDs are given, only these units are looked up instead
+	// of listing all units known to fleet. See fleet.Fleet.Exists.
+	Exists(ctx context.Context, req Request) (bool, error)
+
+	// DesiredScale returns the number of slices the given group is intended to
+	// run, as recorded using SetDesiredScale. In case no desired scale has been
+	// recorded, or Config.StateStore is nil, an error that you can identify
+	// using IsDesiredScaleNotFound is returned.
+	DesiredScale(ctx context.Context, group string) (int, error)
+
+	// SetDesiredScale records the number of slices the given group is intended
+	// to run using Config.StateStore, e.g. to bring the group up again using
+	// the same scale. Nothing is recorded in case there is no state store. In
+	// case the scale is not positive, an error that you can identify using
+	// IsInvalidRequest is returned.
+	SetDesiredScale(ctx context.Context, group string, slices int) error
+
+	// GroupLabels returns the labels of all groups deployed to the cluster,
+	// keyed by group name. Only groups submitted with labels are returned. See
+	// Request.WithLabels.
+	GroupLabels(ctx context.Context) (map[string]map[string]string, error)
+
+	// Freeze freezes the cluster, so that all operations changing groups fail
+	// with an error that you can identify using IsFrozen, unless
+	// Config.OverrideFreeze is set. The given reason is reported by these
+	// errors. The freeze is stored using Config.StateStore. In case there is
+	// no state store, an error that you can identify using IsInvalidRequest is
+	// returned.
+	Freeze(ctx context.Context, reason string) error
+
+	// Unfreeze lifts the freeze of the cluster. See Freeze. Freezes defined
+	// by Config.Policy are not affected.
+	Unfreeze(ctx context.Context) error
+
+	// GetClusterFreeze returns the freeze of the cluster. The zero value is
+	// returned in case the cluster has never been frozen, or there is no state
+	// store.
+	GetClusterFreeze(ctx context.Context) (ClusterFreeze, error)
+
+	// MatchingGroups returns the names of all groups deployed to the cluster
+	// matching the given glob pattern, e.g. "api-*", sorted by name. Groups
+	// are found by filtering the names of units in the cluster, so no local
+	// group directories are needed. In case a unit name fits several groups
+	// matching the pattern, the shortest one is used. In case the pattern is
+	// malformed, an error that you can identify using IsInvalidRequest is
+	// returned.
+	MatchingGroups(ctx context.Context, pattern string) ([]string, error)
+
+	// OrphanUnits returns all units deployed to the cluster not belonging to
+	// any known group, sorted by name. Known groups are the given ones, e.g.
+	// the groups of the local filesystem, and the groups deployed with labels,
+	// see GroupLabels. A unit belongs to a group in case its name is prefixed
+	// with the group name and a dash.
+	OrphanUnits(ctx context.Context, groups []string) ([]OrphanUnit, error)
+
+	// PinSlices pins the given slices of the given group, so that updates skip
+	// them, e.g. to keep a misbehaving instance around for debugging. Pinned
+	// slices are kept in the state store. Without a state store, an error that
+	// you can identify using IsInvalidArgument is returned.
+	PinSlices(ctx context.Context, group string, sliceIDs []string) error
+
+	// UnpinSlices releases the given slices of the given group pinned using
+	// PinSlices. Slices not being pinned are ignored. The slices are not
+	// brought back in sync, use Update for that.
+	UnpinSlices(ctx context.Context, group string, sliceIDs []string) error
+
+	// PinnedSlices returns the sorted IDs of the slices of the given group
+	// pinned using PinSlices. Without a state store, no slices are pinned.
+	PinnedSlices(ctx context.Context, group string) ([]string, error)
+
+	// AnnotateSlices attaches the given key value pairs to the given slices of
+	// the given group, e.g. to tell others why a slice is stopped or pinned.
+	// Without slices, the annotations are attached to the group itself. Keys
+	// given again replace their values, and empty values remove keys.
+	// Annotations are kept in the state store. Without a state store, an error
+	// that you can identify using IsInvalidArgument is returned.
+	AnnotateSlices(ctx context.Context, group string, sliceIDs []string, annotations map[string]string) error
+
+	// SliceAnnotations returns the annotations of the given group attached
+	// using AnnotateSlices, sorted by slice ID and key. Without a state store,
+	// there are no annotations.
+	SliceAnnotations(ctx context.Context, group string) ([]SliceAnnotation, error)
+
+	// CheckFlapping checks whether slices of the given group restarted more
+	// often than Config.FlappingThreshold within Config.FlappingWindow.
+	// Restarts are observed whenever the status of the group is fetched, e.g.
+	// using GetStatus or WaitForStatus, and kept in the state store. In case
+	// slices are flapping, an error of type FlappingError, that you can
+	// identify using IsFlapping, is returned. Without a state store, restarts
+	// are not tracked and nil is returned.
+	CheckFlapping(ctx context.Context, group string) error
+
+	// MachineSlices returns the IDs of all slices of the given group having at
+	// least one unit scheduled on the given machine. The machine is identified
+	// by its fleet machine ID, a prefix of it, or its IP. Units of groups
+	// without slices are reported using an empty slice ID. Global units are
+	// ignored, since they run on all machines. An empty list is returned in
+	// case the group is not deployed. In case no machine is given, an error
+	// that you can identify using IsInvalidRequest is returned.
+	MachineSlices(ctx context.Context, req Request, machine string) ([]string, error)
+
+	// MachineGroups returns the sorted names of all groups having at least one
+	// unit scheduled on the given machine, identified like for MachineSlices.
+	// Only groups managed by inago are considered, i.e. the given groups, e.g.
+	// the groups of the local filesystem, and the groups deployed with labels,
+	// see GroupLabels. A unit belongs to the longest of these groups its name
+	// is prefixed with. Units of other groups and global units are ignored. In
+	// case no machine is given, an error that you can identify using
+	// IsInvalidRequest is returned.
+	MachineGroups(ctx context.Context, machine string, groups []string) ([]string, error)
+
+	// DamagedSlices returns the slices of the given group that need to be
+	// repaired, sorted by slice ID. A slice is damaged in case one of its units
+	// is scheduled on a machine that left the cluster, or is supposed to run
+	// while its systemd state is dead or failed. Units in one of their ready
+	// states are not dead, e.g. oneshot units that exited, see
+	// OneshotReadyStates. In case slice IDs are given, only those slices are
+	// checked. In case no unit of the group can be found, an error that you can
+	// identify using IsUnitNotFound is returned.
+	DamagedSlices(ctx context.Context, req Request) ([]DamagedSlice, error)
+
 	// GroupNeedsUpdate checks if the given group should be updated or not. To
 	// make a decision the unit content of each unit of each slice is compared
 	// using its unit hash. As soon as one unit hash differs, or a unit cannot be
 	// found, Inago assumes the whole group slice to be "dirty" and returns true
 	// having the group slices removed from the given req that are up to date,
-	// otherwise false, leaving the req as it is.
+	// otherwise false, leaving the req as it is. In case no unit of the group
+	// can be found, an error that you can identify using IsUnitNotFound is
+	// returned. In case an environment file template of the request cannot be
+	// rendered, an error that you can identify using IsInvalidRequest is
+	// returned.
 	GroupNeedsUpdate(ctx context.Context, req Request) (Request, bool, error)
 
+	// Plan computes the actions needed to bring the group deployed to the
+	// cluster in line with the units of the given request, without changing
+	// anything. Dirty slices are planned to be updated using the given options,
+	// in the order Update replaces them. Pinned slices are left as they are. In
+	// case the given scale is positive, slices are planned to be created or
+	// destroyed until the group runs that many slices, the way scaling a group
+	// does. Zero keeps the current number of slices. In case too many slices
+	// are pinned to scale down, an error that you can identify using
+	// IsInvalidArgument is returned. See Plan.
+	Plan(ctx context.Context, req Request, scale int, opts UpdateOptions) (Plan, error)
+
 	// Submit schedules a group on the configured fleet cluster. This is done by
 	// setting the state of the units in the group to loaded.
-	// If req.DesiredSlices is positive, new random (non conflicting) SliceIDs will be generated.
+	// If req.DesiredSlices is positive, new (non conflicting) SliceIDs will be generated
+	// according to Config.SliceIDRule.
 	// Otherwise the given req.SliceIDs will be used. Only one of those options can be used.
+	// Slices whose units are all deployed with identical unit hashes are
+	// skipped, so that submitting an unchanged group leaves it as it is.
+	//
+	// In case the request is malformed, an error that you can identify using
+	// IsInvalidRequest is returned. Units too large to be stored by fleet are
+	// reported by the returned ValidationError, see IsUnitTooLarge, instead of
+	// letting fleet fail with an internal server error. Extremely long ExecStart
+	// lines are logged as warnings, see UnitWarnings. In case the policy forbids
+	// the submit, an error that you can identify using IsPolicyViolation is
+	// returned. The task fails with a MultiSliceError in case some slices could
+	// not be submitted, and with an error that you can identify using IsTimeout
+	// in case the units are not loaded within Config.WaitTimeout. In case
+	// Config.SliceIDRule does not provide enough unused IDs, the task fails with
+	// an error that you can identify using IsSliceIDsExhausted.
 	Submit(ctx context.Context, req Request) (*task.Task, error)
 
 	// Start starts a group on the configured fleet cluster. This is done by
 	// setting the state of the units in the group to launched.
+	//
+	// In case the policy forbids the start, an error that you can identify
+	// using IsPolicyViolation is returned. The task fails with an error that
+	// you can identify using IsUnitNotFound or IsUnitSliceNotFound in case the
+	// group or some of the requested slices are not submitted, with a
+	// MultiSliceError in case some slices could not be started, and with an
+	// error that you can identify using IsTimeout in case the units are not
+	// running within Config.WaitTimeout.
 	Start(ctx context.Context, req Request) (*task.Task, error)
 
 	// Stop stops a group on the configured fleet cluster. This is done by
 	// setting the state of the units in the group to loaded.
+	//
+	// Errors are reported the same way as for Start.
 	Stop(ctx context.Context, req Request) (*task.Task, error)
 
 	// Destroy delets a group on the configured fleet cluster. This is done by
 	// setting the state of the units in the group to inactive.
+	//
+	// Errors are reported the same way as for Start.
 	Destroy(ctx context.Context, req Request) (*task.Task, error)
 
+	// Repair destroys the damaged slices of the given group, submits them again
+	// using the unit files deployed to the cluster, and starts them, so that
+	// fleet schedules them on healthy machines. See DamagedSlices. The task
+	// succeeds right away in case no slice is damaged. Errors of the task are
+	// the ones of Destroy, Submit and Start.
+	Repair(ctx context.Context, req Request) (*task.Task, error)
+
 	// GetStatus fetches the current status of a group. If the unit cannot be
 	// found, an error that you can identify using IsUnitNotFound is returned.
+	// In case only some of the requested slices cannot be found, an error that
+	// you can identify using IsUnitSliceNotFound is returned.
 	GetStatus(ctx context.Context, req Request) ([]fleet.UnitStatus, error)
 
-	// WaitForStatus waits for a group to reach the given status.
+	// WatchGroup watches the units of the group and slices of the given
+	// request, using fleet.WatchUnitStates. Unit states are fetched each
+	// Config.WaitSleep. Calling the returned function stops watching.
+	WatchGroup(ctx context.Context, req Request) (<-chan fleet.UnitState, func())
+
+	// WaitForStatus waits for a group to reach the given status. Whether units
+	// are running is decided by their ready states in case some are given using
+	// Request.ReadyStates or Config.ReadyStates. In case no status is given, an
+	// error that you can identify using IsInvalidRequest is returned. In case
+	// the group does not reach the status within Request.WaitTimeout, or
+	// Config.WaitTimeout in case it is not given, or until the deadline of the
+	// given context, whichever comes first, an error that
+	// you can identify using IsTimeout is returned. In case the given context is
+	// canceled, its error is returned. In case fleet is unavailable or does not
+	// answer in time for longer than Config.UnavailableTimeout, the last error
+	// of fleet is returned. In case a unit reports a state that cannot be
+	// aggregated, an error that you can identify using IsInvalidUnitStatus is
+	// returned.
 	WaitForStatus(ctx context.Context, req Request, closer <-chan struct{}, desiredStatuses ...Status) error
 
 	// WaitForTask waits for the given task to reach a final status. Once the
 	// given task has reached the final status, the final task representation is
+	// returned. Errors of the task itself are not returned, but stored in
+	// task.Task.Error. See task.HasFailedStatus. In case the task does not
+	// exist, an error that you can identify using task.IsTaskObjectNotFound is
 	// returned.
 	WaitForTask(ctx context.Context, taskID string, closer <-chan struct{}) (*task.Task, error)
 
@@ -117,7 +456,39 @@ type Controller interface {
 	// opts. The given req identifies the group to update. The given options
 	// define the strategy used to update the given group. See also
 	// UpdateOptions.
+	//
+	// In case the options cannot be applied to the group, an error that you
+	// can identify using IsUpdateNotAllowed is returned. In case the policy
+	// forbids the update, an error that you can identify using
+	// IsPolicyViolation is returned. The task fails with an error that you can
+	// identify using IsUnitsAlreadyUpToDate in case there is nothing to update,
+	// with an error that you can identify using IsUpdateFailed in case slices
+	// could not be replaced, and with an error that you can identify using
+	// IsTimeout in case new slices are not running within Config.WaitTimeout.
+	// In case the units carry a lower semantic version than any deployed slice
+	// and downgrades are not allowed, an error that you can identify using
+	// IsDowngradeNotAllowed is returned.
 	Update(ctx context.Context, req Request, opts UpdateOptions) (*task.Task, error)
+
+	// LastOperation returns the latest operation changing the given group,
+	// which can be reverted using Undo. In case no operation has been recorded,
+	// or there is no state store, an error that you can identify using
+	// IsUndoNotFound is returned.
+	LastOperation(ctx context.Context, group string) (UndoableOperation, error)
+
+	// Undo reverts the given group to the state recorded using
+	// Config.StateStore before the latest Submit, Update or Destroy changed it.
+	// Units are submitted again using the unit files they had, started or
+	// stopped as they were, and units added by the operation are destroyed once
+	// the others are running. Slices replaced by an update are thus brought
+	// back all at once. The undo is recorded itself, so undoing twice redoes
+	// the operation. In case nothing has been recorded, an error that you can
+	// identify using IsUndoNotFound is returned.
+	Undo(ctx context.Context, group string) (*task.Task, error)
+
+	// Events returns the channel configured using Config.Events. See also
+	// Event.
+	Events() <-chan Event
 }
 
 // NewController creates a new Controller that is configured with the given
@@ -128,6 +499,10 @@ type Controller interface {
 //   newController := controller.NewController(newConfig)
 //
 func NewController(config Config) Controller {
+	if config.Tracer != nil {
+		config.Fleet = tracedFleet{Fleet: config.Fleet, Tracer: config.Tracer}
+	}
+
 	newController := controller{
 		Config: config,
 	}
@@ -147,6 +522,16 @@ type Unit struct {
 
 	// Content represents normal systemd unit file content.
 	Content string
+
+	// EnvFile is the name of the environment file template the unit has been
+	// created from using NewEnvUnit, e.g. "appd@.env". It is empty for normal
+	// units.
+	EnvFile string
+
+	// EnvTemplate is the environment file template the unit has been created
+	// from using NewEnvUnit. The content of such units is rendered for each
+	// slice. See Request.ExtendSlices.
+	EnvTemplate string
 }
 
 func (c controller) GroupNeedsUpdate(ctx context.Context, req Request) (Request, bool, error) {
@@ -168,23 +553,34 @@ func (c controller) GroupNeedsUpdate(ctx context.Context, req Request) (Request,
 	}
 	c.Config.Logger.Debug(ctx, "controller: checking slice IDs")
 	for _, u := range req.Units {
-		unitFile, err := unit.NewUnitFile(u.Content)
-		if err != nil {
-			return Request{}, false, maskAny(err)
-		}
-		hash := unitFile.Hash().String()
-
 		for _, uhi := range uhis {
 			if common.UnitBase(u.Name) != uhi.Base {
 				continue
 			}
-			if hash == uhi.Hash {
+			// The content of units writing environment files differs per slice.
+			content, err := u.envUnitContent(req.Group, uhi.SliceID)
+			if err != nil {
+				return Request{}, false, maskAny(err)
+			}
+			unitFile, err := unit.NewUnitFile(content)
+			if err != nil {
+				return Request{}, false, maskAny(err)
+			}
+			if unitFile.Hash().String() == uhi.Hash {
 				continue
 			}
 			if contains(newSliceIDs, uhi.SliceID) {
 				// We already tracked this ID. Go ahead.
 				continue
 			}
+			// Unit hashes differ for unit files that are only formatted
+			// differently. We do not want to update slices for that.
+			deployed, err := c.Fleet.GetContent(ctx, uhi.Name)
+			if err != nil {
+				c.Config.Logger.Debug(ctx, "controller: cannot fetch content of unit '%s': %#v", uhi.Name, err)
+			} else if UnitContentEqual(deployed, content) {
+				continue
+			}
 
 			newSliceIDs = append(newSliceIDs, uhi.SliceID)
 		}
@@ -206,8 +602,23 @@ func (c controller) Submit(ctx context.Context, req Request) (*task.Task, error)
 	if ok, err := ValidateSubmitRequest(req); !ok {
 		return nil, errgo.Cause(err)
 	}
+	for _, warning := range UnitWarnings(req.Units) {
+		c.Config.Logger.Warning(ctx, "controller: %s", warning)
+	}
+	if _, err := ParsePlacement(req.Placement); err != nil {
+		return nil, maskAny(err)
+	}
+	if err := c.checkPolicy(ctx, policy.Submit, req); err != nil {
+		return nil, maskAny(err)
+	}
+	if err := c.verifyImages(ctx, req); err != nil {
+		return nil, maskAny(err)
+	}
 	action := func(ctx context.Context) error {
 		var err error
+		// Newly generated slice IDs are not in use, so there is nothing
+		// deployed that could be unchanged.
+		generated := req.DesiredSlices > 0 && req.isSliceable()
 		if req.DesiredSlices > 0 {
 			req, err = c.ExtendWithRandomSliceIDs(ctx, req)
 			if err != nil {
@@ -220,26 +631,64 @@ func (c controller) Submit(ctx context.Context, req Request) (*task.Task, error)
 			return maskAny(err)
 		}
 
-		c.Config.Logger.Debug(ctx, "action: submitting units")
+		contents := map[string]string{}
 		for _, unit := range req.Units {
-			err := c.Fleet.Submit(ctx, unit.Name, unit.Content)
+			content, err := withPlacement(unit.Content, req.Placement)
 			if err != nil {
 				return maskAny(err)
 			}
+			contents[unit.Name] = content
+		}
+
+		if !generated {
+			var unchanged []string
+			req, unchanged, err = c.withoutUnchangedSlices(ctx, req, contents)
+			if err != nil {
+				return maskAny(err)
+			}
+			if len(req.Units) == 0 {
+				c.Config.Logger.Info(ctx, "controller: group '%s' is deployed unchanged, skipping submit", req.Group)
+				return nil
+			}
+			if len(unchanged) > 0 {
+				c.Config.Logger.Info(ctx, "controller: skipping unchanged slices %v", unchanged)
+			}
+		}
+		names := requestUnitNames(req)
+		ctx = c.recordUndo(ctx, string(policy.Submit), req.Group, names)
+
+		c.Config.Logger.Debug(ctx, "action: submitting units")
+		result, err := forEachUnitBySlice(names, func(name string) error {
+			if err := c.submitUnit(ctx, name, contents[name]); err != nil {
+				return maskAny(err)
+			}
+			c.emitUnitEvent(ctx, EventUnitSubmitted, req.Group, name)
+			return nil
+		})
+		if err != nil {
+			return maskAny(err)
+		}
+		succeededReq, ok := succeededSlicesRequest(req, result)
+		if !ok {
+			return maskAny(result)
 		}
 
 		c.Config.Logger.Debug(ctx, "action: waiting for status of submitted units")
 		closer := make(chan struct{})
-		err = c.WaitForStatus(ctx, req, closer, StatusStopped)
+		err = c.WaitForStatus(ctx, succeededReq, closer, StatusStopped)
 		if err != nil {
 			return maskAny(err)
 		}
 
+		if result.HasFailed() {
+			return maskAny(result)
+		}
+
 		// TODO retry operations
 
 		return nil
 	}
-	taskObject, err := c.TaskService.Create(ctx, action)
+	taskObject, err := c.createTask(ctx, string(policy.Submit), req, action)
 	if err != nil {
 		return nil, maskAny(err)
 	}
@@ -249,6 +698,9 @@ func (c controller) Submit(ctx context.Context, req Request) (*task.Task, error)
 
 func (c controller) Start(ctx context.Context, req Request) (*task.Task, error) {
 	c.Config.Logger.Debug(ctx, "controller: handling start")
+	if err := c.checkPolicy(ctx, policy.Start, req); err != nil {
+		return nil, maskAny(err)
+	}
 
 	action := func(ctx context.Context) error {
 		c.Config.Logger.Debug(ctx, "action: fetching unit status list")
@@ -257,27 +709,37 @@ func (c controller) Start(ctx context.Context, req Request) (*task.Task, error)
 			return maskAny(err)
 		}
 
-		c.Config.Logger.Debug(ctx, "action: starting units")
-		for _, unitStatus := range unitStatusList {
-			err := c.Fleet.Start(ctx, unitStatus.Name)
-			if err != nil {
-				return maskAny(err)
+		if c.Config.PrePullImages {
+			c.Config.Logger.Debug(ctx, "action: pre-pulling images")
+			if err := c.prePullImages(ctx, req.Group, unitStatusList); err != nil {
+				c.Config.Logger.Warning(ctx, "Failed to pre-pull images of group '%s'. (%s)", req.Group, err.Error())
 			}
 		}
 
+		c.Config.Logger.Debug(ctx, "action: starting units")
+		result, err := c.startUnits(ctx, req, unitStatusList)
+		if err != nil {
+			return maskAny(err)
+		}
+		succeededReq, ok := succeededSlicesRequest(req, result)
+		if !ok {
+			return maskAny(result)
+		}
+
 		c.Config.Logger.Debug(ctx, "action: waiting for status of started units")
-		closer := make(chan struct{})
-		err = c.WaitForStatus(ctx, req, closer, StatusRunning)
+		err = c.waitForRunning(ctx, succeededReq)
 		if err != nil {
 			return maskAny(err)
 		}
 
-		// TODO retry operations
+		if err := c.checkFailureBudget(ctx, req, result); err != nil {
+			return maskAny(err)
+		}
 
 		return nil
 	}
 
-	taskObject, err := c.TaskService.Create(ctx, action)
+	taskObject, err := c.createTask(ctx, string(policy.Start), req, action)
 	if err != nil {
 		return nil, maskAny(err)
 	}
@@ -287,6 +749,9 @@ func (c controller) Start(ctx context.Context, req Request) (*task.Task, error)
 
 func (c controller) Stop(ctx context.Context, req Request) (*task.Task, error) {
 	c.Config.Logger.Debug(ctx, "controller: handling stop")
+	if err := c.checkPolicy(ctx, policy.Stop, req); err != nil {
+		return nil, maskAny(err)
+	}
 
 	action := func(ctx context.Context) error {
 		unitStatusList, err := c.groupStatusWithValidate(ctx, req)
@@ -294,25 +759,37 @@ func (c controller) Stop(ctx context.Context, req Request) (*task.Task, error) {
 			return maskAny(err)
 		}
 
-		for _, unitStatus := range unitStatusList {
-			err := c.Fleet.Stop(ctx, unitStatus.Name)
-			if err != nil {
+		result, err := forEachUnitBySlice(unitNames(unitStatusList), func(name string) error {
+			if err := c.Fleet.Stop(ctx, name); err != nil {
 				return maskAny(err)
 			}
+			c.emitUnitEvent(ctx, EventUnitStopped, req.Group, name)
+			return nil
+		})
+		if err != nil {
+			return maskAny(err)
+		}
+		succeededReq, ok := succeededSlicesRequest(req, result)
+		if !ok {
+			return maskAny(result)
 		}
 
 		closer := make(chan struct{})
-		err = c.WaitForStatus(ctx, req, closer, StatusStopped, StatusFailed)
+		err = c.WaitForStatus(ctx, succeededReq, closer, StatusStopped, StatusFailed)
 		if err != nil {
 			return maskAny(err)
 		}
 
+		if result.HasFailed() {
+			return maskAny(result)
+		}
+
 		// TODO retry operations
 
 		return nil
 	}
 
-	taskObject, err := c.TaskService.Create(ctx, action)
+	taskObject, err := c.createTask(ctx, string(policy.Stop), req, action)
 	if err != nil {
 		return nil, maskAny(err)
 	}
@@ -322,32 +799,50 @@ func (c controller) Stop(ctx context.Context, req Request) (*task.Task, error) {
 
 func (c controller) Destroy(ctx context.Context, req Request) (*task.Task, error) {
 	c.Config.Logger.Debug(ctx, "controller: handling destroy")
+	if err := c.checkPolicy(ctx, policy.Destroy, req); err != nil {
+		return nil, maskAny(err)
+	}
 
 	action := func(ctx context.Context) error {
 		unitStatusList, err := c.groupStatusWithValidate(ctx, req)
 		if err != nil {
 			return maskAny(err)
 		}
+		ctx = c.recordUndo(ctx, string(policy.Destroy), req.Group, unitNames(unitStatusList))
 
-		for _, unitStatus := range unitStatusList {
-			err := c.Fleet.Destroy(ctx, unitStatus.Name)
-			if err != nil {
+		result, err := forEachUnitBySlice(unitNames(unitStatusList), func(name string) error {
+			if err := c.Fleet.Destroy(ctx, name); fleet.IsUnitNotFound(err) {
+				// The unit has been destroyed in the meantime.
+			} else if err != nil {
 				return maskAny(err)
 			}
+			c.emitUnitEvent(ctx, EventUnitDestroyed, req.Group, name)
+			return nil
+		})
+		if err != nil {
+			return maskAny(err)
+		}
+		succeededReq, ok := succeededSlicesRequest(req, result)
+		if !ok {
+			return maskAny(result)
 		}
 
 		closer := make(chan struct{})
-		err = c.WaitForStatus(ctx, req, closer, StatusNotFound)
+		err = c.WaitForStatus(ctx, succeededReq, closer, StatusNotFound)
 		if err != nil {
 			return maskAny(err)
 		}
 
+		if result.HasFailed() {
+			return maskAny(result)
+		}
+
 		// TODO retry operations
 
 		return nil
 	}
 
-	taskObject, err := c.TaskService.Create(ctx, action)
+	taskObject, err := c.createTask(ctx, string(policy.Destroy), req, action)
 	if err != nil {
 		return nil, maskAny(err)
 	}
@@ -357,11 +852,22 @@ func (c controller) Destroy(ctx context.Context, req Request) (*task.Task, error
 
 func (c controller) Update(ctx context.Context, req Request, opts UpdateOptions) (*task.Task, error) {
 	c.Config.Logger.Debug(ctx, "controller: handling update for group: %v", req.Group)
+	if err := c.checkPolicy(ctx, policy.Update, req); err != nil {
+		return nil, maskAny(err)
+	}
 
 	numRunning, err := c.getNumRunningSlices(ctx, req)
 	if err != nil {
 		return nil, maskAny(err)
 	}
+	if opts.BatchPercent < 0 || opts.BatchPercent > 100 {
+		return nil, maskAnyf(updateNotAllowedError, "batch percent must be between 1 and 100, or zero")
+	}
+	groupSize := len(req.SliceIDs)
+	if groupSize == 0 {
+		groupSize = numRunning
+	}
+	opts = opts.withBatch(groupSize)
 	c.Config.Logger.Debug(
 		ctx, "controller: running: %v, growth: %v, alive: %v, ready: %v",
 		numRunning, opts.MaxGrowth, opts.MinAlive, opts.ReadySecs,
@@ -402,6 +908,11 @@ func (c controller) Update(ctx context.Context, req Request, opts UpdateOptions)
 			return nil, maskAnyf(updateNotAllowedError, rule.message)
 		}
 	}
+	if !opts.AllowDowngrade {
+		if err := c.checkDowngrade(ctx, req); err != nil {
+			return nil, maskAny(err)
+		}
+	}
 
 	action := func(ctx context.Context) error {
 		req, ok, err := c.GroupNeedsUpdate(ctx, req)
@@ -415,6 +926,19 @@ func (c controller) Update(ctx context.Context, req Request, opts UpdateOptions)
 			return maskAny(unitsAlreadyUpToDate)
 		}
 
+		req, pinned, err := c.withoutPinnedSlices(ctx, req)
+		if err != nil {
+			return maskAny(err)
+		}
+		if len(pinned) > 0 {
+			c.Config.Logger.Info(ctx, "controller: skipping pinned slices %v", pinned)
+		}
+		if len(req.SliceIDs) == 0 {
+			c.Config.Logger.Debug(ctx, "controller: all outdated slices are pinned")
+			return maskAny(unitsAlreadyUpToDate)
+		}
+
+		ctx = c.recordUndo(ctx, string(policy.Update), req.Group, requestUnitNames(req))
 		err = c.UpdateWithStrategy(ctx, req, opts)
 		if err != nil {
 			c.Config.Logger.Error(ctx, "controller: error encountered updating: %v", err)
@@ -426,7 +950,7 @@ func (c controller) Update(ctx context.Context, req Request, opts UpdateOptions)
 		return nil
 	}
 
-	taskObject, err := c.TaskService.Create(ctx, action)
+	taskObject, err := c.createTask(ctx, string(policy.Update), req, action)
 	if err != nil {
 		c.Config.Logger.Error(ctx, "controller: Could not create update task: %v", err)
 		return nil, maskAny(err)
@@ -444,6 +968,12 @@ func (c controller) GetStatus(ctx context.Context, req Request) ([]fleet.UnitSta
 	return status, maskAny(err)
 }
 
+func (c controller) WatchGroup(ctx context.Context, req Request) (<-chan fleet.UnitState, func()) {
+	c.Config.Logger.Debug(ctx, "controller: handling watching group")
+
+	return fleet.WatchUnitStates(c.Fleet, matchesGroupSlices(req), c.Config.WaitSleep)
+}
+
 func (c controller) WaitForStatus(ctx context.Context, req Request, closer <-chan struct{}, desiredStatuses ...Status) error {
 	c.Config.Logger.Debug(ctx, "controller: handling waiting for status")
 
@@ -451,24 +981,91 @@ func (c controller) WaitForStatus(ctx context.Context, req Request, closer <-cha
 		return maskAny(invalidArgumentError)
 	}
 
+	timeout := c.WaitTimeout
+	if req.WaitTimeout > 0 {
+		timeout = req.WaitTimeout
+	}
+
 	fail := make(chan error)
 	done := make(chan struct{})
+	// stop ends polling fleet once we are not waiting anymore, e.g. because
+	// the timeout was reached.
+	stop := make(chan struct{})
+	defer close(stop)
+
+	// sleep waits for the given duration and returns false in case polling
+	// fleet should stop instead.
+	sleep := func(d time.Duration) bool {
+		select {
+		case <-time.After(d):
+			return true
+		case <-stop:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
 
 	go func() {
 		// count describes the count of how often one of the desired aggregated statuses was
 		// seen.
 		count := 0
+		// seen contains the last aggregated status of each unit, to emit events
+		// on status changes.
+		seen := map[string]Status{}
+		backoff := newWaitBackoff(c.WaitSleep, c.WaitMaxSleep)
+		// unavailableSince is the time fleet started to be unavailable, or zero
+		// in case its last answer was successful.
+		var unavailableSince time.Time
 
 	L1:
 		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			default:
+			}
+
 			c.Config.Logger.Debug(ctx, "controller: fetching group status")
 
+			// reached is the number of units having the desired statuses.
+			var reached int
+			start := time.Now()
 			unitStatusList, err := c.groupStatus(ctx, req)
+			latency := time.Since(start)
+			if fleet.IsRegistryUnavailable(err) || fleet.IsTimeout(err) {
+				// fleet being unavailable for a moment does not fail the operation,
+				// as long as the group reaches the desired status in time.
+				if unavailableSince.IsZero() {
+					unavailableSince = start
+				}
+				if time.Since(unavailableSince) >= c.UnavailableTimeout {
+					select {
+					case fail <- maskAny(err):
+					case <-stop:
+					}
+					return
+				}
+				c.Config.Logger.Debug(ctx, "controller: fetching group status failed, trying again: %#v", err)
+				count = 0
+				if !sleep(backoff.Next(latency, true, false)) {
+					return
+				}
+				continue L1
+			}
+			unavailableSince = time.Time{}
+			triggered := triggeredUnits(unitStatusList)
+			c.emitStatusChanges(ctx, req.Group, unitStatusList, seen)
 			for _, desiredStatus := range desiredStatuses {
 				if IsUnitNotFound(err) && desiredStatus == StatusNotFound {
 					goto C1
 				} else if err != nil {
-					fail <- maskAny(err)
+					select {
+					case fail <- maskAny(err):
+					case <-stop:
+					}
 					return
 				}
 			}
@@ -480,19 +1077,35 @@ func (c controller) WaitForStatus(ctx context.Context, req Request, closer <-cha
 				aggregator := Aggregator{
 					Logger: c.Config.Logger,
 				}
-				ok, err := aggregator.UnitHasStatus(us, desiredStatuses...)
+				statuses := desiredStatuses
+				if _, ok := triggered[us.Name]; ok && containsStatus(desiredStatuses, StatusRunning) {
+					// Units activated by timer, socket or path units are stopped until
+					// they get triggered. This is fine for a running group.
+					statuses = append([]Status{StatusStopped}, desiredStatuses...)
+				}
+				ok, err := c.unitHasStatus(aggregator, req, us, statuses)
 				if err != nil {
-					fail <- maskAny(err)
+					select {
+					case fail <- maskAny(err):
+					case <-stop:
+					}
 					return
 				}
 				if !ok {
 					c.Config.Logger.Debug(ctx, "controller: unit %v does not have desired statuses: %v", us, desiredStatuses)
-					// Whenever the aggregated status does not match the desired
-					// statuses, we reset the counter.
-					count = 0
-					time.Sleep(c.WaitSleep)
-					continue L1
+					continue
 				}
+				reached++
+			}
+			if reached < len(unitStatusList) {
+				// Whenever the aggregated status does not match the desired
+				// statuses, we reset the counter. The remaining units are likely to
+				// follow the ones having reached the desired statuses shortly.
+				count = 0
+				if !sleep(backoff.Next(latency, false, reached > 0)) {
+					return
+				}
+				continue L1
 			}
 
 		C1:
@@ -504,10 +1117,15 @@ func (c controller) WaitForStatus(ctx context.Context, req Request, closer <-cha
 				c.Config.Logger.Debug(ctx, "controller: group has reached count (%v) of desired statuses: %v", c.WaitCount, desiredStatuses)
 				break
 			}
-			time.Sleep(c.WaitSleep)
+			if !sleep(backoff.Next(latency, false, false)) {
+				return
+			}
 		}
 
-		done <- struct{}{}
+		select {
+		case done <- struct{}{}:
+		case <-stop:
+		}
 	}()
 
 	select {
@@ -517,7 +1135,12 @@ func (c controller) WaitForStatus(ctx context.Context, req Request, closer <-cha
 		return nil
 	case <-closer:
 		return nil
-	case <-time.After(c.WaitTimeout):
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return maskAny(waitTimeoutReachedError)
+		}
+		return maskAny(ctx.Err())
+	case <-time.After(timeout):
 		return maskAny(waitTimeoutReachedError)
 	}
 }
@@ -546,6 +1169,7 @@ func (c controller) groupStatus(ctx context.Context, req Request) ([]fleet.UnitS
 		return nil, maskAny(err)
 	}
 	c.Config.Logger.Debug(ctx, "controller: received unit status list: %#v", unitStatusList)
+	c.observeRestarts(ctx, req, unitStatusList, time.Now())
 
 	// TODO retry operations
 
@@ -587,6 +1211,147 @@ func validateUnitStatusWithRequest(unitStatusList []fleet.UnitStatus, req Reques
 	return nil
 }
 
+// forEachUnitBySlice calls f for each of the given unit names and collects the
+// results per slice. A slice is considered failed as soon as f fails for one of
+// its units. Only the first error of each slice is recorded.
+func forEachUnitBySlice(names []string, f func(name string) error) (MultiSliceError, error) {
+	var sliceIDs []string
+	failed := map[string]error{}
+
+	for _, name := range names {
+		sliceID, err := common.SliceID(name)
+		if err != nil {
+			return MultiSliceError{}, maskAny(err)
+		}
+		if !contains(sliceIDs, sliceID) {
+			sliceIDs = append(sliceIDs, sliceID)
+		}
+
+		err = f(name)
+		if err == nil {
+			continue
+		}
+		if _, ok := failed[sliceID]; !ok {
+			failed[sliceID] = maskAny(err)
+		}
+	}
+
+	var result MultiSliceError
+	for _, sliceID := range sliceIDs {
+		if err, ok := failed[sliceID]; ok {
+			result.Failed = append(result.Failed, SliceError{SliceID: sliceID, Err: err})
+		} else {
+			result.Succeeded = append(result.Succeeded, sliceID)
+		}
+	}
+
+	return result, nil
+}
+
+// startUnits sets the target state of the given units of the group of the
+// given request to launched. Units activated by timer, socket or path units of
+// the group are only loaded. They are started by systemd once they get
+// triggered. Slices are launched one after another, req.Stagger apart.
+func (c controller) startUnits(ctx context.Context, req Request, unitStatusList []fleet.UnitStatus) (MultiSliceError, error) {
+	triggered := triggeredUnits(unitStatusList)
+	names, err := unitNamesBySlice(unitNames(unitStatusList))
+	if err != nil {
+		return MultiSliceError{}, maskAny(err)
+	}
+	staggered := &stagger{Interval: req.Stagger}
+	var launched []string
+	result, err := forEachUnitBySlice(names, func(name string) error {
+		if _, ok := triggered[name]; ok {
+			c.Config.Logger.Debug(ctx, "action: not starting triggered unit %s", name)
+			return nil
+		}
+		sliceID, err := common.SliceID(name)
+		if err != nil {
+			return maskAny(err)
+		}
+		if !contains(launched, sliceID) {
+			if err := staggered.wait(ctx); err != nil {
+				return maskAny(err)
+			}
+			launched = append(launched, sliceID)
+		}
+		if err := c.Fleet.Start(ctx, name); err != nil {
+			return maskAny(err)
+		}
+		c.emitUnitEvent(ctx, EventUnitStarted, req.Group, name)
+		return nil
+	})
+	if err != nil {
+		return MultiSliceError{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// succeededSlicesRequest returns req restricted to the slices the given result
+// reports as succeeded, so that waiting for the desired status does not block
+// on slices that already failed. False is returned in case no slice succeeded.
+func succeededSlicesRequest(req Request, result MultiSliceError) (Request, bool) {
+	if !result.HasFailed() {
+		return req, true
+	}
+	if len(result.Succeeded) == 0 {
+		return req, false
+	}
+	req.SliceIDs = result.Succeeded
+
+	return req, true
+}
+
+func unitNames(unitStatusList []fleet.UnitStatus) []string {
+	var names []string
+	for _, us := range unitStatusList {
+		names = append(names, us.Name)
+	}
+
+	return names
+}
+
+// requestUnitNames returns the names of the units of the given request.
+func requestUnitNames(req Request) []string {
+	var names []string
+	for _, unit := range req.Units {
+		names = append(names, unit.Name)
+	}
+
+	return names
+}
+
+// triggeredUnits returns the names of all units of the given list, which are
+// activated by a timer, socket or path unit of the same list. Units are
+// paired by naming convention. See common.TriggeredUnit.
+func triggeredUnits(unitStatusList []fleet.UnitStatus) map[string]struct{} {
+	names := map[string]struct{}{}
+	for _, us := range unitStatusList {
+		names[us.Name] = struct{}{}
+	}
+
+	triggered := map[string]struct{}{}
+	for _, us := range unitStatusList {
+		name := common.TriggeredUnit(us.Name)
+		if _, ok := names[name]; ok {
+			triggered[name] = struct{}{}
+		}
+	}
+
+	return triggered
+}
+
+func containsStatus(statuses []Status, status Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
 func containsUnitStatusSliceID(unitStatusList []fleet.UnitStatus, sliceID string) (bool, error) {
 	for _, us := range unitStatusList {
 		ID, err := common.SliceID(us.Name)
@@ -601,26 +1366,54 @@ func containsUnitStatusSliceID(unitStatusList []fleet.UnitStatus, sliceID string
 	return false, nil
 }
 
+// matchesGroupPrefix returns a matcher for the names of units prefixed with the
+// given group name. Units of the given versions of the group are not matched,
+// so that e.g. "mygroup-1a2b3c-foo@1.service" does not belong to "mygroup" in
+// case "1a2b3c" is given. See VersionedGroup.
+func matchesGroupPrefix(group string, versions []string) func(string) bool {
+	var versioned []string
+	for _, v := range versions {
+		versioned = append(versioned, VersionedGroup(group, v))
+	}
+
+	return func(name string) bool {
+		if !strings.HasPrefix(name, group) {
+			return false
+		}
+		for _, prefix := range versioned {
+			if len(name) > len(prefix) && strings.HasPrefix(name, prefix) && strings.ContainsRune("-@.", rune(name[len(prefix)])) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
 // matchesGroupSlices returns a matcher compatible with fleet.GetStatusWithMatcher
 // that matches for each unitfiles that belongs to the group specified by
-// request.Group and request.SliceIDs
+// request.Group and request.SliceIDs. In case request.Match is given, it selects
+// the units instead of request.Group.
 func matchesGroupSlices(request Request) func(string) bool {
+	if request.Match != nil {
+		return matchesUnitMatch(request)
+	}
+
+	inGroup := matchesGroupPrefix(request.Group, request.ExcludeVersions)
+
 	// If only the group name is of interest, return shorter version
 	if request.SliceIDs == nil || len(request.SliceIDs) == 0 {
-		return func(name string) bool {
-			return strings.HasPrefix(name, request.Group)
-		}
+		return inGroup
 	}
 
 	// Normal version that matches on group prefix and slice ID suffix.
 	return func(unitName string) bool {
-		if !strings.HasPrefix(unitName, request.Group) {
+		if !inGroup(unitName) {
 			return false
 		}
 
 		for _, sliceID := range request.SliceIDs {
-			// TODO fix extension
-			if strings.HasSuffix(unitName, "@"+sliceID+".service") {
+			if strings.HasSuffix(unitName, "@"+sliceID+common.UnitExtension(unitName)) {
 				return true
 			}
 		}
@@ -630,16 +1423,20 @@ func matchesGroupSlices(request Request) func(string) bool {
 }
 
 func matchesUnitBase(request Request) func(string) bool {
+	if request.Match != nil {
+		return matchesUnitMatch(Request{Match: request.Match})
+	}
+
+	inGroup := matchesGroupPrefix(request.Group, request.ExcludeVersions)
+
 	// If only the group name is of interest, return shorter version
 	if request.Units == nil || len(request.Units) == 0 {
-		return func(name string) bool {
-			return strings.HasPrefix(name, request.Group)
-		}
+		return inGroup
 	}
 
 	// Normal version that matches on group prefix and slice ID suffix.
 	return func(unitName string) bool {
-		if !strings.HasPrefix(unitName, request.Group) {
+		if !inGroup(unitName) {
 			return false
 		}
 
@@ -13,6 +13,7 @@ import (
 
 	"github.com/giantswarm/inago/fleet"
 	"github.com/giantswarm/inago/logging"
+	"github.com/giantswarm/inago/policy"
 	"github.com/giantswarm/inago/task"
 )
 
@@ -432,6 +433,75 @@ func Test_matchesGroupSlices(t *testing.T) {
 			},
 			Output: false,
 		},
+
+		// Units of excluded versions do not belong to the unversioned group.
+		{
+			InputUnitName: "demo-1a2b3c-main@1.service",
+			InputRequest: Request{
+				RequestConfig: RequestConfig{
+					Group:    "demo",
+					SliceIDs: nil,
+				},
+				ExcludeVersions: []string{"1a2b3c"},
+			},
+			Output: false,
+		},
+		{
+			InputUnitName: "demo-1a2b3c-main@1.service",
+			InputRequest: Request{
+				RequestConfig: RequestConfig{
+					Group:    "demo",
+					SliceIDs: []string{"1"},
+				},
+				ExcludeVersions: []string{"4d5e6f", "1a2b3c"},
+			},
+			Output: false,
+		},
+		{
+			InputUnitName: "demo-1a2b3c-main@1.service",
+			InputRequest: Request{
+				RequestConfig: RequestConfig{
+					Group:    "demo",
+					SliceIDs: []string{"1"},
+				},
+				ExcludeVersions: []string{"4d5e6f"},
+			},
+			Output: true,
+		},
+
+		// Units looking like versions belong to their group, unless the
+		// version is excluded.
+		{
+			InputUnitName: "demo-facade@1.service",
+			InputRequest: Request{
+				RequestConfig: RequestConfig{
+					Group:    "demo",
+					SliceIDs: []string{"1"},
+				},
+			},
+			Output: true,
+		},
+		{
+			InputUnitName: "demo-cafe01.service",
+			InputRequest: Request{
+				RequestConfig: RequestConfig{
+					Group:    "demo",
+					SliceIDs: nil,
+				},
+				ExcludeVersions: []string{"1a2b3c"},
+			},
+			Output: true,
+		},
+		{
+			InputUnitName: "demo-1a2b3c-main@1.service",
+			InputRequest: Request{
+				RequestConfig: RequestConfig{
+					Group:    "demo-1a2b3c",
+					SliceIDs: []string{"1"},
+				},
+			},
+			Output: true,
+		},
 	}
 
 	for id, test := range testCases {
@@ -505,6 +575,7 @@ func TestController_Submit_Error(t *testing.T) {
 	Expect(task).To(BeNil())
 	Expect(validationErr).To(HaveOccurred())
 	Expect(validationErr.Contains(IsNoUnitsInGroup)).To(BeTrue())
+	Expect(IsInvalidRequest(err)).To(BeTrue())
 	mock.AssertExpectationsForObjects(t, fleetMock.Mock)
 }
 
@@ -703,6 +774,195 @@ func TestController_Destroy(t *testing.T) {
 	mock.AssertExpectationsForObjects(t, fleetMock.Mock)
 }
 
+// TestController_Start_SliceFailure ensures that a failure of one slice does
+// not abort the start of the other slices and that the failure is reported per
+// slice.
+func TestController_Start_SliceFailure(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Mocks
+	controller, fleetMock := givenController()
+	runningStatus := func(name, sliceID string) fleet.UnitStatus {
+		return fleet.UnitStatus{
+			Current: "launched",
+			Desired: "launched",
+			Machine: []fleet.MachineStatus{
+				{SystemdActive: "active", SystemdSub: "running"},
+			},
+			Name:    name,
+			SliceID: sliceID,
+		}
+	}
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(
+		[]fleet.UnitStatus{
+			runningStatus("test-main@1.service", "1"),
+			runningStatus("test-main@2.service", "2"),
+		},
+		nil,
+	)
+	fleetMock.On("Start", "test-main@1.service").Return(errgo.New("test error")).Once()
+	fleetMock.On("Start", "test-main@2.service").Return(nil).Once()
+
+	// Execute test
+	req := Request{
+		RequestConfig: RequestConfig{
+			Group:    "test",
+			SliceIDs: []string{"1", "2"},
+		},
+	}
+	taskObject, err := controller.Start(context.Background(), req)
+	Expect(err).To(BeNil())
+
+	taskObject, err = controller.WaitForTask(context.Background(), taskObject.ID, nil)
+	Expect(err).To(BeNil())
+
+	// Assert
+	Expect(task.HasFailedStatus(taskObject)).To(BeTrue())
+	Expect(IsMultiSliceError(taskObject.Error)).To(BeTrue())
+	multiErr := errgo.Cause(taskObject.Error).(MultiSliceError)
+	Expect(multiErr.Succeeded).To(Equal([]string{"2"}))
+	Expect(len(multiErr.Failed)).To(Equal(1))
+	Expect(multiErr.Failed[0].SliceID).To(Equal("1"))
+	mock.AssertExpectationsForObjects(t, fleetMock.Mock)
+}
+
+func TestController_Start_TriggeredUnits(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Mocks
+	controller, fleetMock := givenController()
+	unitStatus := func(name, active, sub string) fleet.UnitStatus {
+		return fleet.UnitStatus{
+			Current: "launched",
+			Desired: "launched",
+			Machine: []fleet.MachineStatus{
+				{SystemdActive: active, SystemdSub: sub},
+			},
+			Name:    name,
+			SliceID: "1",
+		}
+	}
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(
+		[]fleet.UnitStatus{
+			unitStatus("test-backup@1.service", "inactive", "dead"),
+			unitStatus("test-backup@1.timer", "active", "waiting"),
+			unitStatus("test-main@1.service", "active", "running"),
+		},
+		nil,
+	)
+	fleetMock.On("Start", "test-backup@1.timer").Return(nil).Once()
+	fleetMock.On("Start", "test-main@1.service").Return(nil).Once()
+
+	// Execute test
+	req := Request{
+		RequestConfig: RequestConfig{
+			Group:    "test",
+			SliceIDs: []string{"1"},
+		},
+	}
+	taskObject, err := controller.Start(context.Background(), req)
+	Expect(err).To(BeNil())
+
+	taskObject, err = controller.WaitForTask(context.Background(), taskObject.ID, nil)
+	Expect(err).To(BeNil())
+
+	// Assert
+	Expect(task.HasFailedStatus(taskObject)).To(BeFalse())
+	mock.AssertExpectationsForObjects(t, fleetMock.Mock)
+	fleetMock.AssertNotCalled(t, "Start", "test-backup@1.service")
+}
+
+func Test_matchesGroupSlices_UnitKinds(t *testing.T) {
+	RegisterTestingT(t)
+
+	matcher := matchesGroupSlices(Request{
+		RequestConfig: RequestConfig{
+			Group:    "test",
+			SliceIDs: []string{"1"},
+		},
+	})
+
+	Expect(matcher("test-main@1.service")).To(BeTrue())
+	Expect(matcher("test-main@1.timer")).To(BeTrue())
+	Expect(matcher("test-main@1.socket")).To(BeTrue())
+	Expect(matcher("test-main@2.timer")).To(BeFalse())
+	Expect(matcher("other-main@1.timer")).To(BeFalse())
+}
+
+func TestController_Policy(t *testing.T) {
+	RegisterTestingT(t)
+
+	newPolicy, err := policy.Parse([]byte(`{"rules": [{"group": "prod-*", "forbid": ["destroy"]}, {"group": "*", "max-scale": 2}]}`))
+	Expect(err).To(BeNil())
+
+	// Destroying a production group is forbidden without any fleet call.
+	newController, fleetMock := givenController()
+	c := newController.(*controller)
+	c.Config.Policy = newPolicy
+	req := Request{RequestConfig: RequestConfig{Group: "prod-app", SliceIDs: []string{"1"}}}
+	_, err = c.Destroy(context.Background(), req)
+	Expect(policy.IsPolicyViolation(err)).To(BeTrue())
+	Expect(IsPolicyViolation(err)).To(BeTrue())
+	fleetMock.AssertNotCalled(t, "GetStatusWithMatcher", mock.AnythingOfType("func(string) bool"))
+
+	// Submitting more slices than allowed is forbidden.
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(
+		[]fleet.UnitStatus{{Name: "dev-app@1.service", SliceID: "1"}},
+		nil,
+	)
+	req = Request{
+		RequestConfig: RequestConfig{Group: "dev-app"},
+		Units:         []Unit{{Name: "dev-app@.service"}},
+		DesiredSlices: 2,
+	}
+	_, err = c.Submit(context.Background(), req)
+	Expect(policy.IsPolicyViolation(err)).To(BeTrue())
+	fleetMock.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything)
+
+	// Unsigned groups are refused in case the policy requires signatures.
+	c.Config.Policy, err = policy.Parse([]byte(`{"rules": [{"group": "prod-*", "require-signature": true}]}`))
+	Expect(err).To(BeNil())
+	req = Request{RequestConfig: RequestConfig{Group: "prod-app"}}
+	err = c.checkPolicy(context.Background(), policy.Update, req)
+	Expect(IsPolicyViolation(err)).To(BeTrue())
+	req.Signed = true
+	err = c.checkPolicy(context.Background(), policy.Update, req)
+	Expect(err).To(BeNil())
+
+	// Violations are ignored when forced.
+	c.Config.ForcePolicy = true
+	err = c.checkPolicy(context.Background(), policy.Destroy, Request{RequestConfig: RequestConfig{Group: "prod-app"}})
+	Expect(err).To(BeNil())
+}
+
+func Test_forEachUnitBySlice(t *testing.T) {
+	RegisterTestingT(t)
+
+	names := []string{"test-a@1.service", "test-b@1.service", "test-a@2.service", "test-b@2.service", "test-a@3.service"}
+	var called []string
+	result, err := forEachUnitBySlice(names, func(name string) error {
+		called = append(called, name)
+		if name == "test-b@2.service" {
+			return errgo.New("test error")
+		}
+		return nil
+	})
+
+	Expect(err).To(BeNil())
+	Expect(called).To(Equal(names))
+	Expect(result.HasFailed()).To(BeTrue())
+	Expect(result.Succeeded).To(Equal([]string{"1", "3"}))
+	Expect(len(result.Failed)).To(Equal(1))
+	Expect(result.Failed[0].SliceID).To(Equal("2"))
+
+	req, ok := succeededSlicesRequest(Request{}, result)
+	Expect(ok).To(BeTrue())
+	Expect(req.SliceIDs).To(Equal([]string{"1", "3"}))
+
+	_, ok = succeededSlicesRequest(Request{}, MultiSliceError{Failed: result.Failed})
+	Expect(ok).To(BeFalse())
+}
+
 func TestController_Stop(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -942,6 +1202,107 @@ func TestController_WaitForStatus_Timeout(t *testing.T) {
 	Expect(IsWaitTimeoutReached(err)).To(BeTrue()) // Because WaitForStatus is 0 nothing should happen but directly return the error
 }
 
+// TestController_WaitForStatus_Deadline tests Controller.WaitForStatus to end
+// waiting when the deadline of the given context expired, instead of waiting
+// for WaitTimeout.
+func TestController_WaitForStatus_Deadline(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Mocks
+	c, fleetMock := givenController()
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(
+		[]fleet.UnitStatus{
+			{
+				Current: "inactive",
+				Desired: "inactive",
+				Name:    "test-main@1.service",
+			},
+		},
+		nil,
+	)
+
+	// Execute test
+	req := Request{
+		RequestConfig: RequestConfig{
+			Group:    "test",
+			SliceIDs: []string{"1"},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := c.WaitForStatus(ctx, req, nil, StatusRunning)
+	Expect(IsTimeout(err)).To(BeTrue())
+	Expect(time.Since(start)).To(BeNumerically("<", 1*time.Second))
+
+	// Deadlines longer than WaitTimeout do not extend it.
+	c.(*controller).WaitTimeout = 50 * time.Millisecond
+	ctx, cancel = context.WithTimeout(context.Background(), 1*time.Hour)
+	defer cancel()
+	start = time.Now()
+	err = c.WaitForStatus(ctx, req, nil, StatusRunning)
+	Expect(IsTimeout(err)).To(BeTrue())
+	Expect(time.Since(start)).To(BeNumerically("<", 1*time.Second))
+
+	// Canceled contexts end waiting as well.
+	c.(*controller).WaitTimeout = 1 * time.Hour
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start = time.Now()
+	err = c.WaitForStatus(ctx, req, nil, StatusRunning)
+	Expect(err).To(HaveOccurred())
+	Expect(IsTimeout(err)).To(BeFalse())
+	Expect(time.Since(start)).To(BeNumerically("<", 1*time.Second))
+
+	// The timeout of the request overrides WaitTimeout.
+	req.WaitTimeout = 50 * time.Millisecond
+	start = time.Now()
+	err = c.WaitForStatus(context.Background(), req, nil, StatusRunning)
+	Expect(IsTimeout(err)).To(BeTrue())
+	Expect(time.Since(start)).To(BeNumerically("<", 1*time.Second))
+
+	// Fleet is not polled anymore once waiting ended.
+	time.Sleep(50 * time.Millisecond)
+	calls := len(fleetMock.Calls)
+	time.Sleep(100 * time.Millisecond)
+	Expect(fleetMock.Calls).To(HaveLen(calls))
+}
+
+// TestController_WaitForStatus_Unavailable tests Controller.WaitForStatus to
+// give up retrying once fleet was unavailable for Config.UnavailableTimeout.
+func TestController_WaitForStatus_Unavailable(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Mocks
+	c, fleetMock := givenController()
+	timeoutErr := &net.DNSError{Err: "i/o timeout", IsTimeout: true}
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return([]fleet.UnitStatus{}, timeoutErr)
+
+	// Execute test
+	req := Request{
+		RequestConfig: RequestConfig{
+			Group:    "test",
+			SliceIDs: []string{"1"},
+		},
+	}
+	c.(*controller).WaitTimeout = 1 * time.Hour
+	c.(*controller).UnavailableTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	err := c.WaitForStatus(context.Background(), req, nil, StatusRunning)
+	Expect(fleet.IsTimeout(err)).To(BeTrue())
+	Expect(time.Since(start)).To(BeNumerically("<", 1*time.Second))
+	Expect(len(fleetMock.Calls)).To(BeNumerically(">", 1))
+
+	// Without UnavailableTimeout the first error fails waiting.
+	c.(*controller).UnavailableTimeout = 0
+	calls := len(fleetMock.Calls)
+	err = c.WaitForStatus(context.Background(), req, nil, StatusRunning)
+	Expect(fleet.IsTimeout(err)).To(BeTrue())
+	Expect(fleetMock.Calls).To(HaveLen(calls + 1))
+}
+
 // TestController_UpdateValidation tests the validation of the Update method of the controller.
 func TestController_UpdateValidation(t *testing.T) {
 	RegisterTestingT(t)
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"github.com/coreos/fleet/unit"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/fleet"
+)
+
+// withoutUnchangedSlices removes the slices from the given request whose units
+// are all deployed with the content they would be submitted with, and returns
+// the IDs of the removed slices. The request is expected to be extended using
+// Request.ExtendSlices, and contents to hold the content each unit would be
+// submitted with. Submitting such slices again would only reset their units to
+// loaded. Contents are compared using fleet's unit hash, so units only
+// formatted differently are considered unchanged as well.
+func (c controller) withoutUnchangedSlices(ctx context.Context, req Request, contents map[string]string) (Request, []string, error) {
+	deployed, err := c.deployedUnitHashes(ctx, req)
+	if err != nil {
+		return Request{}, nil, maskAny(err)
+	}
+	if len(deployed) == 0 {
+		return req, nil, nil
+	}
+
+	var sliceIDs []string
+	changed := map[string]bool{}
+	for _, u := range req.Units {
+		sliceID, err := common.SliceID(u.Name)
+		if err != nil {
+			return Request{}, nil, maskAny(err)
+		}
+		if !contains(sliceIDs, sliceID) {
+			sliceIDs = append(sliceIDs, sliceID)
+		}
+
+		unitFile, err := unit.NewUnitFile(contents[u.Name])
+		if err != nil {
+			return Request{}, nil, maskAny(err)
+		}
+		if hash, ok := deployed[u.Name]; !ok || hash != unitFile.Hash().String() {
+			changed[sliceID] = true
+		}
+	}
+
+	var skipped []string
+	for _, sliceID := range sliceIDs {
+		if !changed[sliceID] {
+			skipped = append(skipped, sliceID)
+		}
+	}
+	if len(skipped) == 0 {
+		return req, nil, nil
+	}
+
+	var units []Unit
+	for _, u := range req.Units {
+		sliceID, err := common.SliceID(u.Name)
+		if err != nil {
+			return Request{}, nil, maskAny(err)
+		}
+		if changed[sliceID] {
+			units = append(units, u)
+		}
+	}
+	req.Units = units
+
+	var kept []string
+	for _, sliceID := range req.SliceIDs {
+		if !contains(skipped, sliceID) {
+			kept = append(kept, sliceID)
+		}
+	}
+	req.SliceIDs = kept
+
+	return req, skipped, nil
+}
+
+// deployedUnitHashes returns the unit hashes of the deployed units of the
+// given request, keyed by unit name. The hashes are taken from the unit states
+// reported by the machines. Units not scheduled yet are fetched to compute
+// their hash. Units not deployed are missing.
+func (c controller) deployedUnitHashes(ctx context.Context, req Request) (map[string]string, error) {
+	usl, err := c.Fleet.GetStatusWithMatcher(matchesGroupSlices(req))
+	if fleet.IsUnitNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	hashes := map[string]string{}
+	for _, us := range usl {
+		for _, ms := range us.Machine {
+			if ms.UnitHash != "" {
+				hashes[us.Name] = ms.UnitHash
+				break
+			}
+		}
+		if _, ok := hashes[us.Name]; ok {
+			continue
+		}
+
+		content, err := c.Fleet.GetContent(ctx, us.Name)
+		if fleet.IsUnitNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, maskAny(err)
+		}
+		unitFile, err := unit.NewUnitFile(content)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		hashes[us.Name] = unitFile.Hash().String()
+	}
+
+	return hashes, nil
+}
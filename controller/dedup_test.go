@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/logging"
+	"github.com/giantswarm/inago/task"
+)
+
+// submitRecordingFleet records the names of the units submitted.
+type submitRecordingFleet struct {
+	fleet.Fleet
+
+	Mutex     sync.Mutex
+	Submitted []string
+}
+
+func (f *submitRecordingFleet) Submit(ctx context.Context, name, content string) error {
+	f.Mutex.Lock()
+	f.Submitted = append(f.Submitted, name)
+	f.Mutex.Unlock()
+
+	return maskAny(f.Fleet.Submit(ctx, name, content))
+}
+
+func (f *submitRecordingFleet) reset() []string {
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+
+	submitted := f.Submitted
+	f.Submitted = nil
+	sort.Strings(submitted)
+
+	return submitted
+}
+
+func givenSimulatedController(t *testing.T) (controller, *submitRecordingFleet) {
+	newLoggingConfig := logging.DefaultConfig()
+	newLoggingConfig.LogLevel = "ERROR"
+	newLogger := logging.NewLogger(newLoggingConfig)
+
+	newSimulatorConfig := fleet.DefaultSimulatorConfig()
+	newSimulatorConfig.Logger = newLogger
+	newSimulatorConfig.Latency = 10 * time.Millisecond
+	newSimulator, err := fleet.NewSimulator(newSimulatorConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	recordingFleet := &submitRecordingFleet{Fleet: newSimulator}
+
+	newTaskServiceConfig := task.DefaultConfig()
+	newTaskServiceConfig.Logger = newLogger
+	newTaskServiceConfig.WaitSleep = 10 * time.Millisecond
+	newControllerConfig := DefaultConfig()
+	newControllerConfig.Fleet = recordingFleet
+	newControllerConfig.TaskService = task.NewTaskService(newTaskServiceConfig)
+	newControllerConfig.Logger = newLogger
+	newControllerConfig.WaitCount = 1
+	newControllerConfig.WaitSleep = 10 * time.Millisecond
+	newControllerConfig.WaitTimeout = 2 * time.Second
+
+	return controller{newControllerConfig}, recordingFleet
+}
+
+func submitAndWait(c controller, req Request) error {
+	ctx := context.Background()
+	taskObject, err := c.Submit(ctx, req)
+	if err != nil {
+		return maskAny(err)
+	}
+	taskObject, err = c.WaitForTask(ctx, taskObject.ID, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	if task.HasFailedStatus(taskObject) {
+		return maskAny(taskObject.Error)
+	}
+
+	return nil
+}
+
+func TestController_Submit_Unchanged(t *testing.T) {
+	RegisterTestingT(t)
+
+	c, recordingFleet := givenSimulatedController(t)
+	ctx := context.Background()
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "app", SliceIDs: []string{"1", "2"}},
+		Units: []Unit{
+			{Name: "app-main@.service", Content: "[Service]\nExecStart=/bin/main\n"},
+			{Name: "app-sidekick@.service", Content: "[Service]\nExecStart=/bin/sidekick\n"},
+		},
+	}
+	Expect(submitAndWait(c, req)).To(BeNil())
+	Expect(recordingFleet.reset()).To(HaveLen(4))
+
+	// Submitting the unchanged group again does not touch it, even though it
+	// is running meanwhile.
+	Expect(recordingFleet.Start(ctx, "app-main@1.service")).To(BeNil())
+	time.Sleep(30 * time.Millisecond)
+	Expect(submitAndWait(c, req)).To(BeNil())
+	Expect(recordingFleet.reset()).To(BeEmpty())
+	us, err := recordingFleet.GetStatus(ctx, "app-main@1.service")
+	Expect(err).To(BeNil())
+	Expect(us.Desired).To(Equal("launched"))
+
+	// Formatting does not matter.
+	req.Units[0].Content = "[Service]\nExecStart = /bin/main\n\n"
+	Expect(submitAndWait(c, req)).To(BeNil())
+	Expect(recordingFleet.reset()).To(BeEmpty())
+
+	// Slices that are missing, or have a unit that differs, are submitted as a
+	// whole.
+	Expect(recordingFleet.Destroy(ctx, "app-sidekick@1.service")).To(BeNil())
+	req.SliceIDs = []string{"1", "2", "3"}
+	Expect(submitAndWait(c, req)).To(BeNil())
+	Expect(recordingFleet.reset()).To(Equal([]string{
+		"app-main@1.service",
+		"app-main@3.service",
+		"app-sidekick@1.service",
+		"app-sidekick@3.service",
+	}))
+
+	// Generated slice IDs are never deployed.
+	req.SliceIDs = nil
+	req.DesiredSlices = 1
+	Expect(submitAndWait(c, req)).To(BeNil())
+	Expect(recordingFleet.reset()).To(HaveLen(2))
+}
+
+func TestController_Submit_UnchangedWithoutSlices(t *testing.T) {
+	RegisterTestingT(t)
+
+	c, recordingFleet := givenSimulatedController(t)
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "single"},
+		DesiredSlices: 1,
+		Units:         []Unit{{Name: "single.service", Content: "[Service]\nExecStart=/bin/single\n"}},
+	}
+	Expect(submitAndWait(c, req)).To(BeNil())
+	Expect(recordingFleet.reset()).To(Equal([]string{"single.service"}))
+
+	Expect(submitAndWait(c, req)).To(BeNil())
+	Expect(recordingFleet.reset()).To(BeEmpty())
+
+	req.Units[0].Content = "[Service]\nExecStart=/bin/other\n"
+	Expect(submitAndWait(c, req)).To(BeNil())
+	Expect(recordingFleet.reset()).To(Equal([]string{"single.service"}))
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+)
+
+// WithGroup returns a copy of r for the given group. The group prefix of all
+// unit names is replaced with the given group. Within the unit contents, all
+// references to units of r are replaced as well, so that e.g. MachineOf=
+// directives keep pointing to units of the same group. Having group "mygroup",
+// new group "mycopy" and unit file "mygroup-foo@.service" results in the
+// following request.
+//
+//   group:  mycopy
+//   units:  mycopy-foo@.service
+//
+func (r Request) WithGroup(group string) Request {
+	// Replace longer names first in case one unit base is the prefix of
+	// another one.
+	var bases []string
+	for _, unit := range r.Units {
+		bases = append(bases, common.UnitBase(unit.Name))
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(bases)))
+
+	var newUnits []Unit
+	for _, unit := range r.Units {
+		newUnit := unit
+		newUnit.Name = group + strings.TrimPrefix(unit.Name, r.Group)
+		if unit.EnvFile != "" {
+			newUnit.EnvFile = group + strings.TrimPrefix(unit.EnvFile, r.Group)
+		}
+		for _, base := range bases {
+			newBase := group + strings.TrimPrefix(base, r.Group)
+			newUnit.Content = strings.Replace(newUnit.Content, base, newBase, -1)
+			newUnit.EnvTemplate = strings.Replace(newUnit.EnvTemplate, base, newBase, -1)
+		}
+		// Labels refer to the group. See WithLabels.
+		labelSection := "[" + LabelSection + "]\nGroup="
+		newUnit.Content = strings.Replace(newUnit.Content, labelSection+r.Group+"\n", labelSection+group+"\n", -1)
+		newUnits = append(newUnits, newUnit)
+	}
+	r.Units = newUnits
+	r.ReadyStates = withReadyStatesGroup(r.ReadyStates, r.Group, group)
+	r.Group = group
+
+	return r
+}
+
+func (c controller) DeployedUnits(ctx context.Context, req Request) ([]Unit, error) {
+	c.Config.Logger.Debug(ctx, "controller: fetching deployed units of group '%s'", req.Group)
+
+	unitStatusList, err := c.groupStatus(ctx, req)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	// All slices of a group are submitted using the same unit files. So it is
+	// enough to look at the units of one slice.
+	var sliceIDs []string
+	for _, us := range unitStatusList {
+		if !contains(sliceIDs, us.SliceID) {
+			sliceIDs = append(sliceIDs, us.SliceID)
+		}
+	}
+	sort.Strings(sliceIDs)
+
+	var units []Unit
+	for _, us := range unitStatusList {
+		if us.SliceID != sliceIDs[0] {
+			continue
+		}
+
+		content, err := c.Fleet.GetContent(ctx, us.Name)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+
+		name := us.Name
+		if us.SliceID != "" {
+			// Turn the unit name back into the unit file name by removing the slice
+			// ID, e.g. "mygroup-foo@1.service" becomes "mygroup-foo@.service".
+			name = strings.Replace(name, "@"+us.SliceID+".", "@.", 1)
+		}
+
+		// Units writing environment files are rendered for each slice, so they
+		// are turned back into their templates.
+		u, err := parseEnvUnit(Unit{Name: name, Content: content})
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		units = append(units, u)
+	}
+
+	return units, nil
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_Request_WithGroup(t *testing.T) {
+	testCases := []struct {
+		Input    Request
+		Group    string
+		Expected Request
+	}{
+		{
+			Input: Request{
+				RequestConfig: RequestConfig{Group: "foo"},
+				Units: []Unit{
+					{Name: "foo-main@.service", Content: "[Service]\nExecStart=/bin/sh -c 'docker run --name foo-main-%i image'\n"},
+					{Name: "foo-main-sidekick@.service", Content: "[X-Fleet]\nMachineOf=foo-main@%i.service\n"},
+				},
+			},
+			Group: "bar",
+			Expected: Request{
+				RequestConfig: RequestConfig{Group: "bar"},
+				Units: []Unit{
+					{Name: "bar-main@.service", Content: "[Service]\nExecStart=/bin/sh -c 'docker run --name bar-main-%i image'\n"},
+					{Name: "bar-main-sidekick@.service", Content: "[X-Fleet]\nMachineOf=bar-main@%i.service\n"},
+				},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		output := testCase.Input.WithGroup(testCase.Group)
+		if !reflect.DeepEqual(output, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", output)
+		}
+		if ok, err := ValidateRequest(output); !ok {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+	}
+}
+
+func TestController_DeployedUnits(t *testing.T) {
+	controller, fleetMock := givenController()
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(
+		[]fleet.UnitStatus{
+			{Name: "foo-main@2.service", SliceID: "2"},
+			{Name: "foo-main@1.service", SliceID: "1"},
+			{Name: "foo-main@1.timer", SliceID: "1"},
+		},
+		nil,
+	)
+	fleetMock.On("GetContent", "foo-main@1.service").Return("service content", nil).Once()
+	fleetMock.On("GetContent", "foo-main@1.timer").Return("timer content", nil).Once()
+
+	units, err := controller.DeployedUnits(context.Background(), Request{RequestConfig: RequestConfig{Group: "foo"}})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	expected := []Unit{
+		{Name: "foo-main@.service", Content: "service content"},
+		{Name: "foo-main@.timer", Content: "timer content"},
+	}
+	if !reflect.DeepEqual(units, expected) {
+		t.Fatal("expected", expected, "got", units)
+	}
+	mock.AssertExpectationsForObjects(t, fleetMock.Mock)
+}
+
+func TestController_DeployedUnits_DummyFleet(t *testing.T) {
+	controller, dummyFleet := getTestController()
+	req := Request{RequestConfig: RequestConfig{Group: "foo"}}
+
+	_, err := controller.DeployedUnits(context.Background(), req)
+	if !IsUnitNotFound(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	err = dummyFleet.Submit(context.Background(), "foo-main.service", "main content")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	units, err := controller.DeployedUnits(context.Background(), req)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	expected := []Unit{{Name: "foo-main.service", Content: "main content"}}
+	if !reflect.DeepEqual(units, expected) {
+		t.Fatal("expected", expected, "got", units)
+	}
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/coreos/fleet/unit"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func (c controller) MachineSlices(ctx context.Context, req Request, machine string) ([]string, error) {
+	c.Config.Logger.Debug(ctx, "controller: fetching slices of group '%s' on machine '%s'", req.Group, machine)
+
+	if machine == "" {
+		return nil, maskAnyf(invalidArgumentError, "machine must not be empty")
+	}
+
+	unitStatusList, err := c.groupStatus(ctx, req)
+	if IsUnitNotFound(err) {
+		// The group does not exist. So there is nothing scheduled on the machine.
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	var sliceIDs []string
+	for _, us := range unitStatusList {
+		if !scheduledOn(us, machine) || contains(sliceIDs, us.SliceID) {
+			continue
+		}
+		global, err := c.isGlobalUnit(ctx, us.Name)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		if !global {
+			sliceIDs = append(sliceIDs, us.SliceID)
+		}
+	}
+	sort.Strings(sliceIDs)
+
+	return sliceIDs, nil
+}
+
+func (c controller) MachineGroups(ctx context.Context, machine string, groups []string) ([]string, error) {
+	c.Config.Logger.Debug(ctx, "controller: fetching groups on machine '%s'", machine)
+
+	if machine == "" {
+		return nil, maskAnyf(invalidArgumentError, "machine must not be empty")
+	}
+
+	labels, err := c.GroupLabels(ctx)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	known := append([]string{}, groups...)
+	for group := range labels {
+		known = append(known, group)
+	}
+
+	unitStatusList, err := c.Fleet.GetStatusWithMatcher(func(string) bool { return true })
+	if fleet.IsUnitNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	var machineGroups []string
+	for _, us := range unitStatusList {
+		if !scheduledOn(us, machine) {
+			continue
+		}
+		group := knownGroup(known, us.Name)
+		if group == "" {
+			c.Config.Logger.Info(ctx, "controller: skipping unit '%s', it does not belong to a known group", us.Name)
+			continue
+		}
+		if contains(machineGroups, group) {
+			continue
+		}
+		global, err := c.isGlobalUnit(ctx, us.Name)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		if !global {
+			machineGroups = append(machineGroups, group)
+		}
+	}
+	sort.Strings(machineGroups)
+
+	return machineGroups, nil
+}
+
+// scheduledOn checks whether the given unit is scheduled on the given machine.
+// Fleet machine IDs are usually displayed abbreviated. So we also accept
+// prefixes of machine IDs, as well as machine IPs.
+func scheduledOn(us fleet.UnitStatus, machine string) bool {
+	for _, ms := range us.Machine {
+		if strings.HasPrefix(ms.ID, machine) || ms.IP.String() == machine {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isGlobalUnit checks whether the unit having the given name is scheduled on
+// all machines using the Global= option of its X-Fleet section. Global units
+// cannot be moved off a machine, so they are not drained. Units destroyed in
+// the meantime are not global.
+func (c controller) isGlobalUnit(ctx context.Context, name string) (bool, error) {
+	content, err := c.Fleet.GetContent(ctx, name)
+	if fleet.IsUnitNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, maskAny(err)
+	}
+	unitFile, err := unit.NewUnitFile(content)
+	if err != nil {
+		return false, maskAnyf(invalidUnitContentError, "%s: %s", name, err.Error())
+	}
+
+	return lastOption(unitFile.Contents["X-Fleet"]["Global"]) == "true", nil
+}
+
+// knownGroup returns the group of the given groups the unit having the given
+// name belongs to. In case the unit belongs to multiple groups, e.g. "app" and
+// "app-test", the longest one is returned. An empty string is returned in
+// case the unit belongs to none of them.
+func knownGroup(groups []string, name string) string {
+	var found string
+	for _, group := range groups {
+		if strings.HasPrefix(name, group+"-") && len(group) > len(found) {
+			found = group
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func TestController_MachineSlices(t *testing.T) {
+	unitStatusList := []fleet.UnitStatus{
+		{Name: "foo-main@1.service", SliceID: "1", Machine: []fleet.MachineStatus{{ID: "505e0d7802d7439a924c269b76f34b5f", IP: net.ParseIP("10.0.0.101")}}},
+		{Name: "foo-main@2.service", SliceID: "2", Machine: []fleet.MachineStatus{{ID: "9ebb53b04b0d46fb94b4fd1b3f562d2b", IP: net.ParseIP("10.0.0.102")}}},
+		{Name: "foo-side@2.service", SliceID: "2", Machine: []fleet.MachineStatus{{ID: "9ebb53b04b0d46fb94b4fd1b3f562d2b", IP: net.ParseIP("10.0.0.102")}}},
+		{Name: "foo-main@3.service", SliceID: "3", Machine: []fleet.MachineStatus{{ID: "505e0d7802d7439a924c269b76f34b5f", IP: net.ParseIP("10.0.0.101")}}},
+		{Name: "foo-main@4.service", SliceID: "4"},
+		{Name: "foo-logs.service", Machine: []fleet.MachineStatus{{ID: "505e0d7802d7439a924c269b76f34b5f", IP: net.ParseIP("10.0.0.101")}}},
+	}
+
+	testCases := []struct {
+		Machine  string
+		Expected []string
+	}{
+		{Machine: "505e0d7802d7439a924c269b76f34b5f", Expected: []string{"1", "3"}},
+		{Machine: "9ebb53b0", Expected: []string{"2"}},
+		{Machine: "10.0.0.101", Expected: []string{"1", "3"}},
+		{Machine: "10.0.0.103", Expected: nil},
+	}
+
+	for i, testCase := range testCases {
+		controller, fleetMock := givenController()
+		fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(unitStatusList, nil).Once()
+		// Global units run on all machines and cannot be drained.
+		fleetMock.On("GetContent", "foo-logs.service").Return("[Service]\nExecStart=/bin/true\n\n[X-Fleet]\nGlobal=true\n", nil)
+		fleetMock.On("GetContent", mock.AnythingOfType("string")).Return("[Service]\nExecStart=/bin/true\n", nil)
+
+		sliceIDs, err := controller.MachineSlices(context.Background(), Request{RequestConfig: RequestConfig{Group: "foo"}}, testCase.Machine)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if !reflect.DeepEqual(sliceIDs, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", sliceIDs)
+		}
+	}
+}
+
+func TestController_MachineSlices_GroupNotFound(t *testing.T) {
+	controller, _ := getTestController()
+
+	sliceIDs, err := controller.MachineSlices(context.Background(), Request{RequestConfig: RequestConfig{Group: "foo"}}, "10.0.0.101")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(sliceIDs) != 0 {
+		t.Fatal("expected", 0, "got", len(sliceIDs))
+	}
+}
+
+func TestController_MachineGroups(t *testing.T) {
+	controller, dummyFleet := getTestController()
+
+	onMachine := func(name, sliceID, ip string) {
+		dummyFleet.Units[name] = fleet.UnitStatus{
+			Name:    name,
+			SliceID: sliceID,
+			Machine: []fleet.MachineStatus{{ID: "id-" + ip, IP: net.ParseIP(ip)}},
+		}
+		dummyFleet.Contents[name] = "[Service]\nExecStart=/bin/true\n"
+	}
+	onMachine("app-main@1.service", "1", "10.0.0.101")
+	onMachine("app-main@2.service", "2", "10.0.0.102")
+	onMachine("app-test-main@1.service", "1", "10.0.0.101")
+	onMachine("web-nginx.service", "", "10.0.0.101")
+	onMachine("db-postgres@1.service", "1", "10.0.0.102")
+	onMachine("api-v2-server@1.service", "1", "10.0.0.101")
+	dummyFleet.Contents["api-v2-server@1.service"] = "[Service]\nExecStart=/bin/true\n\n[X-Inago]\nGroup=api-v2\n"
+	onMachine("app-logs.service", "", "10.0.0.101")
+	dummyFleet.Contents["app-logs.service"] = "[Service]\nExecStart=/bin/true\n\n[X-Fleet]\nGlobal=true\n"
+	onMachine("etcd-backup.service", "", "10.0.0.101")
+
+	// Groups not known locally are only found using labels. Units of other
+	// groups and global units are left alone.
+	groups, err := controller.MachineGroups(context.Background(), "10.0.0.101", []string{"app", "app-test"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	expected := []string{"api-v2", "app", "app-test"}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Fatal("expected", expected, "got", groups)
+	}
+
+	groups, err = controller.MachineGroups(context.Background(), "id-10.0.0.102", []string{"app"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	expected = []string{"app"}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Fatal("expected", expected, "got", groups)
+	}
+
+	_, err = controller.MachineGroups(context.Background(), "", nil)
+	if !IsInvalidArgument(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
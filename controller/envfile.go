@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/base64"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/coreos/fleet/unit"
+
+	"github.com/giantswarm/inago/common"
+)
+
+// EnvFileDir is the directory environment files rendered from environment
+// file templates are written to on the machines of the cluster.
+const EnvFileDir = "/run/inago"
+
+// envTemplateData is the data environment file templates are rendered with.
+type envTemplateData struct {
+	// Group is the name of the group.
+	Group string
+
+	// SliceID is the ID of the slice the template is rendered for. It is empty
+	// for groups without slices.
+	SliceID string
+}
+
+// envSection is the section of units writing environment files the template
+// they have been created from is stored in, so that units fetched from the
+// cluster can be rendered for other slices. See parseEnvUnit.
+const envSection = "X-Inago-Env"
+
+// envUnitTemplate is the template of the content of units writing environment
+// files. The rendered environment file and the template are base64 encoded so
+// that they do not need to be escaped.
+var envUnitTemplate = template.Must(template.New("env-unit").Parse(`[Unit]
+Description=Environment file {{.Path}}
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/bin/sh -c 'mkdir -p {{.Dir}} && echo {{.Encoded}} | base64 -d > {{.Path}}'
+ExecStop=/bin/rm -f {{.Path}}
+
+[` + envSection + `]
+File={{.File}}
+Template={{.Template}}
+`))
+
+// EnvUnitName returns the name of the unit writing the environment file of
+// the given environment file template.
+//
+//   mygroup-app@.env  =>  mygroup-app-env@.service
+//   mygroup-app.env   =>  mygroup-app-env.service
+//
+func EnvUnitName(envFile string) string {
+	base := strings.TrimSuffix(envFile, common.EnvFileExtension)
+	if strings.HasSuffix(base, "@") {
+		return strings.TrimSuffix(base, "@") + "-env@" + common.ServiceUnitExtension
+	}
+
+	return base + "-env" + common.ServiceUnitExtension
+}
+
+// EnvFilePath returns the path the environment file rendered from the given
+// environment file template is written to for the given slice.
+//
+//   mygroup-app@.env, 1  =>  /run/inago/mygroup-app@1.env
+//   mygroup-app.env, ""  =>  /run/inago/mygroup-app.env
+//
+func EnvFilePath(envFile, sliceID string) string {
+	return path.Join(EnvFileDir, strings.Replace(envFile, "@.", "@"+sliceID+".", 1))
+}
+
+// NewEnvUnit creates the unit writing the environment file rendered from the
+// given environment file template. The template is rendered for each slice
+// using text/template, having access to {{.Group}} and {{.SliceID}}. Units
+// using the environment file reference it using EnvFilePath, e.g. like this.
+//
+//   [Unit]
+//   Requires=mygroup-app-env@%i.service
+//   After=mygroup-app-env@%i.service
+//
+//   [Service]
+//   EnvironmentFile=/run/inago/mygroup-app@%i.env
+//
+//   [X-Fleet]
+//   MachineOf=mygroup-app-env@%i.service
+//
+// In case the template cannot be parsed, an error that you can identify using
+// IsInvalidEnvTemplate is returned.
+func NewEnvUnit(group, envFile, envTemplate string) (Unit, error) {
+	newUnit := Unit{
+		Name:        EnvUnitName(envFile),
+		EnvFile:     envFile,
+		EnvTemplate: envTemplate,
+	}
+
+	// Render the unit once to make sure the template is valid. The content is
+	// rendered again for each slice. See Request.ExtendSlices.
+	var err error
+	newUnit.Content, err = newUnit.envUnitContent(group, "")
+	if err != nil {
+		return Unit{}, maskAny(err)
+	}
+
+	return newUnit, nil
+}
+
+// envUnitContent renders the content of the given unit for the given slice.
+// Units not created using NewEnvUnit keep their content.
+func (u Unit) envUnitContent(group, sliceID string) (string, error) {
+	if u.EnvFile == "" {
+		return u.Content, nil
+	}
+
+	tmpl, err := template.New(u.EnvFile).Option("missingkey=error").Parse(u.EnvTemplate)
+	if err != nil {
+		return "", maskAnyf(invalidEnvTemplateError, "%s: %s", u.EnvFile, err.Error())
+	}
+	env := bytes.NewBuffer(nil)
+	err = tmpl.Execute(env, envTemplateData{Group: group, SliceID: sliceID})
+	if err != nil {
+		return "", maskAnyf(invalidEnvTemplateError, "%s: %s", u.EnvFile, err.Error())
+	}
+
+	filePath := EnvFilePath(u.EnvFile, sliceID)
+	content := bytes.NewBuffer(nil)
+	err = envUnitTemplate.Execute(content, struct {
+		Dir      string
+		Path     string
+		Encoded  string
+		File     string
+		Template string
+	}{
+		Dir:      path.Dir(filePath),
+		Path:     filePath,
+		Encoded:  base64.StdEncoding.EncodeToString(env.Bytes()),
+		File:     u.EnvFile,
+		Template: base64.StdEncoding.EncodeToString([]byte(u.EnvTemplate)),
+	})
+	if err != nil {
+		return "", maskAny(err)
+	}
+
+	return content.String(), nil
+}
+
+// parseEnvUnit turns the given unit fetched from the cluster back into the
+// unit created using NewEnvUnit, in case it writes an environment file. Its
+// content is the one rendered for the slice it was fetched from, while
+// EnvFile and EnvTemplate are restored from the unit's envSection, so that
+// the unit can be rendered for other slices. Other units are returned as they
+// are.
+func parseEnvUnit(u Unit) (Unit, error) {
+	unitFile, err := unit.NewUnitFile(u.Content)
+	if err != nil {
+		return Unit{}, maskAny(err)
+	}
+	section, ok := unitFile.Contents[envSection]
+	if !ok || len(section["File"]) != 1 || len(section["Template"]) != 1 {
+		return u, nil
+	}
+
+	envTemplate, err := base64.StdEncoding.DecodeString(section["Template"][0])
+	if err != nil {
+		return Unit{}, maskAnyf(invalidEnvTemplateError, "%s: %s", u.Name, err.Error())
+	}
+	u.EnvFile = section["File"][0]
+	u.EnvTemplate = string(envTemplate)
+
+	return u, nil
+}
+
+// SourceFile returns the name and content of the file of a group directory
+// the given unit is read from, i.e. the environment file template for units
+// created using NewEnvUnit, and the unit file for others.
+func (u Unit) SourceFile() (string, string) {
+	if u.EnvFile != "" {
+		return u.EnvFile, u.EnvTemplate
+	}
+
+	return u.Name, u.Content
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"encoding/base64"
+	"regexp"
+	"testing"
+)
+
+func Test_EnvUnitName(t *testing.T) {
+	testCases := map[string]string{
+		"mygroup-app@.env": "mygroup-app-env@.service",
+		"mygroup-app.env":  "mygroup-app-env.service",
+	}
+
+	for input, expected := range testCases {
+		if output := EnvUnitName(input); output != expected {
+			t.Fatal("input", input, "expected", expected, "got", output)
+		}
+	}
+}
+
+func Test_EnvFilePath(t *testing.T) {
+	testCases := []struct {
+		EnvFile  string
+		SliceID  string
+		Expected string
+	}{
+		{EnvFile: "mygroup-app@.env", SliceID: "1", Expected: "/run/inago/mygroup-app@1.env"},
+		{EnvFile: "mygroup-app.env", SliceID: "", Expected: "/run/inago/mygroup-app.env"},
+	}
+
+	for i, testCase := range testCases {
+		if output := EnvFilePath(testCase.EnvFile, testCase.SliceID); output != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", output)
+		}
+	}
+}
+
+// renderedEnv extracts the environment file written by the given env unit
+// content.
+func renderedEnv(t *testing.T, content string) string {
+	found := regexp.MustCompile(`echo (\S+) \| base64 -d > (\S+)'`).FindStringSubmatch(content)
+	if len(found) != 3 {
+		t.Fatal("expected", "env unit content", "got", content)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(found[1])
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	return found[2] + "\n" + string(decoded)
+}
+
+func Test_Request_ExtendSlices_EnvUnit(t *testing.T) {
+	envUnit, err := NewEnvUnit("mygroup", "mygroup-app@.env", "GROUP={{.Group}}\nSLICE={{.SliceID}}\n")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	req := Request{
+		RequestConfig: RequestConfig{Group: "mygroup", SliceIDs: []string{"1", "2"}},
+		Units: []Unit{
+			{Name: "mygroup-app@.service", Content: "[Service]\nEnvironmentFile=/run/inago/mygroup-app@%i.env\n"},
+			envUnit,
+		},
+	}
+	if ok, err := ValidateRequest(req); !ok {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	req, err = req.ExtendSlices()
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	expected := map[string]string{
+		"mygroup-app-env@1.service": "/run/inago/mygroup-app@1.env\nGROUP=mygroup\nSLICE=1\n",
+		"mygroup-app-env@2.service": "/run/inago/mygroup-app@2.env\nGROUP=mygroup\nSLICE=2\n",
+	}
+	for _, unit := range req.Units {
+		if unit.EnvFile == "" {
+			continue
+		}
+		if output := renderedEnv(t, unit.Content); output != expected[unit.Name] {
+			t.Fatal("unit", unit.Name, "expected", expected[unit.Name], "got", output)
+		}
+		delete(expected, unit.Name)
+	}
+	if len(expected) != 0 {
+		t.Fatal("expected", 0, "got", len(expected))
+	}
+}
+
+func Test_NewEnvUnit_InvalidTemplate(t *testing.T) {
+	testCases := []string{
+		"FOO={{.Foo",
+		"FOO={{.Unknown}}",
+	}
+
+	for i, testCase := range testCases {
+		_, err := NewEnvUnit("mygroup", "mygroup-app@.env", testCase)
+		if !IsInvalidEnvTemplate(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+	}
+}
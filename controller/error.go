@@ -2,8 +2,12 @@ package controller
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/errgo"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/policy"
 )
 
 // ValidationError capsules validation errors into one error struct.
@@ -14,7 +18,12 @@ type ValidationError struct {
 }
 
 func (e ValidationError) Error() string {
-	return fmt.Sprintf("Found %v validation error", len(e.CausingErrors))
+	var causes []string
+	for _, err := range e.CausingErrors {
+		causes = append(causes, err.Error())
+	}
+
+	return fmt.Sprintf("Found %v validation error: %s", len(e.CausingErrors), strings.Join(causes, "; "))
 }
 
 // Add adds the given error to the list of validation errors
@@ -87,11 +96,35 @@ func IsInvalidUnitStatus(err error) bool {
 
 var waitTimeoutReachedError = errgo.New("wait timeout reached")
 
-// IsWaitTimeoutReached asserts waitTimeoutReachedError.
+// IsWaitTimeoutReached checks whether the given error indicates that a group
+// did not reach the desired status within Config.WaitTimeout.
 func IsWaitTimeoutReached(err error) bool {
 	return errgo.Cause(err) == waitTimeoutReachedError
 }
 
+// IsTimeout checks whether the given error indicates that an operation did not
+// finish in time. This is the case for groups not reaching the desired status
+// within Config.WaitTimeout, see IsWaitTimeoutReached, and for operations
+// whose context reached its deadline.
+func IsTimeout(err error) bool {
+	return IsWaitTimeoutReached(err) || errgo.Cause(err) == context.DeadlineExceeded
+}
+
+var invalidEnvTemplateError = errgo.New("invalid environment file template")
+
+// IsInvalidEnvTemplate returns true if the given error cause is
+// invalidEnvTemplateError.
+func IsInvalidEnvTemplate(err error) bool {
+	return errgo.Cause(err) == invalidEnvTemplateError
+}
+
+var invalidUnitContentError = errgo.New("invalid unit content")
+
+// IsInvalidUnitContent asserts invalidUnitContentError.
+func IsInvalidUnitContent(err error) bool {
+	return errgo.Cause(err) == invalidUnitContentError
+}
+
 var invalidArgumentError = errgo.Newf("invalid argument")
 
 // IsInvalidArgument checks whether the given error indicates a invalid argument
@@ -102,21 +135,37 @@ func IsInvalidArgument(err error) bool {
 
 var updateFailedError = errgo.Newf("update failed")
 
-// IsUpdateFailed asserts updateFailedError.
+// IsUpdateFailed checks whether the given error indicates that a rolling update
+// was aborted, because new slices did not come up or old slices could not be
+// removed. Slices already replaced are kept, so the group may consist of old
+// and new slices afterwards.
 func IsUpdateFailed(err error) bool {
 	return errgo.Cause(err) == updateFailedError
 }
 
 var updateNotAllowedError = errgo.Newf("update not allowed")
 
-// IsUpdateNotAllowed asserts updateNotAllowedError.
+// IsUpdateNotAllowed checks whether the given error indicates that the
+// UpdateOptions given to Controller.Update cannot be applied to the group,
+// e.g. because more slices should be kept alive than are running.
 func IsUpdateNotAllowed(err error) bool {
 	return errgo.Cause(err) == updateNotAllowedError
 }
 
+var downgradeNotAllowedError = errgo.New("downgrade not allowed")
+
+// IsDowngradeNotAllowed checks whether the given error indicates that an
+// update was refused, because the group's units carry a lower semantic version
+// than the deployed slices. See UpdateOptions.AllowDowngrade.
+func IsDowngradeNotAllowed(err error) bool {
+	return errgo.Cause(err) == downgradeNotAllowedError
+}
+
 var unitsAlreadyUpToDate = errgo.Newf("units already up to date")
 
-// IsUnitsAlreadyUpToDate asserts unitsAlreadyUpToDate.
+// IsUnitsAlreadyUpToDate checks whether the given error indicates that an
+// update was not executed, because all slices of the group already run the
+// given unit files.
 func IsUnitsAlreadyUpToDate(err error) bool {
 	return errgo.Cause(err) == unitsAlreadyUpToDate
 }
@@ -163,6 +212,20 @@ func IsUnitsSameName(err error) bool {
 	return errgo.Cause(err) == unitsSameNameError
 }
 
+var unsupportedUnitKindError = errgo.New("unit kind not supported")
+
+// IsUnsupportedUnitKind returns true if the given error cause is unsupportedUnitKindError.
+func IsUnsupportedUnitKind(err error) bool {
+	return errgo.Cause(err) == unsupportedUnitKindError
+}
+
+var triggeredUnitNotInGroupError = errgo.New("unit activated by timer, socket or path unit not in group")
+
+// IsTriggeredUnitNotInGroup returns true if the given error cause is triggeredUnitNotInGroupError.
+func IsTriggeredUnitNotInGroup(err error) bool {
+	return errgo.Cause(err) == triggeredUnitNotInGroupError
+}
+
 var groupsArePrefixError = errgo.New("group is prefix of another group")
 
 // IsGroupsArePrefix returns true if the given error cause is groupsArePrefixError.
@@ -190,3 +253,169 @@ var invalidSubmitRequestNoSliceIDsGivenError = errgo.New("invalid submit request
 func IsInvalidSubmitRequestNoSliceIDsGiven(err error) bool {
 	return errgo.Cause(err) == invalidSubmitRequestNoSliceIDsGivenError
 }
+
+var sliceIDsExhaustedError = errgo.New("slice IDs exhausted")
+
+// IsSliceIDsExhausted checks whether the given error indicates that new slices
+// could not be created, because all IDs of the range defined by
+// Config.SliceIDRule are used already.
+func IsSliceIDsExhausted(err error) bool {
+	return errgo.Cause(err) == sliceIDsExhaustedError
+}
+
+var unitTooLargeError = errgo.New("unit too large")
+
+// IsUnitTooLarge checks whether the given error indicates that a unit cannot
+// be submitted, because fleet is not able to store it. See MaxUnitSize and
+// MaxUnitOptions.
+func IsUnitTooLarge(err error) bool {
+	return errgo.Cause(err) == unitTooLargeError
+}
+
+var sliceCollisionError = errgo.New("slices collide")
+
+// IsSliceCollision checks whether the given error indicates that the slices of
+// a group cannot run side by side on the same machine. See
+// ValidateSliceAwareness.
+func IsSliceCollision(err error) bool {
+	return errgo.Cause(err) == sliceCollisionError
+}
+
+var desiredScaleNotFoundError = errgo.New("desired scale not found")
+
+// IsDesiredScaleNotFound checks whether the given error indicates that no
+// desired scale has been recorded for a group. See Controller.DesiredScale.
+func IsDesiredScaleNotFound(err error) bool {
+	return errgo.Cause(err) == desiredScaleNotFoundError
+}
+
+var undoNotFoundError = errgo.New("undo not found")
+
+// IsUndoNotFound checks whether the given error indicates that no operation
+// of a group has been recorded that could be undone. See Controller.Undo.
+func IsUndoNotFound(err error) bool {
+	return errgo.Cause(err) == undoNotFoundError
+}
+
+var imageNotFoundError = errgo.New("image not found")
+
+// IsImageNotFound checks whether the given error indicates that a Docker image
+// used by a group does not exist in its registry. See Config.Registry.
+func IsImageNotFound(err error) bool {
+	return errgo.Cause(err) == imageNotFoundError
+}
+
+var prePullFailedError = errgo.New("pre-pulling images failed")
+
+// IsPrePullFailed checks whether the given error indicates that images could
+// not be pulled before starting a group. See Config.PrePullImages.
+func IsPrePullFailed(err error) bool {
+	return errgo.Cause(err) == prePullFailedError
+}
+
+var sliceNotRunningError = errgo.New("slice not running")
+
+// IsSliceNotRunning checks whether the given error indicates that a slice did
+// not reach StatusRunning after being started, e.g. because one of its units
+// failed. See Request.MaxFailures.
+func IsSliceNotRunning(err error) bool {
+	return errgo.Cause(err) == sliceNotRunningError
+}
+
+// IsInvalidRequest checks whether the given error indicates that a request
+// given to the controller is malformed. This is the case for ValidationErrors
+// as returned by ValidateRequest and ValidateSubmitRequest, for submit requests
+// defining both or none of Request.SliceIDs and Request.DesiredSlices, for
+// invalid environment file templates and for invalid arguments. Retrying the
+// operation without changing the request does not help.
+func IsInvalidRequest(err error) bool {
+	if _, ok := errgo.Cause(err).(ValidationError); ok {
+		return true
+	}
+
+	return IsInvalidArgument(err) ||
+		IsInvalidEnvTemplate(err) ||
+		IsInvalidSubmitRequestSlicesGiven(err) ||
+		IsInvalidSubmitRequestNoSliceIDsGiven(err)
+}
+
+// IsPolicyViolation checks whether the given error indicates that an operation
+// was rejected, because it violates Config.Policy. See also
+// policy.IsPolicyViolation.
+func IsPolicyViolation(err error) bool {
+	return policy.IsPolicyViolation(err)
+}
+
+var frozenError = errgo.New("frozen")
+
+// IsFrozen checks whether the given error indicates that an operation was
+// refused, because the group is frozen by a freeze of Config.Policy, or
+// because the cluster is frozen. See Controller.Freeze and
+// policy.IsFrozen.
+func IsFrozen(err error) bool {
+	return errgo.Cause(err) == frozenError || policy.IsFrozen(err)
+}
+
+// SliceError represents an error that occurred while operating on a specific
+// slice of a group.
+type SliceError struct {
+	// SliceID is the ID of the slice the error occurred for. It is empty for
+	// groups that are not sliceable.
+	SliceID string
+
+	// Err is the error that occurred.
+	Err error
+}
+
+// MultiSliceError is returned by operations spanning multiple slices in case
+// at least one slice failed. Instead of only reporting the first failure, it
+// records which slices succeeded and which failed.
+type MultiSliceError struct {
+	// Succeeded contains the IDs of all slices the operation succeeded for.
+	Succeeded []string
+
+	// Failed contains the errors of all slices the operation failed for.
+	Failed []SliceError
+}
+
+func (e MultiSliceError) Error() string {
+	return fmt.Sprintf("operation failed for %d of %d slices", len(e.Failed), len(e.Failed)+len(e.Succeeded))
+}
+
+// HasFailed returns true if the operation failed for at least one slice.
+func (e MultiSliceError) HasFailed() bool {
+	return len(e.Failed) > 0
+}
+
+// IsMultiSliceError returns true if the given error cause is a
+// MultiSliceError.
+func IsMultiSliceError(err error) bool {
+	_, ok := errgo.Cause(err).(MultiSliceError)
+	return ok
+}
+
+// FlappingError is returned by Controller.CheckFlapping in case slices of a
+// group restarted more often than Config.FlappingThreshold within
+// Config.FlappingWindow.
+type FlappingError struct {
+	// Group is the group the flapping slices belong to.
+	Group string
+
+	// Slices contains the flapping slices, sorted by ID.
+	Slices []FlappingSlice
+}
+
+func (e FlappingError) Error() string {
+	var slices []string
+	for _, s := range e.Slices {
+		slices = append(slices, fmt.Sprintf("%s (%d restarts)", s.SliceID, s.Restarts))
+	}
+
+	return fmt.Sprintf("slices of group '%s' flapping: %s", e.Group, strings.Join(slices, ", "))
+}
+
+// IsFlapping returns true if the given error cause is a FlappingError.
+func IsFlapping(err error) bool {
+	_, ok := errgo.Cause(err).(FlappingError)
+	return ok
+}
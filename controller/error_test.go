@@ -3,6 +3,8 @@ package controller
 import (
 	"fmt"
 	"testing"
+
+	"golang.org/x/net/context"
 )
 
 func Test_Controller_maskAnyf(t *testing.T) {
@@ -91,6 +93,34 @@ func Test_Controller_errors(t *testing.T) {
 			Output:   IsUnitSliceNotFound(waitTimeoutReachedError),
 			Expected: false,
 		},
+		{
+			Output:   IsTimeout(maskAny(waitTimeoutReachedError)),
+			Expected: true,
+		},
+		{
+			Output:   IsTimeout(maskAny(context.DeadlineExceeded)),
+			Expected: true,
+		},
+		{
+			Output:   IsTimeout(unitNotFoundError),
+			Expected: false,
+		},
+		{
+			Output:   IsInvalidRequest(ValidationError{CausingErrors: []error{noUnitsInGroupError}}),
+			Expected: true,
+		},
+		{
+			Output:   IsInvalidRequest(maskAnyf(invalidArgumentError, "machine must not be empty")),
+			Expected: true,
+		},
+		{
+			Output:   IsInvalidRequest(invalidSubmitRequestNoSliceIDsGivenError),
+			Expected: true,
+		},
+		{
+			Output:   IsInvalidRequest(unitNotFoundError),
+			Expected: false,
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/fleet"
+)
+
+// EventType describes what happened in an Event.
+type EventType string
+
+const (
+	// EventUnitSubmitted is emitted once a unit was submitted to fleet.
+	EventUnitSubmitted EventType = "unit-submitted"
+
+	// EventUnitStarted is emitted once the target state of a unit was set to
+	// launched.
+	EventUnitStarted EventType = "unit-started"
+
+	// EventUnitStopped is emitted once the target state of a unit was set to
+	// loaded.
+	EventUnitStopped EventType = "unit-stopped"
+
+	// EventUnitDestroyed is emitted once a unit was removed from fleet.
+	EventUnitDestroyed EventType = "unit-destroyed"
+
+	// EventUnitStatusChanged is emitted while waiting for a group to reach a
+	// status, each time the aggregated status of one of its units changes.
+	EventUnitStatusChanged EventType = "unit-status-changed"
+
+	// EventSliceReady is emitted during updates once a new slice is running and
+	// the configured ready time has passed.
+	EventSliceReady EventType = "slice-ready"
+
+	// EventSliceRemoved is emitted during updates once an old slice was stopped
+	// and destroyed.
+	EventSliceRemoved EventType = "slice-removed"
+)
+
+// Event describes progress the controller made while executing an operation.
+// See Config.Events.
+type Event struct {
+	// Type describes what happened.
+	Type EventType
+
+	// Time is the time the event was emitted.
+	Time time.Time
+
+	// Group is the group the event refers to.
+	Group string
+
+	// SliceID is the slice the event refers to. It is empty for groups without
+	// slices.
+	SliceID string
+
+	// Unit is the name of the unit the event refers to, e.g. "mygroup-foo@1.service".
+	// It is empty for slice events.
+	Unit string
+
+	// Status is the aggregated status of the unit. It is only set for
+	// EventUnitStatusChanged, and empty in case the unit is not scheduled to
+	// any machine yet.
+	Status Status
+}
+
+func (c controller) Events() <-chan Event {
+	return c.Config.Events
+}
+
+// emitUnitEvent emits an event of the given type for the given unit of the
+// given group.
+func (c controller) emitUnitEvent(ctx context.Context, t EventType, group, name string) {
+	sliceID, _ := common.SliceID(name)
+	c.emit(ctx, Event{Type: t, Group: group, SliceID: sliceID, Unit: name})
+}
+
+// emitSliceEvents emits an event of the given type for each slice of the given
+// request.
+func (c controller) emitSliceEvents(ctx context.Context, t EventType, req Request) {
+	for _, sliceID := range req.SliceIDs {
+		c.emit(ctx, Event{Type: t, Group: req.Group, SliceID: sliceID})
+	}
+}
+
+// emitStatusChanges emits EventUnitStatusChanged for all units of the given
+// list whose aggregated status differs from the one stored in the given map.
+// The map is updated accordingly.
+func (c controller) emitStatusChanges(ctx context.Context, group string, usl []fleet.UnitStatus, seen map[string]Status) {
+	if c.Config.Events == nil {
+		return
+	}
+
+	aggregator := Aggregator{
+		Logger: c.Config.Logger,
+	}
+	for _, us := range usl {
+		var status Status
+		if len(us.Machine) > 0 {
+			aggregated, err := aggregator.AggregateStatus(us.Current, us.Desired, us.Machine[0].SystemdActive, us.Machine[0].SystemdSub)
+			if err != nil {
+				continue
+			}
+			status = aggregated
+		}
+
+		if last, ok := seen[us.Name]; ok && last == status {
+			continue
+		}
+		seen[us.Name] = status
+		c.emit(ctx, Event{Type: EventUnitStatusChanged, Group: group, SliceID: us.SliceID, Unit: us.Name, Status: status})
+	}
+}
+
+// emit sends the given event to the configured events channel. Events are
+// never blocking operations. In case the channel is full, the event is dropped.
+func (c controller) emit(ctx context.Context, e Event) {
+	if c.Config.Events == nil {
+		return
+	}
+
+	e.Time = time.Now()
+	select {
+	case c.Config.Events <- e:
+	default:
+		c.Config.Logger.Debug(ctx, "controller: dropping event %#v, events channel is full", e)
+	}
+}
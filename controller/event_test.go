@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/task"
+)
+
+func TestController_Events(t *testing.T) {
+	testController, _ := getTestController()
+	testController.Config.WaitSleep = 10 * time.Millisecond
+	testController.Config.Events = make(chan Event, 100)
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "foo", SliceIDs: []string{"1"}},
+		Units: []Unit{
+			{Name: "foo-main@.service", Content: "[Service]\nExecStart=/bin/foo\n"},
+		},
+	}
+
+	for _, action := range []func(context.Context, Request) (*task.Task, error){testController.Submit, testController.Start} {
+		if err := testController.executeTaskAction(action, context.Background(), req); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	var types []EventType
+	statuses := map[Status]bool{}
+L:
+	for {
+		select {
+		case e := <-testController.Events():
+			if e.Group != "foo" || e.SliceID != "1" || e.Unit != "foo-main@1.service" || e.Time.IsZero() {
+				t.Fatal("expected", "event for foo-main@1.service", "got", e)
+			}
+			if e.Type == EventUnitStatusChanged {
+				statuses[e.Status] = true
+				continue
+			}
+			types = append(types, e.Type)
+		default:
+			break L
+		}
+	}
+
+	if len(types) != 2 || types[0] != EventUnitSubmitted || types[1] != EventUnitStarted {
+		t.Fatal("expected", []EventType{EventUnitSubmitted, EventUnitStarted}, "got", types)
+	}
+	if !statuses[StatusStopped] || !statuses[StatusRunning] {
+		t.Fatal("expected", "status changes to stopped and running", "got", statuses)
+	}
+}
+
+func TestController_Events_Full(t *testing.T) {
+	testController, _ := getTestController()
+	testController.Config.Events = make(chan Event, 1)
+
+	// Sending events must never block, even if nobody reads them.
+	testController.emit(context.Background(), Event{Type: EventUnitSubmitted})
+	testController.emit(context.Background(), Event{Type: EventUnitStarted})
+
+	e := <-testController.Events()
+	if e.Type != EventUnitSubmitted {
+		t.Fatal("expected", EventUnitSubmitted, "got", e.Type)
+	}
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"golang.org/x/net/context"
+)
+
+func (c controller) Exists(ctx context.Context, req Request) (bool, error) {
+	c.Config.Logger.Debug(ctx, "controller: checking existence of group '%s'", req.Group)
+
+	// Units of sliced groups can only be named in case the slices are known.
+	// Otherwise all units need to be listed to find the group's slices.
+	if len(req.Units) == 0 || (req.isSliceable() && len(req.SliceIDs) == 0) {
+		unitStatusList, err := c.groupStatus(ctx, req)
+		if IsUnitNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, maskAny(err)
+		}
+		if len(unitStatusList) == 0 {
+			return false, nil
+		}
+		if err := validateUnitStatusWithRequest(unitStatusList, req); IsUnitSliceNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, maskAny(err)
+		}
+
+		return true, nil
+	}
+
+	sliceIDs := req.SliceIDs
+	if len(sliceIDs) == 0 {
+		// Groups without slices exist in case any of their units exists.
+		sliceIDs = []string{""}
+	}
+	for _, sliceID := range sliceIDs {
+		sliceReq := req
+		sliceReq.SliceIDs = nil
+		if sliceID != "" {
+			sliceReq.SliceIDs = []string{sliceID}
+		}
+		sliceReq, err := sliceReq.ExtendSlices()
+		if err != nil {
+			return false, maskAny(err)
+		}
+
+		// A slice exists in case any of its units exists, e.g. when it is only
+		// partially submitted.
+		found := false
+		for _, unit := range sliceReq.Units {
+			found, err = c.Fleet.Exists(ctx, unit.Name)
+			if err != nil {
+				return false, maskAny(err)
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestController_Exists_DummyFleet(t *testing.T) {
+	controller, dummyFleet := getTestController()
+	ctx := context.Background()
+
+	for _, name := range []string{"foo-main@1.service", "foo-side@1.service", "bar.service"} {
+		if err := dummyFleet.Submit(ctx, name, "content"); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	sliced := []Unit{{Name: "foo-main@.service"}, {Name: "foo-side@.service"}}
+	testCases := []struct {
+		Group    string
+		SliceIDs []string
+		Units    []Unit
+		Expected bool
+	}{
+		// Units are looked up directly.
+		{Group: "foo", SliceIDs: []string{"1"}, Units: sliced, Expected: true},
+		{Group: "foo", SliceIDs: []string{"1", "2"}, Units: sliced, Expected: false},
+		{Group: "bar", Units: []Unit{{Name: "bar.service"}}, Expected: true},
+		{Group: "baz", Units: []Unit{{Name: "baz.service"}}, Expected: false},
+		// Units are listed.
+		{Group: "foo", Units: sliced, Expected: true},
+		{Group: "foo", SliceIDs: []string{"2"}, Expected: false},
+		{Group: "baz", Expected: false},
+	}
+
+	for i, testCase := range testCases {
+		req := Request{
+			RequestConfig: RequestConfig{Group: testCase.Group, SliceIDs: testCase.SliceIDs},
+			Units:         testCase.Units,
+		}
+		ok, err := controller.Exists(ctx, req)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", ok)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/state"
+)
+
+// flappingNamespace is the namespace of the state store the restarts of
+// slices are stored in, keyed by group.
+const flappingNamespace = "flapping"
+
+// groupActivity is stored for each group whose status has been fetched. It
+// tracks whether slices are active, in order to notice them becoming active
+// again, i.e. restarting.
+type groupActivity struct {
+	Slices map[string]sliceActivity `json:"slices"`
+}
+
+type sliceActivity struct {
+	// Active is whether the slice was active when last observed.
+	Active bool `json:"active"`
+
+	// Started is whether the slice has been observed being active before.
+	// Slices becoming active for the first time are not restarting.
+	Started bool `json:"started"`
+
+	// Restarts contains the times the slice became active again within
+	// Config.FlappingWindow.
+	Restarts []time.Time `json:"restarts,omitempty"`
+}
+
+// FlappingSlice describes a slice restarting more often than
+// Config.FlappingThreshold within Config.FlappingWindow.
+type FlappingSlice struct {
+	SliceID     string    `json:"slice-id"`
+	Restarts    int       `json:"restarts"`
+	LastRestart time.Time `json:"last-restart"`
+}
+
+// observeRestarts records the restarts of the slices of the given group, as
+// observed using the given unit states. A slice restarts when it becomes
+// active again after having been active before. In case the given unit
+// states only cover some slices of the group, as given using the slice IDs
+// of the given request, slices not covered are kept as they are. Restarts
+// older than Config.FlappingWindow are dropped. Without state store, restarts
+// are not tracked, neither are they for units selected using Request.Match.
+// Tracking restarts must not fail fetching the status of a group, so errors
+// are only logged.
+func (c controller) observeRestarts(ctx context.Context, req Request, usl []fleet.UnitStatus, now time.Time) {
+	if c.Config.StateStore == nil || IsGroupPattern(req.Group) || req.Match != nil {
+		return
+	}
+
+	var activity groupActivity
+	err := c.Config.StateStore.Get(flappingNamespace, req.Group, &activity)
+	if err != nil && !state.IsNotFound(err) {
+		c.Config.Logger.Debug(ctx, "controller: reading restarts of group '%s' failed: %#v", req.Group, maskAny(err))
+		return
+	}
+	if activity.Slices == nil {
+		activity.Slices = map[string]sliceActivity{}
+	}
+
+	changed := false
+	active := activeSlices(usl)
+	for sliceID, isActive := range active {
+		last, ok := activity.Slices[sliceID]
+		next := sliceActivity{Active: isActive, Started: last.Started || isActive}
+		if ok && isActive && !last.Active && last.Started {
+			next.Restarts = append(next.Restarts, now)
+		}
+		for _, t := range last.Restarts {
+			if now.Sub(t) <= c.Config.FlappingWindow {
+				next.Restarts = append(next.Restarts, t)
+			}
+		}
+		sort.Sort(timesAscending(next.Restarts))
+
+		if !ok || next.Active != last.Active || next.Started != last.Started || len(next.Restarts) != len(last.Restarts) {
+			changed = true
+		}
+		activity.Slices[sliceID] = next
+	}
+	if len(req.SliceIDs) == 0 {
+		for sliceID := range activity.Slices {
+			if _, ok := active[sliceID]; !ok {
+				delete(activity.Slices, sliceID)
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return
+	}
+	if err := c.Config.StateStore.Set(flappingNamespace, req.Group, activity); err != nil {
+		c.Config.Logger.Debug(ctx, "controller: recording restarts of group '%s' failed: %#v", req.Group, maskAny(err))
+	}
+}
+
+// activeSlices returns whether each slice of the given unit states is active,
+// i.e. all of its units are active on their machines.
+func activeSlices(usl []fleet.UnitStatus) map[string]bool {
+	active := map[string]bool{}
+	for _, us := range usl {
+		unitActive := len(us.Machine) > 0
+		for _, ms := range us.Machine {
+			if ms.SystemdActive != "active" {
+				unitActive = false
+			}
+		}
+
+		if a, ok := active[us.SliceID]; ok {
+			active[us.SliceID] = a && unitActive
+		} else {
+			active[us.SliceID] = unitActive
+		}
+	}
+
+	return active
+}
+
+func (c controller) CheckFlapping(ctx context.Context, group string) error {
+	c.Config.Logger.Debug(ctx, "controller: checking whether slices of group '%s' are flapping", group)
+
+	if c.Config.StateStore == nil {
+		return nil
+	}
+
+	var activity groupActivity
+	err := c.Config.StateStore.Get(flappingNamespace, group, &activity)
+	if state.IsNotFound(err) {
+		return nil
+	} else if state.IsInvalidKey(err) {
+		return maskAnyf(invalidArgumentError, "group: %s", err.Error())
+	} else if err != nil {
+		return maskAny(err)
+	}
+
+	now := time.Now()
+	var flapping []FlappingSlice
+	for sliceID, a := range activity.Slices {
+		var recent []time.Time
+		for _, t := range a.Restarts {
+			if now.Sub(t) <= c.Config.FlappingWindow {
+				recent = append(recent, t)
+			}
+		}
+		if len(recent) <= c.Config.FlappingThreshold {
+			continue
+		}
+		flapping = append(flapping, FlappingSlice{SliceID: sliceID, Restarts: len(recent), LastRestart: recent[len(recent)-1]})
+	}
+	if len(flapping) == 0 {
+		return nil
+	}
+	sort.Sort(flappingSlicesByID(flapping))
+
+	return maskAny(FlappingError{Group: group, Slices: flapping})
+}
+
+type timesAscending []time.Time
+
+func (t timesAscending) Len() int           { return len(t) }
+func (t timesAscending) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t timesAscending) Less(i, j int) bool { return t[i].Before(t[j]) }
+
+type flappingSlicesByID []FlappingSlice
+
+func (f flappingSlicesByID) Len() int           { return len(f) }
+func (f flappingSlicesByID) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f flappingSlicesByID) Less(i, j int) bool { return f[i].SliceID < f[j].SliceID }
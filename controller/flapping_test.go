@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juju/errgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/state"
+)
+
+func TestController_CheckFlapping(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	c, _ := getTestController()
+	Expect(c.CheckFlapping(ctx, "app")).To(BeNil())
+
+	c.Config.StateStore = state.NewMemoryStore()
+	c.Config.FlappingThreshold = 2
+	c.Config.FlappingWindow = 10 * time.Minute
+
+	statuses := func(active1, active2 string) []fleet.UnitStatus {
+		return []fleet.UnitStatus{
+			{Name: "app-main@1.service", SliceID: "1", Machine: []fleet.MachineStatus{{SystemdActive: active1}}},
+			{Name: "app-main@2.service", SliceID: "2", Machine: []fleet.MachineStatus{{SystemdActive: active2}}},
+		}
+	}
+	req := Request{RequestConfig: RequestConfig{Group: "app"}}
+
+	// Slice 1 restarts three times, slice 2 once.
+	start := time.Now().Add(-5 * time.Minute)
+	c.observeRestarts(ctx, req, statuses("activating", "activating"), start)
+	c.observeRestarts(ctx, req, statuses("active", "active"), start.Add(1*time.Minute))
+	Expect(c.CheckFlapping(ctx, "app")).To(BeNil())
+	for i := 0; i < 3; i++ {
+		c.observeRestarts(ctx, req, statuses("failed", "active"), start.Add(time.Duration(2*i+2)*time.Second))
+		c.observeRestarts(ctx, req, statuses("active", "active"), start.Add(time.Duration(2*i+3)*time.Second))
+	}
+	c.observeRestarts(ctx, req, statuses("active", "inactive"), start.Add(10*time.Second))
+	c.observeRestarts(ctx, req, statuses("active", "active"), start.Add(11*time.Second))
+
+	err := c.CheckFlapping(ctx, "app")
+	Expect(IsFlapping(err)).To(BeTrue())
+	flappingErr := errgo.Cause(err).(FlappingError)
+	Expect(flappingErr.Slices).To(HaveLen(1))
+	Expect(flappingErr.Slices[0].SliceID).To(Equal("1"))
+	Expect(flappingErr.Slices[0].Restarts).To(Equal(3))
+	Expect(flappingErr.Error()).To(Equal("slices of group 'app' flapping: 1 (3 restarts)"))
+
+	// Restarts outside of the window do not count.
+	c.Config.FlappingWindow = 1 * time.Minute
+	Expect(c.CheckFlapping(ctx, "app")).To(BeNil())
+
+	// Slices that are gone are forgotten.
+	c.Config.FlappingWindow = 10 * time.Minute
+	c.observeRestarts(ctx, req, statuses("active", "active")[1:], time.Now())
+	Expect(c.CheckFlapping(ctx, "app")).To(BeNil())
+}
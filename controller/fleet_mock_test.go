@@ -63,6 +63,26 @@ func (fm *fleetMock) GetStatus(ctx context.Context, name string) (fleet.UnitStat
 	args := fm.Called(name)
 	return args.Get(0).(fleet.UnitStatus), args.Error(1)
 }
+func (fm *fleetMock) GetContent(ctx context.Context, name string) (string, error) {
+	args := fm.Called(name)
+	return args.String(0), args.Error(1)
+}
+func (fm *fleetMock) Exists(ctx context.Context, name string) (bool, error) {
+	args := fm.Called(name)
+	return args.Bool(0), args.Error(1)
+}
+func (fm *fleetMock) APIVersion(ctx context.Context) (string, error) {
+	args := fm.Called()
+	return args.String(0), args.Error(1)
+}
+func (fm *fleetMock) UnitsPage(ctx context.Context, pageToken string) (fleet.UnitPage, error) {
+	args := fm.Called(pageToken)
+	return args.Get(0).(fleet.UnitPage), args.Error(1)
+}
+func (fm *fleetMock) Machines(ctx context.Context) ([]fleet.Machine, error) {
+	args := fm.Called()
+	return args.Get(0).([]fleet.Machine), args.Error(1)
+}
 func (fm *fleetMock) GetStatusWithExpression(exp *regexp.Regexp) ([]fleet.UnitStatus, error) {
 	args := fm.Called(exp)
 	return args.Get(0).([]fleet.UnitStatus), args.Error(1)
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/policy"
+	"github.com/giantswarm/inago/state"
+)
+
+const (
+	// freezeNamespace is the namespace of the state store the cluster freeze
+	// is stored in.
+	freezeNamespace = "freeze"
+
+	// freezeName is the name the cluster freeze is stored under.
+	freezeName = "cluster"
+)
+
+// ClusterFreeze describes a freeze of all groups of a cluster. While the
+// cluster is frozen, all operations changing groups fail. See
+// Controller.Freeze.
+type ClusterFreeze struct {
+	// Frozen is true while the cluster is frozen.
+	Frozen bool `json:"frozen"`
+
+	// Reason tells why the cluster is frozen, e.g. "release 1.2 testing".
+	Reason string `json:"reason"`
+
+	// Since is the time the cluster was frozen or unfrozen.
+	Since time.Time `json:"since"`
+}
+
+func (c controller) Freeze(ctx context.Context, reason string) error {
+	c.Config.Logger.Debug(ctx, "controller: freezing cluster")
+
+	if err := c.setClusterFreeze(ClusterFreeze{Frozen: true, Reason: reason, Since: time.Now()}); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (c controller) Unfreeze(ctx context.Context) error {
+	c.Config.Logger.Debug(ctx, "controller: unfreezing cluster")
+
+	if err := c.setClusterFreeze(ClusterFreeze{Frozen: false, Since: time.Now()}); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (c controller) GetClusterFreeze(ctx context.Context) (ClusterFreeze, error) {
+	c.Config.Logger.Debug(ctx, "controller: looking up cluster freeze")
+
+	if c.Config.StateStore == nil {
+		return ClusterFreeze{}, nil
+	}
+
+	var freeze ClusterFreeze
+	err := c.Config.StateStore.Get(freezeNamespace, freezeName, &freeze)
+	if state.IsNotFound(err) {
+		return ClusterFreeze{}, nil
+	} else if err != nil {
+		return ClusterFreeze{}, maskAny(err)
+	}
+
+	return freeze, nil
+}
+
+func (c controller) setClusterFreeze(freeze ClusterFreeze) error {
+	if c.Config.StateStore == nil {
+		return maskAnyf(invalidArgumentError, "freezing the cluster requires a state store")
+	}
+
+	if err := c.Config.StateStore.Set(freezeNamespace, freezeName, freeze); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// checkFreeze checks whether the group of the given request is frozen, either
+// by a freeze of Config.Policy or by the cluster freeze. In case
+// Config.OverrideFreeze is set, freezes are only logged.
+func (c controller) checkFreeze(ctx context.Context, op policy.Operation, req Request) error {
+	c.Config.Logger.Debug(ctx, "controller: checking freezes for %s of group '%s'", op, req.Group)
+
+	err := c.Config.Policy.CheckFreeze(op, req.Group, time.Now())
+	if err == nil {
+		freeze, getErr := c.GetClusterFreeze(ctx)
+		if getErr != nil {
+			return maskAny(getErr)
+		}
+		if freeze.Frozen {
+			reason := ""
+			if freeze.Reason != "" {
+				reason = " (" + freeze.Reason + ")"
+			}
+			err = maskAnyf(frozenError, "%s of group '%s' refused, cluster is frozen since %s%s", op, req.Group, freeze.Since.Format(time.RFC3339), reason)
+		}
+	}
+
+	if IsFrozen(err) && c.Config.OverrideFreeze {
+		c.Config.Logger.Warning(ctx, "Overriding freeze: %s", err.Error())
+		return nil
+	} else if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
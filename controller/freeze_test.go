@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/policy"
+	"github.com/giantswarm/inago/state"
+)
+
+func TestController_Freeze(t *testing.T) {
+	testController, _ := getTestController()
+	testController.Config.StateStore = state.NewMemoryStore()
+	ctx := context.Background()
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "foo", SliceIDs: []string{"1"}},
+		Units: []Unit{
+			{Name: "foo-main@.service", Content: "[Service]\nExecStart=/bin/foo\n"},
+		},
+	}
+
+	if err := testController.Freeze(ctx, "release testing"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	freeze, err := testController.GetClusterFreeze(ctx)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if !freeze.Frozen || freeze.Reason != "release testing" {
+		t.Fatal("expected", "frozen cluster", "got", freeze)
+	}
+
+	_, err = testController.Submit(ctx, req)
+	if !IsFrozen(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+
+	// Freezes can be overridden.
+	overriding := testController
+	overriding.Config.OverrideFreeze = true
+	if err := overriding.executeTaskAction(overriding.Submit, ctx, req); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	if err := testController.Unfreeze(ctx); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if err := testController.executeTaskAction(testController.Start, ctx, req); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+}
+
+func TestController_Freeze_Policy(t *testing.T) {
+	testController, _ := getTestController()
+	testController.Config.Policy = policy.Policy{
+		Freezes: []policy.Freeze{
+			{Group: "foo*", Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour), Reason: "holidays"},
+		},
+	}
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "foo", SliceIDs: []string{"1"}},
+		Units: []Unit{
+			{Name: "foo-main@.service", Content: "[Service]\nExecStart=/bin/foo\n"},
+		},
+	}
+	_, err := testController.Submit(context.Background(), req)
+	if !IsFrozen(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+
+	req = req.WithGroup("bar")
+	if err := testController.executeTaskAction(testController.Submit, context.Background(), req); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+}
+
+func TestController_Freeze_NoStateStore(t *testing.T) {
+	testController, _ := getTestController()
+
+	err := testController.Freeze(context.Background(), "")
+	if !IsInvalidRequest(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+	freeze, err := testController.GetClusterFreeze(context.Background())
+	if err != nil || freeze.Frozen {
+		t.Fatal("expected", "unfrozen cluster", "got", freeze, err)
+	}
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/fleet"
+)
+
+// IsGroupPattern checks whether the given group is a glob pattern, e.g.
+// "api-*", rather than the name of a single group. See MatchingGroups.
+func IsGroupPattern(group string) bool {
+	return strings.ContainsAny(group, "*?[")
+}
+
+func (c controller) MatchingGroups(ctx context.Context, pattern string) ([]string, error) {
+	c.Config.Logger.Debug(ctx, "controller: fetching groups matching '%s'", pattern)
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, maskAnyf(invalidArgumentError, "bad group pattern '%s'", pattern)
+	}
+
+	unitStatusList, err := c.Fleet.GetStatusWithMatcher(func(name string) bool {
+		_, ok := matchingGroup(pattern, name)
+		return ok
+	})
+	if fleet.IsUnitNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	var groups []string
+	for _, us := range unitStatusList {
+		if group, ok := matchingGroup(pattern, us.Name); ok && !contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+
+	return groups, nil
+}
+
+// matchingGroup returns the group of the unit having the given name, in case
+// it matches the given glob pattern. Unit names only tell that they are
+// prefixed by their group, e.g. "api-v2-web@1.service" may belong to the
+// groups "api", "api-v2" or "api-v2-web". The shortest of them matching the
+// pattern is used.
+func matchingGroup(pattern, name string) (string, bool) {
+	base := strings.TrimSuffix(name, common.UnitExtension(name))
+	if i := strings.Index(base, "@"); i >= 0 {
+		base = base[:i]
+	}
+
+	for i := 0; i <= len(base); i++ {
+		if i < len(base) && base[i] != '-' {
+			continue
+		}
+		if ok, _ := path.Match(pattern, base[:i]); ok {
+			return base[:i], true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func Test_matchingGroup(t *testing.T) {
+	testCases := []struct {
+		Pattern  string
+		Name     string
+		Expected string
+		OK       bool
+	}{
+		{Pattern: "api-*", Name: "api-v2-web@1.service", Expected: "api-v2", OK: true},
+		{Pattern: "api*", Name: "api-v2-web@1.service", Expected: "api", OK: true},
+		{Pattern: "api-*", Name: "api@1.service", Expected: "", OK: false},
+		{Pattern: "api-*", Name: "api-v2@1.service", Expected: "api-v2", OK: true},
+		{Pattern: "*-db", Name: "payments-db-backup.timer", Expected: "payments-db", OK: true},
+		{Pattern: "web-?", Name: "web-1-main.service", Expected: "web-1", OK: true},
+		{Pattern: "web-[12]", Name: "web-3-main.service", Expected: "", OK: false},
+	}
+
+	for i, testCase := range testCases {
+		group, ok := matchingGroup(testCase.Pattern, testCase.Name)
+		if group != testCase.Expected || ok != testCase.OK {
+			t.Fatal("case", i+1, "expected", testCase.Expected, testCase.OK, "got", group, ok)
+		}
+	}
+}
+
+func TestController_MatchingGroups(t *testing.T) {
+	testController, dummyFleet := getTestController()
+
+	for _, name := range []string{"api-a-main@1.service", "api-a-main@2.service", "api-b-main@1.service", "web-main@1.service"} {
+		if err := dummyFleet.Submit(context.Background(), name, "[Service]\nExecStart=/bin/true\n"); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	groups, err := testController.MatchingGroups(context.Background(), "api-*")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if expected := []string{"api-a", "api-b"}; !reflect.DeepEqual(groups, expected) {
+		t.Fatal("expected", expected, "got", groups)
+	}
+
+	groups, err = testController.MatchingGroups(context.Background(), "db-*")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(groups) != 0 {
+		t.Fatal("expected", 0, "got", groups)
+	}
+
+	_, err = testController.MatchingGroups(context.Background(), "api-[")
+	if !IsInvalidRequest(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
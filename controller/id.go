@@ -2,7 +2,11 @@ package controller
 
 import (
 	"crypto/rand"
+	"fmt"
 	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 var (
@@ -25,3 +29,128 @@ func NewID() string {
 
 	return string(b)
 }
+
+// SliceIDRule defines how the IDs of new slices are created. The zero value
+// creates random IDs using NewID. Otherwise IDs are taken from a range of
+// numbers, e.g. to adopt groups following an existing naming convention like
+// "app@web-01.service". Note that the @ separating the unit name from the slice
+// ID is required by systemd and cannot be changed. See ParseSliceIDRule.
+type SliceIDRule struct {
+	// Prefix is prepended to each number, including any separator, e.g. "web-".
+	Prefix string
+
+	// Suffix is appended to each number, including any separator, e.g. "-eu".
+	Suffix string
+
+	// Width is the minimum number of digits. Numbers are padded with zeros.
+	Width int
+
+	// First is the first number of the range.
+	First int
+
+	// Last is the last number of the range. Zero means IDs are random.
+	Last int
+}
+
+// sliceIDAffixExp matches the characters allowed in slice ID prefixes and
+// suffixes. Dots and @ would break parsing unit names.
+const sliceIDAffixExp = `[A-Za-z0-9_:-]*`
+
+var sliceIDRuleExp = regexp.MustCompile(`^(` + sliceIDAffixExp + `)\{([0-9]+)\.\.([0-9]+)\}(` + sliceIDAffixExp + `)$`)
+
+// ParseSliceIDRule parses a slice ID rule from a pattern like "web-{01..20}".
+// The range is given in braces. In case the first number has leading zeros,
+// numbers are padded to its length. Everything in front of and behind the
+// braces is used as prefix and suffix. An empty pattern results in random IDs.
+//
+//   {1..9}        =>  1, 2, ..., 9
+//   web-{01..20}  =>  web-01, web-02, ..., web-20
+//   {001..100}-eu =>  001-eu, 002-eu, ..., 100-eu
+//
+// In case the pattern is malformed, an error that you can identify using
+// IsInvalidRequest is returned.
+func ParseSliceIDRule(pattern string) (SliceIDRule, error) {
+	if pattern == "" {
+		return SliceIDRule{}, nil
+	}
+
+	matches := sliceIDRuleExp.FindStringSubmatch(pattern)
+	if matches == nil {
+		return SliceIDRule{}, maskAnyf(invalidArgumentError, "slice ID pattern '%s' must look like 'prefix{01..20}suffix'", pattern)
+	}
+	first, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return SliceIDRule{}, maskAnyf(invalidArgumentError, "slice ID pattern '%s': %s", pattern, err.Error())
+	}
+	last, err := strconv.Atoi(matches[3])
+	if err != nil {
+		return SliceIDRule{}, maskAnyf(invalidArgumentError, "slice ID pattern '%s': %s", pattern, err.Error())
+	}
+	if last < first || last == 0 {
+		return SliceIDRule{}, maskAnyf(invalidArgumentError, "slice ID pattern '%s' has an empty range", pattern)
+	}
+
+	rule := SliceIDRule{
+		Prefix: matches[1],
+		Suffix: matches[4],
+		First:  first,
+		Last:   last,
+	}
+	if len(matches[2]) > 1 && strings.HasPrefix(matches[2], "0") {
+		rule.Width = len(matches[2])
+	}
+
+	return rule, nil
+}
+
+// IsRandom checks whether the rule creates random IDs.
+func (r SliceIDRule) IsRandom() bool {
+	return r.Last == 0
+}
+
+// ID returns the slice ID for the given number.
+func (r SliceIDRule) ID(n int) string {
+	return fmt.Sprintf("%s%0*d%s", r.Prefix, r.Width, n, r.Suffix)
+}
+
+// String returns the pattern the rule can be parsed from.
+func (r SliceIDRule) String() string {
+	if r.IsRandom() {
+		return ""
+	}
+
+	return fmt.Sprintf("%s{%0*d..%0*d}%s", r.Prefix, r.Width, r.First, r.Width, r.Last, r.Suffix)
+}
+
+// NewIDs creates the given number of slice IDs not contained in the given
+// list of used IDs. Numbered IDs are taken from the start of the range, so
+// that gaps are filled first. In case the range does not provide enough IDs,
+// an error that you can identify using IsSliceIDsExhausted is returned.
+func (r SliceIDRule) NewIDs(count int, used []string) ([]string, error) {
+	var newIDs []string
+
+	if r.IsRandom() {
+		for len(newIDs) < count {
+			newID := NewID()
+			if contains(used, newID) || contains(newIDs, newID) {
+				continue
+			}
+			newIDs = append(newIDs, newID)
+		}
+
+		return newIDs, nil
+	}
+
+	for n := r.First; n <= r.Last && len(newIDs) < count; n++ {
+		newID := r.ID(n)
+		if contains(used, newID) {
+			continue
+		}
+		newIDs = append(newIDs, newID)
+	}
+	if len(newIDs) < count {
+		return nil, maskAnyf(sliceIDsExhaustedError, "%d slices requested, but only %d of %s are unused", count, len(newIDs), r.String())
+	}
+
+	return newIDs, nil
+}
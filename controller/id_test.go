@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestParseSliceIDRule(t *testing.T) {
+	testCases := []struct {
+		Pattern  string
+		Expected SliceIDRule
+		Error    func(error) bool
+	}{
+		{Pattern: "", Expected: SliceIDRule{}},
+		{Pattern: "{1..9}", Expected: SliceIDRule{First: 1, Last: 9}},
+		{Pattern: "web-{01..20}", Expected: SliceIDRule{Prefix: "web-", Width: 2, First: 1, Last: 20}},
+		{Pattern: "{001..100}-eu", Expected: SliceIDRule{Suffix: "-eu", Width: 3, First: 1, Last: 100}},
+		{Pattern: "{0..3}", Expected: SliceIDRule{First: 0, Last: 3}},
+		{Pattern: "web-01", Error: IsInvalidRequest},
+		{Pattern: "web.{1..3}", Error: IsInvalidRequest},
+		{Pattern: "web@{1..3}", Error: IsInvalidRequest},
+		{Pattern: "{5..3}", Error: IsInvalidRequest},
+		{Pattern: "{0..0}", Error: IsInvalidRequest},
+	}
+
+	for i, testCase := range testCases {
+		rule, err := ParseSliceIDRule(testCase.Pattern)
+		if testCase.Error != nil {
+			if !testCase.Error(err) {
+				t.Fatal("case", i+1, "expected error, got", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if !reflect.DeepEqual(rule, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", rule)
+		}
+		if rule.String() != testCase.Pattern {
+			t.Fatal("case", i+1, "expected", testCase.Pattern, "got", rule.String())
+		}
+	}
+}
+
+func TestSliceIDRule_NewIDs(t *testing.T) {
+	rule := SliceIDRule{Prefix: "web-", Width: 2, First: 1, Last: 4}
+
+	newIDs, err := rule.NewIDs(2, []string{"web-01", "web-03", "abc"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	expected := []string{"web-02", "web-04"}
+	if !reflect.DeepEqual(newIDs, expected) {
+		t.Fatal("expected", expected, "got", newIDs)
+	}
+
+	_, err = rule.NewIDs(3, []string{"web-01", "web-03"})
+	if !IsSliceIDsExhausted(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	newIDs, err = SliceIDRule{}.NewIDs(3, nil)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(newIDs) != 3 || newIDs[0] == newIDs[1] || newIDs[1] == newIDs[2] || newIDs[0] == newIDs[2] {
+		t.Fatal("expected", 3, "distinct random IDs, got", newIDs)
+	}
+}
+
+func TestController_ExtendWithRandomSliceIDs_SliceIDRule(t *testing.T) {
+	controller, dummyFleet := getTestController()
+	controller.Config.SliceIDRule = SliceIDRule{Prefix: "web-", Width: 2, First: 1, Last: 3}
+
+	err := dummyFleet.Submit(context.Background(), "foo-main@web-01.service", "main content")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "foo"},
+		Units:         []Unit{{Name: "foo-main@.service", Content: "main content"}},
+		DesiredSlices: 2,
+	}
+	req, err = controller.ExtendWithRandomSliceIDs(context.Background(), req)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	expected := []string{"web-02", "web-03"}
+	if !reflect.DeepEqual(req.SliceIDs, expected) {
+		t.Fatal("expected", expected, "got", req.SliceIDs)
+	}
+
+	req.SliceIDs = nil
+	req.DesiredSlices = 3
+	_, err = controller.ExtendWithRandomSliceIDs(context.Background(), req)
+	if !IsSliceIDsExhausted(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/state"
+	"github.com/giantswarm/inago/task"
+)
+
+const (
+	// ContextIdempotencyKey is the key for the idempotency key stored in the
+	// context.Context given to mutating operations. See WithIdempotencyKey.
+	ContextIdempotencyKey = "idempotency-key"
+
+	// idempotencyNamespace is the namespace of the state store idempotency
+	// records are stored in.
+	idempotencyNamespace = "idempotency"
+)
+
+// WithIdempotencyKey returns a copy of ctx carrying the given idempotency
+// key. Mutating operations executed using this context, e.g. Submit or
+// Update, are only applied once per key and request. Executing an operation
+// again using the same key, e.g. when CI retries a job, returns a task that
+// succeeds without changing the cluster. Only succeeded operations are
+// recorded, so failed ones can be retried using the same key. Keys are stored
+// using Config.StateStore, and are ignored in case there is no state store.
+//
+//   ctx = controller.WithIdempotencyKey(ctx, "build-1234")
+//   taskObject, err := newController.Submit(ctx, req)
+//
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, ContextIdempotencyKey, key)
+}
+
+// IdempotencyKey returns the idempotency key of the given context, or an
+// empty string in case there is none.
+func IdempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(ContextIdempotencyKey).(string)
+	return key
+}
+
+// idempotencyRecord is stored for each idempotency key. It maps operations,
+// named using idempotentOperationName, to the time they succeeded.
+type idempotencyRecord struct {
+	Operations map[string]time.Time `json:"operations"`
+}
+
+// idempotencyMutex serializes updates of idempotency records, since the
+// operations of a single key may run concurrently, e.g. for multiple groups.
+var idempotencyMutex sync.Mutex
+
+// createTask creates a task executing the given action, like
+// task.Service.Create. In case the context carries an idempotency key, the
+// operation is recorded once the action succeeded, and a recorded operation is
+// not executed again. The action is traced, see Config.Tracer.
+func (c controller) createTask(ctx context.Context, operation string, req Request, action task.Action) (*task.Task, error) {
+	action = c.tracedAction(operation, req, action)
+
+	key := IdempotencyKey(ctx)
+	if key == "" || c.Config.StateStore == nil {
+		taskObject, err := c.TaskService.Create(ctx, action)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		return taskObject, nil
+	}
+
+	name := idempotentOperationName(operation, req)
+	succeeded, ok, err := c.idempotentOperation(key, name)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if ok {
+		c.Config.Logger.Info(ctx, "controller: skipping %s, already applied using idempotency key '%s' at %s", name, key, succeeded.Format(time.RFC3339))
+		taskObject, err := c.TaskService.Create(ctx, func(ctx context.Context) error { return nil })
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		return taskObject, nil
+	}
+
+	// Operations executed as part of this one, e.g. the submit of an update,
+	// must not be recorded on their own.
+	ctx = WithIdempotencyKey(ctx, "")
+	taskObject, err := c.TaskService.Create(ctx, func(ctx context.Context) error {
+		if err := action(ctx); err != nil {
+			return maskAny(err)
+		}
+		if err := c.recordIdempotentOperation(key, name, time.Now()); err != nil {
+			// The operation succeeded. Failing to record it only means it is
+			// applied again in case it is retried.
+			c.Config.Logger.Warning(ctx, "controller: failed to record idempotency key '%s': %#v", key, maskAny(err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return taskObject, nil
+}
+
+// idempotentOperationName returns the name the given operation is recorded
+// with. Besides the group, it identifies the slices and units of the request,
+// so that different requests on the same group using the same key are all
+// applied, e.g. scaling a group up and down again.
+//
+//   submit mygroup slices=1,2 desired=0 content=1a2b3c
+//
+func idempotentOperationName(operation string, req Request) string {
+	sliceIDs := append([]string{}, req.SliceIDs...)
+	sort.Strings(sliceIDs)
+
+	return fmt.Sprintf("%s %s slices=%s desired=%d content=%s", operation, req.Group, strings.Join(sliceIDs, ","), req.DesiredSlices, ContentVersion(req.Units))
+}
+
+// idempotentOperation checks whether the given operation has been recorded
+// for the given idempotency key, and returns when it succeeded.
+func (c controller) idempotentOperation(key, name string) (time.Time, bool, error) {
+	idempotencyMutex.Lock()
+	defer idempotencyMutex.Unlock()
+
+	record, err := c.loadIdempotencyRecord(key)
+	if err != nil {
+		return time.Time{}, false, maskAny(err)
+	}
+	succeeded, ok := record.Operations[name]
+
+	return succeeded, ok, nil
+}
+
+func (c controller) recordIdempotentOperation(key, name string, succeeded time.Time) error {
+	idempotencyMutex.Lock()
+	defer idempotencyMutex.Unlock()
+
+	record, err := c.loadIdempotencyRecord(key)
+	if err != nil {
+		return maskAny(err)
+	}
+	record.Operations[name] = succeeded
+	if err := c.Config.StateStore.Set(idempotencyNamespace, key, record); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (c controller) loadIdempotencyRecord(key string) (idempotencyRecord, error) {
+	var record idempotencyRecord
+	err := c.Config.StateStore.Get(idempotencyNamespace, key, &record)
+	if state.IsInvalidKey(err) {
+		return idempotencyRecord{}, maskAnyf(invalidArgumentError, "idempotency key: %s", err.Error())
+	} else if state.IsNotFound(err) {
+		// Nothing has been applied using this key yet.
+	} else if err != nil {
+		return idempotencyRecord{}, maskAny(err)
+	}
+	if record.Operations == nil {
+		record.Operations = map[string]time.Time{}
+	}
+
+	return record, nil
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/state"
+	"github.com/giantswarm/inago/task"
+)
+
+func TestController_Submit_IdempotencyKey(t *testing.T) {
+	controller, dummyFleet := getTestController()
+	controller.Config.StateStore = state.NewMemoryStore()
+	ctx := WithIdempotencyKey(context.Background(), "build-1234")
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "foo"},
+		Units:         []Unit{{Name: "foo-main@.service", Content: "[Unit]\nDescription=main\n"}},
+		DesiredSlices: 1,
+	}
+
+	// Submitting twice using the same key must only create a single slice.
+	for i := 0; i < 2; i++ {
+		taskObject, err := controller.Submit(ctx, req)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		taskObject, err = controller.WaitForTask(ctx, taskObject.ID, nil)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		if task.HasFailedStatus(taskObject) {
+			t.Fatal("expected", task.StatusSucceeded, "got", taskObject.Error)
+		}
+	}
+	if len(dummyFleet.Units) != 1 {
+		t.Fatal("expected", 1, "got", dummyFleet.Units)
+	}
+
+	// Without key the submit is applied again.
+	taskObject, err := controller.Submit(context.Background(), req)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if _, err := controller.WaitForTask(ctx, taskObject.ID, nil); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(dummyFleet.Units) != 2 {
+		t.Fatal("expected", 2, "got", dummyFleet.Units)
+	}
+
+	_, err = controller.Submit(WithIdempotencyKey(context.Background(), "build/1234"), req)
+	if !IsInvalidRequest(err) {
+		t.Fatal("expected", "invalid request error", "got", err)
+	}
+}
+
+func TestController_Submit_IdempotencyKey_DifferentRequests(t *testing.T) {
+	controller, dummyFleet := getTestController()
+	controller.Config.StateStore = state.NewMemoryStore()
+	ctx := WithIdempotencyKey(context.Background(), "build-1234")
+
+	reqs := []Request{
+		{
+			RequestConfig: RequestConfig{Group: "foo"},
+			Units:         []Unit{{Name: "foo-main@.service", Content: "[Unit]\nDescription=main\n"}},
+			DesiredSlices: 1,
+		},
+		{
+			RequestConfig: RequestConfig{Group: "foo"},
+			Units:         []Unit{{Name: "foo-main@.service", Content: "[Unit]\nDescription=other\n"}},
+			DesiredSlices: 1,
+		},
+	}
+
+	// Different requests on the same group using the same key are all
+	// applied.
+	for _, req := range reqs {
+		taskObject, err := controller.Submit(ctx, req)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		taskObject, err = controller.WaitForTask(ctx, taskObject.ID, nil)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		if task.HasFailedStatus(taskObject) {
+			t.Fatal("expected", task.StatusSucceeded, "got", taskObject.Error)
+		}
+	}
+	if len(dummyFleet.Units) != 2 {
+		t.Fatal("expected", 2, "got", dummyFleet.Units)
+	}
+}
+
+func Test_idempotentOperationName(t *testing.T) {
+	units := []Unit{{Name: "foo-main@.service", Content: "[Unit]\nDescription=main\n"}}
+	a := idempotentOperationName("start", Request{RequestConfig: RequestConfig{Group: "foo", SliceIDs: []string{"2", "1"}}, Units: units})
+	b := idempotentOperationName("start", Request{RequestConfig: RequestConfig{Group: "foo", SliceIDs: []string{"1", "2"}}, Units: units})
+	if a != b {
+		t.Fatal("expected", a, "got", b)
+	}
+
+	others := []Request{
+		{RequestConfig: RequestConfig{Group: "bar", SliceIDs: []string{"1", "2"}}, Units: units},
+		{RequestConfig: RequestConfig{Group: "foo", SliceIDs: []string{"1"}}, Units: units},
+		{RequestConfig: RequestConfig{Group: "foo", SliceIDs: []string{"1", "2"}}, Units: units, DesiredSlices: 2},
+		{RequestConfig: RequestConfig{Group: "foo", SliceIDs: []string{"1", "2"}}},
+	}
+	for i, req := range others {
+		if name := idempotentOperationName("start", req); name == a {
+			t.Fatal("case", i+1, "expected", "different name", "got", name)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/coreos/fleet/unit"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/fleet"
+)
+
+// LabelSection is the section of unit files labels of a group are stored in.
+// Systemd ignores sections prefixed with "X-", so labels are only visible to
+// Inago.
+//
+//   [X-Inago]
+//   Group=mygroup
+//   Label=team=payments
+//
+const LabelSection = "X-Inago"
+
+var labelKeyExp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]*$`)
+
+// ParseLabels parses labels given as "key=value" pairs. In case a pair is
+// malformed, an error that you can identify using IsInvalidRequest is
+// returned.
+//
+//   [team=payments tier=backend]  =>  map[team:payments tier:backend]
+//
+func ParseLabels(pairs []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, pair := range pairs {
+		i := strings.Index(pair, "=")
+		if i < 0 {
+			return nil, maskAnyf(invalidArgumentError, "label '%s' must have the form key=value", pair)
+		}
+		key, value := pair[:i], pair[i+1:]
+		if err := validateLabel(key, value); err != nil {
+			return nil, maskAny(err)
+		}
+		labels[key] = value
+	}
+
+	return labels, nil
+}
+
+func validateLabel(key, value string) error {
+	if !labelKeyExp.MatchString(key) {
+		return maskAnyf(invalidArgumentError, "bad label key '%s'", key)
+	}
+	if strings.ContainsAny(value, " \t\n\",=") {
+		return maskAnyf(invalidArgumentError, "bad value '%s' of label '%s'", value, key)
+	}
+
+	return nil
+}
+
+// WithLabels returns a copy of r where the given labels are added to the
+// content of all units, so that they are stored together with the units in
+// the cluster. Units writing environment files are not labeled, since their
+// content is rendered for each slice. In case a label is malformed, an error
+// that you can identify using IsInvalidRequest is returned.
+func (r Request) WithLabels(labels map[string]string) (Request, error) {
+	if len(labels) == 0 {
+		return r, nil
+	}
+
+	var keys []string
+	for key, value := range labels {
+		if err := validateLabel(key, value); err != nil {
+			return Request{}, maskAny(err)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	section := fmt.Sprintf("\n[%s]\nGroup=%s\n", LabelSection, r.Group)
+	for _, key := range keys {
+		section += fmt.Sprintf("Label=%s=%s\n", key, labels[key])
+	}
+
+	var newUnits []Unit
+	for _, u := range r.Units {
+		if u.EnvFile == "" {
+			u.Content = strings.TrimRight(u.Content, "\n") + "\n" + section
+		}
+		newUnits = append(newUnits, u)
+	}
+	r.Units = newUnits
+
+	return r, nil
+}
+
+// UnitLabels returns the group and the labels stored in the given unit
+// content by Request.WithLabels. An empty group is returned for units without
+// labels.
+func UnitLabels(content string) (string, map[string]string) {
+	unitFile, err := unit.NewUnitFile(content)
+	if err != nil {
+		return "", nil
+	}
+	section := unitFile.Contents[LabelSection]
+	groups := section["Group"]
+	if len(groups) == 0 {
+		return "", nil
+	}
+
+	labels := map[string]string{}
+	for _, pair := range section["Label"] {
+		if i := strings.Index(pair, "="); i > 0 {
+			labels[pair[:i]] = pair[i+1:]
+		}
+	}
+
+	return groups[len(groups)-1], labels
+}
+
+// Selector selects groups by their labels. See ParseSelector.
+type Selector struct {
+	// Equal contains the labels groups need to have with the given value.
+	Equal map[string]string
+
+	// Exists contains the keys of labels groups need to have with any value.
+	Exists []string
+}
+
+// ParseSelector parses a comma separated list of requirements. Groups are
+// selected in case they fulfil all requirements. A requirement is either
+// "key=value", or "key" to select groups having the label at all. In case the
+// selector is malformed, an error that you can identify using
+// IsInvalidRequest is returned.
+//
+//   team=payments,tier=backend
+//   team
+//
+func ParseSelector(s string) (Selector, error) {
+	selector := Selector{Equal: map[string]string{}}
+	for _, requirement := range strings.Split(s, ",") {
+		if requirement == "" {
+			return Selector{}, maskAnyf(invalidArgumentError, "empty requirement in selector '%s'", s)
+		}
+		i := strings.Index(requirement, "=")
+		if i < 0 {
+			if err := validateLabel(requirement, ""); err != nil {
+				return Selector{}, maskAny(err)
+			}
+			selector.Exists = append(selector.Exists, requirement)
+			continue
+		}
+		key, value := requirement[:i], requirement[i+1:]
+		if err := validateLabel(key, value); err != nil {
+			return Selector{}, maskAny(err)
+		}
+		selector.Equal[key] = value
+	}
+
+	return selector, nil
+}
+
+// Matches checks whether the given labels fulfil all requirements of the
+// selector.
+func (s Selector) Matches(labels map[string]string) bool {
+	for key, value := range s.Equal {
+		if v, ok := labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	for _, key := range s.Exists {
+		if _, ok := labels[key]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c controller) GroupLabels(ctx context.Context) (map[string]map[string]string, error) {
+	c.Config.Logger.Debug(ctx, "controller: fetching labels of all groups")
+
+	unitStatusList, err := c.Fleet.GetStatusWithMatcher(func(string) bool { return true })
+	if fleet.IsUnitNotFound(err) {
+		return map[string]map[string]string{}, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	var names []string
+	for _, us := range unitStatusList {
+		names = append(names, us.Name)
+	}
+	sort.Strings(names)
+
+	groups := map[string]map[string]string{}
+	checked := map[string]struct{}{}
+	for _, name := range names {
+		// All units of a group carry the same labels, and all slices have the
+		// same units. So it is enough to look at one instance of each unit of
+		// groups not found yet.
+		if groupOfUnit(groups, name) {
+			continue
+		}
+		base := name
+		if i := strings.Index(name, "@"); i >= 0 {
+			base = name[:i+1] + common.UnitExtension(name)
+		}
+		if _, ok := checked[base]; ok {
+			continue
+		}
+		checked[base] = struct{}{}
+
+		content, err := c.Fleet.GetContent(ctx, name)
+		if fleet.IsUnitNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, maskAny(err)
+		}
+		if group, labels := UnitLabels(content); group != "" {
+			groups[group] = labels
+		}
+	}
+
+	return groups, nil
+}
+
+// groupOfUnit checks whether the unit having the given name belongs to any of
+// the given groups.
+func groupOfUnit(groups map[string]map[string]string, name string) bool {
+	for group := range groups {
+		if strings.HasPrefix(name, group+"-") {
+			return true
+		}
+	}
+
+	return false
+}
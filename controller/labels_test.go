@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_ParseLabels(t *testing.T) {
+	RegisterTestingT(t)
+
+	labels, err := ParseLabels([]string{"team=payments", "tier=backend", "empty="})
+	Expect(err).To(BeNil())
+	Expect(labels).To(Equal(map[string]string{"team": "payments", "tier": "backend", "empty": ""}))
+
+	for _, pair := range []string{"team", "=payments", "team=pay ments", "-team=payments"} {
+		_, err := ParseLabels([]string{pair})
+		Expect(IsInvalidRequest(err)).To(BeTrue())
+	}
+}
+
+func Test_Request_WithLabels(t *testing.T) {
+	RegisterTestingT(t)
+
+	envUnit, err := NewEnvUnit("app", "app-main@.env", "FOO=bar\n")
+	Expect(err).To(BeNil())
+	req := Request{
+		RequestConfig: RequestConfig{Group: "app"},
+		Units: []Unit{
+			{Name: "app-main@.service", Content: "[Service]\nExecStart=/bin/app\n"},
+			envUnit,
+		},
+	}
+
+	labeled, err := req.WithLabels(map[string]string{"tier": "backend", "team": "payments"})
+	Expect(err).To(BeNil())
+	Expect(labeled.Units[0].Content).To(Equal("[Service]\nExecStart=/bin/app\n\n[X-Inago]\nGroup=app\nLabel=team=payments\nLabel=tier=backend\n"))
+	Expect(labeled.Units[1].Content).To(Equal(envUnit.Content))
+	Expect(req.Units[0].Content).To(Equal("[Service]\nExecStart=/bin/app\n"))
+
+	group, labels := UnitLabels(labeled.Units[0].Content)
+	Expect(group).To(Equal("app"))
+	Expect(labels).To(Equal(map[string]string{"team": "payments", "tier": "backend"}))
+
+	// Labels follow the group when it is renamed.
+	group, _ = UnitLabels(labeled.WithGroup("copy").Units[0].Content)
+	Expect(group).To(Equal("copy"))
+
+	// Requests without labels are left as they are.
+	unlabeled, err := req.WithLabels(nil)
+	Expect(err).To(BeNil())
+	Expect(unlabeled).To(Equal(req))
+	group, labels = UnitLabels(unlabeled.Units[0].Content)
+	Expect(group).To(Equal(""))
+	Expect(labels).To(BeNil())
+}
+
+func Test_Selector(t *testing.T) {
+	testCases := []struct {
+		Selector string
+		Labels   map[string]string
+		Expected bool
+	}{
+		{Selector: "team=payments", Labels: map[string]string{"team": "payments", "tier": "backend"}, Expected: true},
+		{Selector: "team=payments,tier=backend", Labels: map[string]string{"team": "payments", "tier": "backend"}, Expected: true},
+		{Selector: "team=payments,tier=frontend", Labels: map[string]string{"team": "payments", "tier": "backend"}, Expected: false},
+		{Selector: "team", Labels: map[string]string{"team": "search"}, Expected: true},
+		{Selector: "team", Labels: nil, Expected: false},
+	}
+
+	for i, testCase := range testCases {
+		selector, err := ParseSelector(testCase.Selector)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if output := selector.Matches(testCase.Labels); output != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", output)
+		}
+	}
+
+	for _, s := range []string{"", "team=payments,", "team=a=b"} {
+		if _, err := ParseSelector(s); !IsInvalidRequest(err) {
+			t.Fatal("selector", s, "expected", true, "got", err)
+		}
+	}
+}
+
+func TestController_GroupLabels(t *testing.T) {
+	RegisterTestingT(t)
+
+	newController, fleetMock := givenController()
+
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(
+		[]fleet.UnitStatus{
+			{Name: "app-main@1.service", SliceID: "1"},
+			{Name: "app-main@2.service", SliceID: "2"},
+			{Name: "app-sidekick@1.service", SliceID: "1"},
+			{Name: "legacy.service"},
+		},
+		nil,
+	)
+	fleetMock.On("GetContent", "app-main@1.service").Return("[Service]\nExecStart=/bin/app\n\n[X-Inago]\nGroup=app\nLabel=team=payments\n", nil).Once()
+	fleetMock.On("GetContent", "legacy.service").Return("[Service]\nExecStart=/bin/legacy\n", nil).Once()
+
+	groups, err := newController.GroupLabels(context.Background())
+	Expect(err).To(BeNil())
+	if !reflect.DeepEqual(groups, map[string]map[string]string{"app": {"team": "payments"}}) {
+		t.Fatal("expected", "app labels", "got", groups)
+	}
+	mock.AssertExpectationsForObjects(t, fleetMock.Mock)
+}
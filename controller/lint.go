@@ -0,0 +1,377 @@
+package controller
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/coreos/fleet/unit"
+)
+
+// deprecatedFleetOptions maps options of the X-Fleet section deprecated by
+// fleet to the options replacing them.
+var deprecatedFleetOptions = map[string]string{
+	"X-ConditionMachineBootID":   "MachineID",
+	"X-ConditionMachineID":       "MachineID",
+	"X-ConditionMachineMetadata": "MachineMetadata",
+	"X-ConditionMachineOf":       "MachineOf",
+	"X-Conflicts":                "Conflicts",
+}
+
+// LintRequest returns warnings about units of the given request that are
+// valid, but probably not meant the way they are written. These are
+//
+//   scheduling constraints fleet cannot satisfy, e.g. a unit conflicting with
+//   the unit it must be scheduled with, or a global unit having MachineOf,
+//
+//   services lacking Restart=, which stay down once they fail,
+//
+//   options deprecated by fleet, e.g. X-ConditionMachineOf,
+//
+//   templated units whose slices collide when running on the same machine,
+//   see lintSliceAwareness,
+//
+//   commands referring to environment variables that are not defined, see
+//   lintEnvironment.
+//
+// Units that cannot be parsed are not checked.
+func LintRequest(req Request) []string {
+	names := map[string]bool{}
+	envFiles := map[string][]string{}
+	for _, u := range req.Units {
+		names[strings.Replace(u.Name, "@.", "@%i.", 1)] = true
+		if u.EnvFile != "" {
+			envFiles[EnvFilePath(u.EnvFile, "%i")] = envTemplateVariables(u.EnvTemplate)
+		}
+	}
+
+	var warnings []string
+	for _, u := range req.Units {
+		unitFile, err := unit.NewUnitFile(u.Content)
+		if err != nil {
+			continue
+		}
+		for _, warning := range lintConstraints(req.Group, unitFile, names) {
+			warnings = append(warnings, u.Name+": "+warning)
+		}
+		for _, warning := range lintRestart(unitFile) {
+			warnings = append(warnings, u.Name+": "+warning)
+		}
+		for _, warning := range lintDeprecated(unitFile) {
+			warnings = append(warnings, u.Name+": "+warning)
+		}
+		for _, warning := range lintSliceAwareness(u.Name, unitFile) {
+			warnings = append(warnings, u.Name+": "+warning)
+		}
+		for _, warning := range lintEnvironment(unitFile, envFiles) {
+			warnings = append(warnings, u.Name+": "+warning)
+		}
+	}
+
+	return warnings
+}
+
+// lintConstraints checks whether fleet is able to satisfy the X-Fleet
+// constraints of the given unit of the given group. The given names are the
+// names of all units of the group, having "%i" as slice ID.
+func lintConstraints(group string, unitFile *unit.UnitFile, names map[string]bool) []string {
+	fleetOptions := unitFile.Contents["X-Fleet"]
+
+	var warnings []string
+	if lastOption(fleetOptions["Global"]) == "true" {
+		for _, option := range []string{"MachineOf", "Conflicts", "MachineID"} {
+			if len(fleetOptions[option]) > 0 {
+				warnings = append(warnings, fmt.Sprintf("global units cannot be scheduled using %s", option))
+			}
+		}
+	}
+	if len(fleetOptions["MachineOf"]) > 0 && len(fleetOptions["MachineID"]) > 0 {
+		warnings = append(warnings, "MachineOf and MachineID cannot both be satisfied")
+	}
+	for _, machineOf := range fleetOptions["MachineOf"] {
+		if strings.HasPrefix(machineOf, group+"-") && !names[machineOf] {
+			warnings = append(warnings, fmt.Sprintf("MachineOf refers to %s, which is not part of the group", machineOf))
+		}
+		for _, conflict := range fleetOptions["Conflicts"] {
+			if ok, _ := path.Match(conflict, machineOf); ok {
+				warnings = append(warnings, fmt.Sprintf("conflicts with %s, which it must be scheduled with", machineOf))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// lintRestart checks whether the given service is restarted by systemd when it
+// fails. Oneshot services are expected to exit, and are not checked.
+func lintRestart(unitFile *unit.UnitFile) []string {
+	serviceOptions, ok := unitFile.Contents["Service"]
+	if !ok || lastOption(serviceOptions["Type"]) == "oneshot" {
+		return nil
+	}
+	if len(serviceOptions["Restart"]) > 0 {
+		return nil
+	}
+
+	return []string{"Restart= is missing, systemd does not restart the service when it fails"}
+}
+
+// lintDeprecated checks whether the given unit uses options deprecated by
+// fleet.
+func lintDeprecated(unitFile *unit.UnitFile) []string {
+	var options []string
+	for option := range unitFile.Contents["X-Fleet"] {
+		if _, ok := deprecatedFleetOptions[option]; ok {
+			options = append(options, option)
+		}
+	}
+	sort.Strings(options)
+
+	var warnings []string
+	for _, option := range options {
+		warnings = append(warnings, fmt.Sprintf("%s is deprecated, use %s instead", option, deprecatedFleetOptions[option]))
+	}
+
+	return warnings
+}
+
+// instanceSpecifiers are the systemd specifiers expanding to values that
+// differ per slice, e.g. "%i" expanding to the slice ID.
+var instanceSpecifiers = []string{"%i", "%I", "%n", "%N", "%f"}
+
+// dockerRunBoolFlags are the flags of "docker run" not taking a value, unless
+// given using "=".
+var dockerRunBoolFlags = map[string]bool{
+	"-d": true, "--detach": true, "-i": true, "--interactive": true, "-t": true,
+	"--tty": true, "-it": true, "-ti": true, "--rm": true, "--init": true,
+	"--privileged": true, "--read-only": true, "-P": true, "--publish-all": true,
+}
+
+// lintSliceAwareness checks whether slices of the given templated unit are
+// able to run side by side on the same machine. Docker containers named the
+// same way, or publishing the same host port, in all slices collide, unless
+// the unit conflicts with its other slices, so that fleet schedules them on
+// different machines. Names and ports using instance specifiers like "%i", or
+// environment variables, are expected to differ per slice. Each returned
+// warning suggests a fix.
+func lintSliceAwareness(name string, unitFile *unit.UnitFile) []string {
+	if !strings.Contains(name, "@.") {
+		return nil
+	}
+	other := strings.Replace(name, "@.", "@0.", 1)
+	for _, conflict := range unitFile.Contents["X-Fleet"]["Conflicts"] {
+		if ok, _ := path.Match(conflict, other); ok {
+			return nil
+		}
+	}
+
+	var warnings []string
+	for _, option := range []string{"ExecStartPre", "ExecStart"} {
+		for _, value := range unitFile.Contents["Service"][option] {
+			names, ports := dockerRunCollisions(value)
+			for _, n := range names {
+				warnings = append(warnings, fmt.Sprintf("container name '%s' is the same for all slices, use e.g. --name %s-%%i", n, n))
+			}
+			for _, p := range ports {
+				warnings = append(warnings, fmt.Sprintf("host port %s is published by all slices, derive it from %%i or add Conflicts=%s to [X-Fleet] to run slices on different machines", p, strings.Replace(name, "@.", "@*.", 1)))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// dockerRunCollisions returns the container names and host ports given to
+// "docker run" by the given command line, which are the same for all slices.
+func dockerRunCollisions(command string) ([]string, []string) {
+	fields := strings.Fields(command)
+	start := -1
+	for i := 0; i+1 < len(fields); i++ {
+		if path.Base(strings.TrimLeft(fields[i], "-@+!:")) == "docker" && fields[i+1] == "run" {
+			start = i + 2
+			break
+		}
+	}
+	if start < 0 {
+		return nil, nil
+	}
+
+	var names, ports []string
+	for i := start; i < len(fields); i++ {
+		flag := fields[i]
+		// The first argument not being a flag is the image. Arguments following
+		// it are passed to the container.
+		if !strings.HasPrefix(flag, "-") {
+			break
+		}
+
+		value := ""
+		if j := strings.Index(flag, "="); j > 0 {
+			flag, value = flag[:j], flag[j+1:]
+		} else if strings.HasPrefix(flag, "-p") && len(flag) > 2 {
+			flag, value = "-p", flag[2:]
+		} else if !dockerRunBoolFlags[flag] && i+1 < len(fields) {
+			i++
+			value = fields[i]
+		}
+		if sliceSpecific(value) {
+			continue
+		}
+
+		switch flag {
+		case "--name":
+			names = append(names, value)
+		case "-p", "--publish":
+			spec := strings.Split(strings.Split(value, "/")[0], ":")
+			if len(spec) > 1 && spec[len(spec)-2] != "" {
+				ports = append(ports, spec[len(spec)-2])
+			}
+		}
+	}
+
+	return names, ports
+}
+
+// sliceSpecific checks whether the given value differs per slice, because it
+// uses an instance specifier or an environment variable.
+func sliceSpecific(value string) bool {
+	if strings.Contains(value, "$") {
+		return true
+	}
+	for _, specifier := range instanceSpecifiers {
+		if strings.Contains(value, specifier) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// knownEnvFiles are environment files provided by CoreOS, and the variables
+// they define.
+var knownEnvFiles = map[string][]string{
+	"/etc/environment": {"COREOS_PUBLIC_IPV4", "COREOS_PRIVATE_IPV4"},
+}
+
+// systemdVariables are the variables systemd defines for the commands of a
+// service itself, e.g. $MAINPID for ExecReload.
+var systemdVariables = []string{"MAINPID", "SERVICE_RESULT", "EXIT_CODE", "EXIT_STATUS"}
+
+var (
+	// bracedVariableExp matches variables systemd expands anywhere in a command
+	// line, e.g. "--name=${NAME}".
+	bracedVariableExp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+	// wordVariableExp matches variables systemd expands in case they are a
+	// separate word of a command line, e.g. "$IMAGE".
+	wordVariableExp = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)$`)
+
+	// envAssignmentExp matches the variable assigned by a line of an
+	// environment file.
+	envAssignmentExp = regexp.MustCompile(`^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)=`)
+)
+
+// lintEnvironment checks whether the variables the commands of the given
+// service refer to are defined using Environment=, or in the environment files
+// of EnvironmentFile=. The given environment files are the ones of the group,
+// keyed by the path they are written to using "%i" as slice ID, see
+// EnvFilePath. Units reading environment files not known to Inago are not
+// checked, since the variables could be defined there. Systemd expands
+// variables at runtime on the machine, and variables not defined expand to
+// empty strings.
+//
+//   Environment="IMAGE=myapp"
+//   ExecStart=/usr/bin/docker run --rm $IMAGE:${TAG}  =>  TAG is not defined
+//
+func lintEnvironment(unitFile *unit.UnitFile, envFiles map[string][]string) []string {
+	service := unitFile.Contents["Service"]
+
+	defined := map[string]bool{}
+	for _, name := range systemdVariables {
+		defined[name] = true
+	}
+	for _, value := range service["Environment"] {
+		for _, assignment := range strings.Fields(value) {
+			assignment = strings.Trim(assignment, `"'`)
+			if i := strings.Index(assignment, "="); i > 0 {
+				defined[assignment[:i]] = true
+			}
+		}
+	}
+	for _, value := range service["EnvironmentFile"] {
+		// A leading "-" makes systemd ignore missing files.
+		envFile := strings.TrimPrefix(value, "-")
+		variables, ok := envFiles[envFile]
+		if !ok {
+			variables, ok = knownEnvFiles[envFile]
+		}
+		if !ok {
+			return nil
+		}
+		for _, name := range variables {
+			defined[name] = true
+		}
+	}
+
+	var options []string
+	for option := range service {
+		if strings.HasPrefix(option, "Exec") {
+			options = append(options, option)
+		}
+	}
+	sort.Strings(options)
+
+	var warnings []string
+	reported := map[string]bool{}
+	for _, option := range options {
+		for _, value := range service[option] {
+			// "$$" is a literal "$", e.g. for variables of a shell.
+			value = strings.Replace(value, "$$", "", -1)
+
+			var names []string
+			for _, match := range bracedVariableExp.FindAllStringSubmatch(value, -1) {
+				names = append(names, match[1])
+			}
+			for _, field := range strings.Fields(value) {
+				if match := wordVariableExp.FindStringSubmatch(field); match != nil {
+					names = append(names, match[1])
+				}
+			}
+
+			for _, name := range names {
+				if defined[name] || reported[name] {
+					continue
+				}
+				reported[name] = true
+				warnings = append(warnings, fmt.Sprintf("%s refers to $%s, which is not defined using Environment= or an environment file of the group", option, name))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// envTemplateVariables returns the variables assigned by the given environment
+// file template. Lines produced by template actions are not considered.
+func envTemplateVariables(envTemplate string) []string {
+	var names []string
+	for _, line := range strings.Split(envTemplate, "\n") {
+		if match := envAssignmentExp.FindStringSubmatch(line); match != nil {
+			names = append(names, match[1])
+		}
+	}
+
+	return names
+}
+
+// lastOption returns the value of the given option that takes effect, i.e. the
+// last one, or an empty string in case there is none.
+func lastOption(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[len(values)-1]
+}
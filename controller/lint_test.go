@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/coreos/fleet/unit"
+)
+
+func TestLintRequest(t *testing.T) {
+	req := Request{
+		RequestConfig: RequestConfig{Group: "foo"},
+		Units: []Unit{
+			{Name: "foo-main@.service", Content: "[Service]\nExecStart=/bin/main\nRestart=always\n"},
+			{Name: "foo-sidekick@.service", Content: "[Service]\nExecStart=/bin/sidekick\n\n[X-Fleet]\nMachineOf=foo-main@%i.service\nConflicts=foo-*\n"},
+			{Name: "foo-setup@.service", Content: "[Service]\nType=oneshot\nExecStart=/bin/setup\n\n[X-Fleet]\nX-ConditionMachineOf=foo-main@%i.service\nMachineOf=foo-missing@%i.service\n"},
+			{Name: "foo-agent@.service", Content: "[Service]\nExecStart=/bin/agent\nRestart=always\n\n[X-Fleet]\nGlobal=true\nMachineOf=foo-main@%i.service\n"},
+		},
+	}
+
+	expected := []string{
+		"foo-sidekick@.service: conflicts with foo-main@%i.service, which it must be scheduled with",
+		"foo-sidekick@.service: Restart= is missing, systemd does not restart the service when it fails",
+		"foo-setup@.service: MachineOf refers to foo-missing@%i.service, which is not part of the group",
+		"foo-setup@.service: X-ConditionMachineOf is deprecated, use MachineOf instead",
+		"foo-agent@.service: global units cannot be scheduled using MachineOf",
+	}
+	if warnings := LintRequest(req); !reflect.DeepEqual(warnings, expected) {
+		t.Fatal("expected", expected, "got", warnings)
+	}
+
+	req.Units = req.Units[:1]
+	if warnings := LintRequest(req); len(warnings) != 0 {
+		t.Fatal("expected", 0, "got", warnings)
+	}
+}
+
+func TestLintSliceAwareness(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Content  string
+		Expected []string
+	}{
+		// Container names and host ports the same for all slices collide.
+		{
+			Name:    "web-main@.service",
+			Content: "[Service]\nExecStartPre=-/usr/bin/docker rm web\nExecStart=/usr/bin/docker run --rm -e MODE=prod --name web -p 8080:80 -p 443 nginx -p 1\n",
+			Expected: []string{
+				"container name 'web' is the same for all slices, use e.g. --name web-%i",
+				"host port 8080 is published by all slices, derive it from %i or add Conflicts=web-main@*.service to [X-Fleet] to run slices on different machines",
+			},
+		},
+		// Names and ports differing per slice do not collide.
+		{
+			Name:     "web-main@.service",
+			Content:  "[Service]\nExecStart=/usr/bin/docker run --name=web-%i --publish ${PORT}:80 -p 10.0.0.1::80/udp nginx\n",
+			Expected: nil,
+		},
+		// Slices conflicting with each other run on different machines.
+		{
+			Name:     "web-main@.service",
+			Content:  "[Service]\nExecStart=/usr/bin/docker run --name web -p8080:80 nginx\n\n[X-Fleet]\nConflicts=web-main@*.service\n",
+			Expected: nil,
+		},
+		// Units without slices are not checked.
+		{
+			Name:     "web-main.service",
+			Content:  "[Service]\nExecStart=/usr/bin/docker run --name web nginx\n",
+			Expected: nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		unitFile, err := unit.NewUnitFile(testCase.Content)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if warnings := lintSliceAwareness(testCase.Name, unitFile); !reflect.DeepEqual(warnings, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", warnings)
+		}
+	}
+}
+
+func TestLintEnvironment(t *testing.T) {
+	envFiles := map[string][]string{
+		"/run/inago/foo-main@%i.env": envTemplateVariables("# Settings\nexport DB_HOST=db-{{.SliceID}}\nDB_PORT=5432\n{{if .SliceID}}EXTRA=1{{end}}\n"),
+	}
+
+	testCases := []struct {
+		Content  string
+		Expected []string
+	}{
+		// Variables defined using Environment= are expanded.
+		{
+			Content:  "[Service]\nEnvironment=\"IMAGE=myapp\" TAG=1.0\nExecStart=/usr/bin/docker run --rm $IMAGE:${TAG}\n",
+			Expected: nil,
+		},
+		{
+			Content: "[Service]\nEnvironment=IMAGE=myapp\nExecStartPre=/usr/bin/docker pull $IMAGE:${TAG}\nExecStart=/usr/bin/docker run --rm --name ${NAME} $IMAGE:${TAG}\n",
+			Expected: []string{
+				"ExecStart refers to $NAME, which is not defined using Environment= or an environment file of the group",
+				"ExecStart refers to $TAG, which is not defined using Environment= or an environment file of the group",
+			},
+		},
+		// Variables of environment files of the group are known.
+		{
+			Content:  "[Service]\nEnvironmentFile=/run/inago/foo-main@%i.env\nExecStart=/bin/main --db ${DB_HOST}:${DB_PORT}\n",
+			Expected: nil,
+		},
+		{
+			Content:  "[Service]\nEnvironmentFile=-/etc/environment\nExecStart=/bin/main --db ${DB_HOST} --ip ${COREOS_PRIVATE_IPV4}\n",
+			Expected: []string{"ExecStart refers to $DB_HOST, which is not defined using Environment= or an environment file of the group"},
+		},
+		// Other environment files could define anything.
+		{
+			Content:  "[Service]\nEnvironmentFile=/etc/myapp.env\nExecStart=/bin/main ${ANYTHING}\n",
+			Expected: nil,
+		},
+		// Variables of shells, variables not being a separate word, and the ones
+		// of systemd are not expanded using the environment.
+		{
+			Content:  "[Service]\nExecStart=/bin/sh -c 'echo $${HOME} $$USER'\nExecStop=/bin/echo -e FOO=$FOO\nExecReload=/bin/kill -HUP $MAINPID\n",
+			Expected: nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		unitFile, err := unit.NewUnitFile(testCase.Content)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if warnings := lintEnvironment(unitFile, envFiles); !reflect.DeepEqual(warnings, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", warnings)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/fleet"
+)
+
+// ParseUnitMatch parses a regular expression selecting units by their names,
+// e.g. to manage units created before following Inago's naming conventions.
+// In case the expression is malformed, an error that you can identify using
+// IsInvalidRequest is returned. See Request.Match.
+//
+//   ^legacy-.*@\d+\.service$
+//
+func ParseUnitMatch(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, maskAnyf(invalidArgumentError, "empty unit match")
+	}
+	match, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, maskAnyf(invalidArgumentError, "bad unit match '%s': %s", expr, err.Error())
+	}
+
+	return match, nil
+}
+
+// matchesUnitMatch returns a matcher compatible with
+// fleet.GetStatusWithMatcher that matches the units selected by the given
+// request's Match. In case slice IDs are given, units of other slices do not
+// match.
+func matchesUnitMatch(request Request) func(string) bool {
+	return func(unitName string) bool {
+		if !request.Match.MatchString(unitName) {
+			return false
+		}
+		if len(request.SliceIDs) == 0 {
+			return true
+		}
+		sliceID, err := common.SliceID(unitName)
+		if err != nil {
+			return false
+		}
+
+		return contains(request.SliceIDs, sliceID)
+	}
+}
+
+// matchedGroups returns the names of all groups the given units may belong
+// to. Unit names only tell that they are prefixed by their group, e.g.
+// "api-v2-web@1.service" may belong to the groups "api", "api-v2" or
+// "api-v2-web", so all of them are returned. This is used to check the policy
+// against units selected using Request.Match, which are not known to belong
+// to a single group.
+func matchedGroups(usl []fleet.UnitStatus) []string {
+	var groups []string
+	for _, us := range usl {
+		base := common.UnitBase(us.Name)
+		for i := 0; i <= len(base); i++ {
+			if i < len(base) && base[i] != '-' {
+				continue
+			}
+			if group := base[:i]; group != "" && !contains(groups, group) {
+				groups = append(groups, group)
+			}
+		}
+	}
+	sort.Strings(groups)
+
+	return groups
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/policy"
+)
+
+func TestParseUnitMatch(t *testing.T) {
+	RegisterTestingT(t)
+
+	match, err := ParseUnitMatch(`^legacy-.*@\d+\.service$`)
+	Expect(err).To(BeNil())
+	Expect(match.MatchString("legacy-db@1.service")).To(BeTrue())
+	Expect(match.MatchString("legacy-db@a.service")).To(BeFalse())
+
+	_, err = ParseUnitMatch("")
+	Expect(IsInvalidRequest(err)).To(BeTrue())
+	_, err = ParseUnitMatch("legacy-(")
+	Expect(IsInvalidRequest(err)).To(BeTrue())
+}
+
+func TestMatchedGroups(t *testing.T) {
+	RegisterTestingT(t)
+
+	groups := matchedGroups([]fleet.UnitStatus{
+		{Name: "api-v2-web@1.service"},
+		{Name: "api-v2-web@2.service"},
+		{Name: "legacy.service"},
+	})
+	Expect(groups).To(Equal([]string{"api", "api-v2", "api-v2-web", "legacy"}))
+}
+
+func TestController_Match(t *testing.T) {
+	RegisterTestingT(t)
+
+	c, recordingFleet := givenSimulatedController(t)
+	ctx := context.Background()
+	for _, name := range []string{"legacy-db@1.service", "legacy-db@2.service", "legacy-cache.service", "prod-api@1.service"} {
+		Expect(recordingFleet.Submit(ctx, name, "[Service]\nExecStart=/bin/true\n")).To(BeNil())
+	}
+
+	match, err := ParseUnitMatch(`^legacy-.*@\d+\.service$`)
+	Expect(err).To(BeNil())
+	req := Request{RequestConfig: RequestConfig{Group: match.String()}, Match: match}
+
+	usl, err := c.GetStatus(ctx, req)
+	Expect(err).To(BeNil())
+	Expect(unitNames(usl)).To(ConsistOf("legacy-db@1.service", "legacy-db@2.service"))
+
+	sliceReq := req
+	sliceReq.SliceIDs = []string{"2"}
+	usl, err = c.GetStatus(ctx, sliceReq)
+	Expect(err).To(BeNil())
+	Expect(unitNames(usl)).To(ConsistOf("legacy-db@2.service"))
+
+	extended, err := c.ExtendWithExistingSliceIDs(req)
+	Expect(err).To(BeNil())
+	Expect(extended.SliceIDs).To(ConsistOf("1", "2"))
+
+	// Units are destroyed in case the policy allows it for all groups they may
+	// belong to.
+	c.Config.Policy, err = policy.Parse([]byte(`{"rules": [{"group": "prod-*", "forbid": ["destroy"]}]}`))
+	Expect(err).To(BeNil())
+	prodMatch, err := ParseUnitMatch(`^prod-`)
+	Expect(err).To(BeNil())
+	_, err = c.Destroy(ctx, Request{RequestConfig: RequestConfig{Group: prodMatch.String()}, Match: prodMatch})
+	Expect(IsPolicyViolation(err)).To(BeTrue())
+
+	Expect(c.executeTaskAction(c.Destroy, ctx, req)).To(BeNil())
+	usl, err = recordingFleet.GetStatusWithMatcher(func(string) bool { return true })
+	Expect(err).To(BeNil())
+	Expect(unitNames(usl)).To(ConsistOf("legacy-cache.service", "prod-api@1.service"))
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// GroupResult describes the outcome of an action executed for a single group
+// by RunGroups.
+type GroupResult struct {
+	// Group is the name of the group the action was executed for.
+	Group string
+
+	// Err is the error returned by the action. It is nil in case the action
+	// succeeded.
+	Err error
+
+	// Duration is the time the action took.
+	Duration time.Duration
+}
+
+// RunGroups executes the given action for each of the given groups. At most
+// parallelism actions are executed at the same time. A parallelism lower than
+// one executes one action at a time. Failing actions do not stop other
+// actions. The results are returned in the order of the given groups.
+//
+//   results := controller.RunGroups(ctx, []string{"api", "worker"}, 2, func(ctx context.Context, group string) error {
+//     ...
+//   })
+//
+func RunGroups(ctx context.Context, groups []string, parallelism int, action func(ctx context.Context, group string) error) []GroupResult {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	results := make([]GroupResult, len(groups))
+	semaphore := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i, group := range groups {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, group string) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			start := time.Now()
+			err := action(ctx, group)
+			results[i] = GroupResult{
+				Group:    group,
+				Err:      err,
+				Duration: time.Since(start),
+			}
+		}(i, group)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/juju/errgo"
+	"golang.org/x/net/context"
+)
+
+func TestRunGroups(t *testing.T) {
+	groups := []string{"api", "worker", "cron", "db", "cache"}
+	failing := errgo.New("failing")
+
+	var mutex sync.Mutex
+	var running, maxRunning int
+	action := func(ctx context.Context, group string) error {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		defer func() {
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+		}()
+
+		if group == "cron" {
+			return failing
+		}
+		return nil
+	}
+
+	results := RunGroups(context.Background(), groups, 2, action)
+
+	if len(results) != len(groups) {
+		t.Fatal("expected", len(groups), "got", len(results))
+	}
+	for i, result := range results {
+		if result.Group != groups[i] {
+			t.Fatal("expected", groups[i], "got", result.Group)
+		}
+		if expected := result.Group == "cron"; (result.Err != nil) != expected {
+			t.Fatal("group", result.Group, "expected error", expected, "got", result.Err)
+		}
+	}
+	if maxRunning > 2 {
+		t.Fatal("expected", 2, "got", maxRunning)
+	}
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/coreos/fleet/unit"
+)
+
+// NormalizeUnitContent returns the canonical form of the given unit file
+// content, so that semantically identical unit files can be compared. Line
+// continuations and whitespace around keys and values are resolved, runs of
+// whitespace are collapsed, sections and options are ordered by name and
+// options repeated with the same value are only kept once. The order of options
+// having the same name is kept, since it is significant for directives like
+// ExecStartPre. For the same reason repeated Exec* directives are always kept.
+// In case the content cannot be parsed, an error that you can identify using
+// IsInvalidUnitContent is returned.
+//
+//   [Service]                          [Service]
+//   ExecStart=/bin/foo \         =>    Environment=A=1
+//     --bar                            ExecStart=/bin/foo --bar
+//   Environment=A=1
+//   Environment = A=1
+//
+func NormalizeUnitContent(content string) (string, error) {
+	unitFile, err := unit.NewUnitFile(content)
+	if err != nil {
+		return "", maskAnyf(invalidUnitContentError, "%s", err.Error())
+	}
+	if len(unitFile.Options) == 0 && strings.TrimSpace(content) != "" {
+		// Lines outside of sections are silently dropped when parsing.
+		return "", maskAnyf(invalidUnitContentError, "no options found")
+	}
+
+	var options []unitOption
+	for _, o := range unitFile.Options {
+		options = append(options, unitOption{Section: o.Section, Name: o.Name, Value: collapseSpace(o.Value)})
+	}
+	sort.Stable(unitOptionsBySectionAndName(options))
+
+	var lines []string
+	seen := map[unitOption]struct{}{}
+	for i, o := range options {
+		if !strings.HasPrefix(o.Name, "Exec") {
+			if _, ok := seen[o]; ok {
+				continue
+			}
+			seen[o] = struct{}{}
+		}
+
+		if i == 0 || options[i-1].Section != o.Section {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, "["+o.Section+"]")
+		}
+		lines = append(lines, o.Name+"="+o.Value)
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// collapseSpace trims the given value and replaces runs of whitespace outside
+// of quotes with a single space. Systemd splits command lines the same way, so
+// this does not change the meaning of values, but makes values spread over
+// multiple lines comparable to values written on a single line.
+func collapseSpace(value string) string {
+	var result []rune
+	var quote rune
+	for _, r := range strings.TrimSpace(value) {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case unicode.IsSpace(r):
+			if len(result) > 0 && result[len(result)-1] == ' ' {
+				continue
+			}
+			r = ' '
+		}
+		result = append(result, r)
+	}
+
+	return string(result)
+}
+
+// UnitContentEqual checks whether the given unit file contents are
+// semantically identical. See NormalizeUnitContent. Contents that cannot be
+// parsed are compared as they are.
+func UnitContentEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	na, err := NormalizeUnitContent(a)
+	if err != nil {
+		return false
+	}
+	nb, err := NormalizeUnitContent(b)
+	if err != nil {
+		return false
+	}
+
+	return na == nb
+}
+
+type unitOption struct {
+	Section string
+	Name    string
+	Value   string
+}
+
+type unitOptionsBySectionAndName []unitOption
+
+func (u unitOptionsBySectionAndName) Len() int      { return len(u) }
+func (u unitOptionsBySectionAndName) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u unitOptionsBySectionAndName) Less(i, j int) bool {
+	if u[i].Section != u[j].Section {
+		return u[i].Section < u[j].Section
+	}
+	return u[i].Name < u[j].Name
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"testing"
+)
+
+func Test_NormalizeUnitContent(t *testing.T) {
+	testCases := []struct {
+		Comment  string
+		Content  string
+		Expected string
+	}{
+		{
+			Comment:  "normalized content is kept",
+			Content:  "[Service]\nExecStart=/bin/foo\n\n[Unit]\nDescription=foo\n",
+			Expected: "[Service]\nExecStart=/bin/foo\n\n[Unit]\nDescription=foo\n",
+		},
+		{
+			Comment:  "sections and options are ordered",
+			Content:  "[Unit]\nDescription=foo\nAfter=docker.service\n\n[Service]\nExecStart=/bin/foo\n",
+			Expected: "[Service]\nExecStart=/bin/foo\n\n[Unit]\nAfter=docker.service\nDescription=foo\n",
+		},
+		{
+			Comment:  "whitespace and line continuations are resolved",
+			Content:  "\n[Unit]\n  Description =  foo  \n\n\n[Service]\nExecStart=/bin/foo \\\n  --bar\n",
+			Expected: "[Service]\nExecStart=/bin/foo --bar\n\n[Unit]\nDescription=foo\n",
+		},
+		{
+			Comment:  "whitespace within quotes is kept",
+			Content:  "[Service]\nExecStart=/bin/sh -c  'echo  foo'\n",
+			Expected: "[Service]\nExecStart=/bin/sh -c 'echo  foo'\n",
+		},
+		{
+			Comment:  "duplicate options are removed",
+			Content:  "[Service]\nEnvironment=A=1\nEnvironment=B=2\nEnvironment=A=1\nExecStart=/bin/foo\n",
+			Expected: "[Service]\nEnvironment=A=1\nEnvironment=B=2\nExecStart=/bin/foo\n",
+		},
+		{
+			Comment:  "order and duplicates of exec directives are kept",
+			Content:  "[Service]\nExecStartPre=/bin/b\nExecStartPre=/bin/a\nExecStartPre=/bin/b\nExecStart=/bin/foo\n",
+			Expected: "[Service]\nExecStart=/bin/foo\nExecStartPre=/bin/b\nExecStartPre=/bin/a\nExecStartPre=/bin/b\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		output, err := NormalizeUnitContent(testCase.Content)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %#v", testCase.Comment, err)
+		}
+		if output != testCase.Expected {
+			t.Fatalf("%s: expected:\n%s\ngot:\n%s", testCase.Comment, testCase.Expected, output)
+		}
+	}
+}
+
+func Test_UnitContentEqual(t *testing.T) {
+	a := "[Unit]\nDescription=foo\n\n[Service]\nExecStart=/bin/foo\n"
+	b := "[Service]\nExecStart = /bin/foo\n[Unit]\nDescription=foo\n"
+	c := "[Service]\nExecStart=/bin/bar\n[Unit]\nDescription=foo\n"
+
+	if !UnitContentEqual(a, b) {
+		t.Fatal("expected", true, "got", false)
+	}
+	if UnitContentEqual(a, c) {
+		t.Fatal("expected", false, "got", true)
+	}
+}
+
+func Test_NormalizeUnitContent_Invalid(t *testing.T) {
+	for _, content := range []string{"no unit file", "[Unit\nDescription=foo\n"} {
+		if _, err := NormalizeUnitContent(content); !IsInvalidUnitContent(err) {
+			t.Fatal("expected", true, "got", false)
+		}
+	}
+}
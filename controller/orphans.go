@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/fleet"
+)
+
+// OrphanUnit is a unit deployed to the cluster that does not belong to any
+// known group, e.g. a leftover of a failed deploy or of manual fleetctl usage.
+// See OrphanUnits.
+type OrphanUnit struct {
+	fleet.UnitStatus
+
+	// Group is the group the unit is named after, i.e. the part of its name up
+	// to the first dash. It is empty in case the name does not follow the
+	// naming pattern of groups, e.g. "etcd.service".
+	Group string
+}
+
+func (c controller) OrphanUnits(ctx context.Context, groups []string) ([]OrphanUnit, error) {
+	c.Config.Logger.Debug(ctx, "controller: fetching units not belonging to any known group")
+
+	labels, err := c.GroupLabels(ctx)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	known := append([]string{}, groups...)
+	for group := range labels {
+		known = append(known, group)
+	}
+
+	unitStatusList, err := c.Fleet.GetStatusWithMatcher(func(string) bool { return true })
+	if fleet.IsUnitNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	var orphans []OrphanUnit
+	for _, us := range unitStatusList {
+		if belongsToGroup(known, us.Name) {
+			continue
+		}
+		orphans = append(orphans, OrphanUnit{UnitStatus: us, Group: namedGroup(us.Name)})
+	}
+	sort.Sort(orphanUnitsByName(orphans))
+
+	return orphans, nil
+}
+
+func belongsToGroup(groups []string, name string) bool {
+	for _, group := range groups {
+		if strings.HasPrefix(name, group+"-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// namedGroup returns the group the unit having the given name is named after.
+// Units of groups are named "<group>-<name>.<ext>", or "<group>-<name>@<slice
+// ID>.<ext>" for groups having slices. An empty string is returned in case the
+// name does not follow this pattern.
+func namedGroup(name string) string {
+	base := strings.TrimSuffix(name, common.UnitExtension(name))
+	if i := strings.Index(base, "@"); i >= 0 {
+		base = base[:i]
+	}
+	i := strings.Index(base, "-")
+	if i <= 0 || i == len(base)-1 {
+		return ""
+	}
+
+	return base[:i]
+}
+
+type orphanUnitsByName []OrphanUnit
+
+func (o orphanUnitsByName) Len() int           { return len(o) }
+func (o orphanUnitsByName) Swap(i, j int)      { o[i], o[j] = o[j], o[i] }
+func (o orphanUnitsByName) Less(i, j int) bool { return o[i].Name < o[j].Name }
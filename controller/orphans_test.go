@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func TestController_OrphanUnits(t *testing.T) {
+	RegisterTestingT(t)
+
+	newController, fleetMock := givenController()
+
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(
+		[]fleet.UnitStatus{
+			{Name: "app-main@1.service", SliceID: "1"},
+			{Name: "local-main@1.service", SliceID: "1"},
+			{Name: "old-main@1.service", SliceID: "1"},
+			{Name: "etcd.service"},
+		},
+		nil,
+	)
+	fleetMock.On("GetContent", "app-main@1.service").Return("[Service]\nExecStart=/bin/app\n\n[X-Inago]\nGroup=app\nLabel=team=payments\n", nil)
+	fleetMock.On("GetContent", "local-main@1.service").Return("[Service]\nExecStart=/bin/local\n", nil)
+	fleetMock.On("GetContent", "old-main@1.service").Return("[Service]\nExecStart=/bin/old\n", nil)
+	fleetMock.On("GetContent", "etcd.service").Return("[Service]\nExecStart=/bin/etcd\n", nil)
+
+	orphans, err := newController.OrphanUnits(context.Background(), []string{"local"})
+	Expect(err).To(BeNil())
+	Expect(orphans).To(HaveLen(2))
+	Expect(orphans[0].Name).To(Equal("etcd.service"))
+	Expect(orphans[0].Group).To(Equal(""))
+	Expect(orphans[1].Name).To(Equal("old-main@1.service"))
+	Expect(orphans[1].Group).To(Equal("old"))
+}
+
+func Test_namedGroup(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []struct {
+		Name     string
+		Expected string
+	}{
+		{Name: "app-main@1.service", Expected: "app"},
+		{Name: "api-v2-web.service", Expected: "api"},
+		{Name: "etcd.service", Expected: ""},
+		{Name: "-main.service", Expected: ""},
+		{Name: "app-.service", Expected: ""},
+	}
+
+	for i, testCase := range testCases {
+		Expect(namedGroup(testCase.Name)).To(Equal(testCase.Expected), "test case %d", i+1)
+	}
+}
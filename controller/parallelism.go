@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"sort"
+	"sync"
+
+	"golang.org/x/net/context"
+)
+
+// sliceMachines returns the IDs of the machines the slices of the given
+// request are scheduled on, keyed by slice ID. Slices not scheduled yet are
+// not contained.
+func (c controller) sliceMachines(ctx context.Context, req Request) (map[string][]string, error) {
+	unitStatusList, err := c.groupStatus(ctx, req)
+	if IsUnitNotFound(err) {
+		return map[string][]string{}, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	machines := map[string][]string{}
+	for _, us := range unitStatusList {
+		for _, ms := range us.Machine {
+			if ms.ID == "" || contains(machines[us.SliceID], ms.ID) {
+				continue
+			}
+			machines[us.SliceID] = append(machines[us.SliceID], ms.ID)
+		}
+	}
+	for sliceID := range machines {
+		sort.Strings(machines[sliceID])
+	}
+
+	return machines, nil
+}
+
+// orderByMachine orders the given slice IDs so that consecutive slices run
+// on different machines, as far as possible. Slices are taken from each
+// machine in turn, keeping their original order per machine. This way the
+// update does not wait for the slots of a single machine while others are
+// idle. Slices are assigned to the first machine they run on.
+//
+//   a1 a2 b1 c1 b2  =>  a1 b1 c1 a2 b2
+//
+func orderByMachine(sliceIDs []string, machines map[string][]string) []string {
+	var machineIDs []string
+	byMachine := map[string][]string{}
+	for _, sliceID := range sliceIDs {
+		machineID := ""
+		if ids := machines[sliceID]; len(ids) > 0 {
+			machineID = ids[0]
+		}
+		if _, ok := byMachine[machineID]; !ok {
+			machineIDs = append(machineIDs, machineID)
+		}
+		byMachine[machineID] = append(byMachine[machineID], sliceID)
+	}
+
+	var ordered []string
+	for len(ordered) < len(sliceIDs) {
+		for _, machineID := range machineIDs {
+			if len(byMachine[machineID]) == 0 {
+				continue
+			}
+			ordered = append(ordered, byMachine[machineID][0])
+			byMachine[machineID] = byMachine[machineID][1:]
+		}
+	}
+
+	return ordered
+}
+
+// machineSlots limits the number of slices being cycled at the same time on
+// each machine. See UpdateOptions.MaxPerMachine.
+type machineSlots struct {
+	mutex      sync.Mutex
+	max        int
+	inProgress map[string]int
+}
+
+func newMachineSlots(max int) *machineSlots {
+	return &machineSlots{
+		max:        max,
+		inProgress: map[string]int{},
+	}
+}
+
+// acquire takes a slot on each of the given machines. In case any machine has
+// no free slot, no slot is taken and false is returned. Without limit, slots
+// are always available.
+func (s *machineSlots) acquire(machineIDs []string) bool {
+	if s.max <= 0 {
+		return true
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, machineID := range machineIDs {
+		if s.inProgress[machineID] >= s.max {
+			return false
+		}
+	}
+	for _, machineID := range machineIDs {
+		s.inProgress[machineID]++
+	}
+
+	return true
+}
+
+// release frees the slots taken using acquire.
+func (s *machineSlots) release(machineIDs []string) {
+	if s.max <= 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, machineID := range machineIDs {
+		s.inProgress[machineID]--
+	}
+}
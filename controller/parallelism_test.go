@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_Parallelism_sliceMachines(t *testing.T) {
+	RegisterTestingT(t)
+
+	newController, fleetMock := givenController()
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(
+		[]fleet.UnitStatus{
+			{Name: "test-main@1.service", SliceID: "1", Machine: []fleet.MachineStatus{{ID: "m2"}}},
+			{Name: "test-side@1.service", SliceID: "1", Machine: []fleet.MachineStatus{{ID: "m1"}}},
+			{Name: "test-main@2.service", SliceID: "2", Machine: []fleet.MachineStatus{{ID: "m2"}}},
+			{Name: "test-side@2.service", SliceID: "2", Machine: []fleet.MachineStatus{{ID: "m2"}}},
+			{Name: "test-main@3.service", SliceID: "3", Machine: []fleet.MachineStatus{{}}},
+		},
+		nil,
+	)
+
+	req := Request{RequestConfig: RequestConfig{Group: "test", SliceIDs: []string{"1", "2", "3"}}}
+	machines, err := newController.(*controller).sliceMachines(context.Background(), req)
+	Expect(err).To(BeNil())
+	Expect(machines).To(Equal(map[string][]string{
+		"1": {"m1", "m2"},
+		"2": {"m2"},
+	}))
+}
+
+func Test_Parallelism_orderByMachine(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []struct {
+		SliceIDs []string
+		Machines map[string][]string
+		Expected []string
+	}{
+		{
+			SliceIDs: []string{"a1", "a2", "b1", "c1", "b2"},
+			Machines: map[string][]string{
+				"a1": {"a"}, "a2": {"a"}, "b1": {"b"}, "b2": {"b"}, "c1": {"c"},
+			},
+			Expected: []string{"a1", "b1", "c1", "a2", "b2"},
+		},
+		{
+			SliceIDs: []string{"1", "2", "3"},
+			Machines: map[string][]string{},
+			Expected: []string{"1", "2", "3"},
+		},
+		{
+			SliceIDs: []string{"1", "2", "3", "4"},
+			Machines: map[string][]string{"1": {"a"}, "2": {"a"}},
+			Expected: []string{"1", "3", "2", "4"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		Expect(orderByMachine(testCase.SliceIDs, testCase.Machines)).To(Equal(testCase.Expected), "test case %d", i+1)
+	}
+}
+
+func Test_Parallelism_machineSlots(t *testing.T) {
+	RegisterTestingT(t)
+
+	slots := newMachineSlots(1)
+	Expect(slots.acquire([]string{"a", "b"})).To(BeTrue())
+	Expect(slots.acquire([]string{"a"})).To(BeFalse())
+	// Slots are taken all or nothing.
+	Expect(slots.acquire([]string{"c", "b"})).To(BeFalse())
+	Expect(slots.acquire([]string{"c"})).To(BeTrue())
+	// Unscheduled slices do not need a slot.
+	Expect(slots.acquire(nil)).To(BeTrue())
+
+	slots.release([]string{"a", "b"})
+	Expect(slots.acquire([]string{"a", "b"})).To(BeTrue())
+
+	unlimited := newMachineSlots(0)
+	Expect(unlimited.acquire([]string{"a"})).To(BeTrue())
+	Expect(unlimited.acquire([]string{"a"})).To(BeTrue())
+}
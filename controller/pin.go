@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"sort"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/state"
+)
+
+// pinNamespace is the namespace of the state store pinned slices are stored
+// in, keyed by group.
+const pinNamespace = "pin"
+
+// pinnedSlices is stored for each group having pinned slices.
+type pinnedSlices struct {
+	SliceIDs []string `json:"slice-ids"`
+}
+
+func (c controller) PinSlices(ctx context.Context, group string, sliceIDs []string) error {
+	c.Config.Logger.Debug(ctx, "controller: pinning slices %v of group '%s'", sliceIDs, group)
+
+	pinned, err := c.PinnedSlices(ctx, group)
+	if err != nil {
+		return maskAny(err)
+	}
+	for _, sliceID := range sliceIDs {
+		if !contains(pinned, sliceID) {
+			pinned = append(pinned, sliceID)
+		}
+	}
+
+	if err := c.setPinnedSlices(group, pinned); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (c controller) UnpinSlices(ctx context.Context, group string, sliceIDs []string) error {
+	c.Config.Logger.Debug(ctx, "controller: unpinning slices %v of group '%s'", sliceIDs, group)
+
+	pinned, err := c.PinnedSlices(ctx, group)
+	if err != nil {
+		return maskAny(err)
+	}
+	var kept []string
+	for _, sliceID := range pinned {
+		if !contains(sliceIDs, sliceID) {
+			kept = append(kept, sliceID)
+		}
+	}
+
+	if err := c.setPinnedSlices(group, kept); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (c controller) PinnedSlices(ctx context.Context, group string) ([]string, error) {
+	if c.Config.StateStore == nil {
+		return nil, nil
+	}
+
+	var pinned pinnedSlices
+	err := c.Config.StateStore.Get(pinNamespace, group, &pinned)
+	if state.IsNotFound(err) {
+		return nil, nil
+	} else if state.IsInvalidKey(err) {
+		return nil, maskAnyf(invalidArgumentError, "group: %s", err.Error())
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return pinned.SliceIDs, nil
+}
+
+func (c controller) setPinnedSlices(group string, sliceIDs []string) error {
+	if c.Config.StateStore == nil {
+		return maskAnyf(invalidArgumentError, "pinning slices requires a state store")
+	}
+
+	sort.Strings(sliceIDs)
+	err := c.Config.StateStore.Set(pinNamespace, group, pinnedSlices{SliceIDs: sliceIDs})
+	if state.IsInvalidKey(err) {
+		return maskAnyf(invalidArgumentError, "group: %s", err.Error())
+	} else if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// withoutPinnedSlices returns a copy of the given request without the slices
+// pinned using PinSlices, and the IDs of the pinned slices that were removed.
+func (c controller) withoutPinnedSlices(ctx context.Context, req Request) (Request, []string, error) {
+	pinned, err := c.PinnedSlices(ctx, req.Group)
+	if err != nil {
+		return Request{}, nil, maskAny(err)
+	}
+
+	var sliceIDs, skipped []string
+	for _, sliceID := range req.SliceIDs {
+		if contains(pinned, sliceID) {
+			skipped = append(skipped, sliceID)
+			continue
+		}
+		sliceIDs = append(sliceIDs, sliceID)
+	}
+	req.SliceIDs = sliceIDs
+
+	return req, skipped, nil
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/state"
+)
+
+func TestController_PinSlices(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	c, _ := getTestController()
+
+	err := c.PinSlices(ctx, "app", []string{"1"})
+	Expect(IsInvalidArgument(err)).To(BeTrue())
+	pinned, err := c.PinnedSlices(ctx, "app")
+	Expect(err).To(BeNil())
+	Expect(pinned).To(BeEmpty())
+
+	c.Config.StateStore = state.NewMemoryStore()
+	Expect(c.PinSlices(ctx, "app", []string{"3", "1"})).To(Succeed())
+	Expect(c.PinSlices(ctx, "app", []string{"1", "2"})).To(Succeed())
+	pinned, err = c.PinnedSlices(ctx, "app")
+	Expect(err).To(BeNil())
+	Expect(pinned).To(Equal([]string{"1", "2", "3"}))
+
+	// Slices are pinned per group.
+	pinned, err = c.PinnedSlices(ctx, "other")
+	Expect(err).To(BeNil())
+	Expect(pinned).To(BeEmpty())
+
+	Expect(c.UnpinSlices(ctx, "app", []string{"2", "4"})).To(Succeed())
+	pinned, err = c.PinnedSlices(ctx, "app")
+	Expect(err).To(BeNil())
+	Expect(pinned).To(Equal([]string{"1", "3"}))
+
+	req, skipped, err := c.withoutPinnedSlices(ctx, Request{RequestConfig: RequestConfig{Group: "app", SliceIDs: []string{"1", "2", "3"}}})
+	Expect(err).To(BeNil())
+	Expect(req.SliceIDs).To(Equal([]string{"2"}))
+	Expect(skipped).To(Equal([]string{"1", "3"}))
+}
+
+func TestUpdate_PinnedSlices(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	c, dummyFleet := getTestController()
+	c.Config.StateStore = state.NewMemoryStore()
+	Expect(dummyFleet.Submit(ctx, "app-main@1.service", "[Service]\nExecStart=/bin/old\n")).To(Succeed())
+	Expect(dummyFleet.Submit(ctx, "app-main@2.service", "[Service]\nExecStart=/bin/old\n")).To(Succeed())
+	Expect(c.PinSlices(ctx, "app", []string{"1", "2"})).To(Succeed())
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "app", SliceIDs: []string{"1", "2"}},
+		Units:         []Unit{{Name: "app-main@.service", Content: "[Service]\nExecStart=/bin/new\n"}},
+	}
+	taskObject, err := c.Update(ctx, req, UpdateOptions{MaxGrowth: 1, MinAlive: 0})
+	Expect(err).To(BeNil())
+	taskObject, err = c.WaitForTask(ctx, taskObject.ID, nil)
+	Expect(err).To(BeNil())
+	Expect(IsUnitsAlreadyUpToDate(taskObject.Error)).To(BeTrue())
+
+	// Pinned slices are left untouched.
+	status, err := c.GetStatus(ctx, Request{RequestConfig: RequestConfig{Group: "app"}})
+	Expect(err).To(BeNil())
+	var sliceIDs []string
+	for _, us := range status {
+		sliceIDs = append(sliceIDs, us.SliceID)
+	}
+	Expect(sliceIDs).To(ConsistOf("1", "2"))
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/coreos/fleet/unit"
+)
+
+// machineMetadataExp matches MachineMetadata options of unit files.
+var machineMetadataExp = regexp.MustCompile(`^\s*MachineMetadata\s*=`)
+
+// ParsePlacement parses placement hints given as "key=value" machine metadata
+// selectors, e.g. "role=canary". Giving the same key multiple times allows
+// machines having any of the values. In case a hint is malformed, an error
+// that you can identify using IsInvalidRequest is returned. See
+// Request.Placement.
+//
+//   [role=canary region=eu-west]
+//
+func ParsePlacement(hints []string) ([]string, error) {
+	var placement []string
+	for _, hint := range hints {
+		i := strings.Index(hint, "=")
+		if i < 0 {
+			return nil, maskAnyf(invalidArgumentError, "placement hint '%s' must have the form key=value", hint)
+		}
+		if err := validateLabel(hint[:i], hint[i+1:]); err != nil {
+			return nil, maskAnyf(invalidArgumentError, "bad placement hint '%s'", hint)
+		}
+		placement = append(placement, hint)
+	}
+
+	return placement, nil
+}
+
+// withPlacement returns the given unit content scheduled according to the
+// given placement hints. The hints are added as MachineMetadata option of the
+// X-Fleet section. Fleet allows machines having any of the values given for a
+// key, so the unit's own MachineMetadata for keys the hints contain are
+// dropped, while the ones for other keys are kept.
+//
+//   [X-Fleet]                                   [X-Fleet]
+//   MachineMetadata="role=web" "region=eu"  =>  MachineMetadata="region=eu" "role=canary"
+//
+//   (placement: role=canary)
+//
+func withPlacement(content string, placement []string) (string, error) {
+	if len(placement) == 0 {
+		return content, nil
+	}
+
+	unitFile, err := unit.NewUnitFile(content)
+	if err != nil {
+		return "", maskAnyf(invalidUnitContentError, "%s", err.Error())
+	}
+
+	keys := map[string]struct{}{}
+	for _, hint := range placement {
+		keys[hint[:strings.Index(hint, "=")]] = struct{}{}
+	}
+	var metadata []string
+	for _, value := range unitFile.Contents["X-Fleet"]["MachineMetadata"] {
+		if i := strings.Index(value, "="); i > 0 {
+			if _, ok := keys[value[:i]]; ok {
+				continue
+			}
+		}
+		metadata = append(metadata, value)
+	}
+	metadata = append(metadata, placement...)
+	for i, value := range metadata {
+		metadata[i] = `"` + value + `"`
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		if !machineMetadataExp.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+	section := fmt.Sprintf("\n[X-Fleet]\nMachineMetadata=%s\n", strings.Join(metadata, " "))
+
+	return strings.Join(lines, "\n") + "\n" + section, nil
+}
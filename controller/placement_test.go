@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/coreos/fleet/unit"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/task"
+)
+
+func Test_ParsePlacement(t *testing.T) {
+	RegisterTestingT(t)
+
+	placement, err := ParsePlacement([]string{"role=canary", "region=eu-west"})
+	Expect(err).To(BeNil())
+	Expect(placement).To(Equal([]string{"role=canary", "region=eu-west"}))
+
+	for _, hint := range []string{"role", "=canary", "role=can ary", "role=\"canary\""} {
+		_, err := ParsePlacement([]string{hint})
+		Expect(IsInvalidRequest(err)).To(BeTrue())
+	}
+}
+
+func Test_withPlacement(t *testing.T) {
+	RegisterTestingT(t)
+
+	content := "[Service]\nExecStart=/bin/app\n\n[X-Fleet]\nConflicts=app@*.service\nMachineMetadata=\"role=web\" \"region=eu\"\n"
+	placed, err := withPlacement(content, []string{"role=canary"})
+	Expect(err).To(BeNil())
+
+	unitFile, err := unit.NewUnitFile(placed)
+	Expect(err).To(BeNil())
+	Expect(unitFile.Contents["Service"]["ExecStart"]).To(Equal([]string{"/bin/app"}))
+	Expect(unitFile.Contents["X-Fleet"]["Conflicts"]).To(Equal([]string{"app@*.service"}))
+	Expect(unitFile.Contents["X-Fleet"]["MachineMetadata"]).To(Equal([]string{"region=eu", "role=canary"}))
+
+	// Without placement hints the content is kept as it is.
+	placed, err = withPlacement(content, nil)
+	Expect(err).To(BeNil())
+	Expect(placed).To(Equal(content))
+}
+
+func TestSubmit_Placement(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	c, dummyFleet := getTestController()
+	req := Request{
+		RequestConfig: RequestConfig{Group: "app", SliceIDs: []string{"1"}},
+		Units:         []Unit{{Name: "app-main@.service", Content: "[Service]\nExecStart=/bin/app\n"}},
+		Placement:     []string{"role=canary"},
+	}
+
+	taskObject, err := c.Submit(ctx, req)
+	Expect(err).To(BeNil())
+	taskObject, err = c.WaitForTask(ctx, taskObject.ID, nil)
+	Expect(err).To(BeNil())
+	Expect(task.HasSucceededStatus(taskObject)).To(BeTrue())
+
+	content, err := dummyFleet.GetContent(ctx, "app-main@1.service")
+	Expect(err).To(BeNil())
+	unitFile, err := unit.NewUnitFile(content)
+	Expect(err).To(BeNil())
+	Expect(unitFile.Contents["X-Fleet"]["MachineMetadata"]).To(Equal([]string{"role=canary"}))
+
+	req.Placement = []string{"role"}
+	_, err = c.Submit(ctx, req)
+	Expect(IsInvalidRequest(err)).To(BeTrue())
+}
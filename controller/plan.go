@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"sort"
+
+	"golang.org/x/net/context"
+)
+
+// PlanAction describes what happens to a slice when a plan is applied.
+type PlanAction string
+
+const (
+	// PlanCreate means the slice is submitted and started.
+	PlanCreate PlanAction = "create"
+
+	// PlanUpdate means the slice is replaced by a slice running the local
+	// units. See Controller.Update.
+	PlanUpdate PlanAction = "update"
+
+	// PlanNoOp means the slice is left as it is.
+	PlanNoOp PlanAction = "no-op"
+
+	// PlanDestroy means the slice is stopped and destroyed.
+	PlanDestroy PlanAction = "destroy"
+)
+
+// PlanStep is the action planned for a single slice.
+type PlanStep struct {
+	// SliceID is the ID of the slice. It is empty for slices to be created,
+	// since their IDs are generated when submitting them, and for groups
+	// without slices.
+	SliceID string `json:"slice-id"`
+
+	// Action is what happens to the slice.
+	Action PlanAction `json:"action"`
+
+	// Reason explains why the action is planned, e.g. "pinned".
+	Reason string `json:"reason"`
+
+	// Machines are the IDs of the machines the slice is scheduled on. Slices
+	// replaced at the same time on a machine are limited by
+	// UpdateOptions.MaxPerMachine.
+	Machines []string `json:"machines,omitempty"`
+}
+
+// UnitChange describes how a unit file of the group changes.
+type UnitChange struct {
+	// Name is the name of the unit file, e.g. "mygroup-foo@.service".
+	Name string `json:"name"`
+
+	// Deployed is the content of the unit file deployed to the cluster. It is
+	// empty in case the unit is added.
+	Deployed string `json:"deployed"`
+
+	// Local is the content of the local unit file. It is empty in case the unit
+	// is removed.
+	Local string `json:"local"`
+}
+
+// Plan describes the changes bringing a group deployed to the cluster in line
+// with a request, without applying them. Plans are meant to be reviewed, e.g.
+// by external approval workflows, before running the corresponding
+// operations.
+type Plan struct {
+	// Group is the name of the planned group.
+	Group string `json:"group"`
+
+	// Steps are the actions planned per slice. Slices are destroyed first,
+	// then created, then updated in the order the update replaces them. Slices
+	// left as they are come last.
+	Steps []PlanStep `json:"steps"`
+
+	// Options are the constraints the update of dirty slices obeys.
+	Options UpdateOptions `json:"options"`
+
+	// Changes are the unit files differing between the cluster and the
+	// request, sorted by name. Formatting changes are not considered. See
+	// UnitContentEqual.
+	Changes []UnitChange `json:"changes"`
+}
+
+// Count returns the number of steps of the plan having the given action.
+func (p Plan) Count(action PlanAction) int {
+	var n int
+	for _, step := range p.Steps {
+		if step.Action == action {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Empty checks whether applying the plan would change anything.
+func (p Plan) Empty() bool {
+	return p.Count(PlanNoOp) == len(p.Steps)
+}
+
+func (c controller) Plan(ctx context.Context, req Request, scale int, opts UpdateOptions) (Plan, error) {
+	c.Config.Logger.Debug(ctx, "controller: planning group '%s'", req.Group)
+
+	plan := Plan{
+		Group:   req.Group,
+		Options: opts,
+	}
+
+	if scale < 0 {
+		return Plan{}, maskAnyf(invalidArgumentError, "scale must not be negative, got %d", scale)
+	}
+
+	exists, err := c.Exists(ctx, Request{RequestConfig: RequestConfig{Group: req.Group}})
+	if err != nil {
+		return Plan{}, maskAny(err)
+	}
+	if !exists {
+		for _, u := range req.Units {
+			plan.Changes = append(plan.Changes, UnitChange{Name: u.Name, Local: u.Content})
+		}
+		sort.Sort(unitChangesByName(plan.Changes))
+
+		n := 1
+		if req.isSliceable() && scale > 0 {
+			n = scale
+		}
+		for i := 0; i < n; i++ {
+			plan.Steps = append(plan.Steps, PlanStep{Action: PlanCreate, Reason: "not deployed"})
+		}
+
+		return plan, nil
+	}
+
+	req, err = c.ExtendWithExistingSliceIDs(req)
+	if err != nil {
+		return Plan{}, maskAny(err)
+	}
+	sort.Strings(req.SliceIDs)
+
+	plan.Changes, err = c.unitChanges(ctx, req)
+	if err != nil {
+		return Plan{}, maskAny(err)
+	}
+
+	if !req.isSliceable() {
+		_, dirty, err := c.GroupNeedsUpdate(ctx, req)
+		if err != nil {
+			return Plan{}, maskAny(err)
+		}
+		step := PlanStep{Action: PlanNoOp, Reason: "up to date"}
+		if dirty {
+			step = PlanStep{Action: PlanUpdate, Reason: "units changed"}
+		}
+		plan.Steps = append(plan.Steps, step)
+
+		return plan, nil
+	}
+
+	pinned, err := c.PinnedSlices(ctx, req.Group)
+	if err != nil {
+		return Plan{}, maskAny(err)
+	}
+	machines, err := c.sliceMachines(ctx, req)
+	if err != nil {
+		return Plan{}, maskAny(err)
+	}
+
+	// Scaling down destroys the unpinned slices sorted last, so that numbered
+	// slice IDs keep the lowest numbers.
+	kept := req.SliceIDs
+	if scale > 0 && scale < len(req.SliceIDs) {
+		var unpinned []string
+		for _, sliceID := range req.SliceIDs {
+			if !contains(pinned, sliceID) {
+				unpinned = append(unpinned, sliceID)
+			}
+		}
+		n := len(req.SliceIDs) - scale
+		if n > len(unpinned) {
+			return Plan{}, maskAnyf(invalidArgumentError, "cannot scale down to %d slices, %d slices are pinned", scale, len(pinned))
+		}
+		surplus := unpinned[len(unpinned)-n:]
+		kept = nil
+		for _, sliceID := range req.SliceIDs {
+			if contains(surplus, sliceID) {
+				plan.Steps = append(plan.Steps, PlanStep{SliceID: sliceID, Action: PlanDestroy, Reason: "scaled down", Machines: machines[sliceID]})
+				continue
+			}
+			kept = append(kept, sliceID)
+		}
+	}
+	for i := len(req.SliceIDs); i < scale; i++ {
+		plan.Steps = append(plan.Steps, PlanStep{Action: PlanCreate, Reason: "scaled up"})
+	}
+
+	keptReq := req
+	keptReq.SliceIDs = kept
+	dirtyReq, dirty, err := c.GroupNeedsUpdate(ctx, keptReq)
+	if err != nil {
+		return Plan{}, maskAny(err)
+	}
+	var dirtySliceIDs []string
+	if dirty {
+		dirtySliceIDs = dirtyReq.SliceIDs
+	}
+
+	var updated, unchanged []PlanStep
+	for _, sliceID := range orderByMachine(kept, machines) {
+		step := PlanStep{SliceID: sliceID, Action: PlanNoOp, Reason: "up to date", Machines: machines[sliceID]}
+		if contains(dirtySliceIDs, sliceID) {
+			if contains(pinned, sliceID) {
+				step.Reason = "pinned"
+			} else {
+				step.Action = PlanUpdate
+				step.Reason = "units changed"
+			}
+		}
+		if step.Action == PlanUpdate {
+			updated = append(updated, step)
+		} else {
+			unchanged = append(unchanged, step)
+		}
+	}
+	plan.Steps = append(plan.Steps, updated...)
+	plan.Steps = append(plan.Steps, unchanged...)
+
+	return plan, nil
+}
+
+// unitChanges compares the units of the given request with the ones deployed
+// to the cluster. The units deployed are the ones of the first slice, so
+// environment file units are rendered for this slice to be comparable.
+func (c controller) unitChanges(ctx context.Context, req Request) ([]UnitChange, error) {
+	deployed, err := c.DeployedUnits(ctx, req)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	local := req
+	if len(req.SliceIDs) > 0 {
+		local.SliceIDs = req.SliceIDs[:1]
+	}
+	local, err = local.ExtendSlices()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	deployedContent := map[string]string{}
+	for _, u := range deployed {
+		deployedContent[u.Name] = u.Content
+	}
+	localContent := map[string]string{}
+	for i, u := range local.Units {
+		localContent[req.Units[i].Name] = u.Content
+	}
+
+	var changes []UnitChange
+	for name, content := range deployedContent {
+		if _, ok := localContent[name]; !ok {
+			changes = append(changes, UnitChange{Name: name, Deployed: content})
+		}
+	}
+	for name, content := range localContent {
+		if UnitContentEqual(deployedContent[name], content) {
+			continue
+		}
+		changes = append(changes, UnitChange{Name: name, Deployed: deployedContent[name], Local: content})
+	}
+	sort.Sort(unitChangesByName(changes))
+
+	return changes, nil
+}
+
+type unitChangesByName []UnitChange
+
+func (u unitChangesByName) Len() int           { return len(u) }
+func (u unitChangesByName) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u unitChangesByName) Less(i, j int) bool { return u[i].Name < u[j].Name }
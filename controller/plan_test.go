@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/state"
+)
+
+func TestController_Plan(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	c, dummyFleet := getTestController()
+	c.Config.StateStore = state.NewMemoryStore()
+	opts := UpdateOptions{MaxGrowth: 1, MinAlive: 1, ReadySecs: 30}
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "app"},
+		Units:         []Unit{{Name: "app-main@.service", Content: "[Service]\nExecStart=/bin/new\n"}},
+	}
+
+	// Groups not deployed are created.
+	scale := 2
+	plan, err := c.Plan(ctx, req, scale, opts)
+	Expect(err).To(BeNil())
+	Expect(plan.Group).To(Equal("app"))
+	Expect(plan.Options).To(Equal(opts))
+	Expect(plan.Count(PlanCreate)).To(Equal(2))
+	Expect(plan.Changes).To(Equal([]UnitChange{{Name: "app-main@.service", Local: "[Service]\nExecStart=/bin/new\n"}}))
+
+	for _, sliceID := range []string{"1", "2", "3"} {
+		Expect(dummyFleet.Submit(ctx, "app-main@"+sliceID+".service", "[Service]\nExecStart=/bin/old\n")).To(Succeed())
+	}
+	Expect(dummyFleet.Submit(ctx, "app-main@4.service", "[Service]\nExecStart=/bin/new\n")).To(Succeed())
+	Expect(c.PinSlices(ctx, "app", []string{"1"})).To(Succeed())
+
+	// Dirty slices are updated, unless they are pinned. Nothing changes
+	// without changes to the units.
+	scale = 0
+	plan, err = c.Plan(ctx, req, scale, opts)
+	Expect(err).To(BeNil())
+	Expect(plan.Steps).To(HaveLen(4))
+	Expect(plan.Steps[0].SliceID).To(Equal("2"))
+	Expect(plan.Steps[0].Action).To(Equal(PlanUpdate))
+	Expect(plan.Steps[1].SliceID).To(Equal("3"))
+	Expect(plan.Steps[1].Action).To(Equal(PlanUpdate))
+	Expect(plan.Steps[2].SliceID).To(Equal("1"))
+	Expect(plan.Steps[2].Action).To(Equal(PlanNoOp))
+	Expect(plan.Steps[2].Reason).To(Equal("pinned"))
+	Expect(plan.Steps[3].SliceID).To(Equal("4"))
+	Expect(plan.Steps[3].Reason).To(Equal("up to date"))
+	Expect(plan.Changes).To(HaveLen(1))
+	Expect(plan.Changes[0].Deployed).To(Equal("[Service]\nExecStart=/bin/old\n"))
+	Expect(plan.Empty()).To(BeFalse())
+
+	// Scaling down destroys the unpinned slices sorted last, scaling up
+	// creates slices.
+	scale = 2
+	plan, err = c.Plan(ctx, req, scale, opts)
+	Expect(err).To(BeNil())
+	Expect(plan.Steps[0]).To(Equal(PlanStep{SliceID: "3", Action: PlanDestroy, Reason: "scaled down"}))
+	Expect(plan.Steps[1]).To(Equal(PlanStep{SliceID: "4", Action: PlanDestroy, Reason: "scaled down"}))
+	Expect(plan.Count(PlanUpdate)).To(Equal(1))
+	Expect(plan.Count(PlanNoOp)).To(Equal(1))
+
+	scale = 6
+	plan, err = c.Plan(ctx, req, scale, opts)
+	Expect(err).To(BeNil())
+	Expect(plan.Count(PlanCreate)).To(Equal(2))
+
+	Expect(c.PinSlices(ctx, "app", []string{"2", "3", "4"})).To(Succeed())
+	scale = 2
+	_, err = c.Plan(ctx, req, scale, opts)
+	Expect(IsInvalidArgument(err)).To(BeTrue())
+
+	req.Units[0].Content = "[Service]\nExecStart=/bin/old\n"
+	scale = 0
+	Expect(c.UnpinSlices(ctx, "app", []string{"1", "2", "3", "4"})).To(Succeed())
+	Expect(dummyFleet.Submit(ctx, "app-main@4.service", "[Service]\nExecStart=/bin/old\n")).To(Succeed())
+	plan, err = c.Plan(ctx, req, scale, opts)
+	Expect(err).To(BeNil())
+	Expect(plan.Changes).To(BeEmpty())
+}
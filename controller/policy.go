@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/policy"
+)
+
+// checkPolicy checks whether the configured policy allows the given operation
+// against the group of the given request. On submit the scale of the group
+// after the submit is checked as well. On submit and update the group must be
+// signed in case the policy requires it, see Request.Signed. In case
+// Config.ForcePolicy is set, policy violations are only logged. Freezes are
+// checked first, see checkFreeze. Units selected using Request.Match are
+// checked against the rules of all groups they may belong to.
+func (c controller) checkPolicy(ctx context.Context, op policy.Operation, req Request) error {
+	if req.Match != nil {
+		return maskAny(c.checkMatchPolicy(ctx, op, req))
+	}
+	if err := c.checkFreeze(ctx, op, req); err != nil {
+		return maskAny(err)
+	}
+
+	c.Config.Logger.Debug(ctx, "controller: checking policy for %s of group '%s'", op, req.Group)
+
+	err := c.Config.Policy.CheckOperation(op, req.Group)
+	if err == nil && op == policy.Submit && c.Config.Policy.LimitsScale(req.Group) {
+		var existingSliceIDs []string
+		existingSliceIDs, err = c.getExistingSliceIDs(req)
+		if err != nil {
+			return maskAny(err)
+		}
+		scale := len(existingSliceIDs) + len(req.SliceIDs) + req.DesiredSlices
+		err = c.Config.Policy.CheckScale(req.Group, scale)
+	}
+	if err == nil && (op == policy.Submit || op == policy.Update) {
+		err = c.Config.Policy.CheckSignature(req.Group, req.Signed)
+	}
+
+	if policy.IsPolicyViolation(err) && c.Config.ForcePolicy {
+		c.Config.Logger.Warning(ctx, "Ignoring policy violation: %s", err.Error())
+		return nil
+	} else if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// checkMatchPolicy checks the policy for each group the units selected using
+// req.Match may belong to, so that selecting units by their names cannot be
+// used to bypass rules of groups. See matchedGroups.
+func (c controller) checkMatchPolicy(ctx context.Context, op policy.Operation, req Request) error {
+	usl, err := c.Fleet.GetStatusWithMatcher(matchesGroupSlices(req))
+	if fleet.IsUnitNotFound(err) {
+		return nil
+	} else if err != nil {
+		return maskAny(err)
+	}
+
+	for _, group := range matchedGroups(usl) {
+		groupReq := req
+		groupReq.Group = group
+		groupReq.Match = nil
+		if err := c.checkPolicy(ctx, op, groupReq); err != nil {
+			return maskAny(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,314 @@
+package controller
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/coreos/fleet/unit"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/fleet"
+)
+
+// PrePullUnitPrefix is the prefix of the transient units pulling images before
+// a group is started. It does not start with a group name, so that pull units
+// are never considered part of a group.
+const PrePullUnitPrefix = "inago-prepull-"
+
+// prePullUnitTemplate is the template of the content of units pulling images.
+// Units are either bound to a single machine, or global in case the group is
+// not scheduled yet.
+var prePullUnitTemplate = template.Must(template.New("prepull-unit").Parse(`[Unit]
+Description=Pre-pull images of group {{.Group}}
+After=docker.service
+Requires=docker.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+{{range .Images}}ExecStart=/usr/bin/docker pull {{.}}
+{{end}}
+[X-Fleet]
+{{if .MachineID}}MachineID={{.MachineID}}{{else}}Global=true{{end}}
+`))
+
+// dockerRunValueFlags are the flags of "docker run" taking a value as separate
+// argument. They need to be skipped to find the image argument.
+var dockerRunValueFlags = map[string]struct{}{
+	"-a": {}, "--attach": {}, "--add-host": {}, "-c": {}, "--cap-add": {},
+	"--cap-drop": {}, "--cidfile": {}, "--cpu-shares": {}, "--device": {},
+	"--dns": {}, "-e": {}, "--entrypoint": {}, "--env": {}, "--env-file": {},
+	"--expose": {}, "-h": {}, "--hostname": {}, "-l": {}, "--label": {},
+	"--link": {}, "--log-driver": {}, "--log-opt": {}, "-m": {}, "--memory": {},
+	"--name": {}, "--net": {}, "-p": {}, "--publish": {}, "--restart": {},
+	"--stop-signal": {}, "-u": {}, "--ulimit": {}, "--user": {}, "-v": {},
+	"--volume": {}, "--volumes-from": {}, "-w": {}, "--workdir": {},
+}
+
+// UnitImages returns the Docker images used by the unit having the given
+// content. Images are taken from "docker pull" and "docker run" commands of
+// the Exec* directives of the [Service] section. Variables defined using
+// Environment= are expanded. Images referring to variables that cannot be
+// expanded, e.g. because they are defined in environment files, are skipped.
+//
+//   Environment="IMAGE=myapp:1.0"
+//   ExecStartPre=/usr/bin/docker pull $IMAGE              =>  [myapp:1.0]
+//   ExecStart=/usr/bin/docker run --rm --name app $IMAGE
+//
+func UnitImages(content string) []string {
+	unitFile, err := unit.NewUnitFile(content)
+	if err != nil {
+		return nil
+	}
+	service := unitFile.Contents["Service"]
+
+	env := map[string]string{}
+	for _, value := range service["Environment"] {
+		for _, assignment := range strings.Fields(value) {
+			assignment = strings.Trim(assignment, `"`)
+			if i := strings.Index(assignment, "="); i > 0 {
+				env[assignment[:i]] = assignment[i+1:]
+			}
+		}
+	}
+	expand := func(s string) string {
+		return os.Expand(s, func(name string) string {
+			if v, ok := env[name]; ok {
+				return v
+			}
+			return "$" + name
+		})
+	}
+
+	var names []string
+	for name := range service {
+		if strings.HasPrefix(name, "Exec") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	seen := map[string]struct{}{}
+	var images []string
+	for _, name := range names {
+		for _, value := range service[name] {
+			image := dockerImage(strings.Fields(value))
+			if image == "" {
+				continue
+			}
+			image = expand(image)
+			if strings.Contains(image, "$") {
+				continue
+			}
+			if _, ok := seen[image]; !ok {
+				seen[image] = struct{}{}
+				images = append(images, image)
+			}
+		}
+	}
+
+	return images
+}
+
+// dockerImage returns the image argument of the given "docker pull" or
+// "docker run" command line. An empty string is returned for other commands.
+func dockerImage(args []string) string {
+	if len(args) < 2 {
+		return ""
+	}
+	// Systemd allows to prefix commands with "-" and "@" to change how they
+	// are executed.
+	binary := strings.TrimLeft(args[0], "-@")
+	if binary != "docker" && !strings.HasSuffix(binary, "/docker") {
+		return ""
+	}
+
+	switch args[1] {
+	case "pull":
+		for _, arg := range args[2:] {
+			if !strings.HasPrefix(arg, "-") {
+				return arg
+			}
+		}
+	case "run":
+		for i := 2; i < len(args); i++ {
+			arg := args[i]
+			if !strings.HasPrefix(arg, "-") {
+				return arg
+			}
+			if _, ok := dockerRunValueFlags[arg]; ok {
+				i++
+			}
+		}
+	}
+
+	return ""
+}
+
+// prePullUnits creates the units pulling the given images of the given group
+// on the given machines. In case no machine is given, a global unit pulling
+// the images on all machines is created.
+func prePullUnits(group string, images, machineIDs []string) ([]Unit, error) {
+	if len(machineIDs) == 0 {
+		machineIDs = []string{""}
+	}
+
+	var units []Unit
+	for _, machineID := range machineIDs {
+		name := PrePullUnitPrefix + group + common.ServiceUnitExtension
+		if machineID != "" {
+			short := machineID
+			if len(short) > 8 {
+				short = short[:8]
+			}
+			name = PrePullUnitPrefix + group + "-" + short + common.ServiceUnitExtension
+		}
+
+		content := bytes.NewBuffer(nil)
+		err := prePullUnitTemplate.Execute(content, struct {
+			Group     string
+			Images    []string
+			MachineID string
+		}{
+			Group:     group,
+			Images:    images,
+			MachineID: machineID,
+		})
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		units = append(units, Unit{Name: name, Content: content.String()})
+	}
+
+	return units, nil
+}
+
+// prePullImages pulls the images used by the units of the given unit states on
+// the machines they are scheduled on, or on all machines in case they are not
+// scheduled yet. The pull units are destroyed once all images are pulled. In
+// case pulling fails, an error that you can identify using IsPrePullFailed is
+// returned.
+func (c controller) prePullImages(ctx context.Context, group string, usl []fleet.UnitStatus) error {
+	c.Config.Logger.Debug(ctx, "controller: pre-pulling images of group '%s'", group)
+
+	// All slices of a group use the same images. It is enough to look at one
+	// unit per unit file.
+	bases := map[string]struct{}{}
+	seenImages := map[string]struct{}{}
+	seenMachines := map[string]struct{}{}
+	var images, machineIDs []string
+	for _, us := range usl {
+		for _, ms := range us.Machine {
+			if _, ok := seenMachines[ms.ID]; !ok && ms.ID != "" {
+				seenMachines[ms.ID] = struct{}{}
+				machineIDs = append(machineIDs, ms.ID)
+			}
+		}
+
+		base := common.UnitBase(us.Name) + common.UnitExtension(us.Name)
+		if _, ok := bases[base]; ok {
+			continue
+		}
+		bases[base] = struct{}{}
+
+		content, err := c.Fleet.GetContent(ctx, us.Name)
+		if err != nil {
+			return maskAny(err)
+		}
+		for _, image := range UnitImages(content) {
+			if _, ok := seenImages[image]; !ok {
+				seenImages[image] = struct{}{}
+				images = append(images, image)
+			}
+		}
+	}
+	if len(images) == 0 {
+		c.Config.Logger.Debug(ctx, "controller: no images to pre-pull for group '%s'", group)
+		return nil
+	}
+	sort.Strings(machineIDs)
+
+	units, err := prePullUnits(group, images, machineIDs)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer func() {
+		for _, u := range units {
+			if err := c.Fleet.Destroy(ctx, u.Name); err != nil {
+				c.Config.Logger.Debug(ctx, "controller: cannot destroy pre-pull unit '%s': %#v", u.Name, err)
+			}
+		}
+	}()
+
+	for _, u := range units {
+		if err := c.Fleet.Submit(ctx, u.Name, u.Content); err != nil {
+			return maskAny(err)
+		}
+		if err := c.Fleet.Start(ctx, u.Name); err != nil {
+			return maskAny(err)
+		}
+	}
+
+	timeout := time.After(c.WaitTimeout)
+	backoff := newWaitBackoff(c.WaitSleep, c.WaitMaxSleep)
+	for _, u := range units {
+		for {
+			start := time.Now()
+			done, err := c.prePullDone(ctx, u.Name)
+			if err != nil {
+				return maskAny(err)
+			}
+			if done {
+				break
+			}
+
+			select {
+			case <-timeout:
+				return maskAnyf(waitTimeoutReachedError, "pre-pulling images %v", images)
+			case <-time.After(backoff.Next(time.Since(start), false, false)):
+			}
+		}
+	}
+
+	c.Config.Logger.Debug(ctx, "controller: pre-pulled images %v of group '%s'", images, group)
+
+	return nil
+}
+
+// prePullDone checks whether the pull unit having the given name finished on
+// all machines it is scheduled on. In case it failed on any machine, an error
+// that you can identify using IsPrePullFailed is returned.
+func (c controller) prePullDone(ctx context.Context, name string) (bool, error) {
+	us, err := c.Fleet.GetStatus(ctx, name)
+	if fleet.IsUnitNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, maskAny(err)
+	}
+	if len(us.Machine) == 0 {
+		return false, nil
+	}
+
+	aggregator := Aggregator{
+		Logger: c.Config.Logger,
+	}
+	for _, ms := range us.Machine {
+		status, err := aggregator.AggregateStatus(us.Current, us.Desired, ms.SystemdActive, ms.SystemdSub)
+		if err != nil {
+			return false, maskAny(err)
+		}
+		if status == StatusFailed {
+			return false, maskAnyf(prePullFailedError, "unit '%s' failed on machine '%s'", name, ms.ID)
+		}
+		if status != StatusRunning {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_UnitImages(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected []string
+	}{
+		{
+			Input: `[Service]
+Environment="IMAGE=registry.example.com/app:1.0" "NAME=app"
+ExecStartPre=/usr/bin/docker pull $IMAGE
+ExecStartPre=-/usr/bin/docker rm -f $NAME
+ExecStart=/usr/bin/docker run --rm --name $NAME ${IMAGE} --port=80
+`,
+			Expected: []string{"registry.example.com/app:1.0"},
+		},
+		{
+			Input: `[Service]
+ExecStartPre=/usr/bin/docker pull busybox
+ExecStart=/usr/bin/docker run --rm -p 80:80 -e FOO=bar --net=host nginx:1.9 nginx -g 'daemon off;'
+`,
+			Expected: []string{"nginx:1.9", "busybox"},
+		},
+		{
+			// Images defined in environment files cannot be resolved.
+			Input: `[Service]
+EnvironmentFile=/etc/app.env
+ExecStart=/usr/bin/docker run $IMAGE
+`,
+			Expected: nil,
+		},
+		{
+			Input: `[Service]
+ExecStart=/usr/bin/etcdctl set /foo bar
+`,
+			Expected: nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		output := UnitImages(testCase.Input)
+		if !reflect.DeepEqual(output, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", output)
+		}
+	}
+}
+
+func Test_prePullUnits(t *testing.T) {
+	RegisterTestingT(t)
+
+	units, err := prePullUnits("app", []string{"nginx:1.9", "busybox"}, []string{"0123456789abcdef", "fedcba9876543210"})
+	Expect(err).To(BeNil())
+	Expect(units).To(HaveLen(2))
+	Expect(units[0].Name).To(Equal("inago-prepull-app-01234567.service"))
+	Expect(units[0].Content).To(ContainSubstring("ExecStart=/usr/bin/docker pull nginx:1.9\nExecStart=/usr/bin/docker pull busybox\n"))
+	Expect(units[0].Content).To(ContainSubstring("MachineID=0123456789abcdef"))
+	Expect(units[1].Name).To(Equal("inago-prepull-app-fedcba98.service"))
+
+	units, err = prePullUnits("app", []string{"nginx:1.9"}, nil)
+	Expect(err).To(BeNil())
+	Expect(units).To(HaveLen(1))
+	Expect(units[0].Name).To(Equal("inago-prepull-app.service"))
+	Expect(units[0].Content).To(ContainSubstring("Global=true"))
+}
+
+func TestController_Start_PrePullImages(t *testing.T) {
+	RegisterTestingT(t)
+
+	newController, fleetMock := givenController()
+	c := newController.(*controller)
+	c.Config.PrePullImages = true
+
+	loaded := func(name, sliceID string) fleet.UnitStatus {
+		return fleet.UnitStatus{
+			Name:    name,
+			SliceID: sliceID,
+			Current: "loaded",
+			Desired: "loaded",
+			Machine: []fleet.MachineStatus{{ID: "0123456789abcdef", SystemdActive: "inactive", SystemdSub: "dead"}},
+		}
+	}
+	running := func(name, sliceID string) fleet.UnitStatus {
+		return fleet.UnitStatus{
+			Name:    name,
+			SliceID: sliceID,
+			Current: "launched",
+			Desired: "launched",
+			Machine: []fleet.MachineStatus{{ID: "0123456789abcdef", SystemdActive: "active", SystemdSub: "running"}},
+		}
+	}
+
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(
+		[]fleet.UnitStatus{loaded("app-main@1.service", "1"), loaded("app-main@2.service", "2")}, nil,
+	).Once()
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(
+		[]fleet.UnitStatus{running("app-main@1.service", "1"), running("app-main@2.service", "2")}, nil,
+	)
+	fleetMock.On("GetContent", "app-main@1.service").Return("[Service]\nExecStart=/usr/bin/docker run nginx:1.9\n", nil).Once()
+
+	pullUnit := "inago-prepull-app-01234567.service"
+	fleetMock.On("Submit", pullUnit, mock.MatchedBy(func(content string) bool {
+		return strings.Contains(content, "docker pull nginx:1.9")
+	})).Return(nil).Once()
+	fleetMock.On("Start", pullUnit).Return(nil).Once()
+	fleetMock.On("GetStatus", pullUnit).Return(fleet.UnitStatus{
+		Name:    pullUnit,
+		Current: "launched",
+		Desired: "launched",
+		Machine: []fleet.MachineStatus{{ID: "0123456789abcdef", SystemdActive: "active", SystemdSub: "exited"}},
+	}, nil).Once()
+	fleetMock.On("Destroy", pullUnit).Return(nil).Once()
+	fleetMock.On("Start", "app-main@1.service").Return(nil).Once()
+	fleetMock.On("Start", "app-main@2.service").Return(nil).Once()
+
+	req := Request{RequestConfig: RequestConfig{Group: "app", SliceIDs: []string{"1", "2"}}}
+	taskObject, err := c.Start(context.Background(), req)
+	Expect(err).To(BeNil())
+	taskObject, err = c.WaitForTask(context.Background(), taskObject.ID, nil)
+	Expect(err).To(BeNil())
+	Expect(taskObject.Error).To(BeNil())
+	mock.AssertExpectationsForObjects(t, fleetMock.Mock)
+}
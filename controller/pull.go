@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// GroupFiles describes the unit files of a group the way they are kept in a
+// group directory, as fetched from the cluster using Pull.
+type GroupFiles struct {
+	// Group is the name of the group.
+	Group string
+
+	// Units contains the unit files of the group, sorted by name. Labels added
+	// using Request.WithLabels are removed from their content. Units writing
+	// environment files are contained as the templates they are rendered from.
+	Units []Unit
+
+	// Labels are the labels stored in the deployed units, or nil in case the
+	// group has no labels.
+	Labels map[string]string
+
+	// SliceIDs contains the IDs of all slices of the group deployed to the
+	// cluster. It is empty for groups without slices.
+	SliceIDs []string
+}
+
+func (c controller) Pull(ctx context.Context, req Request) (GroupFiles, error) {
+	c.Config.Logger.Debug(ctx, "controller: pulling group '%s'", req.Group)
+
+	units, err := c.DeployedUnits(ctx, req)
+	if err != nil {
+		return GroupFiles{}, maskAny(err)
+	}
+	req, err = c.ExtendWithExistingSliceIDs(req)
+	if err != nil {
+		return GroupFiles{}, maskAny(err)
+	}
+
+	sort.Strings(req.SliceIDs)
+
+	files := GroupFiles{
+		Group:    req.Group,
+		SliceIDs: req.SliceIDs,
+	}
+	for _, u := range units {
+		if group, labels := UnitLabels(u.Content); group != "" && len(labels) > 0 {
+			files.Labels = labels
+		}
+		// Environment file templates are written instead of the units
+		// rendered from them.
+		name, content := u.SourceFile()
+		files.Units = append(files.Units, Unit{Name: name, Content: withoutLabels(content)})
+	}
+	sort.Sort(unitsByName(files.Units))
+
+	return files, nil
+}
+
+// withoutLabels removes the labels added by Request.WithLabels from the given
+// unit content.
+func withoutLabels(content string) string {
+	i := strings.Index(content, "\n["+LabelSection+"]\n")
+	if i < 0 {
+		return content
+	}
+
+	return strings.TrimRight(content[:i], "\n") + "\n"
+}
+
+type unitsByName []Unit
+
+func (u unitsByName) Len() int           { return len(u) }
+func (u unitsByName) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u unitsByName) Less(i, j int) bool { return u[i].Name < u[j].Name }
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func Test_withoutLabels(t *testing.T) {
+	testCases := map[string]string{
+		"[Service]\nExecStart=/bin/app\n":                                                  "[Service]\nExecStart=/bin/app\n",
+		"[Service]\nExecStart=/bin/app\n\n[X-Inago]\nGroup=mygroup\nLabel=team=payments\n": "[Service]\nExecStart=/bin/app\n",
+	}
+
+	for input, expected := range testCases {
+		if output := withoutLabels(input); output != expected {
+			t.Fatal("input", input, "expected", expected, "got", output)
+		}
+	}
+}
+
+func TestController_Pull(t *testing.T) {
+	controller, dummyFleet := getTestController()
+	ctx := context.Background()
+	req := Request{RequestConfig: RequestConfig{Group: "foo"}}
+
+	_, err := controller.Pull(ctx, req)
+	if !IsUnitNotFound(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	labeled, err := Request{
+		RequestConfig: RequestConfig{Group: "foo"},
+		Units: []Unit{
+			{Name: "foo-main@.service", Content: "[Service]\nExecStart=/bin/main\n"},
+			{Name: "foo-main@.timer", Content: "[Timer]\nOnCalendar=daily\n"},
+		},
+	}.WithLabels(map[string]string{"team": "payments"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	for _, sliceID := range []string{"2", "1"} {
+		for _, u := range labeled.Units {
+			name := u.Name[:len("foo-main@")] + sliceID + u.Name[len("foo-main@"):]
+			if err := dummyFleet.Submit(ctx, name, u.Content); err != nil {
+				t.Fatal("expected", nil, "got", err)
+			}
+		}
+	}
+
+	files, err := controller.Pull(ctx, req)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	expected := GroupFiles{
+		Group: "foo",
+		Units: []Unit{
+			{Name: "foo-main@.service", Content: "[Service]\nExecStart=/bin/main\n"},
+			{Name: "foo-main@.timer", Content: "[Timer]\nOnCalendar=daily\n"},
+		},
+		Labels:   map[string]string{"team": "payments"},
+		SliceIDs: []string{"1", "2"},
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Fatal("expected", expected, "got", files)
+	}
+}
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/fleet"
+)
+
+// ReadyState is a combination of systemd states a unit is considered ready
+// in. By default a unit is ready once its aggregated status is StatusRunning,
+// which is the case for any unit being active, regardless of whether it is
+// still running or already exited. Units need different readiness gates
+// depending on their type, e.g. a long running service is only ready when
+// "active/running", while a oneshot unit is done when "inactive/dead".
+type ReadyState struct {
+	// Active is the systemd active state, e.g. "active".
+	Active string
+
+	// Sub is the systemd sub state, e.g. "running". An empty sub state matches
+	// any sub state.
+	Sub string
+}
+
+// ParseReadyStates parses a comma separated list of ready states. Each state
+// is given as "active/sub", or "active" to accept any sub state. In case the
+// list is malformed, an error that you can identify using IsInvalidRequest is
+// returned.
+//
+//   active/running
+//   active/exited,inactive/dead
+//   active
+//
+func ParseReadyStates(s string) ([]ReadyState, error) {
+	var states []ReadyState
+	for _, raw := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(raw), "/")
+		if len(parts) > 2 || parts[0] == "" || (len(parts) == 2 && parts[1] == "") {
+			return nil, maskAnyf(invalidArgumentError, "ready state '%s' must have the form active/sub", raw)
+		}
+
+		state := ReadyState{Active: parts[0]}
+		if len(parts) == 2 {
+			state.Sub = parts[1]
+		}
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+// String returns the ready state the way ParseReadyStates accepts it.
+func (s ReadyState) String() string {
+	if s.Sub == "" {
+		return s.Active
+	}
+
+	return s.Active + "/" + s.Sub
+}
+
+func (s ReadyState) matches(ms fleet.MachineStatus) bool {
+	return s.Active == ms.SystemdActive && (s.Sub == "" || s.Sub == ms.SystemdSub)
+}
+
+// OneshotReadyStates are the ready states of oneshot units, which are done
+// once their process exited successfully. Oneshot units having
+// RemainAfterExit=yes stay "active/exited", others become "inactive/dead".
+// Oneshot units that failed end up "failed" instead.
+var OneshotReadyStates = []ReadyState{
+	{Active: "active", Sub: "exited"},
+	{Active: "inactive", Sub: "dead"},
+}
+
+// unitReadyStates returns the ready states given for the unit file of the
+// given unit in the request. The returned bool is false in case the request
+// defines no ready states for the unit.
+func (r Request) unitReadyStates(name string) ([]ReadyState, bool) {
+	sliceID, _ := common.SliceID(name)
+	if sliceID != "" {
+		name = strings.Replace(name, "@"+sliceID+".", "@.", 1)
+	}
+	states, ok := r.ReadyStates[name]
+
+	return states, ok
+}
+
+// UnitReady checks whether the given unit is in one of the ready states the
+// request defines for its unit file on all of its machines, e.g. a oneshot
+// unit that exited successfully, see OneshotReadyStates. Units the request
+// defines no ready states for are never ready this way, their aggregated
+// status decides instead.
+func (r Request) UnitReady(us fleet.UnitStatus) bool {
+	states, ok := r.unitReadyStates(us.Name)
+	if !ok {
+		return false
+	}
+
+	return unitIsReady(us, states)
+}
+
+// readyStates returns the ready states of the given unit. Ready states given
+// for the unit file in the request win over Config.ReadyStates. Nil is
+// returned in case the unit's aggregated status decides about readiness.
+func (c controller) readyStates(req Request, name string) []ReadyState {
+	if states, ok := req.unitReadyStates(name); ok {
+		return states
+	}
+
+	return c.Config.ReadyStates
+}
+
+// unitHasStatus works like Aggregator.UnitHasStatus. In case the given
+// statuses contain StatusRunning and ready states are configured for the
+// unit, these decide whether the unit is running, instead of its aggregated
+// status.
+func (c controller) unitHasStatus(aggregator Aggregator, req Request, us fleet.UnitStatus, statuses []Status) (bool, error) {
+	states := c.readyStates(req, us.Name)
+	if len(states) == 0 || !containsStatus(statuses, StatusRunning) {
+		ok, err := aggregator.UnitHasStatus(us, statuses...)
+		if err != nil {
+			return false, maskAny(err)
+		}
+		return ok, nil
+	}
+
+	if unitIsReady(us, states) {
+		return true, nil
+	}
+
+	var others []Status
+	for _, status := range statuses {
+		if status != StatusRunning {
+			others = append(others, status)
+		}
+	}
+	if len(others) == 0 {
+		return false, nil
+	}
+	ok, err := aggregator.UnitHasStatus(us, others...)
+	if err != nil {
+		return false, maskAny(err)
+	}
+
+	return ok, nil
+}
+
+// unitIsReady checks whether the given unit is scheduled and in one of the
+// given ready states on all of its machines.
+func unitIsReady(us fleet.UnitStatus, states []ReadyState) bool {
+	if len(us.Machine) == 0 {
+		return false
+	}
+
+	for _, ms := range us.Machine {
+		ready := false
+		for _, state := range states {
+			if state.matches(ms) {
+				ready = true
+				break
+			}
+		}
+		if !ready {
+			return false
+		}
+	}
+
+	return true
+}
+
+// withReadyStatesGroup returns a copy of the given ready states where the
+// group prefix of all unit file names is replaced, the same way the names of
+// the units themselves are replaced by WithVersion and WithGroup.
+func withReadyStatesGroup(readyStates map[string][]ReadyState, oldGroup, newGroup string) map[string][]ReadyState {
+	if readyStates == nil {
+		return nil
+	}
+
+	newReadyStates := map[string][]ReadyState{}
+	for name, states := range readyStates {
+		newReadyStates[newGroup+strings.TrimPrefix(name, oldGroup)] = states
+	}
+
+	return newReadyStates
+}
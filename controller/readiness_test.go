@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func TestParseReadyStates(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected []ReadyState
+		Error    bool
+	}{
+		{
+			Input:    "active/running",
+			Expected: []ReadyState{{Active: "active", Sub: "running"}},
+		},
+		{
+			Input:    "active/exited, inactive/dead",
+			Expected: []ReadyState{{Active: "active", Sub: "exited"}, {Active: "inactive", Sub: "dead"}},
+		},
+		{
+			Input:    "active",
+			Expected: []ReadyState{{Active: "active"}},
+		},
+		{Input: "", Error: true},
+		{Input: "active/", Error: true},
+		{Input: "/running", Error: true},
+		{Input: "active/running/foo", Error: true},
+	}
+
+	for i, testCase := range testCases {
+		states, err := ParseReadyStates(testCase.Input)
+		if testCase.Error {
+			if !IsInvalidRequest(err) {
+				t.Fatal("case", i+1, "expected", "invalid request error", "got", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if !reflect.DeepEqual(states, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", states)
+		}
+	}
+}
+
+func TestController_unitHasStatus(t *testing.T) {
+	c := controller{Config: Config{ReadyStates: []ReadyState{{Active: "active", Sub: "running"}}}}
+	req := Request{
+		RequestConfig: RequestConfig{Group: "foo"},
+		ReadyStates: map[string][]ReadyState{
+			"foo-init@.service": {{Active: "inactive", Sub: "dead"}},
+		},
+	}
+	aggregator := Aggregator{Logger: DefaultConfig().Logger}
+
+	testCases := []struct {
+		Name     string
+		Active   string
+		Sub      string
+		Statuses []Status
+		Expected bool
+	}{
+		{Name: "foo-main@1.service", Active: "active", Sub: "running", Statuses: []Status{StatusRunning}, Expected: true},
+		{Name: "foo-main@1.service", Active: "active", Sub: "exited", Statuses: []Status{StatusRunning}, Expected: false},
+		{Name: "foo-init@1.service", Active: "inactive", Sub: "dead", Statuses: []Status{StatusRunning}, Expected: true},
+		{Name: "foo-init@1.service", Active: "active", Sub: "running", Statuses: []Status{StatusRunning}, Expected: false},
+		{Name: "foo-main@1.service", Active: "inactive", Sub: "dead", Statuses: []Status{StatusRunning, StatusStopped}, Expected: true},
+	}
+
+	for i, testCase := range testCases {
+		us := fleet.UnitStatus{
+			Name:    testCase.Name,
+			Current: "launched",
+			Desired: "launched",
+			Machine: []fleet.MachineStatus{{ID: "m1", SystemdActive: testCase.Active, SystemdSub: testCase.Sub}},
+		}
+		ok, err := c.unitHasStatus(aggregator, req, us, testCase.Statuses)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", ok)
+		}
+	}
+
+	versioned := req.WithVersion("1a2b3c")
+	if _, ok := versioned.ReadyStates["foo-1a2b3c-init@.service"]; !ok {
+		t.Fatal("expected", "foo-1a2b3c-init@.service", "got", versioned.ReadyStates)
+	}
+}
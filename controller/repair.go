@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"sort"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/task"
+)
+
+const (
+	// RepairReasonMachineVanished is the reason of slices having a unit
+	// scheduled on a machine that left the cluster.
+	RepairReasonMachineVanished = "machine vanished"
+
+	// RepairReasonUnitDead is the reason of slices having a unit that is
+	// supposed to run, but whose systemd state is dead or failed.
+	RepairReasonUnitDead = "unit dead"
+)
+
+// DamagedSlice describes a slice of a group that needs to be repaired. See
+// Controller.DamagedSlices.
+type DamagedSlice struct {
+	// SliceID is the ID of the damaged slice. It is empty for groups without
+	// slices.
+	SliceID string
+
+	// MachineID is the ID of the machine the damaged unit of the slice is
+	// scheduled on.
+	MachineID string
+
+	// Reason describes why the slice needs to be repaired, e.g.
+	// RepairReasonMachineVanished.
+	Reason string
+}
+
+func (c controller) DamagedSlices(ctx context.Context, req Request) ([]DamagedSlice, error) {
+	c.Config.Logger.Debug(ctx, "controller: fetching damaged slices of group '%s'", req.Group)
+
+	unitStatusList, err := c.groupStatus(ctx, req)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	// Units being in one of their ready states are healthy, e.g. oneshot units
+	// that exited successfully, unless their machine left the cluster.
+	var checked []fleet.UnitStatus
+	for _, us := range unitStatusList {
+		if unitIsReady(us, c.readyStates(req, us.Name)) && !unitVanished(us) {
+			continue
+		}
+		checked = append(checked, us)
+	}
+
+	return damagedSlices(checked, req.SliceIDs), nil
+}
+
+// unitVanished checks whether the given unit is scheduled on a machine that
+// left the cluster.
+func unitVanished(us fleet.UnitStatus) bool {
+	for _, ms := range us.Machine {
+		if ms.Vanished {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c controller) Repair(ctx context.Context, req Request) (*task.Task, error) {
+	c.Config.Logger.Debug(ctx, "controller: handling repair")
+
+	action := func(ctx context.Context) error {
+		c.Config.Logger.Debug(ctx, "action: fetching damaged slices")
+		damaged, err := c.DamagedSlices(ctx, req)
+		if err != nil {
+			return maskAny(err)
+		}
+		if len(damaged) == 0 {
+			return nil
+		}
+
+		// The slices are submitted again using the unit files deployed to the
+		// cluster, not the local ones, so that repairing never updates a group.
+		repairReq := req
+		repairReq.SliceIDs = nil
+		repairReq.Units, err = c.DeployedUnits(ctx, req)
+		if err != nil {
+			return maskAny(err)
+		}
+		for _, d := range damaged {
+			// Groups without slices are repaired as a whole.
+			if d.SliceID != "" && !contains(repairReq.SliceIDs, d.SliceID) {
+				repairReq.SliceIDs = append(repairReq.SliceIDs, d.SliceID)
+			}
+		}
+
+		c.Config.Logger.Debug(ctx, "action: rescheduling slices %v", repairReq.SliceIDs)
+		// Repairs keep the group as it is deployed, so there is nothing to undo.
+		ctx = withoutUndo(ctx)
+		steps := []func(ctx context.Context, req Request) (*task.Task, error){
+			c.Destroy,
+			c.Submit,
+			c.Start,
+		}
+		for _, step := range steps {
+			if err := c.executeTaskAction(step, ctx, repairReq); err != nil {
+				return maskAny(err)
+			}
+		}
+
+		return nil
+	}
+
+	taskObject, err := c.createTask(ctx, "repair", req, action)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return taskObject, nil
+}
+
+// damagedSlices returns the damaged slices of the given unit states, sorted by
+// slice ID. Each slice is reported once, using the first damaged unit found.
+// In case slice IDs are given, only those slices are considered.
+func damagedSlices(unitStatusList []fleet.UnitStatus, sliceIDs []string) []DamagedSlice {
+	var damaged []DamagedSlice
+	seen := map[string]struct{}{}
+	for _, us := range unitStatusList {
+		if len(sliceIDs) > 0 && !contains(sliceIDs, us.SliceID) {
+			continue
+		}
+		if _, ok := seen[us.SliceID]; ok {
+			continue
+		}
+
+		for _, ms := range us.Machine {
+			reason := damageReason(us, ms)
+			if reason == "" {
+				continue
+			}
+			damaged = append(damaged, DamagedSlice{SliceID: us.SliceID, MachineID: ms.ID, Reason: reason})
+			seen[us.SliceID] = struct{}{}
+			break
+		}
+	}
+	sort.Sort(damagedSlicesByID(damaged))
+
+	return damaged
+}
+
+// damageReason returns the reason the given unit on the given machine needs to
+// be repaired, or an empty string in case it is healthy. Units not supposed to
+// run, e.g. stopped units or units only loaded to be triggered by timers, are
+// never considered dead.
+func damageReason(us fleet.UnitStatus, ms fleet.MachineStatus) string {
+	if ms.Vanished {
+		return RepairReasonMachineVanished
+	}
+	if us.Desired != "launched" {
+		return ""
+	}
+	if ms.SystemdActive == "failed" || ms.SystemdSub == "dead" || ms.SystemdSub == "failed" {
+		return RepairReasonUnitDead
+	}
+
+	return ""
+}
+
+type damagedSlicesByID []DamagedSlice
+
+func (d damagedSlicesByID) Len() int           { return len(d) }
+func (d damagedSlicesByID) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d damagedSlicesByID) Less(i, j int) bool { return d[i].SliceID < d[j].SliceID }
@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/task"
+)
+
+func TestDamagedSlices(t *testing.T) {
+	unitStatusList := []fleet.UnitStatus{
+		{Name: "foo-main@1.service", SliceID: "1", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m1", SystemdActive: "active", SystemdSub: "running"}}},
+		{Name: "foo-side@1.service", SliceID: "1", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m1", SystemdActive: "active", SystemdSub: "running"}}},
+		{Name: "foo-main@2.service", SliceID: "2", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m2", SystemdActive: "active", SystemdSub: "running", Vanished: true}}},
+		{Name: "foo-side@2.service", SliceID: "2", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m2", SystemdActive: "active", SystemdSub: "running", Vanished: true}}},
+		{Name: "foo-main@3.service", SliceID: "3", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m3", SystemdActive: "active", SystemdSub: "running"}}},
+		{Name: "foo-side@3.service", SliceID: "3", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m3", SystemdActive: "failed", SystemdSub: "failed"}}},
+		{Name: "foo-main@4.service", SliceID: "4", Desired: "loaded", Machine: []fleet.MachineStatus{{ID: "m1", SystemdActive: "inactive", SystemdSub: "dead"}}},
+		{Name: "foo-main@5.service", SliceID: "5", Desired: "launched"},
+	}
+
+	testCases := []struct {
+		SliceIDs []string
+		Expected []DamagedSlice
+	}{
+		{
+			SliceIDs: nil,
+			Expected: []DamagedSlice{
+				{SliceID: "2", MachineID: "m2", Reason: RepairReasonMachineVanished},
+				{SliceID: "3", MachineID: "m3", Reason: RepairReasonUnitDead},
+			},
+		},
+		{
+			SliceIDs: []string{"1", "3"},
+			Expected: []DamagedSlice{
+				{SliceID: "3", MachineID: "m3", Reason: RepairReasonUnitDead},
+			},
+		},
+		{
+			SliceIDs: []string{"1", "4", "5"},
+			Expected: nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		damaged := damagedSlices(unitStatusList, testCase.SliceIDs)
+		if !reflect.DeepEqual(damaged, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", damaged)
+		}
+	}
+}
+
+func TestController_Repair_DummyFleet(t *testing.T) {
+	controller, dummyFleet := getTestController()
+	ctx := context.Background()
+
+	for _, name := range []string{"foo-main@1.service", "foo-main@2.service"} {
+		if err := dummyFleet.Submit(ctx, name, "main content"); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		if err := dummyFleet.Start(ctx, name); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+	lost := dummyFleet.Units["foo-main@2.service"]
+	lost.Machine[0].Vanished = true
+	dummyFleet.Units["foo-main@2.service"] = lost
+
+	req := Request{RequestConfig: RequestConfig{Group: "foo"}}
+	damaged, err := controller.DamagedSlices(ctx, req)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	expected := []DamagedSlice{{SliceID: "2", Reason: RepairReasonMachineVanished}}
+	if !reflect.DeepEqual(damaged, expected) {
+		t.Fatal("expected", expected, "got", damaged)
+	}
+
+	taskObject, err := controller.Repair(ctx, req)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	taskObject, err = controller.WaitForTask(ctx, taskObject.ID, nil)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if task.HasFailedStatus(taskObject) {
+		t.Fatal("expected", task.StatusSucceeded, "got", taskObject.Error)
+	}
+
+	damaged, err = controller.DamagedSlices(ctx, req)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(damaged) != 0 {
+		t.Fatal("expected", 0, "got", damaged)
+	}
+	if content := dummyFleet.Contents["foo-main@2.service"]; content != "main content" {
+		t.Fatal("expected", "main content", "got", content)
+	}
+}
+
+func TestController_DamagedSlices_ReadyStates(t *testing.T) {
+	unitStatusList := []fleet.UnitStatus{
+		{Name: "foo-init@1.service", SliceID: "1", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m1", SystemdActive: "inactive", SystemdSub: "dead"}}},
+		{Name: "foo-main@1.service", SliceID: "1", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m1", SystemdActive: "active", SystemdSub: "running"}}},
+		{Name: "foo-init@2.service", SliceID: "2", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m2", SystemdActive: "failed", SystemdSub: "failed"}}},
+		{Name: "foo-main@2.service", SliceID: "2", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m2", SystemdActive: "active", SystemdSub: "running"}}},
+		{Name: "foo-init@3.service", SliceID: "3", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m3", SystemdActive: "inactive", SystemdSub: "dead", Vanished: true}}},
+		{Name: "foo-main@3.service", SliceID: "3", Desired: "launched", Machine: []fleet.MachineStatus{{ID: "m3", SystemdActive: "active", SystemdSub: "running", Vanished: true}}},
+	}
+
+	req := Request{RequestConfig: RequestConfig{Group: "foo"}}
+	req.ReadyStates = map[string][]ReadyState{"foo-init@.service": OneshotReadyStates}
+
+	// Oneshot units that exited successfully are not dead, unless their machine
+	// vanished. Failed oneshot units are.
+	expected := []DamagedSlice{
+		{SliceID: "2", MachineID: "m2", Reason: RepairReasonUnitDead},
+		{SliceID: "3", MachineID: "m3", Reason: RepairReasonMachineVanished},
+	}
+
+	controller, fleetMock := givenController()
+	fleetMock.On("GetStatusWithMatcher", mock.AnythingOfType("func(string) bool")).Return(unitStatusList, nil).Once()
+	damaged, err := controller.DamagedSlices(context.Background(), req)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if !reflect.DeepEqual(damaged, expected) {
+		t.Fatal("expected", expected, "got", damaged)
+	}
+
+	if !req.UnitReady(unitStatusList[0]) {
+		t.Fatal("expected", true, "got", false)
+	}
+	if req.UnitReady(unitStatusList[1]) || req.UnitReady(unitStatusList[2]) {
+		t.Fatal("expected", false, "got", true)
+	}
+}
+
+func TestController_Repair_EnvFile(t *testing.T) {
+	controller, dummyFleet := getTestController()
+	ctx := context.Background()
+
+	envUnit, err := NewEnvUnit("foo", "foo-main@.env", "SLICE={{.SliceID}}\n")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	req := Request{
+		RequestConfig: RequestConfig{Group: "foo", SliceIDs: []string{"1", "2"}},
+		Units: []Unit{
+			envUnit,
+			{Name: "foo-main@.service", Content: "[Service]\nEnvironmentFile=/run/inago/foo-main@%i.env\nExecStart=/bin/main\n"},
+		},
+	}
+	extended, err := req.ExtendSlices()
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	for _, u := range extended.Units {
+		if err := dummyFleet.Submit(ctx, u.Name, u.Content); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		if err := dummyFleet.Start(ctx, u.Name); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+	expected := dummyFleet.Contents["foo-main-env@2.service"]
+
+	// Units fetched from the cluster are the ones of slice 1, but the
+	// environment file of slice 2 is rendered for slice 2 when repairing it.
+	for _, name := range []string{"foo-main-env@2.service", "foo-main@2.service"} {
+		lost := dummyFleet.Units[name]
+		lost.Machine[0].Vanished = true
+		dummyFleet.Units[name] = lost
+	}
+	taskObject, err := controller.Repair(ctx, Request{RequestConfig: RequestConfig{Group: "foo"}})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	taskObject, err = controller.WaitForTask(ctx, taskObject.ID, nil)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if task.HasFailedStatus(taskObject) {
+		t.Fatal("expected", task.StatusSucceeded, "got", taskObject.Error)
+	}
+
+	if content := dummyFleet.Contents["foo-main-env@2.service"]; content != expected {
+		t.Fatal("expected", expected, "got", content)
+	}
+	if dummyFleet.Units["foo-main-env@2.service"].Machine[0].Vanished {
+		t.Fatal("expected", "slice 2 to be repaired", "got", "vanished unit")
+	}
+}
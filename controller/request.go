@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"golang.org/x/net/context"
 
+	"github.com/giantswarm/inago/common"
 	"github.com/giantswarm/inago/fleet"
 )
 
@@ -39,9 +41,57 @@ type Request struct {
 	// using the provided slice IDs.
 	Units []Unit
 
-	// DesiredSlices defines the number of random sliceIDs that should be generated
-	// when submitting new groups.
+	// DesiredSlices defines the number of sliceIDs that should be generated
+	// when submitting new groups. See Config.SliceIDRule.
 	DesiredSlices int
+
+	// ReadyStates defines the systemd states units are considered running in
+	// while waiting for them to start, keyed by unit file name, e.g.
+	// "mygroup-init@.service". Units not contained use Config.ReadyStates.
+	ReadyStates map[string][]ReadyState
+
+	// Retry defines whether slices not running after being started are
+	// started again. See RetryOptions.
+	Retry RetryOptions
+
+	// MaxFailures is the number of slices allowed to fail while starting or
+	// updating the group, before the operation stops and fails. Failed slices
+	// within the budget are reported, but do not fail the operation. See
+	// FailureBudget.
+	MaxFailures FailureBudget
+
+	// Stagger is the delay between launching two slices, so that large groups
+	// do not start all at once, overwhelming shared services like databases or
+	// registries. It applies to starting slices, and to updates cycling slices
+	// in parallel. Zero launches slices without delay.
+	Stagger time.Duration
+
+	// Placement contains machine metadata selectors like "role=canary" the
+	// units are scheduled according to. They are injected into the units as
+	// MachineMetadata option at submit time, without changing the unit files.
+	// See ParsePlacement.
+	Placement []string
+
+	// Signed defines whether the definition of the group, i.e. its unit files
+	// and manifest, has been verified using a trusted signature. Policies can
+	// require groups to be signed, see policy.Rule.RequireSignature.
+	Signed bool
+
+	// Match selects the units of the request by matching their names, instead
+	// of by Group and the slice IDs they carry, e.g. to manage units created
+	// before following Inago's naming conventions. Group is then only used to
+	// refer to the selected units, e.g. in logs. See ParseUnitMatch.
+	Match *regexp.Regexp
+
+	// ExcludeVersions are versions of the group whose units do not belong to
+	// the request, although their names are prefixed with Group, e.g. to
+	// replace a group deployed without version by a versioned one. See
+	// VersionedGroup and Controller.ExistingVersions.
+	ExcludeVersions []string
+
+	// WaitTimeout is the maximum time to wait for the group to reach a status
+	// using Controller.WaitForStatus. Zero means Config.WaitTimeout.
+	WaitTimeout time.Duration
 }
 
 // NewRequest returns a Request, given a RequestConfig.
@@ -77,8 +127,21 @@ func (r Request) isSliceable() bool {
 // 	 foo@2.service
 // 	 bar@2.service
 //
+// The content of units created using NewEnvUnit is rendered for each slice.
 func (r Request) ExtendSlices() (Request, error) {
 	if len(r.SliceIDs) == 0 {
+		var newUnits []Unit
+		for _, unit := range r.Units {
+			newUnit := unit
+			content, err := unit.envUnitContent(r.Group, "")
+			if err != nil {
+				return Request{}, maskAny(err)
+			}
+			newUnit.Content = content
+			newUnits = append(newUnits, newUnit)
+		}
+		r.Units = newUnits
+
 		return r, nil
 	}
 
@@ -88,6 +151,11 @@ func (r Request) ExtendSlices() (Request, error) {
 			newUnit := unit
 			// TODO fix extension
 			newUnit.Name = unitExp.ReplaceAllString(newUnit.Name, fmt.Sprintf("@%s.", sliceID))
+			content, err := unit.envUnitContent(r.Group, sliceID)
+			if err != nil {
+				return Request{}, maskAny(err)
+			}
+			newUnit.Content = content
 			newUnits = append(newUnits, newUnit)
 		}
 	}
@@ -157,27 +225,17 @@ func (c controller) ExtendWithRandomSliceIDs(ctx context.Context, req Request) (
 	}
 
 	// Find enough sufficient IDs.
-	var newIDs []string
-	for i := 0; i < req.DesiredSlices; i++ {
-		for {
-			newID := NewID()
-
-			ok, err := containsUnitStatusSliceID(usl, newID)
-			if err != nil {
-				return Request{}, maskAny(err)
-			}
-			if ok {
-				// We already have this ID in the group. Try again.
-				continue
-			}
-			if contains(newIDs, newID) {
-				// We already created this ID. Try again.
-				continue
-			}
-
-			newIDs = append(newIDs, newID)
-			break
+	var usedIDs []string
+	for _, us := range usl {
+		ID, err := common.SliceID(us.Name)
+		if err != nil {
+			return Request{}, maskAny(err)
 		}
+		usedIDs = append(usedIDs, ID)
+	}
+	newIDs, err := c.Config.SliceIDRule.NewIDs(req.DesiredSlices, usedIDs)
+	if err != nil {
+		return Request{}, maskAny(err)
 	}
 	req.SliceIDs = newIDs
 	req.DesiredSlices = 0
@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// RetryOptions define how starting slices is retried. Units failing to start
+// are often caused by transient problems, e.g. a failing docker pull, which
+// are fixed by simply starting them again.
+type RetryOptions struct {
+	// Attempts is the number of times slices not running are started again,
+	// before the operation fails. Zero disables retries.
+	Attempts int
+
+	// Window is the time slices have to reach StatusRunning, before they are
+	// started again. Zero means Config.WaitTimeout.
+	Window time.Duration
+
+	// Resubmit defines whether slices are destroyed and submitted again before
+	// being started again, so that fleet may schedule them on other machines.
+	// Otherwise they are stopped and started again on the same machines.
+	Resubmit bool
+}
+
+// waitForRunning waits for the units of the given request to be running. In
+// case req.Retry allows to, slices not running within the retry window are
+// started again, and waited for again. In case req.MaxFailures allows slices
+// to fail, failed slices are not waited for, and only fail the operation in
+// case they exceed the budget. See checkFailureBudget.
+func (c controller) waitForRunning(ctx context.Context, req Request) error {
+	closer := make(chan struct{})
+	statuses := []Status{StatusRunning}
+	if !req.MaxFailures.IsZero() {
+		statuses = append(statuses, StatusFailed)
+	}
+	if req.Retry.Attempts <= 0 && req.MaxFailures.IsZero() {
+		if err := c.WaitForStatus(ctx, req, closer, statuses...); err != nil {
+			return maskAny(err)
+		}
+		return nil
+	}
+
+	waiter := c
+	if req.Retry.Attempts > 0 && req.Retry.Window > 0 {
+		waiter.Config.WaitTimeout = req.Retry.Window
+	}
+	started := req
+	for attempt := 1; ; attempt++ {
+		waitErr := waiter.WaitForStatus(ctx, req, closer, statuses...)
+		if waitErr != nil && (!IsWaitTimeoutReached(waitErr) || attempt > req.Retry.Attempts) {
+			return maskAny(waitErr)
+		}
+		if waitErr == nil && req.MaxFailures.IsZero() {
+			return nil
+		}
+
+		sliceIDs, err := c.notRunningSlices(ctx, req)
+		if err != nil {
+			return maskAny(err)
+		}
+		if len(sliceIDs) == 0 {
+			if waitErr == nil {
+				return nil
+			}
+			continue
+		}
+		if attempt > req.Retry.Attempts {
+			// All slices are either running or failed.
+			return maskAny(c.failedSlicesResult(ctx, started, sliceIDs))
+		}
+		c.Config.Logger.Info(ctx, "Retrying to start slices %v of group '%s' (attempt %d of %d).", sliceIDs, req.Group, attempt, req.Retry.Attempts)
+
+		// Only the slices started again need to be waited for. Groups without
+		// slices are started again as a whole.
+		if sliceIDs[0] != "" {
+			req.SliceIDs = sliceIDs
+		}
+		if err := c.restartSlices(ctx, req); err != nil {
+			return maskAny(err)
+		}
+	}
+}
+
+// failedSlicesResult checks the given failed slices of the given request
+// against its failure budget. See checkFailureBudget.
+func (c controller) failedSlicesResult(ctx context.Context, req Request, failed []string) error {
+	if len(req.SliceIDs) == 0 {
+		var err error
+		req, err = c.ExtendWithExistingSliceIDs(req)
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+
+	var result MultiSliceError
+	for _, sliceID := range req.SliceIDs {
+		if contains(failed, sliceID) {
+			result.Failed = append(result.Failed, SliceError{SliceID: sliceID, Err: maskAnyf(sliceNotRunningError, "slice '%s'", sliceID)})
+		} else {
+			result.Succeeded = append(result.Succeeded, sliceID)
+		}
+	}
+	if len(result.Failed) == 0 {
+		// Groups without slices.
+		result.Failed = []SliceError{{Err: maskAny(sliceNotRunningError)}}
+	}
+
+	return maskAny(c.checkFailureBudget(ctx, req, result))
+}
+
+// notRunningSlices returns the IDs of the slices of the given request having
+// units that are not running. For groups without slices, a single empty ID is
+// returned in case the group is not running.
+func (c controller) notRunningSlices(ctx context.Context, req Request) ([]string, error) {
+	unitStatusList, err := c.groupStatus(ctx, req)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	aggregator := Aggregator{
+		Logger: c.Config.Logger,
+	}
+	triggered := triggeredUnits(unitStatusList)
+	var sliceIDs []string
+	for _, us := range unitStatusList {
+		if _, ok := triggered[us.Name]; ok || contains(sliceIDs, us.SliceID) {
+			continue
+		}
+		ok, err := c.unitHasStatus(aggregator, req, us, []Status{StatusRunning})
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		if !ok {
+			sliceIDs = append(sliceIDs, us.SliceID)
+		}
+	}
+
+	return sliceIDs, nil
+}
+
+// restartSlices stops, or destroys and submits, the slices of the given
+// request, depending on req.Retry.Resubmit, and starts them again.
+func (c controller) restartSlices(ctx context.Context, req Request) error {
+	resubmit := req.Retry.Resubmit
+	// The operations executed here must not retry on their own, and restarting
+	// slices is nothing to undo.
+	req.Retry = RetryOptions{}
+	ctx = withoutUndo(ctx)
+
+	if resubmit {
+		// Like repairs, slices are submitted again using the unit files deployed
+		// to the cluster, not the local ones.
+		units, err := c.DeployedUnits(ctx, req)
+		if err != nil {
+			return maskAny(err)
+		}
+		req.Units = units
+		if err := c.executeTaskAction(c.Destroy, ctx, req); err != nil {
+			return maskAny(err)
+		}
+		if err := c.executeTaskAction(c.Submit, ctx, req); err != nil {
+			return maskAny(err)
+		}
+	} else {
+		if err := c.executeTaskAction(c.Stop, ctx, req); err != nil {
+			return maskAny(err)
+		}
+	}
+
+	unitStatusList, err := c.groupStatus(ctx, req)
+	if err != nil {
+		return maskAny(err)
+	}
+	result, err := c.startUnits(ctx, req, unitStatusList)
+	if err != nil {
+		return maskAny(err)
+	}
+	if result.HasFailed() {
+		return maskAny(result)
+	}
+
+	return nil
+}
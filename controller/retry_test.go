@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/task"
+)
+
+// flakyFleet is a DummyFleet whose units fail the given number of times when
+// being started.
+type flakyFleet struct {
+	*fleet.DummyFleet
+	Failures int
+}
+
+func (f *flakyFleet) Start(ctx context.Context, name string) error {
+	if err := f.DummyFleet.Start(ctx, name); err != nil {
+		return err
+	}
+
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+	if f.Failures > 0 {
+		f.Failures--
+		unitStatus := f.Units[name]
+		unitStatus.Machine = []fleet.MachineStatus{{SystemdActive: "failed", SystemdSub: "failed"}}
+		f.Units[name] = unitStatus
+	}
+
+	return nil
+}
+
+func TestController_Start_Retry(t *testing.T) {
+	testCases := []struct {
+		Failures int
+		Retry    RetryOptions
+		Error    bool
+	}{
+		{Failures: 1, Retry: RetryOptions{Attempts: 1, Window: time.Second}, Error: false},
+		{Failures: 1, Retry: RetryOptions{Attempts: 1, Window: time.Second, Resubmit: true}, Error: false},
+		{Failures: 2, Retry: RetryOptions{Attempts: 1, Window: time.Second}, Error: true},
+	}
+
+	for i, testCase := range testCases {
+		controller, dummyFleet := getTestController()
+		controller.Config.WaitTimeout = time.Second
+		controller.Config.Fleet = &flakyFleet{DummyFleet: dummyFleet, Failures: testCase.Failures}
+		ctx := context.Background()
+
+		req := Request{
+			RequestConfig: RequestConfig{Group: "foo", SliceIDs: []string{"1"}},
+			Units:         []Unit{{Name: "foo-main@.service", Content: "[Unit]\nDescription=main\n"}},
+			Retry:         testCase.Retry,
+		}
+		if err := controller.executeTaskAction(controller.Submit, ctx, req); err != nil {
+			t.Fatal("case", i, "expected", nil, "got", err)
+		}
+
+		taskObject, err := controller.Start(ctx, req)
+		if err != nil {
+			t.Fatal("case", i, "expected", nil, "got", err)
+		}
+		taskObject, err = controller.WaitForTask(ctx, taskObject.ID, nil)
+		if err != nil {
+			t.Fatal("case", i, "expected", nil, "got", err)
+		}
+		if task.HasFailedStatus(taskObject) != testCase.Error {
+			t.Fatal("case", i, "expected", testCase.Error, "got", taskObject.Error)
+		}
+		if testCase.Error && !IsWaitTimeoutReached(taskObject.Error) {
+			t.Fatal("case", i, "expected", "wait timeout reached error", "got", taskObject.Error)
+		}
+	}
+}
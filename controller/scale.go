@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/state"
+)
+
+// desiredScaleNamespace is the namespace of the state store desired scales
+// are stored in.
+const desiredScaleNamespace = "scale"
+
+// desiredScale is stored for each group having a desired scale.
+type desiredScale struct {
+	Slices  int       `json:"slices"`
+	Updated time.Time `json:"updated"`
+}
+
+func (c controller) DesiredScale(ctx context.Context, group string) (int, error) {
+	c.Config.Logger.Debug(ctx, "controller: looking up desired scale of group '%s'", group)
+
+	if c.Config.StateStore == nil {
+		return 0, maskAnyf(desiredScaleNotFoundError, "no state store configured")
+	}
+
+	var scale desiredScale
+	err := c.Config.StateStore.Get(desiredScaleNamespace, group, &scale)
+	if state.IsNotFound(err) {
+		return 0, maskAnyf(desiredScaleNotFoundError, "%s", group)
+	} else if state.IsInvalidKey(err) {
+		return 0, maskAnyf(invalidArgumentError, "group: %s", err.Error())
+	} else if err != nil {
+		return 0, maskAny(err)
+	}
+
+	return scale.Slices, nil
+}
+
+func (c controller) SetDesiredScale(ctx context.Context, group string, slices int) error {
+	c.Config.Logger.Debug(ctx, "controller: setting desired scale of group '%s' to %d", group, slices)
+
+	if slices < 1 {
+		return maskAnyf(invalidArgumentError, "desired scale must be positive, got %d", slices)
+	}
+	if c.Config.StateStore == nil {
+		return nil
+	}
+
+	scale := desiredScale{
+		Slices:  slices,
+		Updated: time.Now(),
+	}
+	err := c.Config.StateStore.Set(desiredScaleNamespace, group, scale)
+	if state.IsInvalidKey(err) {
+		return maskAnyf(invalidArgumentError, "group: %s", err.Error())
+	} else if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/state"
+)
+
+func TestController_DesiredScale(t *testing.T) {
+	controller, _ := getTestController()
+	ctx := context.Background()
+
+	// Without state store nothing is recorded.
+	if err := controller.SetDesiredScale(ctx, "foo", 3); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if _, err := controller.DesiredScale(ctx, "foo"); !IsDesiredScaleNotFound(err) {
+		t.Fatal("expected", "desired scale not found error", "got", err)
+	}
+
+	controller.Config.StateStore = state.NewMemoryStore()
+	if _, err := controller.DesiredScale(ctx, "foo"); !IsDesiredScaleNotFound(err) {
+		t.Fatal("expected", "desired scale not found error", "got", err)
+	}
+	if err := controller.SetDesiredScale(ctx, "foo", 3); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	scale, err := controller.DesiredScale(ctx, "foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if scale != 3 {
+		t.Fatal("expected", 3, "got", scale)
+	}
+
+	if err := controller.SetDesiredScale(ctx, "foo", 0); !IsInvalidRequest(err) {
+		t.Fatal("expected", "invalid request error", "got", err)
+	}
+	if _, err := controller.DesiredScale(ctx, "foo/bar"); !IsInvalidRequest(err) {
+		t.Fatal("expected", "invalid request error", "got", err)
+	}
+}
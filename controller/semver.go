@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/fleet/unit"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+var semVerExp = regexp.MustCompile(`^v?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// SemVer is a semantic version as described by http://semver.org. Build
+// metadata is ignored.
+type SemVer struct {
+	Major int
+	Minor int
+	Patch int
+
+	// Pre is the pre-release identifier, e.g. "rc.1". Versions having a
+	// pre-release identifier are lower than the same version without one.
+	Pre string
+}
+
+// ParseSemVer parses the given semantic version, optionally prefixed with
+// "v". In case the version is malformed, an error that you can identify using
+// IsInvalidRequest is returned.
+//
+//   1.2.3
+//   v1.2.3-rc.1
+//   1.2.3+build.42
+//
+func ParseSemVer(s string) (SemVer, error) {
+	found := semVerExp.FindStringSubmatch(s)
+	if found == nil {
+		return SemVer{}, maskAnyf(invalidArgumentError, "bad semantic version '%s'", s)
+	}
+
+	var numbers [3]int
+	for i := range numbers {
+		n, err := strconv.Atoi(found[i+1])
+		if err != nil {
+			return SemVer{}, maskAnyf(invalidArgumentError, "bad semantic version '%s'", s)
+		}
+		numbers[i] = n
+	}
+
+	return SemVer{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], Pre: found[4]}, nil
+}
+
+// String returns the version the way ParseSemVer accepts it.
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+
+	return s
+}
+
+// Compare returns -1 in case v is lower than o, 1 in case v is higher than o,
+// and 0 in case both are equal.
+func (v SemVer) Compare(o SemVer) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		} else if d > 0 {
+			return 1
+		}
+	}
+
+	return comparePreRelease(v.Pre, o.Pre)
+}
+
+// comparePreRelease compares pre-release identifiers. Dot separated
+// identifiers are compared one by one, numerically in case both are numbers.
+func comparePreRelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil && an < bn:
+			return -1
+		case aErr == nil && bErr == nil:
+			return 1
+		case aErr == nil:
+			// Numeric identifiers are lower than alphanumeric ones.
+			return -1
+		case bErr == nil:
+			return 1
+		case as[i] < bs[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	if len(as) < len(bs) {
+		return -1
+	} else if len(as) > len(bs) {
+		return 1
+	}
+
+	return 0
+}
+
+// WithSemVer returns a copy of r where the given semantic version is added to
+// the content of all units, so that it is stored together with the units in
+// the cluster. Units writing environment files are left alone, like they are
+// by WithLabels. In case the version is malformed, an error that you can
+// identify using IsInvalidRequest is returned.
+//
+//   [X-Inago]
+//   Version=1.2.3
+//
+func (r Request) WithSemVer(version string) (Request, error) {
+	if _, err := ParseSemVer(version); err != nil {
+		return Request{}, maskAny(err)
+	}
+
+	section := fmt.Sprintf("\n[%s]\nVersion=%s\n", LabelSection, version)
+
+	var newUnits []Unit
+	for _, u := range r.Units {
+		if u.EnvFile == "" {
+			u.Content = strings.TrimRight(u.Content, "\n") + "\n" + section
+		}
+		newUnits = append(newUnits, u)
+	}
+	r.Units = newUnits
+
+	return r, nil
+}
+
+// UnitSemVer returns the semantic version stored in the given unit content by
+// Request.WithSemVer, or an empty string for units without version.
+func UnitSemVer(content string) string {
+	unitFile, err := unit.NewUnitFile(content)
+	if err != nil {
+		return ""
+	}
+	versions := unitFile.Contents[LabelSection]["Version"]
+	if len(versions) == 0 {
+		return ""
+	}
+
+	return versions[len(versions)-1]
+}
+
+// requestSemVer returns the semantic version the units of the given request
+// carry, or an empty string in case there is none.
+func requestSemVer(req Request) string {
+	for _, u := range req.Units {
+		if version := UnitSemVer(u.Content); version != "" {
+			return version
+		}
+	}
+
+	return ""
+}
+
+func (c controller) SliceVersions(ctx context.Context, req Request) (map[string]string, error) {
+	c.Config.Logger.Debug(ctx, "controller: fetching slice versions of group '%s'", req.Group)
+
+	unitStatusList, err := c.groupStatus(ctx, req)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	sliceIDs := map[string]string{}
+	var names []string
+	for _, us := range unitStatusList {
+		sliceIDs[us.Name] = us.SliceID
+		names = append(names, us.Name)
+	}
+	sort.Strings(names)
+
+	// All units of a slice carry the same version, so it is enough to find
+	// one unit having a version per slice.
+	versions := map[string]string{}
+	for _, name := range names {
+		sliceID := sliceIDs[name]
+		if versions[sliceID] != "" {
+			continue
+		}
+		content, err := c.Fleet.GetContent(ctx, name)
+		if fleet.IsUnitNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, maskAny(err)
+		}
+		versions[sliceID] = UnitSemVer(content)
+	}
+
+	return versions, nil
+}
+
+// checkDowngrade checks whether the units of the given request carry a
+// semantic version lower than the one of any deployed slice of the group. In
+// that case an error that you can identify using IsDowngradeNotAllowed is
+// returned. Slices without, or with a malformed version are not considered.
+func (c controller) checkDowngrade(ctx context.Context, req Request) error {
+	version := requestSemVer(req)
+	if version == "" {
+		return nil
+	}
+	newVersion, err := ParseSemVer(version)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	versions, err := c.SliceVersions(ctx, req)
+	if err != nil {
+		return maskAny(err)
+	}
+	var sliceIDs []string
+	for sliceID := range versions {
+		sliceIDs = append(sliceIDs, sliceID)
+	}
+	sort.Strings(sliceIDs)
+	for _, sliceID := range sliceIDs {
+		deployed, err := ParseSemVer(versions[sliceID])
+		if err != nil {
+			continue
+		}
+		if newVersion.Compare(deployed) < 0 {
+			return maskAnyf(downgradeNotAllowedError, "slice '%s' of group '%s' runs version %s, which is higher than %s", sliceID, req.Group, deployed, newVersion)
+		}
+	}
+
+	return nil
+}
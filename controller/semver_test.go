@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+func Test_ParseSemVer(t *testing.T) {
+	RegisterTestingT(t)
+
+	v, err := ParseSemVer("v1.2.3-rc.1+build.42")
+	Expect(err).To(BeNil())
+	Expect(v).To(Equal(SemVer{Major: 1, Minor: 2, Patch: 3, Pre: "rc.1"}))
+	Expect(v.String()).To(Equal("1.2.3-rc.1"))
+
+	for _, s := range []string{"", "1.2", "1.2.3.4", "01.2.3", "1.2.x", "latest"} {
+		_, err := ParseSemVer(s)
+		Expect(IsInvalidRequest(err)).To(BeTrue(), s)
+	}
+}
+
+func Test_SemVer_Compare(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Each version is lower than the next one.
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.2.0",
+		"2.0.0",
+	}
+	for i := range ordered {
+		a, err := ParseSemVer(ordered[i])
+		Expect(err).To(BeNil())
+		Expect(a.Compare(a)).To(Equal(0))
+		if i == 0 {
+			continue
+		}
+		b, err := ParseSemVer(ordered[i-1])
+		Expect(err).To(BeNil())
+		Expect(a.Compare(b)).To(Equal(1), ordered[i])
+		Expect(b.Compare(a)).To(Equal(-1), ordered[i])
+	}
+}
+
+func Test_Request_WithSemVer(t *testing.T) {
+	RegisterTestingT(t)
+
+	envUnit, err := NewEnvUnit("app", "app-main@.env", "FOO=bar\n")
+	Expect(err).To(BeNil())
+	req := Request{
+		RequestConfig: RequestConfig{Group: "app"},
+		Units: []Unit{
+			{Name: "app-main@.service", Content: "[Service]\nExecStart=/bin/app\n"},
+			envUnit,
+		},
+	}
+
+	versioned, err := req.WithSemVer("1.4.2")
+	Expect(err).To(BeNil())
+	Expect(UnitSemVer(versioned.Units[0].Content)).To(Equal("1.4.2"))
+	Expect(versioned.Units[1].Content).To(Equal(envUnit.Content))
+	Expect(requestSemVer(versioned)).To(Equal("1.4.2"))
+	Expect(requestSemVer(req)).To(Equal(""))
+
+	// Labels and version share the same section.
+	labeled, err := versioned.WithLabels(map[string]string{"team": "payments"})
+	Expect(err).To(BeNil())
+	group, labels := UnitLabels(labeled.Units[0].Content)
+	Expect(group).To(Equal("app"))
+	Expect(labels).To(Equal(map[string]string{"team": "payments"}))
+	Expect(UnitSemVer(labeled.Units[0].Content)).To(Equal("1.4.2"))
+
+	_, err = req.WithSemVer("latest")
+	Expect(IsInvalidRequest(err)).To(BeTrue())
+}
+
+func TestUpdate_Downgrade(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	c, dummyFleet := getTestController()
+	deployed := "[Service]\nExecStart=/bin/app\n\n[X-Inago]\nVersion=2.0.0\n"
+	Expect(dummyFleet.Submit(ctx, "app-main@1.service", deployed)).To(Succeed())
+	Expect(dummyFleet.Submit(ctx, "app-main@2.service", "[Service]\nExecStart=/bin/app\n")).To(Succeed())
+
+	versions, err := c.SliceVersions(ctx, Request{RequestConfig: RequestConfig{Group: "app"}})
+	Expect(err).To(BeNil())
+	Expect(versions).To(Equal(map[string]string{"1": "2.0.0", "2": ""}))
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "app", SliceIDs: []string{"1", "2"}},
+		Units:         []Unit{{Name: "app-main@.service", Content: "[Service]\nExecStart=/bin/app\n"}},
+	}
+	req, err = req.WithSemVer("1.9.0")
+	Expect(err).To(BeNil())
+	opts := UpdateOptions{MaxGrowth: 1, MinAlive: 0}
+
+	_, err = c.Update(ctx, req, opts)
+	Expect(IsDowngradeNotAllowed(err)).To(BeTrue())
+
+	opts.AllowDowngrade = true
+	_, err = c.Update(ctx, req, opts)
+	Expect(err).To(BeNil())
+
+	// Upgrades are always allowed.
+	req, err = req.WithSemVer("2.1.0")
+	Expect(err).To(BeNil())
+	opts.AllowDowngrade = false
+	_, err = c.Update(ctx, req, opts)
+	Expect(err).To(BeNil())
+}
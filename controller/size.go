@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coreos/fleet/schema"
+	"github.com/coreos/fleet/unit"
+)
+
+const (
+	// MaxUnitSize is the maximum size in bytes a unit may have as stored by
+	// fleet, i.e. its options encoded as JSON. Fleet stores units in etcd,
+	// which rejects large values. Fleet does not tell about this, but answers
+	// with an internal server error.
+	MaxUnitSize = 1024 * 1024
+
+	// MaxUnitOptions is the maximum number of options a unit may have. Each
+	// option is stored separately by fleet and sent on each status request, so
+	// units having more options are most likely generated by mistake.
+	MaxUnitOptions = 1000
+
+	// MaxExecLineLength is the length of ExecStart lines, and the like, above
+	// which a warning is issued. systemd versions shipped with CoreOS read unit
+	// files using a line buffer of this size and fail to load units having
+	// longer lines.
+	MaxExecLineLength = 2048
+)
+
+// validateUnitSize checks whether fleet is able to store the given unit. In
+// case it is too large, an error that you can identify using IsUnitTooLarge
+// is returned. Units that cannot be parsed are not checked.
+func validateUnitSize(u Unit) error {
+	unitFile, err := unit.NewUnitFile(u.Content)
+	if err != nil {
+		return nil
+	}
+
+	options := schema.MapUnitFileToSchemaUnitOptions(unitFile)
+	if len(options) > MaxUnitOptions {
+		return maskAnyf(unitTooLargeError, "%s has %d options, exceeding the limit of %d", u.Name, len(options), MaxUnitOptions)
+	}
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return maskAny(err)
+	}
+	if len(raw) > MaxUnitSize {
+		return maskAnyf(unitTooLargeError, "%s has %d bytes, exceeding the limit of %d", u.Name, len(raw), MaxUnitSize)
+	}
+
+	return nil
+}
+
+// UnitWarnings returns warnings about the given units that fleet accepts, but
+// that are likely to fail when being started, e.g. extremely long ExecStart
+// lines. See MaxExecLineLength.
+func UnitWarnings(units []Unit) []string {
+	var warnings []string
+	for _, u := range units {
+		unitFile, err := unit.NewUnitFile(u.Content)
+		if err != nil {
+			continue
+		}
+
+		var names []string
+		for name := range unitFile.Contents["Service"] {
+			if strings.HasPrefix(name, "Exec") {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			for _, value := range unitFile.Contents["Service"][name] {
+				if length := len(name) + len("=") + len(value); length > MaxExecLineLength {
+					warnings = append(warnings, fmt.Sprintf("%s: %s line has %d characters, systemd may fail to load lines longer than %d", u.Name, name, length, MaxExecLineLength))
+				}
+			}
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidateRequest_UnitTooLarge(t *testing.T) {
+	var manyOptions []string
+	for i := 0; i <= MaxUnitOptions; i++ {
+		manyOptions = append(manyOptions, fmt.Sprintf("Environment=VAR%d=%d", i, i))
+	}
+
+	testCases := []struct {
+		Content string
+		Valid   bool
+	}{
+		{
+			Content: "[Service]\nExecStart=/bin/true\n",
+			Valid:   true,
+		},
+		{
+			Content: "[Service]\n" + strings.Join(manyOptions, "\n") + "\n",
+			Valid:   false,
+		},
+		{
+			Content: "[Service]\nEnvironment=DATA=" + strings.Repeat("x", MaxUnitSize) + "\n",
+			Valid:   false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		req := Request{
+			RequestConfig: RequestConfig{Group: "foo"},
+			Units:         []Unit{{Name: "foo.service", Content: testCase.Content}},
+		}
+		ok, err := ValidateRequest(req)
+		if ok != testCase.Valid {
+			t.Fatal("case", i+1, "expected", testCase.Valid, "got", ok)
+		}
+		if testCase.Valid {
+			continue
+		}
+		validationErr, isValidationErr := err.(ValidationError)
+		if !isValidationErr || !validationErr.Contains(IsUnitTooLarge) {
+			t.Fatal("case", i+1, "expected", "unit too large error", "got", err)
+		}
+	}
+}
+
+func TestUnitWarnings(t *testing.T) {
+	units := []Unit{
+		{Name: "foo-short.service", Content: "[Service]\nExecStart=/bin/true\n"},
+		{Name: "foo-long.service", Content: "[Service]\nExecStartPre=/bin/true\nExecStart=/bin/echo " + strings.Repeat("x", MaxExecLineLength) + "\n"},
+	}
+
+	warnings := UnitWarnings(units)
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "foo-long.service: ExecStart line has") {
+		t.Fatal("expected", "a single warning about foo-long.service", "got", warnings)
+	}
+}
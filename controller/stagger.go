@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+)
+
+// stagger delays launching slices, so that at least Interval passes between
+// two launches. Slices starting all at once may overwhelm shared services, e.g.
+// databases or registries, they all connect to on startup.
+type stagger struct {
+	Interval time.Duration
+
+	last time.Time
+}
+
+// wait blocks until Interval passed since the previous call. The first call
+// returns right away. In case the given context is done before, its error is
+// returned.
+func (s *stagger) wait(ctx context.Context) error {
+	if s.Interval > 0 && !s.last.IsZero() {
+		if d := s.last.Add(s.Interval).Sub(time.Now()); d > 0 {
+			select {
+			case <-ctx.Done():
+				return maskAny(ctx.Err())
+			case <-time.After(d):
+			}
+		}
+	}
+	s.last = time.Now()
+
+	return nil
+}
+
+// unitNamesBySlice returns the given unit names ordered by slice, so that the
+// units of each slice follow each other. Slices keep the order of their first
+// unit.
+func unitNamesBySlice(names []string) ([]string, error) {
+	var sliceIDs []string
+	bySlice := map[string][]string{}
+	for _, name := range names {
+		sliceID, err := common.SliceID(name)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		if _, ok := bySlice[sliceID]; !ok {
+			sliceIDs = append(sliceIDs, sliceID)
+		}
+		bySlice[sliceID] = append(bySlice[sliceID], name)
+	}
+
+	var ordered []string
+	for _, sliceID := range sliceIDs {
+		ordered = append(ordered, bySlice[sliceID]...)
+	}
+
+	return ordered, nil
+}
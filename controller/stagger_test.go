@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juju/errgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+// startRecordingFleet records the times units are started.
+type startRecordingFleet struct {
+	fleet.Fleet
+
+	Mutex   sync.Mutex
+	Started map[string]time.Time
+}
+
+func (f *startRecordingFleet) Start(ctx context.Context, name string) error {
+	f.Mutex.Lock()
+	f.Started[name] = time.Now()
+	f.Mutex.Unlock()
+
+	return maskAny(f.Fleet.Start(ctx, name))
+}
+
+func TestUnitNamesBySlice(t *testing.T) {
+	RegisterTestingT(t)
+
+	names, err := unitNamesBySlice([]string{
+		"app-a@2.service",
+		"app-a@1.service",
+		"app-b@1.service",
+		"app-b@2.service",
+	})
+	Expect(err).To(BeNil())
+	Expect(names).To(Equal([]string{
+		"app-a@2.service",
+		"app-b@2.service",
+		"app-a@1.service",
+		"app-b@1.service",
+	}))
+}
+
+func TestController_Start_Stagger(t *testing.T) {
+	RegisterTestingT(t)
+
+	c, recordingFleet := givenSimulatedController(t)
+	startFleet := &startRecordingFleet{Fleet: recordingFleet.Fleet, Started: map[string]time.Time{}}
+	c.Config.Fleet = startFleet
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "app", SliceIDs: []string{"1", "2", "3"}},
+		Units: []Unit{
+			{Name: "app-a@.service", Content: "[Service]\nExecStart=/bin/a\n"},
+			{Name: "app-b@.service", Content: "[Service]\nExecStart=/bin/b\n"},
+		},
+	}
+	Expect(submitAndWait(c, req)).To(BeNil())
+
+	stagger := 100 * time.Millisecond
+	req.Stagger = stagger
+	Expect(c.executeTaskAction(c.Start, context.Background(), req)).To(BeNil())
+
+	startFleet.Mutex.Lock()
+	defer startFleet.Mutex.Unlock()
+	Expect(startFleet.Started).To(HaveLen(6))
+	for _, sliceID := range req.SliceIDs {
+		// The units of a slice are launched together.
+		a := startFleet.Started["app-a@"+sliceID+".service"]
+		b := startFleet.Started["app-b@"+sliceID+".service"]
+		Expect(b.Sub(a)).To(BeNumerically("<", stagger))
+	}
+	Expect(startFleet.Started["app-a@2.service"].Sub(startFleet.Started["app-a@1.service"])).To(BeNumerically(">=", stagger))
+	Expect(startFleet.Started["app-a@3.service"].Sub(startFleet.Started["app-a@2.service"])).To(BeNumerically(">=", stagger))
+}
+
+func TestStagger_Canceled(t *testing.T) {
+	RegisterTestingT(t)
+
+	staggered := &stagger{Interval: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	Expect(staggered.wait(ctx)).To(BeNil())
+	cancel()
+	Expect(errgo.Cause(staggered.wait(ctx))).To(Equal(context.Canceled))
+}
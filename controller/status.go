@@ -106,6 +106,7 @@ func allHashesEqual(usl []fleet.UnitStatus) (bool, error) {
 }
 
 type unitHashInfo struct {
+	Name    string
 	Base    string
 	SliceID string
 	Hash    string
@@ -125,6 +126,7 @@ func groupUnitHashInfos(usl []fleet.UnitStatus) ([]unitHashInfo, error) {
 					return nil, maskAny(err)
 				}
 				uhi := unitHashInfo{
+					Name:    us1.Name,
 					Base:    common.UnitBase(us1.Name),
 					SliceID: sliceID,
 					Hash:    m1.UnitHash,
@@ -138,8 +140,9 @@ func groupUnitHashInfos(usl []fleet.UnitStatus) ([]unitHashInfo, error) {
 }
 
 // allStatesEqual returns true if all elements in usl match for the following
-// fields: Current, Desired, Machine.SystemdActive. Note this does not compare
-// hashes sinces this method is supposed to receive only grouped unit statuses.
+// fields: Current, Desired, Machine.SystemdLoad, Machine.SystemdActive. Note
+// this does not compare hashes sinces this method is supposed to receive only
+// grouped unit statuses.
 func allStatesEqual(usl []fleet.UnitStatus) bool {
 	for _, us1 := range usl {
 		for _, us2 := range usl {
@@ -151,6 +154,9 @@ func allStatesEqual(usl []fleet.UnitStatus) bool {
 			}
 			for _, m1 := range us1.Machine {
 				for _, m2 := range us2.Machine {
+					if m1.SystemdLoad != m2.SystemdLoad {
+						return false
+					}
 					if m1.SystemdActive != m2.SystemdActive {
 						return false
 					}
@@ -257,7 +263,7 @@ var (
 			FleetCurrent:  "loaded|launched",
 			FleetDesired:  "*",
 			SystemdActive: "active|reloading",
-			SystemdSub:    "exited|running",
+			SystemdSub:    "exited|running|waiting|listening|mounted|elapsed",
 			Aggregated:    StatusRunning,
 		},
 	}
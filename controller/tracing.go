@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/task"
+	"github.com/giantswarm/inago/tracing"
+)
+
+// Attribute keys describing what spans refer to.
+const (
+	attrOperation = "inago.operation"
+	attrGroup     = "inago.group"
+	attrSlices    = "inago.slices"
+	attrSlice     = "inago.slice"
+	attrUnit      = "inago.unit"
+)
+
+// tracedAction wraps the given action of an operation, so that it is recorded
+// as a span using Config.Tracer. Operations executed as part of the action,
+// e.g. the submit of an update, and the calls of fleet they make, are recorded
+// as children of that span.
+func (c controller) tracedAction(operation string, req Request, action task.Action) task.Action {
+	if c.Config.Tracer == nil {
+		return action
+	}
+
+	return func(ctx context.Context) error {
+		attributes := []tracing.Attribute{
+			tracing.Attr(attrOperation, operation),
+			tracing.Attr(attrGroup, req.Group),
+		}
+		if len(req.SliceIDs) > 0 {
+			attributes = append(attributes, tracing.Attr(attrSlices, strings.Join(req.SliceIDs, ",")))
+		}
+
+		ctx, span := c.Config.Tracer.Start(ctx, operation+" "+req.Group, attributes...)
+		err := action(ctx)
+		span.Finish(err)
+
+		return err
+	}
+}
+
+// tracedFleet records all calls of fleet taking a context as spans, named
+// e.g. "fleet.Start", being children of the span of the operation making
+// them. See Config.Tracer.
+type tracedFleet struct {
+	fleet.Fleet
+
+	Tracer *tracing.Tracer
+}
+
+// start starts a span describing a call of fleet concerning the given unit.
+// The unit is empty for calls concerning the cluster as a whole.
+func (f tracedFleet) start(ctx context.Context, call, name string) (context.Context, *tracing.Span) {
+	var attributes []tracing.Attribute
+	if name != "" {
+		attributes = append(attributes, tracing.Attr(attrUnit, name))
+		if sliceID, _ := common.SliceID(name); sliceID != "" {
+			attributes = append(attributes, tracing.Attr(attrSlice, sliceID))
+		}
+	}
+
+	return f.Tracer.Start(ctx, "fleet."+call, attributes...)
+}
+
+func (f tracedFleet) Submit(ctx context.Context, name, content string) error {
+	ctx, span := f.start(ctx, "Submit", name)
+	err := f.Fleet.Submit(ctx, name, content)
+	span.Finish(err)
+	return err
+}
+
+func (f tracedFleet) Start(ctx context.Context, name string) error {
+	ctx, span := f.start(ctx, "Start", name)
+	err := f.Fleet.Start(ctx, name)
+	span.Finish(err)
+	return err
+}
+
+func (f tracedFleet) Stop(ctx context.Context, name string) error {
+	ctx, span := f.start(ctx, "Stop", name)
+	err := f.Fleet.Stop(ctx, name)
+	span.Finish(err)
+	return err
+}
+
+func (f tracedFleet) Destroy(ctx context.Context, name string) error {
+	ctx, span := f.start(ctx, "Destroy", name)
+	err := f.Fleet.Destroy(ctx, name)
+	span.Finish(err)
+	return err
+}
+
+func (f tracedFleet) GetStatus(ctx context.Context, name string) (fleet.UnitStatus, error) {
+	ctx, span := f.start(ctx, "GetStatus", name)
+	us, err := f.Fleet.GetStatus(ctx, name)
+	span.Finish(err)
+	return us, err
+}
+
+func (f tracedFleet) GetContent(ctx context.Context, name string) (string, error) {
+	ctx, span := f.start(ctx, "GetContent", name)
+	content, err := f.Fleet.GetContent(ctx, name)
+	span.Finish(err)
+	return content, err
+}
+
+func (f tracedFleet) Exists(ctx context.Context, name string) (bool, error) {
+	ctx, span := f.start(ctx, "Exists", name)
+	ok, err := f.Fleet.Exists(ctx, name)
+	span.Finish(err)
+	return ok, err
+}
+
+func (f tracedFleet) APIVersion(ctx context.Context) (string, error) {
+	ctx, span := f.start(ctx, "APIVersion", "")
+	version, err := f.Fleet.APIVersion(ctx)
+	span.Finish(err)
+	return version, err
+}
+
+func (f tracedFleet) UnitsPage(ctx context.Context, pageToken string) (fleet.UnitPage, error) {
+	ctx, span := f.start(ctx, "UnitsPage", "")
+	page, err := f.Fleet.UnitsPage(ctx, pageToken)
+	span.Finish(err)
+	return page, err
+}
+
+func (f tracedFleet) Machines(ctx context.Context) ([]fleet.Machine, error) {
+	ctx, span := f.start(ctx, "Machines", "")
+	machines, err := f.Fleet.Machines(ctx)
+	span.Finish(err)
+	return machines, err
+}
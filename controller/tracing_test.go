@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/task"
+	"github.com/giantswarm/inago/tracing"
+)
+
+// recordingExporter records all spans it is asked to export.
+type recordingExporter struct {
+	Spans []*tracing.Span
+}
+
+func (e *recordingExporter) Export(ctx context.Context, service string, spans []*tracing.Span) error {
+	e.Spans = append(e.Spans, spans...)
+	return nil
+}
+
+func TestController_Tracing(t *testing.T) {
+	exporter := &recordingExporter{}
+	newTracingConfig := tracing.DefaultConfig()
+	newTracingConfig.Exporter = exporter
+	newTracer := tracing.NewTracer(newTracingConfig)
+
+	testController, _ := getTestController()
+	testController.Config.Tracer = newTracer
+	testController = *NewController(testController.Config).(*controller)
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "foo", SliceIDs: []string{"1"}},
+		Units: []Unit{
+			{Name: "foo-main@.service", Content: "[Service]\nExecStart=/bin/foo\n"},
+		},
+	}
+
+	ctx, root := newTracer.Start(context.Background(), "deploy")
+	for _, action := range []func(context.Context, Request) (*task.Task, error){testController.Submit, testController.Start} {
+		if err := testController.executeTaskAction(action, ctx, req); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+	root.Finish(nil)
+	if err := newTracer.Flush(context.Background()); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	spans := map[string]*tracing.Span{}
+	for _, span := range exporter.Spans {
+		if span.TraceID != root.TraceID {
+			t.Fatal("expected", root.TraceID, "got", span.TraceID)
+		}
+		spans[span.Name] = span
+	}
+
+	submit, ok := spans["submit foo"]
+	if !ok {
+		t.Fatal("expected", "span of submit", "got", spans)
+	}
+	if submit.ParentID != root.SpanID {
+		t.Fatal("expected", root.SpanID, "got", submit.ParentID)
+	}
+	if !hasAttribute(submit, "inago.group", "foo") || !hasAttribute(submit, "inago.slices", "1") {
+		t.Fatal("expected", "group and slice attributes", "got", submit.Attributes)
+	}
+
+	call, ok := spans["fleet.Submit"]
+	if !ok {
+		t.Fatal("expected", "span of fleet call", "got", spans)
+	}
+	if call.ParentID != submit.SpanID {
+		t.Fatal("expected", submit.SpanID, "got", call.ParentID)
+	}
+	if !hasAttribute(call, "inago.unit", "foo-main@1.service") || !hasAttribute(call, "inago.slice", "1") {
+		t.Fatal("expected", "unit and slice attributes", "got", call.Attributes)
+	}
+
+	if _, ok := spans["start foo"]; !ok {
+		t.Fatal("expected", "span of start", "got", spans)
+	}
+}
+
+func hasAttribute(span *tracing.Span, key, value string) bool {
+	for _, a := range span.Attributes {
+		if a.Key == key && a.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
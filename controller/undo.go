@@ -0,0 +1,325 @@
+package controller
+
+import (
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/fleet"
+	"github.com/giantswarm/inago/policy"
+	"github.com/giantswarm/inago/state"
+	"github.com/giantswarm/inago/task"
+)
+
+const (
+	// undoNamespace is the namespace of the state store the state of groups
+	// before their latest operation is stored in.
+	undoNamespace = "undo"
+
+	// contextUndoRecorded is the key marking contexts of operations executed as
+	// part of another operation, whose state has already been recorded. See
+	// recordUndo.
+	contextUndoRecorded = "undo-recorded"
+)
+
+// UndoableOperation describes the latest operation changing a group, which can
+// be reverted using Controller.Undo.
+type UndoableOperation struct {
+	// Operation is the name of the operation, e.g. "update".
+	Operation string
+
+	// Time is the time the operation was executed.
+	Time time.Time
+}
+
+// undoRecord is stored for each group before an operation changes it.
+type undoRecord struct {
+	Operation string    `json:"operation"`
+	Time      time.Time `json:"time"`
+
+	// Units maps the names of all units of the group to their unit files.
+	Units map[string]string `json:"units"`
+
+	// Launched are the names of the units supposed to be running.
+	Launched []string `json:"launched"`
+
+	// UnitBases are the bases of the unit files belonging to the group, e.g.
+	// "app-main". See common.UnitBase.
+	UnitBases []string `json:"unit_bases"`
+}
+
+// undoPlan describes the unit operations reverting a group to a recorded
+// state. All lists are sorted by unit name.
+type undoPlan struct {
+	// Replace are the units deployed using other unit files than recorded.
+	// They are destroyed and submitted again.
+	Replace []string
+
+	// Submit are the units recorded, but not deployed anymore.
+	Submit []string
+
+	// Start are the units supposed to be running, but not started.
+	Start []string
+
+	// Stop are the units not supposed to be running, but started.
+	Stop []string
+
+	// Destroy are the units deployed, but not recorded. They are destroyed once
+	// the recorded units are running again.
+	Destroy []string
+}
+
+func (c controller) LastOperation(ctx context.Context, group string) (UndoableOperation, error) {
+	c.Config.Logger.Debug(ctx, "controller: looking up last operation of group '%s'", group)
+
+	record, err := c.loadUndoRecord(group)
+	if err != nil {
+		return UndoableOperation{}, maskAny(err)
+	}
+
+	return UndoableOperation{Operation: record.Operation, Time: record.Time}, nil
+}
+
+func (c controller) Undo(ctx context.Context, group string) (*task.Task, error) {
+	c.Config.Logger.Debug(ctx, "controller: handling undo")
+
+	req := NewRequest(RequestConfig{Group: group})
+	for _, op := range []policy.Operation{policy.Submit, policy.Start, policy.Destroy} {
+		if err := c.checkPolicy(ctx, op, req); err != nil {
+			return nil, maskAny(err)
+		}
+	}
+	record, err := c.loadUndoRecord(group)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	action := func(ctx context.Context) error {
+		current, err := c.groupUndoRecord(ctx, group, record.UnitBases)
+		if err != nil {
+			return maskAny(err)
+		}
+		plan := planUndo(record, current)
+
+		// The undo is recorded itself, so that undoing again redoes the
+		// reverted operation.
+		ctx = c.recordUndo(ctx, "undo", group, record.UnitBases)
+
+		c.Config.Logger.Debug(ctx, "action: replacing units %v", plan.Replace)
+		for _, name := range plan.Replace {
+			if err := c.Fleet.Destroy(ctx, name); err != nil && !fleet.IsUnitNotFound(err) {
+				return maskAny(err)
+			}
+			c.emitUnitEvent(ctx, EventUnitDestroyed, group, name)
+		}
+		for _, name := range append(append([]string{}, plan.Replace...), plan.Submit...) {
+			if err := c.submitUnit(ctx, name, record.Units[name]); err != nil {
+				return maskAny(err)
+			}
+			c.emitUnitEvent(ctx, EventUnitSubmitted, group, name)
+		}
+
+		c.Config.Logger.Debug(ctx, "action: starting units %v", plan.Start)
+		for _, name := range plan.Start {
+			if err := c.Fleet.Start(ctx, name); err != nil {
+				return maskAny(err)
+			}
+			c.emitUnitEvent(ctx, EventUnitStarted, group, name)
+		}
+		for _, name := range plan.Stop {
+			if err := c.Fleet.Stop(ctx, name); err != nil {
+				return maskAny(err)
+			}
+			c.emitUnitEvent(ctx, EventUnitStopped, group, name)
+		}
+		if len(plan.Start) > 0 {
+			startedReq, err := unitsRequest(group, plan.Start)
+			if err != nil {
+				return maskAny(err)
+			}
+			if err := c.waitForRunning(ctx, startedReq); err != nil {
+				return maskAny(err)
+			}
+		}
+
+		c.Config.Logger.Debug(ctx, "action: destroying units %v", plan.Destroy)
+		for _, name := range plan.Destroy {
+			if err := c.Fleet.Destroy(ctx, name); err != nil && !fleet.IsUnitNotFound(err) {
+				return maskAny(err)
+			}
+			c.emitUnitEvent(ctx, EventUnitDestroyed, group, name)
+		}
+
+		return nil
+	}
+
+	taskObject, err := c.createTask(ctx, "undo", req, action)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return taskObject, nil
+}
+
+// recordUndo stores the current state of the given group before the given
+// operation changes it, so that the operation can be reverted using Undo.
+// names are the names of the units or unit files the operation affects. They
+// define the group's units, together with the units of the group's previous
+// record. Operations executed as part of another one are not recorded, e.g.
+// the submit of an update, so the returned context is marked to skip
+// recording. Failing to record does not fail the operation.
+func (c controller) recordUndo(ctx context.Context, operation, group string, names []string) context.Context {
+	if c.Config.StateStore == nil {
+		return ctx
+	}
+	if recorded, _ := ctx.Value(contextUndoRecorded).(bool); recorded {
+		return ctx
+	}
+	ctx = withoutUndo(ctx)
+
+	bases := unitBases(names)
+	if previous, err := c.loadUndoRecord(group); err == nil {
+		bases = unitBases(append(bases, previous.UnitBases...))
+	}
+	record, err := c.groupUndoRecord(ctx, group, bases)
+	if err == nil {
+		record.Operation = operation
+		record.Time = time.Now()
+		err = c.Config.StateStore.Set(undoNamespace, group, record)
+	}
+	if err != nil {
+		c.Config.Logger.Warning(ctx, "Failed to record the state of group '%s' before %s, it cannot be undone. (%s)", group, operation, err.Error())
+	}
+
+	return ctx
+}
+
+// withoutUndo returns a copy of ctx for operations executed as part of
+// another operation, which must not be recorded on their own.
+func withoutUndo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextUndoRecorded, true)
+}
+
+// groupUndoRecord fetches the unit files and states of all units of the given
+// group. A unit belongs to the group in case its base is one of the given
+// bases. Matching the group name as prefix would also capture groups like
+// "app-test" of the group "app".
+func (c controller) groupUndoRecord(ctx context.Context, group string, bases []string) (undoRecord, error) {
+	record := undoRecord{Units: map[string]string{}, UnitBases: bases}
+
+	usl, err := c.Fleet.GetStatusWithMatcher(func(name string) bool {
+		return contains(bases, common.UnitBase(name))
+	})
+	if fleet.IsUnitNotFound(err) {
+		return record, nil
+	} else if err != nil {
+		return undoRecord{}, maskAny(err)
+	}
+
+	for _, us := range usl {
+		content, err := c.Fleet.GetContent(ctx, us.Name)
+		if fleet.IsUnitNotFound(err) {
+			// The unit has been destroyed in the meantime.
+			continue
+		} else if err != nil {
+			return undoRecord{}, maskAny(err)
+		}
+		record.Units[us.Name] = content
+		if us.Desired == "launched" {
+			record.Launched = append(record.Launched, us.Name)
+		}
+	}
+	sort.Strings(record.Launched)
+
+	return record, nil
+}
+
+func (c controller) loadUndoRecord(group string) (undoRecord, error) {
+	if c.Config.StateStore == nil {
+		return undoRecord{}, maskAnyf(undoNotFoundError, "no state store configured")
+	}
+
+	var record undoRecord
+	err := c.Config.StateStore.Get(undoNamespace, group, &record)
+	if state.IsNotFound(err) {
+		return undoRecord{}, maskAnyf(undoNotFoundError, "%s", group)
+	} else if state.IsInvalidKey(err) {
+		return undoRecord{}, maskAnyf(invalidArgumentError, "group: %s", err.Error())
+	} else if err != nil {
+		return undoRecord{}, maskAny(err)
+	}
+
+	return record, nil
+}
+
+// planUndo compares the given recorded and current state of a group and
+// returns the unit operations reverting the group to the recorded state.
+// Unit files only formatted differently are considered equal.
+func planUndo(recorded, current undoRecord) undoPlan {
+	var plan undoPlan
+	for name, content := range recorded.Units {
+		deployed, ok := current.Units[name]
+		if !ok {
+			plan.Submit = append(plan.Submit, name)
+		} else if !UnitContentEqual(deployed, content) {
+			plan.Replace = append(plan.Replace, name)
+		}
+	}
+	for name := range current.Units {
+		if _, ok := recorded.Units[name]; !ok {
+			plan.Destroy = append(plan.Destroy, name)
+		}
+	}
+
+	for _, name := range recorded.Launched {
+		if !contains(current.Launched, name) || contains(plan.Replace, name) || contains(plan.Submit, name) {
+			plan.Start = append(plan.Start, name)
+		}
+	}
+	for _, name := range current.Launched {
+		if _, ok := recorded.Units[name]; ok && !contains(recorded.Launched, name) && !contains(plan.Replace, name) {
+			plan.Stop = append(plan.Stop, name)
+		}
+	}
+
+	sort.Strings(plan.Replace)
+	sort.Strings(plan.Submit)
+	sort.Strings(plan.Start)
+	sort.Strings(plan.Stop)
+	sort.Strings(plan.Destroy)
+
+	return plan
+}
+
+// unitBases returns the sorted and distinct bases of the given unit names.
+func unitBases(names []string) []string {
+	var bases []string
+	for _, name := range names {
+		if base := common.UnitBase(name); !contains(bases, base) {
+			bases = append(bases, base)
+		}
+	}
+	sort.Strings(bases)
+
+	return bases
+}
+
+// unitsRequest returns a request for the slices of the given units of the
+// given group. Units of groups without slices result in a request for the
+// whole group.
+func unitsRequest(group string, names []string) (Request, error) {
+	req := NewRequest(RequestConfig{Group: group})
+	for _, name := range names {
+		sliceID, err := common.SliceID(name)
+		if err != nil {
+			return Request{}, maskAny(err)
+		}
+		if sliceID != "" && !contains(req.SliceIDs, sliceID) {
+			req.SliceIDs = append(req.SliceIDs, sliceID)
+		}
+	}
+
+	return req, nil
+}
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/state"
+	"github.com/giantswarm/inago/task"
+)
+
+func TestController_planUndo(t *testing.T) {
+	RegisterTestingT(t)
+
+	recorded := undoRecord{
+		Units: map[string]string{
+			"app-main@1.service": "[Service]\nExecStart=/bin/main\n",
+			"app-main@2.service": "[Service]\nExecStart=/bin/main\n",
+			"app-main@3.service": "[Service]\nExecStart=/bin/main\n",
+			"app-main@4.service": "[Service]\nExecStart=/bin/main\n",
+		},
+		Launched: []string{"app-main@1.service", "app-main@2.service", "app-main@3.service"},
+	}
+	current := undoRecord{
+		Units: map[string]string{
+			"app-main@1.service": "[Service]\nExecStart = /bin/main\n",
+			"app-main@2.service": "[Service]\nExecStart=/bin/other\n",
+			"app-main@4.service": "[Service]\nExecStart=/bin/main\n",
+			"app-main@5.service": "[Service]\nExecStart=/bin/main\n",
+		},
+		Launched: []string{"app-main@2.service", "app-main@4.service", "app-main@5.service"},
+	}
+
+	Expect(planUndo(recorded, current)).To(Equal(undoPlan{
+		Replace: []string{"app-main@2.service"},
+		Submit:  []string{"app-main@3.service"},
+		Start:   []string{"app-main@1.service", "app-main@2.service", "app-main@3.service"},
+		Stop:    []string{"app-main@4.service"},
+		Destroy: []string{"app-main@5.service"},
+	}))
+	Expect(planUndo(recorded, recorded)).To(Equal(undoPlan{}))
+}
+
+func TestController_Undo(t *testing.T) {
+	RegisterTestingT(t)
+
+	c, recordingFleet := givenSimulatedController(t)
+	ctx := context.Background()
+
+	_, err := c.LastOperation(ctx, "app")
+	Expect(IsUndoNotFound(err)).To(BeTrue())
+	c.Config.StateStore = state.NewMemoryStore()
+	_, err = c.LastOperation(ctx, "app")
+	Expect(IsUndoNotFound(err)).To(BeTrue())
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "app", SliceIDs: []string{"1", "2"}},
+		Units:         []Unit{{Name: "app-main@.service", Content: "[Service]\nExecStart=/bin/main\n"}},
+	}
+	Expect(submitAndWait(c, req)).To(BeNil())
+	Expect(c.executeTaskAction(c.Start, ctx, req)).To(BeNil())
+	op, err := c.LastOperation(ctx, "app")
+	Expect(err).To(BeNil())
+	Expect(op.Operation).To(Equal("submit"))
+
+	// Destroying a slice by mistake is reverted.
+	req.SliceIDs = []string{"2"}
+	Expect(c.executeTaskAction(c.Destroy, ctx, req)).To(BeNil())
+	op, err = c.LastOperation(ctx, "app")
+	Expect(err).To(BeNil())
+	Expect(op.Operation).To(Equal("destroy"))
+	Expect(undoAndWait(c, "app")).To(BeNil())
+	us, err := recordingFleet.GetStatus(ctx, "app-main@2.service")
+	Expect(err).To(BeNil())
+	Expect(us.Desired).To(Equal("launched"))
+	op, err = c.LastOperation(ctx, "app")
+	Expect(err).To(BeNil())
+	Expect(op.Operation).To(Equal("undo"))
+
+	// Undoing again redoes the destroy.
+	Expect(undoAndWait(c, "app")).To(BeNil())
+	_, err = recordingFleet.GetStatus(ctx, "app-main@2.service")
+	Expect(err).NotTo(BeNil())
+	us, err = recordingFleet.GetStatus(ctx, "app-main@1.service")
+	Expect(err).To(BeNil())
+	Expect(us.Desired).To(Equal("launched"))
+}
+
+func undoAndWait(c controller, group string) error {
+	ctx := context.Background()
+	taskObject, err := c.Undo(ctx, group)
+	if err != nil {
+		return maskAny(err)
+	}
+	taskObject, err = c.WaitForTask(ctx, taskObject.ID, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	if task.HasFailedStatus(taskObject) {
+		return maskAny(taskObject.Error)
+	}
+
+	return nil
+}
+
+func TestController_Undo_SiblingGroup(t *testing.T) {
+	RegisterTestingT(t)
+
+	c, recordingFleet := givenSimulatedController(t)
+	c.Config.StateStore = state.NewMemoryStore()
+	ctx := context.Background()
+
+	// The group "app-test" shares the prefix of the group "app", but is not
+	// affected by undoing operations of the latter.
+	sibling := Request{
+		RequestConfig: RequestConfig{Group: "app-test", SliceIDs: []string{"1"}},
+		Units:         []Unit{{Name: "app-test-main@.service", Content: "[Service]\nExecStart=/bin/main\n"}},
+	}
+	Expect(submitAndWait(c, sibling)).To(BeNil())
+	req := Request{
+		RequestConfig: RequestConfig{Group: "app", SliceIDs: []string{"1"}},
+		Units:         []Unit{{Name: "app-main@.service", Content: "[Service]\nExecStart=/bin/main\n"}},
+	}
+	Expect(submitAndWait(c, req)).To(BeNil())
+
+	record, err := c.loadUndoRecord("app")
+	Expect(err).To(BeNil())
+	Expect(record.Units).To(BeEmpty())
+
+	Expect(undoAndWait(c, "app")).To(BeNil())
+	_, err = recordingFleet.GetStatus(ctx, "app-main@1.service")
+	Expect(err).NotTo(BeNil())
+	_, err = recordingFleet.GetStatus(ctx, "app-test-main@1.service")
+	Expect(err).To(BeNil())
+}
@@ -156,6 +156,7 @@ func (c controller) runAddWorker(ctx context.Context, req Request, opts UpdateOp
 	}
 
 	time.Sleep(time.Duration(opts.ReadySecs) * time.Second)
+	c.emitSliceEvents(ctx, EventSliceReady, newReq)
 
 	return newReq, nil
 }
@@ -200,6 +201,8 @@ func (c controller) runRemoveWorker(ctx context.Context, req Request) error {
 	if err := c.executeTaskAction(c.Destroy, ctx, req); err != nil {
 		return maskAny(err)
 	}
+	c.emitSliceEvents(ctx, EventSliceRemoved, req)
+
 	return nil
 }
 
@@ -222,18 +225,67 @@ type UpdateOptions struct {
 	// MaxGrowth represents the number of groups allowed to be added at a given
 	// time. No more than MaxGrowth groups will be added at the same time during
 	// the update process.
-	MaxGrowth int
+	MaxGrowth int `json:"max-growth"`
 
 	// MinAlive represents the number of groups required to stay healthy during
 	// the update process. No more than MinAlive groups will be removed at the
 	// same time during the update process.
-	MinAlive int
+	MinAlive int `json:"min-alive"`
 
 	// ReadySecs represents the number of seconds required to wait before ending
 	// the update process of one group and starting the update process of another
 	// group. This is basically a cool down where the update process sleeps
 	// before updating the next group.
-	ReadySecs int
+	ReadySecs int `json:"ready-secs"`
+
+	// MaxPerMachine is the number of slices allowed to be cycled at the same
+	// time on a single machine, so that e.g. a host is not saturated by
+	// simultaneous docker pulls and stops. Only the machines the replaced
+	// slices run on are considered, since fleet decides where new slices are
+	// scheduled. Zero means there is no limit.
+	MaxPerMachine int `json:"max-per-machine"`
+
+	// AllowDowngrade defines whether the group may be updated to units carrying
+	// a lower semantic version than the deployed slices. See
+	// Request.WithSemVer.
+	AllowDowngrade bool `json:"allow-downgrade"`
+
+	// BatchPercent is the share of the group's slices, from 1 to 100, cycled
+	// at the same time. Slices of a batch are added before the slices they
+	// replace are removed, so the group keeps its capacity. Since the batch
+	// size follows the size of the group, it does not need to be recomputed as
+	// the group scales. BatchPercent overrides MaxGrowth and MinAlive. Zero
+	// means MaxGrowth and MinAlive apply. See BatchSize.
+	BatchPercent int `json:"batch-percent,omitempty"`
+}
+
+// BatchSize returns the number of slices cycled at the same time in a group
+// having the given number of slices, according to BatchPercent. The batch
+// size is rounded up, so that each batch contains at least one slice. Zero is
+// returned in case BatchPercent is not set.
+func (o UpdateOptions) BatchSize(slices int) int {
+	if o.BatchPercent <= 0 {
+		return 0
+	}
+	size := (slices*o.BatchPercent + 99) / 100
+	if size < 1 {
+		size = 1
+	}
+
+	return size
+}
+
+// withBatch returns the options cycling batches of slices of a group having
+// the given number of slices, in case BatchPercent is set. Batches only add
+// slices before removing the old ones, so MinAlive is not used.
+func (o UpdateOptions) withBatch(slices int) UpdateOptions {
+	if o.BatchPercent <= 0 {
+		return o
+	}
+	o.MaxGrowth = o.BatchSize(slices)
+	o.MinAlive = 0
+
+	return o
 }
 
 // updateCurrentSliceIDs updates the list of current slice IDs,
@@ -266,10 +318,26 @@ func (c controller) updateCurrentSliceIDs(ctx context.Context, currentSliceIDs [
 func (c controller) UpdateWithStrategy(ctx context.Context, req Request, opts UpdateOptions) error {
 	c.Config.Logger.Debug(ctx, "controller: running update for group '%v'", req.Group)
 
-	fail := make(chan error, 1)
 	numTotal := len(req.SliceIDs)
-
-	done := make(chan struct{}, numTotal)
+	fail := make(chan SliceError, numTotal)
+	done := make(chan string, numTotal)
+
+	// Slices failing to be replaced end the update, unless req.MaxFailures
+	// allows them to fail. finished counts the slices replaced or failed.
+	var result MultiSliceError
+	finished := 0
+	recordFailure := func(sliceErr SliceError) error {
+		finished++
+		if req.MaxFailures.IsZero() {
+			return maskAny(sliceErr.Err)
+		}
+		result.Failed = append(result.Failed, sliceErr)
+		if req.MaxFailures.Exceeded(len(result.Failed), numTotal) {
+			return maskAny(result)
+		}
+		c.Config.Logger.Warning(ctx, "Failed to replace slice %s of group '%s'. (%s)", sliceErr.SliceID, req.Group, sliceErr.Err.Error())
+		return nil
+	}
 
 	var addInProgress, removeInProgress int64
 
@@ -289,6 +357,16 @@ func (c controller) UpdateWithStrategy(ctx context.Context, req Request, opts Up
 		return maskAnyf(updateNotAllowedError, "invalid min alive option")
 	}
 
+	// Slices are cycled in an order spreading them across machines, so that
+	// the limit of slices per machine blocks the update as little as possible.
+	machines, err := c.sliceMachines(ctx, req)
+	if err != nil {
+		return maskAny(err)
+	}
+	sliceIDs := orderByMachine(req.SliceIDs, machines)
+	slots := newMachineSlots(opts.MaxPerMachine)
+	staggered := &stagger{Interval: req.Stagger}
+
 	// We need to track which slice IDs are currently in use.
 	// This list is updated as slices are added and removed.
 	currentSliceIDsMutex := sync.Mutex{}
@@ -297,11 +375,28 @@ func (c controller) UpdateWithStrategy(ctx context.Context, req Request, opts Up
 		currentSliceIDs = append(currentSliceIDs, id)
 	}
 
-	for _, sliceID := range req.SliceIDs {
+	for _, sliceID := range sliceIDs {
 		newReq := req
 		newReq.SliceIDs = []string{sliceID}
+		// The failure budget applies to the slices of the update, a single slice
+		// is either replaced or failed.
+		newReq.MaxFailures = FailureBudget{}
 
 		for {
+			select {
+			case sliceErr := <-fail:
+				if err := recordFailure(sliceErr); err != nil {
+					return maskAny(err)
+				}
+			default:
+			}
+
+			if !slots.acquire(machines[sliceID]) {
+				c.Config.Logger.Debug(ctx, "controller: waiting for machines %v of slice %v to be free", machines[sliceID], sliceID)
+				time.Sleep(c.WaitSleep)
+				continue
+			}
+
 			c.Config.Logger.Debug(ctx, "controller: attempting to add slice: %v", sliceID)
 
 			currentSliceReq := req
@@ -323,6 +418,10 @@ func (c controller) UpdateWithStrategy(ctx context.Context, req Request, opts Up
 			}
 			if ok {
 				ctx = context.WithValue(ctx, "slice ID", sliceID)
+				if err := staggered.wait(ctx); err != nil {
+					slots.release(machines[sliceID])
+					return maskAny(err)
+				}
 				// we increase the addInProgress counter before starting the goroutine
 				// to avoid a race condition in the allowed calculation
 				atomic.AddInt64(&addInProgress, 1)
@@ -331,8 +430,10 @@ func (c controller) UpdateWithStrategy(ctx context.Context, req Request, opts Up
 					c.Config.Logger.Debug(ctx, "controller: starting to add slice: %v", req.SliceIDs)
 
 					newSliceIDs, err := c.addFirst(ctx, req, opts)
+					slots.release(machines[req.SliceIDs[0]])
 					if err != nil {
-						fail <- maskAny(err)
+						atomic.AddInt64(&addInProgress, -1)
+						fail <- SliceError{SliceID: req.SliceIDs[0], Err: maskAny(err)}
 						return
 					}
 
@@ -341,12 +442,20 @@ func (c controller) UpdateWithStrategy(ctx context.Context, req Request, opts Up
 					currentSliceIDsMutex.Unlock()
 
 					atomic.AddInt64(&addInProgress, -1)
-					done <- struct{}{}
+					done <- req.SliceIDs[0]
 				}(newReq)
 
 				break
 			}
 
+			if opts.BatchPercent > 0 {
+				// Batches only add slices before removing the old ones. The next
+				// slice waits for a slice of the current batch to be replaced.
+				slots.release(machines[sliceID])
+				time.Sleep(c.WaitSleep)
+				continue
+			}
+
 			c.Config.Logger.Debug(ctx, "controller: attempting to remove slice: %v", sliceID)
 			// remove
 			// we are only allowed to remove if the number of minAlive slices
@@ -364,6 +473,10 @@ func (c controller) UpdateWithStrategy(ctx context.Context, req Request, opts Up
 				return maskAny(err)
 			}
 			if ok {
+				if err := staggered.wait(ctx); err != nil {
+					slots.release(machines[sliceID])
+					return maskAny(err)
+				}
 				// we increase the removeInProgress counter before starting the goroutine
 				// to avoid a race condition in the allowed calculation
 				atomic.AddInt64(&removeInProgress, 1)
@@ -372,8 +485,10 @@ func (c controller) UpdateWithStrategy(ctx context.Context, req Request, opts Up
 					c.Config.Logger.Debug(ctx, "controller: starting to remove slice: %v", req.SliceIDs)
 
 					newSliceIDs, err := c.removeFirst(ctx, req, opts)
+					slots.release(machines[req.SliceIDs[0]])
 					if err != nil {
-						fail <- maskAny(err)
+						atomic.AddInt64(&removeInProgress, -1)
+						fail <- SliceError{SliceID: req.SliceIDs[0], Err: maskAny(err)}
 						return
 					}
 
@@ -382,29 +497,30 @@ func (c controller) UpdateWithStrategy(ctx context.Context, req Request, opts Up
 					currentSliceIDsMutex.Unlock()
 
 					atomic.AddInt64(&removeInProgress, -1)
-					done <- struct{}{}
+					done <- req.SliceIDs[0]
 				}(newReq)
 
 				break
 			}
 
+			slots.release(machines[sliceID])
 			time.Sleep(c.WaitSleep)
 		}
 	}
 
-	tc := 0
-
-	for {
+	for finished < numTotal {
 		select {
-		case err := <-fail:
-			return maskAny(err)
-		case <-done:
-			tc++
-			if tc == numTotal {
-				return nil
+		case sliceErr := <-fail:
+			if err := recordFailure(sliceErr); err != nil {
+				return maskAny(err)
 			}
+		case sliceID := <-done:
+			finished++
+			result.Succeeded = append(result.Succeeded, sliceID)
 		case <-time.After(c.WaitTimeout):
 			return maskAny(waitTimeoutReachedError)
 		}
 	}
+
+	return nil
 }
@@ -536,6 +536,56 @@ func TestUpdateWithStrategy(t *testing.T) {
 				}
 			},
 		},
+		// Test an update replacing batches of half of the group slices.
+		{
+			fleetSetUp: func(f fleet.Fleet) {
+				unitNameTemplate := "finch-unit@%v.service"
+
+				for _, id := range []string{"a1", "b2", "c3", "d4"} {
+					unitName := fmt.Sprintf(unitNameTemplate, id)
+
+					f.Submit(context.Background(), unitName, "some content")
+					f.Start(context.Background(), unitName)
+				}
+			},
+			req: Request{
+				RequestConfig: RequestConfig{
+					Group:    "finch",
+					SliceIDs: []string{"a1", "b2", "c3", "d4"},
+				},
+				Units: []Unit{
+					Unit{
+						Name:    "finch-unit@.service",
+						Content: "some updated content",
+					},
+				},
+			},
+			opts: UpdateOptions{
+				BatchPercent: 50,
+			}.withBatch(4),
+			assertion: func(t *testing.T, f *fleet.DummyFleet, e error) {
+				if e != nil {
+					t.Fatal("Error returned by update:", e)
+				}
+
+				unitStatusList, err := f.GetStatusWithMatcher(
+					func(s string) bool {
+						return strings.HasPrefix(s, "finch-unit@") && strings.HasSuffix(s, ".service")
+					},
+				)
+				if err != nil {
+					t.Fatal("Error returned getting statuses: ", err)
+				}
+				if len(unitStatusList) != 4 {
+					t.Fatal("Incorrect number of units:", len(unitStatusList))
+				}
+				for _, unitStatus := range unitStatusList {
+					if content := f.Contents[unitStatus.Name]; content != "some updated content" {
+						t.Fatal("Unit not updated:", unitStatus.Name)
+					}
+				}
+			},
+		},
 	}
 
 	for i, test := range tests {
@@ -548,3 +598,32 @@ func TestUpdateWithStrategy(t *testing.T) {
 		test.assertion(t, dummyFleet, err)
 	}
 }
+
+func TestUpdateOptions_BatchSize(t *testing.T) {
+	tests := []struct {
+		batchPercent int
+		slices       int
+		batchSize    int
+	}{
+		{batchPercent: 0, slices: 10, batchSize: 0},
+		{batchPercent: 25, slices: 100, batchSize: 25},
+		{batchPercent: 25, slices: 10, batchSize: 3},
+		{batchPercent: 10, slices: 1, batchSize: 1},
+		{batchPercent: 100, slices: 7, batchSize: 7},
+	}
+
+	for i, test := range tests {
+		opts := UpdateOptions{MaxGrowth: 1, MinAlive: 1, BatchPercent: test.batchPercent}
+		if batchSize := opts.BatchSize(test.slices); batchSize != test.batchSize {
+			t.Fatal("test", i, "expected", test.batchSize, "got", batchSize)
+		}
+
+		opts = opts.withBatch(test.slices)
+		if test.batchPercent > 0 && (opts.MaxGrowth != test.batchSize || opts.MinAlive != 0) {
+			t.Fatal("test", i, "expected", test.batchSize, "got", opts)
+		}
+		if test.batchPercent == 0 && (opts.MaxGrowth != 1 || opts.MinAlive != 1) {
+			t.Fatal("test", i, "expected", "unchanged options", "got", opts)
+		}
+	}
+}
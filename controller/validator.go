@@ -3,6 +3,10 @@ package controller
 import (
 	"sort"
 	"strings"
+
+	"github.com/coreos/fleet/unit"
+
+	"github.com/giantswarm/inago/common"
 )
 
 // StringsUnique returns true if all strings in the list are unique,
@@ -127,12 +131,104 @@ func ValidateRequest(request Request) (bool, error) {
 		validationError.Add(unitsSameNameError)
 	}
 
+	// Check that all units are of a kind Inago is able to manage.
+	for _, name := range unitNames {
+		if !common.IsSupportedUnit(name) {
+			validationError.Add(unsupportedUnitKindError)
+			break
+		}
+	}
+
+	// Check that all timer, socket and path units activate a unit of the group.
+	if !triggeredUnitsInGroup(request.Units) {
+		validationError.Add(triggeredUnitNotInGroupError)
+	}
+
+	// Check that fleet is able to store all units.
+	for _, unit := range request.Units {
+		if err := validateUnitSize(unit); err != nil {
+			validationError.Add(err)
+		}
+	}
+
+	// Check that slices are able to run side by side in case there are
+	// multiple of them.
+	if request.DesiredSlices > 1 || len(request.SliceIDs) > 1 {
+		for _, err := range sliceCollisionErrors(request.Units) {
+			validationError.Add(err)
+		}
+	}
+
 	if len(validationError.CausingErrors) != 0 {
 		return false, validationError
 	}
 	return true, nil
 }
 
+// ValidateSliceAwareness takes a Request of a group running multiple slices,
+// and returns whether its slices are able to run side by side. If they are
+// not, e.g. because all slices run Docker containers of the same name, the
+// error provides more details, including suggestions how to fix the units.
+// See also LintRequest.
+func ValidateSliceAwareness(request Request) (bool, error) {
+	errs := sliceCollisionErrors(request.Units)
+	if len(errs) != 0 {
+		return false, ValidationError{CausingErrors: errs}
+	}
+	return true, nil
+}
+
+// sliceCollisionErrors returns an error for each way the slices of the given
+// units collide. See lintSliceAwareness.
+func sliceCollisionErrors(units []Unit) []error {
+	var errs []error
+	for _, u := range units {
+		unitFile, err := unit.NewUnitFile(u.Content)
+		if err != nil {
+			continue
+		}
+		for _, warning := range lintSliceAwareness(u.Name, unitFile) {
+			errs = append(errs, maskAnyf(sliceCollisionError, "%s: %s", u.Name, warning))
+		}
+	}
+
+	return errs
+}
+
+// triggeredUnitsInGroup returns true if the units activated by all trigger
+// units of the given list are part of the list as well, false otherwise. The
+// activated unit is the service unit named like the trigger unit, unless the
+// trigger unit's content configures another one using the Unit= directive.
+// Trigger units without content are not checked, because the activated unit
+// cannot be determined without knowing their content.
+func triggeredUnitsInGroup(units []Unit) bool {
+	bases := map[string]struct{}{}
+	for _, u := range units {
+		bases[common.UnitBase(u.Name)+common.UnitExtension(u.Name)] = struct{}{}
+	}
+
+	for _, u := range units {
+		triggered := common.TriggeredUnit(u.Name)
+		if triggered == "" || u.Content == "" {
+			continue
+		}
+
+		if unitFile, err := unit.NewUnitFile(u.Content); err == nil {
+			// The section is named like the unit kind, e.g. "Timer" for ".timer".
+			section := strings.Title(strings.TrimPrefix(common.UnitExtension(u.Name), "."))
+			if values := unitFile.Contents[section]["Unit"]; len(values) > 0 {
+				triggered = values[len(values)-1]
+			}
+		}
+
+		if _, ok := bases[common.UnitBase(triggered)+common.UnitExtension(triggered)]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
 // ValidateMultipleRequest takes a list of Requests, and returns whether
 // they are valid together or not.
 // If the requests are not valid, the error returned provides more details.
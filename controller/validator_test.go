@@ -174,6 +174,128 @@ func TestValidateRequest(t *testing.T) {
 			valid:        false,
 			errAssertion: IsUnitsSameName,
 		},
+		// Test that timer, socket and path units activating services of the
+		// group are valid.
+		{
+			request: Request{
+				RequestConfig: RequestConfig{
+					Group: "group",
+				},
+				Units: []Unit{
+					{
+						Name: "group-backup.service",
+					},
+					{
+						Name:    "group-backup.timer",
+						Content: "[Timer]\nOnCalendar=daily\n",
+					},
+					{
+						Name:    "group-http.socket",
+						Content: "[Socket]\nListenStream=80\nUnit=group-server.service\n",
+					},
+					{
+						Name: "group-server.service",
+					},
+					{
+						Name: "group-data.mount",
+					},
+				},
+			},
+			valid:        true,
+			errAssertion: nil,
+		},
+		// Test that units of unknown kinds are not valid.
+		{
+			request: Request{
+				RequestConfig: RequestConfig{
+					Group: "group",
+				},
+				Units: []Unit{
+					{
+						Name: "group-unit.service",
+					},
+					{
+						Name: "group-README.md",
+					},
+				},
+			},
+			valid:        false,
+			errAssertion: IsUnsupportedUnitKind,
+		},
+		// Test that timer units must activate a unit of the group.
+		{
+			request: Request{
+				RequestConfig: RequestConfig{
+					Group: "group",
+				},
+				Units: []Unit{
+					{
+						Name:    "group-backup@.timer",
+						Content: "[Timer]\nOnCalendar=daily\n",
+					},
+					{
+						Name: "group-unit@.service",
+					},
+				},
+			},
+			valid:        false,
+			errAssertion: IsTriggeredUnitNotInGroup,
+		},
+		// Test that the Unit= directive of socket units must reference a unit of
+		// the group.
+		{
+			request: Request{
+				RequestConfig: RequestConfig{
+					Group: "group",
+				},
+				Units: []Unit{
+					{
+						Name:    "group-http.socket",
+						Content: "[Socket]\nListenStream=80\nUnit=other.service\n",
+					},
+					{
+						Name: "group-http.service",
+					},
+				},
+			},
+			valid:        false,
+			errAssertion: IsTriggeredUnitNotInGroup,
+		},
+		// Test that slices of a group scaled to multiple slices must not run
+		// Docker containers of the same name.
+		{
+			request: Request{
+				RequestConfig: RequestConfig{
+					Group: "web",
+				},
+				DesiredSlices: 2,
+				Units: []Unit{
+					{
+						Name:    "web-main@.service",
+						Content: "[Service]\nExecStart=/usr/bin/docker run --rm --name web nginx\n",
+					},
+				},
+			},
+			valid:        false,
+			errAssertion: IsSliceCollision,
+		},
+		// Test that a single slice is allowed to run a named Docker container.
+		{
+			request: Request{
+				RequestConfig: RequestConfig{
+					Group:    "web",
+					SliceIDs: []string{"1"},
+				},
+				Units: []Unit{
+					{
+						Name:    "web-main@.service",
+						Content: "[Service]\nExecStart=/usr/bin/docker run --rm --name web nginx\n",
+					},
+				},
+			},
+			valid:        true,
+			errAssertion: nil,
+		},
 	}
 
 	for index, test := range tests {
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// verifyImages verifies that the Docker images used by the units of the given
+// request exist, in case Config.Registry is set. In case images do not exist,
+// an error that you can identify using IsImageNotFound is returned. Images
+// that cannot be verified, e.g. because their registry requires credentials,
+// are logged as warnings.
+func (c controller) verifyImages(ctx context.Context, req Request) error {
+	if c.Config.Registry == nil {
+		return nil
+	}
+	c.Config.Logger.Debug(ctx, "controller: verifying images of group '%s'", req.Group)
+
+	images := map[string][]string{}
+	for _, u := range req.Units {
+		for _, image := range UnitImages(u.Content) {
+			images[image] = append(images[image], u.Name)
+		}
+	}
+	var names []string
+	for image := range images {
+		names = append(names, image)
+	}
+	sort.Strings(names)
+
+	var missing []string
+	for _, image := range names {
+		ok, err := c.Config.Registry.ImageExists(ctx, image)
+		if err != nil {
+			c.Config.Logger.Warning(ctx, "Failed to verify image '%s'. (%s)", image, err.Error())
+			continue
+		}
+		if !ok {
+			missing = append(missing, "'"+image+"' of "+strings.Join(images[image], ", "))
+		}
+	}
+	if len(missing) > 0 {
+		return maskAnyf(imageNotFoundError, "%s", strings.Join(missing, "; "))
+	}
+
+	return nil
+}
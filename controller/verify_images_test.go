@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/juju/errgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+// registryFake knows the images of its map. Images mapped to false cannot be
+// verified.
+type registryFake map[string]bool
+
+func (r registryFake) ImageExists(ctx context.Context, image string) (bool, error) {
+	verifiable, ok := r[image]
+	if ok && !verifiable {
+		return false, errgo.New("unauthorized")
+	}
+	return ok, nil
+}
+
+func TestController_Submit_VerifyImages(t *testing.T) {
+	RegisterTestingT(t)
+
+	newController, _ := givenController()
+	c := newController.(*controller)
+	c.Config.Registry = registryFake{"nginx:1.9": true, "private.example.com/app:1.0": false}
+
+	req := Request{
+		RequestConfig: RequestConfig{Group: "app", SliceIDs: []string{"1"}},
+		Units: []Unit{
+			{Name: "app-web@.service", Content: "[Service]\nExecStart=/usr/bin/docker run --name web nginx:1.9\n"},
+			{Name: "app-api@.service", Content: "[Service]\nEnvironment=\"IMAGE=private.example.com/app:1.0\"\nExecStart=/usr/bin/docker run $IMAGE\n"},
+		},
+	}
+	Expect(c.verifyImages(context.Background(), req)).To(Succeed())
+
+	req.Units = append(req.Units, Unit{Name: "app-cache@.service", Content: "[Service]\nExecStartPre=/usr/bin/docker pull redis:3.O\nExecStart=/usr/bin/docker run redis:3.O\n"})
+	_, err := c.Submit(context.Background(), req)
+	Expect(IsImageNotFound(err)).To(BeTrue())
+	Expect(err.Error()).To(ContainSubstring("'redis:3.O' of app-cache@.service"))
+
+	// Without registry, images are not verified.
+	c.Config.Registry = nil
+	Expect(c.verifyImages(context.Background(), req)).To(Succeed())
+}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+const (
+	// versionLength is the number of hex characters used for group versions.
+	// All versions have the same length so that no versioned group name can be
+	// the prefix of another versioned group name of the same group.
+	versionLength = 6
+)
+
+// ContentVersion returns a short version identifier derived from the names and
+// contents of the given units. The same set of units always results in the
+// same version, regardless of the order of units and of the formatting of
+// their contents. See NormalizeUnitContent.
+func ContentVersion(units []Unit) string {
+	var lines []string
+	for _, u := range units {
+		content, err := NormalizeUnitContent(u.Content)
+		if err != nil {
+			content = u.Content
+		}
+		lines = append(lines, u.Name+"\n"+content)
+	}
+	sort.Strings(lines)
+
+	h := sha1.New()
+	for _, l := range lines {
+		h.Write([]byte(l))
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))[:versionLength]
+}
+
+// VersionedGroup returns the group name suffixed with the given version.
+//
+//   VersionedGroup("mygroup", "1a2b3c")  =>  "mygroup-1a2b3c"
+//
+func VersionedGroup(group, version string) string {
+	return group + "-" + version
+}
+
+// WithVersion returns a copy of r where the group name and the group prefix of
+// all unit names are suffixed with the given version. Having group "mygroup",
+// version "1a2b3c" and unit file "mygroup-foo@.service" results in the
+// following request.
+//
+//   group:  mygroup-1a2b3c
+//   units:  mygroup-1a2b3c-foo@.service
+//
+func (r Request) WithVersion(version string) Request {
+	versioned := VersionedGroup(r.Group, version)
+
+	var newUnits []Unit
+	for _, unit := range r.Units {
+		newUnit := unit
+		newUnit.Name = versioned + strings.TrimPrefix(unit.Name, r.Group)
+		newUnits = append(newUnits, newUnit)
+	}
+	r.Units = newUnits
+	r.ReadyStates = withReadyStatesGroup(r.ReadyStates, r.Group, versioned)
+	r.Group = versioned
+
+	return r
+}
+
+// versionedUnitExp returns an expression matching the names of units of all
+// versions of the given group, capturing the version.
+//
+//   mygroup-1a2b3c-foo@1.service  =>  1a2b3c
+//
+func versionedUnitExp(group string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`^%s-([a-f0-9]{%d})[-@.]`, regexp.QuoteMeta(group), versionLength))
+}
+
+// versionsFromUnitStatus extracts all versions of the given group that can be
+// found in the given unit status list.
+func versionsFromUnitStatus(group string, usl []fleet.UnitStatus) []string {
+	exp := versionedUnitExp(group)
+
+	var versions []string
+	for _, us := range usl {
+		found := exp.FindStringSubmatch(us.Name)
+		if len(found) != 2 {
+			continue
+		}
+		if contains(versions, found[1]) {
+			continue
+		}
+		versions = append(versions, found[1])
+	}
+	sort.Strings(versions)
+
+	return versions
+}
+
+func (c controller) ExistingVersions(ctx context.Context, group string) ([]string, error) {
+	c.Config.Logger.Debug(ctx, "controller: looking up existing versions of group '%s'", group)
+
+	usl, err := c.Fleet.GetStatusWithMatcher(func(name string) bool {
+		return strings.HasPrefix(name, group+"-")
+	})
+	if fleet.IsUnitNotFound(err) {
+		// There is no unit at all, thus there is no version either.
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return versionsFromUnitStatus(group, usl), nil
+}
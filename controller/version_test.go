@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/inago/fleet"
+)
+
+func Test_ContentVersion(t *testing.T) {
+	units := []Unit{
+		{Name: "foo-bar@.service", Content: "bar"},
+		{Name: "foo-baz@.service", Content: "baz"},
+	}
+	reversed := []Unit{units[1], units[0]}
+	changed := []Unit{units[0], {Name: "foo-baz@.service", Content: "changed"}}
+
+	v1 := ContentVersion(units)
+	if len(v1) != versionLength {
+		t.Fatal("expected", versionLength, "got", len(v1))
+	}
+	if v2 := ContentVersion(reversed); v1 != v2 {
+		t.Fatal("expected", v1, "got", v2)
+	}
+	if v3 := ContentVersion(changed); v1 == v3 {
+		t.Fatal("expected version to change for changed content")
+	}
+
+	formatted := []Unit{
+		{Name: "foo-bar@.service", Content: "[Unit]\nDescription=bar\n\n[Service]\nExecStart=/bin/bar\n"},
+	}
+	reformatted := []Unit{
+		{Name: "foo-bar@.service", Content: "[Service]\nExecStart = /bin/bar\n[Unit]\nDescription=bar\n"},
+	}
+	if v4, v5 := ContentVersion(formatted), ContentVersion(reformatted); v4 != v5 {
+		t.Fatal("expected", v4, "got", v5)
+	}
+}
+
+func Test_Request_WithVersion(t *testing.T) {
+	testCases := []struct {
+		Input    Request
+		Version  string
+		Expected Request
+	}{
+		{
+			Input: Request{
+				RequestConfig: RequestConfig{Group: "foo"},
+				Units: []Unit{
+					{Name: "foo-bar@.service", Content: "bar"},
+					{Name: "foo@.service", Content: "foo"},
+				},
+			},
+			Version: "1a2b3c",
+			Expected: Request{
+				RequestConfig: RequestConfig{Group: "foo-1a2b3c"},
+				Units: []Unit{
+					{Name: "foo-1a2b3c-bar@.service", Content: "bar"},
+					{Name: "foo-1a2b3c@.service", Content: "foo"},
+				},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		output := testCase.Input.WithVersion(testCase.Version)
+		if !reflect.DeepEqual(output, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", output)
+		}
+		if ok, err := ValidateRequest(output); !ok {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+	}
+}
+
+func Test_versionsFromUnitStatus(t *testing.T) {
+	usl := []fleet.UnitStatus{
+		{Name: "foo-bar@1.service"},
+		{Name: "foo-1a2b3c-bar@1.service"},
+		{Name: "foo-1a2b3c-bar@2.service"},
+		{Name: "foo-ffffff@1.service"},
+		{Name: "foo-abcdefg-bar@1.service"},
+		{Name: "foobar-123456-bar@1.service"},
+	}
+
+	output := versionsFromUnitStatus("foo", usl)
+	expected := []string{"1a2b3c", "ffffff"}
+	if !reflect.DeepEqual(output, expected) {
+		t.Fatal("expected", expected, "got", output)
+	}
+}
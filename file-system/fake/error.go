@@ -42,6 +42,23 @@ func IsNotADirectory(err error) bool {
 	if sce, ok := cause.(*os.SyscallError); ok {
 		return sce.Err == notADirectoryError
 	}
+	if pe, ok := cause.(*os.PathError); ok {
+		return pe.Err == notADirectoryError
+	}
 
 	return cause == notADirectoryError
 }
+
+var isADirectoryError = errgo.New("is a directory")
+
+// IsIsADirectory checks for the given error to be isADirectoryError. This
+// error is returned in case a directory is accessed like a file.
+func IsIsADirectory(err error) bool {
+	cause := errgo.Cause(err)
+
+	if pe, ok := cause.(*os.PathError); ok {
+		return pe.Err == isADirectoryError
+	}
+
+	return cause == isADirectoryError
+}
@@ -5,49 +5,104 @@ package filesystemfake
 import (
 	"os"
 	"path/filepath"
-	"strings"
+	"sort"
+	"sync"
 
 	"github.com/giantswarm/inago/file-system/spec"
 )
 
+// Operation describes a file system operation errors can be injected for.
+type Operation string
+
+const (
+	// ReadDirOperation identifies calls to FileSystem.ReadDir.
+	ReadDirOperation Operation = "ReadDir"
+
+	// ReadFileOperation identifies calls to FileSystem.ReadFile.
+	ReadFileOperation Operation = "ReadFile"
+
+	// WriteFileOperation identifies calls to FileSystem.WriteFile.
+	WriteFileOperation Operation = "WriteFile"
+
+	// MkdirAllOperation identifies calls to FileSystem.MkdirAll.
+	MkdirAllOperation Operation = "MkdirAll"
+)
+
+// FileSystem extends filesystemspec.FileSystem with functionality useful to
+// set up in memory content and failure scenarios in tests.
+type FileSystem interface {
+	filesystemspec.FileSystem
+
+	// MkdirAll is the equivalent to os.MkdirAll. It can be used to create empty
+	// directories. Note that WriteFile creates missing directories on its own.
+	MkdirAll(path string, perm os.FileMode) error
+
+	// InjectError causes all calls of the given operation against paths
+	// matching the given pattern to return the given error. The pattern syntax
+	// is the one of filepath.Match. Injected errors stay active until
+	// ClearErrors is called.
+	InjectError(op Operation, pattern string, err error)
+
+	// ClearErrors removes all errors registered using InjectError.
+	ClearErrors()
+}
+
 // NewFileSystem creates a new fake filesystem. Operations are made against in
 // memory content.
-func NewFileSystem() filesystemspec.FileSystem {
+func NewFileSystem() FileSystem {
 	newFileSystem := &fake{
+		Mutex:   sync.Mutex{},
 		Storage: map[string]os.FileInfo{},
+		Errors:  []injectedError{},
 	}
 
 	return newFileSystem
 }
 
+type injectedError struct {
+	Operation Operation
+	Pattern   string
+	Err       error
+}
+
 type fake struct {
+	Mutex   sync.Mutex
 	Storage map[string]os.FileInfo
+	Errors  []injectedError
 }
 
 func (f *fake) ReadDir(dirname string) ([]os.FileInfo, error) {
-	newFileInfos := []os.FileInfo{}
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
 
-	for filename, fi := range f.Storage {
-		if filename == dirname {
-			if fi.IsDir() {
-				continue
-			} else {
-				return nil, os.NewSyscallError("readdirent", notADirectoryError)
+	dirname = filepath.Clean(dirname)
+	if err := f.injectedError(ReadDirOperation, dirname); err != nil {
+		return nil, maskAny(err)
+	}
+
+	if !isRoot(dirname) {
+		fi, ok := f.Storage[dirname]
+		if !ok {
+			pathErr := &os.PathError{
+				Op:   "open",
+				Path: dirname,
+				Err:  noSuchFileOrDirectoryError,
 			}
+
+			return nil, maskAny(pathErr)
 		}
-		if !strings.HasPrefix(filename, dirname) {
-			continue
-		}
-		fn := strings.Replace(filename, dirname+"/", "", 1)
-		if fn == "" {
-			continue
+		if !fi.IsDir() {
+			return nil, os.NewSyscallError("readdirent", notADirectoryError)
 		}
-		splitted := strings.Split(fn, string(filepath.Separator))
-		if len(splitted) != 1 {
+	}
+
+	newFileInfos := []os.FileInfo{}
+	for filename, fi := range f.Storage {
+		if filepath.Dir(filename) != dirname {
 			continue
 		}
 		if c, ok := fi.(fileInfo); ok {
-			c.File.Name = splitted[0]
+			c.File.Name = filepath.Base(filename)
 			newFileInfos = append(newFileInfos, c)
 			continue
 		}
@@ -55,23 +110,34 @@ func (f *fake) ReadDir(dirname string) ([]os.FileInfo, error) {
 		return nil, maskAny(invalidImplementationError)
 	}
 
-	if len(newFileInfos) > 0 {
-		return newFileInfos, nil
-	}
+	// Sort the file infos by name to behave like ioutil.ReadDir.
+	sort.Sort(byName(newFileInfos))
 
-	pathErr := &os.PathError{
-		Op:   "open",
-		Path: dirname,
-		Err:  noSuchFileOrDirectoryError,
-	}
-
-	return nil, maskAny(pathErr)
+	return newFileInfos, nil
 }
 
 func (f *fake) ReadFile(filename string) ([]byte, error) {
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+
+	filename = filepath.Clean(filename)
+	if err := f.injectedError(ReadFileOperation, filename); err != nil {
+		return nil, maskAny(err)
+	}
+
 	if fi, ok := f.Storage[filename]; ok {
+		if fi.IsDir() {
+			pathErr := &os.PathError{
+				Op:   "read",
+				Path: filename,
+				Err:  isADirectoryError,
+			}
+
+			return nil, maskAny(pathErr)
+		}
 		if c, ok := fi.(fileInfo); ok {
-			b := c.File.Buffer.Bytes()
+			// Return a copy to prevent callers from modifying the stored content.
+			b := append([]byte{}, c.File.Buffer.Bytes()...)
 			return b, nil
 		}
 
@@ -88,18 +154,123 @@ func (f *fake) ReadFile(filename string) ([]byte, error) {
 }
 
 func (f *fake) WriteFile(filename string, bytes []byte, perm os.FileMode) error {
-	dir := filepath.Dir(filename)
-
-	if dir != "." {
-		var ps []string
-		for _, d := range strings.Split(filepath.FromSlash(dir), string(filepath.Separator)) {
-			ps = append(ps, d)
-			p := filepath.Join(ps...)
-			f.Storage[p] = newDirFileInfo(p)
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+
+	filename = filepath.Clean(filename)
+	if err := f.injectedError(WriteFileOperation, filename); err != nil {
+		return maskAny(err)
+	}
+
+	if fi, ok := f.Storage[filename]; ok && fi.IsDir() {
+		pathErr := &os.PathError{
+			Op:   "open",
+			Path: filename,
+			Err:  isADirectoryError,
 		}
+
+		return maskAny(pathErr)
 	}
 
-	f.Storage[filename] = newFileFileInfo(filename, bytes, perm)
+	err := f.mkdirAll(filepath.Dir(filename))
+	if err != nil {
+		return maskAny(err)
+	}
+
+	// Store a copy to prevent callers from modifying the stored content.
+	f.Storage[filename] = newFileFileInfo(filename, append([]byte{}, bytes...), perm)
 
 	return nil
 }
+
+func (f *fake) MkdirAll(path string, perm os.FileMode) error {
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+
+	path = filepath.Clean(path)
+	if err := f.injectedError(MkdirAllOperation, path); err != nil {
+		return maskAny(err)
+	}
+
+	err := f.mkdirAll(path)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (f *fake) InjectError(op Operation, pattern string, err error) {
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+
+	f.Errors = append(f.Errors, injectedError{
+		Operation: op,
+		Pattern:   filepath.Clean(pattern),
+		Err:       err,
+	})
+}
+
+func (f *fake) ClearErrors() {
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+
+	f.Errors = []injectedError{}
+}
+
+// injectedError returns the first error injected for the given operation and
+// path, if any. The caller must hold the lock.
+func (f *fake) injectedError(op Operation, path string) error {
+	for _, ie := range f.Errors {
+		if ie.Operation != op {
+			continue
+		}
+		if ok, err := filepath.Match(ie.Pattern, path); err == nil && ok {
+			return ie.Err
+		}
+	}
+
+	return nil
+}
+
+// mkdirAll creates the given directory and all its missing parents. The
+// caller must hold the lock.
+func (f *fake) mkdirAll(path string) error {
+	if isRoot(path) {
+		return nil
+	}
+
+	if fi, ok := f.Storage[path]; ok {
+		if fi.IsDir() {
+			return nil
+		}
+
+		pathErr := &os.PathError{
+			Op:   "mkdir",
+			Path: path,
+			Err:  notADirectoryError,
+		}
+
+		return maskAny(pathErr)
+	}
+
+	err := f.mkdirAll(filepath.Dir(path))
+	if err != nil {
+		return maskAny(err)
+	}
+	f.Storage[path] = newDirFileInfo(path)
+
+	return nil
+}
+
+// isRoot checks whether the given cleaned path describes the root of the
+// file system tree, which always exists implicitly.
+func isRoot(path string) bool {
+	return path == "." || path == string(filepath.Separator)
+}
+
+type byName []os.FileInfo
+
+func (n byName) Len() int           { return len(n) }
+func (n byName) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n byName) Less(i, j int) bool { return n[i].Name() < n[j].Name() }
@@ -3,6 +3,8 @@ package filesystemfake
 import (
 	"os"
 	"testing"
+
+	"github.com/juju/errgo"
 )
 
 type writeFiles struct {
@@ -243,3 +245,144 @@ func Test_FileSystem_ReadFile(t *testing.T) {
 		}
 	}
 }
+
+func Test_FileSystem_MkdirAll(t *testing.T) {
+	fs := NewFileSystem()
+
+	err := fs.MkdirAll("mydir/foo/bar", os.FileMode(0755))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	fileInfos, err := fs.ReadDir("mydir/foo/bar")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(fileInfos) != 0 {
+		t.Fatal("expected", 0, "got", len(fileInfos))
+	}
+
+	fileInfos, err = fs.ReadDir("mydir/foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(fileInfos) != 1 || fileInfos[0].Name() != "bar" || !fileInfos[0].IsDir() {
+		t.Fatal("expected", "bar", "got", fileInfos)
+	}
+
+	_, err = fs.ReadFile("mydir/foo")
+	if !IsIsADirectory(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	err = fs.WriteFile("mydir/file", []byte("content"), os.FileMode(0644))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = fs.MkdirAll("mydir/file/foo", os.FileMode(0755))
+	if !IsNotADirectory(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+	err = fs.WriteFile("mydir/foo", []byte("content"), os.FileMode(0644))
+	if !IsIsADirectory(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
+
+func Test_FileSystem_InjectError(t *testing.T) {
+	injectedErr := errgo.New("injected error")
+
+	testCases := []struct {
+		Operation Operation
+		Pattern   string
+		Call      func(fs FileSystem) error
+		Expected  bool // whether the injected error is expected
+	}{
+		{
+			Operation: ReadFileOperation,
+			Pattern:   "mydir/foo.service",
+			Call: func(fs FileSystem) error {
+				_, err := fs.ReadFile("mydir/foo.service")
+				return err
+			},
+			Expected: true,
+		},
+		{
+			Operation: ReadFileOperation,
+			Pattern:   "mydir/*",
+			Call: func(fs FileSystem) error {
+				_, err := fs.ReadFile("mydir/foo.service")
+				return err
+			},
+			Expected: true,
+		},
+		{
+			Operation: ReadFileOperation,
+			Pattern:   "otherdir/*",
+			Call: func(fs FileSystem) error {
+				_, err := fs.ReadFile("mydir/foo.service")
+				return err
+			},
+			Expected: false,
+		},
+		{
+			Operation: ReadDirOperation,
+			Pattern:   "mydir",
+			Call: func(fs FileSystem) error {
+				_, err := fs.ReadFile("mydir/foo.service")
+				return err
+			},
+			Expected: false,
+		},
+		{
+			Operation: ReadDirOperation,
+			Pattern:   "mydir",
+			Call: func(fs FileSystem) error {
+				_, err := fs.ReadDir("mydir/")
+				return err
+			},
+			Expected: true,
+		},
+		{
+			Operation: WriteFileOperation,
+			Pattern:   "mydir/bar.service",
+			Call: func(fs FileSystem) error {
+				return fs.WriteFile("mydir/bar.service", []byte("content"), os.FileMode(0644))
+			},
+			Expected: true,
+		},
+		{
+			Operation: MkdirAllOperation,
+			Pattern:   "otherdir",
+			Call: func(fs FileSystem) error {
+				return fs.MkdirAll("otherdir", os.FileMode(0755))
+			},
+			Expected: true,
+		},
+	}
+
+	for i, testCase := range testCases {
+		fs := NewFileSystem()
+		err := fs.WriteFile("mydir/foo.service", []byte("content"), os.FileMode(0644))
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		fs.InjectError(testCase.Operation, testCase.Pattern, injectedErr)
+
+		err = testCase.Call(fs)
+		if testCase.Expected && errgo.Cause(err) != injectedErr {
+			t.Fatal("case", i+1, "expected", injectedErr, "got", err)
+		}
+		if !testCase.Expected && err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		fs.ClearErrors()
+
+		err = testCase.Call(fs)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+	}
+}
@@ -94,6 +94,10 @@ func (fi fileInfo) Name() string {
 // Size returns the length in bytes of the file's internal bytes.Reader
 // instance.
 func (fi fileInfo) Size() int64 {
+	if fi.File.Buffer == nil {
+		return 0
+	}
+
 	return int64(fi.File.Buffer.Len())
 }
 
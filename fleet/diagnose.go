@@ -0,0 +1,207 @@
+package fleet
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/juju/errgo"
+	"golang.org/x/net/context"
+)
+
+var (
+	// DefaultSocketPaths contains the paths fleet's unix socket is usually found
+	// at, in order of preference.
+	DefaultSocketPaths = []string{"/var/run/fleet.sock", "/run/fleet.sock"}
+
+	// diagnoseTimeout is the time each check of Diagnose may take at most.
+	diagnoseTimeout = 5 * time.Second
+)
+
+// Diagnosis is the result of a single check run by Diagnose.
+type Diagnosis struct {
+	// Check describes what has been checked, e.g. "socket /var/run/fleet.sock
+	// exists".
+	Check string
+
+	// Err is the reason the check failed. It is nil in case the check passed.
+	Err error
+
+	// Hint describes how to fix the problem found by a failed check.
+	Hint string
+}
+
+// Diagnose checks step by step whether the fleet API can be reached using the
+// given configuration. Checks are run until the first one fails. So the last
+// diagnosis returned either describes the problem, or that the API works.
+//
+//   - for unix sockets, whether the socket exists and can be connected to
+//   - for TCP endpoints, whether the endpoint accepts connections
+//   - whether the endpoint answers /fleet/v1/machines like the fleet API
+//
+func Diagnose(ctx context.Context, config Config) []Diagnosis {
+	var diagnoses []Diagnosis
+	check := func(d Diagnosis) bool {
+		diagnoses = append(diagnoses, d)
+		return d.Err == nil
+	}
+
+	if config.SSHTunnel == nil {
+		switch config.Endpoint.Scheme {
+		case "unix", "file":
+			if !check(diagnoseSocket(config.Endpoint.Path)) {
+				return diagnoses
+			}
+		case "http", "https":
+			if !check(diagnoseTCP(config.Endpoint)) {
+				return diagnoses
+			}
+		}
+	}
+
+	config.Client = &http.Client{Timeout: diagnoseTimeout}
+	config.RateLimit = 0
+	newFleet, err := NewFleet(config)
+	if err != nil {
+		check(Diagnosis{
+			Check: "endpoint is valid",
+			Err:   err,
+			Hint:  "Use --fleet-endpoint with a URL like unix:///var/run/fleet.sock or http://127.0.0.1:49153.",
+		})
+		return diagnoses
+	}
+	check(newFleet.(fleet).diagnoseMachines())
+
+	return diagnoses
+}
+
+// DetectSocket returns the first path of DefaultSocketPaths a unix socket
+// exists at. An empty string is returned in case there is none.
+func DetectSocket() string {
+	for _, p := range DefaultSocketPaths {
+		if fi, err := os.Stat(p); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			return p
+		}
+	}
+
+	return ""
+}
+
+// IsConnectionFailed checks whether the given error indicates that the fleet
+// API could not be reached at all, as opposed to the API answering with an
+// error. Diagnose helps to find out why.
+func IsConnectionFailed(err error) bool {
+	cause := errgo.Cause(err)
+	if urlErr, ok := cause.(*url.Error); ok {
+		cause = errgo.Cause(urlErr.Err)
+	}
+	_, ok := cause.(*net.OpError)
+	return ok
+}
+
+func diagnoseSocket(socketPath string) Diagnosis {
+	d := Diagnosis{Check: fmt.Sprintf("socket %s exists", socketPath)}
+	fi, err := os.Stat(socketPath)
+	if os.IsNotExist(err) {
+		d.Err = maskAny(err)
+		d.Hint = "fleet does not seem to run on this machine. Start it using 'systemctl start fleet', or connect to a remote cluster using --fleet-endpoint or --tunnel."
+		if detected := DetectSocket(); detected != "" && detected != socketPath {
+			d.Hint = fmt.Sprintf("fleet listens on %s. Use --fleet-endpoint unix://%s.", detected, detected)
+		}
+		return d
+	} else if err != nil {
+		d.Err = maskAny(err)
+		d.Hint = fmt.Sprintf("Check the permissions of the directories containing %s.", socketPath)
+		return d
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		d.Err = maskAnyf(invalidEndpointError, "%s is not a socket", socketPath)
+		d.Hint = "Use --fleet-endpoint to point to fleet's unix socket."
+		return d
+	}
+
+	d = Diagnosis{Check: fmt.Sprintf("socket %s accepts connections", socketPath)}
+	conn, err := net.DialTimeout("unix", socketPath, diagnoseTimeout)
+	if err != nil {
+		d.Err = maskAny(err)
+		if strings.Contains(err.Error(), "permission denied") {
+			d.Hint = fmt.Sprintf("The current user is not allowed to access %s. Run inagoctl as root, or as a member of the group owning the socket.", socketPath)
+		} else {
+			d.Hint = "fleet does not accept connections. Check 'systemctl status fleet.socket fleet'."
+		}
+		return d
+	}
+	conn.Close()
+
+	return d
+}
+
+func diagnoseTCP(endpoint url.URL) Diagnosis {
+	host := endpoint.Host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		if endpoint.Scheme == "https" {
+			host = net.JoinHostPort(host, "443")
+		} else {
+			host = net.JoinHostPort(host, "80")
+		}
+	}
+
+	d := Diagnosis{Check: fmt.Sprintf("%s accepts connections", host)}
+	conn, err := net.DialTimeout("tcp", host, diagnoseTimeout)
+	if err != nil {
+		d.Err = maskAny(err)
+		d.Hint = "Check the address given using --fleet-endpoint, that fleet's API listens on it, e.g. using ListenStream in fleet.socket, and that no firewall blocks it. Use --tunnel to connect via SSH instead."
+		return d
+	}
+	conn.Close()
+
+	return d
+}
+
+// machinesResponse represents the parts of fleet's machines list we are
+// interested in.
+type machinesResponse struct {
+	Machines []json.RawMessage `json:"machines"`
+}
+
+func (f fleet) diagnoseMachines() Diagnosis {
+	URL := f.Config.Endpoint
+	URL.Path = path.Join(URL.Path, "fleet", "v1", "machines")
+
+	d := Diagnosis{Check: "endpoint answers /fleet/v1/machines"}
+	resp, err := f.Config.Client.Get(URL.String())
+	if err != nil {
+		d.Err = maskAny(err)
+		d.Hint = "The connection was established, but the request failed. Check that the endpoint is fleet's API."
+		if f.Config.SSHTunnel != nil {
+			d.Hint = "Check that the tunnel host can be reached via SSH and runs fleet. See also --ssh-username and --ssh-known-hosts-file."
+		}
+		return d
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		d.Err = maskAnyf(invalidAPIResponseError, "unexpected status code %d", resp.StatusCode)
+		d.Hint = "The endpoint does not look like fleet's API. Check --fleet-endpoint."
+		return d
+	}
+	var machines machinesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&machines); err != nil {
+		d.Err = maskAnyf(invalidAPIResponseError, "%s", err.Error())
+		d.Hint = "The endpoint does not look like fleet's API. Check --fleet-endpoint."
+		return d
+	}
+	if len(machines.Machines) == 0 {
+		d.Err = maskAnyf(invalidAPIResponseError, "no machines registered")
+		d.Hint = "fleet answers, but does not know any machine. Check that etcd is healthy and fleet runs on the machines of the cluster."
+		return d
+	}
+
+	return d
+}
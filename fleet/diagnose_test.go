@@ -0,0 +1,128 @@
+package fleet
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func givenMachinesHandler(machines string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fleet/v1/machines" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"machines":[%s]}`, machines)
+	})
+}
+
+func TestDiagnose_Socket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inago-diagnose")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	socketPath := filepath.Join(dir, "fleet.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go http.Serve(listener, givenMachinesHandler(`{"id":"505e0d7802d7439a924c269b76f34b5f"}`))
+
+	filePath := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(filePath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Path          string
+		NumDiagnoses  int
+		ExpectFailure bool
+	}{
+		{Path: filepath.Join(dir, "missing.sock"), NumDiagnoses: 1, ExpectFailure: true},
+		{Path: filePath, NumDiagnoses: 1, ExpectFailure: true},
+		{Path: socketPath, NumDiagnoses: 2, ExpectFailure: false},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultConfig()
+		config.Endpoint = url.URL{Scheme: "unix", Path: testCase.Path}
+
+		diagnoses := Diagnose(context.Background(), config)
+		if len(diagnoses) != testCase.NumDiagnoses {
+			t.Fatal("case", i+1, "expected", testCase.NumDiagnoses, "got", diagnoses)
+		}
+		last := diagnoses[len(diagnoses)-1]
+		if failed := last.Err != nil; failed != testCase.ExpectFailure {
+			t.Fatal("case", i+1, "expected", testCase.ExpectFailure, "got", last)
+		}
+		if failed := last.Hint != ""; failed != testCase.ExpectFailure {
+			t.Fatal("case", i+1, "expected hint", testCase.ExpectFailure, "got", last)
+		}
+	}
+}
+
+func TestDiagnose_HTTP(t *testing.T) {
+	notFleet := httptest.NewServer(http.NotFoundHandler())
+	defer notFleet.Close()
+	noMachines := httptest.NewServer(givenMachinesHandler(""))
+	defer noMachines.Close()
+	healthy := httptest.NewServer(givenMachinesHandler(`{"id":"505e0d7802d7439a924c269b76f34b5f"}`))
+	defer healthy.Close()
+
+	// Find a port nobody listens on.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	testCases := []struct {
+		Endpoint      string
+		NumDiagnoses  int
+		ExpectFailure bool
+	}{
+		{Endpoint: closed.URL, NumDiagnoses: 1, ExpectFailure: true},
+		{Endpoint: notFleet.URL, NumDiagnoses: 2, ExpectFailure: true},
+		{Endpoint: noMachines.URL, NumDiagnoses: 2, ExpectFailure: true},
+		{Endpoint: healthy.URL, NumDiagnoses: 2, ExpectFailure: false},
+	}
+
+	for i, testCase := range testCases {
+		endpoint, err := url.Parse(testCase.Endpoint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config := DefaultConfig()
+		config.Endpoint = *endpoint
+
+		diagnoses := Diagnose(context.Background(), config)
+		if len(diagnoses) != testCase.NumDiagnoses {
+			t.Fatal("case", i+1, "expected", testCase.NumDiagnoses, "got", diagnoses)
+		}
+		last := diagnoses[len(diagnoses)-1]
+		if failed := last.Err != nil; failed != testCase.ExpectFailure {
+			t.Fatal("case", i+1, "expected", testCase.ExpectFailure, "got", last)
+		}
+	}
+}
+
+func TestIsConnectionFailed(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	_, err := http.Get(closed.URL)
+	if !IsConnectionFailed(maskAny(err)) {
+		t.Fatal("expected", true, "got", false)
+	}
+	if IsConnectionFailed(maskAnyf(invalidAPIResponseError, "unexpected status code 404")) {
+		t.Fatal("expected", false, "got", true)
+	}
+}
@@ -1,6 +1,8 @@
 package fleet
 
 import (
+	"sort"
+	"strconv"
 	"sync"
 
 	"golang.org/x/net/context"
@@ -18,9 +20,10 @@ type DummyConfig struct {
 // DummyFleet is an implementation of the Fleet interface,
 // that is primarily intended to be used for testing.
 type DummyFleet struct {
-	Config DummyConfig
-	Units  map[string]UnitStatus
-	Mutex  sync.Mutex
+	Config   DummyConfig
+	Units    map[string]UnitStatus
+	Contents map[string]string
+	Mutex    sync.Mutex
 }
 
 // DefaultDummyConfig returns a best-effort configuration for the DummyFleet struct.
@@ -33,8 +36,9 @@ func DefaultDummyConfig() DummyConfig {
 // NewDummyFleet returns a DummyFleet, given a DummyConfig.
 func NewDummyFleet(DummyConfig) *DummyFleet {
 	return &DummyFleet{
-		Config: DefaultDummyConfig(),
-		Units:  make(map[string]UnitStatus),
+		Config:   DefaultDummyConfig(),
+		Units:    make(map[string]UnitStatus),
+		Contents: make(map[string]string),
 	}
 }
 
@@ -62,6 +66,7 @@ func (f *DummyFleet) Submit(ctx context.Context, name, content string) error {
 			},
 		},
 	}
+	f.Contents[name] = content
 
 	return nil
 }
@@ -134,6 +139,7 @@ func (f *DummyFleet) Destroy(ctx context.Context, name string) error {
 	}
 
 	delete(f.Units, name)
+	delete(f.Contents, name)
 
 	return nil
 }
@@ -173,3 +179,110 @@ func (f *DummyFleet) GetStatusWithMatcher(m func(string) bool) ([]UnitStatus, er
 
 	return unitStatusList, nil
 }
+
+// GetContent returns the content the given unit was submitted with.
+func (f *DummyFleet) GetContent(ctx context.Context, name string) (string, error) {
+	f.Config.Logger.Debug(ctx, "dummy fleet: get content %v", name)
+
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+
+	content, ok := f.Contents[name]
+	if !ok {
+		return "", maskAny(unitNotFoundError)
+	}
+
+	return content, nil
+}
+
+// Exists checks whether the given unit has been submitted.
+func (f *DummyFleet) Exists(ctx context.Context, name string) (bool, error) {
+	f.Config.Logger.Debug(ctx, "dummy fleet: exists %v", name)
+
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+
+	_, ok := f.Contents[name]
+
+	return ok, nil
+}
+
+// APIVersion returns the latest supported fleet API version.
+func (f *DummyFleet) APIVersion(ctx context.Context) (string, error) {
+	return SupportedAPIVersions[len(SupportedAPIVersions)-1], nil
+}
+
+// Machines returns the machines the stored units are scheduled on, since
+// DummyFleet does not know about other machines.
+func (f *DummyFleet) Machines(ctx context.Context) ([]Machine, error) {
+	f.Config.Logger.Debug(ctx, "dummy fleet: machines")
+
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+
+	var machines []Machine
+	seen := map[string]bool{}
+	for _, unitStatus := range f.Units {
+		for _, ms := range unitStatus.Machine {
+			if !seen[ms.ID] {
+				seen[ms.ID] = true
+				machines = append(machines, Machine{ID: ms.ID, IP: ms.IP})
+			}
+		}
+	}
+	sort.Sort(machinesByID(machines))
+
+	return machines, nil
+}
+
+// dummyUnitsPageSize is the number of units on each page returned by
+// DummyFleet.UnitsPage.
+const dummyUnitsPageSize = 100
+
+// UnitsPage returns the units sorted by name, in pages of 100 units. The page
+// token is the index of the first unit of the page.
+func (f *DummyFleet) UnitsPage(ctx context.Context, pageToken string) (UnitPage, error) {
+	f.Config.Logger.Debug(ctx, "dummy fleet: units page %v", pageToken)
+
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+
+	var names []string
+	for name := range f.Units {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	start := 0
+	if pageToken != "" {
+		var err error
+		start, err = strconv.Atoi(pageToken)
+		if err != nil || start < 0 || start > len(names) {
+			return UnitPage{}, maskAnyf(invalidPageTokenError, "%s", pageToken)
+		}
+	}
+	end := start + dummyUnitsPageSize
+	if end > len(names) {
+		end = len(names)
+	}
+
+	page := UnitPage{}
+	for _, name := range names[start:end] {
+		unitStatus := f.Units[name]
+		newUnit := Unit{
+			Name:    name,
+			Content: f.Contents[name],
+			Current: unitStatus.Current,
+			Desired: unitStatus.Desired,
+		}
+		if len(unitStatus.Machine) == 1 {
+			newUnit.MachineID = unitStatus.Machine[0].ID
+		}
+		page.Units = append(page.Units, newUnit)
+	}
+	if end < len(names) {
+		page.NextPageToken = strconv.Itoa(end)
+	}
+
+	return page, nil
+}
@@ -1,6 +1,7 @@
 package fleet
 
 import (
+	"fmt"
 	"testing"
 
 	"golang.org/x/net/context"
@@ -158,3 +159,44 @@ func TestDummyFleet__GetStatusWithMatcher(t *testing.T) {
 		t.Fatal("Incorrect unit status list returned")
 	}
 }
+
+// TestDummyFleet__UnitsPage tests the DummyFleet UnitsPage method.
+func TestDummyFleet__UnitsPage(t *testing.T) {
+	dummyFleet := NewDummyFleet(DefaultDummyConfig())
+
+	for i := 0; i < dummyUnitsPageSize+1; i++ {
+		name := fmt.Sprintf("unit-%03d.service", i)
+		if err := dummyFleet.Submit(context.Background(), name, UnitContent); err != nil {
+			t.Fatal("Error submitting the test unit:", err)
+		}
+	}
+
+	first, err := dummyFleet.UnitsPage(context.Background(), "")
+	if err != nil {
+		t.Fatal("Error getting first page:", err)
+	}
+	if len(first.Units) != dummyUnitsPageSize {
+		t.Fatal("Incorrect number of units on first page:", len(first.Units))
+	}
+	if first.Units[0].Name != "unit-000.service" || first.Units[0].Content != UnitContent {
+		t.Fatal("Incorrect first unit:", first.Units[0])
+	}
+	if first.NextPageToken == "" {
+		t.Fatal("Expected next page token on first page")
+	}
+
+	second, err := dummyFleet.UnitsPage(context.Background(), first.NextPageToken)
+	if err != nil {
+		t.Fatal("Error getting second page:", err)
+	}
+	if len(second.Units) != 1 || second.Units[0].Name != "unit-100.service" {
+		t.Fatal("Incorrect units on second page:", second.Units)
+	}
+	if second.NextPageToken != "" {
+		t.Fatal("Expected no next page token on last page:", second.NextPageToken)
+	}
+
+	if _, err := dummyFleet.UnitsPage(context.Background(), "bogus"); !IsInvalidPageToken(err) {
+		t.Fatal("Expected invalid page token error:", err)
+	}
+}
@@ -2,7 +2,11 @@ package fleet
 
 import (
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 
+	"github.com/coreos/fleet/Godeps/_workspace/src/google.golang.org/api/googleapi"
 	"github.com/juju/errgo"
 )
 
@@ -66,3 +70,117 @@ var invalidEndpointError = errgo.Newf("invalid endpoint")
 func IsInvalidEndpoint(err error) bool {
 	return errgo.Cause(err) == invalidEndpointError
 }
+
+var invalidAPIResponseError = errgo.New("invalid API response")
+
+// IsInvalidAPIResponse checks whether the given error indicates the problem of
+// fleet's API answering with something we cannot interpret.
+func IsInvalidAPIResponse(err error) bool {
+	return errgo.Cause(err) == invalidAPIResponseError
+}
+
+var targetStateNotSetError = errgo.New("target state not set")
+
+// IsTargetStateNotSet checks whether the given error indicates the problem of
+// fleet not persisting the target state of a unit. This can only be returned
+// in case Config.VerifyTargetState is enabled.
+func IsTargetStateNotSet(err error) bool {
+	return errgo.Cause(err) == targetStateNotSetError
+}
+
+var invalidPageTokenError = errgo.New("invalid page token")
+
+// IsInvalidPageToken checks whether the given error indicates the problem of
+// fleet rejecting the page token given to Fleet.UnitsPage.
+func IsInvalidPageToken(err error) bool {
+	return errgo.Cause(err) == invalidPageTokenError
+}
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig checks whether the given error indicates the problem of a
+// configuration that cannot be used, e.g. a failure rate of the Simulator
+// outside of 0 to 1.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var resourceSamplingFailedError = errgo.New("resource sampling failed")
+
+// IsResourceSamplingFailed checks whether the given error indicates the
+// problem of a machine not answering with resource usage that can be
+// interpreted, e.g. because systemd-cgtop is not available.
+func IsResourceSamplingFailed(err error) bool {
+	return errgo.Cause(err) == resourceSamplingFailedError
+}
+
+var conflictError = errgo.New("conflict")
+
+// IsConflict checks whether the given error indicates the problem of fleet
+// answering with 409 Conflict, e.g. because a unit having the same name but
+// different content already exists.
+func IsConflict(err error) bool {
+	return errgo.Cause(err) == conflictError
+}
+
+var registryUnavailableError = errgo.New("registry unavailable")
+
+// IsRegistryUnavailable checks whether the given error indicates the problem
+// of fleet answering with a server error, which usually means fleet cannot
+// reach its registry, i.e. etcd. Retrying later may help.
+func IsRegistryUnavailable(err error) bool {
+	return errgo.Cause(err) == registryUnavailableError
+}
+
+// IsTimeout checks whether the given error indicates the problem of a call
+// of the fleet API timing out, e.g. because of the timeout of Config.Client.
+// Retrying later may help.
+func IsTimeout(err error) bool {
+	cause := errgo.Cause(err)
+	if urlErr, ok := cause.(*url.Error); ok {
+		cause = errgo.Cause(urlErr.Err)
+	}
+	netErr, ok := cause.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+// statusCodeError returns the error classifying the given HTTP status code
+// answered by the fleet API, or nil in case there is no such error.
+func statusCodeError(code int) error {
+	switch {
+	case code == http.StatusNotFound:
+		return unitNotFoundError
+	case code == http.StatusConflict:
+		return conflictError
+	case code >= http.StatusInternalServerError:
+		return registryUnavailableError
+	}
+
+	return nil
+}
+
+// classifyAPIError turns errors of fleet's API client answering with an HTTP
+// error into the errors of this package, so that they can be identified using
+// e.g. IsConflict instead of matching error messages. Other errors are
+// returned as they are.
+func classifyAPIError(err error) error {
+	apiErr, ok := errgo.Cause(err).(*googleapi.Error)
+	if !ok {
+		return err
+	}
+	classified := statusCodeError(apiErr.Code)
+	if classified == nil {
+		return err
+	}
+
+	return maskAnyf(classified, "%d %s", apiErr.Code, apiErr.Message)
+}
+
+var unitsNotScheduledError = errgo.New("units not scheduled")
+
+// IsUnitsNotScheduled checks whether the given error indicates the problem of
+// units not being scheduled on any machine, e.g. because no fleet engine is
+// leading the cluster. See CheckHealth.
+func IsUnitsNotScheduled(err error) bool {
+	return errgo.Cause(err) == unitsNotScheduledError
+}
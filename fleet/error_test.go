@@ -1,8 +1,15 @@
 package fleet
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"testing"
+
+	"github.com/coreos/fleet/Godeps/_workspace/src/google.golang.org/api/googleapi"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
 )
 
 func Test_Fleet_maskAnyf(t *testing.T) {
@@ -83,6 +90,14 @@ func Test_Fleet_errors(t *testing.T) {
 			Output:   IsInvalidEndpoint(invalidUnitStatusError),
 			Expected: false,
 		},
+		{
+			Output:   IsInvalidAPIResponse(invalidAPIResponseError),
+			Expected: true,
+		},
+		{
+			Output:   IsInvalidAPIResponse(invalidEndpointError),
+			Expected: false,
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -91,3 +106,55 @@ func Test_Fleet_errors(t *testing.T) {
 		}
 	}
 }
+
+// testTimeoutError implements net.Error timing out.
+type testTimeoutError struct{}
+
+func (testTimeoutError) Error() string   { return "i/o timeout" }
+func (testTimeoutError) Timeout() bool   { return true }
+func (testTimeoutError) Temporary() bool { return true }
+
+func Test_Fleet_classifyAPIError(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(classifyAPIError(nil)).To(BeNil())
+
+	failure := errors.New("test error")
+	Expect(classifyAPIError(failure)).To(Equal(failure))
+
+	err := classifyAPIError(&googleapi.Error{Code: http.StatusNotFound, Message: "unit does not exist"})
+	Expect(IsUnitNotFound(err)).To(BeTrue())
+	err = classifyAPIError(&googleapi.Error{Code: http.StatusConflict, Message: "unit already exists"})
+	Expect(IsConflict(err)).To(BeTrue())
+	Expect(err.Error()).To(ContainSubstring("unit already exists"))
+	err = classifyAPIError(&googleapi.Error{Code: http.StatusInternalServerError, Message: "registry error"})
+	Expect(IsRegistryUnavailable(err)).To(BeTrue())
+	err = classifyAPIError(maskAny(&googleapi.Error{Code: http.StatusServiceUnavailable}))
+	Expect(IsRegistryUnavailable(err)).To(BeTrue())
+
+	// Errors the classification does not know about are kept.
+	apiErr := &googleapi.Error{Code: http.StatusBadRequest}
+	Expect(classifyAPIError(apiErr)).To(Equal(apiErr))
+}
+
+func Test_Fleet_IsTimeout(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(IsTimeout(nil)).To(BeFalse())
+	Expect(IsTimeout(errors.New("test error"))).To(BeFalse())
+	Expect(IsTimeout(maskAny(testTimeoutError{}))).To(BeTrue())
+	Expect(IsTimeout(&url.Error{Op: "Get", URL: "http://fleet", Err: testTimeoutError{}})).To(BeTrue())
+	Expect(IsTimeout(&url.Error{Op: "Get", URL: "http://fleet", Err: errors.New("test error")})).To(BeFalse())
+}
+
+func TestHooks_classifiedErrors(t *testing.T) {
+	RegisterTestingT(t)
+
+	mock, fleet := givenMockedFleet()
+	fleet.Client = hookedAPI{API: mock}
+
+	mock.On("DestroyUnit", "unit.service").Once().Return(&googleapi.Error{Code: http.StatusNotFound})
+	err := fleet.Destroy(context.Background(), "unit.service")
+	Expect(IsUnitNotFound(err)).To(BeTrue())
+	mock.AssertExpectations(t)
+}
@@ -4,10 +4,13 @@
 package fleet
 
 import (
+	"crypto/tls"
 	"net"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/coreos/fleet/client"
 	"github.com/coreos/fleet/machine"
@@ -32,8 +35,63 @@ type Config struct {
 	Endpoint  url.URL
 	SSHTunnel SSHTunnel
 
+	// TLSConfig configures connections to https endpoints, e.g. to verify the
+	// endpoint using a private CA, or to authenticate using a client
+	// certificate. In case it is nil, the system's CAs are used.
+	TLSConfig *tls.Config
+
+	// Proxy is the HTTP or SOCKS5 proxy requests to http and https endpoints
+	// are sent through, e.g. "http://proxy.example.com:3128". In case it is
+	// nil, the proxy is taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	// environment variables. Proxies cannot be used together with unix
+	// sockets and tunnels.
+	Proxy *url.URL
+
+	// Compression defines whether responses of the fleet API are requested
+	// gzip compressed. Listing the units of large registries returns several
+	// megabytes, which compress well. Fleet itself does not compress responses,
+	// but reverse proxies in front of it usually do. Request bodies are not
+	// compressed, since fleet does not accept compressed requests.
+	Compression bool
+
+	// MaxIdleConnsPerHost is the number of idle connections to the fleet API
+	// kept open to be reused. Bulk operations call the fleet API concurrently,
+	// so keeping more connections than http.DefaultMaxIdleConnsPerHost avoids
+	// connecting, including the TLS handshake, for most calls. It does not
+	// apply to tunnels, which send one request at a time.
+	MaxIdleConnsPerHost int
+
 	// Logger provides an initialised logger.
 	Logger logging.Logger
+
+	// VerifyTargetState defines whether the unit is re-read after setting its
+	// target state, to verify the desired state was actually persisted by fleet.
+	// If fleet ignored the transition, an error that you can identify using
+	// IsTargetStateNotSet is returned.
+	VerifyTargetState bool
+
+	// RateLimit is the maximum number of requests per second sent to the fleet
+	// API. This prevents bulk operations against large groups from
+	// overwhelming fleet and etcd. Zero disables rate limiting.
+	RateLimit float64
+
+	// RateBurst is the number of requests allowed to exceed RateLimit for a
+	// short period of time.
+	RateBurst int
+
+	// Hooks are called around each call of the fleet API. See Hooks.
+	Hooks Hooks
+
+	// UserAgent is the User-Agent header calls of the fleet API are sent with,
+	// e.g. "inagoctl/0.4.0".
+	UserAgent string
+
+	// RequestID identifies the logical operation the client is used for, e.g.
+	// a command of inagoctl. Each call of the fleet API carries it in the
+	// RequestIDHeader, so that the calls of an operation can be found in the
+	// logs of fleet. In case it is empty, NewFleet generates one. See
+	// NewRequestID.
+	RequestID string
 }
 
 // DefaultConfig provides a set of configurations with default values by best
@@ -49,6 +107,21 @@ func DefaultConfig() Config {
 		Endpoint:  *URL,
 		Logger:    logging.NewLogger(logging.DefaultConfig()),
 		SSHTunnel: nil,
+		TLSConfig: nil,
+		Proxy:     nil,
+
+		Compression:         true,
+		MaxIdleConnsPerHost: 16,
+
+		VerifyTargetState: false,
+
+		RateLimit: 20,
+		RateBurst: 40,
+
+		Hooks: Hooks{},
+
+		UserAgent: DefaultUserAgent,
+		RequestID: "",
 	}
 
 	return newConfig
@@ -63,6 +136,10 @@ type MachineStatus struct {
 	// IP represents the machines IP where the related unit is running on.
 	IP net.IP
 
+	// SystemdLoad represents the unit's systemd load state, e.g. "loaded", or
+	// "not-found" in case systemd failed to load the unit. See LoadError.
+	SystemdLoad string
+
 	// SystemdActive represents the unit's systemd active state.
 	SystemdActive string
 
@@ -71,6 +148,43 @@ type MachineStatus struct {
 
 	// UnitHash represents a unique token to identify the content of the unitfile.
 	UnitHash string
+
+	// Vanished is true in case the machine is not part of the fleet cluster
+	// anymore, e.g. because it died, while fleet still reports the unit's
+	// state on it. IP is nil then.
+	Vanished bool
+}
+
+// Machine represents a machine of the fleet cluster.
+type Machine struct {
+	// ID represents the machines fleet agent ID.
+	ID string
+
+	// IP represents the machines public IP.
+	IP net.IP
+}
+
+// systemdLoadErrors describes the systemd load states of units systemd failed
+// to load.
+var systemdLoadErrors = map[string]string{
+	"bad-setting": "unit file contains an invalid setting",
+	"error":       "unit file could not be loaded",
+	"masked":      "unit is masked",
+	"not-found":   "unit file or a unit it references was not found",
+}
+
+// LoadError describes why systemd failed to load the unit on the machine, based
+// on its systemd load state. An empty string is returned in case the unit is
+// loaded, or its load state is unknown.
+func (ms MachineStatus) LoadError() string {
+	if ms.SystemdLoad == "" || ms.SystemdLoad == "loaded" {
+		return ""
+	}
+	if description, ok := systemdLoadErrors[ms.SystemdLoad]; ok {
+		return ms.SystemdLoad + ": " + description
+	}
+
+	return ms.SystemdLoad
 }
 
 // UnitStatus represents the status of a unit.
@@ -121,6 +235,49 @@ type Fleet interface {
 	// GetStatusWithMatcher returns a []UnitStatus, with an element for
 	// each unit where the given matcher returns true.
 	GetStatusWithMatcher(func(string) bool) ([]UnitStatus, error)
+
+	// GetContent fetches the unit file content of a unit as known to fleet. If
+	// the unit cannot be found, an error that you can identify using
+	// IsUnitNotFound is returned.
+	GetContent(ctx context.Context, name string) (string, error)
+
+	// Exists checks whether a unit with the given name is known to fleet. Only
+	// the given unit is fetched, instead of listing all units. This is much
+	// cheaper than GetStatus on clusters running many units.
+	Exists(ctx context.Context, name string) (bool, error)
+
+	// APIVersion fetches the version of the fleet API provided by the configured
+	// endpoint. See also SupportedAPIVersions.
+	APIVersion(ctx context.Context) (string, error)
+
+	// UnitsPage fetches a single page of the units known to fleet. An empty
+	// page token fetches the first page. The returned page contains the token
+	// to fetch the next page with, which is empty for the last page. Since each
+	// page is fetched using a separate request, listing can be resumed after
+	// failures by calling UnitsPage again using the last token. Note that the
+	// units are not a consistent snapshot, units may be added or removed while
+	// listing. In case fleet rejects the page token, e.g. because it is
+	// malformed, an error that you can identify using IsInvalidPageToken is
+	// returned.
+	//
+	//   var token string
+	//   for {
+	//     page, err := newFleet.UnitsPage(ctx, token)
+	//     if err != nil {
+	//       // Retry later using token.
+	//     }
+	//     // Process page.Units.
+	//     if page.NextPageToken == "" {
+	//       break
+	//     }
+	//     token = page.NextPageToken
+	//   }
+	//
+	UnitsPage(ctx context.Context, pageToken string) (UnitPage, error)
+
+	// Machines fetches the machines registered with the fleet cluster, sorted
+	// by ID.
+	Machines(ctx context.Context) ([]Machine, error)
 }
 
 // NewFleet creates a new Fleet that is configured with the given settings.
@@ -132,6 +289,19 @@ type Fleet interface {
 func NewFleet(config Config) (Fleet, error) {
 	var trans http.RoundTripper
 
+	if config.Proxy != nil {
+		if config.SSHTunnel != nil && config.SSHTunnel.IsActive() {
+			return nil, maskAnyf(invalidConfigError, "proxy cannot be used together with a tunnel")
+		}
+		if config.Endpoint.Scheme != "http" && config.Endpoint.Scheme != "https" {
+			return nil, maskAnyf(invalidConfigError, "proxy cannot be used with scheme %q", config.Endpoint.Scheme)
+		}
+	}
+
+	if config.MaxIdleConnsPerHost < 0 {
+		return nil, maskAnyf(invalidConfigError, "max idle connections per host must not be negative")
+	}
+
 	// If a tunnel is provided we need to overwrite the http.Transport.Dial function
 	// to use the tunnel
 	if config.SSHTunnel != nil && config.SSHTunnel.IsActive() {
@@ -153,20 +323,42 @@ func NewFleet(config Config) (Fleet, error) {
 			config.Endpoint.Scheme = "http"
 			config.Endpoint.Host = "domain-sock"
 
-			trans = &http.Transport{
-				Dial: func(s, t string) (net.Conn, error) {
-					// http.Client does not natively support dialing a unix domain socket,
-					// so the dial function must be overridden.
-					return net.Dial("unix", sockPath)
-				},
+			newTransport := newHTTPTransport(config)
+			newTransport.Dial = func(s, t string) (net.Conn, error) {
+				// http.Client does not natively support dialing a unix domain socket,
+				// so the dial function must be overridden.
+				return net.Dial("unix", sockPath)
 			}
+			trans = newTransport
 		case "http", "https":
-			trans = http.DefaultTransport
+			newTransport := newHTTPTransport(config)
+			newTransport.Proxy = http.ProxyFromEnvironment
+			if config.Proxy != nil {
+				newTransport.Proxy = http.ProxyURL(config.Proxy)
+			}
+			newTransport.TLSClientConfig = config.TLSConfig
+			trans = newTransport
 		default:
 			return nil, maskAnyf(invalidEndpointError, "invalid scheme %q", config.Endpoint.Scheme)
 		}
 	}
 
+	if config.RateLimit > 0 {
+		trans = rateLimitedTransport{
+			Transport: trans,
+			Limiter:   newRateLimiter(config.RateLimit, config.RateBurst),
+		}
+	}
+
+	if config.RequestID == "" {
+		config.RequestID = NewRequestID()
+	}
+	trans = headerTransport{
+		Transport: trans,
+		UserAgent: config.UserAgent,
+		RequestID: config.RequestID,
+	}
+
 	config.Client.Transport = trans
 	client, err := client.NewHTTPClient(config.Client, config.Endpoint)
 	if err != nil {
@@ -175,12 +367,27 @@ func NewFleet(config Config) (Fleet, error) {
 
 	newFleet := fleet{
 		Config: config,
-		Client: client,
+		Client: hookedAPI{API: client, Hooks: config.Hooks},
 	}
 
 	return newFleet, nil
 }
 
+// newHTTPTransport creates the transport calls of the fleet API are sent
+// through, using the connection settings of the given configuration. Timeouts
+// are the ones of http.DefaultTransport.
+func newHTTPTransport(config Config) *http.Transport {
+	return &http.Transport{
+		Dial: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).Dial,
+		TLSHandshakeTimeout: 10 * time.Second,
+		DisableCompression:  !config.Compression,
+		MaxIdleConnsPerHost: config.MaxIdleConnsPerHost,
+	}
+}
+
 type fleet struct {
 	Config Config
 	Client client.API
@@ -211,7 +418,7 @@ func (f fleet) Submit(ctx context.Context, name, content string) error {
 func (f fleet) Start(ctx context.Context, name string) error {
 	f.Config.Logger.Debug(ctx, "fleet: starting unit '%v'", name)
 
-	err := f.Client.SetUnitTargetState(name, unitStateLaunched)
+	err := f.setUnitTargetState(ctx, name, unitStateLaunched)
 	if err != nil {
 		return maskAny(err)
 	}
@@ -222,7 +429,7 @@ func (f fleet) Start(ctx context.Context, name string) error {
 func (f fleet) Stop(ctx context.Context, name string) error {
 	f.Config.Logger.Debug(ctx, "fleet: stopping unit '%v'", name)
 
-	err := f.Client.SetUnitTargetState(name, unitStateLoaded)
+	err := f.setUnitTargetState(ctx, name, unitStateLoaded)
 	if err != nil {
 		return maskAny(err)
 	}
@@ -230,6 +437,35 @@ func (f fleet) Stop(ctx context.Context, name string) error {
 	return nil
 }
 
+// setUnitTargetState sets the target state of the given unit. In case
+// VerifyTargetState is configured, the unit is fetched again afterwards to
+// make sure fleet did not silently ignore the transition.
+func (f fleet) setUnitTargetState(ctx context.Context, name, target string) error {
+	err := f.Client.SetUnitTargetState(name, target)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	if !f.Config.VerifyTargetState {
+		return nil
+	}
+
+	f.Config.Logger.Debug(ctx, "fleet: verifying target state '%v' of unit '%v'", target, name)
+	unit, err := f.Client.Unit(name)
+	if err != nil {
+		return maskAny(err)
+	}
+	if unit == nil {
+		// The fleet client returns nil in case the unit cannot be found.
+		return maskAnyf(unitNotFoundError, "%s", name)
+	}
+	if unit.DesiredState != target {
+		return maskAnyf(targetStateNotSetError, "unit '%s' has target state '%s' instead of '%s'", name, unit.DesiredState, target)
+	}
+
+	return nil
+}
+
 func (f fleet) Destroy(ctx context.Context, name string) error {
 	f.Config.Logger.Debug(ctx, "fleet: destroying unit '%v'", name)
 
@@ -259,6 +495,33 @@ func (f fleet) GetStatus(ctx context.Context, name string) (UnitStatus, error) {
 	return unitStatus[0], nil
 }
 
+func (f fleet) GetContent(ctx context.Context, name string) (string, error) {
+	f.Config.Logger.Debug(ctx, "fleet: getting content of unit '%v'", name)
+
+	unit, err := f.Client.Unit(name)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	if unit == nil {
+		// The fleet client returns nil in case the unit cannot be found.
+		return "", maskAnyf(unitNotFoundError, "%s", name)
+	}
+
+	return schema.MapSchemaUnitOptionsToUnitFile(unit.Options).String(), nil
+}
+
+func (f fleet) Exists(ctx context.Context, name string) (bool, error) {
+	f.Config.Logger.Debug(ctx, "fleet: checking existence of unit '%v'", name)
+
+	unit, err := f.Client.Unit(name)
+	if err != nil {
+		return false, maskAny(err)
+	}
+
+	// The fleet client returns nil in case the unit cannot be found.
+	return unit != nil, nil
+}
+
 // GetStatusWithMatcher returns a []UnitStatus, with an element for
 // each unit where the given matcher returns true.
 func (f fleet) GetStatusWithMatcher(matcher func(s string) bool) ([]UnitStatus, error) {
@@ -306,6 +569,29 @@ func (f fleet) GetStatusWithMatcher(matcher func(s string) bool) ([]UnitStatus,
 	return ourStatusList, nil
 }
 
+func (f fleet) Machines(ctx context.Context) ([]Machine, error) {
+	f.Config.Logger.Debug(ctx, "fleet: fetching machines")
+
+	machineStates, err := f.Client.Machines()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	var machines []Machine
+	for _, ms := range machineStates {
+		machines = append(machines, Machine{ID: ms.ID, IP: net.ParseIP(ms.PublicIP)})
+	}
+	sort.Sort(machinesByID(machines))
+
+	return machines, nil
+}
+
+type machinesByID []Machine
+
+func (m machinesByID) Len() int           { return len(m) }
+func (m machinesByID) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m machinesByID) Less(i, j int) bool { return m[i].ID < m[j].ID }
+
 func ipFromUnitState(unitState *schema.UnitState, machineStates []machine.MachineState) (net.IP, error) {
 	for _, ms := range machineStates {
 		if unitState.MachineID == ms.ID {
@@ -319,6 +605,13 @@ func ipFromUnitState(unitState *schema.UnitState, machineStates []machine.Machin
 func mapFleetStateToUnitStatusList(foundFleetUnits []*schema.Unit, foundFleetUnitStates []*schema.UnitState, machines []machine.MachineState) ([]UnitStatus, error) {
 	ourStatusList := []UnitStatus{}
 
+	// Registries may contain thousands of units, so the states are looked up
+	// by unit name instead of scanning all of them for each unit.
+	statesByName := map[string][]*schema.UnitState{}
+	for _, ffus := range foundFleetUnitStates {
+		statesByName[ffus.Name] = append(statesByName[ffus.Name], ffus)
+	}
+
 	for _, ffu := range foundFleetUnits {
 		ID, err := common.SliceID(ffu.Name)
 		if err != nil {
@@ -339,21 +632,23 @@ func mapFleetStateToUnitStatusList(foundFleetUnits []*schema.Unit, foundFleetUni
 			ourUnitStatus.Current = ourUnitStatus.Desired
 		}
 
-		for _, ffus := range foundFleetUnitStates {
-			if ffu.Name != ffus.Name {
-				continue
-			}
-
+		for _, ffus := range statesByName[ffu.Name] {
+			// Fleet keeps reporting the state of units scheduled on machines that
+			// left the cluster until they are rescheduled. We report those machines
+			// as vanished instead of failing.
 			IP, err := ipFromUnitState(ffus, machines)
-			if err != nil {
+			vanished := IsIPNotFound(err)
+			if err != nil && !vanished {
 				return []UnitStatus{}, maskAny(err)
 			}
 			ourMachineStatus := MachineStatus{
 				ID:            ffus.MachineID,
 				IP:            IP,
+				SystemdLoad:   ffus.SystemdLoadState,
 				SystemdActive: ffus.SystemdActiveState,
 				SystemdSub:    ffus.SystemdSubState,
 				UnitHash:      ffus.Hash,
+				Vanished:      vanished,
 			}
 			ourUnitStatus.Machine = append(ourUnitStatus.Machine, ourMachineStatus)
 		}
@@ -135,6 +135,97 @@ func TestFleetStop_Success(t *testing.T) {
 	mock.AssertExpectations(t)
 }
 
+func TestFleetStart_VerifyTargetState(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []struct {
+		Unit     *schema.Unit
+		ErrCheck func(error) bool
+	}{
+		{
+			Unit:     &schema.Unit{Name: "unit.service", DesiredState: unitStateLaunched},
+			ErrCheck: nil,
+		},
+		{
+			Unit:     &schema.Unit{Name: "unit.service", DesiredState: unitStateLoaded},
+			ErrCheck: IsTargetStateNotSet,
+		},
+		{
+			Unit:     nil,
+			ErrCheck: IsUnitNotFound,
+		},
+	}
+
+	for i, testCase := range testCases {
+		mock, fleet := givenMockedFleet()
+		fleet.Config.VerifyTargetState = true
+		mock.On("SetUnitTargetState", "unit.service", unitStateLaunched).Once().Return(nil)
+		mock.On("Unit", "unit.service").Once().Return(testCase.Unit, nil)
+
+		err := fleet.Start(context.Background(), "unit.service")
+		if testCase.ErrCheck == nil {
+			Expect(err).To(Not(HaveOccurred()), "test case %d", i)
+		} else {
+			Expect(testCase.ErrCheck(err)).To(BeTrue(), "test case %d", i)
+		}
+		mock.AssertExpectations(t)
+	}
+}
+
+func TestFleetGetContent(t *testing.T) {
+	RegisterTestingT(t)
+
+	mock, fleet := givenMockedFleet()
+	mock.On("Unit", "unit.service").Once().Return(&schema.Unit{
+		Name: "unit.service",
+		Options: []*schema.UnitOption{
+			{Section: "Service", Name: "ExecStart", Value: "/bin/true"},
+		},
+	}, nil)
+	mock.On("Unit", "missing.service").Once().Return((*schema.Unit)(nil), nil)
+
+	content, err := fleet.GetContent(context.Background(), "unit.service")
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(content).To(Equal("[Service]\nExecStart=/bin/true\n"))
+
+	_, err = fleet.GetContent(context.Background(), "missing.service")
+	Expect(IsUnitNotFound(err)).To(BeTrue())
+	mock.AssertExpectations(t)
+}
+
+func TestFleetExists(t *testing.T) {
+	RegisterTestingT(t)
+
+	mock, fleet := givenMockedFleet()
+	mock.On("Unit", "unit.service").Once().Return(&schema.Unit{Name: "unit.service"}, nil)
+	mock.On("Unit", "missing.service").Once().Return((*schema.Unit)(nil), nil)
+
+	ok, err := fleet.Exists(context.Background(), "unit.service")
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(ok).To(BeTrue())
+
+	ok, err = fleet.Exists(context.Background(), "missing.service")
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(ok).To(BeFalse())
+	mock.AssertExpectations(t)
+}
+
+func TestFleetMachines(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, fleet := givenMockedFleetWithMachines([]machine.MachineState{
+		{ID: "machine-2", PublicIP: "10.0.0.102"},
+		{ID: "machine-1", PublicIP: "10.0.0.101"},
+	})
+
+	machines, err := fleet.Machines(context.Background())
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(machines).To(HaveLen(2))
+	Expect(machines[0].ID).To(Equal("machine-1"))
+	Expect(machines[0].IP.String()).To(Equal("10.0.0.101"))
+	Expect(machines[1].ID).To(Equal("machine-2"))
+}
+
 func TestFleetDestroy_Success(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -195,6 +286,15 @@ func TestFleetGetStatusWithMatcher__Success(t *testing.T) {
 	}))
 }
 
+func Test_Fleet_MachineStatus_LoadError(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(MachineStatus{}.LoadError()).To(BeEmpty())
+	Expect(MachineStatus{SystemdLoad: "loaded"}.LoadError()).To(BeEmpty())
+	Expect(MachineStatus{SystemdLoad: "bad-setting"}.LoadError()).To(Equal("bad-setting: unit file contains an invalid setting"))
+	Expect(MachineStatus{SystemdLoad: "unknown-state"}.LoadError()).To(Equal("unknown-state"))
+}
+
 func Test_Fleet_mapFleetStateToUnitStatusList(t *testing.T) {
 	testCases := []struct {
 		Error                error
@@ -225,12 +325,14 @@ func Test_Fleet_mapFleetStateToUnitStatusList(t *testing.T) {
 				{
 					MachineID:          "machine-ID-1",
 					Name:               "name-1",
+					SystemdLoadState:   "loaded",
 					SystemdActiveState: "systemd-active-state-1",
 					Hash:               "1234",
 				},
 				{
 					MachineID:          "machine-ID-2",
 					Name:               "name-2",
+					SystemdLoadState:   "not-found",
 					SystemdActiveState: "systemd-active-state-2",
 					Hash:               "7890",
 				},
@@ -253,6 +355,7 @@ func Test_Fleet_mapFleetStateToUnitStatusList(t *testing.T) {
 						{
 							ID:            "machine-ID-1",
 							IP:            net.ParseIP("10.0.0.1"),
+							SystemdLoad:   "loaded",
 							SystemdActive: "systemd-active-state-1",
 							UnitHash:      "1234",
 						},
@@ -266,6 +369,7 @@ func Test_Fleet_mapFleetStateToUnitStatusList(t *testing.T) {
 						{
 							ID:            "machine-ID-2",
 							IP:            net.ParseIP("10.0.0.2"),
+							SystemdLoad:   "not-found",
 							SystemdActive: "systemd-active-state-2",
 							UnitHash:      "7890",
 						},
@@ -327,6 +431,50 @@ func Test_Fleet_mapFleetStateToUnitStatusList(t *testing.T) {
 				},
 			},
 		},
+		// This test checks that units scheduled on machines that left the cluster
+		// are reported as vanished.
+		{
+			Error: nil,
+			FoundFleetUnits: []*schema.Unit{
+				{
+					CurrentState: "launched",
+					DesiredState: "launched",
+					MachineID:    "machine-ID-gone",
+					Name:         "name-1",
+				},
+			},
+			FoundFleetUnitStates: []*schema.UnitState{
+				{
+					MachineID:          "machine-ID-gone",
+					Name:               "name-1",
+					SystemdActiveState: "active",
+					SystemdSubState:    "running",
+					Hash:               "1234",
+				},
+			},
+			FleetMachines: []machine.MachineState{
+				{
+					ID:       "machine-ID-1",
+					PublicIP: "10.0.0.1",
+				},
+			},
+			UnitStatusList: []UnitStatus{
+				{
+					Current: "launched",
+					Desired: "launched",
+					Machine: []MachineStatus{
+						{
+							ID:            "machine-ID-gone",
+							SystemdActive: "active",
+							SystemdSub:    "running",
+							UnitHash:      "1234",
+							Vanished:      true,
+						},
+					},
+					Name: "name-1",
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
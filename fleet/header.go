@@ -0,0 +1,79 @@
+package fleet
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	// ContextRequestID is the key for the request ID stored in the
+	// context.Context of an operation. Loggers print it along with each
+	// message, so that messages can be correlated with the calls of the fleet
+	// API sent using the same ID. See WithRequestID.
+	ContextRequestID = "request-id"
+
+	// RequestIDHeader is the HTTP header each call of the fleet API carries
+	// the request ID of the client in. See Config.RequestID.
+	RequestIDHeader = "X-Request-ID"
+
+	// DefaultUserAgent is the User-Agent header fleet API calls are sent
+	// with, unless configured otherwise. See Config.UserAgent.
+	DefaultUserAgent = "inago"
+)
+
+// NewRequestID returns a new random request ID, e.g. "5f0c2e7a9b3d4c18".
+func NewRequestID() string {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(raw)
+}
+
+// WithRequestID returns a copy of ctx carrying the given request ID.
+//
+//   requestID := fleet.NewRequestID()
+//   newFleetConfig.RequestID = requestID
+//   ctx = fleet.WithRequestID(ctx, requestID)
+//
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, ContextRequestID, requestID)
+}
+
+// RequestID returns the request ID of the given context, or an empty string
+// in case there is none.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(ContextRequestID).(string)
+	return requestID
+}
+
+// headerTransport sets the User-Agent and request ID headers of all requests
+// sent using the wrapped transport.
+type headerTransport struct {
+	Transport http.RoundTripper
+	UserAgent string
+	RequestID string
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Round trippers must not modify the given request. So we send a copy.
+	r := new(http.Request)
+	*r = *req
+	r.Header = http.Header{}
+	for key, values := range req.Header {
+		r.Header[key] = append([]string{}, values...)
+	}
+
+	if t.UserAgent != "" {
+		r.Header.Set("User-Agent", t.UserAgent)
+	}
+	if t.RequestID != "" {
+		r.Header.Set(RequestIDHeader, t.RequestID)
+	}
+
+	return t.Transport.RoundTrip(r)
+}
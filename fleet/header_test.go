@@ -0,0 +1,77 @@
+package fleet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestFleet_Headers(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	URL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultConfig()
+	config.Endpoint = *URL
+	config.UserAgent = "inagoctl/1.2.3"
+	config.RequestID = "5f0c2e7a9b3d4c18"
+	newFleet, err := NewFleet(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	newFleet.Exists(ctx, "foo.service")
+	newFleet.Exists(ctx, "bar.service")
+
+	if len(headers) != 2 {
+		t.Fatal("expected", 2, "got", len(headers))
+	}
+	for _, header := range headers {
+		if header.Get("User-Agent") != "inagoctl/1.2.3" {
+			t.Fatal("expected", "inagoctl/1.2.3", "got", header.Get("User-Agent"))
+		}
+		if header.Get(RequestIDHeader) != "5f0c2e7a9b3d4c18" {
+			t.Fatal("expected", "5f0c2e7a9b3d4c18", "got", header.Get(RequestIDHeader))
+		}
+	}
+
+	// Without request ID, one is generated for the client.
+	headers = nil
+	config.RequestID = ""
+	newFleet, err = NewFleet(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newFleet.Exists(ctx, "foo.service")
+	if len(headers) != 1 || len(headers[0].Get(RequestIDHeader)) != 16 {
+		t.Fatal("expected generated request ID, got", headers)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	ctx := context.Background()
+	if RequestID(ctx) != "" {
+		t.Fatal("expected", "", "got", RequestID(ctx))
+	}
+
+	ctx = WithRequestID(ctx, "5f0c2e7a9b3d4c18")
+	if RequestID(ctx) != "5f0c2e7a9b3d4c18" {
+		t.Fatal("expected", "5f0c2e7a9b3d4c18", "got", RequestID(ctx))
+	}
+
+	if NewRequestID() == NewRequestID() {
+		t.Fatal("expected request IDs to differ")
+	}
+}
@@ -0,0 +1,130 @@
+package fleet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coreos/fleet/schema"
+	"github.com/coreos/fleet/unit"
+	"golang.org/x/net/context"
+)
+
+// HealthCheck is the result of a single check run by CheckHealth.
+type HealthCheck struct {
+	// Check describes what has been checked, e.g. "fleet API answers".
+	Check string
+
+	// Detail describes what has been found, e.g. "3 machines".
+	Detail string
+
+	// Latency is the time the check took.
+	Latency time.Duration
+
+	// Err is the reason the check failed. It is nil in case the check passed.
+	Err error
+}
+
+// CheckHealth checks whether the cluster behind the given fleet is able to
+// run units. Checks are run until the first one fails, since later checks
+// depend on earlier ones.
+//
+//   - whether the fleet API answers using a supported version
+//   - whether machines are registered
+//   - whether fleet can read its unit registry, which is stored in etcd
+//   - whether units are scheduled, which is done by the leading fleet engine
+//
+// fleet exposes neither the health of etcd nor the leadership of its engines.
+// So the last two checks tell them by their effects. Note that units are
+// unscheduled for a short time after being submitted.
+func CheckHealth(ctx context.Context, f Fleet) []HealthCheck {
+	var checks []HealthCheck
+	check := func(name string, fn func() (string, error)) bool {
+		start := time.Now()
+		detail, err := fn()
+		checks = append(checks, HealthCheck{
+			Check:   name,
+			Detail:  detail,
+			Latency: time.Since(start),
+			Err:     err,
+		})
+		return err == nil
+	}
+
+	ok := check("fleet API answers", func() (string, error) {
+		version, err := f.APIVersion(ctx)
+		if err != nil {
+			return "", maskAny(err)
+		}
+		if !IsSupportedAPIVersion(version) {
+			return version, maskAnyf(invalidAPIResponseError, "unsupported API version '%s'", version)
+		}
+		return version, nil
+	})
+	if !ok {
+		return checks
+	}
+
+	ok = check("machines registered", func() (string, error) {
+		machines, err := f.Machines(ctx)
+		if err != nil {
+			return "", maskAny(err)
+		}
+		detail := fmt.Sprintf("%d machines", len(machines))
+		if len(machines) == 0 {
+			return detail, maskAnyf(invalidAPIResponseError, "no machines registered")
+		}
+		return detail, nil
+	})
+	if !ok {
+		return checks
+	}
+
+	var units []Unit
+	ok = check("unit registry readable", func() (string, error) {
+		var token string
+		for {
+			page, err := f.UnitsPage(ctx, token)
+			if err != nil {
+				return "", maskAny(err)
+			}
+			units = append(units, page.Units...)
+			if page.NextPageToken == "" {
+				break
+			}
+			token = page.NextPageToken
+		}
+		return fmt.Sprintf("%d units", len(units)), nil
+	})
+	if !ok {
+		return checks
+	}
+
+	check("units scheduled", func() (string, error) {
+		unscheduled := unscheduledUnits(units)
+		if len(unscheduled) > 0 {
+			return fmt.Sprintf("%d unscheduled", len(unscheduled)), maskAnyf(unitsNotScheduledError, "%s", strings.Join(unscheduled, ", "))
+		}
+		return "", nil
+	})
+
+	return checks
+}
+
+// unscheduledUnits returns the names of the given units that are supposed to
+// be scheduled, but are not scheduled on any machine. Global units are never
+// scheduled on a single machine, so they are ignored.
+func unscheduledUnits(units []Unit) []string {
+	var unscheduled []string
+	for _, u := range units {
+		if u.Desired == unitStateInactive || u.MachineID != "" {
+			continue
+		}
+		if uf, err := unit.NewUnitFile(u.Content); err == nil && isFleetGlobalUnit(schema.MapUnitFileToSchemaUnitOptions(uf)) {
+			continue
+		}
+		unscheduled = append(unscheduled, u.Name)
+	}
+
+	return unscheduled
+}
@@ -0,0 +1,67 @@
+package fleet
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// TestCheckHealth tests that the health checks pass on a simulated cluster
+// once all units are scheduled.
+func TestCheckHealth(t *testing.T) {
+	now := time.Unix(0, 0)
+	simulator, err := NewSimulator(testSimulatorConfig(&now))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	ctx := context.Background()
+
+	if err := simulator.Submit(ctx, "foo@1.service", "[Unit]\nDescription=foo\n"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if err := simulator.Submit(ctx, "bar.service", "[Unit]\nDescription=bar\n\n[X-Fleet]\nGlobal=true\n"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// Units are not scheduled right after being submitted. Global units are
+	// never scheduled on a single machine.
+	checks := CheckHealth(ctx, simulator)
+	if len(checks) != 4 {
+		t.Fatal("expected", 4, "got", len(checks))
+	}
+	for _, check := range checks[:3] {
+		if check.Err != nil {
+			t.Fatal("expected", nil, "got", check.Err)
+		}
+	}
+	if checks[1].Detail != "3 machines" {
+		t.Fatal("expected", "3 machines", "got", checks[1].Detail)
+	}
+	if !IsUnitsNotScheduled(checks[3].Err) || checks[3].Detail != "1 unscheduled" {
+		t.Fatal("expected", "units not scheduled error", "got", checks[3])
+	}
+
+	now = now.Add(15 * time.Second)
+	checks = CheckHealth(ctx, simulator)
+	for _, check := range checks {
+		if check.Err != nil {
+			t.Fatal("expected", nil, "got", check.Err)
+		}
+	}
+}
+
+// TestCheckHealth_NoMachines tests that the checks stop at the first one
+// failing.
+func TestCheckHealth_NoMachines(t *testing.T) {
+	checks := CheckHealth(context.Background(), NewDummyFleet(DefaultDummyConfig()))
+	if len(checks) != 2 {
+		t.Fatal("expected", 2, "got", len(checks))
+	}
+	if checks[0].Err != nil || checks[0].Detail != "v1" {
+		t.Fatal("expected", "fleet API answering v1", "got", checks[0])
+	}
+	if !IsInvalidAPIResponse(checks[1].Err) {
+		t.Fatal("expected", "invalid API response error", "got", checks[1].Err)
+	}
+}
@@ -0,0 +1,113 @@
+package fleet
+
+import (
+	"time"
+
+	"github.com/coreos/fleet/client"
+	"github.com/coreos/fleet/machine"
+	"github.com/coreos/fleet/schema"
+)
+
+// Hooks are called around each call of the fleet API, so that consumers can
+// wire their own metrics or tracing systems. Calls are named like the
+// methods of fleet's API client, e.g. "CreateUnit" or "UnitStates". Calls
+// listing units page by page are named "UnitsPage", and calls fetching the
+// discovery document "Discovery". Hooks are called synchronously and should
+// return quickly. Both hooks are optional.
+//
+//   newConfig := fleet.DefaultConfig()
+//   newConfig.Hooks.After = func(name string, d time.Duration, err error) {
+//     apiLatency.WithLabelValues(name).Observe(d.Seconds())
+//   }
+//
+type Hooks struct {
+	// Before is called with the name of the call before it is sent.
+	Before func(name string)
+
+	// After is called with the name of the call once it finished, along with
+	// the time it took and the error it returned, which is nil on success.
+	After func(name string, duration time.Duration, err error)
+}
+
+// call executes the given call of the fleet API, calling the hooks around it.
+// HTTP errors the API answers with are classified, see classifyAPIError.
+func (h Hooks) call(name string, f func() error) error {
+	if h.Before != nil {
+		h.Before(name)
+	}
+	start := time.Now()
+	err := classifyAPIError(f())
+	if h.After != nil {
+		h.After(name, time.Since(start), err)
+	}
+
+	return err
+}
+
+// hookedAPI is a fleet API client calling the given hooks around each call.
+type hookedAPI struct {
+	API   client.API
+	Hooks Hooks
+}
+
+func (a hookedAPI) Machines() ([]machine.MachineState, error) {
+	var machines []machine.MachineState
+	err := a.Hooks.call("Machines", func() error {
+		var err error
+		machines, err = a.API.Machines()
+		return err
+	})
+
+	return machines, err
+}
+
+func (a hookedAPI) Unit(name string) (*schema.Unit, error) {
+	var u *schema.Unit
+	err := a.Hooks.call("Unit", func() error {
+		var err error
+		u, err = a.API.Unit(name)
+		return err
+	})
+
+	return u, err
+}
+
+func (a hookedAPI) Units() ([]*schema.Unit, error) {
+	var units []*schema.Unit
+	err := a.Hooks.call("Units", func() error {
+		var err error
+		units, err = a.API.Units()
+		return err
+	})
+
+	return units, err
+}
+
+func (a hookedAPI) UnitStates() ([]*schema.UnitState, error) {
+	var unitStates []*schema.UnitState
+	err := a.Hooks.call("UnitStates", func() error {
+		var err error
+		unitStates, err = a.API.UnitStates()
+		return err
+	})
+
+	return unitStates, err
+}
+
+func (a hookedAPI) SetUnitTargetState(name, target string) error {
+	return a.Hooks.call("SetUnitTargetState", func() error {
+		return a.API.SetUnitTargetState(name, target)
+	})
+}
+
+func (a hookedAPI) CreateUnit(u *schema.Unit) error {
+	return a.Hooks.call("CreateUnit", func() error {
+		return a.API.CreateUnit(u)
+	})
+}
+
+func (a hookedAPI) DestroyUnit(name string) error {
+	return a.Hooks.call("DestroyUnit", func() error {
+		return a.API.DestroyUnit(name)
+	})
+}
@@ -0,0 +1,97 @@
+package fleet
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+// hookCall records a single call of Hooks.After.
+type hookCall struct {
+	Name string
+	Err  error
+}
+
+// givenRecordingHooks returns hooks recording the names of all calls passed
+// to Before, and all calls passed to After.
+func givenRecordingHooks() (Hooks, *[]string, *[]hookCall) {
+	var before []string
+	var after []hookCall
+
+	hooks := Hooks{
+		Before: func(name string) {
+			before = append(before, name)
+		},
+		After: func(name string, duration time.Duration, err error) {
+			after = append(after, hookCall{Name: name, Err: err})
+		},
+	}
+
+	return hooks, &before, &after
+}
+
+func TestHooks_API(t *testing.T) {
+	RegisterTestingT(t)
+
+	hooks, before, after := givenRecordingHooks()
+	mock, fleet := givenMockedFleet()
+	fleet.Client = hookedAPI{API: mock, Hooks: hooks}
+
+	failure := errors.New("test error")
+	mock.On("SetUnitTargetState", "unit.service", unitStateLaunched).Once().Return(nil)
+	mock.On("SetUnitTargetState", "unit.service", unitStateLoaded).Once().Return(failure)
+
+	err := fleet.Start(context.Background(), "unit.service")
+	Expect(err).To(Not(HaveOccurred()))
+	err = fleet.Stop(context.Background(), "unit.service")
+	Expect(err).To(HaveOccurred())
+
+	Expect(*before).To(Equal([]string{"SetUnitTargetState", "SetUnitTargetState"}))
+	Expect(*after).To(Equal([]hookCall{
+		{Name: "SetUnitTargetState", Err: nil},
+		{Name: "SetUnitTargetState", Err: failure},
+	}))
+	mock.AssertExpectations(t)
+}
+
+func TestHooks_HTTP(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"units": []}`))
+	}))
+	defer server.Close()
+	URL, err := url.Parse(server.URL)
+	Expect(err).To(Not(HaveOccurred()))
+
+	hooks, before, after := givenRecordingHooks()
+	newConfig := DefaultConfig()
+	newConfig.Endpoint = *URL
+	newConfig.Hooks = hooks
+	newFleet, err := NewFleet(newConfig)
+	Expect(err).To(Not(HaveOccurred()))
+
+	_, err = newFleet.UnitsPage(context.Background(), "")
+	Expect(err).To(Not(HaveOccurred()))
+
+	Expect(*before).To(Equal([]string{"UnitsPage"}))
+	Expect(*after).To(Equal([]hookCall{{Name: "UnitsPage", Err: nil}}))
+}
+
+func TestHooks_None(t *testing.T) {
+	RegisterTestingT(t)
+
+	mock, fleet := givenMockedFleet()
+	fleet.Client = hookedAPI{API: mock, Hooks: Hooks{}}
+	mock.On("SetUnitTargetState", "unit.service", unitStateLaunched).Once().Return(nil)
+
+	err := fleet.Start(context.Background(), "unit.service")
+	Expect(err).To(Not(HaveOccurred()))
+	mock.AssertExpectations(t)
+}
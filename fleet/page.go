@@ -0,0 +1,92 @@
+package fleet
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+
+	"github.com/coreos/fleet/schema"
+	"golang.org/x/net/context"
+)
+
+// Unit represents a unit as known to fleet's unit registry.
+type Unit struct {
+	// Name represents the unit file name.
+	Name string
+
+	// Content is the unit file content as submitted to fleet.
+	Content string
+
+	// Current represents the current status within the fleet cluster.
+	Current string
+
+	// Desired represents the desired status within the fleet cluster.
+	Desired string
+
+	// MachineID is the ID of the machine the unit is scheduled on. It is empty
+	// for global units and units not scheduled yet.
+	MachineID string
+}
+
+// UnitPage is a single page of the units known to fleet. See Fleet.UnitsPage.
+type UnitPage struct {
+	// Units are the units of the page.
+	Units []Unit
+
+	// NextPageToken is the token to fetch the next page with. It is empty in
+	// case this is the last page.
+	NextPageToken string
+}
+
+func (f fleet) UnitsPage(ctx context.Context, pageToken string) (UnitPage, error) {
+	f.Config.Logger.Debug(ctx, "fleet: fetching units page '%s'", pageToken)
+
+	URL := f.Config.Endpoint
+	URL.Path = path.Join(URL.Path, "fleet", "v1", "units")
+	if pageToken != "" {
+		query := URL.Query()
+		query.Set("nextPageToken", pageToken)
+		URL.RawQuery = query.Encode()
+	}
+
+	var resp *http.Response
+	err := f.Config.Hooks.call("UnitsPage", func() error {
+		var err error
+		resp, err = f.Config.Client.Get(URL.String())
+		return err
+	})
+	if err != nil {
+		return UnitPage{}, maskAny(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest && pageToken != "" {
+		return UnitPage{}, maskAnyf(invalidPageTokenError, "%s", pageToken)
+	}
+	if resp.StatusCode == http.StatusConflict || resp.StatusCode >= http.StatusInternalServerError {
+		return UnitPage{}, maskAnyf(statusCodeError(resp.StatusCode), "status code %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return UnitPage{}, maskAnyf(invalidAPIResponseError, "unexpected status code %d", resp.StatusCode)
+	}
+
+	var page schema.UnitPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return UnitPage{}, maskAnyf(invalidAPIResponseError, "%s", err.Error())
+	}
+
+	unitPage := UnitPage{
+		NextPageToken: page.NextPageToken,
+	}
+	for _, u := range page.Units {
+		unitPage.Units = append(unitPage.Units, Unit{
+			Name:      u.Name,
+			Content:   schema.MapSchemaUnitOptionsToUnitFile(u.Options).String(),
+			Current:   u.CurrentState,
+			Desired:   u.DesiredState,
+			MachineID: u.MachineID,
+		})
+	}
+
+	return unitPage, nil
+}
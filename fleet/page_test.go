@@ -0,0 +1,67 @@
+package fleet
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+func TestFleetUnitsPage_Success(t *testing.T) {
+	RegisterTestingT(t)
+
+	var requestedTokens []string
+	newFleet, server := givenFleetWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		Expect(r.URL.Path).To(Equal("/fleet/v1/units"))
+		token := r.URL.Query().Get("nextPageToken")
+		requestedTokens = append(requestedTokens, token)
+
+		switch token {
+		case "":
+			w.Write([]byte(`{"units": [{"name": "foo@1.service", "currentState": "launched", "desiredState": "launched", "machineID": "abc", "options": [{"section": "Service", "name": "ExecStart", "value": "/bin/foo"}]}], "nextPageToken": "EwBMLg=="}`))
+		case "EwBMLg==":
+			w.Write([]byte(`{"units": [{"name": "foo@2.service", "currentState": "loaded", "desiredState": "loaded"}]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	defer server.Close()
+
+	first, err := newFleet.UnitsPage(context.Background(), "")
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(first.NextPageToken).To(Equal("EwBMLg=="))
+	Expect(first.Units).To(HaveLen(1))
+	Expect(first.Units[0].Name).To(Equal("foo@1.service"))
+	Expect(first.Units[0].Current).To(Equal("launched"))
+	Expect(first.Units[0].Desired).To(Equal("launched"))
+	Expect(first.Units[0].MachineID).To(Equal("abc"))
+	Expect(first.Units[0].Content).To(ContainSubstring("ExecStart=/bin/foo"))
+
+	second, err := newFleet.UnitsPage(context.Background(), first.NextPageToken)
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(second.NextPageToken).To(BeEmpty())
+	Expect(second.Units).To(HaveLen(1))
+	Expect(second.Units[0].Name).To(Equal("foo@2.service"))
+
+	Expect(requestedTokens).To(Equal([]string{"", "EwBMLg=="}))
+}
+
+func TestFleetUnitsPage_Error(t *testing.T) {
+	RegisterTestingT(t)
+
+	newFleet, server := givenFleetWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("nextPageToken") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`not json`))
+	})
+	defer server.Close()
+
+	_, err := newFleet.UnitsPage(context.Background(), "bogus")
+	Expect(IsInvalidPageToken(err)).To(BeTrue())
+
+	_, err = newFleet.UnitsPage(context.Background(), "")
+	Expect(IsInvalidAPIResponse(err)).To(BeTrue())
+}
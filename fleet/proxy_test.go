@@ -0,0 +1,51 @@
+package fleet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestFleet_Proxy(t *testing.T) {
+	var requested []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Proxies receive the absolute URL of the fleet endpoint.
+		requested = append(requested, r.URL.String())
+		http.NotFound(w, r)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint, err := url.Parse("http://fleet.example.com:49153")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultConfig()
+	config.Endpoint = *endpoint
+	config.Proxy = proxyURL
+	newFleet, err := NewFleet(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newFleet.Exists(context.Background(), "foo.service")
+
+	expected := "http://fleet.example.com:49153/fleet/v1/units/foo.service?alt=json"
+	if len(requested) != 1 || requested[0] != expected {
+		t.Fatal("expected", expected, "got", requested)
+	}
+
+	// Unix sockets cannot be proxied.
+	config = DefaultConfig()
+	config.Proxy = proxyURL
+	_, err = NewFleet(config)
+	if !IsInvalidConfig(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
@@ -0,0 +1,89 @@
+package fleet
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimiter implements a token bucket. Tokens are added at the configured
+// rate up to the configured burst. Each request takes one token. Requests not
+// finding a token wait until it is their turn.
+type rateLimiter struct {
+	Mutex sync.Mutex
+
+	// Rate is the number of tokens added per second.
+	Rate float64
+
+	// Burst is the maximum number of tokens the bucket holds.
+	Burst float64
+
+	// Tokens is the current number of tokens. It is negative in case requests
+	// are waiting.
+	Tokens float64
+
+	// Last is the time tokens were added the last time.
+	Last time.Time
+
+	// Now and Sleep are replaceable for testing.
+	Now   func() time.Time
+	Sleep func(time.Duration)
+}
+
+// newRateLimiter creates a new rateLimiter allowing the given number of
+// requests per second, with bursts of the given size. A burst smaller than 1
+// is treated as 1.
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	newRateLimiter := &rateLimiter{
+		Mutex:  sync.Mutex{},
+		Rate:   rate,
+		Burst:  float64(burst),
+		Tokens: float64(burst),
+		Last:   time.Now(),
+		Now:    time.Now,
+		Sleep:  time.Sleep,
+	}
+
+	return newRateLimiter
+}
+
+// Wait blocks until the caller is allowed to execute a request.
+func (r *rateLimiter) Wait() {
+	r.Mutex.Lock()
+	now := r.Now()
+	r.Tokens += now.Sub(r.Last).Seconds() * r.Rate
+	if r.Tokens > r.Burst {
+		r.Tokens = r.Burst
+	}
+	r.Last = now
+
+	// Take the token even if it is not yet available. This reserves the slot,
+	// so that concurrent callers queue up in order.
+	r.Tokens--
+	var wait time.Duration
+	if r.Tokens < 0 {
+		wait = time.Duration(-r.Tokens / r.Rate * float64(time.Second))
+	}
+	r.Mutex.Unlock()
+
+	if wait > 0 {
+		r.Sleep(wait)
+	}
+}
+
+// rateLimitedTransport is a http.RoundTripper waiting for the given rate
+// limiter before each request.
+type rateLimitedTransport struct {
+	Transport http.RoundTripper
+	Limiter   *rateLimiter
+}
+
+func (t rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.Limiter.Wait()
+
+	return t.Transport.RoundTrip(req)
+}
@@ -0,0 +1,87 @@
+package fleet
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// givenFakeClockRateLimiter returns a rate limiter using a fake clock. All
+// durations the limiter sleeps are recorded and advance the fake clock.
+func givenFakeClockRateLimiter(rate float64, burst int) (*rateLimiter, *[]time.Duration) {
+	now := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	var sleeps []time.Duration
+
+	limiter := newRateLimiter(rate, burst)
+	limiter.Last = now
+	limiter.Now = func() time.Time { return now }
+	limiter.Sleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+		now = now.Add(d)
+	}
+
+	return limiter, &sleeps
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	limiter, sleeps := givenFakeClockRateLimiter(10, 2)
+
+	// The first two requests are covered by the burst. All following requests
+	// are spread according to the rate.
+	for i := 0; i < 4; i++ {
+		limiter.Wait()
+	}
+
+	expected := []time.Duration{100 * time.Millisecond, 100 * time.Millisecond}
+	if !reflect.DeepEqual(*sleeps, expected) {
+		t.Fatal("expected", expected, "got", *sleeps)
+	}
+}
+
+func TestRateLimiter_Wait_Refill(t *testing.T) {
+	limiter, sleeps := givenFakeClockRateLimiter(10, 2)
+
+	limiter.Wait()
+	limiter.Wait()
+	// Waiting for a long time must not refill more tokens than the burst.
+	limiter.Last = limiter.Last.Add(-time.Hour)
+	limiter.Wait()
+	limiter.Wait()
+	limiter.Wait()
+
+	expected := []time.Duration{100 * time.Millisecond}
+	if !reflect.DeepEqual(*sleeps, expected) {
+		t.Fatal("expected", expected, "got", *sleeps)
+	}
+}
+
+type countingTransport struct {
+	Count int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.Count++
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func TestRateLimitedTransport(t *testing.T) {
+	limiter, sleeps := givenFakeClockRateLimiter(1, 1)
+	counting := &countingTransport{}
+	client := &http.Client{
+		Transport: rateLimitedTransport{Transport: counting, Limiter: limiter},
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Get("http://domain-sock/fleet/v1/units"); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	if counting.Count != 3 {
+		t.Fatal("expected", 3, "got", counting.Count)
+	}
+	if len(*sleeps) != 2 {
+		t.Fatal("expected", 2, "got", len(*sleeps))
+	}
+}
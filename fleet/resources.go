@@ -0,0 +1,160 @@
+package fleet
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coreos/fleet/ssh"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/logging"
+)
+
+// cgtopCommand samples the resource usage of all control groups of a machine.
+// The first iteration has no CPU usage yet, so two iterations are needed.
+// Using --raw, memory is reported in bytes.
+const cgtopCommand = "systemd-cgtop --batch --raw --iterations=2 --delay=1"
+
+// ResourceUsage describes the resources a unit uses on a machine.
+type ResourceUsage struct {
+	// CPU is the CPU usage in percent of a single CPU, e.g. 150 for one and a
+	// half CPUs.
+	CPU float64
+
+	// Memory is the memory usage in bytes.
+	Memory uint64
+}
+
+// ResourceSampler samples the resource usage of units on the machines of the
+// cluster.
+type ResourceSampler interface {
+	// SampleResources samples the resource usage of the given units on the
+	// machine having the given IP. Units not running on the machine are
+	// missing from the returned map. In case the machine does not answer with
+	// resource usage that can be interpreted, an error that you can identify
+	// using IsResourceSamplingFailed is returned.
+	SampleResources(ctx context.Context, machineIP string, units []string) (map[string]ResourceUsage, error)
+}
+
+// SSHResourceSamplerConfig holds configuration for the ResourceSampler
+// created by NewSSHResourceSampler.
+type SSHResourceSamplerConfig struct {
+	KnownHostsFile        string
+	Logger                logging.Logger
+	StrictHostKeyChecking bool
+	Timeout               time.Duration
+
+	// Tunnel is the host machines are connected through, in case they cannot
+	// be reached directly. See SSHTunnelConfig.Tunnel.
+	Tunnel string
+
+	Username string
+}
+
+// DefaultSSHResourceSamplerConfig returns a best-effort configuration for
+// the ResourceSampler created by NewSSHResourceSampler.
+func DefaultSSHResourceSamplerConfig() SSHResourceSamplerConfig {
+	return SSHResourceSamplerConfig{
+		KnownHostsFile:        "~/.fleetctl/known_hosts",
+		Logger:                logging.NewLogger(logging.DefaultConfig()),
+		StrictHostKeyChecking: true,
+		Timeout:               10 * time.Second,
+		Tunnel:                "",
+		Username:              "core",
+	}
+}
+
+// NewSSHResourceSampler returns a ResourceSampler connecting to machines
+// using SSH, the same way "fleetctl ssh" does. Resource usage is sampled
+// using systemd-cgtop, which takes about a second. Note that containers
+// started using "docker run" live in control groups of their own, so only
+// the resources used by the docker client are accounted to the unit, unless
+// the container is placed into the unit's control group.
+func NewSSHResourceSampler(config SSHResourceSamplerConfig) ResourceSampler {
+	return &sshResourceSampler{
+		Config: config,
+	}
+}
+
+type sshResourceSampler struct {
+	Config SSHResourceSamplerConfig
+}
+
+func (s *sshResourceSampler) SampleResources(ctx context.Context, machineIP string, units []string) (map[string]ResourceUsage, error) {
+	s.Config.Logger.Debug(ctx, "fleet: sampling resources of %d units on %s", len(units), machineIP)
+
+	checker := newHostKeyChecker(s.Config.StrictHostKeyChecking, s.Config.KnownHostsFile)
+	var client *ssh.SSHForwardingClient
+	var err error
+	if s.Config.Tunnel != "" {
+		client, err = ssh.NewTunnelledSSHClient(s.Config.Username, s.Config.Tunnel, machineIP, checker, false, s.Config.Timeout)
+	} else {
+		client, err = ssh.NewSSHClient(s.Config.Username, machineIP, checker, false, s.Config.Timeout)
+	}
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	defer session.Close()
+	output, err := session.Output(cgtopCommand)
+	if err != nil {
+		return nil, maskAnyf(resourceSamplingFailedError, "%s: %s", machineIP, err.Error())
+	}
+
+	usage, err := parseCgtop(string(output), units)
+	if err != nil {
+		return nil, maskAnyf(resourceSamplingFailedError, "%s: %s", machineIP, err.Error())
+	}
+
+	return usage, nil
+}
+
+// parseCgtop parses the output of cgtopCommand, and returns the resource
+// usage of the given units. Control groups are reported once per iteration,
+// so the last report of a unit wins.
+//
+//   /system.slice/mygroup-app@1.service  4  12.5  52428800  -  -
+//
+func parseCgtop(output string, units []string) (map[string]ResourceUsage, error) {
+	wanted := map[string]struct{}{}
+	for _, name := range units {
+		wanted[name] = struct{}{}
+	}
+
+	usage := map[string]ResourceUsage{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		name := fields[0][strings.LastIndex(fields[0], "/")+1:]
+		if _, ok := wanted[name]; !ok {
+			continue
+		}
+
+		var u ResourceUsage
+		if fields[2] != "-" {
+			cpu, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, maskAnyf(resourceSamplingFailedError, "bad CPU usage '%s' of unit '%s'", fields[2], name)
+			}
+			u.CPU = cpu
+		}
+		if fields[3] != "-" {
+			memory, err := strconv.ParseUint(fields[3], 10, 64)
+			if err != nil {
+				return nil, maskAnyf(resourceSamplingFailedError, "bad memory usage '%s' of unit '%s'", fields[3], name)
+			}
+			u.Memory = memory
+		}
+		usage[name] = u
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,30 @@
+package fleet
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestResources_parseCgtop(t *testing.T) {
+	RegisterTestingT(t)
+
+	output := `/                                           -      -  1048576000        -        -
+/system.slice/mygroup-app@1.service         4      -    52428800        -        -
+/system.slice/mygroup-app@2.service         2      -    10485760        -        -
+/system.slice/other.service                 1      -     1048576        -        -
+/                                           -   12.3  1048576000        -        -
+/system.slice/mygroup-app@1.service         4   10.5    52428800        -        -
+/system.slice/mygroup-app@2.service         2      -    10485760        -        -
+/system.slice/other.service                 1    1.0     1048576        -        -
+`
+	usage, err := parseCgtop(output, []string{"mygroup-app@1.service", "mygroup-app@2.service", "mygroup-app@3.service"})
+	Expect(err).To(BeNil())
+	Expect(usage).To(Equal(map[string]ResourceUsage{
+		"mygroup-app@1.service": {CPU: 10.5, Memory: 52428800},
+		"mygroup-app@2.service": {CPU: 0, Memory: 10485760},
+	}))
+
+	_, err = parseCgtop("/system.slice/a.service 1 high 1024 - -\n", []string{"a.service"})
+	Expect(IsResourceSamplingFailed(err)).To(BeTrue())
+}
@@ -0,0 +1,664 @@
+package fleet
+
+import (
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coreos/fleet/unit"
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/common"
+	"github.com/giantswarm/inago/logging"
+	"github.com/giantswarm/inago/state"
+)
+
+const (
+	// simulatorNamespace is the namespace of the state store the units of the
+	// Simulator are stored in.
+	simulatorNamespace = "simulator"
+
+	// simulatorName is the name the units of the Simulator are stored under.
+	simulatorName = "units"
+
+	// chaosDelayFactor is the factor chaos delays transitions by.
+	chaosDelayFactor = 5
+
+	// chaosDowntimeFactor is the factor of the latency machines dropped by
+	// chaos stay down for.
+	chaosDowntimeFactor = 10
+)
+
+// SimulatorConfig holds configuration for the Simulator struct.
+type SimulatorConfig struct {
+	Logger logging.Logger
+
+	// Latency is the time units take on average to reach the state they have
+	// been asked to reach. The actual time of each transition is randomly taken
+	// from half to one and a half of Latency, so that units do not change their
+	// states all at once.
+	Latency time.Duration
+
+	// FailureRate is the probability of a started unit to fail, from 0 to 1.
+	FailureRate float64
+
+	// Chaos is the probability of chaos striking whenever a unit is submitted,
+	// started or stopped, from 0 to 1. Chaos randomly fails started units,
+	// delays transitions by a multiple of the latency, and drops machines for a
+	// while, so that their units are rescheduled on other machines. The last
+	// machine up is never dropped. This allows to test that operations like
+	// updates cope with flaky clusters.
+	Chaos float64
+
+	// PurgeDelay is the time destroyed units stay known to the registry. Like
+	// fleet, the simulator answers with 409 Conflict when submitting a unit
+	// that is not purged yet. By default units are purged immediately.
+	PurgeDelay time.Duration
+
+	// Machines is the number of machines of the simulated cluster. Units are
+	// scheduled on the machine running the fewest units. Global units are
+	// scheduled on all machines.
+	Machines int
+
+	// Seed initializes the random source deciding about latencies and failures.
+	// Using the same seed, the same operations lead to the same transitions.
+	Seed int64
+
+	// Store optionally persists the simulated cluster, so that it survives the
+	// process, e.g. across multiple invocations of inagoctl.
+	Store state.Store
+
+	// Now returns the current time. It is replaceable for testing.
+	Now func() time.Time
+}
+
+// DefaultSimulatorConfig returns a best-effort configuration for the
+// Simulator struct.
+func DefaultSimulatorConfig() SimulatorConfig {
+	return SimulatorConfig{
+		Logger:      logging.NewLogger(logging.DefaultConfig()),
+		Latency:     2 * time.Second,
+		FailureRate: 0,
+		Chaos:       0,
+		PurgeDelay:  0,
+		Machines:    3,
+		Seed:        time.Now().UnixNano(),
+		Store:       nil,
+		Now:         time.Now,
+	}
+}
+
+// simulatedUnit is a unit of the Simulator.
+type simulatedUnit struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	Hash    string `json:"hash"`
+
+	// Current and Desired are the fleet states of the unit. The unit is in
+	// transition as long as they differ.
+	Current string `json:"current"`
+	Desired string `json:"desired"`
+
+	// Settles is the time the unit reaches its desired state.
+	Settles time.Time `json:"settles"`
+
+	// Fails is true in case the unit fails once it is launched.
+	Fails bool `json:"fails"`
+
+	// MachineIDs are the IDs of the machines the unit is scheduled on.
+	MachineIDs []string `json:"machineIDs"`
+
+	// Global is true in case the unit is scheduled on all machines.
+	Global bool `json:"global,omitempty"`
+
+	SystemdActive string `json:"systemdActive"`
+	SystemdSub    string `json:"systemdSub"`
+}
+
+// Simulator is an implementation of the Fleet interface running in-process.
+// It mimics the state transitions of fleet and systemd, using configurable
+// latencies and failure rates. This allows to rehearse operations against
+// groups locally, and to exercise waiting and rollbacks in tests, without a
+// fleet cluster.
+type Simulator struct {
+	Config SimulatorConfig
+	Mutex  sync.Mutex
+	Rand   *rand.Rand
+	Units  map[string]*simulatedUnit
+
+	// Dropped maps the IDs of the machines dropped by chaos to the time they
+	// rejoin the cluster.
+	Dropped map[string]time.Time
+
+	// Purging maps the names of destroyed units to the time they are purged
+	// from the registry. See SimulatorConfig.PurgeDelay.
+	Purging map[string]time.Time
+}
+
+// NewSimulator returns a Simulator, given a SimulatorConfig. In case
+// SimulatorConfig.Store already holds a simulated cluster, it is restored.
+//
+//	newConfig := fleet.DefaultSimulatorConfig()
+//	newConfig.FailureRate = 0.1
+//	newSimulator, err := fleet.NewSimulator(newConfig)
+func NewSimulator(config SimulatorConfig) (*Simulator, error) {
+	if config.FailureRate < 0 || config.FailureRate > 1 {
+		return nil, maskAnyf(invalidConfigError, "failure rate must be between 0 and 1, got %v", config.FailureRate)
+	}
+	if config.Chaos < 0 || config.Chaos > 1 {
+		return nil, maskAnyf(invalidConfigError, "chaos must be between 0 and 1, got %v", config.Chaos)
+	}
+	if config.Latency < 0 {
+		return nil, maskAnyf(invalidConfigError, "latency must not be negative, got %v", config.Latency)
+	}
+	if config.PurgeDelay < 0 {
+		return nil, maskAnyf(invalidConfigError, "purge delay must not be negative, got %v", config.PurgeDelay)
+	}
+	if config.Machines < 1 {
+		return nil, maskAnyf(invalidConfigError, "number of machines must be positive, got %d", config.Machines)
+	}
+
+	newSimulator := &Simulator{
+		Config:  config,
+		Mutex:   sync.Mutex{},
+		Rand:    rand.New(rand.NewSource(config.Seed)),
+		Units:   map[string]*simulatedUnit{},
+		Dropped: map[string]time.Time{},
+		Purging: map[string]time.Time{},
+	}
+
+	if config.Store != nil {
+		err := config.Store.Get(simulatorNamespace, simulatorName, &newSimulator.Units)
+		if state.IsNotFound(err) {
+			// Nothing has been simulated yet.
+		} else if err != nil {
+			return nil, maskAny(err)
+		}
+		if newSimulator.Units == nil {
+			newSimulator.Units = map[string]*simulatedUnit{}
+		}
+	}
+
+	return newSimulator, nil
+}
+
+// Submit stores the given unit and schedules it on a machine once the
+// latency passed.
+func (s *Simulator) Submit(ctx context.Context, name, content string) error {
+	s.Config.Logger.Debug(ctx, "simulator: submit %v", name)
+
+	unitFile, err := unit.NewUnitFile(content)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	if s.purging(name) {
+		return maskAnyf(conflictError, "unit '%s' is being purged", name)
+	}
+	s.unleashChaos(ctx)
+
+	var machineIDs []string
+	global := false
+	if values, ok := unitFile.Contents["X-Fleet"]["Global"]; ok && len(values) > 0 && values[len(values)-1] == "true" {
+		global = true
+		machineIDs = s.upMachineIDs()
+	} else {
+		machineIDs = []string{s.leastLoadedMachineID()}
+	}
+
+	s.Units[name] = &simulatedUnit{
+		Name:          name,
+		Content:       content,
+		Hash:          unitFile.Hash().String(),
+		Current:       unitStateInactive,
+		Desired:       unitStateLoaded,
+		Settles:       s.settles(),
+		MachineIDs:    machineIDs,
+		Global:        global,
+		SystemdActive: "inactive",
+		SystemdSub:    "dead",
+	}
+
+	return maskAny(s.save())
+}
+
+// Start launches the given unit once the latency passed. The unit fails
+// according to the configured failure rate.
+func (s *Simulator) Start(ctx context.Context, name string) error {
+	s.Config.Logger.Debug(ctx, "simulator: start %v", name)
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.unleashChaos(ctx)
+
+	u, ok := s.Units[name]
+	if !ok {
+		return maskAnyf(unitNotFoundError, "%s", name)
+	}
+	s.settle(u)
+	if u.Desired == unitStateLaunched {
+		return nil
+	}
+
+	u.Desired = unitStateLaunched
+	u.Settles = s.settles()
+	u.Fails = s.Rand.Float64() < s.Config.FailureRate || s.chaos()
+
+	return maskAny(s.save())
+}
+
+// Stop stops the given unit once the latency passed.
+func (s *Simulator) Stop(ctx context.Context, name string) error {
+	s.Config.Logger.Debug(ctx, "simulator: stop %v", name)
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.unleashChaos(ctx)
+
+	u, ok := s.Units[name]
+	if !ok {
+		return maskAnyf(unitNotFoundError, "%s", name)
+	}
+	s.settle(u)
+	if u.Desired == unitStateLoaded {
+		return nil
+	}
+
+	u.Desired = unitStateLoaded
+	u.Settles = s.settles()
+	u.Fails = false
+
+	return maskAny(s.save())
+}
+
+// Destroy removes the given unit immediately. It stays known to the registry
+// until SimulatorConfig.PurgeDelay passed.
+func (s *Simulator) Destroy(ctx context.Context, name string) error {
+	s.Config.Logger.Debug(ctx, "simulator: destroy %v", name)
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if _, ok := s.Units[name]; !ok {
+		return maskAnyf(unitNotFoundError, "%s", name)
+	}
+	delete(s.Units, name)
+	if s.Config.PurgeDelay > 0 {
+		s.Purging[name] = s.Config.Now().Add(s.Config.PurgeDelay)
+	}
+
+	return maskAny(s.save())
+}
+
+// GetStatus returns the UnitStatus for the given name.
+func (s *Simulator) GetStatus(ctx context.Context, name string) (UnitStatus, error) {
+	s.Config.Logger.Debug(ctx, "simulator: get status %v", name)
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	u, ok := s.Units[name]
+	if !ok {
+		return UnitStatus{}, maskAnyf(unitNotFoundError, "%s", name)
+	}
+
+	unitStatus, err := s.unitStatus(u)
+	if err != nil {
+		return UnitStatus{}, maskAny(err)
+	}
+
+	return unitStatus, nil
+}
+
+// GetStatusWithMatcher returns all UnitStatus that match, sorted by name.
+func (s *Simulator) GetStatusWithMatcher(m func(string) bool) ([]UnitStatus, error) {
+	s.Config.Logger.Debug(context.Background(), "simulator: get status with matcher")
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	unitStatusList := []UnitStatus{}
+	for _, name := range s.names() {
+		if !m(name) {
+			continue
+		}
+		unitStatus, err := s.unitStatus(s.Units[name])
+		if err != nil {
+			return []UnitStatus{}, maskAny(err)
+		}
+		unitStatusList = append(unitStatusList, unitStatus)
+	}
+	if len(unitStatusList) == 0 {
+		return []UnitStatus{}, maskAny(unitNotFoundError)
+	}
+
+	return unitStatusList, nil
+}
+
+// GetContent returns the content the given unit was submitted with.
+func (s *Simulator) GetContent(ctx context.Context, name string) (string, error) {
+	s.Config.Logger.Debug(ctx, "simulator: get content %v", name)
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	u, ok := s.Units[name]
+	if !ok {
+		return "", maskAnyf(unitNotFoundError, "%s", name)
+	}
+
+	return u.Content, nil
+}
+
+// Exists checks whether the given unit has been submitted.
+func (s *Simulator) Exists(ctx context.Context, name string) (bool, error) {
+	s.Config.Logger.Debug(ctx, "simulator: exists %v", name)
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	_, ok := s.Units[name]
+
+	return ok || s.purging(name), nil
+}
+
+// APIVersion returns the latest supported fleet API version.
+func (s *Simulator) APIVersion(ctx context.Context) (string, error) {
+	return SupportedAPIVersions[len(SupportedAPIVersions)-1], nil
+}
+
+// Machines returns the simulated machines not dropped by chaos.
+func (s *Simulator) Machines(ctx context.Context) ([]Machine, error) {
+	s.Config.Logger.Debug(ctx, "simulator: machines")
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	var machines []Machine
+	for _, machineID := range s.upMachineIDs() {
+		machines = append(machines, Machine{ID: machineID, IP: simulatedMachineIP(machineID)})
+	}
+	sort.Sort(machinesByID(machines))
+
+	return machines, nil
+}
+
+// UnitsPage returns the units sorted by name, in pages of 100 units, the
+// same way DummyFleet.UnitsPage does.
+func (s *Simulator) UnitsPage(ctx context.Context, pageToken string) (UnitPage, error) {
+	s.Config.Logger.Debug(ctx, "simulator: units page %v", pageToken)
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	names := s.names()
+	start := 0
+	if pageToken != "" {
+		var err error
+		start, err = strconv.Atoi(pageToken)
+		if err != nil || start < 0 || start > len(names) {
+			return UnitPage{}, maskAnyf(invalidPageTokenError, "%s", pageToken)
+		}
+	}
+	end := start + dummyUnitsPageSize
+	if end > len(names) {
+		end = len(names)
+	}
+
+	page := UnitPage{}
+	for _, name := range names[start:end] {
+		u := s.Units[name]
+		s.settle(u)
+		newUnit := Unit{
+			Name:    name,
+			Content: u.Content,
+			Current: u.Current,
+			Desired: u.Desired,
+		}
+		if len(u.MachineIDs) == 1 && u.Current != unitStateInactive {
+			newUnit.MachineID = u.MachineIDs[0]
+		}
+		page.Units = append(page.Units, newUnit)
+	}
+	if end < len(names) {
+		page.NextPageToken = strconv.Itoa(end)
+	}
+
+	return page, nil
+}
+
+// purging checks whether the given unit has been destroyed, but is not purged
+// from the registry yet.
+func (s *Simulator) purging(name string) bool {
+	purged, ok := s.Purging[name]
+	if !ok {
+		return false
+	}
+	if !s.Config.Now().Before(purged) {
+		delete(s.Purging, name)
+		return false
+	}
+
+	return true
+}
+
+// settle moves the given unit to its desired state in case its transition
+// is over. Otherwise the systemd states reflect the ongoing transition.
+func (s *Simulator) settle(u *simulatedUnit) {
+	if u.Current == u.Desired {
+		return
+	}
+
+	if s.Config.Now().Before(u.Settles) {
+		switch u.Desired {
+		case unitStateLaunched:
+			u.SystemdActive, u.SystemdSub = "activating", "start"
+		case unitStateLoaded:
+			if u.Current == unitStateLaunched {
+				u.SystemdActive, u.SystemdSub = "deactivating", "stop-sigterm"
+			}
+		}
+		return
+	}
+
+	u.Current = u.Desired
+	switch {
+	case u.Desired == unitStateLaunched && u.Fails:
+		u.SystemdActive, u.SystemdSub = "failed", "failed"
+	case u.Desired == unitStateLaunched:
+		u.SystemdActive, u.SystemdSub = "active", "running"
+	default:
+		u.SystemdActive, u.SystemdSub = "inactive", "dead"
+	}
+}
+
+// unitStatus settles the given unit and returns its status. Units not yet
+// scheduled are not reported on any machine.
+func (s *Simulator) unitStatus(u *simulatedUnit) (UnitStatus, error) {
+	s.settle(u)
+
+	sliceID, err := common.SliceID(u.Name)
+	if err != nil {
+		return UnitStatus{}, maskAny(err)
+	}
+
+	unitStatus := UnitStatus{
+		Current: u.Current,
+		Desired: u.Desired,
+		Name:    u.Name,
+		SliceID: sliceID,
+	}
+	if u.Current == unitStateInactive {
+		return unitStatus, nil
+	}
+	for _, machineID := range u.MachineIDs {
+		unitStatus.Machine = append(unitStatus.Machine, MachineStatus{
+			ID:            machineID,
+			IP:            simulatedMachineIP(machineID),
+			SystemdLoad:   "loaded",
+			SystemdActive: u.SystemdActive,
+			SystemdSub:    u.SystemdSub,
+			UnitHash:      u.Hash,
+		})
+	}
+
+	return unitStatus, nil
+}
+
+// settles returns the time a transition starting now is over. Chaos delays
+// transitions.
+func (s *Simulator) settles() time.Time {
+	latency := time.Duration((0.5 + s.Rand.Float64()) * float64(s.Config.Latency))
+	if s.chaos() {
+		latency *= chaosDelayFactor
+	}
+	return s.Config.Now().Add(latency)
+}
+
+// chaos decides whether chaos strikes, according to the configured chaos
+// probability.
+func (s *Simulator) chaos() bool {
+	return s.Config.Chaos > 0 && s.Rand.Float64() < s.Config.Chaos
+}
+
+// unleashChaos lets machines dropped before rejoin the cluster in case their
+// downtime is over, and drops a machine in case chaos strikes. Global units
+// are scheduled on all machines up.
+func (s *Simulator) unleashChaos(ctx context.Context) {
+	for _, machineID := range sortedKeys(s.Dropped) {
+		if s.Config.Now().Before(s.Dropped[machineID]) {
+			continue
+		}
+		s.Config.Logger.Debug(ctx, "simulator: chaos: machine %v rejoins", machineID)
+		delete(s.Dropped, machineID)
+	}
+	defer func() {
+		for _, u := range s.Units {
+			if u.Global {
+				u.MachineIDs = s.upMachineIDs()
+			}
+		}
+	}()
+
+	up := s.upMachineIDs()
+	if len(up) < 2 || !s.chaos() {
+		return
+	}
+	dropped := up[s.Rand.Intn(len(up))]
+	s.Config.Logger.Debug(ctx, "simulator: chaos: dropping machine %v", dropped)
+	s.Dropped[dropped] = s.Config.Now().Add(chaosDowntimeFactor * s.Config.Latency)
+
+	// Units of the dropped machine are rescheduled on other machines, where
+	// they need to reach their desired state again.
+	for _, name := range s.names() {
+		u := s.Units[name]
+		if !containsMachineID(u.MachineIDs, dropped) {
+			continue
+		}
+		if u.Global {
+			continue
+		}
+		s.settle(u)
+		u.MachineIDs = []string{s.leastLoadedMachineID()}
+		if u.Current == unitStateLaunched {
+			u.Current = unitStateLoaded
+			u.SystemdActive, u.SystemdSub = "inactive", "dead"
+			u.Settles = s.settles()
+		}
+	}
+}
+
+// upMachineIDs returns the IDs of all machines not dropped by chaos.
+func (s *Simulator) upMachineIDs() []string {
+	var machineIDs []string
+	for i := 0; i < s.Config.Machines; i++ {
+		machineID := simulatedMachineID(i)
+		if _, ok := s.Dropped[machineID]; !ok {
+			machineIDs = append(machineIDs, machineID)
+		}
+	}
+
+	return machineIDs
+}
+
+// leastLoadedMachineID returns the ID of the machine running the fewest
+// units. Ties are broken by machine ID. Machines dropped by chaos are not
+// considered.
+func (s *Simulator) leastLoadedMachineID() string {
+	load := map[string]int{}
+	for _, u := range s.Units {
+		for _, machineID := range u.MachineIDs {
+			load[machineID]++
+		}
+	}
+
+	up := s.upMachineIDs()
+	leastLoaded := up[0]
+	for _, machineID := range up[1:] {
+		if load[machineID] < load[leastLoaded] {
+			leastLoaded = machineID
+		}
+	}
+
+	return leastLoaded
+}
+
+func (s *Simulator) names() []string {
+	var names []string
+	for name := range s.Units {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// save persists the simulated cluster in case a store is configured.
+func (s *Simulator) save() error {
+	if s.Config.Store == nil {
+		return nil
+	}
+	if err := s.Config.Store.Set(simulatorNamespace, simulatorName, s.Units); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func sortedKeys(m map[string]time.Time) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func containsMachineID(machineIDs []string, machineID string) bool {
+	for _, id := range machineIDs {
+		if id == machineID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// simulatedMachineID returns the ID of the simulated machine with the given
+// index, e.g. "simulated-2".
+func simulatedMachineID(i int) string {
+	return "simulated-" + strconv.Itoa(i+1)
+}
+
+// simulatedMachineIP returns the IP of the simulated machine with the given
+// ID, e.g. 10.0.0.2 for "simulated-2".
+func simulatedMachineIP(machineID string) net.IP {
+	n, _ := strconv.Atoi(machineID[len("simulated-"):])
+	return net.IPv4(10, 0, byte(n/256), byte(n%256))
+}
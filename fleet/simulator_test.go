@@ -0,0 +1,263 @@
+package fleet
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/giantswarm/inago/state"
+)
+
+func testSimulatorConfig(now *time.Time) SimulatorConfig {
+	newConfig := DefaultSimulatorConfig()
+	newConfig.Latency = 10 * time.Second
+	newConfig.Seed = 1
+	newConfig.Now = func() time.Time { return *now }
+	return newConfig
+}
+
+// TestSimulator_Transitions tests that units reach their desired states once
+// the latency passed.
+func TestSimulator_Transitions(t *testing.T) {
+	now := time.Unix(0, 0)
+	simulator, err := NewSimulator(testSimulatorConfig(&now))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	ctx := context.Background()
+
+	if err := simulator.Submit(ctx, "foo@1.service", "[Unit]\nDescription=foo\n"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	status, err := simulator.GetStatus(ctx, "foo@1.service")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if status.Current != unitStateInactive || len(status.Machine) != 0 || status.SliceID != "1" {
+		t.Fatal("expected", "unscheduled unit", "got", status)
+	}
+
+	// Transitions take at most one and a half of the latency.
+	now = now.Add(15 * time.Second)
+	status, err = simulator.GetStatus(ctx, "foo@1.service")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if status.Current != unitStateLoaded || len(status.Machine) != 1 || status.Machine[0].SystemdActive != "inactive" {
+		t.Fatal("expected", "loaded unit", "got", status)
+	}
+
+	if err := simulator.Start(ctx, "foo@1.service"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	status, err = simulator.GetStatus(ctx, "foo@1.service")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if status.Desired != unitStateLaunched || status.Machine[0].SystemdActive != "activating" {
+		t.Fatal("expected", "activating unit", "got", status)
+	}
+
+	now = now.Add(15 * time.Second)
+	status, err = simulator.GetStatus(ctx, "foo@1.service")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if status.Current != unitStateLaunched || status.Machine[0].SystemdActive != "active" || status.Machine[0].SystemdSub != "running" {
+		t.Fatal("expected", "running unit", "got", status)
+	}
+
+	if err := simulator.Destroy(ctx, "foo@1.service"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if _, err := simulator.GetStatus(ctx, "foo@1.service"); !IsUnitNotFound(err) {
+		t.Fatal("expected", "unit not found error", "got", err)
+	}
+}
+
+// TestSimulator_Failures tests that started units fail according to the
+// failure rate, and that global units are scheduled on all machines.
+func TestSimulator_Failures(t *testing.T) {
+	now := time.Unix(0, 0)
+	newConfig := testSimulatorConfig(&now)
+	newConfig.FailureRate = 1
+	simulator, err := NewSimulator(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	ctx := context.Background()
+
+	if err := simulator.Submit(ctx, "foo.service", "[Unit]\nDescription=foo\n[X-Fleet]\nGlobal=true\n"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if err := simulator.Start(ctx, "foo.service"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	now = now.Add(15 * time.Second)
+
+	status, err := simulator.GetStatus(ctx, "foo.service")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(status.Machine) != newConfig.Machines {
+		t.Fatal("expected", newConfig.Machines, "got", len(status.Machine))
+	}
+	for _, ms := range status.Machine {
+		if ms.SystemdActive != "failed" {
+			t.Fatal("expected", "failed", "got", ms.SystemdActive)
+		}
+	}
+}
+
+// TestSimulator_Store tests that the simulated cluster is restored from the
+// store.
+func TestSimulator_Store(t *testing.T) {
+	now := time.Unix(0, 0)
+	newConfig := testSimulatorConfig(&now)
+	newConfig.Store = state.NewMemoryStore()
+	simulator, err := NewSimulator(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if err := simulator.Submit(context.Background(), "foo.service", "[Unit]\nDescription=foo\n"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	simulator, err = NewSimulator(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	content, err := simulator.GetContent(context.Background(), "foo.service")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if content != "[Unit]\nDescription=foo\n" {
+		t.Fatal("expected", "[Unit]\nDescription=foo\n", "got", content)
+	}
+}
+
+func TestNewSimulator_InvalidConfig(t *testing.T) {
+	newConfig := DefaultSimulatorConfig()
+	newConfig.FailureRate = 1.5
+	if _, err := NewSimulator(newConfig); !IsInvalidConfig(err) {
+		t.Fatal("expected", "invalid config error", "got", err)
+	}
+
+	newConfig = DefaultSimulatorConfig()
+	newConfig.Chaos = -0.1
+	if _, err := NewSimulator(newConfig); !IsInvalidConfig(err) {
+		t.Fatal("expected", "invalid config error", "got", err)
+	}
+
+	newConfig = DefaultSimulatorConfig()
+	newConfig.Machines = 0
+	if _, err := NewSimulator(newConfig); !IsInvalidConfig(err) {
+		t.Fatal("expected", "invalid config error", "got", err)
+	}
+
+	newConfig = DefaultSimulatorConfig()
+	newConfig.PurgeDelay = -time.Second
+	if _, err := NewSimulator(newConfig); !IsInvalidConfig(err) {
+		t.Fatal("expected", "invalid config error", "got", err)
+	}
+}
+
+// TestSimulator_PurgeDelay tests that destroyed units conflict with units
+// submitted using the same name until they are purged.
+func TestSimulator_PurgeDelay(t *testing.T) {
+	now := time.Unix(0, 0)
+	newConfig := testSimulatorConfig(&now)
+	newConfig.PurgeDelay = 5 * time.Second
+	simulator, err := NewSimulator(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	ctx := context.Background()
+
+	if err := simulator.Submit(ctx, "foo@1.service", "[Unit]\nDescription=foo\n"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if err := simulator.Destroy(ctx, "foo@1.service"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if _, err := simulator.GetStatus(ctx, "foo@1.service"); !IsUnitNotFound(err) {
+		t.Fatal("expected", "unit not found error", "got", err)
+	}
+	if exists, err := simulator.Exists(ctx, "foo@1.service"); err != nil || !exists {
+		t.Fatal("expected", true, "got", exists, err)
+	}
+	if err := simulator.Submit(ctx, "foo@1.service", "[Unit]\nDescription=foo\n"); !IsConflict(err) {
+		t.Fatal("expected", "conflict error", "got", err)
+	}
+
+	now = now.Add(5 * time.Second)
+	if exists, err := simulator.Exists(ctx, "foo@1.service"); err != nil || exists {
+		t.Fatal("expected", false, "got", exists, err)
+	}
+	if err := simulator.Submit(ctx, "foo@1.service", "[Unit]\nDescription=foo\n"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+}
+
+// TestSimulator_Chaos tests that chaos fails started units, and drops
+// machines, rescheduling their units on the machines up.
+func TestSimulator_Chaos(t *testing.T) {
+	now := time.Unix(0, 0)
+	newConfig := testSimulatorConfig(&now)
+	newConfig.Chaos = 1
+	newConfig.Machines = 2
+	simulator, err := NewSimulator(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	ctx := context.Background()
+
+	// Submitting drops one of the two machines, so all units are scheduled on
+	// the other one.
+	for _, name := range []string{"foo@1.service", "foo@2.service"} {
+		if err := simulator.Submit(ctx, name, "[Unit]\nDescription=foo\n"); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+	if len(simulator.Dropped) != 1 {
+		t.Fatal("expected", 1, "got", len(simulator.Dropped))
+	}
+	if simulator.Units["foo@1.service"].MachineIDs[0] != simulator.Units["foo@2.service"].MachineIDs[0] {
+		t.Fatal("expected units on the same machine, got", simulator.Units["foo@1.service"].MachineIDs, simulator.Units["foo@2.service"].MachineIDs)
+	}
+	if _, ok := simulator.Dropped[simulator.Units["foo@1.service"].MachineIDs[0]]; ok {
+		t.Fatal("expected units on the machine up")
+	}
+
+	// Transitions are delayed by chaos, and started units fail.
+	if err := simulator.Start(ctx, "foo@1.service"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	now = now.Add(15 * time.Second)
+	status, err := simulator.GetStatus(ctx, "foo@1.service")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if status.Current == unitStateLaunched {
+		t.Fatal("expected", "delayed transition", "got", status)
+	}
+	now = now.Add(time.Duration(chaosDelayFactor) * 15 * time.Second)
+	status, err = simulator.GetStatus(ctx, "foo@1.service")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if status.Current != unitStateLaunched || status.Machine[0].SystemdActive != "failed" {
+		t.Fatal("expected", "failed unit", "got", status)
+	}
+
+	// Dropped machines rejoin once their downtime is over.
+	now = now.Add(chaosDowntimeFactor * newConfig.Latency)
+	simulator.Config.Chaos = 0
+	if err := simulator.Stop(ctx, "foo@1.service"); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(simulator.Dropped) != 0 {
+		t.Fatal("expected", 0, "got", len(simulator.Dropped))
+	}
+}
@@ -106,11 +106,17 @@ func (t *sshTunnel) NewDialFunc() (func(string, string) (net.Conn, error), error
 // NewHostKeyChecker creates a new HostKeyChecker, or nil if any error is
 // encountered.
 func (t *sshTunnel) NewHostKeyChecker() *ssh.HostKeyChecker {
-	if !t.StrictHostKeyChecking {
+	return newHostKeyChecker(t.StrictHostKeyChecking, t.KnownHostsFile)
+}
+
+// newHostKeyChecker creates a HostKeyChecker using the given known hosts
+// file, or nil in case host keys are not checked strictly.
+func newHostKeyChecker(strict bool, knownHostsFile string) *ssh.HostKeyChecker {
+	if !strict {
 		return nil
 	}
 
-	keyFile := ssh.NewHostKeyFile(t.KnownHostsFile)
+	keyFile := ssh.NewHostKeyFile(knownHostsFile)
 	return ssh.NewHostKeyChecker(keyFile)
 }
 
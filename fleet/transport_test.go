@@ -0,0 +1,152 @@
+package fleet
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/coreos/fleet/schema"
+
+	"github.com/giantswarm/inago/logging"
+)
+
+// newRegistryServer creates a server answering like the fleet API of a
+// cluster running the given number of units, spread over 100 machines. Like
+// reverse proxies in front of fleet, it compresses responses in case the
+// client accepts gzip. The Accept-Encoding headers received are recorded in
+// the given slice, unless it is nil.
+func newRegistryServer(n int, encodings *[]string) *httptest.Server {
+	var units []*schema.Unit
+	var states []*schema.UnitState
+	var machines []*schema.Machine
+	for i := 0; i < 100; i++ {
+		machines = append(machines, &schema.Machine{Id: fmt.Sprintf("machine-%d", i), PrimaryIP: fmt.Sprintf("10.0.0.%d", i)})
+	}
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("app-main@%d.service", i)
+		machineID := fmt.Sprintf("machine-%d", i%100)
+		units = append(units, &schema.Unit{
+			Name:         name,
+			DesiredState: "launched",
+			CurrentState: "launched",
+			MachineID:    machineID,
+			Options: []*schema.UnitOption{
+				{Section: "Unit", Name: "Description", Value: "app main"},
+				{Section: "Service", Name: "ExecStart", Value: "/usr/bin/docker run --rm --name app-main-%i registry.example.com/app:1.0.0"},
+				{Section: "X-Fleet", Name: "Conflicts", Value: "app-main@*.service"},
+			},
+		})
+		states = append(states, &schema.UnitState{Name: name, Hash: "abc", MachineID: machineID, SystemdLoadState: "loaded", SystemdActiveState: "active", SystemdSubState: "running"})
+	}
+
+	pages := map[string]interface{}{
+		"/fleet/v1/units":    schema.UnitPage{Units: units},
+		"/fleet/v1/state":    schema.UnitStatePage{States: states},
+		"/fleet/v1/machines": schema.MachinePage{Machines: machines},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if encodings != nil {
+			*encodings = append(*encodings, r.Header.Get("Accept-Encoding"))
+		}
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			json.NewEncoder(w).Encode(page)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gw := gzip.NewWriter(w)
+		json.NewEncoder(gw).Encode(page)
+		gw.Close()
+	}))
+}
+
+func newRegistryFleet(t testing.TB, server *httptest.Server, compression bool) Fleet {
+	endpoint, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newLoggingConfig := logging.DefaultConfig()
+	newLoggingConfig.LogLevel = "ERROR"
+
+	config := DefaultConfig()
+	config.Endpoint = *endpoint
+	config.Logger = logging.NewLogger(newLoggingConfig)
+	config.RateLimit = 0
+	config.Compression = compression
+	newFleet, err := NewFleet(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return newFleet
+}
+
+func TestFleet_Compression(t *testing.T) {
+	var encodings []string
+	server := newRegistryServer(10, &encodings)
+	defer server.Close()
+
+	for _, compression := range []bool{true, false} {
+		encodings = nil
+		us, err := newRegistryFleet(t, server, compression).GetStatusWithMatcher(func(s string) bool { return true })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(us) != 10 || us[0].Machine[0].SystemdSub != "running" {
+			t.Fatal("expected", 10, "running units", "got", us)
+		}
+
+		expected := ""
+		if compression {
+			expected = "gzip"
+		}
+		for _, encoding := range encodings {
+			if encoding != expected {
+				t.Fatal("expected", expected, "got", encoding)
+			}
+		}
+	}
+}
+
+func TestFleet_MaxIdleConnsPerHost(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxIdleConnsPerHost = -1
+	if _, err := NewFleet(config); !IsInvalidConfig(err) {
+		t.Fatal("expected", "invalid config error", "got", err)
+	}
+}
+
+// benchmarkUnits lists all units of a registry of the given size, the way
+// status and update do, concurrently using the given number of goroutines.
+func benchmarkUnits(b *testing.B, units, parallelism int, compression bool) {
+	server := newRegistryServer(units, nil)
+	defer server.Close()
+	newFleet := newRegistryFleet(b, server, compression)
+
+	b.SetParallelism(parallelism)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := newFleet.GetStatusWithMatcher(func(s string) bool { return true }); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkFleet_Units_5000(b *testing.B)            { benchmarkUnits(b, 5000, 1, false) }
+func BenchmarkFleet_Units_5000_Compressed(b *testing.B) { benchmarkUnits(b, 5000, 1, true) }
+func BenchmarkFleet_Units_5000_Parallel(b *testing.B)   { benchmarkUnits(b, 5000, 8, true) }
+
@@ -0,0 +1,65 @@
+package fleet
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+
+	"golang.org/x/net/context"
+)
+
+var (
+	// SupportedAPIVersions contains all versions of the fleet API Inago is known
+	// to work with.
+	SupportedAPIVersions = []string{"v1"}
+)
+
+// IsSupportedAPIVersion checks whether the given fleet API version is
+// contained in SupportedAPIVersions.
+func IsSupportedAPIVersion(version string) bool {
+	for _, v := range SupportedAPIVersions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// discoveryDocument represents the parts of fleet's API discovery document we
+// are interested in.
+type discoveryDocument struct {
+	Version string `json:"version"`
+}
+
+func (f fleet) APIVersion(ctx context.Context) (string, error) {
+	f.Config.Logger.Debug(ctx, "fleet: fetching API version")
+
+	URL := f.Config.Endpoint
+	URL.Path = path.Join(URL.Path, "fleet", "v1", "discovery")
+
+	var resp *http.Response
+	err := f.Config.Hooks.call("Discovery", func() error {
+		var err error
+		resp, err = f.Config.Client.Get(URL.String())
+		return err
+	})
+	if err != nil {
+		return "", maskAny(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", maskAnyf(invalidAPIResponseError, "unexpected status code %d", resp.StatusCode)
+	}
+
+	var doc discoveryDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return "", maskAnyf(invalidAPIResponseError, "%s", err.Error())
+	}
+	if doc.Version == "" {
+		return "", maskAnyf(invalidAPIResponseError, "discovery document has no version")
+	}
+
+	return doc.Version, nil
+}
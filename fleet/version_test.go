@@ -0,0 +1,79 @@
+package fleet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+func givenFleetWithHandler(handler http.HandlerFunc) (Fleet, *httptest.Server) {
+	server := httptest.NewServer(handler)
+
+	URL, err := url.Parse(server.URL)
+	if err != nil {
+		panic(err)
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.Endpoint = *URL
+	newFleet, err := NewFleet(newConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	return newFleet, server
+}
+
+func TestFleetAPIVersion_Success(t *testing.T) {
+	RegisterTestingT(t)
+
+	var requestedPath string
+	newFleet, server := givenFleetWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		w.Write([]byte(`{"kind": "discovery#restDescription", "version": "v1"}`))
+	})
+	defer server.Close()
+
+	version, err := newFleet.APIVersion(context.Background())
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(version).To(Equal("v1"))
+	Expect(requestedPath).To(Equal("/fleet/v1/discovery"))
+	Expect(IsSupportedAPIVersion(version)).To(BeTrue())
+}
+
+func TestFleetAPIVersion_Error(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []http.HandlerFunc{
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`not json`))
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{}`))
+		},
+	}
+
+	for _, handler := range testCases {
+		newFleet, server := givenFleetWithHandler(handler)
+
+		_, err := newFleet.APIVersion(context.Background())
+		Expect(IsInvalidAPIResponse(err)).To(BeTrue())
+
+		server.Close()
+	}
+}
+
+func TestIsSupportedAPIVersion(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(IsSupportedAPIVersion("v1")).To(BeTrue())
+	Expect(IsSupportedAPIVersion("v2")).To(BeFalse())
+	Expect(IsSupportedAPIVersion("")).To(BeFalse())
+}
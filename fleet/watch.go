@@ -0,0 +1,131 @@
+package fleet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval is the time WatchUnitStates waits between fetching
+// unit states, unless told otherwise.
+const DefaultWatchInterval = 1 * time.Second
+
+// UnitState describes a change of a unit seen by WatchUnitStates.
+type UnitState struct {
+	// Name is the name of the unit, e.g. "mygroup-foo@1.service".
+	Name string
+
+	// Status is the current status of the unit. It is the zero value in case
+	// the unit was removed.
+	Status UnitStatus
+
+	// Removed is true in case the unit is not known to fleet anymore.
+	Removed bool
+
+	// Err is the error fetching unit states failed with. Name and Status are
+	// empty then. Watching continues after the next interval.
+	Err error
+}
+
+// WatchUnitStates watches the states of all units whose names match the
+// given matcher, which works like the one of Fleet.GetStatusWithMatcher.
+// Fleet's API does not support subscriptions, so unit states are fetched
+// each interval. Only changes are sent to the returned channel: units showing
+// up, units whose status changed, and units being removed. Changes of a
+// single fetch are sent sorted by unit name. The first fetch reports all
+// matching units. Calling the returned function stops watching and closes the
+// channel. It must be called to release the watch.
+//
+//   changes, stop := fleet.WatchUnitStates(newFleet, matcher, fleet.DefaultWatchInterval)
+//   defer stop()
+//   for change := range changes {
+//     fmt.Println(change.Name, change.Status.Current, change.Removed)
+//   }
+//
+func WatchUnitStates(f Fleet, matcher func(string) bool, interval time.Duration) (<-chan UnitState, func()) {
+	changes := make(chan UnitState)
+	done := make(chan struct{})
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() { close(done) })
+	}
+
+	go func() {
+		defer close(changes)
+
+		seen := map[string]string{}
+		for {
+			for _, change := range fetchUnitStateChanges(f, matcher, seen) {
+				select {
+				case changes <- change:
+				case <-done:
+					return
+				}
+			}
+
+			select {
+			case <-time.After(interval):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return changes, stop
+}
+
+// fetchUnitStateChanges fetches the states of all units matching the given
+// matcher and returns the ones that changed compared to the given states seen
+// before, which are updated accordingly. The given map stores a fingerprint
+// of the status of each unit, see unitStatusFingerprint.
+func fetchUnitStateChanges(f Fleet, matcher func(string) bool, seen map[string]string) []UnitState {
+	usl, err := f.GetStatusWithMatcher(matcher)
+	if IsUnitNotFound(err) {
+		usl = nil
+	} else if err != nil {
+		return []UnitState{{Err: maskAny(err)}}
+	}
+
+	var changes []UnitState
+	current := map[string]struct{}{}
+	for _, us := range usl {
+		current[us.Name] = struct{}{}
+		fingerprint := unitStatusFingerprint(us)
+		if last, ok := seen[us.Name]; ok && last == fingerprint {
+			continue
+		}
+		seen[us.Name] = fingerprint
+		changes = append(changes, UnitState{Name: us.Name, Status: us})
+	}
+	for name := range seen {
+		if _, ok := current[name]; !ok {
+			delete(seen, name)
+			changes = append(changes, UnitState{Name: name, Removed: true})
+		}
+	}
+	sort.Sort(unitStatesByName(changes))
+
+	return changes
+}
+
+// unitStatusFingerprint returns a string identifying the given status, so
+// that statuses can be compared regardless of the order fleet reports the
+// machines of global units in.
+func unitStatusFingerprint(us UnitStatus) string {
+	var machines []string
+	for _, ms := range us.Machine {
+		machines = append(machines, fmt.Sprintf("%s/%s/%s/%s/%s/%s/%t", ms.ID, ms.IP, ms.SystemdLoad, ms.SystemdActive, ms.SystemdSub, ms.UnitHash, ms.Vanished))
+	}
+	sort.Strings(machines)
+
+	return fmt.Sprintf("%s/%s/%s", us.Current, us.Desired, strings.Join(machines, ","))
+}
+
+type unitStatesByName []UnitState
+
+func (u unitStatesByName) Len() int           { return len(u) }
+func (u unitStatesByName) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u unitStatesByName) Less(i, j int) bool { return u[i].Name < u[j].Name }
@@ -0,0 +1,81 @@
+package fleet
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+func TestWatch_fetchUnitStateChanges(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	dummyFleet := NewDummyFleet(DefaultDummyConfig())
+	matcher := func(name string) bool { return name != "other.service" }
+	seen := map[string]string{}
+
+	// Nothing is deployed yet.
+	Expect(fetchUnitStateChanges(dummyFleet, matcher, seen)).To(BeEmpty())
+
+	Expect(dummyFleet.Submit(ctx, "b.service", "")).To(Succeed())
+	Expect(dummyFleet.Submit(ctx, "a.service", "")).To(Succeed())
+	Expect(dummyFleet.Submit(ctx, "other.service", "")).To(Succeed())
+	changes := fetchUnitStateChanges(dummyFleet, matcher, seen)
+	Expect(changes).To(HaveLen(2))
+	Expect(changes[0].Name).To(Equal("a.service"))
+	Expect(changes[1].Name).To(Equal("b.service"))
+	Expect(changes[0].Status.Current).To(Equal(unitStateLoaded))
+
+	// Unchanged units are not reported again.
+	Expect(fetchUnitStateChanges(dummyFleet, matcher, seen)).To(BeEmpty())
+
+	Expect(dummyFleet.Start(ctx, "b.service")).To(Succeed())
+	changes = fetchUnitStateChanges(dummyFleet, matcher, seen)
+	Expect(changes).To(HaveLen(1))
+	Expect(changes[0].Name).To(Equal("b.service"))
+	Expect(changes[0].Status.Current).To(Equal(unitStateLaunched))
+
+	Expect(dummyFleet.Destroy(ctx, "a.service")).To(Succeed())
+	changes = fetchUnitStateChanges(dummyFleet, matcher, seen)
+	Expect(changes).To(Equal([]UnitState{{Name: "a.service", Removed: true}}))
+}
+
+func TestWatch_unitStatusFingerprint(t *testing.T) {
+	RegisterTestingT(t)
+
+	m1 := MachineStatus{ID: "m1", IP: net.ParseIP("10.0.0.1"), SystemdActive: "active", SystemdSub: "running"}
+	m2 := MachineStatus{ID: "m2", IP: net.ParseIP("10.0.0.2"), SystemdActive: "active", SystemdSub: "running"}
+	us := UnitStatus{Name: "a.service", Current: "launched", Desired: "launched", Machine: []MachineStatus{m1, m2}}
+	reordered := UnitStatus{Name: "a.service", Current: "launched", Desired: "launched", Machine: []MachineStatus{m2, m1}}
+	Expect(unitStatusFingerprint(us)).To(Equal(unitStatusFingerprint(reordered)))
+
+	m2.SystemdSub = "exited"
+	changed := UnitStatus{Name: "a.service", Current: "launched", Desired: "launched", Machine: []MachineStatus{m1, m2}}
+	Expect(unitStatusFingerprint(us)).NotTo(Equal(unitStatusFingerprint(changed)))
+}
+
+func TestWatch_WatchUnitStates(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	dummyFleet := NewDummyFleet(DefaultDummyConfig())
+	Expect(dummyFleet.Submit(ctx, "a.service", "")).To(Succeed())
+
+	changes, stop := WatchUnitStates(dummyFleet, func(string) bool { return true }, 10*time.Millisecond)
+
+	var change UnitState
+	Eventually(changes).Should(Receive(&change))
+	Expect(change.Name).To(Equal("a.service"))
+
+	Expect(dummyFleet.Start(ctx, "a.service")).To(Succeed())
+	Eventually(changes).Should(Receive(&change))
+	Expect(change.Status.Current).To(Equal(unitStateLaunched))
+
+	stop()
+	// Stopping twice must not panic.
+	stop()
+	Eventually(changes).Should(BeClosed())
+}
@@ -6,25 +6,81 @@
     inagoctl [command]
   
   Available Commands:
-    submit      Submit a group
-    status      Get group status
-    start       Start a group
-    stop        Stop a group
-    destroy     Destroy a group
-    up          Bring a group up
-    update      Update a group
-    validate    Validate groups
-    version     Print version
+    submit         Submit a group
+    status         Get group status
+    endpoints      Print the addresses of the active slices of a group
+    exists         Check whether a group exists
+    wait           Wait for a group to reach a state
+    start          Start a group
+    stop           Stop a group
+    destroy        Destroy a group
+    up             Bring a group up
+    scale          Scale a group
+    deploy         Deploy a group
+    clone          Clone a group
+    drain          Drain a machine
+    repair         Repair groups
+    export         Export a group
+    sign           Sign a group
+    adopt          Adopt existing units
+    orphans        List orphaned units
+    pull           Pull a group
+    update         Update a group
+    plan           Show the changes needed to deploy a group
+    dev            Deploy a group on every change
+    undo           Undo the last operation of a group
+    stats          Show deployment statistics
+    top            Show a live dashboard of groups
+    pin            Pin slices of a group
+    unpin          Unpin slices of a group
+    annotate       Annotate slices of a group
+    validate       Validate groups
+    init           Create a group
+    list           List groups
+    stack          Manage a stack of groups
+    freeze         Manage deployment freezes
+    cluster-health Check the health of the cluster
+    clusters       List the clusters of the configuration file
+    config         Manage the configuration file
+    completion     Print bash completion
+    version        Print version
   
   Flags:
+        --backend string                 backend operations are executed against, one of fleet or simulator (default "fleet")
+        --ca-file string                 CA certificate file used to verify the fleet endpoint, for https endpoints
+        --cert-file string               client certificate file used to authenticate against the fleet endpoint, for https endpoints
+        --cluster string                 cluster of the config file to connect to, setting fleet-endpoint, tunnel and TLS files
+        --color string                   color status output, one of auto, always or never (default "auto")
+        --config string                  configuration file, e.g. defining notifications (default "~/.inago/config.yaml")
+        --env string                     environment whose overlay is merged over the definitions of groups, i.e. <group>/overlays/<env>
         --fleet-endpoint string          endpoint used to connect to fleet (default "unix:///var/run/fleet.sock")
+        --force-policy                   execute operations even though they violate the policy
+        --format string                  print results and progress as table, json, yaml or jsonl, or endpoints as hosts (default "table")
     -h, --help                           help for inagoctl
+        --idempotency-key string         operations already applied using this key are skipped, e.g. when retrying in CI
+        --key-file string                client key file used to authenticate against the fleet endpoint, for https endpoints
         --no-block                       block on synchronous actions
+        --no-tty                         print progress line by line instead of updating it in place
+        --override-freeze                execute operations even though groups are frozen
+        --policy-file string             file defining rules that restrict operations against groups
+        --pre-pull-images                pull Docker images of groups on their machines before starting them
+        --profile string                 profile of the config file setting defaults of global flags (the current profile by default)
+        --proxy string                   HTTP or SOCKS5 proxy requests to http and https fleet endpoints are sent through, e.g. http://proxy:3128 (HTTP_PROXY and HTTPS_PROXY by default)
+        --rate-limit float               maximum number of requests per second sent to fleet (0 disables the limit) (default 20)
+        --report string                  file a JSON report of mutating commands is written to, including timings, states and errors of slices
+        --simulator-chaos float          probability of chaos failing units, delaying transitions and dropping machines of the simulator, from 0 to 1
+        --simulator-failure-rate float   probability of units started by the simulator to fail, from 0 to 1
+        --simulator-latency duration     average time units of the simulator take to change their state (default 2s)
+        --simulator-seed int             seed making latencies and failures of the simulator reproducible (random by default)
+        --slice-ids string               pattern IDs of new slices are taken from, e.g. 'web-{01..20}' (random IDs by default)
         --ssh-known-hosts-file string    file used to store remote machine fingerprints (default "~/.fleetctl/known_hosts")
         --ssh-strict-host-key-checking   verify host keys presented by remote machines before initiating SSH connections (default true)
         --ssh-timeout duration           timeout in seconds when establishing the connection via SSH (default 10s)
         --ssh-username string            username to use when connecting to CoreOS machine (default "core")
+        --state-dir string               directory used to store state like the history of groups (default "~/.inago/state")
         --tunnel string                  use a tunnel to communicate with fleet
     -v, --verbose                        verbose output
+        --verify-images                  verify that the Docker images of groups exist in their registries before submitting them
+        --verify-key string              public key the signatures of groups are verified with before submitting them
   
   Use "inagoctl [command] --help" for more information about a command.
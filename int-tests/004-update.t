@@ -19,7 +19,7 @@ Submit units
 
 Modify unit and perform update
   $ echo "[Unit]\nDescription=Unit 1 (CHANGED)\n[Service]\nExecStart=/bin/bash -c 'while true; do echo Hello %n; sleep 10; done'\n" > $GROUP/$GROUP-1-unit@.service
-  $ inagoctl --fleet-endpoint=${FLEET_ENDPOINT} update $GROUP --max-growth=1 --min-alive=1 > 48.out 2>&1
+  $ inagoctl --fleet-endpoint=${FLEET_ENDPOINT} update --yes $GROUP --max-growth=1 --min-alive=1 > 48.out 2>&1
   $ sleep 10
 
 Shut down
@@ -18,7 +18,7 @@ Submit units
 
 Modify unit and perform update - invalid, as we cannot have a min-alive greater than the original number of units
   $ echo "[Unit]\nDescription=Unit 1 (CHANGED)\n[Service]\nExecStart=/bin/bash -c 'while true; do echo Hello %n; sleep 10; done'\n" > $GROUP/$GROUP-unit@.service
-  $ inagoctl --fleet-endpoint=${FLEET_ENDPOINT} update $GROUP --max-growth=$MAX_GROWTH --min-alive=$MIN_ALIVE
+  $ inagoctl --fleet-endpoint=${FLEET_ENDPOINT} update --yes $GROUP --max-growth=$MAX_GROWTH --min-alive=$MIN_ALIVE
   [a-zA-Z0-9\[\{\/\.\:\}\s]* update not allowed: cannot have minimum alive units greater than current number of units}] (re)
   [1]
   $ sleep 10
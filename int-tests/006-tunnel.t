@@ -27,14 +27,14 @@ Test the status of the update group, after starting
   002-tunnel-group@[a-z\d]{3}\s*\*\s*launched\s*launched\s*active\s*[0-9.]*\s*[a-z0-9]* (re)
   
 Update update-group without changing the unit file first.
-  $ inagoctl --tunnel=${INAGO_TUNNEL_ENDPOINT} --ssh-strict-host-key-checking=false update --max-growth 1 --min-alive 1 $GROUP
+  $ inagoctl --tunnel=${INAGO_TUNNEL_ENDPOINT} --ssh-strict-host-key-checking=false update --yes --max-growth 1 --min-alive 1 $GROUP
   .*\|\scontext.Background: Not updating group '002-tunnel-group'. \(units already up to date\) (re)
 
 Changing content of update-group-bar unit file.
   $ echo "[Unit]\nDescription=Inago Update Test Unit CHANGED\n\n[Service]\nExecStart=/bin/bash -c \"while true; do echo Hi; sleep 10; done\"" > $GROUP/$GROUP-bar@.service
 
 Update update-group.
-  $ inagoctl --tunnel=${INAGO_TUNNEL_ENDPOINT} --ssh-strict-host-key-checking=false update --max-growth 2 --min-alive 1 $GROUP
+  $ inagoctl --tunnel=${INAGO_TUNNEL_ENDPOINT} --ssh-strict-host-key-checking=false update --yes --max-growth 2 --min-alive 1 $GROUP
   .*controller: adding units (re)
   .*controller: adding units (re)
   .*controller: removing units (re)
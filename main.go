@@ -8,6 +8,9 @@ import (
 )
 
 func main() {
+	// Commands not known to inagoctl may be provided by plugins.
+	cli.ExecutePlugin(os.Args[1:])
+
 	if err := cli.MainCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(-1)
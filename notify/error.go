@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"fmt"
+
+	"github.com/juju/errgo"
+)
+
+var (
+	maskAny = errgo.MaskFunc(errgo.Any)
+)
+
+func maskAnyf(err error, f string, v ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	f = fmt.Sprintf("%s: %s", err.Error(), f)
+	newErr := errgo.WithCausef(nil, errgo.Cause(err), f, v...)
+	newErr.(*errgo.Err).SetLocation(1)
+
+	return newErr
+}
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig checks whether the given error indicates that a notifier
+// cannot be created using the given configuration, e.g. because the webhook
+// URL is missing.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var notificationFailedError = errgo.New("notification failed")
+
+// IsNotificationFailed checks whether the given error indicates that a
+// notification was rejected by the receiving service.
+func IsNotificationFailed(err error) bool {
+	return errgo.Cause(err) == notificationFailedError
+}
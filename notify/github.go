@@ -0,0 +1,224 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// GitHubConfig holds configuration for the GitHub notifier.
+type GitHubConfig struct {
+	// Client is the HTTP client used to call the GitHub API.
+	Client *http.Client
+
+	// APIURL is the URL of the GitHub API, e.g.
+	// "https://github.example.com/api/v3" for GitHub Enterprise.
+	APIURL string
+
+	// Token is the access token used to authenticate against the GitHub API.
+	// It needs the "repo_deployment" scope.
+	Token string
+
+	// Repository is the repository deployments are created in, e.g.
+	// "giantswarm/inago".
+	Repository string
+
+	// Ref is the branch, tag or commit SHA deployed.
+	Ref string
+
+	// Environment is the environment deployed to, e.g. "staging".
+	Environment string
+
+	// Operations are the operations deployments are created for. Others are
+	// ignored.
+	Operations []string
+}
+
+// DefaultGitHubConfig provides a set of configurations with default values by
+// best effort.
+func DefaultGitHubConfig() GitHubConfig {
+	return GitHubConfig{
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		APIURL:      "https://api.github.com",
+		Token:       "",
+		Repository:  "",
+		Ref:         "",
+		Environment: "production",
+		Operations:  []string{"up", "update"},
+	}
+}
+
+// NewGitHub creates a Notifier tracking operations as GitHub deployments, so
+// that their state shows up on pull requests. A deployment of the configured
+// ref is created once an operation starts, and its status goes from pending to
+// in_progress, and to success or failure once the operation finished.
+//
+//   newGitHubConfig := notify.DefaultGitHubConfig()
+//   newGitHubConfig.Token = os.Getenv("GITHUB_TOKEN")
+//   newGitHubConfig.Repository = "giantswarm/inago"
+//   newGitHubConfig.Ref = "master"
+//   newGitHub, err := notify.NewGitHub(newGitHubConfig)
+//
+func NewGitHub(config GitHubConfig) (Notifier, error) {
+	if config.Token == "" {
+		return nil, maskAnyf(invalidConfigError, "GitHub token must not be empty")
+	}
+	if strings.Count(config.Repository, "/") != 1 || strings.HasPrefix(config.Repository, "/") || strings.HasSuffix(config.Repository, "/") {
+		return nil, maskAnyf(invalidConfigError, "GitHub repository must have the form owner/name, got '%s'", config.Repository)
+	}
+	if config.Ref == "" {
+		return nil, maskAnyf(invalidConfigError, "GitHub ref must not be empty")
+	}
+	if config.Environment == "" {
+		return nil, maskAnyf(invalidConfigError, "GitHub environment must not be empty")
+	}
+
+	newGitHub := &github{
+		GitHubConfig: config,
+	}
+
+	return newGitHub, nil
+}
+
+type github struct {
+	GitHubConfig
+
+	// deploymentID is the ID of the deployment of the current operation. It is
+	// zero in case no deployment was created.
+	deploymentID int64
+	mutex        sync.Mutex
+}
+
+// githubDeployment is the payload creating a GitHub deployment. Required
+// contexts are empty, so that deployments are created regardless of the
+// state of the ref's checks.
+type githubDeployment struct {
+	ID               int64    `json:"id,omitempty"`
+	Ref              string   `json:"ref,omitempty"`
+	Task             string   `json:"task,omitempty"`
+	Environment      string   `json:"environment,omitempty"`
+	Description      string   `json:"description,omitempty"`
+	AutoMerge        bool     `json:"auto_merge"`
+	RequiredContexts []string `json:"required_contexts"`
+}
+
+// githubDeploymentStatus is the payload creating a status of a GitHub
+// deployment.
+type githubDeploymentStatus struct {
+	State       string `json:"state"`
+	Description string `json:"description,omitempty"`
+}
+
+var githubPhaseStates = map[Phase]string{
+	PhaseSucceeded: "success",
+	PhaseFailed:    "failure",
+}
+
+func (g *github) Notify(ctx context.Context, m Message) error {
+	if !g.tracks(m.Operation) {
+		return nil
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if m.Phase == PhaseStarted {
+		g.deploymentID = 0
+		var deployment githubDeployment
+		err := g.post("deployments", githubDeployment{
+			Ref:              g.Ref,
+			Task:             "deploy",
+			Environment:      g.Environment,
+			Description:      m.Command(),
+			AutoMerge:        false,
+			RequiredContexts: []string{},
+		}, &deployment)
+		if err != nil {
+			return maskAny(err)
+		}
+		g.deploymentID = deployment.ID
+
+		for _, state := range []string{"pending", "in_progress"} {
+			if err := g.postStatus(state, m.String()); err != nil {
+				return maskAny(err)
+			}
+		}
+
+		return nil
+	}
+
+	if g.deploymentID == 0 {
+		// Creating the deployment failed, which was reported already.
+		return nil
+	}
+	if err := g.postStatus(githubPhaseStates[m.Phase], m.String()); err != nil {
+		return maskAny(err)
+	}
+	g.deploymentID = 0
+
+	return nil
+}
+
+// tracks checks whether deployments are created for the given operation.
+func (g *github) tracks(operation string) bool {
+	for _, o := range g.Operations {
+		if o == operation {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *github) postStatus(state, description string) error {
+	path := fmt.Sprintf("deployments/%d/statuses", g.deploymentID)
+	if err := g.post(path, githubDeploymentStatus{State: state, Description: description}, nil); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// post posts the given payload to the given path of the repository's API,
+// decoding the response into the given value, unless it is nil.
+func (g *github) post(path string, payload, v interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	URL := strings.TrimSuffix(g.APIURL, "/") + "/repos/" + g.Repository + "/" + path
+	req, err := http.NewRequest("POST", URL, bytes.NewReader(b))
+	if err != nil {
+		return maskAny(err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "token "+g.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return maskAnyf(notificationFailedError, "GitHub answered %s: %s", resp.Status, string(body))
+	}
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return maskAny(err)
+		}
+	}
+
+	return nil
+}
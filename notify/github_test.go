@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func Test_GitHub_Notify(t *testing.T) {
+	var deployments []githubDeployment
+	var statuses []string
+	status := http.StatusCreated
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token XXX" {
+			t.Fatal("expected", "token XXX", "got", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/repos/giantswarm/inago/deployments":
+			var deployment githubDeployment
+			if err := json.NewDecoder(r.Body).Decode(&deployment); err != nil {
+				t.Fatal("expected", nil, "got", err)
+			}
+			deployments = append(deployments, deployment)
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(githubDeployment{ID: 42})
+		case "/repos/giantswarm/inago/deployments/42/statuses":
+			var s githubDeploymentStatus
+			if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+				t.Fatal("expected", nil, "got", err)
+			}
+			statuses = append(statuses, s.State)
+			w.WriteHeader(status)
+		default:
+			t.Fatal("expected", "deployments API", "got", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	newGitHubConfig := DefaultGitHubConfig()
+	newGitHubConfig.APIURL = server.URL
+	newGitHubConfig.Token = "XXX"
+	newGitHubConfig.Repository = "giantswarm/inago"
+	newGitHubConfig.Ref = "abc123"
+	newGitHubConfig.Environment = "staging"
+	newGitHub, err := NewGitHub(newGitHubConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	messages := []Message{
+		{Operation: "up", Args: []string{"myapp", "3"}, Phase: PhaseStarted},
+		{Operation: "up", Args: []string{"myapp", "3"}, Phase: PhaseSucceeded, Duration: 62 * time.Second},
+		{Operation: "update", Args: []string{"myapp"}, Phase: PhaseStarted},
+		{Operation: "update", Args: []string{"myapp"}, Phase: PhaseFailed, Duration: 3 * time.Second},
+		// Operations not tracked are ignored.
+		{Operation: "destroy", Args: []string{"myapp"}, Phase: PhaseStarted},
+		{Operation: "destroy", Args: []string{"myapp"}, Phase: PhaseSucceeded},
+	}
+	for _, m := range messages {
+		if err := newGitHub.Notify(context.Background(), m); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	expectedDeployment := githubDeployment{Ref: "abc123", Task: "deploy", Environment: "staging", Description: "inagoctl up myapp 3", RequiredContexts: []string{}}
+	if len(deployments) != 2 || !reflect.DeepEqual(deployments[0], expectedDeployment) {
+		t.Fatal("expected", expectedDeployment, "got", deployments)
+	}
+	expectedStatuses := []string{"pending", "in_progress", "success", "pending", "in_progress", "failure"}
+	if !reflect.DeepEqual(statuses, expectedStatuses) {
+		t.Fatal("expected", expectedStatuses, "got", statuses)
+	}
+
+	// Without deployment, the outcome of the operation is not posted.
+	status = http.StatusUnprocessableEntity
+	statuses = nil
+	if err := newGitHub.Notify(context.Background(), messages[0]); !IsNotificationFailed(err) {
+		t.Fatal("expected", "notification failed error", "got", err)
+	}
+	if err := newGitHub.Notify(context.Background(), messages[1]); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(statuses) != 0 {
+		t.Fatal("expected", 0, "got", statuses)
+	}
+}
+
+func Test_NewGitHub_InvalidConfig(t *testing.T) {
+	for _, repository := range []string{"", "inago", "giantswarm/", "giantswarm/inago/x"} {
+		newGitHubConfig := DefaultGitHubConfig()
+		newGitHubConfig.Token = "XXX"
+		newGitHubConfig.Ref = "master"
+		newGitHubConfig.Repository = repository
+		if _, err := NewGitHub(newGitHubConfig); !IsInvalidConfig(err) {
+			t.Fatal("expected", "invalid config error", "got", err)
+		}
+	}
+
+	newGitHubConfig := DefaultGitHubConfig()
+	newGitHubConfig.Repository = "giantswarm/inago"
+	newGitHubConfig.Ref = "master"
+	if _, err := NewGitHub(newGitHubConfig); !IsInvalidConfig(err) {
+		t.Fatal("expected", "invalid config error", "got", err)
+	}
+}
@@ -0,0 +1,65 @@
+// Package notify implements notifiers announcing the lifecycle of operations
+// executed against groups, e.g. in a chat channel, so that a team sees when
+// deployments start, succeed or fail.
+package notify
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// Phase describes where in its lifecycle an operation is.
+type Phase string
+
+const (
+	// PhaseStarted is the phase of an operation that just started.
+	PhaseStarted Phase = "started"
+
+	// PhaseSucceeded is the phase of an operation that finished successfully.
+	PhaseSucceeded Phase = "succeeded"
+
+	// PhaseFailed is the phase of an operation that failed.
+	PhaseFailed Phase = "failed"
+)
+
+// Message describes a phase of an operation.
+type Message struct {
+	// Operation is the operation being executed, e.g. "up".
+	Operation string
+
+	// Args are the arguments of the operation, e.g. the group and scale.
+	Args []string
+
+	// Phase is the phase the operation reached.
+	Phase Phase
+
+	// Duration is the time the operation took. It is only set for finished
+	// operations.
+	Duration time.Duration
+}
+
+// Command returns the command line executing the operation, e.g.
+// "inagoctl up myapp 3".
+func (m Message) Command() string {
+	return strings.Join(append([]string{"inagoctl", m.Operation}, m.Args...), " ")
+}
+
+// String returns a human readable description of the message, e.g.
+// "inagoctl up myapp 3 succeeded after 1m2s".
+func (m Message) String() string {
+	s := m.Command() + " " + string(m.Phase)
+	if m.Phase != PhaseStarted {
+		s += " after " + m.Duration.String()
+	}
+
+	return s
+}
+
+// Notifier sends messages about operations to some receiver.
+type Notifier interface {
+	// Notify sends the given message. Notifications are best effort. Callers
+	// usually log errors instead of failing the operation.
+	Notify(ctx context.Context, m Message) error
+}
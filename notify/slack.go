@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// SlackConfig holds configuration for the Slack notifier.
+type SlackConfig struct {
+	// Client is the HTTP client used to post messages.
+	Client *http.Client
+
+	// WebhookURL is the URL of the Slack incoming webhook messages are posted
+	// to.
+	WebhookURL string
+
+	// Channel optionally overrides the channel configured for the webhook,
+	// e.g. "#deployments".
+	Channel string
+
+	// MentionOnFailure is appended to messages of failed operations, e.g.
+	// "<!here>" or "<@U024BE7LH>", to alert people.
+	MentionOnFailure string
+}
+
+// DefaultSlackConfig provides a set of configurations with default values by
+// best effort.
+func DefaultSlackConfig() SlackConfig {
+	return SlackConfig{
+		Client:           &http.Client{Timeout: 10 * time.Second},
+		WebhookURL:       "",
+		Channel:          "",
+		MentionOnFailure: "",
+	}
+}
+
+// NewSlack creates a Notifier posting messages to a Slack incoming webhook.
+//
+//   newSlackConfig := notify.DefaultSlackConfig()
+//   newSlackConfig.WebhookURL = "https://hooks.slack.com/services/T000/B000/XXX"
+//   newSlack, err := notify.NewSlack(newSlackConfig)
+//
+func NewSlack(config SlackConfig) (Notifier, error) {
+	if config.WebhookURL == "" {
+		return nil, maskAnyf(invalidConfigError, "Slack webhook URL must not be empty")
+	}
+
+	newSlack := slack{
+		SlackConfig: config,
+	}
+
+	return newSlack, nil
+}
+
+type slack struct {
+	SlackConfig
+}
+
+// slackPayload is the JSON payload of Slack incoming webhooks.
+type slackPayload struct {
+	Channel string `json:"channel,omitempty"`
+	Text    string `json:"text"`
+}
+
+var slackPhaseIcons = map[Phase]string{
+	PhaseStarted:   ":rocket:",
+	PhaseSucceeded: ":white_check_mark:",
+	PhaseFailed:    ":x:",
+}
+
+func (s slack) Notify(ctx context.Context, m Message) error {
+	b, err := json.Marshal(slackPayload{
+		Channel: s.Channel,
+		Text:    slackText(m, s.MentionOnFailure),
+	})
+	if err != nil {
+		return maskAny(err)
+	}
+
+	resp, err := s.Client.Post(s.WebhookURL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return maskAny(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return maskAnyf(notificationFailedError, "Slack answered %s: %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+// slackText formats the given message for Slack. The command is formatted as
+// code, and the given mention is added to messages of failed operations.
+func slackText(m Message, mention string) string {
+	text := slackPhaseIcons[m.Phase] + " `" + m.Command() + "` " + string(m.Phase)
+	if m.Phase != PhaseStarted {
+		text += " after " + m.Duration.String()
+	}
+	if m.Phase == PhaseFailed && mention != "" {
+		text += " " + mention
+	}
+
+	return text
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func Test_Slack_Notify(t *testing.T) {
+	var payloads []slackPayload
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload slackPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		payloads = append(payloads, payload)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	newSlackConfig := DefaultSlackConfig()
+	newSlackConfig.WebhookURL = server.URL
+	newSlackConfig.Channel = "#deployments"
+	newSlackConfig.MentionOnFailure = "<!here>"
+	newSlack, err := NewSlack(newSlackConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	messages := []Message{
+		{Operation: "up", Args: []string{"myapp", "3"}, Phase: PhaseStarted},
+		{Operation: "up", Args: []string{"myapp", "3"}, Phase: PhaseSucceeded, Duration: 62 * time.Second},
+		{Operation: "update", Args: []string{"myapp"}, Phase: PhaseFailed, Duration: 3 * time.Second},
+	}
+	for _, m := range messages {
+		if err := newSlack.Notify(context.Background(), m); err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	expected := []slackPayload{
+		{Channel: "#deployments", Text: ":rocket: `inagoctl up myapp 3` started"},
+		{Channel: "#deployments", Text: ":white_check_mark: `inagoctl up myapp 3` succeeded after 1m2s"},
+		{Channel: "#deployments", Text: ":x: `inagoctl update myapp` failed after 3s <!here>"},
+	}
+	if len(payloads) != len(expected) {
+		t.Fatal("expected", expected, "got", payloads)
+	}
+	for i := range expected {
+		if payloads[i] != expected[i] {
+			t.Fatal("expected", expected[i], "got", payloads[i])
+		}
+	}
+
+	status = http.StatusNotFound
+	if err := newSlack.Notify(context.Background(), messages[0]); !IsNotificationFailed(err) {
+		t.Fatal("expected", "notification failed error", "got", err)
+	}
+}
+
+func Test_NewSlack_InvalidConfig(t *testing.T) {
+	if _, err := NewSlack(DefaultSlackConfig()); !IsInvalidConfig(err) {
+		t.Fatal("expected", "invalid config error", "got", err)
+	}
+}
@@ -0,0 +1,47 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/juju/errgo"
+)
+
+var (
+	maskAny = errgo.MaskFunc(errgo.Any)
+)
+
+func maskAnyf(err error, f string, v ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	f = fmt.Sprintf("%s: %s", err.Error(), f)
+	newErr := errgo.WithCausef(nil, errgo.Cause(err), f, v...)
+	newErr.(*errgo.Err).SetLocation(1)
+
+	return newErr
+}
+
+var invalidPolicyError = errgo.New("invalid policy")
+
+// IsInvalidPolicy checks whether the given error indicates that a policy could
+// not be parsed.
+func IsInvalidPolicy(err error) bool {
+	return errgo.Cause(err) == invalidPolicyError
+}
+
+var policyViolationError = errgo.New("policy violation")
+
+// IsPolicyViolation checks whether the given error indicates that an operation
+// was forbidden by a policy.
+func IsPolicyViolation(err error) bool {
+	return errgo.Cause(err) == policyViolationError
+}
+
+var frozenError = errgo.New("frozen")
+
+// IsFrozen checks whether the given error indicates that an operation was
+// forbidden by a freeze.
+func IsFrozen(err error) bool {
+	return errgo.Cause(err) == frozenError
+}
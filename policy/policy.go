@@ -0,0 +1,227 @@
+// Package policy implements rules restricting the operations Inago is allowed
+// to execute against groups. Policies are defined in a simple JSON rules file.
+//
+//   {
+//     "rules": [
+//       { "group": "prod-*", "forbid": ["destroy"], "require-signature": true },
+//       { "group": "*", "max-scale": 10 }
+//     ],
+//     "freezes": [
+//       { "group": "prod-*", "start": "2016-12-23T00:00:00Z", "end": "2017-01-02T00:00:00Z", "reason": "holidays" }
+//     ]
+//   }
+//
+package policy
+
+import (
+	"encoding/json"
+	"path"
+	"time"
+)
+
+// Operation represents an operation executed against a group.
+type Operation string
+
+const (
+	// Submit represents submitting a group.
+	Submit Operation = "submit"
+
+	// Start represents starting a group.
+	Start Operation = "start"
+
+	// Stop represents stopping a group.
+	Stop Operation = "stop"
+
+	// Destroy represents destroying a group.
+	Destroy Operation = "destroy"
+
+	// Update represents updating a group.
+	Update Operation = "update"
+)
+
+var operations = []Operation{Submit, Start, Stop, Destroy, Update}
+
+// Rule restricts the operations allowed against all groups matching the
+// rule's group pattern.
+type Rule struct {
+	// Group is a pattern matched against group names, e.g. "prod-*". The
+	// pattern syntax is the one of path.Match.
+	Group string `json:"group"`
+
+	// Forbid contains all operations not allowed against matching groups.
+	Forbid []Operation `json:"forbid,omitempty"`
+
+	// MaxScale is the maximum number of slices matching groups are allowed to
+	// have. Zero means there is no limit.
+	MaxScale int `json:"max-scale,omitempty"`
+
+	// RequireSignature defines whether matching groups are only allowed to be
+	// submitted and updated in case their definition carries a verified
+	// signature.
+	RequireSignature bool `json:"require-signature,omitempty"`
+}
+
+// Freeze forbids all operations against matching groups within a time window,
+// e.g. during a change-freeze period.
+type Freeze struct {
+	// Group is a pattern matched against group names, e.g. "prod-*". The
+	// pattern syntax is the one of path.Match. An empty pattern matches all
+	// groups.
+	Group string `json:"group,omitempty"`
+
+	// Start is the time the freeze starts.
+	Start time.Time `json:"start"`
+
+	// End is the time the freeze ends.
+	End time.Time `json:"end"`
+
+	// Reason tells why groups are frozen, e.g. "holidays".
+	Reason string `json:"reason,omitempty"`
+}
+
+// Policy is a set of rules and freezes. All rules and freezes matching a group
+// are applied.
+type Policy struct {
+	Rules   []Rule   `json:"rules"`
+	Freezes []Freeze `json:"freezes,omitempty"`
+}
+
+// Parse parses the given JSON encoded policy. In case the policy is malformed,
+// an error that you can identify using IsInvalidPolicy is returned.
+func Parse(b []byte) (Policy, error) {
+	var p Policy
+	if err := json.Unmarshal(b, &p); err != nil {
+		return Policy{}, maskAnyf(invalidPolicyError, "%s", err.Error())
+	}
+
+	for _, r := range p.Rules {
+		if _, err := path.Match(r.Group, ""); err != nil {
+			return Policy{}, maskAnyf(invalidPolicyError, "bad group pattern '%s'", r.Group)
+		}
+		if r.MaxScale < 0 {
+			return Policy{}, maskAnyf(invalidPolicyError, "negative max scale for group pattern '%s'", r.Group)
+		}
+		for _, op := range r.Forbid {
+			if !isOperation(op) {
+				return Policy{}, maskAnyf(invalidPolicyError, "unknown operation '%s'", op)
+			}
+		}
+	}
+
+	for _, f := range p.Freezes {
+		if _, err := path.Match(f.Group, ""); err != nil {
+			return Policy{}, maskAnyf(invalidPolicyError, "bad group pattern '%s'", f.Group)
+		}
+		if f.Start.IsZero() || f.End.IsZero() {
+			return Policy{}, maskAnyf(invalidPolicyError, "freeze for group pattern '%s' must have a start and an end", f.Group)
+		}
+		if !f.End.After(f.Start) {
+			return Policy{}, maskAnyf(invalidPolicyError, "freeze for group pattern '%s' must end after it starts", f.Group)
+		}
+	}
+
+	return p, nil
+}
+
+// CheckFreeze checks whether the given operation against the given group is
+// forbidden by a freeze at the given time. In case it is, an error that you
+// can identify using IsFrozen is returned.
+func (p Policy) CheckFreeze(op Operation, group string, now time.Time) error {
+	for _, f := range p.Freezes {
+		if f.Group != "" {
+			// Patterns are validated by Parse, so errors can be ignored here.
+			if ok, _ := path.Match(f.Group, group); !ok {
+				continue
+			}
+		}
+		if now.Before(f.Start) || !now.Before(f.End) {
+			continue
+		}
+
+		reason := ""
+		if f.Reason != "" {
+			reason = " (" + f.Reason + ")"
+		}
+		return maskAnyf(frozenError, "%s of group '%s' refused, group is frozen until %s%s", op, group, f.End.Format(time.RFC3339), reason)
+	}
+
+	return nil
+}
+
+// CheckOperation checks whether the given operation is allowed against the
+// given group. In case it is not, an error that you can identify using
+// IsPolicyViolation is returned.
+func (p Policy) CheckOperation(op Operation, group string) error {
+	for _, r := range p.matchingRules(group) {
+		for _, forbidden := range r.Forbid {
+			if forbidden == op {
+				return maskAnyf(policyViolationError, "%s of group '%s' forbidden by rule for '%s'", op, group, r.Group)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckScale checks whether the given group is allowed to have the given
+// number of slices. In case it is not, an error that you can identify using
+// IsPolicyViolation is returned.
+func (p Policy) CheckScale(group string, scale int) error {
+	for _, r := range p.matchingRules(group) {
+		if r.MaxScale > 0 && scale > r.MaxScale {
+			return maskAnyf(policyViolationError, "scale %d of group '%s' exceeds maximum of %d defined by rule for '%s'", scale, group, r.MaxScale, r.Group)
+		}
+	}
+
+	return nil
+}
+
+// CheckSignature checks whether the given group is allowed to be submitted or
+// updated, given whether its definition carries a verified signature. In case
+// it is not, an error that you can identify using IsPolicyViolation is
+// returned.
+func (p Policy) CheckSignature(group string, signed bool) error {
+	if signed {
+		return nil
+	}
+	for _, r := range p.matchingRules(group) {
+		if r.RequireSignature {
+			return maskAnyf(policyViolationError, "group '%s' must be signed according to rule for '%s'", group, r.Group)
+		}
+	}
+
+	return nil
+}
+
+// LimitsScale checks whether any rule limits the scale of the given group.
+func (p Policy) LimitsScale(group string) bool {
+	for _, r := range p.matchingRules(group) {
+		if r.MaxScale > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p Policy) matchingRules(group string) []Rule {
+	var rules []Rule
+	for _, r := range p.Rules {
+		// Patterns are validated by Parse, so errors can be ignored here.
+		if ok, _ := path.Match(r.Group, group); ok {
+			rules = append(rules, r)
+		}
+	}
+
+	return rules
+}
+
+func isOperation(op Operation) bool {
+	for _, o := range operations {
+		if o == op {
+			return true
+		}
+	}
+
+	return false
+}
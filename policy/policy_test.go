@@ -0,0 +1,159 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Policy_Parse(t *testing.T) {
+	testCases := []struct {
+		Input        string
+		ErrorMatcher func(err error) bool
+	}{
+		{
+			Input:        `{"rules": [{"group": "prod-*", "forbid": ["destroy", "stop"]}, {"group": "*", "max-scale": 3}]}`,
+			ErrorMatcher: nil,
+		},
+		{
+			Input:        `{}`,
+			ErrorMatcher: nil,
+		},
+		{
+			Input:        `not json`,
+			ErrorMatcher: IsInvalidPolicy,
+		},
+		{
+			Input:        `{"rules": [{"group": "prod-[", "forbid": ["destroy"]}]}`,
+			ErrorMatcher: IsInvalidPolicy,
+		},
+		{
+			Input:        `{"rules": [{"group": "prod-*", "forbid": ["explode"]}]}`,
+			ErrorMatcher: IsInvalidPolicy,
+		},
+		{
+			Input:        `{"rules": [{"group": "prod-*", "max-scale": -1}]}`,
+			ErrorMatcher: IsInvalidPolicy,
+		},
+		{
+			Input:        `{"freezes": [{"group": "prod-*", "start": "2016-12-23T00:00:00Z", "end": "2017-01-02T00:00:00Z", "reason": "holidays"}]}`,
+			ErrorMatcher: nil,
+		},
+		{
+			Input:        `{"freezes": [{"start": "2016-12-23T00:00:00Z"}]}`,
+			ErrorMatcher: IsInvalidPolicy,
+		},
+		{
+			Input:        `{"freezes": [{"start": "2017-01-02T00:00:00Z", "end": "2016-12-23T00:00:00Z"}]}`,
+			ErrorMatcher: IsInvalidPolicy,
+		},
+		{
+			Input:        `{"freezes": [{"group": "prod-[", "start": "2016-12-23T00:00:00Z", "end": "2017-01-02T00:00:00Z"}]}`,
+			ErrorMatcher: IsInvalidPolicy,
+		},
+	}
+
+	for i, testCase := range testCases {
+		_, err := Parse([]byte(testCase.Input))
+		if testCase.ErrorMatcher == nil && err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if testCase.ErrorMatcher != nil && !testCase.ErrorMatcher(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+	}
+}
+
+func Test_Policy_Check(t *testing.T) {
+	p, err := Parse([]byte(`{"rules": [{"group": "prod-*", "forbid": ["destroy"], "max-scale": 5}, {"group": "*", "max-scale": 10}]}`))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Operation Operation
+		Group     string
+		Scale     int
+		Violation bool
+	}{
+		{Operation: Destroy, Group: "prod-app", Scale: 1, Violation: true},
+		{Operation: Stop, Group: "prod-app", Scale: 1, Violation: false},
+		{Operation: Destroy, Group: "dev-app", Scale: 1, Violation: false},
+		{Operation: Submit, Group: "prod-app", Scale: 5, Violation: false},
+		{Operation: Submit, Group: "prod-app", Scale: 6, Violation: true},
+		{Operation: Submit, Group: "dev-app", Scale: 10, Violation: false},
+		{Operation: Submit, Group: "dev-app", Scale: 11, Violation: true},
+	}
+
+	for i, testCase := range testCases {
+		err := p.CheckOperation(testCase.Operation, testCase.Group)
+		if err == nil {
+			err = p.CheckScale(testCase.Group, testCase.Scale)
+		}
+		if IsPolicyViolation(err) != testCase.Violation {
+			t.Fatal("case", i+1, "expected", testCase.Violation, "got", err)
+		}
+	}
+
+	if !p.LimitsScale("dev-app") {
+		t.Fatal("expected", true, "got", false)
+	}
+	if (Policy{}).LimitsScale("dev-app") {
+		t.Fatal("expected", false, "got", true)
+	}
+}
+
+func Test_Policy_CheckFreeze(t *testing.T) {
+	p, err := Parse([]byte(`{"freezes": [{"group": "prod-*", "start": "2016-12-23T00:00:00Z", "end": "2017-01-02T00:00:00Z", "reason": "holidays"}]}`))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Group    string
+		Now      time.Time
+		Expected bool
+	}{
+		{Group: "prod-api", Now: time.Date(2016, 12, 24, 12, 0, 0, 0, time.UTC), Expected: true},
+		{Group: "prod-api", Now: time.Date(2016, 12, 23, 0, 0, 0, 0, time.UTC), Expected: true},
+		{Group: "prod-api", Now: time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC), Expected: false},
+		{Group: "prod-api", Now: time.Date(2016, 12, 22, 23, 59, 0, 0, time.UTC), Expected: false},
+		{Group: "dev-api", Now: time.Date(2016, 12, 24, 12, 0, 0, 0, time.UTC), Expected: false},
+	}
+
+	for i, testCase := range testCases {
+		err := p.CheckFreeze(Update, testCase.Group, testCase.Now)
+		if IsFrozen(err) != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", err)
+		}
+	}
+
+	// Freezes without group pattern apply to all groups.
+	p.Freezes[0].Group = ""
+	if err := p.CheckFreeze(Update, "dev-api", time.Date(2016, 12, 24, 12, 0, 0, 0, time.UTC)); !IsFrozen(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
+
+func Test_Policy_CheckSignature(t *testing.T) {
+	p, err := Parse([]byte(`{"rules": [{"group": "prod-*", "require-signature": true}, {"group": "*", "max-scale": 3}]}`))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Group    string
+		Signed   bool
+		Expected bool
+	}{
+		{Group: "prod-api", Signed: false, Expected: true},
+		{Group: "prod-api", Signed: true, Expected: false},
+		{Group: "dev-api", Signed: false, Expected: false},
+	}
+
+	for i, testCase := range testCases {
+		err := p.CheckSignature(testCase.Group, testCase.Signed)
+		if IsPolicyViolation(err) != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package registry
+
+import (
+	"fmt"
+
+	"github.com/juju/errgo"
+)
+
+var (
+	maskAny = errgo.MaskFunc(errgo.Any)
+)
+
+func maskAnyf(err error, f string, v ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	f = fmt.Sprintf("%s: %s", err.Error(), f)
+	newErr := errgo.WithCausef(nil, errgo.Cause(err), f, v...)
+	newErr.(*errgo.Err).SetLocation(1)
+
+	return newErr
+}
+
+var invalidImageError = errgo.New("invalid image")
+
+// IsInvalidImage checks whether the given error indicates that an image
+// reference cannot be parsed.
+func IsInvalidImage(err error) bool {
+	return errgo.Cause(err) == invalidImageError
+}
+
+var requestFailedError = errgo.New("registry request failed")
+
+// IsRequestFailed checks whether the given error indicates that a registry
+// answered a request using an unexpected status, e.g. because it requires
+// credentials.
+func IsRequestFailed(err error) bool {
+	return errgo.Cause(err) == requestFailedError
+}
@@ -0,0 +1,261 @@
+// Package registry implements a client of the Docker Registry HTTP API V2,
+// which is used to verify that images referenced by unit files exist before
+// they are deployed.
+package registry
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+const (
+	// dockerHubRegistry is the registry of images not naming a registry.
+	dockerHubRegistry = "registry-1.docker.io"
+
+	// manifestMediaTypes are the manifest media types accepted when looking up
+	// an image. Without them, registries may answer with a schema 1 manifest
+	// or not find images pushed using newer clients.
+	manifestMediaTypes = "application/vnd.docker.distribution.manifest.v2+json, " +
+		"application/vnd.docker.distribution.manifest.list.v2+json, " +
+		"application/vnd.oci.image.manifest.v1+json, " +
+		"application/vnd.oci.image.index.v1+json"
+)
+
+// Config holds configuration for the registry client.
+type Config struct {
+	// Client is the HTTP client used to talk to registries.
+	Client *http.Client
+}
+
+// DefaultConfig provides a set of configurations with default values by best
+// effort.
+func DefaultConfig() Config {
+	return Config{
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Registry looks up images in the registries they refer to.
+type Registry interface {
+	// ImageExists checks whether the given image exists in its registry, e.g.
+	// "quay.io/giantswarm/app:1.0", "redis" or "app@sha256:...". Images not
+	// naming a registry are looked up at Docker Hub. Anonymous access is used,
+	// so images of registries requiring credentials cannot be verified. In
+	// that case, an error that you can identify using IsRequestFailed is
+	// returned.
+	ImageExists(ctx context.Context, image string) (bool, error)
+}
+
+// NewRegistry creates a new configured registry client.
+//
+//   newRegistry := registry.NewRegistry(registry.DefaultConfig())
+//   ok, err := newRegistry.ImageExists(ctx, "quay.io/giantswarm/app:1.0")
+//
+func NewRegistry(config Config) Registry {
+	newRegistry := &registry{
+		Config: config,
+	}
+
+	return newRegistry
+}
+
+type registry struct {
+	Config
+}
+
+// Reference is a parsed image reference.
+type Reference struct {
+	// Registry is the host of the registry, e.g. "quay.io".
+	Registry string
+
+	// Repository is the name of the image within the registry, e.g.
+	// "giantswarm/app".
+	Repository string
+
+	// Tag is the tag or digest of the image, e.g. "1.0" or "sha256:...".
+	Tag string
+}
+
+// ParseReference parses the given image reference. The registry defaults to
+// Docker Hub, and the tag to "latest". Official images of Docker Hub are
+// prefixed with "library/".
+//
+//   redis                        =>  registry-1.docker.io library/redis latest
+//   quay.io/giantswarm/app:1.0   =>  quay.io giantswarm/app 1.0
+//   localhost:5000/app@sha256:a  =>  localhost:5000 app sha256:a
+//
+func ParseReference(image string) (Reference, error) {
+	if image == "" || strings.ContainsAny(image, " \t") {
+		return Reference{}, maskAnyf(invalidImageError, "'%s'", image)
+	}
+
+	ref := Reference{Registry: dockerHubRegistry, Tag: "latest"}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+	}
+
+	// The first component is a registry in case it looks like a host.
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.Registry = host
+			name = name[i+1:]
+		}
+	}
+	if ref.Registry == dockerHubRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	if name == "" || ref.Tag == "" || strings.HasSuffix(name, "/") {
+		return Reference{}, maskAnyf(invalidImageError, "'%s'", image)
+	}
+	ref.Repository = name
+
+	return ref, nil
+}
+
+func (r *registry) ImageExists(ctx context.Context, image string) (bool, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return false, maskAny(err)
+	}
+
+	manifestURL := "https://" + ref.Registry + "/v2/" + ref.Repository + "/manifests/" + ref.Tag
+	resp, err := r.head(ctx, manifestURL, "")
+	if err != nil {
+		return false, maskAny(err)
+	}
+
+	// Registries like Docker Hub require a token, even for anonymous access.
+	// The response tells where to get it.
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := r.token(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return false, maskAny(err)
+		}
+		resp, err = r.head(ctx, manifestURL, token)
+		if err != nil {
+			return false, maskAny(err)
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, maskAnyf(requestFailedError, "%s answered %s for image '%s'", ref.Registry, resp.Status, image)
+	}
+}
+
+// head requests the given URL using the HEAD method, authorized using the
+// given bearer token unless it is empty.
+func (r *registry) head(ctx context.Context, u, token string) (*http.Response, error) {
+	req, err := http.NewRequest("HEAD", u, nil)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	req.Header.Set("Accept", manifestMediaTypes)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := ctxhttp.Do(ctx, r.Client, req)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	resp.Body.Close()
+
+	return resp, nil
+}
+
+// token fetches an anonymous bearer token as described by the given
+// WWW-Authenticate header.
+//
+//   Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/redis:pull"
+//
+func (r *registry) token(ctx context.Context, challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", maskAnyf(requestFailedError, "unsupported authentication '%s'", challenge)
+	}
+	params := parseChallenge(strings.TrimPrefix(challenge, "Bearer "))
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", maskAnyf(requestFailedError, "invalid authentication realm '%s'", params["realm"])
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := params[key]; ok {
+			query.Set(key, value)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := ctxhttp.Get(ctx, r.Client, realm.String())
+	if err != nil {
+		return "", maskAny(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", maskAnyf(requestFailedError, "%s answered %s", realm.Host, resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", maskAny(err)
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	if body.Token != "" {
+		return body.Token, nil
+	}
+
+	return body.AccessToken, nil
+}
+
+// parseChallenge parses the comma separated key="value" pairs of a
+// WWW-Authenticate header.
+func parseChallenge(s string) map[string]string {
+	params := map[string]string{}
+	for len(s) > 0 {
+		i := strings.Index(s, "=")
+		if i < 0 {
+			break
+		}
+		key := strings.TrimSpace(s[:i])
+		s = s[i+1:]
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := strings.Index(s[1:], `"`)
+			if end < 0 {
+				value, s = s[1:], ""
+			} else {
+				value, s = s[1:end+1], s[end+2:]
+			}
+		} else if end := strings.Index(s, ","); end >= 0 {
+			value, s = s[:end], s[end:]
+		} else {
+			value, s = s, ""
+		}
+		params[key] = value
+		s = strings.TrimPrefix(strings.TrimSpace(s), ",")
+	}
+
+	return params
+}
@@ -0,0 +1,87 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+func Test_Registry_ParseReference(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []struct {
+		Image    string
+		Expected Reference
+	}{
+		{"redis", Reference{Registry: dockerHubRegistry, Repository: "library/redis", Tag: "latest"}},
+		{"giantswarm/app:1.0", Reference{Registry: dockerHubRegistry, Repository: "giantswarm/app", Tag: "1.0"}},
+		{"quay.io/giantswarm/app:1.0", Reference{Registry: "quay.io", Repository: "giantswarm/app", Tag: "1.0"}},
+		{"localhost:5000/app", Reference{Registry: "localhost:5000", Repository: "app", Tag: "latest"}},
+		{"localhost:5000/app@sha256:abc", Reference{Registry: "localhost:5000", Repository: "app", Tag: "sha256:abc"}},
+	}
+	for _, testCase := range testCases {
+		ref, err := ParseReference(testCase.Image)
+		Expect(err).To(BeNil())
+		Expect(ref).To(Equal(testCase.Expected))
+	}
+
+	for _, image := range []string{"", "app:", "quay.io/", "my app"} {
+		_, err := ParseReference(image)
+		Expect(IsInvalidImage(err)).To(BeTrue(), image)
+	}
+}
+
+func Test_Registry_ImageExists(t *testing.T) {
+	RegisterTestingT(t)
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			Expect(r.URL.Query().Get("scope")).To(Equal("repository:app:pull"))
+			w.Write([]byte(`{"token": "secret"}`))
+		case r.Header.Get("Authorization") != "Bearer secret":
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test",scope="repository:app:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/app/manifests/1.0":
+			Expect(r.Method).To(Equal("HEAD"))
+			Expect(r.Header.Get("Accept")).To(ContainSubstring("manifest.v2+json"))
+		case r.URL.Path == "/v2/app/manifests/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	newConfig := DefaultConfig()
+	newConfig.Client = server.Client()
+	newRegistry := NewRegistry(newConfig)
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	ok, err := newRegistry.ImageExists(context.Background(), host+"/app:1.0")
+	Expect(err).To(BeNil())
+	Expect(ok).To(BeTrue())
+
+	ok, err = newRegistry.ImageExists(context.Background(), host+"/app:1.1")
+	Expect(err).To(BeNil())
+	Expect(ok).To(BeFalse())
+
+	_, err = newRegistry.ImageExists(context.Background(), host+"/app:broken")
+	Expect(IsRequestFailed(err)).To(BeTrue())
+}
+
+func Test_Registry_parseChallenge(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(parseChallenge(`realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/redis:pull,push"`)).To(Equal(map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/redis:pull,push",
+	}))
+	Expect(parseChallenge(`realm=https://auth,service=x`)).To(Equal(map[string]string{"realm": "https://auth", "service": "x"}))
+}
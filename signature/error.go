@@ -0,0 +1,39 @@
+package signature
+
+import (
+	"fmt"
+
+	"github.com/juju/errgo"
+)
+
+var (
+	maskAny = errgo.MaskFunc(errgo.Any)
+)
+
+func maskAnyf(err error, f string, v ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	f = fmt.Sprintf("%s: %s", err.Error(), f)
+	newErr := errgo.WithCausef(nil, errgo.Cause(err), f, v...)
+	newErr.(*errgo.Err).SetLocation(1)
+
+	return newErr
+}
+
+var invalidKeyError = errgo.New("invalid key")
+
+// IsInvalidKey checks whether the given error indicates that a key could not
+// be parsed, or is of an unsupported type.
+func IsInvalidKey(err error) bool {
+	return errgo.Cause(err) == invalidKeyError
+}
+
+var invalidSignatureError = errgo.New("invalid signature")
+
+// IsInvalidSignature checks whether the given error indicates that a signature
+// is malformed, or does not match the signed files.
+func IsInvalidSignature(err error) bool {
+	return errgo.Cause(err) == invalidSignatureError
+}
@@ -0,0 +1,159 @@
+// Package signature implements signing and verifying group definitions, so
+// that clusters can be restricted to deploy groups signed by trusted keys.
+// The files of a group are signed as a whole. Keys are PEM encoded RSA or
+// ECDSA keys, as e.g. created using openssl.
+//
+//   openssl ecparam -name prime256v1 -genkey -noout -out inago.key
+//   openssl ec -in inago.key -pubout -out inago.pub
+//
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Digest returns the SHA-256 digest of the given files, keyed by their names.
+// Names and contents are both covered, so renaming a file changes the digest
+// as well. The order the files are given in does not matter.
+func Digest(files map[string][]byte) []byte {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s\n%d\n", name, len(files[name]))
+		h.Write(files[name])
+	}
+
+	return h.Sum(nil)
+}
+
+// ParsePrivateKey parses the given PEM encoded RSA or ECDSA private key. In
+// case the key cannot be parsed, an error that you can identify using
+// IsInvalidKey is returned.
+func ParsePrivateKey(raw []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, maskAnyf(invalidKeyError, "no PEM data found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, maskAnyf(invalidKeyError, "%s", err.Error())
+		}
+		return key, nil
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, maskAnyf(invalidKeyError, "%s", err.Error())
+		}
+		return key, nil
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, maskAnyf(invalidKeyError, "%s", err.Error())
+		}
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			return key, nil
+		case *ecdsa.PrivateKey:
+			return key, nil
+		}
+		return nil, maskAnyf(invalidKeyError, "unsupported private key type %T", key)
+	}
+
+	return nil, maskAnyf(invalidKeyError, "unsupported PEM block '%s'", block.Type)
+}
+
+// ParsePublicKey parses the given PEM encoded RSA or ECDSA public key. In case
+// the key cannot be parsed, an error that you can identify using IsInvalidKey
+// is returned.
+func ParsePublicKey(raw []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, maskAnyf(invalidKeyError, "no PEM data found")
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, maskAnyf(invalidKeyError, "unsupported PEM block '%s'", block.Type)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, maskAnyf(invalidKeyError, "%s", err.Error())
+	}
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return key, nil
+	case *ecdsa.PublicKey:
+		return key, nil
+	}
+
+	return nil, maskAnyf(invalidKeyError, "unsupported public key type %T", key)
+}
+
+// Sign signs the given files using the given private key. The returned
+// signature is base64 encoded, so it can be stored as text file next to the
+// signed files.
+func Sign(key crypto.Signer, files map[string][]byte) ([]byte, error) {
+	raw, err := key.Sign(rand.Reader, Digest(files), crypto.SHA256)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(raw) + "\n"), nil
+}
+
+// ecdsaSignature is the ASN.1 structure of ECDSA signatures.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// Verify verifies the given signature, as created by Sign, of the given files
+// using the given public key. In case the signature is malformed, or the
+// files have not been signed using the private key belonging to the public
+// key, an error that you can identify using IsInvalidSignature is returned.
+func Verify(key crypto.PublicKey, files map[string][]byte, signature []byte) error {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return maskAnyf(invalidSignatureError, "%s", err.Error())
+	}
+	digest := Digest(files)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, raw); err != nil {
+			return maskAnyf(invalidSignatureError, "%s", err.Error())
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		var sig ecdsaSignature
+		if rest, err := asn1.Unmarshal(raw, &sig); err != nil {
+			return maskAnyf(invalidSignatureError, "%s", err.Error())
+		} else if len(rest) != 0 {
+			return maskAnyf(invalidSignatureError, "trailing data after signature")
+		}
+		if !ecdsa.Verify(key, digest, sig.R, sig.S) {
+			return maskAnyf(invalidSignatureError, "verification failed")
+		}
+		return nil
+	}
+
+	return maskAnyf(invalidKeyError, "unsupported public key type %T", key)
+}
@@ -0,0 +1,125 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func testKeyPair(t *testing.T, ec bool) ([]byte, []byte) {
+	var private crypto.Signer
+	var block *pem.Block
+	if ec {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, block = key, &pem.Block{Type: "EC PRIVATE KEY", Bytes: raw}
+	} else {
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, block = key, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	}
+
+	raw, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(block), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: raw})
+}
+
+func Test_Signature_Digest(t *testing.T) {
+	a := Digest(map[string][]byte{"a": []byte("foo"), "b": []byte("bar")})
+	b := Digest(map[string][]byte{"b": []byte("bar"), "a": []byte("foo")})
+	if string(a) != string(b) {
+		t.Fatal("expected digest to not depend on the order of files")
+	}
+
+	// Moving content between files changes the digest.
+	c := Digest(map[string][]byte{"a": []byte("foob"), "b": []byte("ar")})
+	if string(a) == string(c) {
+		t.Fatal("expected digest to change when content moves between files")
+	}
+	d := Digest(map[string][]byte{"c": []byte("foo"), "b": []byte("bar")})
+	if string(a) == string(d) {
+		t.Fatal("expected digest to change when files are renamed")
+	}
+}
+
+func Test_Signature_SignVerify(t *testing.T) {
+	files := map[string][]byte{
+		"mygroup-foo@.service": []byte("[Service]\nExecStart=/bin/foo\n"),
+		"group.json":           []byte(`{"version": "1.0.0"}`),
+	}
+
+	for _, ec := range []bool{true, false} {
+		rawPrivate, rawPublic := testKeyPair(t, ec)
+		private, err := ParsePrivateKey(rawPrivate)
+		if err != nil {
+			t.Fatal("ec", ec, "expected", nil, "got", err)
+		}
+		public, err := ParsePublicKey(rawPublic)
+		if err != nil {
+			t.Fatal("ec", ec, "expected", nil, "got", err)
+		}
+
+		sig, err := Sign(private, files)
+		if err != nil {
+			t.Fatal("ec", ec, "expected", nil, "got", err)
+		}
+		if err := Verify(public, files, sig); err != nil {
+			t.Fatal("ec", ec, "expected", nil, "got", err)
+		}
+
+		changed := map[string][]byte{
+			"mygroup-foo@.service": []byte("[Service]\nExecStart=/bin/evil\n"),
+			"group.json":           files["group.json"],
+		}
+		if err := Verify(public, changed, sig); !IsInvalidSignature(err) {
+			t.Fatal("ec", ec, "expected", invalidSignatureError, "got", err)
+		}
+
+		_, otherPublic := testKeyPair(t, ec)
+		other, err := ParsePublicKey(otherPublic)
+		if err != nil {
+			t.Fatal("ec", ec, "expected", nil, "got", err)
+		}
+		if err := Verify(other, files, sig); !IsInvalidSignature(err) {
+			t.Fatal("ec", ec, "expected", invalidSignatureError, "got", err)
+		}
+	}
+}
+
+func Test_Signature_invalidInput(t *testing.T) {
+	rawPrivate, rawPublic := testKeyPair(t, true)
+
+	if _, err := ParsePrivateKey([]byte("not a key")); !IsInvalidKey(err) {
+		t.Fatal("expected", invalidKeyError, "got", err)
+	}
+	if _, err := ParsePrivateKey(rawPublic); !IsInvalidKey(err) {
+		t.Fatal("expected", invalidKeyError, "got", err)
+	}
+	if _, err := ParsePublicKey(rawPrivate); !IsInvalidKey(err) {
+		t.Fatal("expected", invalidKeyError, "got", err)
+	}
+
+	public, err := ParsePublicKey(rawPublic)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if err := Verify(public, nil, []byte("not base64!")); !IsInvalidSignature(err) {
+		t.Fatal("expected", invalidSignatureError, "got", err)
+	}
+}
@@ -0,0 +1,39 @@
+package stack
+
+import (
+	"fmt"
+
+	"github.com/juju/errgo"
+)
+
+var (
+	maskAny = errgo.MaskFunc(errgo.Any)
+)
+
+func maskAnyf(err error, f string, v ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	f = fmt.Sprintf("%s: %s", err.Error(), f)
+	newErr := errgo.WithCausef(nil, errgo.Cause(err), f, v...)
+	newErr.(*errgo.Err).SetLocation(1)
+
+	return newErr
+}
+
+var invalidStackError = errgo.New("invalid stack")
+
+// IsInvalidStack checks whether the given error indicates that a stack could
+// not be parsed or describes groups inconsistently.
+func IsInvalidStack(err error) bool {
+	return errgo.Cause(err) == invalidStackError
+}
+
+var dependencyCycleError = errgo.New("dependency cycle")
+
+// IsDependencyCycle checks whether the given error indicates that groups of a
+// stack depend on each other.
+func IsDependencyCycle(err error) bool {
+	return errgo.Cause(err) == dependencyCycleError
+}
@@ -0,0 +1,153 @@
+// Package stack implements stack manifests describing multiple groups that
+// are brought up and down together, respecting their dependencies.
+//
+//   groups:
+//   - name: db
+//     scale: 1
+//   - name: api
+//     scale: 3
+//     depends-on: [db]
+//   - name: worker
+//     scale: 2
+//     depends-on: [db, api]
+//
+package stack
+
+import (
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultScale is the number of slices of groups not defining a scale.
+const DefaultScale = 1
+
+// Stack describes a set of groups.
+type Stack struct {
+	// Groups are the groups of the stack.
+	Groups []Group `yaml:"groups"`
+}
+
+// Group describes a single group of a stack.
+type Group struct {
+	// Name is the name of the group. It refers to a group directory located next
+	// to the stack file.
+	Name string `yaml:"name"`
+
+	// Scale is the number of slices of the group. It defaults to DefaultScale.
+	// It is ignored for groups without slices.
+	Scale int `yaml:"scale"`
+
+	// DependsOn are the names of the groups that need to be up before this
+	// group is brought up, and that are brought down only after this group.
+	DependsOn []string `yaml:"depends-on"`
+}
+
+// Parse parses the given YAML encoded stack. In case the stack is malformed,
+// an error that you can identify using IsInvalidStack is returned. In case
+// groups of the stack depend on each other, an error that you can identify
+// using IsDependencyCycle is returned.
+func Parse(b []byte) (Stack, error) {
+	var s Stack
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return Stack{}, maskAnyf(invalidStackError, "%s", err.Error())
+	}
+	if len(s.Groups) == 0 {
+		return Stack{}, maskAnyf(invalidStackError, "no groups defined")
+	}
+
+	names := map[string]struct{}{}
+	for i, g := range s.Groups {
+		if g.Name == "" || strings.ContainsAny(g.Name, "@/") {
+			return Stack{}, maskAnyf(invalidStackError, "bad group name '%s'", g.Name)
+		}
+		if _, ok := names[g.Name]; ok {
+			return Stack{}, maskAnyf(invalidStackError, "group '%s' defined twice", g.Name)
+		}
+		names[g.Name] = struct{}{}
+
+		if g.Scale < 0 {
+			return Stack{}, maskAnyf(invalidStackError, "negative scale for group '%s'", g.Name)
+		}
+		if g.Scale == 0 {
+			s.Groups[i].Scale = DefaultScale
+		}
+	}
+	for _, g := range s.Groups {
+		for _, d := range g.DependsOn {
+			if _, ok := names[d]; !ok {
+				return Stack{}, maskAnyf(invalidStackError, "group '%s' depends on unknown group '%s'", g.Name, d)
+			}
+		}
+	}
+
+	if _, err := s.Levels(); err != nil {
+		return Stack{}, maskAny(err)
+	}
+
+	return s, nil
+}
+
+// Group returns the group of the stack having the given name.
+func (s Stack) Group(name string) (Group, bool) {
+	for _, g := range s.Groups {
+		if g.Name == name {
+			return g, true
+		}
+	}
+
+	return Group{}, false
+}
+
+// Levels returns the names of the groups of the stack in dependency order.
+// Groups of the same level do not depend on each other and can be brought up
+// at the same time, once all groups of the previous levels are up. Names
+// within a level are sorted. In case groups depend on each other, an error
+// that you can identify using IsDependencyCycle is returned.
+//
+//   db, api -> db, worker -> [db, api]  =>  [[db] [api] [worker]]
+//   db, cache, api -> [db, cache]       =>  [[cache db] [api]]
+//
+func (s Stack) Levels() ([][]string, error) {
+	pending := map[string][]string{}
+	for _, g := range s.Groups {
+		pending[g.Name] = g.DependsOn
+	}
+
+	done := map[string]struct{}{}
+	var levels [][]string
+	for len(pending) > 0 {
+		var level []string
+		for name, deps := range pending {
+			ready := true
+			for _, d := range deps {
+				if _, ok := done[d]; !ok {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				level = append(level, name)
+			}
+		}
+
+		if len(level) == 0 {
+			var cyclic []string
+			for name := range pending {
+				cyclic = append(cyclic, name)
+			}
+			sort.Strings(cyclic)
+			return nil, maskAnyf(dependencyCycleError, "groups %v depend on each other", cyclic)
+		}
+
+		sort.Strings(level)
+		for _, name := range level {
+			delete(pending, name)
+			done[name] = struct{}{}
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
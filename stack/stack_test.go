@@ -0,0 +1,90 @@
+package stack
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Stack_Parse(t *testing.T) {
+	testCases := []struct {
+		Input        string
+		ErrorMatcher func(err error) bool
+	}{
+		{
+			Input:        "groups:\n- name: db\n- name: api\n  scale: 3\n  depends-on: [db]\n",
+			ErrorMatcher: nil,
+		},
+		{
+			Input:        "groups: [",
+			ErrorMatcher: IsInvalidStack,
+		},
+		{
+			Input:        "groups: []\n",
+			ErrorMatcher: IsInvalidStack,
+		},
+		{
+			Input:        "groups:\n- name: db\n- name: db\n",
+			ErrorMatcher: IsInvalidStack,
+		},
+		{
+			Input:        "groups:\n- name: db@1\n",
+			ErrorMatcher: IsInvalidStack,
+		},
+		{
+			Input:        "groups:\n- name: db\n  scale: -1\n",
+			ErrorMatcher: IsInvalidStack,
+		},
+		{
+			Input:        "groups:\n- name: api\n  depends-on: [db]\n",
+			ErrorMatcher: IsInvalidStack,
+		},
+		{
+			Input:        "groups:\n- name: db\n  depends-on: [api]\n- name: api\n  depends-on: [db]\n",
+			ErrorMatcher: IsDependencyCycle,
+		},
+	}
+
+	for i, testCase := range testCases {
+		_, err := Parse([]byte(testCase.Input))
+		if testCase.ErrorMatcher == nil && err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if testCase.ErrorMatcher != nil && !testCase.ErrorMatcher(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
+
+func Test_Stack_Levels(t *testing.T) {
+	s, err := Parse([]byte(`
+groups:
+- name: worker
+  scale: 2
+  depends-on: [db, api]
+- name: api
+  scale: 3
+  depends-on: [db]
+- name: db
+- name: cache
+`))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	levels, err := s.Levels()
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	expected := [][]string{{"cache", "db"}, {"api"}, {"worker"}}
+	if !reflect.DeepEqual(levels, expected) {
+		t.Fatal("expected", expected, "got", levels)
+	}
+
+	g, ok := s.Group("db")
+	if !ok {
+		t.Fatal("expected", true, "got", false)
+	}
+	if g.Scale != DefaultScale {
+		t.Fatal("expected", DefaultScale, "got", g.Scale)
+	}
+}
@@ -0,0 +1,39 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errgo"
+)
+
+var (
+	maskAny = errgo.MaskFunc(errgo.Any)
+)
+
+func maskAnyf(err error, f string, v ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	f = fmt.Sprintf("%s: %s", err.Error(), f)
+	newErr := errgo.WithCausef(nil, errgo.Cause(err), f, v...)
+	newErr.(*errgo.Err).SetLocation(1)
+
+	return newErr
+}
+
+var notFoundError = errgo.New("not found")
+
+// IsNotFound checks whether the given error indicates that no value is stored
+// under the requested name.
+func IsNotFound(err error) bool {
+	return errgo.Cause(err) == notFoundError
+}
+
+var invalidKeyError = errgo.New("invalid key")
+
+// IsInvalidKey checks whether the given error indicates that a namespace or
+// name cannot be used to store values.
+func IsInvalidKey(err error) bool {
+	return errgo.Cause(err) == invalidKeyError
+}
@@ -0,0 +1,128 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/inago/file-system/real"
+	"github.com/giantswarm/inago/file-system/spec"
+)
+
+// fileExtension is the extension of files containing stored values.
+const fileExtension = ".json"
+
+// FileStoreConfig provides all necessary and injectable configurations for a
+// new file store.
+type FileStoreConfig struct {
+	// Dependencies.
+
+	FileSystem filesystemspec.FileSystem
+
+	// Settings.
+
+	// Dir is the directory values are stored in. Each namespace is a sub
+	// directory containing a JSON file for each value.
+	Dir string
+}
+
+// DefaultFileStoreConfig provides a set of configurations with default values
+// by best effort.
+func DefaultFileStoreConfig() FileStoreConfig {
+	newConfig := FileStoreConfig{
+		FileSystem: filesystemreal.NewFileSystem(),
+		Dir:        filepath.Join(os.Getenv("HOME"), ".inago", "state"),
+	}
+
+	return newConfig
+}
+
+// NewFileStore creates a Store persisting values as JSON files.
+//
+//   <dir>/history/mygroup.json
+//
+func NewFileStore(config FileStoreConfig) Store {
+	newStore := &fileStore{
+		FileStoreConfig: config,
+	}
+
+	return newStore
+}
+
+type fileStore struct {
+	FileStoreConfig
+}
+
+func (fs *fileStore) Get(namespace, name string, v interface{}) error {
+	if err := validateKey(namespace, name); err != nil {
+		return maskAny(err)
+	}
+
+	// The file system interface does not tell apart missing files from other
+	// errors. So we look up the file first.
+	names, err := fs.List(namespace)
+	if err != nil {
+		return maskAny(err)
+	}
+	i := sort.SearchStrings(names, name)
+	if i == len(names) || names[i] != name {
+		return maskAnyf(notFoundError, "%s/%s", namespace, name)
+	}
+
+	raw, err := fs.FileSystem.ReadFile(fs.path(namespace, name))
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (fs *fileStore) Set(namespace, name string, v interface{}) error {
+	if err := validateKey(namespace, name); err != nil {
+		return maskAny(err)
+	}
+
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return maskAny(err)
+	}
+	err = fs.FileSystem.WriteFile(fs.path(namespace, name), append(raw, '\n'), os.FileMode(0600))
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (fs *fileStore) List(namespace string) ([]string, error) {
+	if err := validateKeyPart(namespace); err != nil {
+		return nil, maskAny(err)
+	}
+
+	fileInfos, err := fs.FileSystem.ReadDir(filepath.Join(fs.Dir, namespace))
+	if err != nil {
+		// Namespaces are created on demand. Nothing has been stored in case the
+		// namespace cannot be read.
+		return nil, nil
+	}
+
+	var names []string
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() || !strings.HasSuffix(fileInfo.Name(), fileExtension) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(fileInfo.Name(), fileExtension))
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (fs *fileStore) path(namespace, name string) string {
+	return filepath.Join(fs.Dir, namespace, name+fileExtension)
+}
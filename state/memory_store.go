@@ -0,0 +1,82 @@
+package state
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// NewMemoryStore creates a Store keeping values in memory. This is useful for
+// testing.
+func NewMemoryStore() Store {
+	newStore := &memoryStore{
+		Mutex:   sync.Mutex{},
+		Storage: map[string]map[string][]byte{},
+	}
+
+	return newStore
+}
+
+type memoryStore struct {
+	Mutex   sync.Mutex
+	Storage map[string]map[string][]byte
+}
+
+func (ms *memoryStore) Get(namespace, name string, v interface{}) error {
+	if err := validateKey(namespace, name); err != nil {
+		return maskAny(err)
+	}
+
+	ms.Mutex.Lock()
+	defer ms.Mutex.Unlock()
+
+	raw, ok := ms.Storage[namespace][name]
+	if !ok {
+		return maskAnyf(notFoundError, "%s/%s", namespace, name)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (ms *memoryStore) Set(namespace, name string, v interface{}) error {
+	if err := validateKey(namespace, name); err != nil {
+		return maskAny(err)
+	}
+
+	// Values are encoded like in other stores, so that callers cannot modify
+	// stored values and encoding problems show up in tests.
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	ms.Mutex.Lock()
+	defer ms.Mutex.Unlock()
+
+	if _, ok := ms.Storage[namespace]; !ok {
+		ms.Storage[namespace] = map[string][]byte{}
+	}
+	ms.Storage[namespace][name] = raw
+
+	return nil
+}
+
+func (ms *memoryStore) List(namespace string) ([]string, error) {
+	if err := validateKeyPart(namespace); err != nil {
+		return nil, maskAny(err)
+	}
+
+	ms.Mutex.Lock()
+	defer ms.Mutex.Unlock()
+
+	var names []string
+	for name := range ms.Storage[namespace] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
@@ -0,0 +1,47 @@
+// Package state provides a store persisting state of Inago across invocations
+// of inagoctl, e.g. the status history of groups. Values are organized in
+// namespaces, e.g. "history", and identified by names, e.g. group names.
+package state
+
+// Store represents some storage solution to persist state. Values are JSON
+// encoded.
+type Store interface {
+	// Get decodes the value stored under the given name within the given
+	// namespace into v. In case no value is stored, an error that you can
+	// identify using IsNotFound is returned.
+	Get(namespace, name string, v interface{}) error
+
+	// Set stores the given value under the given name within the given
+	// namespace. Existing values are overwritten.
+	Set(namespace, name string, v interface{}) error
+
+	// List returns the sorted names of all values stored within the given
+	// namespace.
+	List(namespace string) ([]string, error)
+}
+
+// validateKey checks whether the given namespace and name can be used to
+// store values.
+func validateKey(namespace, name string) error {
+	if err := validateKeyPart(namespace); err != nil {
+		return maskAny(err)
+	}
+	if err := validateKeyPart(name); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func validateKeyPart(part string) error {
+	if part == "" || part == "." || part == ".." {
+		return maskAnyf(invalidKeyError, "'%s'", part)
+	}
+	for _, r := range part {
+		if r == '/' || r == '\\' {
+			return maskAnyf(invalidKeyError, "'%s' must not contain path separators", part)
+		}
+	}
+
+	return nil
+}
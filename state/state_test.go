@@ -0,0 +1,84 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/inago/file-system/fake"
+)
+
+type testValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func givenStores() map[string]Store {
+	newFileStoreConfig := DefaultFileStoreConfig()
+	newFileStoreConfig.FileSystem = filesystemfake.NewFileSystem()
+	newFileStoreConfig.Dir = "/home/core/.inago/state"
+
+	return map[string]Store{
+		"file":   NewFileStore(newFileStoreConfig),
+		"memory": NewMemoryStore(),
+	}
+}
+
+func Test_Store_GetSetList(t *testing.T) {
+	for kind, store := range givenStores() {
+		var v testValue
+		if err := store.Get("history", "mygroup", &v); !IsNotFound(err) {
+			t.Fatal(kind, "expected", true, "got", false)
+		}
+		names, err := store.List("history")
+		if err != nil {
+			t.Fatal(kind, "expected", nil, "got", err)
+		}
+		if len(names) != 0 {
+			t.Fatal(kind, "expected", 0, "got", len(names))
+		}
+
+		for _, name := range []string{"mygroup", "other", "mygroup"} {
+			if err := store.Set("history", name, testValue{Name: name, Count: 2}); err != nil {
+				t.Fatal(kind, "expected", nil, "got", err)
+			}
+		}
+
+		if err := store.Get("history", "mygroup", &v); err != nil {
+			t.Fatal(kind, "expected", nil, "got", err)
+		}
+		if expected := (testValue{Name: "mygroup", Count: 2}); v != expected {
+			t.Fatal(kind, "expected", expected, "got", v)
+		}
+		if err := store.Get("other-namespace", "mygroup", &v); !IsNotFound(err) {
+			t.Fatal(kind, "expected", true, "got", false)
+		}
+
+		names, err = store.List("history")
+		if err != nil {
+			t.Fatal(kind, "expected", nil, "got", err)
+		}
+		if expected := []string{"mygroup", "other"}; !reflect.DeepEqual(names, expected) {
+			t.Fatal(kind, "expected", expected, "got", names)
+		}
+	}
+}
+
+func Test_Store_InvalidKey(t *testing.T) {
+	testCases := []struct {
+		Namespace string
+		Name      string
+	}{
+		{Namespace: "", Name: "mygroup"},
+		{Namespace: "history", Name: ""},
+		{Namespace: "history", Name: "../mygroup"},
+		{Namespace: "..", Name: "mygroup"},
+	}
+
+	for kind, store := range givenStores() {
+		for i, testCase := range testCases {
+			if err := store.Set(testCase.Namespace, testCase.Name, testValue{}); !IsInvalidKey(err) {
+				t.Fatal(kind, "case", i+1, "expected", true, "got", false)
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package tracing
+
+import (
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+const (
+	// defaultOTLPEndpoint is the endpoint spans are sent to in case the OTLP
+	// exporter is asked for explicitly, but no endpoint is given.
+	defaultOTLPEndpoint = "http://localhost:4318"
+
+	// otlpTracesPath is the path spans are sent to, relative to
+	// OTEL_EXPORTER_OTLP_ENDPOINT.
+	otlpTracesPath = "/v1/traces"
+)
+
+// ConfigFromEnv creates the configuration of a tracer from the standard
+// OpenTelemetry environment variables, looked up using the given function,
+// which is usually os.LookupEnv. The given service name is used unless
+// OTEL_SERVICE_NAME is set. Tracing is disabled unless an OTLP endpoint is
+// configured or OTEL_TRACES_EXPORTER is "otlp". The following variables are
+// supported.
+//
+//   OTEL_SDK_DISABLED                    "true" disables tracing
+//   OTEL_TRACES_EXPORTER                 "otlp" or "none"
+//   OTEL_EXPORTER_OTLP_ENDPOINT          base URL, "/v1/traces" is appended
+//   OTEL_EXPORTER_OTLP_TRACES_ENDPOINT   URL spans are sent to as it is
+//   OTEL_EXPORTER_OTLP_HEADERS           e.g. "api-key=secret,team=ops"
+//   OTEL_EXPORTER_OTLP_TRACES_HEADERS    like above, but only for spans
+//   OTEL_EXPORTER_OTLP_PROTOCOL          only "http/json" is supported
+//   OTEL_SERVICE_NAME                    e.g. "inagoctl"
+//   TRACEPARENT                          W3C trace context of the parent span
+//
+// In case the environment asks for something not supported, e.g. the gRPC
+// protocol, an error that you can identify using IsInvalidConfig is returned.
+func ConfigFromEnv(serviceName string, lookup func(string) (string, bool)) (Config, error) {
+	get := func(key string) string {
+		value, _ := lookup(key)
+		return strings.TrimSpace(value)
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.ServiceName = serviceName
+	if name := get("OTEL_SERVICE_NAME"); name != "" {
+		newConfig.ServiceName = name
+	}
+
+	if strings.ToLower(get("OTEL_SDK_DISABLED")) == "true" {
+		return newConfig, nil
+	}
+
+	exporter := get("OTEL_TRACES_EXPORTER")
+	switch exporter {
+	case "", "otlp":
+	case "none":
+		return newConfig, nil
+	default:
+		return Config{}, maskAnyf(invalidConfigError, "OTEL_TRACES_EXPORTER must be otlp or none, got '%s'", exporter)
+	}
+
+	for _, key := range []string{"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL"} {
+		if protocol := get(key); protocol != "" && protocol != "http/json" {
+			return Config{}, maskAnyf(invalidConfigError, "%s must be http/json, got '%s'", key, protocol)
+		}
+	}
+
+	newOTLPConfig := DefaultOTLPConfig()
+	if endpoint := get("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		newOTLPConfig.Endpoint = endpoint
+	} else if endpoint := get("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		newOTLPConfig.Endpoint = strings.TrimSuffix(endpoint, "/") + otlpTracesPath
+	} else if exporter == "otlp" {
+		newOTLPConfig.Endpoint = defaultOTLPEndpoint + otlpTracesPath
+	} else {
+		// Nothing asks for tracing.
+		return newConfig, nil
+	}
+
+	for _, key := range []string{"OTEL_EXPORTER_OTLP_HEADERS", "OTEL_EXPORTER_OTLP_TRACES_HEADERS"} {
+		headers, err := parseHeaders(get(key))
+		if err != nil {
+			return Config{}, maskAnyf(invalidConfigError, "%s: %s", key, err.Error())
+		}
+		for name, value := range headers {
+			newOTLPConfig.Headers[name] = value
+		}
+	}
+
+	newExporter, err := NewOTLPExporter(newOTLPConfig)
+	if err != nil {
+		return Config{}, maskAny(err)
+	}
+	newConfig.Exporter = newExporter
+
+	if traceParent := get("TRACEPARENT"); traceParent != "" {
+		parent, err := ParseTraceParent(traceParent)
+		if err != nil {
+			return Config{}, maskAny(err)
+		}
+		newConfig.Parent = parent
+	}
+
+	return newConfig, nil
+}
+
+// parseHeaders parses a comma separated list of URL encoded key-value pairs,
+// the way OTEL_EXPORTER_OTLP_HEADERS is defined.
+func parseHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	if s == "" {
+		return headers, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, maskAnyf(invalidConfigError, "header '%s' must have the form key=value", pair)
+		}
+		value, err := url.QueryUnescape(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, maskAnyf(invalidConfigError, "header '%s': %s", pair, err.Error())
+		}
+		headers[strings.TrimSpace(parts[0])] = value
+	}
+
+	return headers, nil
+}
+
+// ParseTraceParent parses a W3C trace context, as found in the traceparent
+// HTTP header, e.g. to continue the trace of the CI job executing inagoctl. In
+// case it is malformed, an error that you can identify using IsInvalidConfig
+// is returned.
+//
+//   00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+//
+func ParseTraceParent(s string) (SpanContext, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, maskAnyf(invalidConfigError, "traceparent '%s' must have the form version-traceid-spanid-flags", s)
+	}
+
+	var sc SpanContext
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, maskAnyf(invalidConfigError, "traceparent '%s': %s", s, err.Error())
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, maskAnyf(invalidConfigError, "traceparent '%s': %s", s, err.Error())
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, maskAnyf(invalidConfigError, "traceparent '%s' must not have zero IDs", s)
+	}
+
+	return sc, nil
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"fmt"
+
+	"github.com/juju/errgo"
+)
+
+var (
+	maskAny = errgo.MaskFunc(errgo.Any)
+)
+
+func maskAnyf(err error, f string, v ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	f = fmt.Sprintf("%s: %s", err.Error(), f)
+	newErr := errgo.WithCausef(nil, errgo.Cause(err), f, v...)
+	newErr.(*errgo.Err).SetLocation(1)
+
+	return newErr
+}
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig checks whether the given error indicates that tracing cannot
+// be configured the way it was asked for, e.g. because an unsupported exporter
+// is set in the environment.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var exportFailedError = errgo.New("export failed")
+
+// IsExportFailed checks whether the given error indicates that spans were
+// rejected by the receiving collector.
+func IsExportFailed(err error) bool {
+	return errgo.Cause(err) == exportFailedError
+}
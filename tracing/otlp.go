@@ -0,0 +1,195 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// instrumentationScope is the name spans are reported to be recorded by.
+const instrumentationScope = "github.com/giantswarm/inago"
+
+// OTLP span kinds and status codes. See
+// https://github.com/open-telemetry/opentelemetry-proto.
+const (
+	otlpSpanKindInternal = 1
+
+	otlpStatusCodeOK    = 1
+	otlpStatusCodeError = 2
+)
+
+// OTLPConfig represents the configuration used to create a new OTLP
+// exporter.
+type OTLPConfig struct {
+	// Client is the HTTP client used to send spans.
+	Client *http.Client
+
+	// Endpoint is the URL spans are sent to, e.g.
+	// "http://localhost:4318/v1/traces".
+	Endpoint string
+
+	// Headers are sent along with each request, e.g. to authenticate against
+	// the collector.
+	Headers map[string]string
+}
+
+// DefaultOTLPConfig provides a default configuration to create a new OTLP
+// exporter by best effort.
+func DefaultOTLPConfig() OTLPConfig {
+	newConfig := OTLPConfig{
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		Endpoint: "",
+		Headers:  map[string]string{},
+	}
+
+	return newConfig
+}
+
+// OTLPExporter sends spans to an OpenTelemetry collector using OTLP over HTTP,
+// encoded as JSON.
+type OTLPExporter struct {
+	OTLPConfig
+}
+
+// NewOTLPExporter creates a new OTLP exporter. In case the configuration is
+// invalid, e.g. the endpoint is missing, an error that you can identify using
+// IsInvalidConfig is returned.
+func NewOTLPExporter(config OTLPConfig) (*OTLPExporter, error) {
+	if config.Endpoint == "" {
+		return nil, maskAnyf(invalidConfigError, "OTLP endpoint must not be empty")
+	}
+	if config.Client == nil {
+		return nil, maskAnyf(invalidConfigError, "HTTP client must not be empty")
+	}
+
+	newExporter := &OTLPExporter{
+		OTLPConfig: config,
+	}
+
+	return newExporter, nil
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func (e *OTLPExporter) Export(ctx context.Context, service string, spans []*Span) error {
+	scopeSpans := otlpScopeSpans{
+		Scope: otlpScope{Name: instrumentationScope},
+	}
+	for _, span := range spans {
+		scopeSpans.Spans = append(scopeSpans.Spans, newOTLPSpan(span))
+	}
+	body := otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: []otlpAttribute{newOTLPAttribute(Attr("service.name", service))},
+				},
+				ScopeSpans: []otlpScopeSpans{scopeSpans},
+			},
+		},
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return maskAny(err)
+	}
+	req, err := http.NewRequest("POST", e.OTLPConfig.Endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return maskAny(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.OTLPConfig.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.OTLPConfig.Client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return maskAnyf(exportFailedError, "collector answered with status code %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+	}
+
+	return nil
+}
+
+func newOTLPSpan(span *Span) otlpSpan {
+	s := otlpSpan{
+		TraceID:           span.TraceID.String(),
+		SpanID:            span.SpanID.String(),
+		Name:              span.Name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+		Status:            otlpStatus{Code: otlpStatusCodeOK},
+	}
+	if span.ParentID != (SpanID{}) {
+		s.ParentSpanID = span.ParentID.String()
+	}
+	for _, a := range span.Attributes {
+		s.Attributes = append(s.Attributes, newOTLPAttribute(a))
+	}
+	if span.Err != nil {
+		s.Status = otlpStatus{Code: otlpStatusCodeError, Message: span.Err.Error()}
+	}
+
+	return s
+}
+
+func newOTLPAttribute(a Attribute) otlpAttribute {
+	return otlpAttribute{Key: a.Key, Value: otlpValue{StringValue: a.Value}}
+}
@@ -0,0 +1,276 @@
+// Package tracing records spans describing the operations executed against
+// groups, and exports them using the OpenTelemetry protocol (OTLP), so that
+// deployments can be traced end-to-end alongside application traces. Exporting
+// is configured using the standard OpenTelemetry environment variables. See
+// ConfigFromEnv.
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// contextSpan is the key of the span stored in a context.Context. See
+// WithSpan.
+const contextSpan = "tracing-span"
+
+// TraceID identifies a trace, i.e. all spans of an operation executed
+// end-to-end.
+type TraceID [16]byte
+
+// String returns the trace ID hex encoded, the way OTLP and W3C trace context
+// expect it.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID identifies a single span of a trace.
+type SpanID [8]byte
+
+// String returns the span ID hex encoded, the way OTLP and W3C trace context
+// expect it.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Attribute describes what a span refers to, e.g. the group an operation is
+// executed against.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Attr creates an attribute using the given key and value.
+func Attr(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanContext identifies a span that other spans can be children of. It is
+// used to continue traces started by other processes, e.g. a CI job. See
+// ParseTraceParent.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// IsValid checks whether the span context refers to a span.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// Span describes a single operation, e.g. submitting a group, or a single call
+// of fleet's API. Spans are created using Tracer.Start and need to be
+// finished using Span.Finish. All methods of Span can be called on nil,
+// which is what Tracer.Start returns in case tracing is disabled.
+type Span struct {
+	// TraceID identifies the trace the span is part of.
+	TraceID TraceID
+
+	// SpanID identifies the span.
+	SpanID SpanID
+
+	// ParentID identifies the span this span is a child of. It is zero for
+	// root spans.
+	ParentID SpanID
+
+	// Name describes the operation, e.g. "submit".
+	Name string
+
+	// Start is the time the operation started.
+	Start time.Time
+
+	// End is the time the operation finished.
+	End time.Time
+
+	// Attributes describe what the operation refers to.
+	Attributes []Attribute
+
+	// Err is the error the operation failed with, or nil in case it succeeded.
+	Err error
+
+	tracer *Tracer
+}
+
+// Context returns the span context identifying the span.
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+
+	return SpanContext{TraceID: s.TraceID, SpanID: s.SpanID}
+}
+
+// SetAttributes adds the given attributes to the span.
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	s.Attributes = append(s.Attributes, attributes...)
+}
+
+// Finish finishes the span. The given error is the error the operation failed
+// with, or nil in case it succeeded. Finished spans are exported on the next
+// call of Tracer.Flush.
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	s.End = s.tracer.Config.Now()
+	s.Err = err
+	s.tracer.finished = append(s.tracer.finished, s)
+}
+
+// WithSpan returns a copy of ctx carrying the given span. Spans started using
+// the returned context are children of the given span.
+func WithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, contextSpan, span)
+}
+
+// SpanFromContext returns the span carried by the given context, or nil in
+// case there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(contextSpan).(*Span)
+	return span
+}
+
+// Exporter sends finished spans to a tracing backend.
+type Exporter interface {
+	// Export sends the given spans, which were recorded by the given service,
+	// to the tracing backend.
+	Export(ctx context.Context, service string, spans []*Span) error
+}
+
+// Config represents the configuration used to create a new tracer.
+type Config struct {
+	// Exporter sends finished spans to a tracing backend. Nil disables
+	// tracing. See NewOTLPExporter.
+	Exporter Exporter
+
+	// ServiceName is the name of the service spans are recorded by, e.g.
+	// "inagoctl".
+	ServiceName string
+
+	// Parent is the span root spans are children of, e.g. the span of the CI
+	// job executing inagoctl. The zero value starts a new trace.
+	Parent SpanContext
+
+	// Now returns the current time. It is used to time spans.
+	Now func() time.Time
+}
+
+// DefaultConfig provides a default configuration to create a new tracer by
+// best effort. Tracing is disabled unless an exporter is configured.
+func DefaultConfig() Config {
+	newConfig := Config{
+		Exporter:    nil,
+		ServiceName: "inago",
+		Parent:      SpanContext{},
+		Now:         time.Now,
+	}
+
+	return newConfig
+}
+
+// Tracer records spans and exports them using the configured exporter. A nil
+// tracer records nothing, so that tracing can be disabled without checking
+// for it wherever spans are started.
+type Tracer struct {
+	Config
+
+	mutex    sync.Mutex
+	finished []*Span
+}
+
+// NewTracer creates a new tracer. In case the given configuration does not
+// define an exporter, nil is returned, which disables tracing.
+//
+//   ctx, span := newTracer.Start(ctx, "submit", tracing.Attr("inago.group", "mygroup"))
+//   err := submit(ctx)
+//   span.Finish(err)
+//
+func NewTracer(config Config) *Tracer {
+	if config.Exporter == nil {
+		return nil
+	}
+
+	newTracer := &Tracer{
+		Config: config,
+	}
+
+	return newTracer
+}
+
+// Start starts a span having the given name and attributes. The span is a
+// child of the span carried by the given context, or of Config.Parent in case
+// there is none. The returned context carries the new span. In case the tracer
+// is nil, the given context and a nil span are returned.
+func (t *Tracer) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	parent := t.Config.Parent
+	if span := SpanFromContext(ctx); span != nil {
+		parent = span.Context()
+	}
+
+	span := &Span{
+		TraceID:    parent.TraceID,
+		SpanID:     newSpanID(),
+		ParentID:   parent.SpanID,
+		Name:       name,
+		Start:      t.Config.Now(),
+		Attributes: attributes,
+		tracer:     t,
+	}
+	if !parent.IsValid() {
+		span.TraceID = newTraceID()
+		span.ParentID = SpanID{}
+	}
+
+	return WithSpan(ctx, span), span
+}
+
+// Flush exports all spans finished since the last flush. Spans are dropped
+// in case exporting them fails, so tracing never piles up memory.
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mutex.Lock()
+	spans := t.finished
+	t.finished = nil
+	t.mutex.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+	if err := t.Config.Exporter.Export(ctx, t.Config.ServiceName, spans); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	rand.Read(id[:])
+	return id
+}
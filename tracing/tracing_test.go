@@ -0,0 +1,224 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestTracer_Nil(t *testing.T) {
+	newTracer := NewTracer(DefaultConfig())
+	if newTracer != nil {
+		t.Fatal("expected", nil, "got", newTracer)
+	}
+
+	ctx, span := newTracer.Start(context.Background(), "submit")
+	span.SetAttributes(Attr("inago.group", "foo"))
+	span.Finish(nil)
+	if SpanFromContext(ctx) != nil {
+		t.Fatal("expected", nil, "got", SpanFromContext(ctx))
+	}
+	if err := newTracer.Flush(ctx); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+}
+
+func TestTracer_Parent(t *testing.T) {
+	parent, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	newConfig := DefaultConfig()
+	newConfig.Exporter = &OTLPExporter{}
+	newConfig.Parent = parent
+	newTracer := NewTracer(newConfig)
+
+	ctx, root := newTracer.Start(context.Background(), "up")
+	_, child := newTracer.Start(ctx, "submit")
+
+	if root.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || root.ParentID.String() != "00f067aa0ba902b7" {
+		t.Fatal("expected", parent, "got", root.TraceID, root.ParentID)
+	}
+	if child.TraceID != root.TraceID || child.ParentID != root.SpanID {
+		t.Fatal("expected", root.Context(), "got", child.TraceID, child.ParentID)
+	}
+}
+
+func TestOTLPExporter_Export(t *testing.T) {
+	var body otlpRequest
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("api-key")
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	newOTLPConfig := DefaultOTLPConfig()
+	newOTLPConfig.Endpoint = server.URL + otlpTracesPath
+	newOTLPConfig.Headers["api-key"] = "secret"
+	newExporter, err := NewOTLPExporter(newOTLPConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	newConfig := DefaultConfig()
+	newConfig.Exporter = newExporter
+	newConfig.ServiceName = "inagoctl"
+	newTracer := NewTracer(newConfig)
+
+	ctx, root := newTracer.Start(context.Background(), "up", Attr("inago.group", "foo"))
+	_, child := newTracer.Start(ctx, "fleet.Start")
+	child.Finish(errors.New("test error"))
+	root.Finish(nil)
+	if err := newTracer.Flush(context.Background()); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	if header != "secret" {
+		t.Fatal("expected", "secret", "got", header)
+	}
+	if len(body.ResourceSpans) != 1 || body.ResourceSpans[0].Resource.Attributes[0].Value.StringValue != "inagoctl" {
+		t.Fatal("expected", "resource of inagoctl", "got", body.ResourceSpans)
+	}
+	spans := body.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatal("expected", 2, "got", len(spans))
+	}
+	if spans[0].Name != "fleet.Start" || spans[0].Status.Code != otlpStatusCodeError || spans[0].ParentSpanID != root.SpanID.String() {
+		t.Fatal("expected", "failed child span", "got", spans[0])
+	}
+	if spans[1].Name != "up" || spans[1].Status.Code != otlpStatusCodeOK || spans[1].ParentSpanID != "" || spans[1].Attributes[0].Key != "inago.group" {
+		t.Fatal("expected", "succeeded root span", "got", spans[1])
+	}
+
+	// Flushed spans are not exported again.
+	body = otlpRequest{}
+	if err := newTracer.Flush(context.Background()); err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(body.ResourceSpans) != 0 {
+		t.Fatal("expected", 0, "got", len(body.ResourceSpans))
+	}
+}
+
+func TestOTLPExporter_Export_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	newOTLPConfig := DefaultOTLPConfig()
+	newOTLPConfig.Endpoint = server.URL
+	newExporter, err := NewOTLPExporter(newOTLPConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	err = newExporter.Export(context.Background(), "inagoctl", []*Span{{Name: "up"}})
+	if !IsExportFailed(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	testCases := []struct {
+		Env      map[string]string
+		Enabled  bool
+		Endpoint string
+		Service  string
+		ErrCheck func(error) bool
+	}{
+		{
+			Env:     map[string]string{},
+			Enabled: false,
+			Service: "inagoctl",
+		},
+		{
+			Env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318/"},
+			Enabled:  true,
+			Endpoint: "http://collector:4318/v1/traces",
+			Service:  "inagoctl",
+		},
+		{
+			Env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://traces:4318/custom", "OTEL_SERVICE_NAME": "deployer"},
+			Enabled:  true,
+			Endpoint: "http://traces:4318/custom",
+			Service:  "deployer",
+		},
+		{
+			Env:      map[string]string{"OTEL_TRACES_EXPORTER": "otlp"},
+			Enabled:  true,
+			Endpoint: "http://localhost:4318/v1/traces",
+			Service:  "inagoctl",
+		},
+		{
+			Env:     map[string]string{"OTEL_TRACES_EXPORTER": "none", "OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"},
+			Enabled: false,
+			Service: "inagoctl",
+		},
+		{
+			Env:     map[string]string{"OTEL_SDK_DISABLED": "true", "OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"},
+			Enabled: false,
+			Service: "inagoctl",
+		},
+		{
+			Env:      map[string]string{"OTEL_TRACES_EXPORTER": "jaeger"},
+			ErrCheck: IsInvalidConfig,
+		},
+		{
+			Env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4317", "OTEL_EXPORTER_OTLP_PROTOCOL": "grpc"},
+			ErrCheck: IsInvalidConfig,
+		},
+		{
+			Env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_EXPORTER_OTLP_HEADERS": "broken"},
+			ErrCheck: IsInvalidConfig,
+		},
+		{
+			Env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "TRACEPARENT": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+			ErrCheck: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		lookup := func(key string) (string, bool) {
+			value, ok := testCase.Env[key]
+			return value, ok
+		}
+
+		config, err := ConfigFromEnv("inagoctl", lookup)
+		if testCase.ErrCheck != nil {
+			if !testCase.ErrCheck(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		if config.ServiceName != testCase.Service {
+			t.Fatal("case", i+1, "expected", testCase.Service, "got", config.ServiceName)
+		}
+		if (config.Exporter != nil) != testCase.Enabled {
+			t.Fatal("case", i+1, "expected", testCase.Enabled, "got", config.Exporter != nil)
+		}
+		if testCase.Enabled && config.Exporter.(*OTLPExporter).Endpoint != testCase.Endpoint {
+			t.Fatal("case", i+1, "expected", testCase.Endpoint, "got", config.Exporter.(*OTLPExporter).Endpoint)
+		}
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := parseHeaders("api-key=secret%3D1, team=ops")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(headers) != 2 || headers["api-key"] != "secret=1" || headers["team"] != "ops" {
+		t.Fatal("expected", map[string]string{"api-key": "secret=1", "team": "ops"}, "got", headers)
+	}
+}